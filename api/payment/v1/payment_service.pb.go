@@ -9,6 +9,7 @@ package paymentv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,6 +23,56 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// PaymentSortField represents the field payment listings are ordered by
+type PaymentSortField int32
+
+const (
+	PaymentSortField_PAYMENT_SORT_FIELD_UNSPECIFIED PaymentSortField = 0
+	PaymentSortField_PAYMENT_SORT_FIELD_CREATED_AT  PaymentSortField = 1
+	PaymentSortField_PAYMENT_SORT_FIELD_AMOUNT      PaymentSortField = 2
+)
+
+// Enum value maps for PaymentSortField.
+var (
+	PaymentSortField_name = map[int32]string{
+		0: "PAYMENT_SORT_FIELD_UNSPECIFIED",
+		1: "PAYMENT_SORT_FIELD_CREATED_AT",
+		2: "PAYMENT_SORT_FIELD_AMOUNT",
+	}
+	PaymentSortField_value = map[string]int32{
+		"PAYMENT_SORT_FIELD_UNSPECIFIED": 0,
+		"PAYMENT_SORT_FIELD_CREATED_AT":  1,
+		"PAYMENT_SORT_FIELD_AMOUNT":      2,
+	}
+)
+
+func (x PaymentSortField) Enum() *PaymentSortField {
+	p := new(PaymentSortField)
+	*p = x
+	return p
+}
+
+func (x PaymentSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_payment_service_proto_enumTypes[0].Descriptor()
+}
+
+func (PaymentSortField) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_payment_service_proto_enumTypes[0]
+}
+
+func (x PaymentSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentSortField.Descriptor instead.
+func (PaymentSortField) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{0}
+}
+
 // PaymentStatus represents the status of a payment
 type PaymentStatus int32
 
@@ -65,11 +116,11 @@ func (x PaymentStatus) String() string {
 }
 
 func (PaymentStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_payment_v1_payment_service_proto_enumTypes[0].Descriptor()
+	return file_api_payment_v1_payment_service_proto_enumTypes[1].Descriptor()
 }
 
 func (PaymentStatus) Type() protoreflect.EnumType {
-	return &file_api_payment_v1_payment_service_proto_enumTypes[0]
+	return &file_api_payment_v1_payment_service_proto_enumTypes[1]
 }
 
 func (x PaymentStatus) Number() protoreflect.EnumNumber {
@@ -78,7 +129,7 @@ func (x PaymentStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PaymentStatus.Descriptor instead.
 func (PaymentStatus) EnumDescriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{0}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{1}
 }
 
 // PaymentMethod represents the method used for payment
@@ -121,11 +172,11 @@ func (x PaymentMethod) String() string {
 }
 
 func (PaymentMethod) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_payment_v1_payment_service_proto_enumTypes[1].Descriptor()
+	return file_api_payment_v1_payment_service_proto_enumTypes[2].Descriptor()
 }
 
 func (PaymentMethod) Type() protoreflect.EnumType {
-	return &file_api_payment_v1_payment_service_proto_enumTypes[1]
+	return &file_api_payment_v1_payment_service_proto_enumTypes[2]
 }
 
 func (x PaymentMethod) Number() protoreflect.EnumNumber {
@@ -134,7 +185,113 @@ func (x PaymentMethod) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PaymentMethod.Descriptor instead.
 func (PaymentMethod) EnumDescriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{1}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{2}
+}
+
+// EntitlementChangeType identifies what happened to a watched entitlement
+type EntitlementChangeType int32
+
+const (
+	EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_UNSPECIFIED EntitlementChangeType = 0
+	EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_GRANTED     EntitlementChangeType = 1
+	EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_REVOKED     EntitlementChangeType = 2
+	EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_EXPIRED     EntitlementChangeType = 3
+	EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_UPDATED     EntitlementChangeType = 4
+)
+
+// Enum value maps for EntitlementChangeType.
+var (
+	EntitlementChangeType_name = map[int32]string{
+		0: "ENTITLEMENT_CHANGE_TYPE_UNSPECIFIED",
+		1: "ENTITLEMENT_CHANGE_TYPE_GRANTED",
+		2: "ENTITLEMENT_CHANGE_TYPE_REVOKED",
+		3: "ENTITLEMENT_CHANGE_TYPE_EXPIRED",
+		4: "ENTITLEMENT_CHANGE_TYPE_UPDATED",
+	}
+	EntitlementChangeType_value = map[string]int32{
+		"ENTITLEMENT_CHANGE_TYPE_UNSPECIFIED": 0,
+		"ENTITLEMENT_CHANGE_TYPE_GRANTED":     1,
+		"ENTITLEMENT_CHANGE_TYPE_REVOKED":     2,
+		"ENTITLEMENT_CHANGE_TYPE_EXPIRED":     3,
+		"ENTITLEMENT_CHANGE_TYPE_UPDATED":     4,
+	}
+)
+
+func (x EntitlementChangeType) Enum() *EntitlementChangeType {
+	p := new(EntitlementChangeType)
+	*p = x
+	return p
+}
+
+func (x EntitlementChangeType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EntitlementChangeType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_payment_service_proto_enumTypes[3].Descriptor()
+}
+
+func (EntitlementChangeType) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_payment_service_proto_enumTypes[3]
+}
+
+func (x EntitlementChangeType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EntitlementChangeType.Descriptor instead.
+func (EntitlementChangeType) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{3}
+}
+
+// InvoiceFormat selects the document rendered with an invoice
+type InvoiceFormat int32
+
+const (
+	InvoiceFormat_INVOICE_FORMAT_UNSPECIFIED InvoiceFormat = 0 // Invoice data only
+	InvoiceFormat_INVOICE_FORMAT_HTML        InvoiceFormat = 1
+	InvoiceFormat_INVOICE_FORMAT_PDF         InvoiceFormat = 2
+)
+
+// Enum value maps for InvoiceFormat.
+var (
+	InvoiceFormat_name = map[int32]string{
+		0: "INVOICE_FORMAT_UNSPECIFIED",
+		1: "INVOICE_FORMAT_HTML",
+		2: "INVOICE_FORMAT_PDF",
+	}
+	InvoiceFormat_value = map[string]int32{
+		"INVOICE_FORMAT_UNSPECIFIED": 0,
+		"INVOICE_FORMAT_HTML":        1,
+		"INVOICE_FORMAT_PDF":         2,
+	}
+)
+
+func (x InvoiceFormat) Enum() *InvoiceFormat {
+	p := new(InvoiceFormat)
+	*p = x
+	return p
+}
+
+func (x InvoiceFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InvoiceFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_payment_v1_payment_service_proto_enumTypes[4].Descriptor()
+}
+
+func (InvoiceFormat) Type() protoreflect.EnumType {
+	return &file_api_payment_v1_payment_service_proto_enumTypes[4]
+}
+
+func (x InvoiceFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InvoiceFormat.Descriptor instead.
+func (InvoiceFormat) EnumDescriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{4}
 }
 
 // CreatePaymentRequest represents a request to create a payment
@@ -458,9 +615,13 @@ func (x *UpdatePaymentStatusResponse) GetSuccess() bool {
 // GetPaymentsByCustomerRequest represents a request to get customer payments
 type GetPaymentsByCustomerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"` // Customer identifier
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                            // Maximum number of payments to return
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                          // Number of payments to skip
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`                       // Customer identifier
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`                                                  // Maximum number of payments to return
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                                                // Number of payments to skip (ignored when page_token is set)
+	Filter        *PaymentFilter         `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`                                                 // Optional additional filters
+	SortBy        PaymentSortField       `protobuf:"varint,5,opt,name=sort_by,json=sortBy,proto3,enum=payment.v1.PaymentSortField" json:"sort_by,omitempty"` // Sort field (defaults to created_at)
+	SortAscending bool                   `protobuf:"varint,6,opt,name=sort_ascending,json=sortAscending,proto3" json:"sort_ascending,omitempty"`             // Sort ascending instead of the default descending
+	PageToken     string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                          // Opaque token from a previous response's next_page_token
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -516,11 +677,40 @@ func (x *GetPaymentsByCustomerRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *GetPaymentsByCustomerRequest) GetFilter() *PaymentFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetPaymentsByCustomerRequest) GetSortBy() PaymentSortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return PaymentSortField_PAYMENT_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *GetPaymentsByCustomerRequest) GetSortAscending() bool {
+	if x != nil {
+		return x.SortAscending
+	}
+	return false
+}
+
+func (x *GetPaymentsByCustomerRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 // GetPaymentsByCustomerResponse represents a response with customer payments
 type GetPaymentsByCustomerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // Total payments matching the request across all pages
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Token for the next page; empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -569,11 +759,22 @@ func (x *GetPaymentsByCustomerResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *GetPaymentsByCustomerResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 // ListPaymentsRequest represents a request to list payments
 type ListPaymentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`   // Maximum number of payments to return
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // Number of payments to skip
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`                                                  // Maximum number of payments to return
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`                                                // Number of payments to skip (ignored when page_token is set)
+	Filter        *PaymentFilter         `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`                                                 // Optional filters
+	SortBy        PaymentSortField       `protobuf:"varint,4,opt,name=sort_by,json=sortBy,proto3,enum=payment.v1.PaymentSortField" json:"sort_by,omitempty"` // Sort field (defaults to created_at)
+	SortAscending bool                   `protobuf:"varint,5,opt,name=sort_ascending,json=sortAscending,proto3" json:"sort_ascending,omitempty"`             // Sort ascending instead of the default descending
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                          // Opaque token from a previous response's next_page_token
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -622,11 +823,40 @@ func (x *ListPaymentsRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *ListPaymentsRequest) GetFilter() *PaymentFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListPaymentsRequest) GetSortBy() PaymentSortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return PaymentSortField_PAYMENT_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *ListPaymentsRequest) GetSortAscending() bool {
+	if x != nil {
+		return x.SortAscending
+	}
+	return false
+}
+
+func (x *ListPaymentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
 // ListPaymentsResponse represents a response with payments list
 type ListPaymentsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`                                       // Total payments matching the request across all pages
+	NextPageToken string                 `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Token for the next page; empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -675,26 +905,135 @@ func (x *ListPaymentsResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *ListPaymentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// PaymentFilter narrows payment listings; unset fields are ignored
+type PaymentFilter struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Status            string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`                                                  // Payment status
+	Currency          string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`                                              // Currency code
+	PaymentMethod     string                 `protobuf:"bytes,3,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`               // Payment method
+	ExternalPaymentId string                 `protobuf:"bytes,4,opt,name=external_payment_id,json=externalPaymentId,proto3" json:"external_payment_id,omitempty"` // External payment processor ID
+	CreatedAfter      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`                  // Inclusive lower bound on creation time
+	CreatedBefore     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`               // Exclusive upper bound on creation time
+	MinAmount         *float64               `protobuf:"fixed64,7,opt,name=min_amount,json=minAmount,proto3,oneof" json:"min_amount,omitempty"`                   // Inclusive minimum amount in dollars
+	MaxAmount         *float64               `protobuf:"fixed64,8,opt,name=max_amount,json=maxAmount,proto3,oneof" json:"max_amount,omitempty"`                   // Inclusive maximum amount in dollars
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PaymentFilter) Reset() {
+	*x = PaymentFilter{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentFilter) ProtoMessage() {}
+
+func (x *PaymentFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentFilter.ProtoReflect.Descriptor instead.
+func (*PaymentFilter) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PaymentFilter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentFilter) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentFilter) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentFilter) GetExternalPaymentId() string {
+	if x != nil {
+		return x.ExternalPaymentId
+	}
+	return ""
+}
+
+func (x *PaymentFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *PaymentFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *PaymentFilter) GetMinAmount() float64 {
+	if x != nil && x.MinAmount != nil {
+		return *x.MinAmount
+	}
+	return 0
+}
+
+func (x *PaymentFilter) GetMaxAmount() float64 {
+	if x != nil && x.MaxAmount != nil {
+		return *x.MaxAmount
+	}
+	return 0
+}
+
 // Payment represents a payment transaction
 type Payment struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                            // Payment identifier
-	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`                                  // Amount in dollars
-	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`                                // Currency code
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                    // Payment status
-	PaymentMethod string                 `protobuf:"bytes,5,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"` // Payment method
-	CustomerId    string                 `protobuf:"bytes,6,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`          // Customer identifier
-	OrderId       string                 `protobuf:"bytes,7,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`                   // Order identifier
-	Description   string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`                          // Payment description
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`             // Creation timestamp
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`            // Last update timestamp
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                           // Payment identifier
+	Amount            float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`                                                 // Amount in dollars
+	Currency          string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`                                               // Currency code
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                                                   // Payment status
+	PaymentMethod     string                 `protobuf:"bytes,5,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`                // Payment method
+	CustomerId        string                 `protobuf:"bytes,6,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`                         // Customer identifier
+	OrderId           string                 `protobuf:"bytes,7,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`                                  // Order identifier
+	Description       string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`                                         // Payment description
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                            // Creation timestamp
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                           // Last update timestamp
+	ExternalPaymentId string                 `protobuf:"bytes,11,opt,name=external_payment_id,json=externalPaymentId,proto3" json:"external_payment_id,omitempty"` // External payment processor ID
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Payment) Reset() {
 	*x = Payment{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[10]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -706,7 +1045,7 @@ func (x *Payment) String() string {
 func (*Payment) ProtoMessage() {}
 
 func (x *Payment) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[10]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -719,7 +1058,7 @@ func (x *Payment) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Payment.ProtoReflect.Descriptor instead.
 func (*Payment) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{10}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Payment) GetId() string {
@@ -792,6 +1131,13 @@ func (x *Payment) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Payment) GetExternalPaymentId() string {
+	if x != nil {
+		return x.ExternalPaymentId
+	}
+	return ""
+}
+
 // CreateCheckoutSessionRequest represents a request to create a checkout session
 type CreateCheckoutSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -799,17 +1145,19 @@ type CreateCheckoutSessionRequest struct {
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // User identifier
 	FamilyId      string                 `protobuf:"bytes,3,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`          // Family identifier (optional)
 	CountryCode   string                 `protobuf:"bytes,4,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"` // Country code for pricing
-	BasePrice     float64                `protobuf:"fixed64,5,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`     // Base price in dollars
-	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                          // Currency code
+	BasePrice     float64                `protobuf:"fixed64,5,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`     // Deprecated: ignored, the price is quoted from the plan catalog
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                          // Currency code (optional, must match the plan's currency)
 	SuccessUrl    string                 `protobuf:"bytes,7,opt,name=success_url,json=successUrl,proto3" json:"success_url,omitempty"`    // Success redirect URL
 	CancelUrl     string                 `protobuf:"bytes,8,opt,name=cancel_url,json=cancelUrl,proto3" json:"cancel_url,omitempty"`       // Cancel redirect URL
+	Region        string                 `protobuf:"bytes,9,opt,name=region,proto3" json:"region,omitempty"`                              // State or province for regional sales tax (optional)
+	TaxId         string                 `protobuf:"bytes,10,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`                  // Business tax ID; applies the reverse charge where valid (optional)
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateCheckoutSessionRequest) Reset() {
 	*x = CreateCheckoutSessionRequest{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[11]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -821,7 +1169,7 @@ func (x *CreateCheckoutSessionRequest) String() string {
 func (*CreateCheckoutSessionRequest) ProtoMessage() {}
 
 func (x *CreateCheckoutSessionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[11]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -834,7 +1182,7 @@ func (x *CreateCheckoutSessionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCheckoutSessionRequest.ProtoReflect.Descriptor instead.
 func (*CreateCheckoutSessionRequest) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{11}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CreateCheckoutSessionRequest) GetPlanId() string {
@@ -893,19 +1241,34 @@ func (x *CreateCheckoutSessionRequest) GetCancelUrl() string {
 	return ""
 }
 
+func (x *CreateCheckoutSessionRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CreateCheckoutSessionRequest) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
 // CreateCheckoutSessionResponse represents a response with checkout session details
 type CreateCheckoutSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Checkout session ID
 	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`                              // Checkout URL
 	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Session expiration
+	Session       *CheckoutSession       `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"`                      // Recorded session with the quoted price
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateCheckoutSessionResponse) Reset() {
 	*x = CreateCheckoutSessionResponse{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[12]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -917,7 +1280,7 @@ func (x *CreateCheckoutSessionResponse) String() string {
 func (*CreateCheckoutSessionResponse) ProtoMessage() {}
 
 func (x *CreateCheckoutSessionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[12]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,7 +1293,7 @@ func (x *CreateCheckoutSessionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateCheckoutSessionResponse.ProtoReflect.Descriptor instead.
 func (*CreateCheckoutSessionResponse) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{12}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CreateCheckoutSessionResponse) GetSessionId() string {
@@ -954,6 +1317,13 @@ func (x *CreateCheckoutSessionResponse) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *CreateCheckoutSessionResponse) GetSession() *CheckoutSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
 // ProcessWebhookRequest represents a webhook processing request
 type ProcessWebhookRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -966,7 +1336,7 @@ type ProcessWebhookRequest struct {
 
 func (x *ProcessWebhookRequest) Reset() {
 	*x = ProcessWebhookRequest{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[13]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -978,7 +1348,7 @@ func (x *ProcessWebhookRequest) String() string {
 func (*ProcessWebhookRequest) ProtoMessage() {}
 
 func (x *ProcessWebhookRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[13]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -991,7 +1361,7 @@ func (x *ProcessWebhookRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProcessWebhookRequest.ProtoReflect.Descriptor instead.
 func (*ProcessWebhookRequest) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{13}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *ProcessWebhookRequest) GetPayload() []byte {
@@ -1026,7 +1396,7 @@ type ProcessWebhookResponse struct {
 
 func (x *ProcessWebhookResponse) Reset() {
 	*x = ProcessWebhookResponse{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[14]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1038,7 +1408,7 @@ func (x *ProcessWebhookResponse) String() string {
 func (*ProcessWebhookResponse) ProtoMessage() {}
 
 func (x *ProcessWebhookResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[14]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1051,7 +1421,7 @@ func (x *ProcessWebhookResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProcessWebhookResponse.ProtoReflect.Descriptor instead.
 func (*ProcessWebhookResponse) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{14}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ProcessWebhookResponse) GetSuccess() bool {
@@ -1080,7 +1450,7 @@ type ListEntitlementsRequest struct {
 
 func (x *ListEntitlementsRequest) Reset() {
 	*x = ListEntitlementsRequest{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[15]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1092,7 +1462,7 @@ func (x *ListEntitlementsRequest) String() string {
 func (*ListEntitlementsRequest) ProtoMessage() {}
 
 func (x *ListEntitlementsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[15]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1105,7 +1475,7 @@ func (x *ListEntitlementsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListEntitlementsRequest.ProtoReflect.Descriptor instead.
 func (*ListEntitlementsRequest) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{15}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ListEntitlementsRequest) GetUserId() string {
@@ -1140,7 +1510,7 @@ type ListEntitlementsResponse struct {
 
 func (x *ListEntitlementsResponse) Reset() {
 	*x = ListEntitlementsResponse{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[16]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1152,7 +1522,7 @@ func (x *ListEntitlementsResponse) String() string {
 func (*ListEntitlementsResponse) ProtoMessage() {}
 
 func (x *ListEntitlementsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[16]
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1165,7 +1535,7 @@ func (x *ListEntitlementsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListEntitlementsResponse.ProtoReflect.Descriptor instead.
 func (*ListEntitlementsResponse) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{16}
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ListEntitlementsResponse) GetEntitlements() []*Entitlement {
@@ -1182,30 +1552,32 @@ func (x *ListEntitlementsResponse) GetTotal() int32 {
 	return 0
 }
 
-// CheckEntitlementRequest represents a request to check user entitlement
-type CheckEntitlementRequest struct {
+// ExportPaymentsRequest represents a request to stream payments
+type ExportPaymentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // User identifier
-	FeatureCode   string                 `protobuf:"bytes,2,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"` // Feature code to check
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`                       // Optional customer identifier
+	Filter        *PaymentFilter         `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`                                                 // Optional filters, as for ListPayments
+	SortBy        PaymentSortField       `protobuf:"varint,3,opt,name=sort_by,json=sortBy,proto3,enum=payment.v1.PaymentSortField" json:"sort_by,omitempty"` // Sort field (defaults to created_at)
+	SortAscending bool                   `protobuf:"varint,4,opt,name=sort_ascending,json=sortAscending,proto3" json:"sort_ascending,omitempty"`             // Sort ascending instead of the default descending
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CheckEntitlementRequest) Reset() {
-	*x = CheckEntitlementRequest{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[17]
+func (x *ExportPaymentsRequest) Reset() {
+	*x = ExportPaymentsRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CheckEntitlementRequest) String() string {
+func (x *ExportPaymentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CheckEntitlementRequest) ProtoMessage() {}
+func (*ExportPaymentsRequest) ProtoMessage() {}
 
-func (x *CheckEntitlementRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[17]
+func (x *ExportPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1216,110 +1588,64 @@ func (x *CheckEntitlementRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CheckEntitlementRequest.ProtoReflect.Descriptor instead.
-func (*CheckEntitlementRequest) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ExportPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ExportPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *CheckEntitlementRequest) GetUserId() string {
+func (x *ExportPaymentsRequest) GetCustomerId() string {
 	if x != nil {
-		return x.UserId
+		return x.CustomerId
 	}
 	return ""
 }
 
-func (x *CheckEntitlementRequest) GetFeatureCode() string {
+func (x *ExportPaymentsRequest) GetFilter() *PaymentFilter {
 	if x != nil {
-		return x.FeatureCode
+		return x.Filter
 	}
-	return ""
-}
-
-// CheckEntitlementResponse represents a response to entitlement check
-type CheckEntitlementResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`        // Whether user has access
-	Entitlement   *Entitlement           `protobuf:"bytes,2,opt,name=entitlement,proto3" json:"entitlement,omitempty"` // Entitlement details (if exists)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *CheckEntitlementResponse) Reset() {
-	*x = CheckEntitlementResponse{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[18]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CheckEntitlementResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return nil
 }
 
-func (*CheckEntitlementResponse) ProtoMessage() {}
-
-func (x *CheckEntitlementResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[18]
+func (x *ExportPaymentsRequest) GetSortBy() PaymentSortField {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.SortBy
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use CheckEntitlementResponse.ProtoReflect.Descriptor instead.
-func (*CheckEntitlementResponse) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{18}
+	return PaymentSortField_PAYMENT_SORT_FIELD_UNSPECIFIED
 }
 
-func (x *CheckEntitlementResponse) GetAllowed() bool {
+func (x *ExportPaymentsRequest) GetSortAscending() bool {
 	if x != nil {
-		return x.Allowed
+		return x.SortAscending
 	}
 	return false
 }
 
-func (x *CheckEntitlementResponse) GetEntitlement() *Entitlement {
-	if x != nil {
-		return x.Entitlement
-	}
-	return nil
-}
-
-// Entitlement represents a user entitlement
-type Entitlement struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                               // Entitlement identifier
-	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                         // User identifier
-	FamilyId       string                 `protobuf:"bytes,3,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`                   // Family identifier (optional)
-	FeatureCode    string                 `protobuf:"bytes,4,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`          // Feature code
-	PlanId         string                 `protobuf:"bytes,5,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                         // Plan identifier
-	SubscriptionId string                 `protobuf:"bytes,6,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // Subscription identifier (optional)
-	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                                       // Entitlement status
-	GrantedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=granted_at,json=grantedAt,proto3" json:"granted_at,omitempty"`                // Grant timestamp
-	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                // Expiration timestamp (optional)
-	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`               // Creation timestamp
-	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`               // Last update timestamp
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// ExportEntitlementsRequest represents a request to stream entitlements
+type ExportEntitlementsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // Optional user identifier
+	FeatureCode   string                 `protobuf:"bytes,2,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"` // Optional feature code
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`                              // Optional entitlement status
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`                              // Optional source: paid, complimentary or manual
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Entitlement) Reset() {
-	*x = Entitlement{}
+func (x *ExportEntitlementsRequest) Reset() {
+	*x = ExportEntitlementsRequest{}
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Entitlement) String() string {
+func (x *ExportEntitlementsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Entitlement) ProtoMessage() {}
+func (*ExportEntitlementsRequest) ProtoMessage() {}
 
-func (x *Entitlement) ProtoReflect() protoreflect.Message {
+func (x *ExportEntitlementsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1331,109 +1657,62 @@ func (x *Entitlement) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Entitlement.ProtoReflect.Descriptor instead.
-func (*Entitlement) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExportEntitlementsRequest.ProtoReflect.Descriptor instead.
+func (*ExportEntitlementsRequest) Descriptor() ([]byte, []int) {
 	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *Entitlement) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *Entitlement) GetUserId() string {
+func (x *ExportEntitlementsRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *Entitlement) GetFamilyId() string {
-	if x != nil {
-		return x.FamilyId
-	}
-	return ""
-}
-
-func (x *Entitlement) GetFeatureCode() string {
+func (x *ExportEntitlementsRequest) GetFeatureCode() string {
 	if x != nil {
 		return x.FeatureCode
 	}
 	return ""
 }
 
-func (x *Entitlement) GetPlanId() string {
-	if x != nil {
-		return x.PlanId
-	}
-	return ""
-}
-
-func (x *Entitlement) GetSubscriptionId() string {
-	if x != nil {
-		return x.SubscriptionId
-	}
-	return ""
-}
-
-func (x *Entitlement) GetStatus() string {
+func (x *ExportEntitlementsRequest) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
 
-func (x *Entitlement) GetGrantedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.GrantedAt
-	}
-	return nil
-}
-
-func (x *Entitlement) GetExpiresAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.ExpiresAt
-	}
-	return nil
-}
-
-func (x *Entitlement) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
-}
-
-func (x *Entitlement) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *ExportEntitlementsRequest) GetSource() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Source
 	}
-	return nil
+	return ""
 }
 
-// ListPricingZonesRequest represents a request to list pricing zones
-type ListPricingZonesRequest struct {
+// WatchEntitlementsRequest represents a request to watch a user's entitlements
+type WatchEntitlementsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // User identifier
+	FamilyId      string                 `protobuf:"bytes,2,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"` // Optional family whose shared entitlements are also watched
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPricingZonesRequest) Reset() {
-	*x = ListPricingZonesRequest{}
+func (x *WatchEntitlementsRequest) Reset() {
+	*x = WatchEntitlementsRequest{}
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPricingZonesRequest) String() string {
+func (x *WatchEntitlementsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPricingZonesRequest) ProtoMessage() {}
+func (*WatchEntitlementsRequest) ProtoMessage() {}
 
-func (x *ListPricingZonesRequest) ProtoReflect() protoreflect.Message {
+func (x *WatchEntitlementsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1445,33 +1724,48 @@ func (x *ListPricingZonesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPricingZonesRequest.ProtoReflect.Descriptor instead.
-func (*ListPricingZonesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchEntitlementsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEntitlementsRequest) Descriptor() ([]byte, []int) {
 	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{20}
 }
 
-// ListPricingZonesResponse represents a response with pricing zones list
-type ListPricingZonesResponse struct {
+func (x *WatchEntitlementsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WatchEntitlementsRequest) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+// WatchEntitlementsResponse is one message of an entitlement watch
+type WatchEntitlementsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PricingZones  []*PricingZone         `protobuf:"bytes,1,rep,name=pricing_zones,json=pricingZones,proto3" json:"pricing_zones,omitempty"`
+	Snapshot      []*Entitlement         `protobuf:"bytes,1,rep,name=snapshot,proto3" json:"snapshot,omitempty"` // Current entitlements; set on the first message only
+	Change        *EntitlementChange     `protobuf:"bytes,2,opt,name=change,proto3" json:"change,omitempty"`     // Set on every later message
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListPricingZonesResponse) Reset() {
-	*x = ListPricingZonesResponse{}
+func (x *WatchEntitlementsResponse) Reset() {
+	*x = WatchEntitlementsResponse{}
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListPricingZonesResponse) String() string {
+func (x *WatchEntitlementsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListPricingZonesResponse) ProtoMessage() {}
+func (*WatchEntitlementsResponse) ProtoMessage() {}
 
-func (x *ListPricingZonesResponse) ProtoReflect() protoreflect.Message {
+func (x *WatchEntitlementsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1483,47 +1777,49 @@ func (x *ListPricingZonesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListPricingZonesResponse.ProtoReflect.Descriptor instead.
-func (*ListPricingZonesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WatchEntitlementsResponse.ProtoReflect.Descriptor instead.
+func (*WatchEntitlementsResponse) Descriptor() ([]byte, []int) {
 	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *ListPricingZonesResponse) GetPricingZones() []*PricingZone {
+func (x *WatchEntitlementsResponse) GetSnapshot() []*Entitlement {
 	if x != nil {
-		return x.PricingZones
+		return x.Snapshot
 	}
 	return nil
 }
 
-// PricingZone represents a pricing zone
-type PricingZone struct {
-	state             protoimpl.MessageState `protogen:"open.v1"`
-	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                          // Pricing zone identifier
-	IsoCode           string                 `protobuf:"bytes,2,opt,name=iso_code,json=isoCode,proto3" json:"iso_code,omitempty"`                                 // ISO country code
-	Country           string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`                                                // Country name
-	Zone              string                 `protobuf:"bytes,4,opt,name=zone,proto3" json:"zone,omitempty"`                                                      // Zone type (A, B, C, D)
-	ZoneName          string                 `protobuf:"bytes,5,opt,name=zone_name,json=zoneName,proto3" json:"zone_name,omitempty"`                              // Zone name (Premium, Mid-High, etc.)
-	PricingMultiplier float64                `protobuf:"fixed64,6,opt,name=pricing_multiplier,json=pricingMultiplier,proto3" json:"pricing_multiplier,omitempty"` // Pricing multiplier
-	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                           // Creation timestamp
-	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                           // Last update timestamp
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+func (x *WatchEntitlementsResponse) GetChange() *EntitlementChange {
+	if x != nil {
+		return x.Change
+	}
+	return nil
 }
 
-func (x *PricingZone) Reset() {
-	*x = PricingZone{}
+// EntitlementChange represents a change to a watched entitlement
+type EntitlementChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          EntitlementChangeType  `protobuf:"varint,1,opt,name=type,proto3,enum=payment.v1.EntitlementChangeType" json:"type,omitempty"` // What happened
+	Entitlement   *Entitlement           `protobuf:"bytes,2,opt,name=entitlement,proto3" json:"entitlement,omitempty"`                          // Entitlement after the change
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`          // When the change happened
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntitlementChange) Reset() {
+	*x = EntitlementChange{}
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PricingZone) String() string {
+func (x *EntitlementChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PricingZone) ProtoMessage() {}
+func (*EntitlementChange) ProtoMessage() {}
 
-func (x *PricingZone) ProtoReflect() protoreflect.Message {
+func (x *EntitlementChange) ProtoReflect() protoreflect.Message {
 	mi := &file_api_payment_v1_payment_service_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1535,91 +1831,4707 @@ func (x *PricingZone) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PricingZone.ProtoReflect.Descriptor instead.
-func (*PricingZone) Descriptor() ([]byte, []int) {
+// Deprecated: Use EntitlementChange.ProtoReflect.Descriptor instead.
+func (*EntitlementChange) Descriptor() ([]byte, []int) {
 	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *PricingZone) GetId() string {
+func (x *EntitlementChange) GetType() EntitlementChangeType {
 	if x != nil {
-		return x.Id
+		return x.Type
 	}
-	return ""
+	return EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_UNSPECIFIED
 }
 
-func (x *PricingZone) GetIsoCode() string {
+func (x *EntitlementChange) GetEntitlement() *Entitlement {
 	if x != nil {
-		return x.IsoCode
+		return x.Entitlement
 	}
-	return ""
+	return nil
 }
 
-func (x *PricingZone) GetCountry() string {
+func (x *EntitlementChange) GetOccurredAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Country
+		return x.OccurredAt
 	}
-	return ""
+	return nil
 }
 
-func (x *PricingZone) GetZone() string {
-	if x != nil {
-		return x.Zone
-	}
-	return ""
+// GrantEntitlementRequest represents a request to grant an entitlement outside of checkout
+type GrantEntitlementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // User identifier
+	FeatureCode   string                 `protobuf:"bytes,2,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"` // Feature to grant
+	PlanId        string                 `protobuf:"bytes,3,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                // Plan the grant is attributed to, by code or UUID
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`       // Optional expiry; never expires when unset
+	Complimentary bool                   `protobuf:"varint,5,opt,name=complimentary,proto3" json:"complimentary,omitempty"`               // Granted without payment; otherwise recorded as a manual grant
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`                              // Required; recorded in the audit log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PricingZone) GetZoneName() string {
-	if x != nil {
-		return x.ZoneName
-	}
+func (x *GrantEntitlementRequest) Reset() {
+	*x = GrantEntitlementRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantEntitlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantEntitlementRequest) ProtoMessage() {}
+
+func (x *GrantEntitlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantEntitlementRequest.ProtoReflect.Descriptor instead.
+func (*GrantEntitlementRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GrantEntitlementRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GrantEntitlementRequest) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *GrantEntitlementRequest) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *GrantEntitlementRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *GrantEntitlementRequest) GetComplimentary() bool {
+	if x != nil {
+		return x.Complimentary
+	}
+	return false
+}
+
+func (x *GrantEntitlementRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// GrantEntitlementResponse represents the granted entitlement
+type GrantEntitlementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entitlement   *Entitlement           `protobuf:"bytes,1,opt,name=entitlement,proto3" json:"entitlement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantEntitlementResponse) Reset() {
+	*x = GrantEntitlementResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantEntitlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantEntitlementResponse) ProtoMessage() {}
+
+func (x *GrantEntitlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantEntitlementResponse.ProtoReflect.Descriptor instead.
+func (*GrantEntitlementResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GrantEntitlementResponse) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+// RevokeEntitlementRequest represents a request to revoke an entitlement
+type RevokeEntitlementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`         // Entitlement identifier
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // Required; recorded in the audit log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeEntitlementRequest) Reset() {
+	*x = RevokeEntitlementRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeEntitlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeEntitlementRequest) ProtoMessage() {}
+
+func (x *RevokeEntitlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeEntitlementRequest.ProtoReflect.Descriptor instead.
+func (*RevokeEntitlementRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RevokeEntitlementRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RevokeEntitlementRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// RevokeEntitlementResponse represents the revoked entitlement
+type RevokeEntitlementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entitlement   *Entitlement           `protobuf:"bytes,1,opt,name=entitlement,proto3" json:"entitlement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeEntitlementResponse) Reset() {
+	*x = RevokeEntitlementResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeEntitlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeEntitlementResponse) ProtoMessage() {}
+
+func (x *RevokeEntitlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeEntitlementResponse.ProtoReflect.Descriptor instead.
+func (*RevokeEntitlementResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RevokeEntitlementResponse) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+// ExtendEntitlementRequest represents a request to move an entitlement's expiry later
+type ExtendEntitlementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                // Entitlement identifier
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // New expiry; must be after the current one
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                        // Required; recorded in the audit log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendEntitlementRequest) Reset() {
+	*x = ExtendEntitlementRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendEntitlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendEntitlementRequest) ProtoMessage() {}
+
+func (x *ExtendEntitlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendEntitlementRequest.ProtoReflect.Descriptor instead.
+func (*ExtendEntitlementRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExtendEntitlementRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ExtendEntitlementRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *ExtendEntitlementRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// ExtendEntitlementResponse represents the extended entitlement
+type ExtendEntitlementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entitlement   *Entitlement           `protobuf:"bytes,1,opt,name=entitlement,proto3" json:"entitlement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExtendEntitlementResponse) Reset() {
+	*x = ExtendEntitlementResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExtendEntitlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExtendEntitlementResponse) ProtoMessage() {}
+
+func (x *ExtendEntitlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExtendEntitlementResponse.ProtoReflect.Descriptor instead.
+func (*ExtendEntitlementResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ExtendEntitlementResponse) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+// ExplainEntitlementRequest represents a request to explain an entitlement check
+type ExplainEntitlementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // User identifier
+	FeatureCode   string                 `protobuf:"bytes,2,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"` // Feature code that was checked
+	FamilyId      string                 `protobuf:"bytes,3,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`          // Optional family whose entitlements to the feature are listed too
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainEntitlementRequest) Reset() {
+	*x = ExplainEntitlementRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainEntitlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainEntitlementRequest) ProtoMessage() {}
+
+func (x *ExplainEntitlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainEntitlementRequest.ProtoReflect.Descriptor instead.
+func (*ExplainEntitlementRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ExplainEntitlementRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ExplainEntitlementRequest) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *ExplainEntitlementRequest) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+// ExplainEntitlementResponse is the decision trace of an entitlement check
+type ExplainEntitlementResponse struct {
+	state            protoimpl.MessageState  `protogen:"open.v1"`
+	Allowed          bool                    `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`                                            // What CheckEntitlement returns
+	Rule             string                  `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`                                                   // Rule that decided, e.g. negative_cache, expired or no_plan_for_feature
+	Reason           string                  `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                               // Human-readable explanation of the decision
+	Candidates       []*EntitlementCandidate `protobuf:"bytes,4,rep,name=candidates,proto3" json:"candidates,omitempty"`                                       // The user's entitlements to the feature, newest first, then the family's
+	Cache            *EntitlementCacheState  `protobuf:"bytes,5,opt,name=cache,proto3" json:"cache,omitempty"`                                                 // What Redis holds for the check
+	PlansWithFeature []string                `protobuf:"bytes,6,rep,name=plans_with_feature,json=plansWithFeature,proto3" json:"plans_with_feature,omitempty"` // Catalog plans granting the feature, archived ones included
+	Feature          *Feature                `protobuf:"bytes,7,opt,name=feature,proto3" json:"feature,omitempty"`                                             // Feature catalog entry; unset if the feature is unknown
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExplainEntitlementResponse) Reset() {
+	*x = ExplainEntitlementResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainEntitlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainEntitlementResponse) ProtoMessage() {}
+
+func (x *ExplainEntitlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainEntitlementResponse.ProtoReflect.Descriptor instead.
+func (*ExplainEntitlementResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ExplainEntitlementResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *ExplainEntitlementResponse) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *ExplainEntitlementResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ExplainEntitlementResponse) GetCandidates() []*EntitlementCandidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *ExplainEntitlementResponse) GetCache() *EntitlementCacheState {
+	if x != nil {
+		return x.Cache
+	}
+	return nil
+}
+
+func (x *ExplainEntitlementResponse) GetPlansWithFeature() []string {
+	if x != nil {
+		return x.PlansWithFeature
+	}
+	return nil
+}
+
+func (x *ExplainEntitlementResponse) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+// EntitlementCandidate is an entitlement considered by an entitlement check
+type EntitlementCandidate struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Entitlement        *Entitlement           `protobuf:"bytes,1,opt,name=entitlement,proto3" json:"entitlement,omitempty"`                                         // The entitlement
+	Valid              bool                   `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`                                                    // Whether CheckEntitlement honours it
+	Reason             string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`                                                   // Why it is not honoured, e.g. "was revoked"; empty when valid
+	PlanCode           string                 `protobuf:"bytes,4,opt,name=plan_code,json=planCode,proto3" json:"plan_code,omitempty"`                               // Catalog plan it was granted from; empty if unknown
+	PlanGrantsFeature  bool                   `protobuf:"varint,5,opt,name=plan_grants_feature,json=planGrantsFeature,proto3" json:"plan_grants_feature,omitempty"` // Whether the plan still includes the feature
+	SubscriptionStatus string                 `protobuf:"bytes,6,opt,name=subscription_status,json=subscriptionStatus,proto3" json:"subscription_status,omitempty"` // Status of its subscription; empty if it has none or it is unknown
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *EntitlementCandidate) Reset() {
+	*x = EntitlementCandidate{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntitlementCandidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitlementCandidate) ProtoMessage() {}
+
+func (x *EntitlementCandidate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitlementCandidate.ProtoReflect.Descriptor instead.
+func (*EntitlementCandidate) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *EntitlementCandidate) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+func (x *EntitlementCandidate) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *EntitlementCandidate) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *EntitlementCandidate) GetPlanCode() string {
+	if x != nil {
+		return x.PlanCode
+	}
+	return ""
+}
+
+func (x *EntitlementCandidate) GetPlanGrantsFeature() bool {
+	if x != nil {
+		return x.PlanGrantsFeature
+	}
+	return false
+}
+
+func (x *EntitlementCandidate) GetSubscriptionStatus() string {
+	if x != nil {
+		return x.SubscriptionStatus
+	}
+	return ""
+}
+
+// EntitlementCacheState is what Redis holds for an entitlement check
+type EntitlementCacheState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`                   // Whether the cache is configured
+	NotFound      bool                   `protobuf:"varint,2,opt,name=not_found,json=notFound,proto3" json:"not_found,omitempty"` // A negative result is cached
+	Entitlement   *Entitlement           `protobuf:"bytes,3,opt,name=entitlement,proto3" json:"entitlement,omitempty"`            // Cached entitlement, if any
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                        // Set when Redis could not be read
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntitlementCacheState) Reset() {
+	*x = EntitlementCacheState{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntitlementCacheState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntitlementCacheState) ProtoMessage() {}
+
+func (x *EntitlementCacheState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntitlementCacheState.ProtoReflect.Descriptor instead.
+func (*EntitlementCacheState) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *EntitlementCacheState) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *EntitlementCacheState) GetNotFound() bool {
+	if x != nil {
+		return x.NotFound
+	}
+	return false
+}
+
+func (x *EntitlementCacheState) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+func (x *EntitlementCacheState) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// CheckEntitlementRequest represents a request to check user entitlement
+type CheckEntitlementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                // User identifier
+	FeatureCode   string                 `protobuf:"bytes,2,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"` // Feature code to check
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckEntitlementRequest) Reset() {
+	*x = CheckEntitlementRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckEntitlementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckEntitlementRequest) ProtoMessage() {}
+
+func (x *CheckEntitlementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckEntitlementRequest.ProtoReflect.Descriptor instead.
+func (*CheckEntitlementRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CheckEntitlementRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckEntitlementRequest) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+// CheckEntitlementResponse represents a response to entitlement check
+type CheckEntitlementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`        // Whether user has access
+	Entitlement   *Entitlement           `protobuf:"bytes,2,opt,name=entitlement,proto3" json:"entitlement,omitempty"` // Entitlement details (if exists)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckEntitlementResponse) Reset() {
+	*x = CheckEntitlementResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckEntitlementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckEntitlementResponse) ProtoMessage() {}
+
+func (x *CheckEntitlementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckEntitlementResponse.ProtoReflect.Descriptor instead.
+func (*CheckEntitlementResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *CheckEntitlementResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *CheckEntitlementResponse) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+// Entitlement represents a user entitlement
+type Entitlement struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                               // Entitlement identifier
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                         // User identifier
+	FamilyId       string                 `protobuf:"bytes,3,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`                   // Family identifier (optional)
+	FeatureCode    string                 `protobuf:"bytes,4,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`          // Feature code
+	PlanId         string                 `protobuf:"bytes,5,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                         // Plan identifier
+	SubscriptionId string                 `protobuf:"bytes,6,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"` // Subscription identifier (optional)
+	Status         string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`                                       // Entitlement status
+	GrantedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=granted_at,json=grantedAt,proto3" json:"granted_at,omitempty"`                // Grant timestamp
+	ExpiresAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                // Expiration timestamp (optional)
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`               // Creation timestamp
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`               // Last update timestamp
+	Source         string                 `protobuf:"bytes,12,opt,name=source,proto3" json:"source,omitempty"`                                      // paid, complimentary or manual for grants made by support
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Entitlement) Reset() {
+	*x = Entitlement{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entitlement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entitlement) ProtoMessage() {}
+
+func (x *Entitlement) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entitlement.ProtoReflect.Descriptor instead.
+func (*Entitlement) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Entitlement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Entitlement) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Entitlement) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+func (x *Entitlement) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *Entitlement) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *Entitlement) GetSubscriptionId() string {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return ""
+}
+
+func (x *Entitlement) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Entitlement) GetGrantedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GrantedAt
+	}
+	return nil
+}
+
+func (x *Entitlement) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Entitlement) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Entitlement) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Entitlement) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// ListPricingZonesRequest represents a request to list pricing zones
+type ListPricingZonesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPricingZonesRequest) Reset() {
+	*x = ListPricingZonesRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPricingZonesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPricingZonesRequest) ProtoMessage() {}
+
+func (x *ListPricingZonesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPricingZonesRequest.ProtoReflect.Descriptor instead.
+func (*ListPricingZonesRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{36}
+}
+
+// ListPricingZonesResponse represents a response with pricing zones list
+type ListPricingZonesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PricingZones  []*PricingZone         `protobuf:"bytes,1,rep,name=pricing_zones,json=pricingZones,proto3" json:"pricing_zones,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPricingZonesResponse) Reset() {
+	*x = ListPricingZonesResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPricingZonesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPricingZonesResponse) ProtoMessage() {}
+
+func (x *ListPricingZonesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPricingZonesResponse.ProtoReflect.Descriptor instead.
+func (*ListPricingZonesResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListPricingZonesResponse) GetPricingZones() []*PricingZone {
+	if x != nil {
+		return x.PricingZones
+	}
+	return nil
+}
+
+// PricingZone represents a pricing zone
+type PricingZone struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                          // Pricing zone identifier
+	IsoCode           string                 `protobuf:"bytes,2,opt,name=iso_code,json=isoCode,proto3" json:"iso_code,omitempty"`                                 // ISO country code
+	Country           string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`                                                // Country name
+	Zone              string                 `protobuf:"bytes,4,opt,name=zone,proto3" json:"zone,omitempty"`                                                      // Zone type (A, B, C, D)
+	ZoneName          string                 `protobuf:"bytes,5,opt,name=zone_name,json=zoneName,proto3" json:"zone_name,omitempty"`                              // Zone name (Premium, Mid-High, etc.)
+	PricingMultiplier float64                `protobuf:"fixed64,6,opt,name=pricing_multiplier,json=pricingMultiplier,proto3" json:"pricing_multiplier,omitempty"` // Pricing multiplier
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                           // Creation timestamp
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                           // Last update timestamp
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PricingZone) Reset() {
+	*x = PricingZone{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricingZone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricingZone) ProtoMessage() {}
+
+func (x *PricingZone) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricingZone.ProtoReflect.Descriptor instead.
+func (*PricingZone) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PricingZone) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PricingZone) GetIsoCode() string {
+	if x != nil {
+		return x.IsoCode
+	}
+	return ""
+}
+
+func (x *PricingZone) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *PricingZone) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *PricingZone) GetZoneName() string {
+	if x != nil {
+		return x.ZoneName
+	}
+	return ""
+}
+
+func (x *PricingZone) GetPricingMultiplier() float64 {
+	if x != nil {
+		return x.PricingMultiplier
+	}
+	return 0
+}
+
+func (x *PricingZone) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *PricingZone) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// BulkCheckEntitlementsRequest represents a request for bulk entitlement checking
+type BulkCheckEntitlementsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier
+	Checks        []*BulkCheckItem       `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`               // List of entitlement checks
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCheckEntitlementsRequest) Reset() {
+	*x = BulkCheckEntitlementsRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCheckEntitlementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCheckEntitlementsRequest) ProtoMessage() {}
+
+func (x *BulkCheckEntitlementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCheckEntitlementsRequest.ProtoReflect.Descriptor instead.
+func (*BulkCheckEntitlementsRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *BulkCheckEntitlementsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BulkCheckEntitlementsRequest) GetChecks() []*BulkCheckItem {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+// BulkCheckItem represents a single entitlement check
+type BulkCheckItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeatureCode   string                 `protobuf:"bytes,1,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`                                                  // Feature code to check
+	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`                                                                         // Specific operation (optional)
+	ResourceSize  int64                  `protobuf:"varint,3,opt,name=resource_size,json=resourceSize,proto3" json:"resource_size,omitempty"`                                              // Resource size for quota checks (optional)
+	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (optional)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCheckItem) Reset() {
+	*x = BulkCheckItem{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCheckItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCheckItem) ProtoMessage() {}
+
+func (x *BulkCheckItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCheckItem.ProtoReflect.Descriptor instead.
+func (*BulkCheckItem) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *BulkCheckItem) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *BulkCheckItem) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *BulkCheckItem) GetResourceSize() int64 {
+	if x != nil {
+		return x.ResourceSize
+	}
+	return 0
+}
+
+func (x *BulkCheckItem) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// BulkCheckEntitlementsResponse represents the response from bulk entitlement checking
+type BulkCheckEntitlementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkCheckResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // Results for each check
+	Summary       *BulkCheckSummary      `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"` // Summary statistics
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCheckEntitlementsResponse) Reset() {
+	*x = BulkCheckEntitlementsResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCheckEntitlementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCheckEntitlementsResponse) ProtoMessage() {}
+
+func (x *BulkCheckEntitlementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCheckEntitlementsResponse.ProtoReflect.Descriptor instead.
+func (*BulkCheckEntitlementsResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BulkCheckEntitlementsResponse) GetResults() []*BulkCheckResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BulkCheckEntitlementsResponse) GetSummary() *BulkCheckSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+// BulkCheckResult represents the result of a single entitlement check
+type BulkCheckResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FeatureCode   string                 `protobuf:"bytes,1,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`                                                  // Feature code that was checked
+	Authorized    bool                   `protobuf:"varint,2,opt,name=authorized,proto3" json:"authorized,omitempty"`                                                                      // Whether the user is authorized
+	Entitlement   *Entitlement           `protobuf:"bytes,3,opt,name=entitlement,proto3" json:"entitlement,omitempty"`                                                                     // Entitlement details (if authorized)
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                                                               // Reason for denial (if not authorized)
+	UpgradeUrl    string                 `protobuf:"bytes,5,opt,name=upgrade_url,json=upgradeUrl,proto3" json:"upgrade_url,omitempty"`                                                     // Upgrade URL (if not authorized)
+	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkCheckResult) Reset() {
+	*x = BulkCheckResult{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCheckResult) ProtoMessage() {}
+
+func (x *BulkCheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCheckResult.ProtoReflect.Descriptor instead.
+func (*BulkCheckResult) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BulkCheckResult) GetFeatureCode() string {
+	if x != nil {
+		return x.FeatureCode
+	}
+	return ""
+}
+
+func (x *BulkCheckResult) GetAuthorized() bool {
+	if x != nil {
+		return x.Authorized
+	}
+	return false
+}
+
+func (x *BulkCheckResult) GetEntitlement() *Entitlement {
+	if x != nil {
+		return x.Entitlement
+	}
+	return nil
+}
+
+func (x *BulkCheckResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BulkCheckResult) GetUpgradeUrl() string {
+	if x != nil {
+		return x.UpgradeUrl
+	}
+	return ""
+}
+
+func (x *BulkCheckResult) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// BulkCheckSummary provides summary statistics for bulk checks
+type BulkCheckSummary struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TotalChecks      int32                  `protobuf:"varint,1,opt,name=total_checks,json=totalChecks,proto3" json:"total_checks,omitempty"`                  // Total number of checks performed
+	Authorized       int32                  `protobuf:"varint,2,opt,name=authorized,proto3" json:"authorized,omitempty"`                                       // Number of authorized checks
+	NotAuthorized    int32                  `protobuf:"varint,3,opt,name=not_authorized,json=notAuthorized,proto3" json:"not_authorized,omitempty"`            // Number of not authorized checks
+	CacheHits        int32                  `protobuf:"varint,4,opt,name=cache_hits,json=cacheHits,proto3" json:"cache_hits,omitempty"`                        // Number of cache hits
+	CacheMisses      int32                  `protobuf:"varint,5,opt,name=cache_misses,json=cacheMisses,proto3" json:"cache_misses,omitempty"`                  // Number of cache misses
+	ProcessingTimeMs int64                  `protobuf:"varint,6,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"` // Processing time in milliseconds
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BulkCheckSummary) Reset() {
+	*x = BulkCheckSummary{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkCheckSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkCheckSummary) ProtoMessage() {}
+
+func (x *BulkCheckSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkCheckSummary.ProtoReflect.Descriptor instead.
+func (*BulkCheckSummary) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BulkCheckSummary) GetTotalChecks() int32 {
+	if x != nil {
+		return x.TotalChecks
+	}
+	return 0
+}
+
+func (x *BulkCheckSummary) GetAuthorized() int32 {
+	if x != nil {
+		return x.Authorized
+	}
+	return 0
+}
+
+func (x *BulkCheckSummary) GetNotAuthorized() int32 {
+	if x != nil {
+		return x.NotAuthorized
+	}
+	return 0
+}
+
+func (x *BulkCheckSummary) GetCacheHits() int32 {
+	if x != nil {
+		return x.CacheHits
+	}
+	return 0
+}
+
+func (x *BulkCheckSummary) GetCacheMisses() int32 {
+	if x != nil {
+		return x.CacheMisses
+	}
+	return 0
+}
+
+func (x *BulkCheckSummary) GetProcessingTimeMs() int64 {
+	if x != nil {
+		return x.ProcessingTimeMs
+	}
+	return 0
+}
+
+// Plan represents a catalog plan at its current version
+type Plan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                         // Catalog identifier, e.g. "pro_monthly"
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`                                     // Display name
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`                       // Description
+	FeatureCodes  []string               `protobuf:"bytes,4,rep,name=feature_codes,json=featureCodes,proto3" json:"feature_codes,omitempty"` // Features granted by the plan
+	BillingCycle  string                 `protobuf:"bytes,5,opt,name=billing_cycle,json=billingCycle,proto3" json:"billing_cycle,omitempty"` // monthly, yearly or one_time
+	PriceCents    int64                  `protobuf:"varint,6,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`      // Price in cents
+	Currency      string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`                             // Currency code
+	MaxUsers      int32                  `protobuf:"varint,8,opt,name=max_users,json=maxUsers,proto3" json:"max_users,omitempty"`            // Seats for family plans (0 = unlimited/individual)
+	UsageLimits   string                 `protobuf:"bytes,9,opt,name=usage_limits,json=usageLimits,proto3" json:"usage_limits,omitempty"`    // Usage limits as a JSON object
+	Metadata      string                 `protobuf:"bytes,10,opt,name=metadata,proto3" json:"metadata,omitempty"`                            // Additional metadata as a JSON object
+	Active        bool                   `protobuf:"varint,11,opt,name=active,proto3" json:"active,omitempty"`                               // Whether the plan can be sold
+	Version       int32                  `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`                             // Current plan version
+	ArchivedAt    *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`      // Set once the plan is archived
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`         // Creation timestamp
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`         // Last update timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Plan) Reset() {
+	*x = Plan{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plan) ProtoMessage() {}
+
+func (x *Plan) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plan.ProtoReflect.Descriptor instead.
+func (*Plan) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Plan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Plan) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Plan) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Plan) GetFeatureCodes() []string {
+	if x != nil {
+		return x.FeatureCodes
+	}
+	return nil
+}
+
+func (x *Plan) GetBillingCycle() string {
+	if x != nil {
+		return x.BillingCycle
+	}
+	return ""
+}
+
+func (x *Plan) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *Plan) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Plan) GetMaxUsers() int32 {
+	if x != nil {
+		return x.MaxUsers
+	}
+	return 0
+}
+
+func (x *Plan) GetUsageLimits() string {
+	if x != nil {
+		return x.UsageLimits
+	}
+	return ""
+}
+
+func (x *Plan) GetMetadata() string {
+	if x != nil {
+		return x.Metadata
+	}
+	return ""
+}
+
+func (x *Plan) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Plan) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Plan) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *Plan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Plan) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// PlanVersion represents an immutable revision of a plan's pricing and features
+type PlanVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanId        string                 `protobuf:"bytes,1,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                   // Catalog identifier
+	Version       int32                  `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`                              // Version number
+	FeatureCodes  []string               `protobuf:"bytes,3,rep,name=feature_codes,json=featureCodes,proto3" json:"feature_codes,omitempty"` // Features granted at this version
+	BillingCycle  string                 `protobuf:"bytes,4,opt,name=billing_cycle,json=billingCycle,proto3" json:"billing_cycle,omitempty"` // Billing cycle at this version
+	PriceCents    int64                  `protobuf:"varint,5,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`      // Price in cents at this version
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                             // Currency code
+	MaxUsers      int32                  `protobuf:"varint,7,opt,name=max_users,json=maxUsers,proto3" json:"max_users,omitempty"`            // Seats at this version
+	UsageLimits   string                 `protobuf:"bytes,8,opt,name=usage_limits,json=usageLimits,proto3" json:"usage_limits,omitempty"`    // Usage limits as a JSON object
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`          // When the version was created
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanVersion) Reset() {
+	*x = PlanVersion{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanVersion) ProtoMessage() {}
+
+func (x *PlanVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanVersion.ProtoReflect.Descriptor instead.
+func (*PlanVersion) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PlanVersion) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *PlanVersion) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *PlanVersion) GetFeatureCodes() []string {
+	if x != nil {
+		return x.FeatureCodes
+	}
+	return nil
+}
+
+func (x *PlanVersion) GetBillingCycle() string {
+	if x != nil {
+		return x.BillingCycle
+	}
+	return ""
+}
+
+func (x *PlanVersion) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *PlanVersion) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PlanVersion) GetMaxUsers() int32 {
+	if x != nil {
+		return x.MaxUsers
+	}
+	return 0
+}
+
+func (x *PlanVersion) GetUsageLimits() string {
+	if x != nil {
+		return x.UsageLimits
+	}
+	return ""
+}
+
+func (x *PlanVersion) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreatePlanRequest represents a request to create a plan. Output-only
+// fields (active, version, timestamps) are ignored.
+type CreatePlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePlanRequest) Reset() {
+	*x = CreatePlanRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlanRequest) ProtoMessage() {}
+
+func (x *CreatePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlanRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlanRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *CreatePlanRequest) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+// CreatePlanResponse represents the created plan
+type CreatePlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePlanResponse) Reset() {
+	*x = CreatePlanResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlanResponse) ProtoMessage() {}
+
+func (x *CreatePlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlanResponse.ProtoReflect.Descriptor instead.
+func (*CreatePlanResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *CreatePlanResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+// UpdatePlanRequest represents a request to update a plan
+type UpdatePlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`                               // Plan with id and the fields to change
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"` // Fields of plan to update
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePlanRequest) Reset() {
+	*x = UpdatePlanRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePlanRequest) ProtoMessage() {}
+
+func (x *UpdatePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePlanRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePlanRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *UpdatePlanRequest) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *UpdatePlanRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// UpdatePlanResponse represents the updated plan
+type UpdatePlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	NewVersion    bool                   `protobuf:"varint,2,opt,name=new_version,json=newVersion,proto3" json:"new_version,omitempty"` // Whether the update created a new plan version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePlanResponse) Reset() {
+	*x = UpdatePlanResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePlanResponse) ProtoMessage() {}
+
+func (x *UpdatePlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePlanResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePlanResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *UpdatePlanResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *UpdatePlanResponse) GetNewVersion() bool {
+	if x != nil {
+		return x.NewVersion
+	}
+	return false
+}
+
+// ArchivePlanRequest represents a request to archive a plan
+type ArchivePlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Catalog identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchivePlanRequest) Reset() {
+	*x = ArchivePlanRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePlanRequest) ProtoMessage() {}
+
+func (x *ArchivePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePlanRequest.ProtoReflect.Descriptor instead.
+func (*ArchivePlanRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ArchivePlanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ArchivePlanResponse represents the archived plan
+type ArchivePlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchivePlanResponse) Reset() {
+	*x = ArchivePlanResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivePlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePlanResponse) ProtoMessage() {}
+
+func (x *ArchivePlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePlanResponse.ProtoReflect.Descriptor instead.
+func (*ArchivePlanResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ArchivePlanResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+// ListPlansRequest represents a request to list plans
+type ListPlansRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncludeArchived bool                   `protobuf:"varint,1,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // Include archived plans
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPlansRequest) Reset() {
+	*x = ListPlansRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlansRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlansRequest) ProtoMessage() {}
+
+func (x *ListPlansRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlansRequest.ProtoReflect.Descriptor instead.
+func (*ListPlansRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListPlansRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+// ListPlansResponse represents a response with plans
+type ListPlansResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plans         []*Plan                `protobuf:"bytes,1,rep,name=plans,proto3" json:"plans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlansResponse) Reset() {
+	*x = ListPlansResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlansResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlansResponse) ProtoMessage() {}
+
+func (x *ListPlansResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlansResponse.ProtoReflect.Descriptor instead.
+func (*ListPlansResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListPlansResponse) GetPlans() []*Plan {
+	if x != nil {
+		return x.Plans
+	}
+	return nil
+}
+
+// GetPlanRequest represents a request to get a plan
+type GetPlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // Catalog identifier
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlanRequest) Reset() {
+	*x = GetPlanRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlanRequest) ProtoMessage() {}
+
+func (x *GetPlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlanRequest.ProtoReflect.Descriptor instead.
+func (*GetPlanRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetPlanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// GetPlanResponse represents a plan with its version history
+type GetPlanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Plan          *Plan                  `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	Versions      []*PlanVersion         `protobuf:"bytes,2,rep,name=versions,proto3" json:"versions,omitempty"` // All versions, newest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlanResponse) Reset() {
+	*x = GetPlanResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlanResponse) ProtoMessage() {}
+
+func (x *GetPlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlanResponse.ProtoReflect.Descriptor instead.
+func (*GetPlanResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetPlanResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *GetPlanResponse) GetVersions() []*PlanVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// Feature represents an entry of the feature catalog
+type Feature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`                                                                                                            // Feature code, e.g. "pro_storage"
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`                                                                                              // Description
+	QuotaSchema   map[string]string      `protobuf:"bytes,3,rep,name=quota_schema,json=quotaSchema,proto3" json:"quota_schema,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // usage_limits keys the feature governs, by JSON type: integer, number, string, boolean, array or object
+	DefaultLimits string                 `protobuf:"bytes,4,opt,name=default_limits,json=defaultLimits,proto3" json:"default_limits,omitempty"`                                                                     // Limits granted when a plan sets none, as a JSON object
+	Deprecated    bool                   `protobuf:"varint,5,opt,name=deprecated,proto3" json:"deprecated,omitempty"`                                                                                               // Cannot be added to plans; existing grants keep working
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                                                                                 // Creation timestamp
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`                                                                                 // Last update timestamp
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Feature) Reset() {
+	*x = Feature{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Feature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Feature) ProtoMessage() {}
+
+func (x *Feature) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Feature.ProtoReflect.Descriptor instead.
+func (*Feature) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *Feature) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Feature) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Feature) GetQuotaSchema() map[string]string {
+	if x != nil {
+		return x.QuotaSchema
+	}
+	return nil
+}
+
+func (x *Feature) GetDefaultLimits() string {
+	if x != nil {
+		return x.DefaultLimits
+	}
+	return ""
+}
+
+func (x *Feature) GetDeprecated() bool {
+	if x != nil {
+		return x.Deprecated
+	}
+	return false
+}
+
+func (x *Feature) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Feature) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ListFeaturesRequest represents a request to list the feature catalog
+type ListFeaturesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeaturesRequest) Reset() {
+	*x = ListFeaturesRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeaturesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeaturesRequest) ProtoMessage() {}
+
+func (x *ListFeaturesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeaturesRequest.ProtoReflect.Descriptor instead.
+func (*ListFeaturesRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{57}
+}
+
+// ListFeaturesResponse represents a response with catalog features
+type ListFeaturesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Features      []*Feature             `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty"` // By code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFeaturesResponse) Reset() {
+	*x = ListFeaturesResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFeaturesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFeaturesResponse) ProtoMessage() {}
+
+func (x *ListFeaturesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFeaturesResponse.ProtoReflect.Descriptor instead.
+func (*ListFeaturesResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListFeaturesResponse) GetFeatures() []*Feature {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+// CreateFeatureRequest represents a request to create a feature. Output-only
+// fields (deprecated, timestamps) are ignored.
+type CreateFeatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       *Feature               `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFeatureRequest) Reset() {
+	*x = CreateFeatureRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFeatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeatureRequest) ProtoMessage() {}
+
+func (x *CreateFeatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeatureRequest.ProtoReflect.Descriptor instead.
+func (*CreateFeatureRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *CreateFeatureRequest) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+// CreateFeatureResponse represents the created feature
+type CreateFeatureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       *Feature               `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFeatureResponse) Reset() {
+	*x = CreateFeatureResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFeatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFeatureResponse) ProtoMessage() {}
+
+func (x *CreateFeatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFeatureResponse.ProtoReflect.Descriptor instead.
+func (*CreateFeatureResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CreateFeatureResponse) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+// UpdateFeatureRequest represents a request to update a feature
+type UpdateFeatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       *Feature               `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`                         // Feature with code and the fields to change
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"` // Fields of feature to update
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFeatureRequest) Reset() {
+	*x = UpdateFeatureRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFeatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeatureRequest) ProtoMessage() {}
+
+func (x *UpdateFeatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeatureRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFeatureRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *UpdateFeatureRequest) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+func (x *UpdateFeatureRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+// UpdateFeatureResponse represents the updated feature
+type UpdateFeatureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Feature       *Feature               `protobuf:"bytes,1,opt,name=feature,proto3" json:"feature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFeatureResponse) Reset() {
+	*x = UpdateFeatureResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFeatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeatureResponse) ProtoMessage() {}
+
+func (x *UpdateFeatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeatureResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFeatureResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *UpdateFeatureResponse) GetFeature() *Feature {
+	if x != nil {
+		return x.Feature
+	}
+	return nil
+}
+
+// CheckoutSession represents a checkout opened with a billing provider and
+// the price quoted for it
+type CheckoutSession struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	SessionId         string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                            // Provider session ID
+	Provider          string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`                                               // Billing provider, e.g. "stripe"
+	PlanId            string                 `protobuf:"bytes,3,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                                     // Catalog identifier
+	PlanVersion       int32                  `protobuf:"varint,4,opt,name=plan_version,json=planVersion,proto3" json:"plan_version,omitempty"`                     // Plan version the price was quoted from
+	UserId            string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                     // User identifier
+	FamilyId          string                 `protobuf:"bytes,6,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`                               // Family identifier (optional)
+	CountryCode       string                 `protobuf:"bytes,7,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`                      // Country code used for pricing
+	BasePrice         float64                `protobuf:"fixed64,8,opt,name=base_price,json=basePrice,proto3" json:"base_price,omitempty"`                          // Catalog price in dollars
+	QuotedPrice       float64                `protobuf:"fixed64,9,opt,name=quoted_price,json=quotedPrice,proto3" json:"quoted_price,omitempty"`                    // Price after the pricing multiplier in dollars
+	PricingMultiplier float64                `protobuf:"fixed64,10,opt,name=pricing_multiplier,json=pricingMultiplier,proto3" json:"pricing_multiplier,omitempty"` // Applied pricing zone multiplier
+	Currency          string                 `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`                                              // Currency code
+	Status            string                 `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`                                                  // open, completing, complete, needs_review, expired or cancelled
+	Url               string                 `protobuf:"bytes,13,opt,name=url,proto3" json:"url,omitempty"`                                                        // Checkout URL
+	ExpiresAt         *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                           // Session expiration
+	CompletedAt       *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`                     // Set once payment completes
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                           // Creation timestamp
+	Region            string                 `protobuf:"bytes,17,opt,name=region,proto3" json:"region,omitempty"`                                                  // State or province used for tax
+	TaxId             string                 `protobuf:"bytes,18,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`                                       // Buyer's business tax ID
+	TaxName           string                 `protobuf:"bytes,19,opt,name=tax_name,json=taxName,proto3" json:"tax_name,omitempty"`                                 // e.g. VAT, GST or Sales tax; empty when untaxed
+	TaxRatePercent    float64                `protobuf:"fixed64,20,opt,name=tax_rate_percent,json=taxRatePercent,proto3" json:"tax_rate_percent,omitempty"`        // Applied tax rate in percent
+	TaxInclusive      bool                   `protobuf:"varint,21,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`                 // Whether quoted_price includes the tax
+	ReverseCharge     bool                   `protobuf:"varint,22,opt,name=reverse_charge,json=reverseCharge,proto3" json:"reverse_charge,omitempty"`              // Whether the buyer accounts for the tax
+	TaxAmount         float64                `protobuf:"fixed64,23,opt,name=tax_amount,json=taxAmount,proto3" json:"tax_amount,omitempty"`                         // Tax in dollars
+	TotalPrice        float64                `protobuf:"fixed64,24,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`                      // Amount charged in dollars, including tax
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CheckoutSession) Reset() {
+	*x = CheckoutSession{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutSession) ProtoMessage() {}
+
+func (x *CheckoutSession) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutSession.ProtoReflect.Descriptor instead.
+func (*CheckoutSession) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *CheckoutSession) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetPlanVersion() int32 {
+	if x != nil {
+		return x.PlanVersion
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetBasePrice() float64 {
+	if x != nil {
+		return x.BasePrice
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetQuotedPrice() float64 {
+	if x != nil {
+		return x.QuotedPrice
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetPricingMultiplier() float64 {
+	if x != nil {
+		return x.PricingMultiplier
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *CheckoutSession) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *CheckoutSession) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *CheckoutSession) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetTaxName() string {
+	if x != nil {
+		return x.TaxName
+	}
+	return ""
+}
+
+func (x *CheckoutSession) GetTaxRatePercent() float64 {
+	if x != nil {
+		return x.TaxRatePercent
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *CheckoutSession) GetReverseCharge() bool {
+	if x != nil {
+		return x.ReverseCharge
+	}
+	return false
+}
+
+func (x *CheckoutSession) GetTaxAmount() float64 {
+	if x != nil {
+		return x.TaxAmount
+	}
+	return 0
+}
+
+func (x *CheckoutSession) GetTotalPrice() float64 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+// GetCheckoutSessionRequest represents a request to get a checkout session
+type GetCheckoutSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Provider session ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutSessionRequest) Reset() {
+	*x = GetCheckoutSessionRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutSessionRequest) ProtoMessage() {}
+
+func (x *GetCheckoutSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutSessionRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckoutSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetCheckoutSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// GetCheckoutSessionResponse represents a checkout session
+type GetCheckoutSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *CheckoutSession       `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutSessionResponse) Reset() {
+	*x = GetCheckoutSessionResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutSessionResponse) ProtoMessage() {}
+
+func (x *GetCheckoutSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutSessionResponse.ProtoReflect.Descriptor instead.
+func (*GetCheckoutSessionResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetCheckoutSessionResponse) GetSession() *CheckoutSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// CancelCheckoutSessionRequest represents a request to cancel a checkout session
+type CancelCheckoutSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // Provider session ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCheckoutSessionRequest) Reset() {
+	*x = CancelCheckoutSessionRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCheckoutSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCheckoutSessionRequest) ProtoMessage() {}
+
+func (x *CancelCheckoutSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCheckoutSessionRequest.ProtoReflect.Descriptor instead.
+func (*CancelCheckoutSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *CancelCheckoutSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+// CancelCheckoutSessionResponse represents the cancelled checkout session
+type CancelCheckoutSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *CheckoutSession       `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCheckoutSessionResponse) Reset() {
+	*x = CancelCheckoutSessionResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCheckoutSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCheckoutSessionResponse) ProtoMessage() {}
+
+func (x *CancelCheckoutSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCheckoutSessionResponse.ProtoReflect.Descriptor instead.
+func (*CancelCheckoutSessionResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *CancelCheckoutSessionResponse) GetSession() *CheckoutSession {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+// Address represents a postal address
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line1         string                 `protobuf:"bytes,1,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,2,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"` // State, province or county
+	PostalCode    string                 `protobuf:"bytes,5,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+// BillingDetails identifies the buyer on an invoice
+type BillingDetails struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Address       *Address               `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	TaxId         string                 `protobuf:"bytes,4,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"` // Business tax ID, e.g. a VAT number
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BillingDetails) Reset() {
+	*x = BillingDetails{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BillingDetails) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BillingDetails) ProtoMessage() {}
+
+func (x *BillingDetails) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BillingDetails.ProtoReflect.Descriptor instead.
+func (*BillingDetails) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *BillingDetails) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BillingDetails) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *BillingDetails) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *BillingDetails) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+// InvoiceLineItem represents one line of an invoice
+type InvoiceLineItem struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Kind            string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"` // plan, zone_adjustment, discount or tax
+	Description     string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity        int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitAmountCents int64                  `protobuf:"varint,4,opt,name=unit_amount_cents,json=unitAmountCents,proto3" json:"unit_amount_cents,omitempty"`
+	AmountCents     int64                  `protobuf:"varint,5,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"` // Signed; discounts are negative
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *InvoiceLineItem) Reset() {
+	*x = InvoiceLineItem{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvoiceLineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvoiceLineItem) ProtoMessage() {}
+
+func (x *InvoiceLineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvoiceLineItem.ProtoReflect.Descriptor instead.
+func (*InvoiceLineItem) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *InvoiceLineItem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *InvoiceLineItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *InvoiceLineItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *InvoiceLineItem) GetUnitAmountCents() int64 {
+	if x != nil {
+		return x.UnitAmountCents
+	}
+	return 0
+}
+
+func (x *InvoiceLineItem) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+// Invoice represents an invoice issued for a completed payment
+type Invoice struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                                          // Invoice identifier
+	Number            string                 `protobuf:"bytes,2,opt,name=number,proto3" json:"number,omitempty"`                                                  // Sequential number, e.g. INV-2025-000042
+	PaymentId         string                 `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`                           // Invoiced payment
+	CheckoutSessionId string                 `protobuf:"bytes,4,opt,name=checkout_session_id,json=checkoutSessionId,proto3" json:"checkout_session_id,omitempty"` // Checkout session (optional)
+	UserId            string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                    // User identifier
+	FamilyId          string                 `protobuf:"bytes,6,opt,name=family_id,json=familyId,proto3" json:"family_id,omitempty"`                              // Family identifier (optional)
+	PlanId            string                 `protobuf:"bytes,7,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                                    // Catalog identifier
+	PlanVersion       int32                  `protobuf:"varint,8,opt,name=plan_version,json=planVersion,proto3" json:"plan_version,omitempty"`                    // Plan version invoiced
+	Currency          string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`                                              // Currency code
+	SubtotalCents     int64                  `protobuf:"varint,10,opt,name=subtotal_cents,json=subtotalCents,proto3" json:"subtotal_cents,omitempty"`
+	DiscountCents     int64                  `protobuf:"varint,11,opt,name=discount_cents,json=discountCents,proto3" json:"discount_cents,omitempty"`
+	TaxCents          int64                  `protobuf:"varint,12,opt,name=tax_cents,json=taxCents,proto3" json:"tax_cents,omitempty"`
+	TotalCents        int64                  `protobuf:"varint,13,opt,name=total_cents,json=totalCents,proto3" json:"total_cents,omitempty"`
+	Billing           *BillingDetails        `protobuf:"bytes,14,opt,name=billing,proto3" json:"billing,omitempty"`
+	Status            string                 `protobuf:"bytes,15,opt,name=status,proto3" json:"status,omitempty"` // issued or void
+	LineItems         []*InvoiceLineItem     `protobuf:"bytes,16,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	IssuedAt          *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`                 // Issue timestamp
+	TaxInclusive      bool                   `protobuf:"varint,18,opt,name=tax_inclusive,json=taxInclusive,proto3" json:"tax_inclusive,omitempty"`    // Whether line amounts include the tax line
+	ReverseCharge     bool                   `protobuf:"varint,19,opt,name=reverse_charge,json=reverseCharge,proto3" json:"reverse_charge,omitempty"` // Whether the buyer accounts for the tax
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Invoice) Reset() {
+	*x = Invoice{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Invoice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Invoice) ProtoMessage() {}
+
+func (x *Invoice) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Invoice.ProtoReflect.Descriptor instead.
+func (*Invoice) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *Invoice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Invoice) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Invoice) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Invoice) GetCheckoutSessionId() string {
+	if x != nil {
+		return x.CheckoutSessionId
+	}
+	return ""
+}
+
+func (x *Invoice) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Invoice) GetFamilyId() string {
+	if x != nil {
+		return x.FamilyId
+	}
+	return ""
+}
+
+func (x *Invoice) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *Invoice) GetPlanVersion() int32 {
+	if x != nil {
+		return x.PlanVersion
+	}
+	return 0
+}
+
+func (x *Invoice) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Invoice) GetSubtotalCents() int64 {
+	if x != nil {
+		return x.SubtotalCents
+	}
+	return 0
+}
+
+func (x *Invoice) GetDiscountCents() int64 {
+	if x != nil {
+		return x.DiscountCents
+	}
+	return 0
+}
+
+func (x *Invoice) GetTaxCents() int64 {
+	if x != nil {
+		return x.TaxCents
+	}
+	return 0
+}
+
+func (x *Invoice) GetTotalCents() int64 {
+	if x != nil {
+		return x.TotalCents
+	}
+	return 0
+}
+
+func (x *Invoice) GetBilling() *BillingDetails {
+	if x != nil {
+		return x.Billing
+	}
+	return nil
+}
+
+func (x *Invoice) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Invoice) GetLineItems() []*InvoiceLineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+func (x *Invoice) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *Invoice) GetTaxInclusive() bool {
+	if x != nil {
+		return x.TaxInclusive
+	}
+	return false
+}
+
+func (x *Invoice) GetReverseCharge() bool {
+	if x != nil {
+		return x.ReverseCharge
+	}
+	return false
+}
+
+// GetInvoiceRequest represents a request to get an invoice
+type GetInvoiceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                        // Invoice ID or invoice number
+	Format        InvoiceFormat          `protobuf:"varint,2,opt,name=format,proto3,enum=payment.v1.InvoiceFormat" json:"format,omitempty"` // Document to render (optional)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInvoiceRequest) Reset() {
+	*x = GetInvoiceRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInvoiceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInvoiceRequest) ProtoMessage() {}
+
+func (x *GetInvoiceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInvoiceRequest.ProtoReflect.Descriptor instead.
+func (*GetInvoiceRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetInvoiceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetInvoiceRequest) GetFormat() InvoiceFormat {
+	if x != nil {
+		return x.Format
+	}
+	return InvoiceFormat_INVOICE_FORMAT_UNSPECIFIED
+}
+
+// GetInvoiceResponse represents an invoice and its rendered document
+type GetInvoiceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invoice       *Invoice               `protobuf:"bytes,1,opt,name=invoice,proto3" json:"invoice,omitempty"`
+	Document      []byte                 `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`                          // Rendered document, empty without a format
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // MIME type of document
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInvoiceResponse) Reset() {
+	*x = GetInvoiceResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInvoiceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInvoiceResponse) ProtoMessage() {}
+
+func (x *GetInvoiceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInvoiceResponse.ProtoReflect.Descriptor instead.
+func (*GetInvoiceResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetInvoiceResponse) GetInvoice() *Invoice {
+	if x != nil {
+		return x.Invoice
+	}
+	return nil
+}
+
+func (x *GetInvoiceResponse) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *GetInvoiceResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// ListInvoicesRequest represents a request to list invoices
+type ListInvoicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`          // Defaults to the caller; only admins may list other users or, left empty, every user
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Invoices per page (default 50, max 500)
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // Token from a previous response
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvoicesRequest) Reset() {
+	*x = ListInvoicesRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvoicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesRequest) ProtoMessage() {}
+
+func (x *ListInvoicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesRequest.ProtoReflect.Descriptor instead.
+func (*ListInvoicesRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *ListInvoicesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListInvoicesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListInvoicesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// ListInvoicesResponse represents a page of invoices
+type ListInvoicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invoices      []*Invoice             `protobuf:"bytes,1,rep,name=invoices,proto3" json:"invoices,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInvoicesResponse) Reset() {
+	*x = ListInvoicesResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInvoicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInvoicesResponse) ProtoMessage() {}
+
+func (x *ListInvoicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInvoicesResponse.ProtoReflect.Descriptor instead.
+func (*ListInvoicesResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ListInvoicesResponse) GetInvoices() []*Invoice {
+	if x != nil {
+		return x.Invoices
+	}
+	return nil
+}
+
+func (x *ListInvoicesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Customer represents a user's billing profile
+type Customer struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                       // Customer identifier
+	UserId             string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier
+	Name               string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Email              string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Address            *Address               `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	TaxId              string                 `protobuf:"bytes,6,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`                                          // Business tax ID, normalized
+	PreferredCurrency  string                 `protobuf:"bytes,7,opt,name=preferred_currency,json=preferredCurrency,proto3" json:"preferred_currency,omitempty"`      // ISO 4217 code (optional)
+	Provider           string                 `protobuf:"bytes,8,opt,name=provider,proto3" json:"provider,omitempty"`                                                 // Billing provider holding the customer
+	ProviderCustomerId string                 `protobuf:"bytes,9,opt,name=provider_customer_id,json=providerCustomerId,proto3" json:"provider_customer_id,omitempty"` // Customer ID at the billing provider
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *Customer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Customer) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Customer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *Customer) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+func (x *Customer) GetPreferredCurrency() string {
+	if x != nil {
+		return x.PreferredCurrency
+	}
+	return ""
+}
+
+func (x *Customer) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Customer) GetProviderCustomerId() string {
+	if x != nil {
+		return x.ProviderCustomerId
+	}
+	return ""
+}
+
+func (x *Customer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Customer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// SavedPaymentMethod represents a payment method saved for a customer
+type SavedPaymentMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`                                    // Payment method identifier
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`                        // Billing provider that tokenized it
+	Type          PaymentMethod          `protobuf:"varint,3,opt,name=type,proto3,enum=payment.v1.PaymentMethod" json:"type,omitempty"` // Credit card, debit card, bank transfer or wallet
+	Brand         string                 `protobuf:"bytes,4,opt,name=brand,proto3" json:"brand,omitempty"`                              // e.g. visa
+	Last4         string                 `protobuf:"bytes,5,opt,name=last4,proto3" json:"last4,omitempty"`                              // Last four digits
+	ExpMonth      int32                  `protobuf:"varint,6,opt,name=exp_month,json=expMonth,proto3" json:"exp_month,omitempty"`
+	ExpYear       int32                  `protobuf:"varint,7,opt,name=exp_year,json=expYear,proto3" json:"exp_year,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,8,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"` // Reused by checkout and dunning retries
+	Expired       bool                   `protobuf:"varint,9,opt,name=expired,proto3" json:"expired,omitempty"`                      // Past its expiry date
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SavedPaymentMethod) Reset() {
+	*x = SavedPaymentMethod{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SavedPaymentMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SavedPaymentMethod) ProtoMessage() {}
+
+func (x *SavedPaymentMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SavedPaymentMethod.ProtoReflect.Descriptor instead.
+func (*SavedPaymentMethod) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *SavedPaymentMethod) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SavedPaymentMethod) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *SavedPaymentMethod) GetType() PaymentMethod {
+	if x != nil {
+		return x.Type
+	}
+	return PaymentMethod_PAYMENT_METHOD_UNSPECIFIED
+}
+
+func (x *SavedPaymentMethod) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *SavedPaymentMethod) GetLast4() string {
+	if x != nil {
+		return x.Last4
+	}
+	return ""
+}
+
+func (x *SavedPaymentMethod) GetExpMonth() int32 {
+	if x != nil {
+		return x.ExpMonth
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetExpYear() int32 {
+	if x != nil {
+		return x.ExpYear
+	}
+	return 0
+}
+
+func (x *SavedPaymentMethod) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *SavedPaymentMethod) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *SavedPaymentMethod) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// UpsertCustomerRequest represents a billing profile to save
+type UpsertCustomerRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier (defaults to the caller)
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email             string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Address           *Address               `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	TaxId             string                 `protobuf:"bytes,5,opt,name=tax_id,json=taxId,proto3" json:"tax_id,omitempty"`                                     // Business tax ID (optional)
+	PreferredCurrency string                 `protobuf:"bytes,6,opt,name=preferred_currency,json=preferredCurrency,proto3" json:"preferred_currency,omitempty"` // ISO 4217 code (optional)
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UpsertCustomerRequest) Reset() {
+	*x = UpsertCustomerRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertCustomerRequest) ProtoMessage() {}
+
+func (x *UpsertCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertCustomerRequest.ProtoReflect.Descriptor instead.
+func (*UpsertCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *UpsertCustomerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpsertCustomerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpsertCustomerRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UpsertCustomerRequest) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *UpsertCustomerRequest) GetTaxId() string {
+	if x != nil {
+		return x.TaxId
+	}
+	return ""
+}
+
+func (x *UpsertCustomerRequest) GetPreferredCurrency() string {
+	if x != nil {
+		return x.PreferredCurrency
+	}
+	return ""
+}
+
+// UpsertCustomerResponse represents the saved billing profile
+type UpsertCustomerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertCustomerResponse) Reset() {
+	*x = UpsertCustomerResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertCustomerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertCustomerResponse) ProtoMessage() {}
+
+func (x *UpsertCustomerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertCustomerResponse.ProtoReflect.Descriptor instead.
+func (*UpsertCustomerResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *UpsertCustomerResponse) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+// GetCustomerRequest represents a request to get a billing profile
+type GetCustomerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier (defaults to the caller)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerRequest) Reset() {
+	*x = GetCustomerRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerRequest) ProtoMessage() {}
+
+func (x *GetCustomerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerRequest.ProtoReflect.Descriptor instead.
+func (*GetCustomerRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetCustomerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetCustomerResponse represents a billing profile
+type GetCustomerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Customer      *Customer              `protobuf:"bytes,1,opt,name=customer,proto3" json:"customer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCustomerResponse) Reset() {
+	*x = GetCustomerResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCustomerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCustomerResponse) ProtoMessage() {}
+
+func (x *GetCustomerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCustomerResponse.ProtoReflect.Descriptor instead.
+func (*GetCustomerResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *GetCustomerResponse) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+// AddPaymentMethodRequest represents a payment method to save
+type AddPaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`              // User identifier (defaults to the caller)
+	Token         string                 `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`                              // Payment method token from the provider's client SDK
+	SetDefault    bool                   `protobuf:"varint,3,opt,name=set_default,json=setDefault,proto3" json:"set_default,omitempty"` // Make it the default; the first method always is
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodRequest) Reset() {
+	*x = AddPaymentMethodRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodRequest) ProtoMessage() {}
+
+func (x *AddPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *AddPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddPaymentMethodRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *AddPaymentMethodRequest) GetSetDefault() bool {
+	if x != nil {
+		return x.SetDefault
+	}
+	return false
+}
+
+// AddPaymentMethodResponse represents the saved payment method
+type AddPaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethod *SavedPaymentMethod    `protobuf:"bytes,1,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodResponse) Reset() {
+	*x = AddPaymentMethodResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodResponse) ProtoMessage() {}
+
+func (x *AddPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *AddPaymentMethodResponse) GetPaymentMethod() *SavedPaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return nil
+}
+
+// ListPaymentMethodsRequest represents a request to list saved payment methods
+type ListPaymentMethodsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier (defaults to the caller)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentMethodsRequest) Reset() {
+	*x = ListPaymentMethodsRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentMethodsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentMethodsRequest) ProtoMessage() {}
+
+func (x *ListPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentMethodsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentMethodsRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *ListPaymentMethodsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ListPaymentMethodsResponse represents a user's saved payment methods
+type ListPaymentMethodsResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethods []*SavedPaymentMethod  `protobuf:"bytes,1,rep,name=payment_methods,json=paymentMethods,proto3" json:"payment_methods,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListPaymentMethodsResponse) Reset() {
+	*x = ListPaymentMethodsResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentMethodsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentMethodsResponse) ProtoMessage() {}
+
+func (x *ListPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentMethodsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentMethodsResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *ListPaymentMethodsResponse) GetPaymentMethods() []*SavedPaymentMethod {
+	if x != nil {
+		return x.PaymentMethods
+	}
+	return nil
+}
+
+// SetDefaultPaymentMethodRequest represents a request to change the default payment method
+type SetDefaultPaymentMethodRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                              // User identifier (defaults to the caller)
+	PaymentMethodId string                 `protobuf:"bytes,2,opt,name=payment_method_id,json=paymentMethodId,proto3" json:"payment_method_id,omitempty"` // Saved payment method
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetDefaultPaymentMethodRequest) Reset() {
+	*x = SetDefaultPaymentMethodRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultPaymentMethodRequest) ProtoMessage() {}
+
+func (x *SetDefaultPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *SetDefaultPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
 	return ""
 }
 
-func (x *PricingZone) GetPricingMultiplier() float64 {
+func (x *SetDefaultPaymentMethodRequest) GetPaymentMethodId() string {
 	if x != nil {
-		return x.PricingMultiplier
+		return x.PaymentMethodId
 	}
-	return 0
+	return ""
 }
 
-func (x *PricingZone) GetCreatedAt() *timestamppb.Timestamp {
+// SetDefaultPaymentMethodResponse represents the new default payment method
+type SetDefaultPaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentMethod *SavedPaymentMethod    `protobuf:"bytes,1,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultPaymentMethodResponse) Reset() {
+	*x = SetDefaultPaymentMethodResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultPaymentMethodResponse) ProtoMessage() {}
+
+func (x *SetDefaultPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[87]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*SetDefaultPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *SetDefaultPaymentMethodResponse) GetPaymentMethod() *SavedPaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
 	}
 	return nil
 }
 
-func (x *PricingZone) GetUpdatedAt() *timestamppb.Timestamp {
+// DetachPaymentMethodRequest represents a request to detach a saved payment method
+type DetachPaymentMethodRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                              // User identifier (defaults to the caller)
+	PaymentMethodId string                 `protobuf:"bytes,2,opt,name=payment_method_id,json=paymentMethodId,proto3" json:"payment_method_id,omitempty"` // Saved payment method
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DetachPaymentMethodRequest) Reset() {
+	*x = DetachPaymentMethodRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetachPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetachPaymentMethodRequest) ProtoMessage() {}
+
+func (x *DetachPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[88]
 	if x != nil {
-		return x.UpdatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetachPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*DetachPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *DetachPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DetachPaymentMethodRequest) GetPaymentMethodId() string {
+	if x != nil {
+		return x.PaymentMethodId
+	}
+	return ""
+}
+
+// DetachPaymentMethodResponse represents a response to detaching a payment method
+type DetachPaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DetachPaymentMethodResponse) Reset() {
+	*x = DetachPaymentMethodResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DetachPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DetachPaymentMethodResponse) ProtoMessage() {}
+
+func (x *DetachPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DetachPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*DetachPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *DetachPaymentMethodResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// GetRevenueReportRequest represents a request for monthly revenue movements
+type GetRevenueReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`                      // First month; any time within it
+	To            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`                          // Last month, inclusive (defaults to the current month)
+	GroupBy       []string               `protobuf:"bytes,3,rep,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"` // plan, pricing_zone and/or currency; always sliced by currency
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRevenueReportRequest) Reset() {
+	*x = GetRevenueReportRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRevenueReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevenueReportRequest) ProtoMessage() {}
+
+func (x *GetRevenueReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevenueReportRequest.ProtoReflect.Descriptor instead.
+func (*GetRevenueReportRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *GetRevenueReportRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
 	}
 	return nil
 }
 
-// BulkCheckEntitlementsRequest represents a request for bulk entitlement checking
-type BulkCheckEntitlementsRequest struct {
+func (x *GetRevenueReportRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetRevenueReportRequest) GetGroupBy() []string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return nil
+}
+
+// RevenueMovement represents how recurring revenue and customers changed in a month for one slice
+type RevenueMovement struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Month               *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`                                // First day of the month
+	PlanId              string                 `protobuf:"bytes,2,opt,name=plan_id,json=planId,proto3" json:"plan_id,omitempty"`                // Set when grouped by plan
+	PricingZone         string                 `protobuf:"bytes,3,opt,name=pricing_zone,json=pricingZone,proto3" json:"pricing_zone,omitempty"` // Set when grouped by pricing zone; empty when unknown
+	Currency            string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	StartingMrrCents    int64                  `protobuf:"varint,5,opt,name=starting_mrr_cents,json=startingMrrCents,proto3" json:"starting_mrr_cents,omitempty"`
+	NewMrrCents         int64                  `protobuf:"varint,6,opt,name=new_mrr_cents,json=newMrrCents,proto3" json:"new_mrr_cents,omitempty"`
+	ExpansionMrrCents   int64                  `protobuf:"varint,7,opt,name=expansion_mrr_cents,json=expansionMrrCents,proto3" json:"expansion_mrr_cents,omitempty"`
+	ContractionMrrCents int64                  `protobuf:"varint,8,opt,name=contraction_mrr_cents,json=contractionMrrCents,proto3" json:"contraction_mrr_cents,omitempty"`
+	ChurnedMrrCents     int64                  `protobuf:"varint,9,opt,name=churned_mrr_cents,json=churnedMrrCents,proto3" json:"churned_mrr_cents,omitempty"`
+	EndingMrrCents      int64                  `protobuf:"varint,10,opt,name=ending_mrr_cents,json=endingMrrCents,proto3" json:"ending_mrr_cents,omitempty"`
+	EndingArrCents      int64                  `protobuf:"varint,11,opt,name=ending_arr_cents,json=endingArrCents,proto3" json:"ending_arr_cents,omitempty"` // Ending MRR times 12
+	StartingCustomers   int32                  `protobuf:"varint,12,opt,name=starting_customers,json=startingCustomers,proto3" json:"starting_customers,omitempty"`
+	NewCustomers        int32                  `protobuf:"varint,13,opt,name=new_customers,json=newCustomers,proto3" json:"new_customers,omitempty"`
+	ChurnedCustomers    int32                  `protobuf:"varint,14,opt,name=churned_customers,json=churnedCustomers,proto3" json:"churned_customers,omitempty"`
+	EndingCustomers     int32                  `protobuf:"varint,15,opt,name=ending_customers,json=endingCustomers,proto3" json:"ending_customers,omitempty"`
+	LogoChurnRate       float64                `protobuf:"fixed64,16,opt,name=logo_churn_rate,json=logoChurnRate,proto3" json:"logo_churn_rate,omitempty"` // Churned over starting customers
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RevenueMovement) Reset() {
+	*x = RevenueMovement{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevenueMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevenueMovement) ProtoMessage() {}
+
+func (x *RevenueMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevenueMovement.ProtoReflect.Descriptor instead.
+func (*RevenueMovement) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *RevenueMovement) GetMonth() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Month
+	}
+	return nil
+}
+
+func (x *RevenueMovement) GetPlanId() string {
+	if x != nil {
+		return x.PlanId
+	}
+	return ""
+}
+
+func (x *RevenueMovement) GetPricingZone() string {
+	if x != nil {
+		return x.PricingZone
+	}
+	return ""
+}
+
+func (x *RevenueMovement) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RevenueMovement) GetStartingMrrCents() int64 {
+	if x != nil {
+		return x.StartingMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetNewMrrCents() int64 {
+	if x != nil {
+		return x.NewMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetExpansionMrrCents() int64 {
+	if x != nil {
+		return x.ExpansionMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetContractionMrrCents() int64 {
+	if x != nil {
+		return x.ContractionMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetChurnedMrrCents() int64 {
+	if x != nil {
+		return x.ChurnedMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetEndingMrrCents() int64 {
+	if x != nil {
+		return x.EndingMrrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetEndingArrCents() int64 {
+	if x != nil {
+		return x.EndingArrCents
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetStartingCustomers() int32 {
+	if x != nil {
+		return x.StartingCustomers
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetNewCustomers() int32 {
+	if x != nil {
+		return x.NewCustomers
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetChurnedCustomers() int32 {
+	if x != nil {
+		return x.ChurnedCustomers
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetEndingCustomers() int32 {
+	if x != nil {
+		return x.EndingCustomers
+	}
+	return 0
+}
+
+func (x *RevenueMovement) GetLogoChurnRate() float64 {
+	if x != nil {
+		return x.LogoChurnRate
+	}
+	return 0
+}
+
+// GetRevenueReportResponse represents monthly revenue movements, by month then slice
+type GetRevenueReportResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // User identifier
-	Checks        []*BulkCheckItem       `protobuf:"bytes,2,rep,name=checks,proto3" json:"checks,omitempty"`               // List of entitlement checks
+	Movements     []*RevenueMovement     `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCheckEntitlementsRequest) Reset() {
-	*x = BulkCheckEntitlementsRequest{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[23]
+func (x *GetRevenueReportResponse) Reset() {
+	*x = GetRevenueReportResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[92]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCheckEntitlementsRequest) String() string {
+func (x *GetRevenueReportResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCheckEntitlementsRequest) ProtoMessage() {}
+func (*GetRevenueReportResponse) ProtoMessage() {}
 
-func (x *BulkCheckEntitlementsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[23]
+func (x *GetRevenueReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[92]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1630,51 +6542,43 @@ func (x *BulkCheckEntitlementsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCheckEntitlementsRequest.ProtoReflect.Descriptor instead.
-func (*BulkCheckEntitlementsRequest) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{23}
-}
-
-func (x *BulkCheckEntitlementsRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use GetRevenueReportResponse.ProtoReflect.Descriptor instead.
+func (*GetRevenueReportResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{92}
 }
 
-func (x *BulkCheckEntitlementsRequest) GetChecks() []*BulkCheckItem {
+func (x *GetRevenueReportResponse) GetMovements() []*RevenueMovement {
 	if x != nil {
-		return x.Checks
+		return x.Movements
 	}
 	return nil
 }
 
-// BulkCheckItem represents a single entitlement check
-type BulkCheckItem struct {
+// AuditFieldChange represents the value of one field before and after a change
+type AuditFieldChange struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FeatureCode   string                 `protobuf:"bytes,1,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`                                                  // Feature code to check
-	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`                                                                         // Specific operation (optional)
-	ResourceSize  int64                  `protobuf:"varint,3,opt,name=resource_size,json=resourceSize,proto3" json:"resource_size,omitempty"`                                              // Resource size for quota checks (optional)
-	Metadata      map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (optional)
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	BeforeJson    string                 `protobuf:"bytes,2,opt,name=before_json,json=beforeJson,proto3" json:"before_json,omitempty"` // JSON value; empty for created records
+	AfterJson     string                 `protobuf:"bytes,3,opt,name=after_json,json=afterJson,proto3" json:"after_json,omitempty"`    // JSON value; empty for deleted records
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCheckItem) Reset() {
-	*x = BulkCheckItem{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[24]
+func (x *AuditFieldChange) Reset() {
+	*x = AuditFieldChange{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCheckItem) String() string {
+func (x *AuditFieldChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCheckItem) ProtoMessage() {}
+func (*AuditFieldChange) ProtoMessage() {}
 
-func (x *BulkCheckItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[24]
+func (x *AuditFieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[93]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1685,63 +6589,64 @@ func (x *BulkCheckItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCheckItem.ProtoReflect.Descriptor instead.
-func (*BulkCheckItem) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use AuditFieldChange.ProtoReflect.Descriptor instead.
+func (*AuditFieldChange) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{93}
 }
 
-func (x *BulkCheckItem) GetFeatureCode() string {
+func (x *AuditFieldChange) GetField() string {
 	if x != nil {
-		return x.FeatureCode
+		return x.Field
 	}
 	return ""
 }
 
-func (x *BulkCheckItem) GetOperation() string {
+func (x *AuditFieldChange) GetBeforeJson() string {
 	if x != nil {
-		return x.Operation
+		return x.BeforeJson
 	}
 	return ""
 }
 
-func (x *BulkCheckItem) GetResourceSize() int64 {
-	if x != nil {
-		return x.ResourceSize
-	}
-	return 0
-}
-
-func (x *BulkCheckItem) GetMetadata() map[string]string {
+func (x *AuditFieldChange) GetAfterJson() string {
 	if x != nil {
-		return x.Metadata
+		return x.AfterJson
 	}
-	return nil
+	return ""
 }
 
-// BulkCheckEntitlementsResponse represents the response from bulk entitlement checking
-type BulkCheckEntitlementsResponse struct {
+// AuditEvent represents one recorded change: who made it, what changed and why
+type AuditEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Results       []*BulkCheckResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // Results for each check
-	Summary       *BulkCheckSummary      `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"` // Summary statistics
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EntityType    string                 `protobuf:"bytes,2,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"` // payment, entitlement, subscription, plan, pricing_zone or feature
+	EntityId      string                 `protobuf:"bytes,3,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`                        // e.g. create, update, status_change
+	ActorType     string                 `protobuf:"bytes,5,opt,name=actor_type,json=actorType,proto3" json:"actor_type,omitempty"` // user, service or system
+	ActorId       string                 `protobuf:"bytes,6,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`       // User ID or SPIFFE ID; empty for system changes
+	Changes       []*AuditFieldChange    `protobuf:"bytes,7,rep,name=changes,proto3" json:"changes,omitempty"`                      // Sorted by field
+	RequestId     string                 `protobuf:"bytes,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCheckEntitlementsResponse) Reset() {
-	*x = BulkCheckEntitlementsResponse{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[25]
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCheckEntitlementsResponse) String() string {
+func (x *AuditEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCheckEntitlementsResponse) ProtoMessage() {}
+func (*AuditEvent) ProtoMessage() {}
 
-func (x *BulkCheckEntitlementsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[25]
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1752,138 +6657,108 @@ func (x *BulkCheckEntitlementsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCheckEntitlementsResponse.ProtoReflect.Descriptor instead.
-func (*BulkCheckEntitlementsResponse) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *BulkCheckEntitlementsResponse) GetResults() []*BulkCheckResult {
+func (x *AuditEvent) GetId() string {
 	if x != nil {
-		return x.Results
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-func (x *BulkCheckEntitlementsResponse) GetSummary() *BulkCheckSummary {
+func (x *AuditEvent) GetEntityType() string {
 	if x != nil {
-		return x.Summary
+		return x.EntityType
 	}
-	return nil
-}
-
-// BulkCheckResult represents the result of a single entitlement check
-type BulkCheckResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	FeatureCode   string                 `protobuf:"bytes,1,opt,name=feature_code,json=featureCode,proto3" json:"feature_code,omitempty"`                                                  // Feature code that was checked
-	Authorized    bool                   `protobuf:"varint,2,opt,name=authorized,proto3" json:"authorized,omitempty"`                                                                      // Whether the user is authorized
-	Entitlement   *Entitlement           `protobuf:"bytes,3,opt,name=entitlement,proto3" json:"entitlement,omitempty"`                                                                     // Entitlement details (if authorized)
-	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                                                               // Reason for denial (if not authorized)
-	UpgradeUrl    string                 `protobuf:"bytes,5,opt,name=upgrade_url,json=upgradeUrl,proto3" json:"upgrade_url,omitempty"`                                                     // Upgrade URL (if not authorized)
-	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *BulkCheckResult) Reset() {
-	*x = BulkCheckResult{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[26]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *BulkCheckResult) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return ""
 }
 
-func (*BulkCheckResult) ProtoMessage() {}
-
-func (x *BulkCheckResult) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[26]
+func (x *AuditEvent) GetEntityId() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.EntityId
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use BulkCheckResult.ProtoReflect.Descriptor instead.
-func (*BulkCheckResult) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{26}
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
 }
 
-func (x *BulkCheckResult) GetFeatureCode() string {
+func (x *AuditEvent) GetActorType() string {
 	if x != nil {
-		return x.FeatureCode
+		return x.ActorType
 	}
 	return ""
 }
 
-func (x *BulkCheckResult) GetAuthorized() bool {
+func (x *AuditEvent) GetActorId() string {
 	if x != nil {
-		return x.Authorized
+		return x.ActorId
 	}
-	return false
+	return ""
 }
 
-func (x *BulkCheckResult) GetEntitlement() *Entitlement {
+func (x *AuditEvent) GetChanges() []*AuditFieldChange {
 	if x != nil {
-		return x.Entitlement
+		return x.Changes
 	}
 	return nil
 }
 
-func (x *BulkCheckResult) GetReason() string {
+func (x *AuditEvent) GetRequestId() string {
 	if x != nil {
-		return x.Reason
+		return x.RequestId
 	}
 	return ""
 }
 
-func (x *BulkCheckResult) GetUpgradeUrl() string {
+func (x *AuditEvent) GetReason() string {
 	if x != nil {
-		return x.UpgradeUrl
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *BulkCheckResult) GetMetadata() map[string]string {
+func (x *AuditEvent) GetOccurredAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Metadata
+		return x.OccurredAt
 	}
 	return nil
 }
 
-// BulkCheckSummary provides summary statistics for bulk checks
-type BulkCheckSummary struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	TotalChecks      int32                  `protobuf:"varint,1,opt,name=total_checks,json=totalChecks,proto3" json:"total_checks,omitempty"`                  // Total number of checks performed
-	Authorized       int32                  `protobuf:"varint,2,opt,name=authorized,proto3" json:"authorized,omitempty"`                                       // Number of authorized checks
-	NotAuthorized    int32                  `protobuf:"varint,3,opt,name=not_authorized,json=notAuthorized,proto3" json:"not_authorized,omitempty"`            // Number of not authorized checks
-	CacheHits        int32                  `protobuf:"varint,4,opt,name=cache_hits,json=cacheHits,proto3" json:"cache_hits,omitempty"`                        // Number of cache hits
-	CacheMisses      int32                  `protobuf:"varint,5,opt,name=cache_misses,json=cacheMisses,proto3" json:"cache_misses,omitempty"`                  // Number of cache misses
-	ProcessingTimeMs int64                  `protobuf:"varint,6,opt,name=processing_time_ms,json=processingTimeMs,proto3" json:"processing_time_ms,omitempty"` // Processing time in milliseconds
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// ListAuditEventsRequest represents a request to list audit events
+type ListAuditEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"` // Restrict to one entity type (optional)
+	EntityId      string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`       // Restrict to one entity; requires entity_type
+	ActorId       string                 `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`          // Restrict to one actor (optional)
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`      // Events per page (default 50, max 500)
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`    // Token from a previous response
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BulkCheckSummary) Reset() {
-	*x = BulkCheckSummary{}
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[27]
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BulkCheckSummary) String() string {
+func (x *ListAuditEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BulkCheckSummary) ProtoMessage() {}
+func (*ListAuditEventsRequest) ProtoMessage() {}
 
-func (x *BulkCheckSummary) ProtoReflect() protoreflect.Message {
-	mi := &file_api_payment_v1_payment_service_proto_msgTypes[27]
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1894,51 +6769,97 @@ func (x *BulkCheckSummary) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BulkCheckSummary.ProtoReflect.Descriptor instead.
-func (*BulkCheckSummary) Descriptor() ([]byte, []int) {
-	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *BulkCheckSummary) GetTotalChecks() int32 {
+func (x *ListAuditEventsRequest) GetEntityType() string {
 	if x != nil {
-		return x.TotalChecks
+		return x.EntityType
 	}
-	return 0
+	return ""
 }
 
-func (x *BulkCheckSummary) GetAuthorized() int32 {
+func (x *ListAuditEventsRequest) GetEntityId() string {
 	if x != nil {
-		return x.Authorized
+		return x.EntityId
 	}
-	return 0
+	return ""
 }
 
-func (x *BulkCheckSummary) GetNotAuthorized() int32 {
+func (x *ListAuditEventsRequest) GetActorId() string {
 	if x != nil {
-		return x.NotAuthorized
+		return x.ActorId
 	}
-	return 0
+	return ""
 }
 
-func (x *BulkCheckSummary) GetCacheHits() int32 {
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
 	if x != nil {
-		return x.CacheHits
+		return x.PageSize
 	}
 	return 0
 }
 
-func (x *BulkCheckSummary) GetCacheMisses() int32 {
+func (x *ListAuditEventsRequest) GetPageToken() string {
 	if x != nil {
-		return x.CacheMisses
+		return x.PageToken
 	}
-	return 0
+	return ""
 }
 
-func (x *BulkCheckSummary) GetProcessingTimeMs() int64 {
+// ListAuditEventsResponse represents a page of audit events
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_payment_v1_payment_service_proto_msgTypes[96]
 	if x != nil {
-		return x.ProcessingTimeMs
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_payment_v1_payment_service_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
 }
 
 var File_api_payment_v1_payment_service_proto protoreflect.FileDescriptor
@@ -1946,7 +6867,7 @@ var File_api_payment_v1_payment_service_proto protoreflect.FileDescriptor
 const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\n" +
 	"$api/payment/v1/payment_service.proto\x12\n" +
-	"payment.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcf\x01\n" +
+	"payment.v1\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcf\x01\n" +
 	"\x14CreatePaymentRequest\x12\x16\n" +
 	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
 	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12%\n" +
@@ -1965,21 +6886,46 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\"7\n" +
 	"\x1bUpdatePaymentStatusResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"m\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x9d\x02\n" +
 	"\x1cGetPaymentsByCustomerRequest\x12\x1f\n" +
 	"\vcustomer_id\x18\x01 \x01(\tR\n" +
 	"customerId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"f\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x121\n" +
+	"\x06filter\x18\x04 \x01(\v2\x19.payment.v1.PaymentFilterR\x06filter\x125\n" +
+	"\asort_by\x18\x05 \x01(\x0e2\x1c.payment.v1.PaymentSortFieldR\x06sortBy\x12%\n" +
+	"\x0esort_ascending\x18\x06 \x01(\bR\rsortAscending\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"\x8e\x01\n" +
 	"\x1dGetPaymentsByCustomerResponse\x12/\n" +
 	"\bpayments\x18\x01 \x03(\v2\x13.payment.v1.PaymentR\bpayments\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"C\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\xf3\x01\n" +
 	"\x13ListPaymentsRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"]\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x121\n" +
+	"\x06filter\x18\x03 \x01(\v2\x19.payment.v1.PaymentFilterR\x06filter\x125\n" +
+	"\asort_by\x18\x04 \x01(\x0e2\x1c.payment.v1.PaymentSortFieldR\x06sortBy\x12%\n" +
+	"\x0esort_ascending\x18\x05 \x01(\bR\rsortAscending\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\x85\x01\n" +
 	"\x14ListPaymentsResponse\x12/\n" +
 	"\bpayments\x18\x01 \x03(\v2\x13.payment.v1.PaymentR\bpayments\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"\xe0\x02\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\"\x84\x03\n" +
+	"\rPaymentFilter\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x03 \x01(\tR\rpaymentMethod\x12.\n" +
+	"\x13external_payment_id\x18\x04 \x01(\tR\x11externalPaymentId\x12?\n" +
+	"\rcreated_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\"\n" +
+	"\n" +
+	"min_amount\x18\a \x01(\x01H\x00R\tminAmount\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_amount\x18\b \x01(\x01H\x01R\tmaxAmount\x88\x01\x01B\r\n" +
+	"\v_min_amountB\r\n" +
+	"\v_max_amount\"\x90\x03\n" +
 	"\aPayment\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
@@ -1994,7 +6940,8 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x8b\x02\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12.\n" +
+	"\x13external_payment_id\x18\v \x01(\tR\x11externalPaymentId\"\xba\x02\n" +
 	"\x1cCreateCheckoutSessionRequest\x12\x17\n" +
 	"\aplan_id\x18\x01 \x01(\tR\x06planId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
@@ -2006,13 +6953,17 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\vsuccess_url\x18\a \x01(\tR\n" +
 	"successUrl\x12\x1d\n" +
 	"\n" +
-	"cancel_url\x18\b \x01(\tR\tcancelUrl\"\x8b\x01\n" +
+	"cancel_url\x18\b \x01(\tR\tcancelUrl\x12\x16\n" +
+	"\x06region\x18\t \x01(\tR\x06region\x12\x15\n" +
+	"\x06tax_id\x18\n" +
+	" \x01(\tR\x05taxId\"\xc2\x01\n" +
 	"\x1dCreateCheckoutSessionResponse\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x129\n" +
 	"\n" +
-	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"k\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x125\n" +
+	"\asession\x18\x04 \x01(\v2\x1b.payment.v1.CheckoutSessionR\asession\"k\n" +
 	"\x15ProcessWebhookRequest\x12\x18\n" +
 	"\apayload\x18\x01 \x01(\fR\apayload\x12\x1c\n" +
 	"\tsignature\x18\x02 \x01(\tR\tsignature\x12\x1a\n" +
@@ -2026,13 +6977,83 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\"m\n" +
 	"\x18ListEntitlementsResponse\x12;\n" +
 	"\fentitlements\x18\x01 \x03(\v2\x17.payment.v1.EntitlementR\fentitlements\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"U\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"\xc9\x01\n" +
+	"\x15ExportPaymentsRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x121\n" +
+	"\x06filter\x18\x02 \x01(\v2\x19.payment.v1.PaymentFilterR\x06filter\x125\n" +
+	"\asort_by\x18\x03 \x01(\x0e2\x1c.payment.v1.PaymentSortFieldR\x06sortBy\x12%\n" +
+	"\x0esort_ascending\x18\x04 \x01(\bR\rsortAscending\"\x87\x01\n" +
+	"\x19ExportEntitlementsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\ffeature_code\x18\x02 \x01(\tR\vfeatureCode\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"P\n" +
+	"\x18WatchEntitlementsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfamily_id\x18\x02 \x01(\tR\bfamilyId\"\x87\x01\n" +
+	"\x19WatchEntitlementsResponse\x123\n" +
+	"\bsnapshot\x18\x01 \x03(\v2\x17.payment.v1.EntitlementR\bsnapshot\x125\n" +
+	"\x06change\x18\x02 \x01(\v2\x1d.payment.v1.EntitlementChangeR\x06change\"\xc2\x01\n" +
+	"\x11EntitlementChange\x125\n" +
+	"\x04type\x18\x01 \x01(\x0e2!.payment.v1.EntitlementChangeTypeR\x04type\x129\n" +
+	"\ventitlement\x18\x02 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"\xe7\x01\n" +
+	"\x17GrantEntitlementRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\ffeature_code\x18\x02 \x01(\tR\vfeatureCode\x12\x17\n" +
+	"\aplan_id\x18\x03 \x01(\tR\x06planId\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12$\n" +
+	"\rcomplimentary\x18\x05 \x01(\bR\rcomplimentary\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"U\n" +
+	"\x18GrantEntitlementResponse\x129\n" +
+	"\ventitlement\x18\x01 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\"B\n" +
+	"\x18RevokeEntitlementRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"V\n" +
+	"\x19RevokeEntitlementResponse\x129\n" +
+	"\ventitlement\x18\x01 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\"}\n" +
+	"\x18ExtendEntitlementRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"V\n" +
+	"\x19ExtendEntitlementResponse\x129\n" +
+	"\ventitlement\x18\x01 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\"t\n" +
+	"\x19ExplainEntitlementRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\ffeature_code\x18\x02 \x01(\tR\vfeatureCode\x12\x1b\n" +
+	"\tfamily_id\x18\x03 \x01(\tR\bfamilyId\"\xba\x02\n" +
+	"\x1aExplainEntitlementResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12@\n" +
+	"\n" +
+	"candidates\x18\x04 \x03(\v2 .payment.v1.EntitlementCandidateR\n" +
+	"candidates\x127\n" +
+	"\x05cache\x18\x05 \x01(\v2!.payment.v1.EntitlementCacheStateR\x05cache\x12,\n" +
+	"\x12plans_with_feature\x18\x06 \x03(\tR\x10plansWithFeature\x12-\n" +
+	"\afeature\x18\a \x01(\v2\x13.payment.v1.FeatureR\afeature\"\xfd\x01\n" +
+	"\x14EntitlementCandidate\x129\n" +
+	"\ventitlement\x18\x01 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\x12\x14\n" +
+	"\x05valid\x18\x02 \x01(\bR\x05valid\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tplan_code\x18\x04 \x01(\tR\bplanCode\x12.\n" +
+	"\x13plan_grants_feature\x18\x05 \x01(\bR\x11planGrantsFeature\x12/\n" +
+	"\x13subscription_status\x18\x06 \x01(\tR\x12subscriptionStatus\"\x9f\x01\n" +
+	"\x15EntitlementCacheState\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1b\n" +
+	"\tnot_found\x18\x02 \x01(\bR\bnotFound\x129\n" +
+	"\ventitlement\x18\x03 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"U\n" +
 	"\x17CheckEntitlementRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
 	"\ffeature_code\x18\x02 \x01(\tR\vfeatureCode\"o\n" +
 	"\x18CheckEntitlementResponse\x12\x18\n" +
 	"\aallowed\x18\x01 \x01(\bR\aallowed\x129\n" +
-	"\ventitlement\x18\x02 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\"\xbc\x03\n" +
+	"\ventitlement\x18\x02 \x01(\v2\x17.payment.v1.EntitlementR\ventitlement\"\xd4\x03\n" +
 	"\vEntitlement\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
@@ -2049,7 +7070,8 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"created_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x19\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06source\x18\f \x01(\tR\x06source\"\x19\n" +
 	"\x17ListPricingZonesRequest\"X\n" +
 	"\x18ListPricingZonesResponse\x12<\n" +
 	"\rpricing_zones\x18\x01 \x03(\v2\x17.payment.v1.PricingZoneR\fpricingZones\"\xa8\x02\n" +
@@ -2100,7 +7122,318 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\n" +
 	"cache_hits\x18\x04 \x01(\x05R\tcacheHits\x12!\n" +
 	"\fcache_misses\x18\x05 \x01(\x05R\vcacheMisses\x12,\n" +
-	"\x12processing_time_ms\x18\x06 \x01(\x03R\x10processingTimeMs*\xbf\x01\n" +
+	"\x12processing_time_ms\x18\x06 \x01(\x03R\x10processingTimeMs\"\x94\x04\n" +
+	"\x04Plan\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12#\n" +
+	"\rfeature_codes\x18\x04 \x03(\tR\ffeatureCodes\x12#\n" +
+	"\rbilling_cycle\x18\x05 \x01(\tR\fbillingCycle\x12\x1f\n" +
+	"\vprice_cents\x18\x06 \x01(\x03R\n" +
+	"priceCents\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x1b\n" +
+	"\tmax_users\x18\b \x01(\x05R\bmaxUsers\x12!\n" +
+	"\fusage_limits\x18\t \x01(\tR\vusageLimits\x12\x1a\n" +
+	"\bmetadata\x18\n" +
+	" \x01(\tR\bmetadata\x12\x16\n" +
+	"\x06active\x18\v \x01(\bR\x06active\x12\x18\n" +
+	"\aversion\x18\f \x01(\x05R\aversion\x12;\n" +
+	"\varchived_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc2\x02\n" +
+	"\vPlanVersion\x12\x17\n" +
+	"\aplan_id\x18\x01 \x01(\tR\x06planId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x05R\aversion\x12#\n" +
+	"\rfeature_codes\x18\x03 \x03(\tR\ffeatureCodes\x12#\n" +
+	"\rbilling_cycle\x18\x04 \x01(\tR\fbillingCycle\x12\x1f\n" +
+	"\vprice_cents\x18\x05 \x01(\x03R\n" +
+	"priceCents\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1b\n" +
+	"\tmax_users\x18\a \x01(\x05R\bmaxUsers\x12!\n" +
+	"\fusage_limits\x18\b \x01(\tR\vusageLimits\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"9\n" +
+	"\x11CreatePlanRequest\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\":\n" +
+	"\x12CreatePlanResponse\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\"v\n" +
+	"\x11UpdatePlanRequest\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"[\n" +
+	"\x12UpdatePlanResponse\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\x12\x1f\n" +
+	"\vnew_version\x18\x02 \x01(\bR\n" +
+	"newVersion\"$\n" +
+	"\x12ArchivePlanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x13ArchivePlanResponse\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\"=\n" +
+	"\x10ListPlansRequest\x12)\n" +
+	"\x10include_archived\x18\x01 \x01(\bR\x0fincludeArchived\";\n" +
+	"\x11ListPlansResponse\x12&\n" +
+	"\x05plans\x18\x01 \x03(\v2\x10.payment.v1.PlanR\x05plans\" \n" +
+	"\x0eGetPlanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"l\n" +
+	"\x0fGetPlanResponse\x12$\n" +
+	"\x04plan\x18\x01 \x01(\v2\x10.payment.v1.PlanR\x04plan\x123\n" +
+	"\bversions\x18\x02 \x03(\v2\x17.payment.v1.PlanVersionR\bversions\"\x85\x03\n" +
+	"\aFeature\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12G\n" +
+	"\fquota_schema\x18\x03 \x03(\v2$.payment.v1.Feature.QuotaSchemaEntryR\vquotaSchema\x12%\n" +
+	"\x0edefault_limits\x18\x04 \x01(\tR\rdefaultLimits\x12\x1e\n" +
+	"\n" +
+	"deprecated\x18\x05 \x01(\bR\n" +
+	"deprecated\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a>\n" +
+	"\x10QuotaSchemaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x15\n" +
+	"\x13ListFeaturesRequest\"G\n" +
+	"\x14ListFeaturesResponse\x12/\n" +
+	"\bfeatures\x18\x01 \x03(\v2\x13.payment.v1.FeatureR\bfeatures\"E\n" +
+	"\x14CreateFeatureRequest\x12-\n" +
+	"\afeature\x18\x01 \x01(\v2\x13.payment.v1.FeatureR\afeature\"F\n" +
+	"\x15CreateFeatureResponse\x12-\n" +
+	"\afeature\x18\x01 \x01(\v2\x13.payment.v1.FeatureR\afeature\"\x82\x01\n" +
+	"\x14UpdateFeatureRequest\x12-\n" +
+	"\afeature\x18\x01 \x01(\v2\x13.payment.v1.FeatureR\afeature\x12;\n" +
+	"\vupdate_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\"F\n" +
+	"\x15UpdateFeatureResponse\x12-\n" +
+	"\afeature\x18\x01 \x01(\v2\x13.payment.v1.FeatureR\afeature\"\xcd\x06\n" +
+	"\x0fCheckoutSession\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x17\n" +
+	"\aplan_id\x18\x03 \x01(\tR\x06planId\x12!\n" +
+	"\fplan_version\x18\x04 \x01(\x05R\vplanVersion\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfamily_id\x18\x06 \x01(\tR\bfamilyId\x12!\n" +
+	"\fcountry_code\x18\a \x01(\tR\vcountryCode\x12\x1d\n" +
+	"\n" +
+	"base_price\x18\b \x01(\x01R\tbasePrice\x12!\n" +
+	"\fquoted_price\x18\t \x01(\x01R\vquotedPrice\x12-\n" +
+	"\x12pricing_multiplier\x18\n" +
+	" \x01(\x01R\x11pricingMultiplier\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06status\x12\x10\n" +
+	"\x03url\x18\r \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"expires_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12=\n" +
+	"\fcompleted_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06region\x18\x11 \x01(\tR\x06region\x12\x15\n" +
+	"\x06tax_id\x18\x12 \x01(\tR\x05taxId\x12\x19\n" +
+	"\btax_name\x18\x13 \x01(\tR\ataxName\x12(\n" +
+	"\x10tax_rate_percent\x18\x14 \x01(\x01R\x0etaxRatePercent\x12#\n" +
+	"\rtax_inclusive\x18\x15 \x01(\bR\ftaxInclusive\x12%\n" +
+	"\x0ereverse_charge\x18\x16 \x01(\bR\rreverseCharge\x12\x1d\n" +
+	"\n" +
+	"tax_amount\x18\x17 \x01(\x01R\ttaxAmount\x12\x1f\n" +
+	"\vtotal_price\x18\x18 \x01(\x01R\n" +
+	"totalPrice\":\n" +
+	"\x19GetCheckoutSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"S\n" +
+	"\x1aGetCheckoutSessionResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.payment.v1.CheckoutSessionR\asession\"=\n" +
+	"\x1cCancelCheckoutSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"V\n" +
+	"\x1dCancelCheckoutSessionResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.payment.v1.CheckoutSessionR\asession\"\x9c\x01\n" +
+	"\aAddress\x12\x14\n" +
+	"\x05line1\x18\x01 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x02 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x05 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\"\x80\x01\n" +
+	"\x0eBillingDetails\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12-\n" +
+	"\aaddress\x18\x03 \x01(\v2\x13.payment.v1.AddressR\aaddress\x12\x15\n" +
+	"\x06tax_id\x18\x04 \x01(\tR\x05taxId\"\xb2\x01\n" +
+	"\x0fInvoiceLineItem\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12*\n" +
+	"\x11unit_amount_cents\x18\x04 \x01(\x03R\x0funitAmountCents\x12!\n" +
+	"\famount_cents\x18\x05 \x01(\x03R\vamountCents\"\xa9\x05\n" +
+	"\aInvoice\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\tR\x06number\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\tR\tpaymentId\x12.\n" +
+	"\x13checkout_session_id\x18\x04 \x01(\tR\x11checkoutSessionId\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tfamily_id\x18\x06 \x01(\tR\bfamilyId\x12\x17\n" +
+	"\aplan_id\x18\a \x01(\tR\x06planId\x12!\n" +
+	"\fplan_version\x18\b \x01(\x05R\vplanVersion\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12%\n" +
+	"\x0esubtotal_cents\x18\n" +
+	" \x01(\x03R\rsubtotalCents\x12%\n" +
+	"\x0ediscount_cents\x18\v \x01(\x03R\rdiscountCents\x12\x1b\n" +
+	"\ttax_cents\x18\f \x01(\x03R\btaxCents\x12\x1f\n" +
+	"\vtotal_cents\x18\r \x01(\x03R\n" +
+	"totalCents\x124\n" +
+	"\abilling\x18\x0e \x01(\v2\x1a.payment.v1.BillingDetailsR\abilling\x12\x16\n" +
+	"\x06status\x18\x0f \x01(\tR\x06status\x12:\n" +
+	"\n" +
+	"line_items\x18\x10 \x03(\v2\x1b.payment.v1.InvoiceLineItemR\tlineItems\x127\n" +
+	"\tissued_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12#\n" +
+	"\rtax_inclusive\x18\x12 \x01(\bR\ftaxInclusive\x12%\n" +
+	"\x0ereverse_charge\x18\x13 \x01(\bR\rreverseCharge\"V\n" +
+	"\x11GetInvoiceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x19.payment.v1.InvoiceFormatR\x06format\"\x82\x01\n" +
+	"\x12GetInvoiceResponse\x12-\n" +
+	"\ainvoice\x18\x01 \x01(\v2\x13.payment.v1.InvoiceR\ainvoice\x12\x1a\n" +
+	"\bdocument\x18\x02 \x01(\fR\bdocument\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"j\n" +
+	"\x13ListInvoicesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"o\n" +
+	"\x14ListInvoicesResponse\x12/\n" +
+	"\binvoices\x18\x01 \x03(\v2\x13.payment.v1.InvoiceR\binvoices\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x96\x03\n" +
+	"\bCustomer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12-\n" +
+	"\aaddress\x18\x05 \x01(\v2\x13.payment.v1.AddressR\aaddress\x12\x15\n" +
+	"\x06tax_id\x18\x06 \x01(\tR\x05taxId\x12-\n" +
+	"\x12preferred_currency\x18\a \x01(\tR\x11preferredCurrency\x12\x1a\n" +
+	"\bprovider\x18\b \x01(\tR\bprovider\x120\n" +
+	"\x14provider_customer_id\x18\t \x01(\tR\x12providerCustomerId\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc7\x02\n" +
+	"\x12SavedPaymentMethod\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12-\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x19.payment.v1.PaymentMethodR\x04type\x12\x14\n" +
+	"\x05brand\x18\x04 \x01(\tR\x05brand\x12\x14\n" +
+	"\x05last4\x18\x05 \x01(\tR\x05last4\x12\x1b\n" +
+	"\texp_month\x18\x06 \x01(\x05R\bexpMonth\x12\x19\n" +
+	"\bexp_year\x18\a \x01(\x05R\aexpYear\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\b \x01(\bR\tisDefault\x12\x18\n" +
+	"\aexpired\x18\t \x01(\bR\aexpired\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xcf\x01\n" +
+	"\x15UpsertCustomerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12-\n" +
+	"\aaddress\x18\x04 \x01(\v2\x13.payment.v1.AddressR\aaddress\x12\x15\n" +
+	"\x06tax_id\x18\x05 \x01(\tR\x05taxId\x12-\n" +
+	"\x12preferred_currency\x18\x06 \x01(\tR\x11preferredCurrency\"J\n" +
+	"\x16UpsertCustomerResponse\x120\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x14.payment.v1.CustomerR\bcustomer\"-\n" +
+	"\x12GetCustomerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"G\n" +
+	"\x13GetCustomerResponse\x120\n" +
+	"\bcustomer\x18\x01 \x01(\v2\x14.payment.v1.CustomerR\bcustomer\"i\n" +
+	"\x17AddPaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12\x1f\n" +
+	"\vset_default\x18\x03 \x01(\bR\n" +
+	"setDefault\"a\n" +
+	"\x18AddPaymentMethodResponse\x12E\n" +
+	"\x0epayment_method\x18\x01 \x01(\v2\x1e.payment.v1.SavedPaymentMethodR\rpaymentMethod\"4\n" +
+	"\x19ListPaymentMethodsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"e\n" +
+	"\x1aListPaymentMethodsResponse\x12G\n" +
+	"\x0fpayment_methods\x18\x01 \x03(\v2\x1e.payment.v1.SavedPaymentMethodR\x0epaymentMethods\"e\n" +
+	"\x1eSetDefaultPaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12*\n" +
+	"\x11payment_method_id\x18\x02 \x01(\tR\x0fpaymentMethodId\"h\n" +
+	"\x1fSetDefaultPaymentMethodResponse\x12E\n" +
+	"\x0epayment_method\x18\x01 \x01(\v2\x1e.payment.v1.SavedPaymentMethodR\rpaymentMethod\"a\n" +
+	"\x1aDetachPaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12*\n" +
+	"\x11payment_method_id\x18\x02 \x01(\tR\x0fpaymentMethodId\"7\n" +
+	"\x1bDetachPaymentMethodResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x90\x01\n" +
+	"\x17GetRevenueReportRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x19\n" +
+	"\bgroup_by\x18\x03 \x03(\tR\agroupBy\"\xa5\x05\n" +
+	"\x0fRevenueMovement\x120\n" +
+	"\x05month\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05month\x12\x17\n" +
+	"\aplan_id\x18\x02 \x01(\tR\x06planId\x12!\n" +
+	"\fpricing_zone\x18\x03 \x01(\tR\vpricingZone\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12,\n" +
+	"\x12starting_mrr_cents\x18\x05 \x01(\x03R\x10startingMrrCents\x12\"\n" +
+	"\rnew_mrr_cents\x18\x06 \x01(\x03R\vnewMrrCents\x12.\n" +
+	"\x13expansion_mrr_cents\x18\a \x01(\x03R\x11expansionMrrCents\x122\n" +
+	"\x15contraction_mrr_cents\x18\b \x01(\x03R\x13contractionMrrCents\x12*\n" +
+	"\x11churned_mrr_cents\x18\t \x01(\x03R\x0fchurnedMrrCents\x12(\n" +
+	"\x10ending_mrr_cents\x18\n" +
+	" \x01(\x03R\x0eendingMrrCents\x12(\n" +
+	"\x10ending_arr_cents\x18\v \x01(\x03R\x0eendingArrCents\x12-\n" +
+	"\x12starting_customers\x18\f \x01(\x05R\x11startingCustomers\x12#\n" +
+	"\rnew_customers\x18\r \x01(\x05R\fnewCustomers\x12+\n" +
+	"\x11churned_customers\x18\x0e \x01(\x05R\x10churnedCustomers\x12)\n" +
+	"\x10ending_customers\x18\x0f \x01(\x05R\x0fendingCustomers\x12&\n" +
+	"\x0flogo_churn_rate\x18\x10 \x01(\x01R\rlogoChurnRate\"U\n" +
+	"\x18GetRevenueReportResponse\x129\n" +
+	"\tmovements\x18\x01 \x03(\v2\x1b.payment.v1.RevenueMovementR\tmovements\"h\n" +
+	"\x10AuditFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x1f\n" +
+	"\vbefore_json\x18\x02 \x01(\tR\n" +
+	"beforeJson\x12\x1d\n" +
+	"\n" +
+	"after_json\x18\x03 \x01(\tR\tafterJson\"\xd8\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\ventity_type\x18\x02 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x03 \x01(\tR\bentityId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1d\n" +
+	"\n" +
+	"actor_type\x18\x05 \x01(\tR\tactorType\x12\x19\n" +
+	"\bactor_id\x18\x06 \x01(\tR\aactorId\x126\n" +
+	"\achanges\x18\a \x03(\v2\x1c.payment.v1.AuditFieldChangeR\achanges\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\tR\trequestId\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12;\n" +
+	"\voccurred_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"\xad\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\tR\aactorId\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"q\n" +
+	"\x17ListAuditEventsResponse\x12.\n" +
+	"\x06events\x18\x01 \x03(\v2\x16.payment.v1.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*x\n" +
+	"\x10PaymentSortField\x12\"\n" +
+	"\x1ePAYMENT_SORT_FIELD_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dPAYMENT_SORT_FIELD_CREATED_AT\x10\x01\x12\x1d\n" +
+	"\x19PAYMENT_SORT_FIELD_AMOUNT\x10\x02*\xbf\x01\n" +
 	"\rPaymentStatus\x12\x1e\n" +
 	"\x1aPAYMENT_STATUS_UNSPECIFIED\x10\x00\x12\x1a\n" +
 	"\x16PAYMENT_STATUS_PENDING\x10\x01\x12\x1c\n" +
@@ -2113,7 +7446,17 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\x1aPAYMENT_METHOD_CREDIT_CARD\x10\x01\x12\x1d\n" +
 	"\x19PAYMENT_METHOD_DEBIT_CARD\x10\x02\x12 \n" +
 	"\x1cPAYMENT_METHOD_BANK_TRANSFER\x10\x03\x12!\n" +
-	"\x1dPAYMENT_METHOD_DIGITAL_WALLET\x10\x042\xae\b\n" +
+	"\x1dPAYMENT_METHOD_DIGITAL_WALLET\x10\x04*\xd4\x01\n" +
+	"\x15EntitlementChangeType\x12'\n" +
+	"#ENTITLEMENT_CHANGE_TYPE_UNSPECIFIED\x10\x00\x12#\n" +
+	"\x1fENTITLEMENT_CHANGE_TYPE_GRANTED\x10\x01\x12#\n" +
+	"\x1fENTITLEMENT_CHANGE_TYPE_REVOKED\x10\x02\x12#\n" +
+	"\x1fENTITLEMENT_CHANGE_TYPE_EXPIRED\x10\x03\x12#\n" +
+	"\x1fENTITLEMENT_CHANGE_TYPE_UPDATED\x10\x04*`\n" +
+	"\rInvoiceFormat\x12\x1e\n" +
+	"\x1aINVOICE_FORMAT_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13INVOICE_FORMAT_HTML\x10\x01\x12\x16\n" +
+	"\x12INVOICE_FORMAT_PDF\x10\x022\xac\x1b\n" +
 	"\x0ePaymentService\x12T\n" +
 	"\rCreatePayment\x12 .payment.v1.CreatePaymentRequest\x1a!.payment.v1.CreatePaymentResponse\x12K\n" +
 	"\n" +
@@ -2126,7 +7469,37 @@ const file_api_payment_v1_payment_service_proto_rawDesc = "" +
 	"\x10ListEntitlements\x12#.payment.v1.ListEntitlementsRequest\x1a$.payment.v1.ListEntitlementsResponse\x12]\n" +
 	"\x10CheckEntitlement\x12#.payment.v1.CheckEntitlementRequest\x1a$.payment.v1.CheckEntitlementResponse\x12l\n" +
 	"\x15BulkCheckEntitlements\x12(.payment.v1.BulkCheckEntitlementsRequest\x1a).payment.v1.BulkCheckEntitlementsResponse\x12]\n" +
-	"\x10ListPricingZones\x12#.payment.v1.ListPricingZonesRequest\x1a$.payment.v1.ListPricingZonesResponseB<Z:github.com/jia-app/paymentservice/api/payment/v1;paymentv1b\x06proto3"
+	"\x10ListPricingZones\x12#.payment.v1.ListPricingZonesRequest\x1a$.payment.v1.ListPricingZonesResponse\x12J\n" +
+	"\x0eExportPayments\x12!.payment.v1.ExportPaymentsRequest\x1a\x13.payment.v1.Payment0\x01\x12V\n" +
+	"\x12ExportEntitlements\x12%.payment.v1.ExportEntitlementsRequest\x1a\x17.payment.v1.Entitlement0\x01\x12K\n" +
+	"\n" +
+	"CreatePlan\x12\x1d.payment.v1.CreatePlanRequest\x1a\x1e.payment.v1.CreatePlanResponse\x12K\n" +
+	"\n" +
+	"UpdatePlan\x12\x1d.payment.v1.UpdatePlanRequest\x1a\x1e.payment.v1.UpdatePlanResponse\x12N\n" +
+	"\vArchivePlan\x12\x1e.payment.v1.ArchivePlanRequest\x1a\x1f.payment.v1.ArchivePlanResponse\x12H\n" +
+	"\tListPlans\x12\x1c.payment.v1.ListPlansRequest\x1a\x1d.payment.v1.ListPlansResponse\x12B\n" +
+	"\aGetPlan\x12\x1a.payment.v1.GetPlanRequest\x1a\x1b.payment.v1.GetPlanResponse\x12Q\n" +
+	"\fListFeatures\x12\x1f.payment.v1.ListFeaturesRequest\x1a .payment.v1.ListFeaturesResponse\x12T\n" +
+	"\rCreateFeature\x12 .payment.v1.CreateFeatureRequest\x1a!.payment.v1.CreateFeatureResponse\x12T\n" +
+	"\rUpdateFeature\x12 .payment.v1.UpdateFeatureRequest\x1a!.payment.v1.UpdateFeatureResponse\x12c\n" +
+	"\x12GetCheckoutSession\x12%.payment.v1.GetCheckoutSessionRequest\x1a&.payment.v1.GetCheckoutSessionResponse\x12l\n" +
+	"\x15CancelCheckoutSession\x12(.payment.v1.CancelCheckoutSessionRequest\x1a).payment.v1.CancelCheckoutSessionResponse\x12K\n" +
+	"\n" +
+	"GetInvoice\x12\x1d.payment.v1.GetInvoiceRequest\x1a\x1e.payment.v1.GetInvoiceResponse\x12Q\n" +
+	"\fListInvoices\x12\x1f.payment.v1.ListInvoicesRequest\x1a .payment.v1.ListInvoicesResponse\x12W\n" +
+	"\x0eUpsertCustomer\x12!.payment.v1.UpsertCustomerRequest\x1a\".payment.v1.UpsertCustomerResponse\x12N\n" +
+	"\vGetCustomer\x12\x1e.payment.v1.GetCustomerRequest\x1a\x1f.payment.v1.GetCustomerResponse\x12]\n" +
+	"\x10AddPaymentMethod\x12#.payment.v1.AddPaymentMethodRequest\x1a$.payment.v1.AddPaymentMethodResponse\x12c\n" +
+	"\x12ListPaymentMethods\x12%.payment.v1.ListPaymentMethodsRequest\x1a&.payment.v1.ListPaymentMethodsResponse\x12r\n" +
+	"\x17SetDefaultPaymentMethod\x12*.payment.v1.SetDefaultPaymentMethodRequest\x1a+.payment.v1.SetDefaultPaymentMethodResponse\x12f\n" +
+	"\x13DetachPaymentMethod\x12&.payment.v1.DetachPaymentMethodRequest\x1a'.payment.v1.DetachPaymentMethodResponse\x12]\n" +
+	"\x10GetRevenueReport\x12#.payment.v1.GetRevenueReportRequest\x1a$.payment.v1.GetRevenueReportResponse\x12Z\n" +
+	"\x0fListAuditEvents\x12\".payment.v1.ListAuditEventsRequest\x1a#.payment.v1.ListAuditEventsResponse\x12b\n" +
+	"\x11WatchEntitlements\x12$.payment.v1.WatchEntitlementsRequest\x1a%.payment.v1.WatchEntitlementsResponse0\x01\x12]\n" +
+	"\x10GrantEntitlement\x12#.payment.v1.GrantEntitlementRequest\x1a$.payment.v1.GrantEntitlementResponse\x12`\n" +
+	"\x11RevokeEntitlement\x12$.payment.v1.RevokeEntitlementRequest\x1a%.payment.v1.RevokeEntitlementResponse\x12`\n" +
+	"\x11ExtendEntitlement\x12$.payment.v1.ExtendEntitlementRequest\x1a%.payment.v1.ExtendEntitlementResponse\x12c\n" +
+	"\x12ExplainEntitlement\x12%.payment.v1.ExplainEntitlementRequest\x1a&.payment.v1.ExplainEntitlementResponseB<Z:github.com/jia-app/paymentservice/api/payment/v1;paymentv1b\x06proto3"
 
 var (
 	file_api_payment_v1_payment_service_proto_rawDescOnce sync.Once
@@ -2140,93 +7513,297 @@ func file_api_payment_v1_payment_service_proto_rawDescGZIP() []byte {
 	return file_api_payment_v1_payment_service_proto_rawDescData
 }
 
-var file_api_payment_v1_payment_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_payment_v1_payment_service_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_api_payment_v1_payment_service_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_api_payment_v1_payment_service_proto_msgTypes = make([]protoimpl.MessageInfo, 100)
 var file_api_payment_v1_payment_service_proto_goTypes = []any{
-	(PaymentStatus)(0),                    // 0: payment.v1.PaymentStatus
-	(PaymentMethod)(0),                    // 1: payment.v1.PaymentMethod
-	(*CreatePaymentRequest)(nil),          // 2: payment.v1.CreatePaymentRequest
-	(*CreatePaymentResponse)(nil),         // 3: payment.v1.CreatePaymentResponse
-	(*GetPaymentRequest)(nil),             // 4: payment.v1.GetPaymentRequest
-	(*GetPaymentResponse)(nil),            // 5: payment.v1.GetPaymentResponse
-	(*UpdatePaymentStatusRequest)(nil),    // 6: payment.v1.UpdatePaymentStatusRequest
-	(*UpdatePaymentStatusResponse)(nil),   // 7: payment.v1.UpdatePaymentStatusResponse
-	(*GetPaymentsByCustomerRequest)(nil),  // 8: payment.v1.GetPaymentsByCustomerRequest
-	(*GetPaymentsByCustomerResponse)(nil), // 9: payment.v1.GetPaymentsByCustomerResponse
-	(*ListPaymentsRequest)(nil),           // 10: payment.v1.ListPaymentsRequest
-	(*ListPaymentsResponse)(nil),          // 11: payment.v1.ListPaymentsResponse
-	(*Payment)(nil),                       // 12: payment.v1.Payment
-	(*CreateCheckoutSessionRequest)(nil),  // 13: payment.v1.CreateCheckoutSessionRequest
-	(*CreateCheckoutSessionResponse)(nil), // 14: payment.v1.CreateCheckoutSessionResponse
-	(*ProcessWebhookRequest)(nil),         // 15: payment.v1.ProcessWebhookRequest
-	(*ProcessWebhookResponse)(nil),        // 16: payment.v1.ProcessWebhookResponse
-	(*ListEntitlementsRequest)(nil),       // 17: payment.v1.ListEntitlementsRequest
-	(*ListEntitlementsResponse)(nil),      // 18: payment.v1.ListEntitlementsResponse
-	(*CheckEntitlementRequest)(nil),       // 19: payment.v1.CheckEntitlementRequest
-	(*CheckEntitlementResponse)(nil),      // 20: payment.v1.CheckEntitlementResponse
-	(*Entitlement)(nil),                   // 21: payment.v1.Entitlement
-	(*ListPricingZonesRequest)(nil),       // 22: payment.v1.ListPricingZonesRequest
-	(*ListPricingZonesResponse)(nil),      // 23: payment.v1.ListPricingZonesResponse
-	(*PricingZone)(nil),                   // 24: payment.v1.PricingZone
-	(*BulkCheckEntitlementsRequest)(nil),  // 25: payment.v1.BulkCheckEntitlementsRequest
-	(*BulkCheckItem)(nil),                 // 26: payment.v1.BulkCheckItem
-	(*BulkCheckEntitlementsResponse)(nil), // 27: payment.v1.BulkCheckEntitlementsResponse
-	(*BulkCheckResult)(nil),               // 28: payment.v1.BulkCheckResult
-	(*BulkCheckSummary)(nil),              // 29: payment.v1.BulkCheckSummary
-	nil,                                   // 30: payment.v1.BulkCheckItem.MetadataEntry
-	nil,                                   // 31: payment.v1.BulkCheckResult.MetadataEntry
-	(*timestamppb.Timestamp)(nil),         // 32: google.protobuf.Timestamp
+	(PaymentSortField)(0),                   // 0: payment.v1.PaymentSortField
+	(PaymentStatus)(0),                      // 1: payment.v1.PaymentStatus
+	(PaymentMethod)(0),                      // 2: payment.v1.PaymentMethod
+	(EntitlementChangeType)(0),              // 3: payment.v1.EntitlementChangeType
+	(InvoiceFormat)(0),                      // 4: payment.v1.InvoiceFormat
+	(*CreatePaymentRequest)(nil),            // 5: payment.v1.CreatePaymentRequest
+	(*CreatePaymentResponse)(nil),           // 6: payment.v1.CreatePaymentResponse
+	(*GetPaymentRequest)(nil),               // 7: payment.v1.GetPaymentRequest
+	(*GetPaymentResponse)(nil),              // 8: payment.v1.GetPaymentResponse
+	(*UpdatePaymentStatusRequest)(nil),      // 9: payment.v1.UpdatePaymentStatusRequest
+	(*UpdatePaymentStatusResponse)(nil),     // 10: payment.v1.UpdatePaymentStatusResponse
+	(*GetPaymentsByCustomerRequest)(nil),    // 11: payment.v1.GetPaymentsByCustomerRequest
+	(*GetPaymentsByCustomerResponse)(nil),   // 12: payment.v1.GetPaymentsByCustomerResponse
+	(*ListPaymentsRequest)(nil),             // 13: payment.v1.ListPaymentsRequest
+	(*ListPaymentsResponse)(nil),            // 14: payment.v1.ListPaymentsResponse
+	(*PaymentFilter)(nil),                   // 15: payment.v1.PaymentFilter
+	(*Payment)(nil),                         // 16: payment.v1.Payment
+	(*CreateCheckoutSessionRequest)(nil),    // 17: payment.v1.CreateCheckoutSessionRequest
+	(*CreateCheckoutSessionResponse)(nil),   // 18: payment.v1.CreateCheckoutSessionResponse
+	(*ProcessWebhookRequest)(nil),           // 19: payment.v1.ProcessWebhookRequest
+	(*ProcessWebhookResponse)(nil),          // 20: payment.v1.ProcessWebhookResponse
+	(*ListEntitlementsRequest)(nil),         // 21: payment.v1.ListEntitlementsRequest
+	(*ListEntitlementsResponse)(nil),        // 22: payment.v1.ListEntitlementsResponse
+	(*ExportPaymentsRequest)(nil),           // 23: payment.v1.ExportPaymentsRequest
+	(*ExportEntitlementsRequest)(nil),       // 24: payment.v1.ExportEntitlementsRequest
+	(*WatchEntitlementsRequest)(nil),        // 25: payment.v1.WatchEntitlementsRequest
+	(*WatchEntitlementsResponse)(nil),       // 26: payment.v1.WatchEntitlementsResponse
+	(*EntitlementChange)(nil),               // 27: payment.v1.EntitlementChange
+	(*GrantEntitlementRequest)(nil),         // 28: payment.v1.GrantEntitlementRequest
+	(*GrantEntitlementResponse)(nil),        // 29: payment.v1.GrantEntitlementResponse
+	(*RevokeEntitlementRequest)(nil),        // 30: payment.v1.RevokeEntitlementRequest
+	(*RevokeEntitlementResponse)(nil),       // 31: payment.v1.RevokeEntitlementResponse
+	(*ExtendEntitlementRequest)(nil),        // 32: payment.v1.ExtendEntitlementRequest
+	(*ExtendEntitlementResponse)(nil),       // 33: payment.v1.ExtendEntitlementResponse
+	(*ExplainEntitlementRequest)(nil),       // 34: payment.v1.ExplainEntitlementRequest
+	(*ExplainEntitlementResponse)(nil),      // 35: payment.v1.ExplainEntitlementResponse
+	(*EntitlementCandidate)(nil),            // 36: payment.v1.EntitlementCandidate
+	(*EntitlementCacheState)(nil),           // 37: payment.v1.EntitlementCacheState
+	(*CheckEntitlementRequest)(nil),         // 38: payment.v1.CheckEntitlementRequest
+	(*CheckEntitlementResponse)(nil),        // 39: payment.v1.CheckEntitlementResponse
+	(*Entitlement)(nil),                     // 40: payment.v1.Entitlement
+	(*ListPricingZonesRequest)(nil),         // 41: payment.v1.ListPricingZonesRequest
+	(*ListPricingZonesResponse)(nil),        // 42: payment.v1.ListPricingZonesResponse
+	(*PricingZone)(nil),                     // 43: payment.v1.PricingZone
+	(*BulkCheckEntitlementsRequest)(nil),    // 44: payment.v1.BulkCheckEntitlementsRequest
+	(*BulkCheckItem)(nil),                   // 45: payment.v1.BulkCheckItem
+	(*BulkCheckEntitlementsResponse)(nil),   // 46: payment.v1.BulkCheckEntitlementsResponse
+	(*BulkCheckResult)(nil),                 // 47: payment.v1.BulkCheckResult
+	(*BulkCheckSummary)(nil),                // 48: payment.v1.BulkCheckSummary
+	(*Plan)(nil),                            // 49: payment.v1.Plan
+	(*PlanVersion)(nil),                     // 50: payment.v1.PlanVersion
+	(*CreatePlanRequest)(nil),               // 51: payment.v1.CreatePlanRequest
+	(*CreatePlanResponse)(nil),              // 52: payment.v1.CreatePlanResponse
+	(*UpdatePlanRequest)(nil),               // 53: payment.v1.UpdatePlanRequest
+	(*UpdatePlanResponse)(nil),              // 54: payment.v1.UpdatePlanResponse
+	(*ArchivePlanRequest)(nil),              // 55: payment.v1.ArchivePlanRequest
+	(*ArchivePlanResponse)(nil),             // 56: payment.v1.ArchivePlanResponse
+	(*ListPlansRequest)(nil),                // 57: payment.v1.ListPlansRequest
+	(*ListPlansResponse)(nil),               // 58: payment.v1.ListPlansResponse
+	(*GetPlanRequest)(nil),                  // 59: payment.v1.GetPlanRequest
+	(*GetPlanResponse)(nil),                 // 60: payment.v1.GetPlanResponse
+	(*Feature)(nil),                         // 61: payment.v1.Feature
+	(*ListFeaturesRequest)(nil),             // 62: payment.v1.ListFeaturesRequest
+	(*ListFeaturesResponse)(nil),            // 63: payment.v1.ListFeaturesResponse
+	(*CreateFeatureRequest)(nil),            // 64: payment.v1.CreateFeatureRequest
+	(*CreateFeatureResponse)(nil),           // 65: payment.v1.CreateFeatureResponse
+	(*UpdateFeatureRequest)(nil),            // 66: payment.v1.UpdateFeatureRequest
+	(*UpdateFeatureResponse)(nil),           // 67: payment.v1.UpdateFeatureResponse
+	(*CheckoutSession)(nil),                 // 68: payment.v1.CheckoutSession
+	(*GetCheckoutSessionRequest)(nil),       // 69: payment.v1.GetCheckoutSessionRequest
+	(*GetCheckoutSessionResponse)(nil),      // 70: payment.v1.GetCheckoutSessionResponse
+	(*CancelCheckoutSessionRequest)(nil),    // 71: payment.v1.CancelCheckoutSessionRequest
+	(*CancelCheckoutSessionResponse)(nil),   // 72: payment.v1.CancelCheckoutSessionResponse
+	(*Address)(nil),                         // 73: payment.v1.Address
+	(*BillingDetails)(nil),                  // 74: payment.v1.BillingDetails
+	(*InvoiceLineItem)(nil),                 // 75: payment.v1.InvoiceLineItem
+	(*Invoice)(nil),                         // 76: payment.v1.Invoice
+	(*GetInvoiceRequest)(nil),               // 77: payment.v1.GetInvoiceRequest
+	(*GetInvoiceResponse)(nil),              // 78: payment.v1.GetInvoiceResponse
+	(*ListInvoicesRequest)(nil),             // 79: payment.v1.ListInvoicesRequest
+	(*ListInvoicesResponse)(nil),            // 80: payment.v1.ListInvoicesResponse
+	(*Customer)(nil),                        // 81: payment.v1.Customer
+	(*SavedPaymentMethod)(nil),              // 82: payment.v1.SavedPaymentMethod
+	(*UpsertCustomerRequest)(nil),           // 83: payment.v1.UpsertCustomerRequest
+	(*UpsertCustomerResponse)(nil),          // 84: payment.v1.UpsertCustomerResponse
+	(*GetCustomerRequest)(nil),              // 85: payment.v1.GetCustomerRequest
+	(*GetCustomerResponse)(nil),             // 86: payment.v1.GetCustomerResponse
+	(*AddPaymentMethodRequest)(nil),         // 87: payment.v1.AddPaymentMethodRequest
+	(*AddPaymentMethodResponse)(nil),        // 88: payment.v1.AddPaymentMethodResponse
+	(*ListPaymentMethodsRequest)(nil),       // 89: payment.v1.ListPaymentMethodsRequest
+	(*ListPaymentMethodsResponse)(nil),      // 90: payment.v1.ListPaymentMethodsResponse
+	(*SetDefaultPaymentMethodRequest)(nil),  // 91: payment.v1.SetDefaultPaymentMethodRequest
+	(*SetDefaultPaymentMethodResponse)(nil), // 92: payment.v1.SetDefaultPaymentMethodResponse
+	(*DetachPaymentMethodRequest)(nil),      // 93: payment.v1.DetachPaymentMethodRequest
+	(*DetachPaymentMethodResponse)(nil),     // 94: payment.v1.DetachPaymentMethodResponse
+	(*GetRevenueReportRequest)(nil),         // 95: payment.v1.GetRevenueReportRequest
+	(*RevenueMovement)(nil),                 // 96: payment.v1.RevenueMovement
+	(*GetRevenueReportResponse)(nil),        // 97: payment.v1.GetRevenueReportResponse
+	(*AuditFieldChange)(nil),                // 98: payment.v1.AuditFieldChange
+	(*AuditEvent)(nil),                      // 99: payment.v1.AuditEvent
+	(*ListAuditEventsRequest)(nil),          // 100: payment.v1.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),         // 101: payment.v1.ListAuditEventsResponse
+	nil,                                     // 102: payment.v1.BulkCheckItem.MetadataEntry
+	nil,                                     // 103: payment.v1.BulkCheckResult.MetadataEntry
+	nil,                                     // 104: payment.v1.Feature.QuotaSchemaEntry
+	(*timestamppb.Timestamp)(nil),           // 105: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),           // 106: google.protobuf.FieldMask
 }
 var file_api_payment_v1_payment_service_proto_depIdxs = []int32{
-	12, // 0: payment.v1.CreatePaymentResponse.payment:type_name -> payment.v1.Payment
-	12, // 1: payment.v1.GetPaymentResponse.payment:type_name -> payment.v1.Payment
-	12, // 2: payment.v1.GetPaymentsByCustomerResponse.payments:type_name -> payment.v1.Payment
-	12, // 3: payment.v1.ListPaymentsResponse.payments:type_name -> payment.v1.Payment
-	32, // 4: payment.v1.Payment.created_at:type_name -> google.protobuf.Timestamp
-	32, // 5: payment.v1.Payment.updated_at:type_name -> google.protobuf.Timestamp
-	32, // 6: payment.v1.CreateCheckoutSessionResponse.expires_at:type_name -> google.protobuf.Timestamp
-	21, // 7: payment.v1.ListEntitlementsResponse.entitlements:type_name -> payment.v1.Entitlement
-	21, // 8: payment.v1.CheckEntitlementResponse.entitlement:type_name -> payment.v1.Entitlement
-	32, // 9: payment.v1.Entitlement.granted_at:type_name -> google.protobuf.Timestamp
-	32, // 10: payment.v1.Entitlement.expires_at:type_name -> google.protobuf.Timestamp
-	32, // 11: payment.v1.Entitlement.created_at:type_name -> google.protobuf.Timestamp
-	32, // 12: payment.v1.Entitlement.updated_at:type_name -> google.protobuf.Timestamp
-	24, // 13: payment.v1.ListPricingZonesResponse.pricing_zones:type_name -> payment.v1.PricingZone
-	32, // 14: payment.v1.PricingZone.created_at:type_name -> google.protobuf.Timestamp
-	32, // 15: payment.v1.PricingZone.updated_at:type_name -> google.protobuf.Timestamp
-	26, // 16: payment.v1.BulkCheckEntitlementsRequest.checks:type_name -> payment.v1.BulkCheckItem
-	30, // 17: payment.v1.BulkCheckItem.metadata:type_name -> payment.v1.BulkCheckItem.MetadataEntry
-	28, // 18: payment.v1.BulkCheckEntitlementsResponse.results:type_name -> payment.v1.BulkCheckResult
-	29, // 19: payment.v1.BulkCheckEntitlementsResponse.summary:type_name -> payment.v1.BulkCheckSummary
-	21, // 20: payment.v1.BulkCheckResult.entitlement:type_name -> payment.v1.Entitlement
-	31, // 21: payment.v1.BulkCheckResult.metadata:type_name -> payment.v1.BulkCheckResult.MetadataEntry
-	2,  // 22: payment.v1.PaymentService.CreatePayment:input_type -> payment.v1.CreatePaymentRequest
-	4,  // 23: payment.v1.PaymentService.GetPayment:input_type -> payment.v1.GetPaymentRequest
-	6,  // 24: payment.v1.PaymentService.UpdatePaymentStatus:input_type -> payment.v1.UpdatePaymentStatusRequest
-	8,  // 25: payment.v1.PaymentService.GetPaymentsByCustomer:input_type -> payment.v1.GetPaymentsByCustomerRequest
-	10, // 26: payment.v1.PaymentService.ListPayments:input_type -> payment.v1.ListPaymentsRequest
-	13, // 27: payment.v1.PaymentService.CreateCheckoutSession:input_type -> payment.v1.CreateCheckoutSessionRequest
-	15, // 28: payment.v1.PaymentService.ProcessWebhook:input_type -> payment.v1.ProcessWebhookRequest
-	17, // 29: payment.v1.PaymentService.ListEntitlements:input_type -> payment.v1.ListEntitlementsRequest
-	19, // 30: payment.v1.PaymentService.CheckEntitlement:input_type -> payment.v1.CheckEntitlementRequest
-	25, // 31: payment.v1.PaymentService.BulkCheckEntitlements:input_type -> payment.v1.BulkCheckEntitlementsRequest
-	22, // 32: payment.v1.PaymentService.ListPricingZones:input_type -> payment.v1.ListPricingZonesRequest
-	3,  // 33: payment.v1.PaymentService.CreatePayment:output_type -> payment.v1.CreatePaymentResponse
-	5,  // 34: payment.v1.PaymentService.GetPayment:output_type -> payment.v1.GetPaymentResponse
-	7,  // 35: payment.v1.PaymentService.UpdatePaymentStatus:output_type -> payment.v1.UpdatePaymentStatusResponse
-	9,  // 36: payment.v1.PaymentService.GetPaymentsByCustomer:output_type -> payment.v1.GetPaymentsByCustomerResponse
-	11, // 37: payment.v1.PaymentService.ListPayments:output_type -> payment.v1.ListPaymentsResponse
-	14, // 38: payment.v1.PaymentService.CreateCheckoutSession:output_type -> payment.v1.CreateCheckoutSessionResponse
-	16, // 39: payment.v1.PaymentService.ProcessWebhook:output_type -> payment.v1.ProcessWebhookResponse
-	18, // 40: payment.v1.PaymentService.ListEntitlements:output_type -> payment.v1.ListEntitlementsResponse
-	20, // 41: payment.v1.PaymentService.CheckEntitlement:output_type -> payment.v1.CheckEntitlementResponse
-	27, // 42: payment.v1.PaymentService.BulkCheckEntitlements:output_type -> payment.v1.BulkCheckEntitlementsResponse
-	23, // 43: payment.v1.PaymentService.ListPricingZones:output_type -> payment.v1.ListPricingZonesResponse
-	33, // [33:44] is the sub-list for method output_type
-	22, // [22:33] is the sub-list for method input_type
-	22, // [22:22] is the sub-list for extension type_name
-	22, // [22:22] is the sub-list for extension extendee
-	0,  // [0:22] is the sub-list for field type_name
+	16,  // 0: payment.v1.CreatePaymentResponse.payment:type_name -> payment.v1.Payment
+	16,  // 1: payment.v1.GetPaymentResponse.payment:type_name -> payment.v1.Payment
+	15,  // 2: payment.v1.GetPaymentsByCustomerRequest.filter:type_name -> payment.v1.PaymentFilter
+	0,   // 3: payment.v1.GetPaymentsByCustomerRequest.sort_by:type_name -> payment.v1.PaymentSortField
+	16,  // 4: payment.v1.GetPaymentsByCustomerResponse.payments:type_name -> payment.v1.Payment
+	15,  // 5: payment.v1.ListPaymentsRequest.filter:type_name -> payment.v1.PaymentFilter
+	0,   // 6: payment.v1.ListPaymentsRequest.sort_by:type_name -> payment.v1.PaymentSortField
+	16,  // 7: payment.v1.ListPaymentsResponse.payments:type_name -> payment.v1.Payment
+	105, // 8: payment.v1.PaymentFilter.created_after:type_name -> google.protobuf.Timestamp
+	105, // 9: payment.v1.PaymentFilter.created_before:type_name -> google.protobuf.Timestamp
+	105, // 10: payment.v1.Payment.created_at:type_name -> google.protobuf.Timestamp
+	105, // 11: payment.v1.Payment.updated_at:type_name -> google.protobuf.Timestamp
+	105, // 12: payment.v1.CreateCheckoutSessionResponse.expires_at:type_name -> google.protobuf.Timestamp
+	68,  // 13: payment.v1.CreateCheckoutSessionResponse.session:type_name -> payment.v1.CheckoutSession
+	40,  // 14: payment.v1.ListEntitlementsResponse.entitlements:type_name -> payment.v1.Entitlement
+	15,  // 15: payment.v1.ExportPaymentsRequest.filter:type_name -> payment.v1.PaymentFilter
+	0,   // 16: payment.v1.ExportPaymentsRequest.sort_by:type_name -> payment.v1.PaymentSortField
+	40,  // 17: payment.v1.WatchEntitlementsResponse.snapshot:type_name -> payment.v1.Entitlement
+	27,  // 18: payment.v1.WatchEntitlementsResponse.change:type_name -> payment.v1.EntitlementChange
+	3,   // 19: payment.v1.EntitlementChange.type:type_name -> payment.v1.EntitlementChangeType
+	40,  // 20: payment.v1.EntitlementChange.entitlement:type_name -> payment.v1.Entitlement
+	105, // 21: payment.v1.EntitlementChange.occurred_at:type_name -> google.protobuf.Timestamp
+	105, // 22: payment.v1.GrantEntitlementRequest.expires_at:type_name -> google.protobuf.Timestamp
+	40,  // 23: payment.v1.GrantEntitlementResponse.entitlement:type_name -> payment.v1.Entitlement
+	40,  // 24: payment.v1.RevokeEntitlementResponse.entitlement:type_name -> payment.v1.Entitlement
+	105, // 25: payment.v1.ExtendEntitlementRequest.expires_at:type_name -> google.protobuf.Timestamp
+	40,  // 26: payment.v1.ExtendEntitlementResponse.entitlement:type_name -> payment.v1.Entitlement
+	36,  // 27: payment.v1.ExplainEntitlementResponse.candidates:type_name -> payment.v1.EntitlementCandidate
+	37,  // 28: payment.v1.ExplainEntitlementResponse.cache:type_name -> payment.v1.EntitlementCacheState
+	61,  // 29: payment.v1.ExplainEntitlementResponse.feature:type_name -> payment.v1.Feature
+	40,  // 30: payment.v1.EntitlementCandidate.entitlement:type_name -> payment.v1.Entitlement
+	40,  // 31: payment.v1.EntitlementCacheState.entitlement:type_name -> payment.v1.Entitlement
+	40,  // 32: payment.v1.CheckEntitlementResponse.entitlement:type_name -> payment.v1.Entitlement
+	105, // 33: payment.v1.Entitlement.granted_at:type_name -> google.protobuf.Timestamp
+	105, // 34: payment.v1.Entitlement.expires_at:type_name -> google.protobuf.Timestamp
+	105, // 35: payment.v1.Entitlement.created_at:type_name -> google.protobuf.Timestamp
+	105, // 36: payment.v1.Entitlement.updated_at:type_name -> google.protobuf.Timestamp
+	43,  // 37: payment.v1.ListPricingZonesResponse.pricing_zones:type_name -> payment.v1.PricingZone
+	105, // 38: payment.v1.PricingZone.created_at:type_name -> google.protobuf.Timestamp
+	105, // 39: payment.v1.PricingZone.updated_at:type_name -> google.protobuf.Timestamp
+	45,  // 40: payment.v1.BulkCheckEntitlementsRequest.checks:type_name -> payment.v1.BulkCheckItem
+	102, // 41: payment.v1.BulkCheckItem.metadata:type_name -> payment.v1.BulkCheckItem.MetadataEntry
+	47,  // 42: payment.v1.BulkCheckEntitlementsResponse.results:type_name -> payment.v1.BulkCheckResult
+	48,  // 43: payment.v1.BulkCheckEntitlementsResponse.summary:type_name -> payment.v1.BulkCheckSummary
+	40,  // 44: payment.v1.BulkCheckResult.entitlement:type_name -> payment.v1.Entitlement
+	103, // 45: payment.v1.BulkCheckResult.metadata:type_name -> payment.v1.BulkCheckResult.MetadataEntry
+	105, // 46: payment.v1.Plan.archived_at:type_name -> google.protobuf.Timestamp
+	105, // 47: payment.v1.Plan.created_at:type_name -> google.protobuf.Timestamp
+	105, // 48: payment.v1.Plan.updated_at:type_name -> google.protobuf.Timestamp
+	105, // 49: payment.v1.PlanVersion.created_at:type_name -> google.protobuf.Timestamp
+	49,  // 50: payment.v1.CreatePlanRequest.plan:type_name -> payment.v1.Plan
+	49,  // 51: payment.v1.CreatePlanResponse.plan:type_name -> payment.v1.Plan
+	49,  // 52: payment.v1.UpdatePlanRequest.plan:type_name -> payment.v1.Plan
+	106, // 53: payment.v1.UpdatePlanRequest.update_mask:type_name -> google.protobuf.FieldMask
+	49,  // 54: payment.v1.UpdatePlanResponse.plan:type_name -> payment.v1.Plan
+	49,  // 55: payment.v1.ArchivePlanResponse.plan:type_name -> payment.v1.Plan
+	49,  // 56: payment.v1.ListPlansResponse.plans:type_name -> payment.v1.Plan
+	49,  // 57: payment.v1.GetPlanResponse.plan:type_name -> payment.v1.Plan
+	50,  // 58: payment.v1.GetPlanResponse.versions:type_name -> payment.v1.PlanVersion
+	104, // 59: payment.v1.Feature.quota_schema:type_name -> payment.v1.Feature.QuotaSchemaEntry
+	105, // 60: payment.v1.Feature.created_at:type_name -> google.protobuf.Timestamp
+	105, // 61: payment.v1.Feature.updated_at:type_name -> google.protobuf.Timestamp
+	61,  // 62: payment.v1.ListFeaturesResponse.features:type_name -> payment.v1.Feature
+	61,  // 63: payment.v1.CreateFeatureRequest.feature:type_name -> payment.v1.Feature
+	61,  // 64: payment.v1.CreateFeatureResponse.feature:type_name -> payment.v1.Feature
+	61,  // 65: payment.v1.UpdateFeatureRequest.feature:type_name -> payment.v1.Feature
+	106, // 66: payment.v1.UpdateFeatureRequest.update_mask:type_name -> google.protobuf.FieldMask
+	61,  // 67: payment.v1.UpdateFeatureResponse.feature:type_name -> payment.v1.Feature
+	105, // 68: payment.v1.CheckoutSession.expires_at:type_name -> google.protobuf.Timestamp
+	105, // 69: payment.v1.CheckoutSession.completed_at:type_name -> google.protobuf.Timestamp
+	105, // 70: payment.v1.CheckoutSession.created_at:type_name -> google.protobuf.Timestamp
+	68,  // 71: payment.v1.GetCheckoutSessionResponse.session:type_name -> payment.v1.CheckoutSession
+	68,  // 72: payment.v1.CancelCheckoutSessionResponse.session:type_name -> payment.v1.CheckoutSession
+	73,  // 73: payment.v1.BillingDetails.address:type_name -> payment.v1.Address
+	74,  // 74: payment.v1.Invoice.billing:type_name -> payment.v1.BillingDetails
+	75,  // 75: payment.v1.Invoice.line_items:type_name -> payment.v1.InvoiceLineItem
+	105, // 76: payment.v1.Invoice.issued_at:type_name -> google.protobuf.Timestamp
+	4,   // 77: payment.v1.GetInvoiceRequest.format:type_name -> payment.v1.InvoiceFormat
+	76,  // 78: payment.v1.GetInvoiceResponse.invoice:type_name -> payment.v1.Invoice
+	76,  // 79: payment.v1.ListInvoicesResponse.invoices:type_name -> payment.v1.Invoice
+	73,  // 80: payment.v1.Customer.address:type_name -> payment.v1.Address
+	105, // 81: payment.v1.Customer.created_at:type_name -> google.protobuf.Timestamp
+	105, // 82: payment.v1.Customer.updated_at:type_name -> google.protobuf.Timestamp
+	2,   // 83: payment.v1.SavedPaymentMethod.type:type_name -> payment.v1.PaymentMethod
+	105, // 84: payment.v1.SavedPaymentMethod.created_at:type_name -> google.protobuf.Timestamp
+	73,  // 85: payment.v1.UpsertCustomerRequest.address:type_name -> payment.v1.Address
+	81,  // 86: payment.v1.UpsertCustomerResponse.customer:type_name -> payment.v1.Customer
+	81,  // 87: payment.v1.GetCustomerResponse.customer:type_name -> payment.v1.Customer
+	82,  // 88: payment.v1.AddPaymentMethodResponse.payment_method:type_name -> payment.v1.SavedPaymentMethod
+	82,  // 89: payment.v1.ListPaymentMethodsResponse.payment_methods:type_name -> payment.v1.SavedPaymentMethod
+	82,  // 90: payment.v1.SetDefaultPaymentMethodResponse.payment_method:type_name -> payment.v1.SavedPaymentMethod
+	105, // 91: payment.v1.GetRevenueReportRequest.from:type_name -> google.protobuf.Timestamp
+	105, // 92: payment.v1.GetRevenueReportRequest.to:type_name -> google.protobuf.Timestamp
+	105, // 93: payment.v1.RevenueMovement.month:type_name -> google.protobuf.Timestamp
+	96,  // 94: payment.v1.GetRevenueReportResponse.movements:type_name -> payment.v1.RevenueMovement
+	98,  // 95: payment.v1.AuditEvent.changes:type_name -> payment.v1.AuditFieldChange
+	105, // 96: payment.v1.AuditEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	99,  // 97: payment.v1.ListAuditEventsResponse.events:type_name -> payment.v1.AuditEvent
+	5,   // 98: payment.v1.PaymentService.CreatePayment:input_type -> payment.v1.CreatePaymentRequest
+	7,   // 99: payment.v1.PaymentService.GetPayment:input_type -> payment.v1.GetPaymentRequest
+	9,   // 100: payment.v1.PaymentService.UpdatePaymentStatus:input_type -> payment.v1.UpdatePaymentStatusRequest
+	11,  // 101: payment.v1.PaymentService.GetPaymentsByCustomer:input_type -> payment.v1.GetPaymentsByCustomerRequest
+	13,  // 102: payment.v1.PaymentService.ListPayments:input_type -> payment.v1.ListPaymentsRequest
+	17,  // 103: payment.v1.PaymentService.CreateCheckoutSession:input_type -> payment.v1.CreateCheckoutSessionRequest
+	19,  // 104: payment.v1.PaymentService.ProcessWebhook:input_type -> payment.v1.ProcessWebhookRequest
+	21,  // 105: payment.v1.PaymentService.ListEntitlements:input_type -> payment.v1.ListEntitlementsRequest
+	38,  // 106: payment.v1.PaymentService.CheckEntitlement:input_type -> payment.v1.CheckEntitlementRequest
+	44,  // 107: payment.v1.PaymentService.BulkCheckEntitlements:input_type -> payment.v1.BulkCheckEntitlementsRequest
+	41,  // 108: payment.v1.PaymentService.ListPricingZones:input_type -> payment.v1.ListPricingZonesRequest
+	23,  // 109: payment.v1.PaymentService.ExportPayments:input_type -> payment.v1.ExportPaymentsRequest
+	24,  // 110: payment.v1.PaymentService.ExportEntitlements:input_type -> payment.v1.ExportEntitlementsRequest
+	51,  // 111: payment.v1.PaymentService.CreatePlan:input_type -> payment.v1.CreatePlanRequest
+	53,  // 112: payment.v1.PaymentService.UpdatePlan:input_type -> payment.v1.UpdatePlanRequest
+	55,  // 113: payment.v1.PaymentService.ArchivePlan:input_type -> payment.v1.ArchivePlanRequest
+	57,  // 114: payment.v1.PaymentService.ListPlans:input_type -> payment.v1.ListPlansRequest
+	59,  // 115: payment.v1.PaymentService.GetPlan:input_type -> payment.v1.GetPlanRequest
+	62,  // 116: payment.v1.PaymentService.ListFeatures:input_type -> payment.v1.ListFeaturesRequest
+	64,  // 117: payment.v1.PaymentService.CreateFeature:input_type -> payment.v1.CreateFeatureRequest
+	66,  // 118: payment.v1.PaymentService.UpdateFeature:input_type -> payment.v1.UpdateFeatureRequest
+	69,  // 119: payment.v1.PaymentService.GetCheckoutSession:input_type -> payment.v1.GetCheckoutSessionRequest
+	71,  // 120: payment.v1.PaymentService.CancelCheckoutSession:input_type -> payment.v1.CancelCheckoutSessionRequest
+	77,  // 121: payment.v1.PaymentService.GetInvoice:input_type -> payment.v1.GetInvoiceRequest
+	79,  // 122: payment.v1.PaymentService.ListInvoices:input_type -> payment.v1.ListInvoicesRequest
+	83,  // 123: payment.v1.PaymentService.UpsertCustomer:input_type -> payment.v1.UpsertCustomerRequest
+	85,  // 124: payment.v1.PaymentService.GetCustomer:input_type -> payment.v1.GetCustomerRequest
+	87,  // 125: payment.v1.PaymentService.AddPaymentMethod:input_type -> payment.v1.AddPaymentMethodRequest
+	89,  // 126: payment.v1.PaymentService.ListPaymentMethods:input_type -> payment.v1.ListPaymentMethodsRequest
+	91,  // 127: payment.v1.PaymentService.SetDefaultPaymentMethod:input_type -> payment.v1.SetDefaultPaymentMethodRequest
+	93,  // 128: payment.v1.PaymentService.DetachPaymentMethod:input_type -> payment.v1.DetachPaymentMethodRequest
+	95,  // 129: payment.v1.PaymentService.GetRevenueReport:input_type -> payment.v1.GetRevenueReportRequest
+	100, // 130: payment.v1.PaymentService.ListAuditEvents:input_type -> payment.v1.ListAuditEventsRequest
+	25,  // 131: payment.v1.PaymentService.WatchEntitlements:input_type -> payment.v1.WatchEntitlementsRequest
+	28,  // 132: payment.v1.PaymentService.GrantEntitlement:input_type -> payment.v1.GrantEntitlementRequest
+	30,  // 133: payment.v1.PaymentService.RevokeEntitlement:input_type -> payment.v1.RevokeEntitlementRequest
+	32,  // 134: payment.v1.PaymentService.ExtendEntitlement:input_type -> payment.v1.ExtendEntitlementRequest
+	34,  // 135: payment.v1.PaymentService.ExplainEntitlement:input_type -> payment.v1.ExplainEntitlementRequest
+	6,   // 136: payment.v1.PaymentService.CreatePayment:output_type -> payment.v1.CreatePaymentResponse
+	8,   // 137: payment.v1.PaymentService.GetPayment:output_type -> payment.v1.GetPaymentResponse
+	10,  // 138: payment.v1.PaymentService.UpdatePaymentStatus:output_type -> payment.v1.UpdatePaymentStatusResponse
+	12,  // 139: payment.v1.PaymentService.GetPaymentsByCustomer:output_type -> payment.v1.GetPaymentsByCustomerResponse
+	14,  // 140: payment.v1.PaymentService.ListPayments:output_type -> payment.v1.ListPaymentsResponse
+	18,  // 141: payment.v1.PaymentService.CreateCheckoutSession:output_type -> payment.v1.CreateCheckoutSessionResponse
+	20,  // 142: payment.v1.PaymentService.ProcessWebhook:output_type -> payment.v1.ProcessWebhookResponse
+	22,  // 143: payment.v1.PaymentService.ListEntitlements:output_type -> payment.v1.ListEntitlementsResponse
+	39,  // 144: payment.v1.PaymentService.CheckEntitlement:output_type -> payment.v1.CheckEntitlementResponse
+	46,  // 145: payment.v1.PaymentService.BulkCheckEntitlements:output_type -> payment.v1.BulkCheckEntitlementsResponse
+	42,  // 146: payment.v1.PaymentService.ListPricingZones:output_type -> payment.v1.ListPricingZonesResponse
+	16,  // 147: payment.v1.PaymentService.ExportPayments:output_type -> payment.v1.Payment
+	40,  // 148: payment.v1.PaymentService.ExportEntitlements:output_type -> payment.v1.Entitlement
+	52,  // 149: payment.v1.PaymentService.CreatePlan:output_type -> payment.v1.CreatePlanResponse
+	54,  // 150: payment.v1.PaymentService.UpdatePlan:output_type -> payment.v1.UpdatePlanResponse
+	56,  // 151: payment.v1.PaymentService.ArchivePlan:output_type -> payment.v1.ArchivePlanResponse
+	58,  // 152: payment.v1.PaymentService.ListPlans:output_type -> payment.v1.ListPlansResponse
+	60,  // 153: payment.v1.PaymentService.GetPlan:output_type -> payment.v1.GetPlanResponse
+	63,  // 154: payment.v1.PaymentService.ListFeatures:output_type -> payment.v1.ListFeaturesResponse
+	65,  // 155: payment.v1.PaymentService.CreateFeature:output_type -> payment.v1.CreateFeatureResponse
+	67,  // 156: payment.v1.PaymentService.UpdateFeature:output_type -> payment.v1.UpdateFeatureResponse
+	70,  // 157: payment.v1.PaymentService.GetCheckoutSession:output_type -> payment.v1.GetCheckoutSessionResponse
+	72,  // 158: payment.v1.PaymentService.CancelCheckoutSession:output_type -> payment.v1.CancelCheckoutSessionResponse
+	78,  // 159: payment.v1.PaymentService.GetInvoice:output_type -> payment.v1.GetInvoiceResponse
+	80,  // 160: payment.v1.PaymentService.ListInvoices:output_type -> payment.v1.ListInvoicesResponse
+	84,  // 161: payment.v1.PaymentService.UpsertCustomer:output_type -> payment.v1.UpsertCustomerResponse
+	86,  // 162: payment.v1.PaymentService.GetCustomer:output_type -> payment.v1.GetCustomerResponse
+	88,  // 163: payment.v1.PaymentService.AddPaymentMethod:output_type -> payment.v1.AddPaymentMethodResponse
+	90,  // 164: payment.v1.PaymentService.ListPaymentMethods:output_type -> payment.v1.ListPaymentMethodsResponse
+	92,  // 165: payment.v1.PaymentService.SetDefaultPaymentMethod:output_type -> payment.v1.SetDefaultPaymentMethodResponse
+	94,  // 166: payment.v1.PaymentService.DetachPaymentMethod:output_type -> payment.v1.DetachPaymentMethodResponse
+	97,  // 167: payment.v1.PaymentService.GetRevenueReport:output_type -> payment.v1.GetRevenueReportResponse
+	101, // 168: payment.v1.PaymentService.ListAuditEvents:output_type -> payment.v1.ListAuditEventsResponse
+	26,  // 169: payment.v1.PaymentService.WatchEntitlements:output_type -> payment.v1.WatchEntitlementsResponse
+	29,  // 170: payment.v1.PaymentService.GrantEntitlement:output_type -> payment.v1.GrantEntitlementResponse
+	31,  // 171: payment.v1.PaymentService.RevokeEntitlement:output_type -> payment.v1.RevokeEntitlementResponse
+	33,  // 172: payment.v1.PaymentService.ExtendEntitlement:output_type -> payment.v1.ExtendEntitlementResponse
+	35,  // 173: payment.v1.PaymentService.ExplainEntitlement:output_type -> payment.v1.ExplainEntitlementResponse
+	136, // [136:174] is the sub-list for method output_type
+	98,  // [98:136] is the sub-list for method input_type
+	98,  // [98:98] is the sub-list for extension type_name
+	98,  // [98:98] is the sub-list for extension extendee
+	0,   // [0:98] is the sub-list for field type_name
 }
 
 func init() { file_api_payment_v1_payment_service_proto_init() }
@@ -2234,13 +7811,14 @@ func file_api_payment_v1_payment_service_proto_init() {
 	if File_api_payment_v1_payment_service_proto != nil {
 		return
 	}
+	file_api_payment_v1_payment_service_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_payment_v1_payment_service_proto_rawDesc), len(file_api_payment_v1_payment_service_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   30,
+			NumEnums:      5,
+			NumMessages:   100,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,17 +19,44 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PaymentService_CreatePayment_FullMethodName         = "/payment.v1.PaymentService/CreatePayment"
-	PaymentService_GetPayment_FullMethodName            = "/payment.v1.PaymentService/GetPayment"
-	PaymentService_UpdatePaymentStatus_FullMethodName   = "/payment.v1.PaymentService/UpdatePaymentStatus"
-	PaymentService_GetPaymentsByCustomer_FullMethodName = "/payment.v1.PaymentService/GetPaymentsByCustomer"
-	PaymentService_ListPayments_FullMethodName          = "/payment.v1.PaymentService/ListPayments"
-	PaymentService_CreateCheckoutSession_FullMethodName = "/payment.v1.PaymentService/CreateCheckoutSession"
-	PaymentService_ProcessWebhook_FullMethodName        = "/payment.v1.PaymentService/ProcessWebhook"
-	PaymentService_ListEntitlements_FullMethodName      = "/payment.v1.PaymentService/ListEntitlements"
-	PaymentService_CheckEntitlement_FullMethodName      = "/payment.v1.PaymentService/CheckEntitlement"
-	PaymentService_BulkCheckEntitlements_FullMethodName = "/payment.v1.PaymentService/BulkCheckEntitlements"
-	PaymentService_ListPricingZones_FullMethodName      = "/payment.v1.PaymentService/ListPricingZones"
+	PaymentService_CreatePayment_FullMethodName           = "/payment.v1.PaymentService/CreatePayment"
+	PaymentService_GetPayment_FullMethodName              = "/payment.v1.PaymentService/GetPayment"
+	PaymentService_UpdatePaymentStatus_FullMethodName     = "/payment.v1.PaymentService/UpdatePaymentStatus"
+	PaymentService_GetPaymentsByCustomer_FullMethodName   = "/payment.v1.PaymentService/GetPaymentsByCustomer"
+	PaymentService_ListPayments_FullMethodName            = "/payment.v1.PaymentService/ListPayments"
+	PaymentService_CreateCheckoutSession_FullMethodName   = "/payment.v1.PaymentService/CreateCheckoutSession"
+	PaymentService_ProcessWebhook_FullMethodName          = "/payment.v1.PaymentService/ProcessWebhook"
+	PaymentService_ListEntitlements_FullMethodName        = "/payment.v1.PaymentService/ListEntitlements"
+	PaymentService_CheckEntitlement_FullMethodName        = "/payment.v1.PaymentService/CheckEntitlement"
+	PaymentService_BulkCheckEntitlements_FullMethodName   = "/payment.v1.PaymentService/BulkCheckEntitlements"
+	PaymentService_ListPricingZones_FullMethodName        = "/payment.v1.PaymentService/ListPricingZones"
+	PaymentService_ExportPayments_FullMethodName          = "/payment.v1.PaymentService/ExportPayments"
+	PaymentService_ExportEntitlements_FullMethodName      = "/payment.v1.PaymentService/ExportEntitlements"
+	PaymentService_CreatePlan_FullMethodName              = "/payment.v1.PaymentService/CreatePlan"
+	PaymentService_UpdatePlan_FullMethodName              = "/payment.v1.PaymentService/UpdatePlan"
+	PaymentService_ArchivePlan_FullMethodName             = "/payment.v1.PaymentService/ArchivePlan"
+	PaymentService_ListPlans_FullMethodName               = "/payment.v1.PaymentService/ListPlans"
+	PaymentService_GetPlan_FullMethodName                 = "/payment.v1.PaymentService/GetPlan"
+	PaymentService_ListFeatures_FullMethodName            = "/payment.v1.PaymentService/ListFeatures"
+	PaymentService_CreateFeature_FullMethodName           = "/payment.v1.PaymentService/CreateFeature"
+	PaymentService_UpdateFeature_FullMethodName           = "/payment.v1.PaymentService/UpdateFeature"
+	PaymentService_GetCheckoutSession_FullMethodName      = "/payment.v1.PaymentService/GetCheckoutSession"
+	PaymentService_CancelCheckoutSession_FullMethodName   = "/payment.v1.PaymentService/CancelCheckoutSession"
+	PaymentService_GetInvoice_FullMethodName              = "/payment.v1.PaymentService/GetInvoice"
+	PaymentService_ListInvoices_FullMethodName            = "/payment.v1.PaymentService/ListInvoices"
+	PaymentService_UpsertCustomer_FullMethodName          = "/payment.v1.PaymentService/UpsertCustomer"
+	PaymentService_GetCustomer_FullMethodName             = "/payment.v1.PaymentService/GetCustomer"
+	PaymentService_AddPaymentMethod_FullMethodName        = "/payment.v1.PaymentService/AddPaymentMethod"
+	PaymentService_ListPaymentMethods_FullMethodName      = "/payment.v1.PaymentService/ListPaymentMethods"
+	PaymentService_SetDefaultPaymentMethod_FullMethodName = "/payment.v1.PaymentService/SetDefaultPaymentMethod"
+	PaymentService_DetachPaymentMethod_FullMethodName     = "/payment.v1.PaymentService/DetachPaymentMethod"
+	PaymentService_GetRevenueReport_FullMethodName        = "/payment.v1.PaymentService/GetRevenueReport"
+	PaymentService_ListAuditEvents_FullMethodName         = "/payment.v1.PaymentService/ListAuditEvents"
+	PaymentService_WatchEntitlements_FullMethodName       = "/payment.v1.PaymentService/WatchEntitlements"
+	PaymentService_GrantEntitlement_FullMethodName        = "/payment.v1.PaymentService/GrantEntitlement"
+	PaymentService_RevokeEntitlement_FullMethodName       = "/payment.v1.PaymentService/RevokeEntitlement"
+	PaymentService_ExtendEntitlement_FullMethodName       = "/payment.v1.PaymentService/ExtendEntitlement"
+	PaymentService_ExplainEntitlement_FullMethodName      = "/payment.v1.PaymentService/ExplainEntitlement"
 )
 
 // PaymentServiceClient is the client API for PaymentService service.
@@ -60,6 +87,60 @@ type PaymentServiceClient interface {
 	BulkCheckEntitlements(ctx context.Context, in *BulkCheckEntitlementsRequest, opts ...grpc.CallOption) (*BulkCheckEntitlementsResponse, error)
 	// ListPricingZones retrieves all pricing zones
 	ListPricingZones(ctx context.Context, in *ListPricingZonesRequest, opts ...grpc.CallOption) (*ListPricingZonesResponse, error)
+	// ExportPayments streams every payment matching the filter
+	ExportPayments(ctx context.Context, in *ExportPaymentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Payment], error)
+	// ExportEntitlements streams every entitlement matching the filter
+	ExportEntitlements(ctx context.Context, in *ExportEntitlementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Entitlement], error)
+	// CreatePlan adds a plan to the catalog
+	CreatePlan(ctx context.Context, in *CreatePlanRequest, opts ...grpc.CallOption) (*CreatePlanResponse, error)
+	// UpdatePlan updates a plan; price or feature changes create a new plan version
+	UpdatePlan(ctx context.Context, in *UpdatePlanRequest, opts ...grpc.CallOption) (*UpdatePlanResponse, error)
+	// ArchivePlan stops a plan from being sold without affecting existing subscriptions
+	ArchivePlan(ctx context.Context, in *ArchivePlanRequest, opts ...grpc.CallOption) (*ArchivePlanResponse, error)
+	// ListPlans lists catalog plans
+	ListPlans(ctx context.Context, in *ListPlansRequest, opts ...grpc.CallOption) (*ListPlansResponse, error)
+	// GetPlan retrieves a plan with its version history
+	GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error)
+	// ListFeatures lists the feature catalog, deprecated features included
+	ListFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error)
+	// CreateFeature adds a feature to the catalog
+	CreateFeature(ctx context.Context, in *CreateFeatureRequest, opts ...grpc.CallOption) (*CreateFeatureResponse, error)
+	// UpdateFeature updates a feature's description, quota schema, default limits or deprecation
+	UpdateFeature(ctx context.Context, in *UpdateFeatureRequest, opts ...grpc.CallOption) (*UpdateFeatureResponse, error)
+	// GetCheckoutSession retrieves a checkout session and its quoted price
+	GetCheckoutSession(ctx context.Context, in *GetCheckoutSessionRequest, opts ...grpc.CallOption) (*GetCheckoutSessionResponse, error)
+	// CancelCheckoutSession cancels an open checkout session with the billing provider
+	CancelCheckoutSession(ctx context.Context, in *CancelCheckoutSessionRequest, opts ...grpc.CallOption) (*CancelCheckoutSessionResponse, error)
+	// GetInvoice retrieves an invoice, optionally rendered as a document
+	GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*GetInvoiceResponse, error)
+	// ListInvoices lists invoices newest first with keyset pagination
+	ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error)
+	// UpsertCustomer creates or updates a user's billing profile
+	UpsertCustomer(ctx context.Context, in *UpsertCustomerRequest, opts ...grpc.CallOption) (*UpsertCustomerResponse, error)
+	// GetCustomer retrieves a user's billing profile
+	GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*GetCustomerResponse, error)
+	// AddPaymentMethod saves a payment method tokenized by the billing provider
+	AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error)
+	// ListPaymentMethods lists a user's saved payment methods, default first
+	ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error)
+	// SetDefaultPaymentMethod sets the method reused by checkout and dunning retries
+	SetDefaultPaymentMethod(ctx context.Context, in *SetDefaultPaymentMethodRequest, opts ...grpc.CallOption) (*SetDefaultPaymentMethodResponse, error)
+	// DetachPaymentMethod detaches a saved payment method and removes it
+	DetachPaymentMethod(ctx context.Context, in *DetachPaymentMethodRequest, opts ...grpc.CallOption) (*DetachPaymentMethodResponse, error)
+	// GetRevenueReport reports MRR, ARR and churn by month from daily revenue snapshots
+	GetRevenueReport(ctx context.Context, in *GetRevenueReportRequest, opts ...grpc.CallOption) (*GetRevenueReportResponse, error)
+	// ListAuditEvents lists recorded changes to payments, entitlements, subscriptions, plans, pricing zones and features, newest first
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
+	// WatchEntitlements streams a user's current entitlements, then every grant, revoke or expiry for the user or their family
+	WatchEntitlements(ctx context.Context, in *WatchEntitlementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEntitlementsResponse], error)
+	// GrantEntitlement grants a user a feature outside of checkout, optionally as a complimentary account
+	GrantEntitlement(ctx context.Context, in *GrantEntitlementRequest, opts ...grpc.CallOption) (*GrantEntitlementResponse, error)
+	// RevokeEntitlement revokes an entitlement
+	RevokeEntitlement(ctx context.Context, in *RevokeEntitlementRequest, opts ...grpc.CallOption) (*RevokeEntitlementResponse, error)
+	// ExtendEntitlement moves an entitlement's expiry later
+	ExtendEntitlement(ctx context.Context, in *ExtendEntitlementRequest, opts ...grpc.CallOption) (*ExtendEntitlementResponse, error)
+	// ExplainEntitlement traces why CheckEntitlement allows or denies a user a feature
+	ExplainEntitlement(ctx context.Context, in *ExplainEntitlementRequest, opts ...grpc.CallOption) (*ExplainEntitlementResponse, error)
 }
 
 type paymentServiceClient struct {
@@ -180,6 +261,303 @@ func (c *paymentServiceClient) ListPricingZones(ctx context.Context, in *ListPri
 	return out, nil
 }
 
+func (c *paymentServiceClient) ExportPayments(ctx context.Context, in *ExportPaymentsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Payment], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PaymentService_ServiceDesc.Streams[0], PaymentService_ExportPayments_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportPaymentsRequest, Payment]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_ExportPaymentsClient = grpc.ServerStreamingClient[Payment]
+
+func (c *paymentServiceClient) ExportEntitlements(ctx context.Context, in *ExportEntitlementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Entitlement], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PaymentService_ServiceDesc.Streams[1], PaymentService_ExportEntitlements_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportEntitlementsRequest, Entitlement]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_ExportEntitlementsClient = grpc.ServerStreamingClient[Entitlement]
+
+func (c *paymentServiceClient) CreatePlan(ctx context.Context, in *CreatePlanRequest, opts ...grpc.CallOption) (*CreatePlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePlanResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CreatePlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) UpdatePlan(ctx context.Context, in *UpdatePlanRequest, opts ...grpc.CallOption) (*UpdatePlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePlanResponse)
+	err := c.cc.Invoke(ctx, PaymentService_UpdatePlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ArchivePlan(ctx context.Context, in *ArchivePlanRequest, opts ...grpc.CallOption) (*ArchivePlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchivePlanResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ArchivePlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListPlans(ctx context.Context, in *ListPlansRequest, opts ...grpc.CallOption) (*ListPlansResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPlansResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListPlans_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetPlan(ctx context.Context, in *GetPlanRequest, opts ...grpc.CallOption) (*GetPlanResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPlanResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListFeatures(ctx context.Context, in *ListFeaturesRequest, opts ...grpc.CallOption) (*ListFeaturesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFeaturesResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListFeatures_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CreateFeature(ctx context.Context, in *CreateFeatureRequest, opts ...grpc.CallOption) (*CreateFeatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFeatureResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CreateFeature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) UpdateFeature(ctx context.Context, in *UpdateFeatureRequest, opts ...grpc.CallOption) (*UpdateFeatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFeatureResponse)
+	err := c.cc.Invoke(ctx, PaymentService_UpdateFeature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetCheckoutSession(ctx context.Context, in *GetCheckoutSessionRequest, opts ...grpc.CallOption) (*GetCheckoutSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCheckoutSessionResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetCheckoutSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CancelCheckoutSession(ctx context.Context, in *CancelCheckoutSessionRequest, opts ...grpc.CallOption) (*CancelCheckoutSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelCheckoutSessionResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CancelCheckoutSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetInvoice(ctx context.Context, in *GetInvoiceRequest, opts ...grpc.CallOption) (*GetInvoiceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInvoiceResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetInvoice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListInvoices(ctx context.Context, in *ListInvoicesRequest, opts ...grpc.CallOption) (*ListInvoicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListInvoicesResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListInvoices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) UpsertCustomer(ctx context.Context, in *UpsertCustomerRequest, opts ...grpc.CallOption) (*UpsertCustomerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpsertCustomerResponse)
+	err := c.cc.Invoke(ctx, PaymentService_UpsertCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetCustomer(ctx context.Context, in *GetCustomerRequest, opts ...grpc.CallOption) (*GetCustomerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCustomerResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetCustomer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AddPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentMethodsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListPaymentMethods_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SetDefaultPaymentMethod(ctx context.Context, in *SetDefaultPaymentMethodRequest, opts ...grpc.CallOption) (*SetDefaultPaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDefaultPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_SetDefaultPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) DetachPaymentMethod(ctx context.Context, in *DetachPaymentMethodRequest, opts ...grpc.CallOption) (*DetachPaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DetachPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_DetachPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetRevenueReport(ctx context.Context, in *GetRevenueReportRequest, opts ...grpc.CallOption) (*GetRevenueReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRevenueReportResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetRevenueReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) WatchEntitlements(ctx context.Context, in *WatchEntitlementsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEntitlementsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PaymentService_ServiceDesc.Streams[2], PaymentService_WatchEntitlements_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEntitlementsRequest, WatchEntitlementsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_WatchEntitlementsClient = grpc.ServerStreamingClient[WatchEntitlementsResponse]
+
+func (c *paymentServiceClient) GrantEntitlement(ctx context.Context, in *GrantEntitlementRequest, opts ...grpc.CallOption) (*GrantEntitlementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantEntitlementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GrantEntitlement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RevokeEntitlement(ctx context.Context, in *RevokeEntitlementRequest, opts ...grpc.CallOption) (*RevokeEntitlementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeEntitlementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RevokeEntitlement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExtendEntitlement(ctx context.Context, in *ExtendEntitlementRequest, opts ...grpc.CallOption) (*ExtendEntitlementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtendEntitlementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ExtendEntitlement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ExplainEntitlement(ctx context.Context, in *ExplainEntitlementRequest, opts ...grpc.CallOption) (*ExplainEntitlementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainEntitlementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ExplainEntitlement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentServiceServer is the server API for PaymentService service.
 // All implementations must embed UnimplementedPaymentServiceServer
 // for forward compatibility.
@@ -208,6 +586,60 @@ type PaymentServiceServer interface {
 	BulkCheckEntitlements(context.Context, *BulkCheckEntitlementsRequest) (*BulkCheckEntitlementsResponse, error)
 	// ListPricingZones retrieves all pricing zones
 	ListPricingZones(context.Context, *ListPricingZonesRequest) (*ListPricingZonesResponse, error)
+	// ExportPayments streams every payment matching the filter
+	ExportPayments(*ExportPaymentsRequest, grpc.ServerStreamingServer[Payment]) error
+	// ExportEntitlements streams every entitlement matching the filter
+	ExportEntitlements(*ExportEntitlementsRequest, grpc.ServerStreamingServer[Entitlement]) error
+	// CreatePlan adds a plan to the catalog
+	CreatePlan(context.Context, *CreatePlanRequest) (*CreatePlanResponse, error)
+	// UpdatePlan updates a plan; price or feature changes create a new plan version
+	UpdatePlan(context.Context, *UpdatePlanRequest) (*UpdatePlanResponse, error)
+	// ArchivePlan stops a plan from being sold without affecting existing subscriptions
+	ArchivePlan(context.Context, *ArchivePlanRequest) (*ArchivePlanResponse, error)
+	// ListPlans lists catalog plans
+	ListPlans(context.Context, *ListPlansRequest) (*ListPlansResponse, error)
+	// GetPlan retrieves a plan with its version history
+	GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error)
+	// ListFeatures lists the feature catalog, deprecated features included
+	ListFeatures(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error)
+	// CreateFeature adds a feature to the catalog
+	CreateFeature(context.Context, *CreateFeatureRequest) (*CreateFeatureResponse, error)
+	// UpdateFeature updates a feature's description, quota schema, default limits or deprecation
+	UpdateFeature(context.Context, *UpdateFeatureRequest) (*UpdateFeatureResponse, error)
+	// GetCheckoutSession retrieves a checkout session and its quoted price
+	GetCheckoutSession(context.Context, *GetCheckoutSessionRequest) (*GetCheckoutSessionResponse, error)
+	// CancelCheckoutSession cancels an open checkout session with the billing provider
+	CancelCheckoutSession(context.Context, *CancelCheckoutSessionRequest) (*CancelCheckoutSessionResponse, error)
+	// GetInvoice retrieves an invoice, optionally rendered as a document
+	GetInvoice(context.Context, *GetInvoiceRequest) (*GetInvoiceResponse, error)
+	// ListInvoices lists invoices newest first with keyset pagination
+	ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error)
+	// UpsertCustomer creates or updates a user's billing profile
+	UpsertCustomer(context.Context, *UpsertCustomerRequest) (*UpsertCustomerResponse, error)
+	// GetCustomer retrieves a user's billing profile
+	GetCustomer(context.Context, *GetCustomerRequest) (*GetCustomerResponse, error)
+	// AddPaymentMethod saves a payment method tokenized by the billing provider
+	AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error)
+	// ListPaymentMethods lists a user's saved payment methods, default first
+	ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error)
+	// SetDefaultPaymentMethod sets the method reused by checkout and dunning retries
+	SetDefaultPaymentMethod(context.Context, *SetDefaultPaymentMethodRequest) (*SetDefaultPaymentMethodResponse, error)
+	// DetachPaymentMethod detaches a saved payment method and removes it
+	DetachPaymentMethod(context.Context, *DetachPaymentMethodRequest) (*DetachPaymentMethodResponse, error)
+	// GetRevenueReport reports MRR, ARR and churn by month from daily revenue snapshots
+	GetRevenueReport(context.Context, *GetRevenueReportRequest) (*GetRevenueReportResponse, error)
+	// ListAuditEvents lists recorded changes to payments, entitlements, subscriptions, plans, pricing zones and features, newest first
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	// WatchEntitlements streams a user's current entitlements, then every grant, revoke or expiry for the user or their family
+	WatchEntitlements(*WatchEntitlementsRequest, grpc.ServerStreamingServer[WatchEntitlementsResponse]) error
+	// GrantEntitlement grants a user a feature outside of checkout, optionally as a complimentary account
+	GrantEntitlement(context.Context, *GrantEntitlementRequest) (*GrantEntitlementResponse, error)
+	// RevokeEntitlement revokes an entitlement
+	RevokeEntitlement(context.Context, *RevokeEntitlementRequest) (*RevokeEntitlementResponse, error)
+	// ExtendEntitlement moves an entitlement's expiry later
+	ExtendEntitlement(context.Context, *ExtendEntitlementRequest) (*ExtendEntitlementResponse, error)
+	// ExplainEntitlement traces why CheckEntitlement allows or denies a user a feature
+	ExplainEntitlement(context.Context, *ExplainEntitlementRequest) (*ExplainEntitlementResponse, error)
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
@@ -251,6 +683,87 @@ func (UnimplementedPaymentServiceServer) BulkCheckEntitlements(context.Context,
 func (UnimplementedPaymentServiceServer) ListPricingZones(context.Context, *ListPricingZonesRequest) (*ListPricingZonesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListPricingZones not implemented")
 }
+func (UnimplementedPaymentServiceServer) ExportPayments(*ExportPaymentsRequest, grpc.ServerStreamingServer[Payment]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportPayments not implemented")
+}
+func (UnimplementedPaymentServiceServer) ExportEntitlements(*ExportEntitlementsRequest, grpc.ServerStreamingServer[Entitlement]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportEntitlements not implemented")
+}
+func (UnimplementedPaymentServiceServer) CreatePlan(context.Context, *CreatePlanRequest) (*CreatePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlan not implemented")
+}
+func (UnimplementedPaymentServiceServer) UpdatePlan(context.Context, *UpdatePlanRequest) (*UpdatePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePlan not implemented")
+}
+func (UnimplementedPaymentServiceServer) ArchivePlan(context.Context, *ArchivePlanRequest) (*ArchivePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchivePlan not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListPlans(context.Context, *ListPlansRequest) (*ListPlansResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlans not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetPlan(context.Context, *GetPlanRequest) (*GetPlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlan not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListFeatures(context.Context, *ListFeaturesRequest) (*ListFeaturesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFeatures not implemented")
+}
+func (UnimplementedPaymentServiceServer) CreateFeature(context.Context, *CreateFeatureRequest) (*CreateFeatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFeature not implemented")
+}
+func (UnimplementedPaymentServiceServer) UpdateFeature(context.Context, *UpdateFeatureRequest) (*UpdateFeatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFeature not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetCheckoutSession(context.Context, *GetCheckoutSessionRequest) (*GetCheckoutSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCheckoutSession not implemented")
+}
+func (UnimplementedPaymentServiceServer) CancelCheckoutSession(context.Context, *CancelCheckoutSessionRequest) (*CancelCheckoutSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCheckoutSession not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetInvoice(context.Context, *GetInvoiceRequest) (*GetInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoice not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListInvoices(context.Context, *ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedPaymentServiceServer) UpsertCustomer(context.Context, *UpsertCustomerRequest) (*UpsertCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertCustomer not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetCustomer(context.Context, *GetCustomerRequest) (*GetCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCustomer not implemented")
+}
+func (UnimplementedPaymentServiceServer) AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentMethods not implemented")
+}
+func (UnimplementedPaymentServiceServer) SetDefaultPaymentMethod(context.Context, *SetDefaultPaymentMethodRequest) (*SetDefaultPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) DetachPaymentMethod(context.Context, *DetachPaymentMethodRequest) (*DetachPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetachPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetRevenueReport(context.Context, *GetRevenueReportRequest) (*GetRevenueReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevenueReport not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedPaymentServiceServer) WatchEntitlements(*WatchEntitlementsRequest, grpc.ServerStreamingServer[WatchEntitlementsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEntitlements not implemented")
+}
+func (UnimplementedPaymentServiceServer) GrantEntitlement(context.Context, *GrantEntitlementRequest) (*GrantEntitlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantEntitlement not implemented")
+}
+func (UnimplementedPaymentServiceServer) RevokeEntitlement(context.Context, *RevokeEntitlementRequest) (*RevokeEntitlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeEntitlement not implemented")
+}
+func (UnimplementedPaymentServiceServer) ExtendEntitlement(context.Context, *ExtendEntitlementRequest) (*ExtendEntitlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendEntitlement not implemented")
+}
+func (UnimplementedPaymentServiceServer) ExplainEntitlement(context.Context, *ExplainEntitlementRequest) (*ExplainEntitlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExplainEntitlement not implemented")
+}
 func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
 func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
 
@@ -470,34 +983,499 @@ func _PaymentService_ListPricingZones_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
-// PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var PaymentService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "payment.v1.PaymentService",
-	HandlerType: (*PaymentServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreatePayment",
-			Handler:    _PaymentService_CreatePayment_Handler,
-		},
-		{
-			MethodName: "GetPayment",
-			Handler:    _PaymentService_GetPayment_Handler,
-		},
-		{
-			MethodName: "UpdatePaymentStatus",
-			Handler:    _PaymentService_UpdatePaymentStatus_Handler,
-		},
-		{
-			MethodName: "GetPaymentsByCustomer",
-			Handler:    _PaymentService_GetPaymentsByCustomer_Handler,
-		},
-		{
-			MethodName: "ListPayments",
-			Handler:    _PaymentService_ListPayments_Handler,
-		},
-		{
+func _PaymentService_ExportPayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportPaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PaymentServiceServer).ExportPayments(m, &grpc.GenericServerStream[ExportPaymentsRequest, Payment]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_ExportPaymentsServer = grpc.ServerStreamingServer[Payment]
+
+func _PaymentService_ExportEntitlements_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportEntitlementsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PaymentServiceServer).ExportEntitlements(m, &grpc.GenericServerStream[ExportEntitlementsRequest, Entitlement]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_ExportEntitlementsServer = grpc.ServerStreamingServer[Entitlement]
+
+func _PaymentService_CreatePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CreatePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CreatePlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CreatePlan(ctx, req.(*CreatePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_UpdatePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).UpdatePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_UpdatePlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).UpdatePlan(ctx, req.(*UpdatePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ArchivePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchivePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ArchivePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ArchivePlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ArchivePlan(ctx, req.(*ArchivePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListPlans_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlansRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListPlans(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListPlans_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListPlans(ctx, req.(*ListPlansRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetPlan(ctx, req.(*GetPlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListFeatures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeaturesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListFeatures(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListFeatures_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListFeatures(ctx, req.(*ListFeaturesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CreateFeature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFeatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CreateFeature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CreateFeature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CreateFeature(ctx, req.(*CreateFeatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_UpdateFeature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).UpdateFeature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_UpdateFeature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).UpdateFeature(ctx, req.(*UpdateFeatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetCheckoutSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckoutSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetCheckoutSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetCheckoutSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetCheckoutSession(ctx, req.(*GetCheckoutSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CancelCheckoutSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCheckoutSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CancelCheckoutSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CancelCheckoutSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CancelCheckoutSession(ctx, req.(*CancelCheckoutSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetInvoice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetInvoice(ctx, req.(*GetInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListInvoices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListInvoices(ctx, req.(*ListInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_UpsertCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).UpsertCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_UpsertCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).UpsertCustomer(ctx, req.(*UpsertCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetCustomer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetCustomer(ctx, req.(*GetCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_AddPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AddPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AddPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AddPaymentMethod(ctx, req.(*AddPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListPaymentMethods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentMethodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListPaymentMethods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListPaymentMethods_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListPaymentMethods(ctx, req.(*ListPaymentMethodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SetDefaultPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SetDefaultPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_SetDefaultPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SetDefaultPaymentMethod(ctx, req.(*SetDefaultPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_DetachPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetachPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).DetachPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_DetachPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).DetachPaymentMethod(ctx, req.(*DetachPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetRevenueReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRevenueReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetRevenueReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetRevenueReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetRevenueReport(ctx, req.(*GetRevenueReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_WatchEntitlements_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEntitlementsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PaymentServiceServer).WatchEntitlements(m, &grpc.GenericServerStream[WatchEntitlementsRequest, WatchEntitlementsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_WatchEntitlementsServer = grpc.ServerStreamingServer[WatchEntitlementsResponse]
+
+func _PaymentService_GrantEntitlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantEntitlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GrantEntitlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GrantEntitlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GrantEntitlement(ctx, req.(*GrantEntitlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RevokeEntitlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeEntitlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RevokeEntitlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RevokeEntitlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RevokeEntitlement(ctx, req.(*RevokeEntitlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExtendEntitlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendEntitlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExtendEntitlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ExtendEntitlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExtendEntitlement(ctx, req.(*ExtendEntitlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ExplainEntitlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainEntitlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ExplainEntitlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ExplainEntitlement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ExplainEntitlement(ctx, req.(*ExplainEntitlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "payment.v1.PaymentService",
+	HandlerType: (*PaymentServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePayment",
+			Handler:    _PaymentService_CreatePayment_Handler,
+		},
+		{
+			MethodName: "GetPayment",
+			Handler:    _PaymentService_GetPayment_Handler,
+		},
+		{
+			MethodName: "UpdatePaymentStatus",
+			Handler:    _PaymentService_UpdatePaymentStatus_Handler,
+		},
+		{
+			MethodName: "GetPaymentsByCustomer",
+			Handler:    _PaymentService_GetPaymentsByCustomer_Handler,
+		},
+		{
+			MethodName: "ListPayments",
+			Handler:    _PaymentService_ListPayments_Handler,
+		},
+		{
 			MethodName: "CreateCheckoutSession",
 			Handler:    _PaymentService_CreateCheckoutSession_Handler,
 		},
@@ -521,7 +1499,119 @@ var PaymentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListPricingZones",
 			Handler:    _PaymentService_ListPricingZones_Handler,
 		},
+		{
+			MethodName: "CreatePlan",
+			Handler:    _PaymentService_CreatePlan_Handler,
+		},
+		{
+			MethodName: "UpdatePlan",
+			Handler:    _PaymentService_UpdatePlan_Handler,
+		},
+		{
+			MethodName: "ArchivePlan",
+			Handler:    _PaymentService_ArchivePlan_Handler,
+		},
+		{
+			MethodName: "ListPlans",
+			Handler:    _PaymentService_ListPlans_Handler,
+		},
+		{
+			MethodName: "GetPlan",
+			Handler:    _PaymentService_GetPlan_Handler,
+		},
+		{
+			MethodName: "ListFeatures",
+			Handler:    _PaymentService_ListFeatures_Handler,
+		},
+		{
+			MethodName: "CreateFeature",
+			Handler:    _PaymentService_CreateFeature_Handler,
+		},
+		{
+			MethodName: "UpdateFeature",
+			Handler:    _PaymentService_UpdateFeature_Handler,
+		},
+		{
+			MethodName: "GetCheckoutSession",
+			Handler:    _PaymentService_GetCheckoutSession_Handler,
+		},
+		{
+			MethodName: "CancelCheckoutSession",
+			Handler:    _PaymentService_CancelCheckoutSession_Handler,
+		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _PaymentService_GetInvoice_Handler,
+		},
+		{
+			MethodName: "ListInvoices",
+			Handler:    _PaymentService_ListInvoices_Handler,
+		},
+		{
+			MethodName: "UpsertCustomer",
+			Handler:    _PaymentService_UpsertCustomer_Handler,
+		},
+		{
+			MethodName: "GetCustomer",
+			Handler:    _PaymentService_GetCustomer_Handler,
+		},
+		{
+			MethodName: "AddPaymentMethod",
+			Handler:    _PaymentService_AddPaymentMethod_Handler,
+		},
+		{
+			MethodName: "ListPaymentMethods",
+			Handler:    _PaymentService_ListPaymentMethods_Handler,
+		},
+		{
+			MethodName: "SetDefaultPaymentMethod",
+			Handler:    _PaymentService_SetDefaultPaymentMethod_Handler,
+		},
+		{
+			MethodName: "DetachPaymentMethod",
+			Handler:    _PaymentService_DetachPaymentMethod_Handler,
+		},
+		{
+			MethodName: "GetRevenueReport",
+			Handler:    _PaymentService_GetRevenueReport_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _PaymentService_ListAuditEvents_Handler,
+		},
+		{
+			MethodName: "GrantEntitlement",
+			Handler:    _PaymentService_GrantEntitlement_Handler,
+		},
+		{
+			MethodName: "RevokeEntitlement",
+			Handler:    _PaymentService_RevokeEntitlement_Handler,
+		},
+		{
+			MethodName: "ExtendEntitlement",
+			Handler:    _PaymentService_ExtendEntitlement_Handler,
+		},
+		{
+			MethodName: "ExplainEntitlement",
+			Handler:    _PaymentService_ExplainEntitlement_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportPayments",
+			Handler:       _PaymentService_ExportPayments_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportEntitlements",
+			Handler:       _PaymentService_ExportEntitlements_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchEntitlements",
+			Handler:       _PaymentService_WatchEntitlements_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/payment/v1/payment_service.proto",
 }
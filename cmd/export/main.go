@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+)
+
+func main() {
+	var (
+		addr     = flag.String("addr", "localhost:8081", "payment service gRPC address")
+		token    = flag.String("token", os.Getenv("PAYMENT_EXPORT_TOKEN"), "auth token sent as better-auth-token (default $PAYMENT_EXPORT_TOKEN)")
+		kind     = flag.String("type", "payments", "what to export: payments or entitlements")
+		format   = flag.String("format", formatCSV, "output format: csv or ndjson")
+		output   = flag.String("o", "-", "output file, - for stdout")
+		customer = flag.String("customer", "", "payments: filter by customer ID")
+		status   = flag.String("status", "", "filter by status")
+		currency = flag.String("currency", "", "payments: filter by currency")
+		method   = flag.String("method", "", "payments: filter by payment method")
+		since    = flag.String("since", "", "payments: created at or after (RFC 3339)")
+		until    = flag.String("until", "", "payments: created before (RFC 3339)")
+		user     = flag.String("user", "", "entitlements: filter by user ID")
+		feature  = flag.String("feature", "", "entitlements: filter by feature code")
+		source   = flag.String("source", "", "entitlements: filter by source, paid, complimentary or manual")
+	)
+	flag.Parse()
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "better-auth-token", *token)
+	}
+
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", *addr, err)
+	}
+	defer conn.Close()
+	client := paymentv1.NewPaymentServiceClient(conn)
+
+	var count int
+	switch *kind {
+	case "payments":
+		filter := &paymentv1.PaymentFilter{
+			Status:        *status,
+			Currency:      *currency,
+			PaymentMethod: *method,
+		}
+		if filter.CreatedAfter, err = parseTime(*since); err != nil {
+			log.Fatalf("Invalid -since: %v", err)
+		}
+		if filter.CreatedBefore, err = parseTime(*until); err != nil {
+			log.Fatalf("Invalid -until: %v", err)
+		}
+		count, err = exportPayments(ctx, client, &paymentv1.ExportPaymentsRequest{
+			CustomerId: *customer,
+			Filter:     filter,
+		}, *format, out)
+	case "entitlements":
+		count, err = exportEntitlements(ctx, client, &paymentv1.ExportEntitlementsRequest{
+			UserId:      *user,
+			FeatureCode: *feature,
+			Status:      *status,
+			Source:      *source,
+		}, *format, out)
+	default:
+		log.Fatalf("Unknown export type %q (want payments or entitlements)", *kind)
+	}
+	if err != nil {
+		log.Fatalf("Export failed after %d rows: %v", count, err)
+	}
+
+	log.Printf("Exported %d %s", count, *kind)
+}
+
+func exportPayments(ctx context.Context, client paymentv1.PaymentServiceClient, req *paymentv1.ExportPaymentsRequest, format string, out io.Writer) (int, error) {
+	w, err := newRecordWriter(format, out, paymentColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	stream, err := client.ExportPayments(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return drain(stream.Recv, func(p *paymentv1.Payment) error {
+		return w.Write(p, paymentRow(p))
+	}, w)
+}
+
+func exportEntitlements(ctx context.Context, client paymentv1.PaymentServiceClient, req *paymentv1.ExportEntitlementsRequest, format string, out io.Writer) (int, error) {
+	w, err := newRecordWriter(format, out, entitlementColumns)
+	if err != nil {
+		return 0, err
+	}
+
+	stream, err := client.ExportEntitlements(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return drain(stream.Recv, func(e *paymentv1.Entitlement) error {
+		return w.Write(e, entitlementRow(e))
+	}, w)
+}
+
+// drain receives messages until the stream ends, writing each one
+func drain[T any](recv func() (T, error), write func(T) error, w recordWriter) (int, error) {
+	count := 0
+	for {
+		msg, err := recv()
+		if errors.Is(err, io.EOF) {
+			return count, w.Flush()
+		}
+		if err != nil {
+			_ = w.Flush()
+			return count, err
+		}
+		if err := write(msg); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+func parseTime(value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected RFC 3339 timestamp: %w", err)
+	}
+	return timestamppb.New(t), nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+var (
+	paymentColumns = []string{
+		"id", "amount", "currency", "status", "payment_method", "customer_id",
+		"order_id", "description", "external_payment_id", "created_at", "updated_at",
+	}
+	entitlementColumns = []string{
+		"id", "user_id", "family_id", "feature_code", "plan_id", "subscription_id",
+		"status", "granted_at", "expires_at", "created_at", "updated_at", "source",
+	}
+)
+
+// recordWriter writes exported records one at a time. CSV writers use the
+// flattened row; NDJSON writers marshal the message itself.
+type recordWriter interface {
+	Write(msg proto.Message, row []string) error
+	Flush() error
+}
+
+// newRecordWriter creates a writer for the given format. CSV output starts
+// with a header row built from columns.
+func newRecordWriter(format string, out io.Writer, columns []string) (recordWriter, error) {
+	switch format {
+	case formatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(columns); err != nil {
+			return nil, fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		return &csvWriter{w: w}, nil
+	case formatNDJSON:
+		return &ndjsonWriter{w: bufio.NewWriter(out)}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want %s or %s)", format, formatCSV, formatNDJSON)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) Write(_ proto.Message, row []string) error {
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+}
+
+var ndjsonOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+func (n *ndjsonWriter) Write(msg proto.Message, _ []string) error {
+	data, err := ndjsonOptions.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+	if _, err := n.w.Write(data); err != nil {
+		return err
+	}
+	return n.w.WriteByte('\n')
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// paymentRow flattens a payment in paymentColumns order
+func paymentRow(p *paymentv1.Payment) []string {
+	return []string{
+		p.Id,
+		strconv.FormatFloat(p.Amount, 'f', 2, 64),
+		p.Currency,
+		p.Status,
+		p.PaymentMethod,
+		p.CustomerId,
+		p.OrderId,
+		p.Description,
+		p.ExternalPaymentId,
+		formatTimestamp(p.CreatedAt),
+		formatTimestamp(p.UpdatedAt),
+	}
+}
+
+// entitlementRow flattens an entitlement in entitlementColumns order
+func entitlementRow(e *paymentv1.Entitlement) []string {
+	return []string{
+		e.Id,
+		e.UserId,
+		e.FamilyId,
+		e.FeatureCode,
+		e.PlanId,
+		e.SubscriptionId,
+		e.Status,
+		formatTimestamp(e.GrantedAt),
+		formatTimestamp(e.ExpiresAt),
+		formatTimestamp(e.CreatedAt),
+		formatTimestamp(e.UpdatedAt),
+		e.Source,
+	}
+}
+
+func formatTimestamp(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339)
+}
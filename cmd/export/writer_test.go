@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+)
+
+func testPayment() *paymentv1.Payment {
+	return &paymentv1.Payment{
+		Id:            "8a6e0804-2bd0-4672-b79d-d97027f9071a",
+		Amount:        19.5,
+		Currency:      "USD",
+		Status:        "completed",
+		PaymentMethod: "credit_card",
+		CustomerId:    "user-1",
+		OrderId:       "order-1",
+		Description:   "Plan, monthly",
+		CreatedAt:     timestamppb.New(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRecordWriter(formatCSV, &buf, paymentColumns)
+	if err != nil {
+		t.Fatalf("newRecordWriter failed: %v", err)
+	}
+	p := testPayment()
+	if err := w.Write(p, paymentRow(p)); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got %q", buf.String())
+	}
+	if lines[0] != strings.Join(paymentColumns, ",") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	want := `8a6e0804-2bd0-4672-b79d-d97027f9071a,19.50,USD,completed,credit_card,user-1,order-1,"Plan, monthly",,2025-01-02T03:04:05Z,`
+	if lines[1] != want {
+		t.Errorf("expected row %q, got %q", want, lines[1])
+	}
+}
+
+func TestNDJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRecordWriter(formatNDJSON, &buf, paymentColumns)
+	if err != nil {
+		t.Fatalf("newRecordWriter failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		p := testPayment()
+		if err := w.Write(p, paymentRow(p)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("line is not valid JSON: %v", err)
+	}
+	if decoded["customer_id"] != "user-1" {
+		t.Errorf("expected proto field names, got %v", decoded)
+	}
+}
+
+func TestNewRecordWriter_UnknownFormat(t *testing.T) {
+	if _, err := newRecordWriter("xml", &bytes.Buffer{}, paymentColumns); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
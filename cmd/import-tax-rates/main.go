@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/db"
+	sharedlog "github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// CSV columns, in order. The header row is required and skipped.
+//
+//	country_code,region,name,rate_percent,inclusive,digital_services,reverse_charge
+//
+// region may be empty for a country-wide rate. The boolean columns may be
+// empty, defaulting to exclusive, applicable to digital services and without
+// reverse charge.
+const csvColumns = 7
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Usage: go run ./cmd/import-tax-rates <csv-file-path>")
+	}
+
+	csvFilePath := os.Args[1]
+
+	// Load configuration
+	cfg, err := config.Load("config.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize logger
+	if err := sharedlog.Init(cfg.Log.Level); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// Read and validate the whole file before touching the database
+	file, err := os.Open(csvFilePath)
+	if err != nil {
+		log.Fatalf("Failed to open CSV file: %v", err)
+	}
+	rates, err := readTaxRates(file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read tax rates from CSV: %v", err)
+	}
+
+	fmt.Printf("Loaded %d tax rates from CSV\n", len(rates))
+
+	// Initialize database connection
+	dbConfig := &db.Config{
+		DSN:      cfg.Postgres.DSN,
+		MaxConns: cfg.Postgres.MaxConns,
+	}
+	dbPool, err := db.NewPool(ctx, dbConfig)
+	if err != nil {
+		log.Fatalf("Failed to create database pool: %v", err)
+	}
+	defer dbPool.Close()
+
+	// Initialize repository
+	store, err := postgres.NewStoreWithPool(dbPool.Pool)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+
+	if err := store.TaxRate().BulkUpsert(ctx, rates); err != nil {
+		log.Fatalf("Failed to import tax rates: %v", err)
+	}
+
+	fmt.Println("Successfully imported tax rates to database")
+}
+
+// readTaxRates parses tax rates from CSV. Any invalid row fails the import
+// so that a typo never silently leaves a jurisdiction untaxed.
+func readTaxRates(r io.Reader) ([]domain.TaxRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = csvColumns
+	reader.TrimLeadingSpace = true
+
+	// Skip header row
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	var rates []domain.TaxRate
+	seen := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV record: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		rate, err := parseTaxRate(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		key := rate.CountryCode + "/" + rate.Region
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate rate for %s, first defined on line %d", line, key, prev)
+		}
+		seen[key] = line
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// parseTaxRate parses one CSV record
+func parseTaxRate(record []string) (domain.TaxRate, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	ratePercent, err := strconv.ParseFloat(record[3], 64)
+	if err != nil {
+		return domain.TaxRate{}, fmt.Errorf("invalid rate_percent %q", record[3])
+	}
+
+	rate := domain.TaxRate{
+		CountryCode: strings.ToUpper(record[0]),
+		Region:      strings.ToUpper(record[1]),
+		Name:        record[2],
+		RatePercent: ratePercent,
+	}
+	if rate.Inclusive, err = parseBool(record[4], false); err != nil {
+		return domain.TaxRate{}, fmt.Errorf("invalid inclusive %q", record[4])
+	}
+	if rate.DigitalServices, err = parseBool(record[5], true); err != nil {
+		return domain.TaxRate{}, fmt.Errorf("invalid digital_services %q", record[5])
+	}
+	if rate.ReverseCharge, err = parseBool(record[6], false); err != nil {
+		return domain.TaxRate{}, fmt.Errorf("invalid reverse_charge %q", record[6])
+	}
+
+	if err := rate.Validate(); err != nil {
+		return domain.TaxRate{}, err
+	}
+	return rate, nil
+}
+
+// parseBool parses a boolean column, returning def when it is empty
+func parseBool(value string, def bool) (bool, error) {
+	if value == "" {
+		return def, nil
+	}
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadTaxRates(t *testing.T) {
+	input := `country_code,region,name,rate_percent,inclusive,digital_services,reverse_charge
+de,,VAT,19,true,,true
+US,ny,Sales tax,4,,,
+`
+	rates, err := readTaxRates(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("expected 2 rates, got %d", len(rates))
+	}
+
+	de := rates[0]
+	if de.CountryCode != "DE" || de.Region != "" || de.RatePercent != 19 || !de.Inclusive || !de.DigitalServices || !de.ReverseCharge {
+		t.Errorf("unexpected DE rate: %+v", de)
+	}
+	ny := rates[1]
+	if ny.CountryCode != "US" || ny.Region != "NY" || ny.Inclusive || !ny.DigitalServices || ny.ReverseCharge {
+		t.Errorf("unexpected US-NY rate: %+v", ny)
+	}
+}
+
+func TestReadTaxRates_Invalid(t *testing.T) {
+	header := "country_code,region,name,rate_percent,inclusive,digital_services,reverse_charge\n"
+	tests := map[string]string{
+		"bad rate":       "DE,,VAT,nineteen,,,\n",
+		"bad boolean":    "DE,,VAT,19,maybe,,\n",
+		"bad country":    "DEU,,VAT,19,,,\n",
+		"missing column": "DE,,VAT,19\n",
+		"duplicate":      "DE,,VAT,19,,,\nDE,,VAT,7,,,\n",
+	}
+
+	for name, rows := range tests {
+		if _, err := readTaxRates(strings.NewReader(header + rows)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/db"
+	sharedlog "github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+func main() {
+	var (
+		customer = flag.String("customer", "", "print the balances of one customer instead of the trial balance")
+		account  = flag.String("account", "", "print the balances of one account instead of the trial balance")
+	)
+	flag.Parse()
+
+	// Without flags, check that debits equal credits in every currency and
+	// exit 1 when they do not
+
+	// Load configuration
+	cfg, err := config.Load("config.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize logger
+	if err := sharedlog.Init(cfg.Log.Level); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Initialize database connection
+	dbPool, err := db.NewPool(ctx, &db.Config{
+		DSN:      cfg.Postgres.DSN,
+		MaxConns: cfg.Postgres.MaxConns,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create database pool: %v", err)
+	}
+	defer dbPool.Close()
+
+	store, err := postgres.NewStoreWithPool(dbPool.Pool)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+	ledger := usecase.NewLedgerUseCase(store.Ledger(), store.Payment())
+
+	var balances []domain.AccountBalance
+	switch {
+	case *customer != "":
+		balances, err = ledger.CustomerBalances(ctx, *customer)
+	case *account != "":
+		balances, err = ledger.AccountBalances(ctx, *account)
+	default:
+		tb, err := ledger.TrialBalance(ctx)
+		if err != nil {
+			log.Fatalf("Failed to compute the trial balance: %v", err)
+		}
+		if err := writeTrialBalance(os.Stdout, tb); err != nil {
+			log.Fatalf("Failed to write the trial balance: %v", err)
+		}
+		if !tb.Balanced() {
+			os.Exit(1)
+		}
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to get balances: %v", err)
+	}
+	if err := writeBalances(os.Stdout, balances); err != nil {
+		log.Fatalf("Failed to write balances: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// writeBalances writes one line per account and currency
+func writeBalances(out io.Writer, balances []domain.AccountBalance) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "ACCOUNT\tCURRENCY\tDEBIT\tCREDIT\tBALANCE\t")
+	for _, b := range balances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", b.AccountCode, b.Currency,
+			formatCents(b.DebitCents), formatCents(b.CreditCents), formatCents(b.BalanceCents()))
+	}
+	return w.Flush()
+}
+
+// writeTrialBalance writes the account balances, the totals per currency
+// and any unbalanced entries, ending with whether the ledger balances
+func writeTrialBalance(out io.Writer, tb *domain.TrialBalance) error {
+	if err := writeBalances(out, tb.Accounts); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CURRENCY\tDEBITS\tCREDITS\tDIFFERENCE\t")
+	for _, total := range tb.Totals() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", total.Currency,
+			formatCents(total.DebitCents), formatCents(total.CreditCents), formatCents(total.DebitCents-total.CreditCents))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, e := range tb.Unbalanced {
+		fmt.Fprintf(out, "Unbalanced entry %s (%s): debits %s, credits %s %s\n",
+			e.Reference, e.EntryID, formatCents(e.DebitCents), formatCents(e.CreditCents), e.Currency)
+	}
+
+	if tb.Balanced() {
+		_, err := fmt.Fprintln(out, "\nOK: debits equal credits")
+		return err
+	}
+	_, err := fmt.Fprintln(out, "\nFAIL: debits do not equal credits")
+	return err
+}
+
+// formatCents formats an amount in cents as a decimal, e.g. -12.05
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
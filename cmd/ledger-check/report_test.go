@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func TestWriteTrialBalance(t *testing.T) {
+	tb := &domain.TrialBalance{Accounts: []domain.AccountBalance{
+		{AccountCode: domain.AccountProviderClearing, NormalBalance: domain.PostingDebit, Currency: "USD", DebitCents: 1199},
+		{AccountCode: domain.AccountRevenue, NormalBalance: domain.PostingCredit, Currency: "USD", CreditCents: 999},
+		{AccountCode: domain.AccountTaxPayable, NormalBalance: domain.PostingCredit, Currency: "USD", CreditCents: 200},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeTrialBalance(&buf, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "OK: debits equal credits") {
+		t.Errorf("expected a balanced trial balance:\n%s", out)
+	}
+	if !strings.Contains(out, "revenue") || !strings.Contains(out, "9.99") {
+		t.Errorf("expected the revenue balance:\n%s", out)
+	}
+
+	tb.Unbalanced = []domain.UnbalancedEntry{{Reference: "payment:1:charge", Currency: "USD", DebitCents: 100, CreditCents: 99}}
+	buf.Reset()
+	if err := writeTrialBalance(&buf, tb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "FAIL") || !strings.Contains(out, "payment:1:charge") {
+		t.Errorf("expected the unbalanced entry to fail the check:\n%s", out)
+	}
+}
+
+func TestFormatCents(t *testing.T) {
+	tests := map[int64]string{0: "0.00", 5: "0.05", 1199: "11.99", -1205: "-12.05"}
+	for cents, want := range tests {
+		if got := formatCents(cents); got != want {
+			t.Errorf("formatCents(%d) = %q, want %q", cents, got, want)
+		}
+	}
+}
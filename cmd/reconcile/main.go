@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/app"
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	"github.com/jia-app/paymentservice/internal/payment/subscription"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+	"github.com/jia-app/paymentservice/internal/shared/cache"
+	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/db"
+	sharedlog "github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+func main() {
+	var (
+		since  = flag.String("since", "", "reconcile records created at or after (RFC 3339, default 24h before -until)")
+		until  = flag.String("until", "", "reconcile records created before (RFC 3339, default now)")
+		format = flag.String("format", formatCSV, "output format: csv or json")
+		output = flag.String("o", "-", "output file, - for stdout")
+		heal   = flag.Bool("heal", false, "re-apply completions the provider recorded but the service missed")
+		name   = flag.String("provider", "", "provider to reconcile when routing between several (default the first routed provider)")
+	)
+	flag.Parse()
+
+	to := time.Now()
+	if *until != "" {
+		t, err := time.Parse(time.RFC3339, *until)
+		if err != nil {
+			log.Fatalf("Invalid -until: %v", err)
+		}
+		to = t
+	}
+	from := to.Add(-24 * time.Hour)
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			log.Fatalf("Invalid -since: %v", err)
+		}
+		from = t
+	}
+	if *format != formatCSV && *format != formatJSON {
+		log.Fatalf("Unknown format %q (want %s or %s)", *format, formatCSV, formatJSON)
+	}
+
+	// Load configuration
+	cfg, err := config.Load("config.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize logger
+	if err := sharedlog.Init(cfg.Log.Level); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Initialize database connection
+	dbPool, err := db.NewPool(ctx, &db.Config{
+		DSN:      cfg.Postgres.DSN,
+		MaxConns: cfg.Postgres.MaxConns,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create database pool: %v", err)
+	}
+	defer dbPool.Close()
+
+	store, err := postgres.NewStoreWithPool(dbPool.Pool)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+
+	provider, err := app.NewBillingProvider(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to create billing provider: %v", err)
+	}
+	defer provider.Close()
+
+	var checkoutUseCase *usecase.CheckoutUseCase
+	if *heal {
+		checkoutUseCase, err = newCheckoutUseCase(cfg, store, provider)
+		if err != nil {
+			log.Fatalf("Failed to set up healing: %v", err)
+		}
+	}
+
+	providerName := app.BillingProviderName(cfg)
+	if *name != "" {
+		if !billing.Handles(provider, *name) {
+			log.Fatalf("Billing provider %q is not configured", *name)
+		}
+		providerName = *name
+	}
+
+	reconciler := usecase.NewReconciler(provider, providerName, store.Payment(), store.Subscription(), checkoutUseCase)
+	report, err := reconciler.Reconcile(ctx, usecase.ReconcileRequest{From: from, To: to, Heal: *heal})
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeReport(*format, out, report); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Checked %d charges, %d payments and %d subscriptions: %d discrepancies, %d healed\n",
+		report.ChargesChecked, report.PaymentsChecked, report.SubscriptionsChecked,
+		len(report.Discrepancies), report.Healed)
+}
+
+// newCheckoutUseCase builds the checkout use case healing goes through, so
+// that re-applied events grant entitlements and issue invoices as webhooks do
+func newCheckoutUseCase(cfg *config.Config, store *postgres.Store, provider billing.Provider) (*usecase.CheckoutUseCase, error) {
+	renderer, err := app.NewInvoiceRenderer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invoice renderer: %w", err)
+	}
+	invoiceUseCase := usecase.NewInvoiceUseCase(store.Invoice(), store.Plan(), renderer)
+	customerUseCase := usecase.NewCustomerUseCase(store.Customer(), provider, app.BillingProviderName(cfg))
+	ledgerUseCase := usecase.NewLedgerUseCase(store.Ledger(), store.Payment())
+	auditor := audit.NewRecorder(store.Audit())
+
+	// Evict the service's cached entitlements when Redis is reachable;
+	// otherwise they refresh when the cache entries expire
+	entitlementCache, err := cache.NewCache(cfg.Redis.Addr, cfg.Redis.Password, cfg.Redis.DB)
+	if err != nil {
+		log.Printf("Redis unavailable, cached entitlements will not be evicted: %v", err)
+		entitlementCache = nil
+	}
+
+	return usecase.NewCheckoutUseCase(
+		store.Plan(),
+		store.Entitlement(),
+		store.PricingZone(),
+		store.Payment(),
+		store.CheckoutSession(),
+		usecase.NewFeatureCatalogUseCase(store.Feature(), store.Plan(), nil),
+		provider,
+		app.BillingProviderName(cfg),
+		app.NewCheckoutExpiryConfig(cfg),
+		entitlementCache,
+		nil,
+		nil,
+		usecase.CheckoutOptions{
+			Invoices:      invoiceUseCase,
+			Customers:     customerUseCase,
+			Ledger:        ledgerUseCase,
+			Subscriptions: subscription.NewLifecycleManager(store.Subscription(), store.Entitlement(), nil, nil, auditor),
+			Auditor:       auditor,
+		},
+	), nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+var discrepancyColumns = []string{
+	"kind", "record", "provider_id", "session_id", "local_id", "user_id", "currency",
+	"provider_amount_cents", "local_amount_cents", "provider_status", "local_status",
+	"created_at", "healed", "heal_error",
+}
+
+// writeReport writes a reconciliation report. CSV output has one row per
+// discrepancy; JSON output is the whole report, including its totals.
+func writeReport(format string, out io.Writer, report *domain.ReconciliationReport) error {
+	switch format {
+	case formatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(discrepancyColumns); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		for _, d := range report.Discrepancies {
+			if err := w.Write(discrepancyRow(d)); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		w.Flush()
+		return w.Error()
+	case formatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		return fmt.Errorf("unknown format %q (want %s or %s)", format, formatCSV, formatJSON)
+	}
+}
+
+// discrepancyRow flattens a discrepancy into discrepancyColumns order
+func discrepancyRow(d domain.Discrepancy) []string {
+	return []string{
+		string(d.Kind),
+		string(d.Record),
+		d.ProviderID,
+		d.SessionID,
+		d.LocalID,
+		d.UserID,
+		d.Currency,
+		formatCents(d.ProviderAmountCents),
+		formatCents(d.LocalAmountCents),
+		d.ProviderStatus,
+		d.LocalStatus,
+		d.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatBool(d.Healed),
+		d.HealError,
+	}
+}
+
+// formatCents formats an amount, leaving unknown (zero) amounts empty
+func formatCents(cents int64) string {
+	if cents == 0 {
+		return ""
+	}
+	return strconv.FormatInt(cents, 10)
+}
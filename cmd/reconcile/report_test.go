@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func testReport() *domain.ReconciliationReport {
+	return &domain.ReconciliationReport{
+		Provider:       "stripe",
+		From:           time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC),
+		ChargesChecked: 2,
+		Discrepancies: []domain.Discrepancy{
+			{
+				Kind:                domain.DiscrepancyMissingLocal,
+				Record:              domain.ReconciledPayment,
+				ProviderID:          "pi_1",
+				SessionID:           "cs_1",
+				UserID:              "user-1",
+				Currency:            "USD",
+				ProviderAmountCents: 999,
+				ProviderStatus:      "succeeded",
+				CreatedAt:           time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+				Healed:              true,
+			},
+			{
+				Kind:           domain.DiscrepancyStatusMismatch,
+				Record:         domain.ReconciledSubscription,
+				ProviderID:     "sub_1",
+				LocalID:        "8a3e1c0e-0000-4000-8000-000000000001",
+				ProviderStatus: "cancelled",
+				LocalStatus:    "active",
+				CreatedAt:      time.Date(2025, 3, 1, 13, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestWriteReport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReport(formatCSV, &buf, testReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d rows", len(rows))
+	}
+	if strings.Join(rows[0], ",") != strings.Join(discrepancyColumns, ",") {
+		t.Errorf("unexpected header: %v", rows[0])
+	}
+
+	want := []string{"missing_local", "payment", "pi_1", "cs_1", "", "user-1", "USD", "999", "", "succeeded", "", "2025-03-01T12:00:00Z", "true", ""}
+	if strings.Join(rows[1], ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected row:\n got %v\nwant %v", rows[1], want)
+	}
+	if rows[2][0] != "status_mismatch" || rows[2][10] != "active" {
+		t.Errorf("unexpected row: %v", rows[2])
+	}
+}
+
+func TestWriteReport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReport(formatJSON, &buf, testReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got domain.ReconciliationReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if got.ChargesChecked != 2 || len(got.Discrepancies) != 2 {
+		t.Errorf("unexpected report: %+v", got)
+	}
+	if got.Discrepancies[0].Kind != domain.DiscrepancyMissingLocal || !got.Discrepancies[0].Healed {
+		t.Errorf("unexpected discrepancy: %+v", got.Discrepancies[0])
+	}
+}
+
+func TestWriteReport_UnknownFormat(t *testing.T) {
+	if err := writeReport("xml", &bytes.Buffer{}, testReport()); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/db"
+	sharedlog "github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+const monthLayout = "2006-01"
+
+func main() {
+	var (
+		from     = flag.String("from", "", "first month to report (YYYY-MM, default 11 months before -to)")
+		to       = flag.String("to", "", "last month to report (YYYY-MM, default the current month)")
+		groupBy  = flag.String("group-by", "", "comma-separated dimensions to slice by: plan, pricing_zone, currency")
+		format   = flag.String("format", formatTable, "output format: table, csv or json")
+		output   = flag.String("o", "-", "output file, - for stdout")
+		snapshot = flag.String("snapshot", "", "take the snapshot of one day (YYYY-MM-DD) instead of reporting")
+		backfill = flag.Int("backfill", 0, "snapshot every missing day, going back at most this many days, before reporting")
+	)
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load("config.yaml")
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize logger
+	if err := sharedlog.Init(cfg.Log.Level); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Initialize database connection
+	dbPool, err := db.NewPool(ctx, &db.Config{
+		DSN:      cfg.Postgres.DSN,
+		MaxConns: cfg.Postgres.MaxConns,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create database pool: %v", err)
+	}
+	defer dbPool.Close()
+
+	store, err := postgres.NewStoreWithPool(dbPool.Pool)
+	if err != nil {
+		log.Fatalf("Failed to create repository: %v", err)
+	}
+	revenue := usecase.NewRevenueUseCase(store.Revenue())
+
+	if *snapshot != "" {
+		day, err := time.Parse(time.DateOnly, *snapshot)
+		if err != nil {
+			log.Fatalf("Invalid -snapshot: %v", err)
+		}
+		n, err := revenue.Snapshot(ctx, day)
+		if err != nil {
+			log.Fatalf("Failed to snapshot %s: %v", *snapshot, err)
+		}
+		log.Printf("Snapshotted %d subscriptions for %s", n, *snapshot)
+		return
+	}
+	if *backfill > 0 {
+		days, err := revenue.SnapshotMissingDays(ctx, *backfill)
+		if err != nil {
+			log.Fatalf("Failed to backfill snapshots: %v", err)
+		}
+		log.Printf("Snapshotted %d days", len(days))
+	}
+
+	req := usecase.RevenueReportRequest{To: time.Now()}
+	if *to != "" {
+		if req.To, err = time.Parse(monthLayout, *to); err != nil {
+			log.Fatalf("Invalid -to: %v", err)
+		}
+	}
+	req.From = domain.MonthStart(req.To).AddDate(0, -11, 0)
+	if *from != "" {
+		if req.From, err = time.Parse(monthLayout, *from); err != nil {
+			log.Fatalf("Invalid -from: %v", err)
+		}
+	}
+	if *groupBy != "" {
+		for _, name := range strings.Split(*groupBy, ",") {
+			dim, err := domain.ParseRevenueDimension(strings.TrimSpace(name))
+			if err != nil {
+				log.Fatalf("Invalid -group-by: %v", err)
+			}
+			req.GroupBy = append(req.GroupBy, dim)
+		}
+	}
+
+	report, err := revenue.GetRevenueReport(ctx, req)
+	if err != nil {
+		log.Fatalf("Failed to build the revenue report: %v", err)
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := writeReport(out, *format, report); err != nil {
+		log.Fatalf("Failed to write the revenue report: %v", err)
+	}
+}
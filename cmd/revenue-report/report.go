@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+const (
+	formatTable = "table"
+	formatCSV   = "csv"
+	formatJSON  = "json"
+)
+
+var reportColumns = []string{
+	"month", "currency", "plan_id", "pricing_zone",
+	"starting_mrr", "new_mrr", "expansion_mrr", "contraction_mrr", "churned_mrr", "ending_mrr", "ending_arr",
+	"starting_customers", "new_customers", "churned_customers", "ending_customers", "logo_churn_rate",
+}
+
+// writeReport writes the report's movements in the given format
+func writeReport(out io.Writer, format string, report *domain.RevenueReport) error {
+	switch format {
+	case formatTable:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "MONTH\tCURRENCY\tPLAN\tZONE\tSTART MRR\tNEW\tEXPANSION\tCONTRACTION\tCHURNED\tEND MRR\tARR\tCUSTOMERS\tLOGO CHURN\t")
+		for _, m := range report.Movements {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%.1f%%\t\n",
+				m.Month.Format(monthLayout), m.Slice.Currency, m.Slice.PlanID, m.Slice.PricingZone,
+				formatCents(m.StartingMRRCents), formatCents(m.NewMRRCents), formatCents(m.ExpansionMRRCents),
+				formatCents(m.ContractionMRRCents), formatCents(m.ChurnedMRRCents), formatCents(m.EndingMRRCents),
+				formatCents(m.ARRCents()), m.EndingCustomers, m.LogoChurnRate()*100)
+		}
+		return w.Flush()
+	case formatCSV:
+		w := csv.NewWriter(out)
+		if err := w.Write(reportColumns); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		for _, m := range report.Movements {
+			if err := w.Write(movementRow(m)); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		w.Flush()
+		return w.Error()
+	case formatJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	default:
+		return fmt.Errorf("unknown format %q (want %s, %s or %s)", format, formatTable, formatCSV, formatJSON)
+	}
+}
+
+// movementRow flattens a movement into reportColumns
+func movementRow(m domain.RevenueMovement) []string {
+	return []string{
+		m.Month.Format(monthLayout),
+		m.Slice.Currency,
+		m.Slice.PlanID,
+		m.Slice.PricingZone,
+		formatCents(m.StartingMRRCents),
+		formatCents(m.NewMRRCents),
+		formatCents(m.ExpansionMRRCents),
+		formatCents(m.ContractionMRRCents),
+		formatCents(m.ChurnedMRRCents),
+		formatCents(m.EndingMRRCents),
+		formatCents(m.ARRCents()),
+		strconv.Itoa(m.StartingCustomers),
+		strconv.Itoa(m.NewCustomers),
+		strconv.Itoa(m.ChurnedCustomers),
+		strconv.Itoa(m.EndingCustomers),
+		strconv.FormatFloat(m.LogoChurnRate(), 'f', 4, 64),
+	}
+}
+
+// formatCents formats an amount in cents as a decimal, e.g. -12.05
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
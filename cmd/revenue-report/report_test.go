@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func testReport() *domain.RevenueReport {
+	month := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	return &domain.RevenueReport{
+		From:    month,
+		To:      month,
+		GroupBy: []domain.RevenueDimension{domain.RevenueByPlan},
+		Movements: []domain.RevenueMovement{{
+			Month:             month,
+			Slice:             domain.RevenueSlice{PlanID: "premium", Currency: "USD"},
+			StartingMRRCents:  2000,
+			NewMRRCents:       500,
+			ChurnedMRRCents:   1000,
+			EndingMRRCents:    1500,
+			StartingCustomers: 2,
+			NewCustomers:      1,
+			ChurnedCustomers:  1,
+			EndingCustomers:   2,
+		}},
+	}
+}
+
+func TestWriteReport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReport(&buf, formatCSV, testReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 2 || len(rows[1]) != len(reportColumns) {
+		t.Fatalf("expected a header and one row, got %v", rows)
+	}
+	row := rows[1]
+	if row[0] != "2025-02" || row[2] != "premium" || row[9] != "15.00" || row[10] != "180.00" || row[15] != "0.5000" {
+		t.Errorf("unexpected row: %v", row)
+	}
+}
+
+func TestWriteReport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReport(&buf, formatJSON, testReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded domain.RevenueReport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.Movements) != 1 || decoded.Movements[0].EndingMRRCents != 1500 {
+		t.Errorf("unexpected report: %+v", decoded)
+	}
+}
+
+func TestWriteReport_Table(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReport(&buf, formatTable, testReport()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "premium") || !strings.Contains(out, "50.0%") {
+		t.Errorf("unexpected table:\n%s", out)
+	}
+	if err := writeReport(&buf, "xml", testReport()); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -12,21 +12,46 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jia-app/paymentservice/internal/app/server"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	"github.com/jia-app/paymentservice/internal/shared/cache"
 	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+	"github.com/jia-app/paymentservice/internal/shared/health"
 	"github.com/jia-app/paymentservice/internal/shared/log"
 	"github.com/jia-app/paymentservice/internal/shared/metrics"
 	"github.com/jia-app/paymentservice/internal/shared/services"
+	"github.com/jia-app/paymentservice/internal/shared/tracing"
 )
 
+// repositoryStore is the set of payment repositories shared by the
+// postgres and memory storage modes
+type repositoryStore interface {
+	Payment() repo.PaymentRepository
+	Plan() repo.PlanRepository
+	Entitlement() repo.EntitlementRepository
+	Subscription() repo.SubscriptionRepository
+	PricingZone() repo.PricingZoneRepository
+	Usage() repo.UsageRepository
+}
+
 // App represents the application
 type App struct {
-	config           *config.Config
-	logger           *zap.Logger
-	dbPool           *pgxpool.Pool
-	redisClient      *redis.Client
-	grpcServer       *server.GRPCServer
-	metricsCollector *metrics.MetricsCollector
-	serviceManager   *services.ServiceManager
+	config               *config.Config
+	logger               *zap.Logger
+	dbPool               *pgxpool.Pool
+	store                repositoryStore
+	redisClient          *redis.Client
+	entitlementHub       *events.EntitlementHub // Feeds WatchEntitlements streams on this replica
+	entitlementPublisher events.EntitlementPublisher
+	entitlementFanout    *events.RedisEntitlementFanout // Nil without Redis
+	entitlementCache     *cache.Cache                   // Nil without Redis
+	grpcServer           *server.GRPCServer
+	adminServer          *health.Server
+	metricsCollector     *metrics.MetricsCollector
+	serviceManager       *services.ServiceManager
+	shutdownTracing      tracing.ShutdownFunc
 }
 
 // New creates a new application instance
@@ -41,10 +66,29 @@ func New(cfg *config.Config) (*App, error) {
 		zap.String("app_name", cfg.AppName),
 		zap.String("grpc_address", cfg.GRPC.Address))
 
-	// Initialize database pool
-	dbPool, err := initializeDatabase(cfg)
+	// Initialize tracing before any instrumented clients are created
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing, cfg.AppName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+		return nil, fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
+	// Initialize the repository store; memory mode skips the database entirely
+	var dbPool *pgxpool.Pool
+	var store repositoryStore
+	if cfg.Storage.Mode == config.StorageModeMemory {
+		logger.Warn("Using in-memory storage, data will not be persisted")
+		store = memory.NewStore()
+	} else {
+		dbPool, err = initializeDatabase(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize database: %w", err)
+		}
+		pgStore, err := postgres.NewStoreWithPool(dbPool)
+		if err != nil {
+			dbPool.Close()
+			return nil, fmt.Errorf("failed to initialize repositories: %w", err)
+		}
+		store = pgStore
 	}
 
 	// Initialize Redis client (optional)
@@ -56,9 +100,30 @@ func New(cfg *config.Config) (*App, error) {
 		redisClient = nil
 	}
 
+	// Initialize entitlement change fan-out for WatchEntitlements
+	entitlementHub := events.NewEntitlementHub()
+	var entitlementPublisher events.EntitlementPublisher = entitlementHub
+	var entitlementFanout *events.RedisEntitlementFanout
+	if redisClient != nil {
+		entitlementFanout = events.NewRedisEntitlementFanout(redisClient, entitlementHub, logger)
+		entitlementPublisher = entitlementFanout
+	} else {
+		logger.Warn("Redis not configured, entitlement watches only see changes made by this replica")
+	}
+
 	// Initialize metrics collector
 	metricsCollector := metrics.NewMetricsCollector()
 
+	// Initialize the two-tier entitlement cache; replicas drop their local
+	// copies when any of them evicts an entitlement from Redis
+	var entitlementCache *cache.Cache
+	if redisClient != nil {
+		entitlementCache = cache.NewTieredCache(redisClient, cache.LocalConfig{
+			Size: cfg.Redis.LocalCacheSize,
+			TTL:  time.Duration(cfg.Redis.LocalCacheTTLSec) * time.Second,
+		}, metricsCollector)
+	}
+
 	// Initialize service discovery and service manager if enabled
 	var serviceManager *services.ServiceManager
 	if cfg.ServiceMesh.Enabled {
@@ -87,6 +152,18 @@ func New(cfg *config.Config) (*App, error) {
 	// Initialize gRPC server
 	grpcServer := server.NewGRPCServer(cfg, dbPool, redisClient, metricsCollector)
 
+	// Initialize admin HTTP server for probes and Prometheus scraping
+	healthService := health.NewService(metricsCollector)
+	if dbPool != nil {
+		healthService.AddReadinessCheck("database", dbPool.Ping)
+	}
+	if redisClient != nil {
+		healthService.AddReadinessCheck("redis", func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
+	adminServer := health.NewServer(cfg.Admin.Address, healthService)
+
 	// Log service mesh configuration
 	if cfg.ServiceMesh.Enabled {
 		allowedSpiffeIDs := []string{
@@ -100,18 +177,27 @@ func New(cfg *config.Config) (*App, error) {
 		logger.Info("Service mesh disabled, using standard JWT authentication")
 	}
 
-	// TODO: Initialize and register payment service
+	// TODO: Initialize and register payment service on top of store, passing
+	// entitlementPublisher to the use cases, entitlementHub to the
+	// entitlement use case and entitlementCache to the entitlement use cases
 	// paymentService := transport.NewPaymentService(...)
 	// grpcServer.RegisterPaymentService(paymentService)
 
 	return &App{
-		config:           cfg,
-		logger:           logger,
-		dbPool:           dbPool,
-		redisClient:      redisClient,
-		grpcServer:       grpcServer,
-		metricsCollector: metricsCollector,
-		serviceManager:   serviceManager,
+		config:               cfg,
+		logger:               logger,
+		dbPool:               dbPool,
+		store:                store,
+		redisClient:          redisClient,
+		entitlementHub:       entitlementHub,
+		entitlementPublisher: entitlementPublisher,
+		entitlementFanout:    entitlementFanout,
+		entitlementCache:     entitlementCache,
+		grpcServer:           grpcServer,
+		adminServer:          adminServer,
+		metricsCollector:     metricsCollector,
+		serviceManager:       serviceManager,
+		shutdownTracing:      shutdownTracing,
 	}, nil
 }
 
@@ -122,6 +208,32 @@ func (a *App) Run(ctx context.Context) error {
 	// Start health monitoring
 	a.grpcServer.StartHealthMonitoring(ctx)
 
+	// Start admin HTTP server; it stops when ctx is cancelled
+	go func() {
+		if err := a.adminServer.Serve(ctx); err != nil {
+			a.logger.Error("Admin HTTP server error", zap.Error(err))
+		}
+	}()
+
+	// Relay entitlement changes from other replicas; it stops when ctx is cancelled
+	if a.entitlementFanout != nil {
+		go func() {
+			if err := a.entitlementFanout.Run(ctx); err != nil {
+				a.logger.Error("Entitlement change relay error", zap.Error(err))
+			}
+		}()
+	}
+
+	// Drop locally cached entitlements evicted by other replicas; it stops
+	// when ctx is cancelled
+	if a.entitlementCache != nil {
+		go func() {
+			if err := a.entitlementCache.RunInvalidation(ctx); err != nil {
+				a.logger.Error("Entitlement cache invalidation error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start gRPC server
 	if err := a.grpcServer.Serve(ctx); err != nil {
 		return fmt.Errorf("gRPC server error: %w", err)
@@ -153,6 +265,13 @@ func (a *App) Shutdown(ctx context.Context) error {
 		a.dbPool.Close()
 	}
 
+	// Flush buffered spans
+	if a.shutdownTracing != nil {
+		if err := a.shutdownTracing(ctx); err != nil {
+			a.logger.Error("Failed to shut down tracing", zap.Error(err))
+		}
+	}
+
 	a.logger.Info("Application shutdown complete")
 	return nil
 }
@@ -162,7 +281,13 @@ func initializeDatabase(cfg *config.Config) (*pgxpool.Pool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	pool, err := pgxpool.New(ctx, cfg.Postgres.DSN)
+	poolConfig, err := pgxpool.ParseConfig(cfg.Postgres.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database pool: %w", err)
 	}
@@ -182,6 +307,7 @@ func initializeRedis(cfg *config.Config) (*redis.Client, error) {
 		DB:       cfg.Redis.DB,
 		Password: cfg.Redis.Password,
 	})
+	client.AddHook(tracing.NewRedisHook())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -2,14 +2,26 @@ package app
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
 	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/adyenbp"
+	"github.com/jia-app/paymentservice/internal/billing/paypalbp"
 	"github.com/jia-app/paymentservice/internal/billing/stripebp"
+	"github.com/jia-app/paymentservice/internal/payment/invoice"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
 	"github.com/jia-app/paymentservice/internal/shared/config"
 	"github.com/jia-app/paymentservice/internal/shared/log"
 )
@@ -21,14 +33,87 @@ func NewBillingProvider(ctx context.Context, cfg *config.Config) (billing.Provid
 	log.Info(ctx, "Initializing billing provider",
 		zap.String("provider", cfg.Billing.Provider))
 
-	switch cfg.Billing.Provider {
+	if cfg.Billing.Provider == "routing" {
+		return NewRoutingProvider(ctx, cfg, logger)
+	}
+
+	provider, err := newProvider(ctx, cfg, cfg.Billing.Provider, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return billing.WithTracing(cfg.Billing.Provider, provider), nil
+}
+
+// newProvider creates the named billing provider
+func newProvider(ctx context.Context, cfg *config.Config, name string, logger *zap.Logger) (billing.Provider, error) {
+	switch name {
 	case "stripe":
 		return NewStripeProvider(ctx, cfg, logger)
+	case "paypal":
+		return NewPayPalProvider(ctx, cfg, logger)
+	case "adyen":
+		return NewAdyenProvider(ctx, cfg, logger)
 	case "mock", "noop":
 		return NewMockProvider(ctx, logger)
 	default:
-		return nil, fmt.Errorf("unsupported billing provider: %s", cfg.Billing.Provider)
+		return nil, fmt.Errorf("unsupported billing provider: %s", name)
+	}
+}
+
+// NewRoutingProvider creates every provider listed in the routing
+// configuration and a router that picks one per checkout. Each provider is
+// traced under its own name.
+func NewRoutingProvider(ctx context.Context, cfg *config.Config, logger *zap.Logger) (billing.Provider, error) {
+	routes := make([]billing.Route, 0, len(cfg.Billing.Routing.Providers))
+	for _, name := range cfg.Billing.Routing.Providers {
+		provider, err := newProvider(ctx, cfg, name, logger)
+		if err != nil {
+			for _, route := range routes {
+				route.Provider.Close()
+			}
+			return nil, fmt.Errorf("failed to create %s provider for routing: %w", name, err)
+		}
+		routes = append(routes, billing.Route{Name: name, Provider: billing.WithTracing(name, provider)})
+	}
+
+	router, err := billing.NewRouter(routes, NewRoutingRules(cfg))
+	if err != nil {
+		for _, route := range routes {
+			route.Provider.Close()
+		}
+		return nil, fmt.Errorf("invalid billing routing configuration: %w", err)
+	}
+
+	log.Info(ctx, "Routing checkouts between billing providers",
+		zap.Strings("providers", cfg.Billing.Routing.Providers),
+		zap.Int("rules", len(cfg.Billing.Routing.Rules)))
+
+	return router, nil
+}
+
+// NewRoutingRules converts the routing configuration into router rules
+func NewRoutingRules(cfg *config.Config) []billing.RoutingRule {
+	rules := make([]billing.RoutingRule, 0, len(cfg.Billing.Routing.Rules))
+	for _, r := range cfg.Billing.Routing.Rules {
+		rules = append(rules, billing.RoutingRule{
+			Countries:  r.Countries,
+			Currencies: r.Currencies,
+			Plans:      r.Plans,
+			Providers:  r.Providers,
+		})
+	}
+	return rules
+}
+
+// BillingProviderName returns the provider name the use cases record on
+// customers and payment methods: the configured provider, or the default
+// one when routing
+func BillingProviderName(cfg *config.Config) string {
+	if cfg.Billing.Provider == "routing" && len(cfg.Billing.Routing.Providers) > 0 {
+		return cfg.Billing.Routing.Providers[0]
 	}
+	return cfg.Billing.Provider
 }
 
 // NewStripeProvider creates a Stripe billing provider
@@ -41,11 +126,16 @@ func NewStripeProvider(ctx context.Context, cfg *config.Config, logger *zap.Logg
 		log.Warn(ctx, "Stripe publishable key not configured - some features may not work")
 	}
 
-	provider := stripebp.NewAdapter(
-		cfg.Billing.StripeSecret,
-		cfg.Billing.StripePublishable,
-		logger,
-	)
+	if cfg.Billing.StripeWebhookSecret == "" {
+		log.Warn(ctx, "Stripe webhook secret not configured - webhook signatures will not be verified")
+	}
+
+	provider := stripebp.NewAdapter(stripebp.Config{
+		SecretKey:      cfg.Billing.StripeSecret,
+		PublishableKey: cfg.Billing.StripePublishable,
+		WebhookSecret:  cfg.Billing.StripeWebhookSecret,
+		BaseURL:        cfg.Billing.StripeBaseURL,
+	}, logger)
 
 	log.Info(ctx, "Stripe billing provider initialized successfully",
 		zap.String("publishable_key_prefix", getKeyPrefix(cfg.Billing.StripePublishable)))
@@ -53,12 +143,147 @@ func NewStripeProvider(ctx context.Context, cfg *config.Config, logger *zap.Logg
 	return provider, nil
 }
 
+// NewPayPalProvider creates a PayPal billing provider
+func NewPayPalProvider(ctx context.Context, cfg *config.Config, logger *zap.Logger) (billing.Provider, error) {
+	if cfg.Billing.PayPalClientID == "" || cfg.Billing.PayPalClientSecret == "" {
+		return nil, fmt.Errorf("paypal client ID and secret are required")
+	}
+	if cfg.Billing.PayPalWebhookID == "" || cfg.Billing.PayPalWebhookCert == "" {
+		return nil, fmt.Errorf("paypal webhook ID and certificate are required")
+	}
+
+	pemData, err := os.ReadFile(cfg.Billing.PayPalWebhookCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read paypal webhook certificate: %w", err)
+	}
+	cert, err := paypalbp.ParseCertificate(pemData)
+	if err != nil {
+		return nil, fmt.Errorf("invalid paypal webhook certificate: %w", err)
+	}
+
+	provider := paypalbp.NewAdapter(paypalbp.Config{
+		ClientID:     cfg.Billing.PayPalClientID,
+		ClientSecret: cfg.Billing.PayPalClientSecret,
+		BaseURL:      cfg.Billing.PayPalBaseURL,
+		WebhookID:    cfg.Billing.PayPalWebhookID,
+		WebhookCert:  cert,
+	}, logger)
+
+	log.Info(ctx, "PayPal billing provider initialized successfully",
+		zap.String("client_id_prefix", getKeyPrefix(cfg.Billing.PayPalClientID)),
+		zap.String("base_url", cfg.Billing.PayPalBaseURL),
+		zap.Time("webhook_cert_expires_at", cert.NotAfter))
+
+	return provider, nil
+}
+
+// NewAdyenProvider creates an Adyen billing provider
+func NewAdyenProvider(ctx context.Context, cfg *config.Config, logger *zap.Logger) (billing.Provider, error) {
+	if cfg.Billing.AdyenAPIKey == "" || cfg.Billing.AdyenMerchantAccount == "" {
+		return nil, fmt.Errorf("adyen API key and merchant account are required")
+	}
+	if cfg.Billing.AdyenHMACKey == "" {
+		return nil, fmt.Errorf("adyen webhook HMAC key is required")
+	}
+
+	provider, err := adyenbp.NewAdapter(adyenbp.Config{
+		APIKey:          cfg.Billing.AdyenAPIKey,
+		MerchantAccount: cfg.Billing.AdyenMerchantAccount,
+		BaseURL:         cfg.Billing.AdyenBaseURL,
+		HMACKey:         cfg.Billing.AdyenHMACKey,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "Adyen billing provider initialized successfully",
+		zap.String("merchant_account", cfg.Billing.AdyenMerchantAccount),
+		zap.String("base_url", cfg.Billing.AdyenBaseURL))
+
+	return provider, nil
+}
+
+// NewCheckoutExpiryConfig converts the billing configuration into checkout
+// expiry settings, keeping defaults for anything left unset
+func NewCheckoutExpiryConfig(cfg *config.Config) usecase.CheckoutExpiryConfig {
+	expiry := usecase.DefaultCheckoutExpiryConfig()
+	c := cfg.Billing.CheckoutExpiry
+
+	if c.IntervalSec > 0 {
+		expiry.Interval = time.Duration(c.IntervalSec) * time.Second
+	}
+	if c.BatchSize > 0 {
+		expiry.BatchSize = c.BatchSize
+	}
+	if c.DefaultWindowMinutes > 0 {
+		expiry.DefaultWindow = time.Duration(c.DefaultWindowMinutes) * time.Minute
+	}
+	for provider, minutes := range c.WindowMinutes {
+		if minutes > 0 {
+			expiry.ProviderWindows[provider] = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	return expiry
+}
+
+// NewReconciliationConfig converts the billing configuration into scheduled
+// reconciliation settings, keeping defaults for anything left unset
+func NewReconciliationConfig(cfg *config.Config) usecase.ReconciliationConfig {
+	reconciliation := usecase.DefaultReconciliationConfig()
+	c := cfg.Billing.Reconciliation
+
+	if c.IntervalMinutes > 0 {
+		reconciliation.Interval = time.Duration(c.IntervalMinutes) * time.Minute
+	}
+	if c.WindowHours > 0 {
+		reconciliation.Window = time.Duration(c.WindowHours) * time.Hour
+	}
+	if c.DelayMinutes > 0 {
+		reconciliation.Delay = time.Duration(c.DelayMinutes) * time.Minute
+	}
+	reconciliation.AutoHeal = c.AutoHeal
+
+	return reconciliation
+}
+
+// NewRevenueSnapshotConfig converts the billing configuration into daily
+// revenue snapshot settings, keeping defaults for anything left unset
+func NewRevenueSnapshotConfig(cfg *config.Config) usecase.RevenueSnapshotConfig {
+	snapshots := usecase.DefaultRevenueSnapshotConfig()
+	c := cfg.Billing.RevenueSnapshots
+
+	if c.IntervalMinutes > 0 {
+		snapshots.Interval = time.Duration(c.IntervalMinutes) * time.Minute
+	}
+	if c.BackfillDays > 0 {
+		snapshots.BackfillDays = c.BackfillDays
+	}
+
+	return snapshots
+}
+
+// NewInvoiceRenderer creates the invoice renderer from the seller configuration
+func NewInvoiceRenderer(cfg *config.Config) (*invoice.Renderer, error) {
+	seller := invoice.Seller{
+		Name:    cfg.Invoice.SellerName,
+		Address: cfg.Invoice.SellerAddress,
+		Email:   cfg.Invoice.SellerEmail,
+		TaxID:   cfg.Invoice.SellerTaxID,
+	}
+	if seller.Name == "" {
+		seller.Name = cfg.AppName
+	}
+
+	return invoice.NewRenderer(seller)
+}
+
 // NewMockProvider creates a mock billing provider for testing/development
 func NewMockProvider(ctx context.Context, logger *zap.Logger) (billing.Provider, error) {
 	log.Info(ctx, "Using mock billing provider for testing/development")
 
 	// Return a mock implementation that logs all operations
-	return &MockProvider{logger: logger}, nil
+	return &MockProvider{logger: logger, sessions: make(map[string]*mockSession)}, nil
 }
 
 // getKeyPrefix returns the first 8 characters of a key for logging (for security)
@@ -69,9 +294,36 @@ func getKeyPrefix(key string) string {
 	return key[:8] + "***"
 }
 
-// MockProvider is a mock implementation of billing.Provider for testing
+// MockWebhookSecret is the secret the mock provider's webhooks are signed
+// with. Signatures use Stripe's scheme, "t=<unix time>,v1=<hex HMAC-SHA256
+// of "<unix time>.<payload>">", and are accepted for mockWebhookTolerance.
+const MockWebhookSecret = "whsec_mock"
+
+// mockWebhookTolerance is how old a mock webhook signature may be
+const mockWebhookTolerance = 5 * time.Minute
+
+// MockProvider is a mock implementation of billing.Provider for testing.
+// Checkout sessions are kept in memory and paid with CompleteCheckout,
+// which returns the signed webhook a real provider would send.
 type MockProvider struct {
 	logger *zap.Logger
+
+	mu       sync.Mutex
+	sessions map[string]*mockSession
+}
+
+// mockSession is a checkout session and the request that opened it
+type mockSession struct {
+	session billing.Session
+	req     billing.CreateCheckoutSessionRequest
+}
+
+// mockEvent is the payload of a mock webhook
+type mockEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	SessionID string `json:"session_id,omitempty"`
+	Created   int64  `json:"created"`
 }
 
 // CreateCheckoutSession creates a mock checkout session
@@ -80,10 +332,34 @@ func (m *MockProvider) CreateCheckoutSession(ctx context.Context, req billing.Cr
 		zap.String("plan_id", req.PlanID.String()),
 		zap.String("user_id", req.UserID))
 
+	now := time.Now()
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(24 * time.Hour) // 24 hours from now
+	}
+
+	id := "mock_session_" + uuid.New().String()
+	url := "https://mock-checkout.example.com/session/" + id
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = &mockSession{
+		session: billing.Session{
+			ID:        id,
+			Status:    string(billing.SessionStatusOpen),
+			URL:       url,
+			ExpiresAt: expiresAt,
+			Metadata:  map[string]interface{}{"user_id": req.UserID, "plan_id": req.PlanID.String()},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		req: req,
+	}
+
 	return &billing.CreateCheckoutSessionResponse{
-		SessionID: "mock_session_" + req.PlanID.String()[:8],
-		URL:       "https://mock-checkout.example.com/session",
-		ExpiresAt: time.Now().Add(24 * time.Hour), // 24 hours from now
+		SessionID: id,
+		URL:       url,
+		ExpiresAt: expiresAt,
 	}, nil
 }
 
@@ -91,49 +367,248 @@ func (m *MockProvider) CreateCheckoutSession(ctx context.Context, req billing.Cr
 func (m *MockProvider) GetSession(ctx context.Context, sessionID string) (*billing.Session, error) {
 	m.logger.Info("Mock: Getting session", zap.String("session_id", sessionID))
 
-	now := time.Now()
-	return &billing.Session{
-		ID:        sessionID,
-		Status:    string(billing.SessionStatusOpen),
-		URL:       "https://mock-checkout.example.com/session",
-		ExpiresAt: now.Add(24 * time.Hour),
-		CreatedAt: now,
-		UpdatedAt: now,
-	}, nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session := s.session
+	return &session, nil
 }
 
-// CancelSession cancels a mock session
+// CancelSession cancels an open mock session
 func (m *MockProvider) CancelSession(ctx context.Context, sessionID string) error {
 	m.logger.Info("Mock: Cancelling session", zap.String("session_id", sessionID))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.open(sessionID)
+	if err != nil {
+		return err
+	}
+	s.close(billing.SessionStatusCancelled)
 	return nil
 }
 
-// ValidateWebhook validates a mock webhook
+// CompleteCheckout pays an open mock session, as a buyer would on a real
+// provider's checkout page, and returns the signed checkout.session.completed
+// webhook for it
+func (m *MockProvider) CompleteCheckout(sessionID string) (payload []byte, signature string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.open(sessionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	payload, err = json.Marshal(mockEvent{
+		ID:        "mock_evt_" + uuid.New().String(),
+		Type:      string(billing.WebhookEventTypeCheckoutSessionCompleted),
+		SessionID: sessionID,
+		Created:   time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode mock webhook: %w", err)
+	}
+	s.close(billing.SessionStatusComplete)
+	return payload, SignMockWebhook(payload, time.Now()), nil
+}
+
+// ExpireSession lets an open mock session run out its time
+func (m *MockProvider) ExpireSession(sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, err := m.open(sessionID)
+	if err != nil {
+		return err
+	}
+	s.close(billing.SessionStatusExpired)
+	s.session.ExpiresAt = s.session.UpdatedAt
+	return nil
+}
+
+// session returns a session, expiring it if its time has run out. The
+// caller must hold m.mu.
+func (m *MockProvider) session(sessionID string) (*mockSession, error) {
+	s, ok := m.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("mock checkout session %s not found", sessionID)
+	}
+	if s.session.Status == string(billing.SessionStatusOpen) && !time.Now().Before(s.session.ExpiresAt) {
+		s.close(billing.SessionStatusExpired)
+	}
+	return s, nil
+}
+
+// open returns a session that is still open. The caller must hold m.mu.
+func (m *MockProvider) open(sessionID string) (*mockSession, error) {
+	s, err := m.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if s.session.Status != string(billing.SessionStatusOpen) {
+		return nil, fmt.Errorf("mock checkout session %s is %s", sessionID, s.session.Status)
+	}
+	return s, nil
+}
+
+func (s *mockSession) close(status billing.SessionStatus) {
+	s.session.Status = string(status)
+	s.session.UpdatedAt = time.Now()
+}
+
+// SignMockWebhook returns the signature of a mock webhook sent at the given time
+func SignMockWebhook(payload []byte, at time.Time) string {
+	return signMockWebhook(MockWebhookSecret, payload, at)
+}
+
+func signMockWebhook(secret string, payload []byte, at time.Time) string {
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), mockSignature(secret, at.Unix(), payload))
+}
+
+func mockSignature(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhook validates a mock webhook signed with MockWebhookSecret
 func (m *MockProvider) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
 	m.logger.Info("Mock: Validating webhook", zap.String("signature", signature))
+
+	var timestamp int64
+	var provided string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			provided = value
+		}
+	}
+	if timestamp == 0 || provided == "" {
+		return fmt.Errorf("invalid webhook signature: missing timestamp or signature")
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > mockWebhookTolerance || age < -mockWebhookTolerance {
+		return fmt.Errorf("invalid webhook signature: timestamp outside the tolerance")
+	}
+	if !hmac.Equal([]byte(provided), []byte(mockSignature(MockWebhookSecret, timestamp, payload))) {
+		return fmt.Errorf("invalid webhook signature: signature mismatch")
+	}
 	return nil
 }
 
-// ParseWebhook parses a mock webhook
+// ParseWebhook parses a mock webhook. Only checkout.session.completed
+// events are sent; the checkout details come from the session they complete.
 func (m *MockProvider) ParseWebhook(ctx context.Context, payload []byte) (*billing.WebhookResult, error) {
 	m.logger.Info("Mock: Parsing webhook", zap.Int("payload_size", len(payload)))
 
+	var event mockEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	if event.Type != string(billing.WebhookEventTypeCheckoutSessionCompleted) {
+		return nil, fmt.Errorf("unhandled event type: %s", event.Type)
+	}
+
+	m.mu.Lock()
+	s, ok := m.sessions[event.SessionID]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("mock checkout session %s not found", event.SessionID)
+	}
+
 	return &billing.WebhookResult{
-		EventType:   string(billing.WebhookEventTypeCheckoutSessionCompleted),
-		SessionID:   "mock_session_123",
-		UserID:      "spiff_id_mock_user",
-		FeatureCode: "premium_feature",
-		PlanID:      uuid.New(), // Generate a new UUID for mock
-		Amount:      2999,
-		Currency:    "usd",
-		Status:      "succeeded",
-		ExpiresAt:   nil,
+		EventType:    event.Type,
+		SessionID:    event.SessionID,
+		UserID:       s.req.UserID,
+		FamilyID:     s.req.FamilyID,
+		PlanID:       s.req.PlanID,
+		PlanIDString: s.req.PlanID.String(),
+		Amount:       s.req.BasePrice,
+		Currency:     strings.ToUpper(s.req.Currency),
+		Status:       "completed",
 		Metadata: map[string]interface{}{
-			"mock": true,
+			"mock":     true,
+			"event_id": event.ID,
 		},
 	}, nil
 }
 
+// SaveCustomer saves a mock customer
+func (m *MockProvider) SaveCustomer(ctx context.Context, req billing.SaveCustomerRequest) (string, error) {
+	m.logger.Info("Mock: Saving customer",
+		zap.String("customer_id", req.CustomerID),
+		zap.String("user_id", req.UserID))
+
+	if req.CustomerID != "" {
+		return req.CustomerID, nil
+	}
+	return "mock_customer_" + uuid.New().String(), nil
+}
+
+// AttachPaymentMethod attaches a mock payment method, a Visa card ending in 4242
+func (m *MockProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*billing.PaymentMethodDetails, error) {
+	m.logger.Info("Mock: Attaching payment method", zap.String("customer_id", customerID))
+
+	return &billing.PaymentMethodDetails{
+		ID:       "mock_pm_" + uuid.New().String(),
+		Type:     "credit_card",
+		Brand:    "visa",
+		Last4:    "4242",
+		ExpMonth: 12,
+		ExpYear:  int32(time.Now().Year() + 3),
+	}, nil
+}
+
+// DetachPaymentMethod detaches a mock payment method
+func (m *MockProvider) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	m.logger.Info("Mock: Detaching payment method", zap.String("payment_method_id", paymentMethodID))
+	return nil
+}
+
+// SetDefaultPaymentMethod sets a mock customer's default payment method
+func (m *MockProvider) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	m.logger.Info("Mock: Setting default payment method",
+		zap.String("customer_id", customerID),
+		zap.String("payment_method_id", paymentMethodID))
+	return nil
+}
+
+// ChargePaymentMethod charges a mock payment method; charges always succeed
+func (m *MockProvider) ChargePaymentMethod(ctx context.Context, req billing.ChargeRequest) (*billing.ChargeResult, error) {
+	m.logger.Info("Mock: Charging payment method",
+		zap.String("customer_id", req.CustomerID),
+		zap.String("payment_method_id", req.PaymentMethodID),
+		zap.Float64("amount", req.Amount))
+
+	return &billing.ChargeResult{
+		ID:     "mock_charge_" + uuid.New().String(),
+		Status: billing.ChargeStatusSucceeded,
+	}, nil
+}
+
+// ListCharges lists mock charges; the mock provider keeps no history
+func (m *MockProvider) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	m.logger.Info("Mock: Listing charges",
+		zap.Time("created_after", req.CreatedAfter),
+		zap.Time("created_before", req.CreatedBefore))
+
+	return &billing.ChargePage{Charges: []billing.Charge{}}, nil
+}
+
+// ListSubscriptions lists mock subscriptions; the mock provider keeps no history
+func (m *MockProvider) ListSubscriptions(ctx context.Context, req billing.ListRequest) (*billing.SubscriptionPage, error) {
+	m.logger.Info("Mock: Listing subscriptions",
+		zap.Time("created_after", req.CreatedAfter),
+		zap.Time("created_before", req.CreatedBefore))
+
+	return &billing.SubscriptionPage{Subscriptions: []billing.Subscription{}}, nil
+}
+
 // Close closes the mock provider
 func (m *MockProvider) Close() error {
 	m.logger.Info("Mock: Closing provider")
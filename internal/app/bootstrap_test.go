@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/conformance"
+)
+
+// mockFixtures drives a MockProvider
+type mockFixtures struct {
+	provider *MockProvider
+}
+
+func (f mockFixtures) CompleteCheckout(sessionID string) (conformance.Webhook, error) {
+	payload, signature, err := f.provider.CompleteCheckout(sessionID)
+	return conformance.Webhook{Payload: payload, Signature: signature}, err
+}
+
+func (f mockFixtures) ExpireSession(sessionID string) error {
+	return f.provider.ExpireSession(sessionID)
+}
+
+func (f mockFixtures) UnknownEvent() (conformance.Webhook, error) {
+	payload, err := json.Marshal(mockEvent{ID: "mock_evt_1", Type: "customer.created", Created: time.Now().Unix()})
+	return conformance.Webhook{Payload: payload, Signature: SignMockWebhook(payload, time.Now())}, err
+}
+
+func (f mockFixtures) SignedAt(payload []byte, at time.Time) (conformance.Webhook, bool, error) {
+	return conformance.Webhook{Payload: payload, Signature: SignMockWebhook(payload, at)}, true, nil
+}
+
+func (f mockFixtures) Forge(payload []byte) (conformance.Webhook, error) {
+	return conformance.Webhook{Payload: payload, Signature: signMockWebhook("whsec_forged", payload, time.Now())}, nil
+}
+
+func TestMockProvider_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (billing.Provider, conformance.Fixtures) {
+		provider, err := NewMockProvider(context.Background(), zap.NewNop())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return provider, mockFixtures{provider: provider.(*MockProvider)}
+	})
+}
@@ -46,7 +46,11 @@ func NewGRPCServer(cfg *config.Config, dbPool *pgxpool.Pool, redisClient *redis.
 
 	// Create interceptors
 	authInterceptor := interceptors.NewAuthInterceptor()
+	for _, subject := range cfg.Auth.AdminSubjects {
+		authInterceptor.AddAdminSubject(subject)
+	}
 	loggingInterceptor := interceptors.NewLoggingInterceptor()
+	tracingInterceptor := interceptors.NewTracingInterceptor()
 
 	// Create rate limiting interceptor
 	rateLimitConfig := ratelimit.RateLimitConfigs.Moderate
@@ -75,6 +79,7 @@ func NewGRPCServer(cfg *config.Config, dbPool *pgxpool.Pool, redisClient *redis.
 	server := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			grpc_recovery.UnaryServerInterceptor(recoveryOpts...),
+			tracingInterceptor.Unary(),
 			grpc_zap.UnaryServerInterceptor(logger, zapOpts...),
 			metricsUnaryInterceptor,
 			rateLimitInterceptor,
@@ -83,6 +88,7 @@ func NewGRPCServer(cfg *config.Config, dbPool *pgxpool.Pool, redisClient *redis.
 		)),
 		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 			grpc_recovery.StreamServerInterceptor(recoveryOpts...),
+			tracingInterceptor.Stream(),
 			grpc_zap.StreamServerInterceptor(logger, zapOpts...),
 			metricsStreamInterceptor,
 			rateLimitStreamInterceptor,
@@ -207,7 +213,8 @@ func (s *GRPCServer) checkDependencies() {
 // checkDatabase checks if the database is reachable
 func (s *GRPCServer) checkDatabase(ctx context.Context) bool {
 	if s.dbPool == nil {
-		return false
+		// Memory storage mode runs without a database
+		return s.config.Storage.Mode == config.StorageModeMemory
 	}
 
 	if err := s.dbPool.Ping(ctx); err != nil {
@@ -21,6 +21,12 @@ type AuthInterceptor struct {
 	// Spiffe IDs allowed for service-to-service calls
 	allowedSpiffeIDs map[string]bool
 
+	// Methods only administrators may call
+	adminMethods map[string]bool
+
+	// User and spiffe IDs of administrators
+	adminSubjects map[string]bool
+
 	// Enable spiffe validation for service-to-service calls
 	enableSpiffeValidation bool
 }
@@ -38,6 +44,22 @@ func NewAuthInterceptor() *AuthInterceptor {
 			"spiffe://jia.app/family-service":   true,
 			"spiffe://jia.app/document-service": true,
 		},
+		adminMethods: map[string]bool{
+			"/payment.v1.PaymentService/GrantEntitlement":   true,
+			"/payment.v1.PaymentService/RevokeEntitlement":  true,
+			"/payment.v1.PaymentService/ExtendEntitlement":  true,
+			"/payment.v1.PaymentService/ExplainEntitlement": true,
+			"/payment.v1.PaymentService/CreatePlan":         true,
+			"/payment.v1.PaymentService/UpdatePlan":         true,
+			"/payment.v1.PaymentService/ArchivePlan":        true,
+			"/payment.v1.PaymentService/CreateFeature":      true,
+			"/payment.v1.PaymentService/UpdateFeature":      true,
+			"/payment.v1.PaymentService/ExportPayments":     true,
+			"/payment.v1.PaymentService/ExportEntitlements": true,
+			"/payment.v1.PaymentService/GetRevenueReport":   true,
+			"/payment.v1.PaymentService/ListAuditEvents":    true,
+		},
+		adminSubjects:          map[string]bool{},
 		enableSpiffeValidation: false, // Set to true when spiffe is configured
 	}
 }
@@ -77,6 +99,11 @@ func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
 		// Inject user_id into context and add to logs
 		ctx = log.WithUserID(ctx, userID)
 
+		ctx, err = i.authorize(ctx, info.FullMethod, userID)
+		if err != nil {
+			return nil, err
+		}
+
 		// Log successful authentication
 		log.Info(ctx, "Request authenticated",
 			zap.String("method", info.FullMethod),
@@ -108,6 +135,11 @@ func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
 		// Create new context with user_id
 		ctx := log.WithUserID(stream.Context(), userID)
 
+		ctx, err = i.authorize(ctx, info.FullMethod, userID)
+		if err != nil {
+			return err
+		}
+
 		// Log successful authentication
 		log.Info(ctx, "Stream authenticated",
 			zap.String("method", info.FullMethod),
@@ -169,6 +201,21 @@ func (i *AuthInterceptor) authenticate(ctx context.Context) (string, error) {
 	return userID, nil
 }
 
+// authorize marks administrators' contexts and turns everyone else away
+// from admin methods
+func (i *AuthInterceptor) authorize(ctx context.Context, method, userID string) (context.Context, error) {
+	if i.adminSubjects[userID] {
+		return auth.WithAdmin(ctx), nil
+	}
+	if i.adminMethods[method] {
+		log.Warn(ctx, "Admin method called without admin rights",
+			zap.String("method", method),
+			zap.String("user_id", userID))
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires admin rights", method)
+	}
+	return ctx, nil
+}
+
 // authenticatedServerStream wraps grpc.ServerStream to provide authenticated context
 type authenticatedServerStream struct {
 	grpc.ServerStream
@@ -200,6 +247,21 @@ func (i *AuthInterceptor) RemoveAllowedSpiffeID(spiffeID string) {
 	delete(i.allowedSpiffeIDs, spiffeID)
 }
 
+// AddAdminMethod restricts a method to administrators
+func (i *AuthInterceptor) AddAdminMethod(method string) {
+	i.adminMethods[method] = true
+}
+
+// AddAdminSubject grants a user or spiffe ID admin rights
+func (i *AuthInterceptor) AddAdminSubject(subject string) {
+	i.adminSubjects[subject] = true
+}
+
+// RemoveAdminSubject revokes a user or spiffe ID's admin rights
+func (i *AuthInterceptor) RemoveAdminSubject(subject string) {
+	delete(i.adminSubjects, subject)
+}
+
 // EnableSpiffeValidation enables spiffe ID validation
 func (i *AuthInterceptor) EnableSpiffeValidation() {
 	i.enableSpiffeValidation = true
@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/jia-app/paymentservice/internal/shared/auth"
 	"github.com/jia-app/paymentservice/internal/shared/log"
 )
 
@@ -152,3 +153,137 @@ func TestAuthInterceptor(t *testing.T) {
 		})
 	}
 }
+
+// Test that admin methods are reserved for configured admin subjects
+func TestAuthInterceptor_AdminMethods(t *testing.T) {
+	_ = log.Init("info")
+
+	authInterceptor := NewAuthInterceptor()
+	authInterceptor.AddAdminSubject("spiff_id_support")
+
+	var sawAdmin bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		sawAdmin = auth.IsAdmin(ctx)
+		return "success", nil
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		token        string
+		expectedCode codes.Code
+		expectAdmin  bool
+	}{
+		{
+			name:         "user token is denied an admin method",
+			method:       "/payment.v1.PaymentService/GrantEntitlement",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token is denied revoking",
+			method:       "/payment.v1.PaymentService/RevokeEntitlement",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token is denied explaining entitlement checks",
+			method:       "/payment.v1.PaymentService/ExplainEntitlement",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "admin token explains entitlement checks",
+			method:       "/payment.v1.PaymentService/ExplainEntitlement",
+			token:        "spiff_id_support",
+			expectedCode: codes.OK,
+			expectAdmin:  true,
+		},
+		{
+			name:         "admin token calls an admin method",
+			method:       "/payment.v1.PaymentService/ExtendEntitlement",
+			token:        "spiff_id_support",
+			expectedCode: codes.OK,
+			expectAdmin:  true,
+		},
+		{
+			name:         "user token is denied changing the plan catalog",
+			method:       "/payment.v1.PaymentService/CreatePlan",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token is denied archiving a plan",
+			method:       "/payment.v1.PaymentService/ArchivePlan",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "admin token updates a plan",
+			method:       "/payment.v1.PaymentService/UpdatePlan",
+			token:        "spiff_id_support",
+			expectedCode: codes.OK,
+			expectAdmin:  true,
+		},
+		{
+			name:         "user token is denied changing the feature catalog",
+			method:       "/payment.v1.PaymentService/UpdateFeature",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token is denied exporting payments",
+			method:       "/payment.v1.PaymentService/ExportPayments",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "admin token exports entitlements",
+			method:       "/payment.v1.PaymentService/ExportEntitlements",
+			token:        "spiff_id_support",
+			expectedCode: codes.OK,
+			expectAdmin:  true,
+		},
+		{
+			name:         "user token is denied the revenue report",
+			method:       "/payment.v1.PaymentService/GetRevenueReport",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token is denied the audit log",
+			method:       "/payment.v1.PaymentService/ListAuditEvents",
+			token:        "spiff_id_12345",
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "user token reads the plan catalog",
+			method:       "/payment.v1.PaymentService/ListPlans",
+			token:        "spiff_id_12345",
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "user token calls other methods",
+			method:       "/payment.v1.PaymentService/CheckEntitlement",
+			token:        "spiff_id_12345",
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sawAdmin = false
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.New(map[string]string{
+				"better-auth-token": tt.token,
+			}))
+
+			_, err := authInterceptor.Unary()(ctx, "test_request", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.expectedCode {
+				t.Fatalf("Expected %s, got: %v", tt.expectedCode, err)
+			}
+			if sawAdmin != tt.expectAdmin {
+				t.Errorf("Expected admin context %v, got %v", tt.expectAdmin, sawAdmin)
+			}
+		})
+	}
+}
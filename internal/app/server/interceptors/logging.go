@@ -8,8 +8,10 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/shared/log"
 )
 
@@ -31,9 +33,7 @@ func (i *LoggingInterceptor) Unary() grpc.UnaryServerInterceptor {
 	) (interface{}, error) {
 		start := time.Now()
 
-		// Generate request ID
-		requestID := uuid.New().String()
-		ctx = log.WithRequestID(ctx, requestID)
+		ctx = withRequestContext(ctx)
 
 		// Extract user_id from metadata if available
 		// TODO: Extract from JWT token or metadata
@@ -79,9 +79,7 @@ func (i *LoggingInterceptor) Stream() grpc.StreamServerInterceptor {
 	) error {
 		start := time.Now()
 
-		// Generate request ID
-		requestID := uuid.New().String()
-		ctx := log.WithRequestID(stream.Context(), requestID)
+		ctx := withRequestContext(stream.Context())
 
 		// Wrap the stream with our context
 		wrappedStream := &wrappedServerStream{
@@ -119,6 +117,26 @@ func (i *LoggingInterceptor) Stream() grpc.StreamServerInterceptor {
 	}
 }
 
+// withRequestContext adds the request ID and the caller's reason for any
+// change the request makes to the context. Callers may send their own
+// request ID in x-request-id to correlate audit events with their logs;
+// otherwise one is generated.
+func withRequestContext(ctx context.Context) context.Context {
+	requestID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get("x-request-id"); len(ids) > 0 {
+			requestID = ids[0]
+		}
+		if reasons := md.Get("x-audit-reason"); len(reasons) > 0 {
+			ctx = audit.WithReason(ctx, reasons[0])
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	return log.WithRequestID(ctx, requestID)
+}
+
 // wrappedServerStream wraps grpc.ServerStream to provide a custom context
 type wrappedServerStream struct {
 	grpc.ServerStream
@@ -0,0 +1,43 @@
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+func TestLoggingInterceptor_RequestContext(t *testing.T) {
+	_ = log.Init("info")
+	info := &grpc.UnaryServerInfo{FullMethod: "/payment.v1.PaymentService/UpdatePlan"}
+
+	var requestID, reason string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		requestID, _ = ctx.Value(log.RequestIDKey).(string)
+		reason = audit.ReasonFromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "req-42",
+		"x-audit-reason", "price correction",
+	))
+	if _, err := NewLoggingInterceptor().Unary()(ctx, nil, info, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requestID != "req-42" || reason != "price correction" {
+		t.Errorf("expected the caller's request ID and reason, got %q and %q", requestID, reason)
+	}
+
+	// Without metadata a request ID is generated
+	if _, err := NewLoggingInterceptor().Unary()(context.Background(), nil, info, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requestID == "" || requestID == "req-42" || reason != "" {
+		t.Errorf("expected a generated request ID and no reason, got %q and %q", requestID, reason)
+	}
+}
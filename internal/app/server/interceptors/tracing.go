@@ -0,0 +1,57 @@
+package interceptors
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/jia-app/paymentservice/internal/shared/tracing"
+)
+
+// TracingInterceptor starts a server span for every RPC, continuing any
+// W3C trace context sent by the caller
+type TracingInterceptor struct{}
+
+// NewTracingInterceptor creates a new tracing interceptor
+func NewTracingInterceptor() *TracingInterceptor {
+	return &TracingInterceptor{}
+}
+
+// Unary returns a unary interceptor for request tracing
+func (i *TracingInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, span := tracing.Start(tracing.ExtractIncoming(ctx), tracing.SpanName(info.FullMethod),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(tracing.RPCAttributes(info.FullMethod)...),
+		)
+
+		resp, err := handler(ctx, req)
+		tracing.EndRPC(span, err)
+		return resp, err
+	}
+}
+
+// Stream returns a stream interceptor for request tracing
+func (i *TracingInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, span := tracing.Start(tracing.ExtractIncoming(stream.Context()), tracing.SpanName(info.FullMethod),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(tracing.RPCAttributes(info.FullMethod)...),
+		)
+
+		err := handler(srv, &wrappedServerStream{ServerStream: stream, ctx: ctx})
+		tracing.EndRPC(span, err)
+		return err
+	}
+}
@@ -0,0 +1,351 @@
+// Package adyenbp implements billing.Provider for Adyen using the Checkout
+// API's payment links, stored payment methods and payments, and verifies
+// standard notification webhooks with their HMAC signatures.
+package adyenbp
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// TestBaseURL is the Adyen test Checkout API, used when no base URL is configured
+const TestBaseURL = "https://checkout-test.adyen.com/v71"
+
+// Payment link lifetimes
+const (
+	defaultSessionLifetime = 24 * time.Hour      // Adyen's default when no expiry is set
+	maxSessionLifetime     = 70 * 24 * time.Hour // Longest expiry Adyen accepts
+)
+
+// Config holds the Adyen adapter settings
+type Config struct {
+	APIKey          string
+	MerchantAccount string
+	BaseURL         string       // Checkout API base URL including the version; TestBaseURL when empty
+	HMACKey         string       // Hex HMAC key of the notification webhook
+	HTTPClient      *http.Client // Defaults to a client with a 30 second timeout
+}
+
+// Adapter implements the billing.Provider interface for Adyen
+type Adapter struct {
+	client          *client
+	merchantAccount string
+	hmacKey         []byte
+	logger          *zap.Logger
+	circuitBreaker  *circuitbreaker.CircuitBreaker
+	now             func() time.Time
+}
+
+// NewAdapter creates a new Adyen billing adapter
+func NewAdapter(cfg Config, logger *zap.Logger) (*Adapter, error) {
+	hmacKey, err := hex.DecodeString(cfg.HMACKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Adyen HMAC key: %w", err)
+	}
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = TestBaseURL
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Adapter{
+		client: &client{
+			baseURL:    baseURL,
+			apiKey:     cfg.APIKey,
+			httpClient: httpClient,
+		},
+		merchantAccount: cfg.MerchantAccount,
+		hmacKey:         hmacKey,
+		logger:          logger,
+		circuitBreaker:  circuitbreaker.GetOrCreateGlobal("adyen", circuitbreaker.ExternalAPIConfig),
+		now:             time.Now,
+	}, nil
+}
+
+// CreateCheckoutSession creates a payment link, Adyen's hosted payment page
+// offering the payment methods enabled for the shopper's country. The
+// session ID is the payment link ID. Checkouts for a customer ask the
+// shopper for consent to save their payment method for later charges.
+func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateCheckoutSessionRequest) (*billing.CreateCheckoutSessionResponse, error) {
+	var result *billing.CreateCheckoutSessionResponse
+
+	link := paymentLink{
+		Reference:       uuid.NewString(),
+		Amount:          newAmount(req.BasePrice, req.Currency),
+		MerchantAccount: a.merchantAccount,
+		Description:     fmt.Sprintf("Plan ID: %s", req.PlanID.String()),
+		CountryCode:     strings.ToUpper(req.CountryCode),
+		ReturnURL:       req.SuccessURL,
+		ExpiresAt:       sessionExpiry(req.ExpiresAt, a.now()).UTC().Format(time.RFC3339),
+		Metadata: newMetadata(req.Metadata,
+			metadataUserID, req.UserID,
+			metadataPlanID, req.PlanID.String(),
+			metadataFamilyID, getStringValue(req.FamilyID),
+		),
+	}
+	if req.CustomerID != "" {
+		link.ShopperReference = req.CustomerID
+		link.StorePaymentMethodMode = "askForConsent"
+		link.RecurringProcessingModel = "Subscription"
+	}
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		var resp paymentLink
+		if err := a.client.do(ctx, http.MethodPost, "/paymentLinks", link, "", &resp); err != nil {
+			a.logger.Error("Failed to create Adyen payment link",
+				zap.Error(err),
+				zap.String("plan_id", req.PlanID.String()),
+				zap.String("user_id", req.UserID))
+			return nil, fmt.Errorf("failed to create checkout session: %w", err)
+		}
+
+		a.logger.Info("Created Adyen payment link",
+			zap.String("session_id", resp.ID),
+			zap.String("reference", resp.Reference),
+			zap.String("plan_id", req.PlanID.String()),
+			zap.String("user_id", req.UserID),
+			zap.String("checkout_url", resp.URL))
+
+		result = &billing.CreateCheckoutSessionResponse{
+			SessionID: resp.ID,
+			URL:       resp.URL,
+			ExpiresAt: parseTime(resp.ExpiresAt),
+		}
+		return result, nil
+	})
+
+	return result, err
+}
+
+// GetSession retrieves a payment link as a checkout session
+func (a *Adapter) GetSession(ctx context.Context, sessionID string) (*billing.Session, error) {
+	var result *billing.Session
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		link, err := a.getLink(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve checkout session: %w", err)
+		}
+		result = convertLinkSession(link)
+		return result, nil
+	})
+
+	return result, err
+}
+
+// CancelSession cancels a checkout by expiring its payment link. Adyen has
+// no cancelled status, so the session reports as expired afterwards.
+func (a *Adapter) CancelSession(ctx context.Context, sessionID string) error {
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		link, err := a.getLink(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+		}
+		if link.Status != "active" {
+			return nil, fmt.Errorf("checkout %s cannot be cancelled: it is %s", sessionID, link.Status)
+		}
+
+		patch := map[string]string{"status": "expired"}
+		if err := a.client.do(ctx, http.MethodPatch, "/paymentLinks/"+pathEscape(sessionID), patch, "", nil); err != nil {
+			a.logger.Error("Failed to expire Adyen payment link", zap.Error(err), zap.String("session_id", sessionID))
+			return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+		}
+
+		a.logger.Info("Cancelled Adyen payment link", zap.String("session_id", sessionID))
+		return nil, nil
+	})
+
+	return err
+}
+
+// SaveCustomer returns the shopper reference to store payment methods
+// under. Adyen keeps no customer records of its own; a shopper is a
+// reference chosen by the merchant, so a new one is generated for new
+// customers and existing references are returned unchanged.
+func (a *Adapter) SaveCustomer(ctx context.Context, req billing.SaveCustomerRequest) (string, error) {
+	if req.CustomerID != "" {
+		return req.CustomerID, nil
+	}
+	return uuid.NewString(), nil
+}
+
+// AttachPaymentMethod looks up a payment method Adyen stored for the shopper
+// during a payment, such as a checkout they consented to saving it in. The
+// token is the stored payment method ID.
+func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerID, token string) (*billing.PaymentMethodDetails, error) {
+	var result *billing.PaymentMethodDetails
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		stored, err := a.getStoredPaymentMethod(ctx, customerID, token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach payment method: %w", err)
+		}
+		result = convertStoredPaymentMethod(customerID, stored)
+		return result, nil
+	})
+
+	return result, err
+}
+
+// DetachPaymentMethod deletes a stored payment method
+func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	shopperReference, storedID, err := splitPaymentMethodID(paymentMethodID)
+	if err != nil {
+		return fmt.Errorf("failed to detach payment method: %w", err)
+	}
+
+	_, err = a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		query := url.Values{"merchantAccount": {a.merchantAccount}, "shopperReference": {shopperReference}}
+		path := "/storedPaymentMethods/" + pathEscape(storedID) + "?" + query.Encode()
+		if err := a.client.do(ctx, http.MethodDelete, path, nil, "", nil); err != nil {
+			a.logger.Error("Failed to delete Adyen stored payment method",
+				zap.Error(err),
+				zap.String("payment_method_id", paymentMethodID))
+			return nil, fmt.Errorf("failed to detach payment method: %w", err)
+		}
+		return nil, nil
+	})
+
+	return err
+}
+
+// SetDefaultPaymentMethod is a no-op: Adyen has no default payment method,
+// and charges always name the stored payment method to use
+func (a *Adapter) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	return nil
+}
+
+// ChargePaymentMethod charges a stored payment method as a subscription
+// payment without the shopper present. Refusals are reported as a failed
+// result rather than an error so that they don't trip the circuit breaker.
+func (a *Adapter) ChargePaymentMethod(ctx context.Context, req billing.ChargeRequest) (*billing.ChargeResult, error) {
+	shopperReference, storedID, err := splitPaymentMethodID(req.PaymentMethodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to charge payment method: %w", err)
+	}
+	reference := req.IdempotencyKey
+	if reference == "" {
+		reference = uuid.NewString()
+	}
+
+	var result *billing.ChargeResult
+	_, err = a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		// Payments name a stored payment method together with its type
+		stored, err := a.getStoredPaymentMethod(ctx, shopperReference, storedID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to charge payment method: %w", err)
+		}
+
+		payment := paymentRequest{
+			Amount:                   newAmount(req.Amount, req.Currency),
+			Reference:                reference,
+			MerchantAccount:          a.merchantAccount,
+			ShopperReference:         shopperReference,
+			ShopperInteraction:       "ContAuth",
+			RecurringProcessingModel: "Subscription",
+			ShopperStatement:         req.Description,
+			Metadata:                 newMetadata(req.Metadata),
+		}
+		payment.PaymentMethod.Type = stored.Type
+		payment.PaymentMethod.StoredPaymentMethodID = storedID
+
+		var resp paymentResponse
+		if err := a.client.do(ctx, http.MethodPost, "/payments", payment, req.IdempotencyKey, &resp); err != nil {
+			a.logger.Error("Failed to charge Adyen stored payment method",
+				zap.Error(err),
+				zap.String("customer_id", req.CustomerID),
+				zap.String("payment_method_id", req.PaymentMethodID))
+			return nil, fmt.Errorf("failed to charge payment method: %w", err)
+		}
+
+		result = &billing.ChargeResult{ID: resp.PSPReference, Status: resultChargeStatus(resp.ResultCode)}
+		if result.Status == billing.ChargeStatusFailed {
+			result.FailureReason = resp.RefusalReason
+			if result.FailureReason == "" {
+				result.FailureReason = resp.ResultCode
+			}
+		}
+		return result, nil
+	})
+
+	return result, err
+}
+
+// ListCharges is not supported: Adyen has no API to list payments. They are
+// reported through notifications and settlement reports instead.
+func (a *Adapter) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	return nil, fmt.Errorf("failed to list charges: Adyen has no payment listing API: %w", billing.ErrNotSupported)
+}
+
+// ListSubscriptions returns no subscriptions: Adyen keeps none, since
+// renewals are charged by the service with ChargePaymentMethod
+func (a *Adapter) ListSubscriptions(ctx context.Context, req billing.ListRequest) (*billing.SubscriptionPage, error) {
+	return &billing.SubscriptionPage{Subscriptions: []billing.Subscription{}}, nil
+}
+
+// Close closes the Adyen adapter
+func (a *Adapter) Close() error {
+	a.client.httpClient.CloseIdleConnections()
+	a.logger.Info("Adyen adapter closed")
+	return nil
+}
+
+// getLink retrieves a payment link
+func (a *Adapter) getLink(ctx context.Context, id string) (*paymentLink, error) {
+	var link paymentLink
+	if err := a.client.do(ctx, http.MethodGet, "/paymentLinks/"+pathEscape(id), nil, "", &link); err != nil {
+		a.logger.Error("Failed to retrieve Adyen payment link", zap.Error(err), zap.String("session_id", id))
+		return nil, err
+	}
+	return &link, nil
+}
+
+// getStoredPaymentMethod finds one of a shopper's stored payment methods
+func (a *Adapter) getStoredPaymentMethod(ctx context.Context, shopperReference, storedID string) (*storedPaymentMethod, error) {
+	query := url.Values{"merchantAccount": {a.merchantAccount}, "shopperReference": {shopperReference}}
+	var resp storedPaymentMethods
+	if err := a.client.do(ctx, http.MethodGet, "/storedPaymentMethods?"+query.Encode(), nil, "", &resp); err != nil {
+		a.logger.Error("Failed to list Adyen stored payment methods", zap.Error(err), zap.String("customer_id", shopperReference))
+		return nil, err
+	}
+	for i := range resp.StoredPaymentMethods {
+		if resp.StoredPaymentMethods[i].ID == storedID {
+			return &resp.StoredPaymentMethods[i], nil
+		}
+	}
+	return nil, fmt.Errorf("payment method %s is not stored for customer %s", storedID, shopperReference)
+}
+
+// sessionExpiry caps a requested expiry at the longest Adyen accepts,
+// defaulting to Adyen's default lifetime when none is requested
+func sessionExpiry(requested, now time.Time) time.Time {
+	if requested.IsZero() {
+		return now.Add(defaultSessionLifetime)
+	}
+	if latest := now.Add(maxSessionLifetime); requested.After(latest) {
+		return latest
+	}
+	return requested
+}
+
+// Helper function to safely get string value from pointer
+func getStringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
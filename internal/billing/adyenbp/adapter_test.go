@@ -0,0 +1,197 @@
+package adyenbp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+func TestAdapter_CheckoutSession(t *testing.T) {
+	fake, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+	familyID := "family-1"
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{
+		PlanID:      uuid.New(),
+		UserID:      "user-1",
+		FamilyID:    &familyID,
+		SuccessURL:  "https://jia.app/success",
+		CountryCode: "nl",
+		BasePrice:   9.99,
+		Currency:    "eur",
+		CustomerID:  "shopper-1",
+		ExpiresAt:   time.Now().Add(365 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.URL != "https://test.adyen.link/"+resp.SessionID {
+		t.Errorf("expected the payment link URL, got %q", resp.URL)
+	}
+	if time.Until(resp.ExpiresAt) > maxSessionLifetime {
+		t.Errorf("expected the expiry to be capped at Adyen's %s, got %v", maxSessionLifetime, resp.ExpiresAt)
+	}
+	link := fake.links[resp.SessionID]
+	if link.Amount != (amount{Currency: "EUR", Value: 999}) || link.CountryCode != "NL" {
+		t.Errorf("unexpected payment link amount or country %+v", link)
+	}
+	if link.ShopperReference != "shopper-1" || link.StorePaymentMethodMode != "askForConsent" {
+		t.Errorf("expected the shopper to be asked to store their payment method, got %+v", link)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusOpen) || session.Metadata["user_id"] != "user-1" || session.Metadata["family_id"] != "family-1" {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	if err := adapter.CancelSession(ctx, resp.SessionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session, _ := adapter.GetSession(ctx, resp.SessionID); session == nil || session.Status != string(billing.SessionStatusExpired) {
+		t.Errorf("expected the cancelled payment link to have expired, got %+v", session)
+	}
+	if err := adapter.CancelSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected an error cancelling an expired payment link")
+	}
+	if _, err := adapter.GetSession(ctx, "PL-missing"); err == nil {
+		t.Error("expected an error for an unknown payment link")
+	}
+}
+
+func TestAdapter_StoredPaymentMethods(t *testing.T) {
+	fake, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+
+	customerID, err := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id, _ := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{CustomerID: customerID}); id != customerID {
+		t.Errorf("expected existing customers to keep their reference, got %q", id)
+	}
+
+	fake.store(customerID, storedPaymentMethod{ID: "M5N7TQ4TG5PFWR50", Type: "scheme", Brand: "visa", LastFour: "1111", ExpiryMonth: "03", ExpiryYear: "2030"})
+	fake.store(customerID, storedPaymentMethod{ID: "K2QDVZ6V8RW2ZX82", Type: "sepadirectdebit", LastFour: "3000"})
+
+	pm, err := adapter.AttachPaymentMethod(ctx, customerID, "M5N7TQ4TG5PFWR50")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pm.ID != customerID+":M5N7TQ4TG5PFWR50" || pm.Type != "credit_card" || pm.Brand != "visa" || pm.Last4 != "1111" || pm.ExpMonth != 3 || pm.ExpYear != 2030 {
+		t.Errorf("unexpected payment method %+v", pm)
+	}
+	if sepa, _ := adapter.AttachPaymentMethod(ctx, customerID, "K2QDVZ6V8RW2ZX82"); sepa == nil || sepa.Type != "bank_transfer" {
+		t.Errorf("expected a bank transfer, got %+v", sepa)
+	}
+	if _, err := adapter.AttachPaymentMethod(ctx, "someone-else", "M5N7TQ4TG5PFWR50"); err == nil {
+		t.Error("expected an error attaching another shopper's payment method")
+	}
+
+	if err := adapter.DetachPaymentMethod(ctx, pm.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fake.stored[customerID]) != 1 {
+		t.Errorf("expected the stored payment method to be deleted, got %+v", fake.stored[customerID])
+	}
+	if err := adapter.DetachPaymentMethod(ctx, "M5N7TQ4TG5PFWR50"); err == nil {
+		t.Error("expected an error for a payment method ID without a shopper reference")
+	}
+}
+
+func TestAdapter_ChargePaymentMethod(t *testing.T) {
+	fake, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+	fake.store("shopper-1", storedPaymentMethod{ID: "K2QDVZ6V8RW2ZX82", Type: "sepadirectdebit"})
+
+	req := billing.ChargeRequest{
+		CustomerID:      "shopper-1",
+		PaymentMethodID: paymentMethodID("shopper-1", "K2QDVZ6V8RW2ZX82"),
+		Amount:          3.25,
+		Currency:        "KWD",
+		IdempotencyKey:  "renewal-1",
+		Metadata:        map[string]string{"user_id": "user-1", "plan_id": "premium"},
+	}
+	charge, err := adapter.ChargePaymentMethod(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charge.Status != billing.ChargeStatusSucceeded || charge.ID == "" {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	payment := fake.payments[charge.ID]
+	if payment.Amount.Value != 3250 || payment.PaymentMethod.Type != "sepadirectdebit" || payment.Reference != "renewal-1" || payment.Metadata["user_id"] != "user-1" {
+		t.Errorf("unexpected payment %+v", payment)
+	}
+	if again, _ := adapter.ChargePaymentMethod(ctx, req); again == nil || again.ID != charge.ID {
+		t.Errorf("expected a retry with the same idempotency key to return the first charge, got %+v", again)
+	}
+
+	fake.refuse = "Not enough balance"
+	req.IdempotencyKey = "renewal-2"
+	refused, err := adapter.ChargePaymentMethod(ctx, req)
+	if err != nil {
+		t.Fatalf("expected a refusal to be a result, got %v", err)
+	}
+	if refused.Status != billing.ChargeStatusFailed || refused.FailureReason != "Not enough balance" {
+		t.Errorf("unexpected refused charge %+v", refused)
+	}
+}
+
+func TestAdapter_List(t *testing.T) {
+	_, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+
+	if _, err := adapter.ListCharges(ctx, billing.ListRequest{}); !errors.Is(err, billing.ErrNotSupported) {
+		t.Errorf("expected listing charges to be unsupported, got %v", err)
+	}
+	subs, err := adapter.ListSubscriptions(ctx, billing.ListRequest{})
+	if err != nil || len(subs.Subscriptions) != 0 || subs.NextCursor != "" {
+		t.Errorf("expected no subscriptions, got %+v, %v", subs, err)
+	}
+}
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		major    float64
+		currency string
+		want     int64
+	}{
+		{9.99, "usd", 999},
+		{1200, "JPY", 1200},
+		{3.25, "KWD", 3250},
+		{0.1 + 0.2, "EUR", 30},
+	}
+	for _, tt := range tests {
+		a := newAmount(tt.major, tt.currency)
+		if a.Value != tt.want {
+			t.Errorf("%v %s: expected %d minor units, got %d", tt.major, tt.currency, tt.want, a.Value)
+		}
+		if back := a.major(); back-tt.major > 1e-9 || tt.major-back > 1e-9 {
+			t.Errorf("%v %s: expected the amount back, got %v", tt.major, tt.currency, back)
+		}
+	}
+}
+
+func TestNewMetadata(t *testing.T) {
+	metadata := newMetadata(map[string]string{
+		"plan_code":                "premium",
+		"user_id":                  "someone-else",
+		"a_key_longer_than_twenty": "x",
+		"description":              string(make([]byte, maxMetadataValueLength+1)),
+	}, metadataUserID, "user-1", metadataFamilyID, "")
+
+	if metadata["user_id"] != "user-1" || metadata["plan_code"] != "premium" {
+		t.Errorf("expected the checkout pairs to win over extra entries, got %v", metadata)
+	}
+	if len(metadata) != 2 {
+		t.Errorf("expected empty and oversized entries to be left out, got %v", metadata)
+	}
+}
@@ -0,0 +1,91 @@
+package adyenbp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// apiError is an error response from the Adyen Checkout API
+type apiError struct {
+	StatusCode   int    `json:"status"`
+	ErrorCode    string `json:"errorCode"`
+	Message      string `json:"message"`
+	ErrorType    string `json:"errorType"`
+	PSPReference string `json:"pspReference"`
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("adyen: %d %s: %s", e.StatusCode, e.ErrorCode, e.Message)
+	if e.PSPReference != "" {
+		msg += " [psp_reference " + e.PSPReference + "]"
+	}
+	return msg
+}
+
+// client calls the Adyen Checkout API with an API key
+type client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// do sends a JSON request and decodes the JSON response into out, which may
+// be nil. A non-empty idempotencyKey is sent as Idempotency-Key, which makes
+// retries of the same request return the original result.
+func (c *client) do(ctx context.Context, method, path string, body any, idempotencyKey string, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("adyen: %s %s: %w", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("adyen: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.ErrorCode = http.StatusText(resp.StatusCode)
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("adyen: failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package adyenbp
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/conformance"
+)
+
+// adyenFixtures drives the fake Adyen API behind the adapter under test and
+// builds the notifications Adyen would send for it
+type adyenFixtures struct {
+	fake *fakeAdyen
+	key  []byte
+}
+
+func (f adyenFixtures) CompleteCheckout(sessionID string) (conformance.Webhook, error) {
+	f.fake.mu.Lock()
+	defer f.fake.mu.Unlock()
+	link, ok := f.fake.links[sessionID]
+	if !ok {
+		return conformance.Webhook{}, fmt.Errorf("no such payment link: %s", sessionID)
+	}
+	if link.Status != "active" {
+		return conformance.Webhook{}, fmt.Errorf("payment link %s is %s", sessionID, link.Status)
+	}
+	link.Status = "completed"
+
+	item := notificationItem{
+		AdditionalData:      map[string]string{"paymentLinkId": link.ID},
+		Amount:              link.Amount,
+		EventCode:           "AUTHORISATION",
+		EventDate:           time.Now().UTC().Format(time.RFC3339),
+		MerchantAccountCode: link.MerchantAccount,
+		MerchantReference:   link.Reference,
+		PSPReference:        f.fake.id("PSP"),
+		PaymentMethod:       "visa",
+		Success:             "true",
+	}
+	for k, v := range link.Metadata {
+		item.AdditionalData["metadata."+k] = v
+	}
+	return notify(f.key, item)
+}
+
+func (f adyenFixtures) ExpireSession(sessionID string) error {
+	f.fake.mu.Lock()
+	defer f.fake.mu.Unlock()
+	link, ok := f.fake.links[sessionID]
+	if !ok {
+		return fmt.Errorf("no such payment link: %s", sessionID)
+	}
+	link.Status = "expired"
+	return nil
+}
+
+func (f adyenFixtures) UnknownEvent() (conformance.Webhook, error) {
+	return notify(f.key, notificationItem{
+		AdditionalData:      map[string]string{},
+		EventCode:           "REPORT_AVAILABLE",
+		EventDate:           time.Now().UTC().Format(time.RFC3339),
+		MerchantAccountCode: "JiaAppECOM",
+		PSPReference:        "settlement_detail_report_batch_1.csv",
+		Success:             "true",
+	})
+}
+
+// SignedAt reports that Adyen signatures carry no timestamp; the HMAC covers
+// the item fields only
+func (f adyenFixtures) SignedAt(payload []byte, at time.Time) (conformance.Webhook, bool, error) {
+	return conformance.Webhook{}, false, nil
+}
+
+func (f adyenFixtures) Forge(payload []byte) (conformance.Webhook, error) {
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return conformance.Webhook{}, err
+	}
+	forged := []byte("a key that is not the merchant's")
+	for i := range n.NotificationItems {
+		item := &n.NotificationItems[i].NotificationRequestItem
+		item.AdditionalData["hmacSignature"] = sign(forged, item)
+	}
+	payload, err := json.Marshal(n)
+	return conformance.Webhook{Payload: payload}, err
+}
+
+// notify signs an item and wraps it in a notification, as Adyen sends it
+func notify(key []byte, item notificationItem) (conformance.Webhook, error) {
+	item.AdditionalData["hmacSignature"] = sign(key, &item)
+	var n notification
+	n.Live = "false"
+	n.NotificationItems = append(n.NotificationItems, struct {
+		NotificationRequestItem notificationItem `json:"NotificationRequestItem"`
+	}{item})
+	payload, err := json.Marshal(n)
+	return conformance.Webhook{Payload: payload}, err
+}
+
+func TestAdapter_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (billing.Provider, conformance.Fixtures) {
+		fake, adapter := newFakeAdyen(t)
+		return adapter, adyenFixtures{fake: fake, key: adapter.hmacKey}
+	})
+}
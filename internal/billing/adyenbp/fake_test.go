@@ -0,0 +1,205 @@
+package adyenbp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// testHMACKey is the key the recorded notifications in testdata are signed with
+const testHMACKey = "44782DEF547AAA06C910C43932B1EB0C71FC68D9D0C057550C48EC2ACF6BA056"
+
+// fakeAdyen is an in-memory stand-in for the Adyen Checkout API, serving the
+// endpoints the adapter calls
+type fakeAdyen struct {
+	t *testing.T
+
+	mu        sync.Mutex
+	links     map[string]*paymentLink
+	stored    map[string][]storedPaymentMethod // Shopper reference to stored payment methods
+	payments  map[string]*paymentRequest       // PSP reference to payment
+	responses map[string]paymentResponse       // Idempotency-Key to the response it produced
+	nextID    int
+	refuse    string // Refusal reason of payments, if set
+}
+
+// newFakeAdyen starts a fake Adyen API and an adapter that talks to it
+func newFakeAdyen(t *testing.T) (*fakeAdyen, *Adapter) {
+	t.Helper()
+	f := &fakeAdyen{
+		t:         t,
+		links:     make(map[string]*paymentLink),
+		stored:    make(map[string][]storedPaymentMethod),
+		payments:  make(map[string]*paymentRequest),
+		responses: make(map[string]paymentResponse),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v71/paymentLinks", f.authed(f.createLink))
+	mux.HandleFunc("GET /v71/paymentLinks/{id}", f.authed(f.getLink))
+	mux.HandleFunc("PATCH /v71/paymentLinks/{id}", f.authed(f.patchLink))
+	mux.HandleFunc("GET /v71/storedPaymentMethods", f.authed(f.listStored))
+	mux.HandleFunc("DELETE /v71/storedPaymentMethods/{id}", f.authed(f.deleteStored))
+	mux.HandleFunc("POST /v71/payments", f.authed(f.createPayment))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	adapter, err := NewAdapter(Config{
+		APIKey:          "AQE-test",
+		MerchantAccount: "JiaAppECOM",
+		BaseURL:         server.URL + "/v71",
+		HMACKey:         testHMACKey,
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create adapter: %v", err)
+	}
+	// Keep failures in one test from opening the shared breaker for others
+	adapter.circuitBreaker = circuitbreaker.NewCircuitBreaker(circuitbreaker.ExternalAPIConfig)
+	return f, adapter
+}
+
+// authed rejects requests without the API key
+func (f *fakeAdyen) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "AQE-test" {
+			writeError(w, http.StatusUnauthorized, "000", "HTTP Status Response - Unauthorized")
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		next(w, r)
+	}
+}
+
+func (f *fakeAdyen) id(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s%04d", prefix, f.nextID)
+}
+
+func (f *fakeAdyen) createLink(w http.ResponseWriter, r *http.Request) {
+	var link paymentLink
+	f.decode(r, &link)
+	if link.MerchantAccount != "JiaAppECOM" || link.Amount.Currency == "" {
+		writeError(w, http.StatusUnprocessableEntity, "14_0", "Missing merchant account or amount")
+		return
+	}
+	link.ID = f.id("PL")
+	link.URL = "https://test.adyen.link/" + link.ID
+	link.Status = "active"
+	link.CreationDate = time.Now().UTC().Format(time.RFC3339)
+	f.links[link.ID] = &link
+	writeJSON(w, http.StatusCreated, link)
+}
+
+func (f *fakeAdyen) getLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := f.links[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "15_001", "Payment link not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, link)
+}
+
+func (f *fakeAdyen) patchLink(w http.ResponseWriter, r *http.Request) {
+	link, ok := f.links[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "15_001", "Payment link not found")
+		return
+	}
+	var patch struct {
+		Status string `json:"status"`
+	}
+	f.decode(r, &patch)
+	if patch.Status != "expired" {
+		writeError(w, http.StatusUnprocessableEntity, "15_002", "Only expiring a payment link is supported")
+		return
+	}
+	link.Status = patch.Status
+	writeJSON(w, http.StatusOK, link)
+}
+
+// store saves a payment method for a shopper, as a payment they consented to would
+func (f *fakeAdyen) store(shopperReference string, m storedPaymentMethod) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stored[shopperReference] = append(f.stored[shopperReference], m)
+}
+
+func (f *fakeAdyen) listStored(w http.ResponseWriter, r *http.Request) {
+	shopperReference := r.URL.Query().Get("shopperReference")
+	if r.URL.Query().Get("merchantAccount") != "JiaAppECOM" || shopperReference == "" {
+		writeError(w, http.StatusUnprocessableEntity, "14_0", "Missing merchant account or shopper reference")
+		return
+	}
+	writeJSON(w, http.StatusOK, storedPaymentMethods{ShopperReference: shopperReference, StoredPaymentMethods: f.stored[shopperReference]})
+}
+
+func (f *fakeAdyen) deleteStored(w http.ResponseWriter, r *http.Request) {
+	shopperReference := r.URL.Query().Get("shopperReference")
+	methods := f.stored[shopperReference]
+	for i, m := range methods {
+		if m.ID == r.PathValue("id") {
+			f.stored[shopperReference] = append(methods[:i], methods[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "000", "Stored payment method not found")
+}
+
+func (f *fakeAdyen) createPayment(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Idempotency-Key")
+	if resp, ok := f.responses[key]; ok && key != "" {
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
+	var payment paymentRequest
+	f.decode(r, &payment)
+	if payment.ShopperInteraction != "ContAuth" || payment.PaymentMethod.StoredPaymentMethodID == "" {
+		writeError(w, http.StatusUnprocessableEntity, "14_0", "Not a payment with a stored payment method")
+		return
+	}
+
+	resp := paymentResponse{PSPReference: f.id("PSP"), ResultCode: "Authorised", MerchantReference: payment.Reference}
+	if f.refuse != "" {
+		resp.ResultCode = "Refused"
+		resp.RefusalReason = f.refuse
+	}
+	f.payments[resp.PSPReference] = &payment
+	if key != "" {
+		f.responses[key] = resp
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (f *fakeAdyen) decode(r *http.Request, v any) {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		f.t.Errorf("fake Adyen: malformed %s %s body: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, errorCode, message string) {
+	writeJSON(w, status, map[string]any{
+		"status":       status,
+		"errorCode":    errorCode,
+		"message":      message,
+		"errorType":    "validation",
+		"pspReference": "ERR0001",
+	})
+}
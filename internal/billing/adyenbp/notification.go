@@ -0,0 +1,229 @@
+package adyenbp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// notification is an Adyen standard notification webhook
+type notification struct {
+	Live              string `json:"live"`
+	NotificationItems []struct {
+		NotificationRequestItem notificationItem `json:"NotificationRequestItem"`
+	} `json:"notificationItems"`
+}
+
+// notificationItem is one event of a notification
+type notificationItem struct {
+	AdditionalData      map[string]string `json:"additionalData"`
+	Amount              amount            `json:"amount"`
+	EventCode           string            `json:"eventCode"`
+	EventDate           string            `json:"eventDate"`
+	MerchantAccountCode string            `json:"merchantAccountCode"`
+	MerchantReference   string            `json:"merchantReference"`
+	OriginalReference   string            `json:"originalReference,omitempty"`
+	PSPReference        string            `json:"pspReference"`
+	PaymentMethod       string            `json:"paymentMethod,omitempty"`
+	Reason              string            `json:"reason,omitempty"`
+	Success             string            `json:"success"` // "true" or "false"
+}
+
+// items returns the notification's events
+func (n *notification) items() []notificationItem {
+	items := make([]notificationItem, 0, len(n.NotificationItems))
+	for _, wrapper := range n.NotificationItems {
+		items = append(items, wrapper.NotificationRequestItem)
+	}
+	return items
+}
+
+// signingString returns the fields of an item Adyen signs, joined with colons
+func (item *notificationItem) signingString() string {
+	return strings.Join([]string{
+		item.PSPReference,
+		item.OriginalReference,
+		item.MerchantAccountCode,
+		item.MerchantReference,
+		strconv.FormatInt(item.Amount.Value, 10),
+		item.Amount.Currency,
+		item.EventCode,
+		item.Success,
+	}, ":")
+}
+
+// sign returns the base64 HMAC-SHA256 signature of an item
+func sign(key []byte, item *notificationItem) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(item.signingString()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ValidateWebhook verifies the HMAC signature of every item in an Adyen
+// notification. Adyen signs each item inside the payload rather than the
+// request, in additionalData.hmacSignature, so the signature argument is
+// not used.
+func (a *Adapter) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
+	if len(a.hmacKey) == 0 {
+		return fmt.Errorf("webhook HMAC key must be configured")
+	}
+
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		return fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	items := n.items()
+	if len(items) == 0 {
+		return fmt.Errorf("notification has no items")
+	}
+
+	for i := range items {
+		item := &items[i]
+		provided, err := base64.StdEncoding.DecodeString(item.AdditionalData["hmacSignature"])
+		if err != nil || len(provided) == 0 {
+			return fmt.Errorf("missing or malformed HMAC signature for %s", item.PSPReference)
+		}
+		expected, _ := base64.StdEncoding.DecodeString(sign(a.hmacKey, item))
+		if !hmac.Equal(expected, provided) {
+			return fmt.Errorf("signature mismatch for %s", item.PSPReference)
+		}
+	}
+
+	a.logger.Debug("Validated Adyen notification signatures", zap.Int("items", len(items)))
+	return nil
+}
+
+// ParseWebhook parses an Adyen notification. Adyen batches one item per
+// notification unless configured otherwise; batches are rejected, since a
+// webhook result describes a single event.
+//
+// AUTHORISATION completes the checkout of its payment link, or reports a
+// renewal charged with ChargePaymentMethod as a succeeded payment. REFUND
+// and CHARGEBACK report the payment being reversed, referenced by their own
+// PSP reference; reversals of payments made without a payment link carry the
+// payment's merchant reference as their session.
+func (a *Adapter) ParseWebhook(ctx context.Context, payload []byte) (*billing.WebhookResult, error) {
+	var n notification
+	if err := json.Unmarshal(payload, &n); err != nil {
+		a.logger.Error("Failed to parse webhook payload", zap.Error(err))
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	items := n.items()
+	if len(items) != 1 {
+		return nil, fmt.Errorf("failed to parse webhook payload: expected one notification item, got %d", len(items))
+	}
+	item := items[0]
+
+	a.logger.Info("Processing webhook event",
+		zap.String("event_type", item.EventCode),
+		zap.String("psp_reference", item.PSPReference),
+		zap.String("success", item.Success))
+
+	result := newWebhookResult(&item)
+	succeeded := item.Success == "true"
+
+	switch item.EventCode {
+	case "AUTHORISATION":
+		switch {
+		case !succeeded:
+			result.EventType = string(billing.WebhookEventTypePaymentFailed)
+			result.Status = "failed"
+			result.Metadata["failure_reason"] = item.Reason
+		case result.SessionID == "":
+			// Not paid through a payment link: a stored payment method charged
+			// without the shopper present
+			result.EventType = string(billing.WebhookEventTypePaymentSucceeded)
+			result.SessionID = item.MerchantReference
+			result.Status = "completed"
+		default:
+			result.EventType = string(billing.WebhookEventTypeCheckoutSessionCompleted)
+			result.Status = "completed"
+		}
+	case "REFUND":
+		result.EventType = string(billing.WebhookEventTypePaymentRefunded)
+		result.Status = "refunded"
+		setReversal(result, &item)
+		if !succeeded {
+			result.Status = "failed"
+			result.Metadata["failure_reason"] = item.Reason
+		}
+	case "CHARGEBACK":
+		result.EventType = string(billing.WebhookEventTypePaymentChargeback)
+		result.Status = "chargeback"
+		result.Metadata["chargeback_reason"] = item.Reason
+		setReversal(result, &item)
+	default:
+		a.logger.Info("Unhandled webhook event type", zap.String("event_type", item.EventCode))
+		return nil, fmt.Errorf("unhandled event type: %s", item.EventCode)
+	}
+
+	return result, nil
+}
+
+// newWebhookResult starts a webhook result from a notification item. The
+// checkout metadata is echoed by Adyen in additionalData as metadata.<key>.
+func newWebhookResult(item *notificationItem) *billing.WebhookResult {
+	data := item.AdditionalData
+	planID := data["metadata."+metadataPlanID]
+	result := &billing.WebhookResult{
+		SessionID:    data["paymentLinkId"],
+		UserID:       data["metadata."+metadataUserID],
+		PlanIDString: planID,
+		PlanID:       planUUID(planID),
+		Amount:       item.Amount.major(),
+		Currency:     item.Amount.Currency,
+		Customer:     convertCustomerDetails(data),
+		Metadata: map[string]interface{}{
+			"adyen_psp_reference":      item.PSPReference,
+			"adyen_merchant_reference": item.MerchantReference,
+			"adyen_event_code":         item.EventCode,
+			"adyen_payment_method":     item.PaymentMethod,
+			"adyen_original_reference": item.OriginalReference,
+			"adyen_merchant_account":   item.MerchantAccountCode,
+			"adyen_event_date":         item.EventDate,
+		},
+	}
+	if familyID := data["metadata."+metadataFamilyID]; familyID != "" {
+		result.FamilyID = &familyID
+	}
+	return result
+}
+
+// setReversal references a refund or chargeback by its PSP reference and
+// falls back to the merchant reference for the session of payments made
+// without a payment link
+func setReversal(result *billing.WebhookResult, item *notificationItem) {
+	result.Reference = item.PSPReference
+	if result.SessionID == "" {
+		result.SessionID = item.MerchantReference
+	}
+}
+
+// convertCustomerDetails converts the shopper details Adyen includes in
+// additionalData when they are enabled for the webhook
+func convertCustomerDetails(data map[string]string) *billing.CustomerDetails {
+	customer := &billing.CustomerDetails{
+		Name:  data["shopperName"],
+		Email: data["shopperEmail"],
+		Address: billing.CustomerAddress{
+			Line1:      strings.TrimSpace(data["billingAddress.street"] + " " + data["billingAddress.houseNumberOrName"]),
+			City:       data["billingAddress.city"],
+			Region:     data["billingAddress.stateOrProvince"],
+			PostalCode: data["billingAddress.postalCode"],
+			Country:    data["billingAddress.country"],
+		},
+	}
+	if *customer == (billing.CustomerDetails{}) {
+		return nil
+	}
+	return customer
+}
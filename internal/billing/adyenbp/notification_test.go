@@ -0,0 +1,130 @@
+package adyenbp
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// fixture reads a recorded notification from testdata
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestAdapter_ValidateWebhook(t *testing.T) {
+	_, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"authorisation.json", "authorisation_refused.json", "authorisation_renewal.json", "refund.json", "chargeback.json", "report_available.json"} {
+		if err := adapter.ValidateWebhook(ctx, fixture(t, name), ""); err != nil {
+			t.Errorf("%s: expected a valid signature, got %v", name, err)
+		}
+	}
+
+	payload := fixture(t, "authorisation.json")
+	tests := map[string][]byte{
+		"tampered amount":   bytes.Replace(payload, []byte(`"value": 999`), []byte(`"value": 1`), 1),
+		"tampered success":  bytes.Replace(payload, []byte(`"success": "true"`), []byte(`"success": "false"`), 1),
+		"missing signature": bytes.Replace(payload, []byte(`"hmacSignature"`), []byte(`"signature"`), 1),
+		"garbled signature": bytes.Replace(payload, []byte(`"hmacSignature": "`), []byte(`"hmacSignature": "!!`), 1),
+		"no items":          []byte(`{"live":"false","notificationItems":[]}`),
+		"malformed":         []byte("not json"),
+	}
+	for name, body := range tests {
+		if err := adapter.ValidateWebhook(ctx, body, ""); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	other, err := NewAdapter(Config{HMACKey: "00112233445566778899AABBCCDDEEFF00112233445566778899AABBCCDDEEFF"}, adapter.logger)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := other.ValidateWebhook(ctx, payload, ""); err == nil {
+		t.Error("expected an error validating with another key")
+	}
+	if _, err := NewAdapter(Config{HMACKey: "not hex"}, adapter.logger); err == nil {
+		t.Error("expected an error for a malformed HMAC key")
+	}
+}
+
+func TestAdapter_ParseWebhook(t *testing.T) {
+	_, adapter := newFakeAdyen(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		fixture   string
+		want      billing.WebhookEventType
+		status    string
+		sessionID string
+		amount    float64
+		currency  string
+	}{
+		{"authorisation.json", billing.WebhookEventTypeCheckoutSessionCompleted, "completed", "PLE83C39B0A0DE4A7A", 9.99, "EUR"},
+		{"authorisation_refused.json", billing.WebhookEventTypePaymentFailed, "failed", "PLE83C39B0A0DE4A7A", 9.99, "EUR"},
+		{"authorisation_renewal.json", billing.WebhookEventTypePaymentSucceeded, "completed", "renewal-7b2d", 1200, "JPY"},
+		{"refund.json", billing.WebhookEventTypePaymentRefunded, "refunded", "PLE83C39B0A0DE4A7A", 9.99, "EUR"},
+		{"chargeback.json", billing.WebhookEventTypePaymentChargeback, "chargeback", "renewal-7b2d", 3.25, "KWD"},
+	}
+	for _, tt := range tests {
+		result, err := adapter.ParseWebhook(ctx, fixture(t, tt.fixture))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.fixture, err)
+			continue
+		}
+		if result.EventType != string(tt.want) || result.Status != tt.status || result.SessionID != tt.sessionID {
+			t.Errorf("%s: expected %s (%s) for %q, got %s (%s) for %q", tt.fixture, tt.want, tt.status, tt.sessionID, result.EventType, result.Status, result.SessionID)
+		}
+		if result.Amount != tt.amount || result.Currency != tt.currency {
+			t.Errorf("%s: expected %v %s, got %v %s", tt.fixture, tt.amount, tt.currency, result.Amount, result.Currency)
+		}
+		if result.UserID != "user-1" || result.PlanIDString != "premium" || result.PlanID == uuid.Nil {
+			t.Errorf("%s: expected the checkout metadata, got %+v", tt.fixture, result)
+		}
+	}
+
+	result, err := adapter.ParseWebhook(ctx, fixture(t, "authorisation.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FamilyID == nil || *result.FamilyID != "family-1" || result.Metadata["adyen_psp_reference"] != "QFQTPCQ8HXSKGK82" {
+		t.Errorf("unexpected authorisation %+v", result)
+	}
+	if c := result.Customer; c == nil || c.Email != "ada@example.com" || c.Address.Country != "GB" || c.Address.City != "London" {
+		t.Errorf("expected the shopper's details, got %+v", result.Customer)
+	}
+
+	refused, _ := adapter.ParseWebhook(ctx, fixture(t, "authorisation_refused.json"))
+	if refused == nil || refused.Metadata["failure_reason"] != "Not enough balance" || refused.Customer != nil {
+		t.Errorf("expected the refusal reason, got %+v", refused)
+	}
+	refund, _ := adapter.ParseWebhook(ctx, fixture(t, "refund.json"))
+	if refund == nil || refund.Metadata["adyen_original_reference"] != "QFQTPCQ8HXSKGK82" || refund.Reference != "T8LJQZ9VKRV5HN82" {
+		t.Errorf("expected the refunded payment, got %+v", refund)
+	}
+	chargeback, _ := adapter.ParseWebhook(ctx, fixture(t, "chargeback.json"))
+	if chargeback == nil || chargeback.Reference != "WQ8RPXJK6V2MKS82" {
+		t.Errorf("expected the dispute's reference, got %+v", chargeback)
+	}
+
+	if _, err := adapter.ParseWebhook(ctx, fixture(t, "report_available.json")); err == nil {
+		t.Error("expected an error for an unhandled event code")
+	}
+	if _, err := adapter.ParseWebhook(ctx, []byte(`{"live":"false","notificationItems":[]}`)); err == nil {
+		t.Error("expected an error for a notification without items")
+	}
+	if _, err := adapter.ParseWebhook(ctx, []byte("not json")); err == nil {
+		t.Error("expected an error for a malformed payload")
+	}
+}
@@ -0,0 +1,253 @@
+package adyenbp
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// amount is an Adyen amount in the currency's minor units
+type amount struct {
+	Currency string `json:"currency"`
+	Value    int64  `json:"value"`
+}
+
+// currencyExponents lists the currencies whose minor unit is not a
+// hundredth, by the number of decimals Adyen uses for them
+var currencyExponents = map[string]int{
+	"CVE": 0, "DJF": 0, "GNF": 0, "IDR": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// exponent returns the number of decimals of a currency's minor unit
+func exponent(currency string) int {
+	if e, ok := currencyExponents[strings.ToUpper(currency)]; ok {
+		return e
+	}
+	return 2
+}
+
+// newAmount converts an amount in dollars (or the currency's major unit)
+func newAmount(major float64, currency string) amount {
+	currency = strings.ToUpper(currency)
+	return amount{Currency: currency, Value: int64(math.Round(major * math.Pow10(exponent(currency))))}
+}
+
+// major returns the value in dollars (or the currency's major unit)
+func (a amount) major() float64 {
+	return float64(a.Value) / math.Pow10(exponent(a.Currency))
+}
+
+// paymentLink is an Adyen payment link, the hosted page a checkout is paid on
+type paymentLink struct {
+	ID                       string            `json:"id,omitempty"`
+	URL                      string            `json:"url,omitempty"`
+	Status                   string            `json:"status,omitempty"` // active, paymentPending, completed, paid or expired
+	Reference                string            `json:"reference"`
+	Amount                   amount            `json:"amount"`
+	MerchantAccount          string            `json:"merchantAccount"`
+	Description              string            `json:"description,omitempty"`
+	CountryCode              string            `json:"countryCode,omitempty"`
+	ReturnURL                string            `json:"returnUrl,omitempty"`
+	ShopperReference         string            `json:"shopperReference,omitempty"`
+	StorePaymentMethodMode   string            `json:"storePaymentMethodMode,omitempty"`
+	RecurringProcessingModel string            `json:"recurringProcessingModel,omitempty"`
+	ExpiresAt                string            `json:"expiresAt,omitempty"`
+	CreationDate             string            `json:"creationDate,omitempty"`
+	UpdatedAt                string            `json:"updatedAt,omitempty"`
+	Metadata                 map[string]string `json:"metadata,omitempty"`
+}
+
+// storedPaymentMethod is a payment method Adyen saved for a shopper
+type storedPaymentMethod struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"` // e.g. scheme for cards, sepadirectdebit, paypal
+	Brand       string `json:"brand,omitempty"`
+	LastFour    string `json:"lastFour,omitempty"`
+	ExpiryMonth string `json:"expiryMonth,omitempty"`
+	ExpiryYear  string `json:"expiryYear,omitempty"`
+	HolderName  string `json:"holderName,omitempty"`
+}
+
+type storedPaymentMethods struct {
+	ShopperReference     string                `json:"shopperReference"`
+	StoredPaymentMethods []storedPaymentMethod `json:"storedPaymentMethods"`
+}
+
+// paymentRequest is a payment made without the shopper present
+type paymentRequest struct {
+	Amount          amount `json:"amount"`
+	Reference       string `json:"reference"`
+	MerchantAccount string `json:"merchantAccount"`
+	PaymentMethod   struct {
+		Type                  string `json:"type"`
+		StoredPaymentMethodID string `json:"storedPaymentMethodId"`
+	} `json:"paymentMethod"`
+	ShopperReference         string            `json:"shopperReference"`
+	ShopperInteraction       string            `json:"shopperInteraction"`
+	RecurringProcessingModel string            `json:"recurringProcessingModel"`
+	ShopperStatement         string            `json:"shopperStatement,omitempty"`
+	Metadata                 map[string]string `json:"metadata,omitempty"`
+}
+
+type paymentResponse struct {
+	PSPReference      string `json:"pspReference"`
+	ResultCode        string `json:"resultCode"`
+	RefusalReason     string `json:"refusalReason,omitempty"`
+	RefusalReasonCode string `json:"refusalReasonCode,omitempty"`
+	MerchantReference string `json:"merchantReference,omitempty"`
+}
+
+// Checkout metadata keys
+const (
+	metadataUserID   = "user_id"
+	metadataPlanID   = "plan_id"
+	metadataFamilyID = "family_id"
+)
+
+// Adyen metadata limits
+const (
+	maxMetadataEntries     = 20
+	maxMetadataKeyLength   = 20
+	maxMetadataValueLength = 80
+)
+
+// newMetadata builds payment metadata from key-value pairs followed by
+// extra entries, leaving out empty values and entries Adyen would reject
+func newMetadata(extra map[string]string, pairs ...string) map[string]string {
+	metadata := make(map[string]string)
+	add := func(k, v string) {
+		if v == "" || len(k) > maxMetadataKeyLength || len(v) > maxMetadataValueLength || len(metadata) >= maxMetadataEntries {
+			return
+		}
+		if _, ok := metadata[k]; !ok {
+			metadata[k] = v
+		}
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		add(pairs[i], pairs[i+1])
+	}
+	for k, v := range extra {
+		add(k, v)
+	}
+	return metadata
+}
+
+// paymentMethodID joins a shopper reference and a stored payment method ID.
+// Adyen needs both to use or delete a stored payment method.
+func paymentMethodID(shopperReference, storedID string) string {
+	return shopperReference + ":" + storedID
+}
+
+// splitPaymentMethodID splits a payment method ID made by paymentMethodID
+func splitPaymentMethodID(id string) (shopperReference, storedID string, err error) {
+	i := strings.LastIndex(id, ":")
+	if i <= 0 || i == len(id)-1 {
+		return "", "", fmt.Errorf("invalid Adyen payment method ID %q", id)
+	}
+	return id[:i], id[i+1:], nil
+}
+
+// parseTime parses an Adyen timestamp, returning the zero time for empty or
+// malformed values
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// linkSessionStatus maps a payment link status to a checkout session status
+func linkSessionStatus(status string) billing.SessionStatus {
+	switch status {
+	case "completed", "paid":
+		return billing.SessionStatusComplete
+	case "expired":
+		return billing.SessionStatusExpired
+	default: // active, paymentPending
+		return billing.SessionStatusOpen
+	}
+}
+
+// resultChargeStatus maps the result code of a payment to a charge status
+func resultChargeStatus(resultCode string) billing.ChargeStatus {
+	switch resultCode {
+	case "Authorised":
+		return billing.ChargeStatusSucceeded
+	case "Pending", "Received":
+		return billing.ChargeStatusPending
+	case "ChallengeShopper", "IdentifyShopper", "RedirectShopper", "PresentToShopper":
+		return billing.ChargeStatusRequiresAction
+	default: // Refused, Cancelled, Error
+		return billing.ChargeStatusFailed
+	}
+}
+
+// convertLinkSession converts a payment link to a checkout session
+func convertLinkSession(l *paymentLink) *billing.Session {
+	created := parseTime(l.CreationDate)
+	updated := parseTime(l.UpdatedAt)
+	if updated.IsZero() {
+		updated = created
+	}
+	metadata := map[string]interface{}{"adyen_status": l.Status, "adyen_reference": l.Reference}
+	for k, v := range l.Metadata {
+		metadata[k] = v
+	}
+	return &billing.Session{
+		ID:        l.ID,
+		Status:    string(linkSessionStatus(l.Status)),
+		URL:       l.URL,
+		ExpiresAt: parseTime(l.ExpiresAt),
+		Metadata:  metadata,
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
+
+// convertStoredPaymentMethod converts a stored payment method
+func convertStoredPaymentMethod(shopperReference string, m *storedPaymentMethod) *billing.PaymentMethodDetails {
+	details := &billing.PaymentMethodDetails{ID: paymentMethodID(shopperReference, m.ID)}
+	switch {
+	case m.Type == "scheme":
+		details.Type = "credit_card"
+		details.Brand = m.Brand
+		details.Last4 = m.LastFour
+		month, _ := strconv.Atoi(m.ExpiryMonth)
+		year, _ := strconv.Atoi(m.ExpiryYear)
+		details.ExpMonth, details.ExpYear = int32(month), int32(year)
+	case m.Type == "ach" || strings.Contains(m.Type, "directdebit"):
+		details.Type = "bank_transfer"
+		details.Last4 = m.LastFour
+	default:
+		details.Type = "digital_wallet"
+		details.Brand = m.Type
+	}
+	return details
+}
+
+// planUUID returns a plan ID as a UUID, deriving a deterministic one from
+// string plan IDs
+func planUUID(planID string) uuid.UUID {
+	if planID == "" {
+		return uuid.Nil
+	}
+	if parsed, err := uuid.Parse(planID); err == nil {
+		return parsed
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(planID))
+}
+
+// pathEscape escapes a provider ID for use in a URL path
+func pathEscape(id string) string {
+	return url.PathEscape(id)
+}
@@ -0,0 +1,287 @@
+// Package billingtest provides an in-memory billing.Provider for tests
+package billingtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// FakeProvider is an in-memory billing.Provider. Checkout sessions,
+// customers and payment methods it creates are kept in memory; charges and
+// subscriptions to be listed are seeded with AddCharge and AddSubscription.
+// Webhook payloads are JSON-encoded billing.WebhookResult values.
+type FakeProvider struct {
+	mu             sync.Mutex
+	seq            int
+	sessions       map[string]*billing.Session
+	charges        []billing.Charge
+	subscriptions  []billing.Subscription
+	paymentMethods map[string]string // Payment method ID -> customer ID
+	defaults       map[string]string // Customer ID -> default payment method ID
+}
+
+// NewFakeProvider creates an empty fake provider
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{
+		sessions:       make(map[string]*billing.Session),
+		paymentMethods: make(map[string]string),
+		defaults:       make(map[string]string),
+	}
+}
+
+// AddCharge seeds a charge to be returned by ListCharges
+func (f *FakeProvider) AddCharge(charge billing.Charge) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.charges = append(f.charges, charge)
+}
+
+// AddSubscription seeds a subscription to be returned by ListSubscriptions
+func (f *FakeProvider) AddSubscription(sub billing.Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscriptions = append(f.subscriptions, sub)
+}
+
+// CreateCheckoutSession implements billing.Provider
+func (f *FakeProvider) CreateCheckoutSession(ctx context.Context, req billing.CreateCheckoutSessionRequest) (*billing.CreateCheckoutSessionResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.nextID("cs_fake")
+	now := time.Now()
+	expiresAt := req.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(24 * time.Hour)
+	}
+	metadata := make(map[string]interface{}, len(req.Metadata)+2)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	metadata["user_id"] = req.UserID
+	metadata["plan_id"] = req.PlanID.String()
+
+	f.sessions[id] = &billing.Session{
+		ID:        id,
+		Status:    string(billing.SessionStatusOpen),
+		URL:       "https://checkout.fake/" + id,
+		ExpiresAt: expiresAt,
+		Metadata:  metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return &billing.CreateCheckoutSessionResponse{SessionID: id, URL: f.sessions[id].URL, ExpiresAt: expiresAt}, nil
+}
+
+// GetSession implements billing.Provider
+func (f *FakeProvider) GetSession(ctx context.Context, sessionID string) (*billing.Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("no such checkout session: %s", sessionID)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+// CancelSession implements billing.Provider
+func (f *FakeProvider) CancelSession(ctx context.Context, sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s, ok := f.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("no such checkout session: %s", sessionID)
+	}
+	s.Status = string(billing.SessionStatusCancelled)
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// ValidateWebhook implements billing.Provider; any non-empty signature is valid
+func (f *FakeProvider) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("missing webhook signature")
+	}
+	return nil
+}
+
+// ParseWebhook implements billing.Provider
+func (f *FakeProvider) ParseWebhook(ctx context.Context, payload []byte) (*billing.WebhookResult, error) {
+	var result billing.WebhookResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	return &result, nil
+}
+
+// SaveCustomer implements billing.Provider
+func (f *FakeProvider) SaveCustomer(ctx context.Context, req billing.SaveCustomerRequest) (string, error) {
+	if req.CustomerID != "" {
+		return req.CustomerID, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nextID("cus_fake"), nil
+}
+
+// AttachPaymentMethod implements billing.Provider; every token is a Visa card
+func (f *FakeProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*billing.PaymentMethodDetails, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.nextID("pm_fake")
+	f.paymentMethods[id] = customerID
+	return &billing.PaymentMethodDetails{
+		ID:       id,
+		Type:     "credit_card",
+		Brand:    "visa",
+		Last4:    "4242",
+		ExpMonth: 12,
+		ExpYear:  int32(time.Now().Year() + 3),
+	}, nil
+}
+
+// DetachPaymentMethod implements billing.Provider
+func (f *FakeProvider) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.paymentMethods[paymentMethodID]; !ok {
+		return fmt.Errorf("no such payment method: %s", paymentMethodID)
+	}
+	delete(f.paymentMethods, paymentMethodID)
+	return nil
+}
+
+// SetDefaultPaymentMethod implements billing.Provider
+func (f *FakeProvider) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.paymentMethods[paymentMethodID] != customerID {
+		return fmt.Errorf("payment method %s is not attached to %s", paymentMethodID, customerID)
+	}
+	f.defaults[customerID] = paymentMethodID
+	return nil
+}
+
+// ChargePaymentMethod implements billing.Provider. Charges of attached
+// methods succeed and are recorded for ListCharges.
+func (f *FakeProvider) ChargePaymentMethod(ctx context.Context, req billing.ChargeRequest) (*billing.ChargeResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.paymentMethods[req.PaymentMethodID] != req.CustomerID {
+		return &billing.ChargeResult{Status: billing.ChargeStatusFailed, FailureReason: "payment method not attached"}, nil
+	}
+
+	id := f.nextID("ch_fake")
+	f.charges = append(f.charges, billing.Charge{
+		ID:         id,
+		CustomerID: req.CustomerID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Status:     billing.ChargeStatusSucceeded,
+		CreatedAt:  time.Now(),
+	})
+	return &billing.ChargeResult{ID: id, Status: billing.ChargeStatusSucceeded}, nil
+}
+
+// ListCharges implements billing.Provider, oldest first
+func (f *FakeProvider) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []billing.Charge
+	for _, c := range f.charges {
+		if inWindow(c.CreatedAt, req) {
+			matched = append(matched, c)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].CreatedAt.Before(matched[j].CreatedAt) })
+
+	start, end, next, err := pageBounds(len(matched), req)
+	if err != nil {
+		return nil, err
+	}
+	return &billing.ChargePage{Charges: matched[start:end], NextCursor: next}, nil
+}
+
+// ListSubscriptions implements billing.Provider, oldest first
+func (f *FakeProvider) ListSubscriptions(ctx context.Context, req billing.ListRequest) (*billing.SubscriptionPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var matched []billing.Subscription
+	for _, s := range f.subscriptions {
+		if inWindow(s.CreatedAt, req) {
+			matched = append(matched, s)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].CreatedAt.Before(matched[j].CreatedAt) })
+
+	start, end, next, err := pageBounds(len(matched), req)
+	if err != nil {
+		return nil, err
+	}
+	return &billing.SubscriptionPage{Subscriptions: matched[start:end], NextCursor: next}, nil
+}
+
+// Close implements billing.Provider
+func (f *FakeProvider) Close() error {
+	return nil
+}
+
+// nextID returns a new unique ID with the given prefix; callers hold f.mu
+func (f *FakeProvider) nextID(prefix string) string {
+	f.seq++
+	return fmt.Sprintf("%s_%d", prefix, f.seq)
+}
+
+// inWindow reports whether t falls in the request's [CreatedAfter, CreatedBefore) window
+func inWindow(t time.Time, req billing.ListRequest) bool {
+	if !req.CreatedAfter.IsZero() && t.Before(req.CreatedAfter) {
+		return false
+	}
+	if !req.CreatedBefore.IsZero() && !t.Before(req.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// pageBounds returns the slice bounds of the requested page of n records and
+// the cursor of the following page. Cursors are offsets.
+func pageBounds(n int, req billing.ListRequest) (int, int, string, error) {
+	start := 0
+	if req.Cursor != "" {
+		offset, err := strconv.Atoi(req.Cursor)
+		if err != nil || offset < 0 {
+			return 0, 0, "", fmt.Errorf("invalid cursor: %q", req.Cursor)
+		}
+		start = min(offset, n)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	end := min(start+limit, n)
+
+	next := ""
+	if end < n {
+		next = strconv.Itoa(end)
+	}
+	return start, end, next, nil
+}
@@ -0,0 +1,268 @@
+// Package conformance holds the contract every billing.Provider adapter is
+// expected to honour: how checkout sessions move through their lifecycle,
+// which webhooks are turned away, and how webhook results are normalized.
+// Adapters run it from their own tests with Run, against whatever fake of
+// their provider's API they already test with.
+package conformance
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// Webhook is a webhook as the provider delivers it
+type Webhook struct {
+	Payload   []byte
+	Signature string
+}
+
+// Fixtures drives the provider side of a checkout: what a real provider
+// would do on its own when the buyer pays or the session times out, and the
+// webhooks it would (or would never) send
+type Fixtures interface {
+	// CompleteCheckout pays an open session and returns the signed webhook
+	// the provider sends for it
+	CompleteCheckout(sessionID string) (Webhook, error)
+
+	// ExpireSession lets an open session run out its time
+	ExpireSession(sessionID string) error
+
+	// UnknownEvent returns a validly signed webhook of an event type the
+	// adapter does not handle
+	UnknownEvent() (Webhook, error)
+
+	// SignedAt signs payload as if it were sent at the given time. ok is
+	// false when the provider's signatures carry no timestamp.
+	SignedAt(payload []byte, at time.Time) (wh Webhook, ok bool, err error)
+
+	// Forge signs payload with a key other than the provider's
+	Forge(payload []byte) (Webhook, error)
+}
+
+// Factory returns a fresh provider and the fixtures that drive it
+type Factory func(t *testing.T) (billing.Provider, Fixtures)
+
+const (
+	checkoutPrice    = 19.99
+	checkoutCurrency = "eur"
+	checkoutUserID   = "user-conformance"
+)
+
+// Run runs the conformance suite against the providers newProvider returns
+func Run(t *testing.T, newProvider Factory) {
+	t.Run("SessionLifecycle", func(t *testing.T) { testSessionLifecycle(t, newProvider) })
+	t.Run("UnknownSession", func(t *testing.T) { testUnknownSession(t, newProvider) })
+	t.Run("ExpiredSession", func(t *testing.T) { testExpiredSession(t, newProvider) })
+	t.Run("CompletionWebhook", func(t *testing.T) { testCompletionWebhook(t, newProvider) })
+	t.Run("BadSignatures", func(t *testing.T) { testBadSignatures(t, newProvider) })
+	t.Run("ReplayedTimestamp", func(t *testing.T) { testReplayedTimestamp(t, newProvider) })
+	t.Run("UnknownEvent", func(t *testing.T) { testUnknownEvent(t, newProvider) })
+}
+
+// openSession creates the checkout session every case starts from
+func openSession(t *testing.T, provider billing.Provider) (billing.CreateCheckoutSessionRequest, *billing.CreateCheckoutSessionResponse) {
+	t.Helper()
+	req := billing.CreateCheckoutSessionRequest{
+		PlanID:      uuid.New(),
+		UserID:      checkoutUserID,
+		SuccessURL:  "https://jia.app/success",
+		CancelURL:   "https://jia.app/cancel",
+		CountryCode: "DE",
+		BasePrice:   checkoutPrice,
+		Currency:    checkoutCurrency,
+		ExpiresAt:   time.Now().Add(2 * time.Hour).Truncate(time.Second),
+	}
+	resp, err := provider.CreateCheckoutSession(context.Background(), req)
+	if err != nil {
+		t.Fatalf("failed to create checkout session: %v", err)
+	}
+	return req, resp
+}
+
+// completedWebhook opens a session and pays it
+func completedWebhook(t *testing.T, provider billing.Provider, fixtures Fixtures) (billing.CreateCheckoutSessionRequest, string, Webhook) {
+	t.Helper()
+	req, resp := openSession(t, provider)
+	wh, err := fixtures.CompleteCheckout(resp.SessionID)
+	if err != nil {
+		t.Fatalf("failed to complete checkout: %v", err)
+	}
+	return req, resp.SessionID, wh
+}
+
+func testSessionLifecycle(t *testing.T, newProvider Factory) {
+	provider, _ := newProvider(t)
+	ctx := context.Background()
+
+	req, resp := openSession(t, provider)
+	if resp.SessionID == "" || resp.URL == "" {
+		t.Fatalf("expected a session ID and checkout URL, got %+v", resp)
+	}
+	if !resp.ExpiresAt.After(time.Now()) || resp.ExpiresAt.After(req.ExpiresAt.Add(time.Minute)) {
+		t.Errorf("expected the session to expire by the requested %v, got %v", req.ExpiresAt, resp.ExpiresAt)
+	}
+
+	session, err := provider.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.ID != resp.SessionID || session.Status != string(billing.SessionStatusOpen) {
+		t.Errorf("expected open session %s, got %+v", resp.SessionID, session)
+	}
+
+	if err := provider.CancelSession(ctx, resp.SessionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	session, err = provider.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusCancelled) && session.Status != string(billing.SessionStatusExpired) {
+		t.Errorf("expected a cancelled session to be closed, got %s", session.Status)
+	}
+}
+
+func testUnknownSession(t *testing.T, newProvider Factory) {
+	provider, _ := newProvider(t)
+	if session, err := provider.GetSession(context.Background(), "unknown-session"); err == nil {
+		t.Errorf("expected an error for an unknown session, got %+v", session)
+	}
+}
+
+func testExpiredSession(t *testing.T, newProvider Factory) {
+	provider, fixtures := newProvider(t)
+	ctx := context.Background()
+
+	_, resp := openSession(t, provider)
+	if err := fixtures.ExpireSession(resp.SessionID); err != nil {
+		t.Fatalf("failed to expire session: %v", err)
+	}
+	session, err := provider.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusExpired) {
+		t.Errorf("expected the session to be expired, got %s", session.Status)
+	}
+	if err := provider.CancelSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected an expired session not to be cancellable")
+	}
+}
+
+func testCompletionWebhook(t *testing.T, newProvider Factory) {
+	provider, fixtures := newProvider(t)
+	ctx := context.Background()
+
+	req, sessionID, wh := completedWebhook(t, provider, fixtures)
+	if err := provider.ValidateWebhook(ctx, wh.Payload, wh.Signature); err != nil {
+		t.Fatalf("expected the completion webhook to validate, got %v", err)
+	}
+	result, err := provider.ParseWebhook(ctx, wh.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.EventType != string(billing.WebhookEventTypeCheckoutSessionCompleted) {
+		t.Errorf("expected event type %s, got %s", billing.WebhookEventTypeCheckoutSessionCompleted, result.EventType)
+	}
+	if result.SessionID != sessionID || result.UserID != req.UserID || result.PlanID != req.PlanID {
+		t.Errorf("expected session %s for user %s and plan %s, got %+v", sessionID, req.UserID, req.PlanID, result)
+	}
+	if result.Amount != checkoutPrice || result.Currency != strings.ToUpper(checkoutCurrency) {
+		t.Errorf("expected %.2f %s, got %.2f %s", checkoutPrice, strings.ToUpper(checkoutCurrency), result.Amount, result.Currency)
+	}
+	if result.Status != "completed" {
+		t.Errorf("expected status completed, got %s", result.Status)
+	}
+}
+
+func testBadSignatures(t *testing.T, newProvider Factory) {
+	provider, fixtures := newProvider(t)
+	ctx := context.Background()
+
+	_, _, wh := completedWebhook(t, provider, fixtures)
+	if err := provider.ValidateWebhook(ctx, tamper(t, wh.Payload), wh.Signature); err == nil {
+		t.Error("expected a tampered payload to be rejected")
+	}
+
+	forged, err := fixtures.Forge(wh.Payload)
+	if err != nil {
+		t.Fatalf("failed to forge webhook: %v", err)
+	}
+	if err := provider.ValidateWebhook(ctx, forged.Payload, forged.Signature); err == nil {
+		t.Error("expected a forged signature to be rejected")
+	}
+
+	// Providers that sign inside the payload have no signature header to leave out
+	if wh.Signature != "" {
+		if err := provider.ValidateWebhook(ctx, wh.Payload, ""); err == nil {
+			t.Error("expected a missing signature to be rejected")
+		}
+	}
+
+	if _, err := provider.ParseWebhook(ctx, []byte("not json")); err == nil {
+		t.Error("expected a malformed payload not to parse")
+	}
+}
+
+// tamper changes the first digit in payload, so an amount or timestamp no
+// longer matches what was signed
+func tamper(t *testing.T, payload []byte) []byte {
+	t.Helper()
+	tampered := append([]byte(nil), payload...)
+	for i, c := range tampered {
+		if c >= '0' && c <= '9' {
+			tampered[i] = '0' + (c-'0'+1)%10
+			return tampered
+		}
+	}
+	t.Fatalf("payload has no digit to tamper with: %s", payload)
+	return nil
+}
+
+func testReplayedTimestamp(t *testing.T, newProvider Factory) {
+	provider, fixtures := newProvider(t)
+	ctx := context.Background()
+
+	_, _, wh := completedWebhook(t, provider, fixtures)
+	replayed, ok, err := fixtures.SignedAt(wh.Payload, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("failed to sign webhook: %v", err)
+	}
+	if !ok {
+		t.Skip("the provider's signatures carry no timestamp")
+	}
+	if err := provider.ValidateWebhook(ctx, replayed.Payload, replayed.Signature); err == nil {
+		t.Error("expected a webhook signed an hour ago to be rejected")
+	}
+
+	recent, _, err := fixtures.SignedAt(wh.Payload, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("failed to sign webhook: %v", err)
+	}
+	if err := provider.ValidateWebhook(ctx, recent.Payload, recent.Signature); err != nil {
+		t.Errorf("expected a webhook signed a minute ago to validate, got %v", err)
+	}
+}
+
+func testUnknownEvent(t *testing.T, newProvider Factory) {
+	provider, fixtures := newProvider(t)
+	ctx := context.Background()
+
+	wh, err := fixtures.UnknownEvent()
+	if err != nil {
+		t.Fatalf("failed to build webhook: %v", err)
+	}
+	if err := provider.ValidateWebhook(ctx, wh.Payload, wh.Signature); err != nil {
+		t.Fatalf("expected the webhook to validate, got %v", err)
+	}
+	if result, err := provider.ParseWebhook(ctx, wh.Payload); err == nil {
+		t.Errorf("expected an unknown event type not to parse, got %+v", result)
+	}
+}
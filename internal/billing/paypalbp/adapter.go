@@ -0,0 +1,624 @@
+// Package paypalbp implements billing.Provider for PayPal using the Orders,
+// Subscriptions, Payment Method Tokens and Transaction Search REST APIs.
+package paypalbp
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// SandboxBaseURL is the PayPal sandbox REST API, used when no base URL is configured
+const SandboxBaseURL = "https://api-m.sandbox.paypal.com"
+
+// PlanMetadataKey is the checkout metadata key naming a PayPal billing plan.
+// Checkouts that set it open a subscription on that plan; all others open a
+// one-off order.
+const PlanMetadataKey = "paypal_plan_id"
+
+// sessionLifetime is how long PayPal keeps an unapproved checkout payable
+const sessionLifetime = 3 * time.Hour
+
+// Config holds the PayPal adapter settings
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	BaseURL      string            // REST API base URL; SandboxBaseURL when empty
+	WebhookID    string            // ID of the webhook subscription events are delivered to
+	WebhookCert  *x509.Certificate // Certificate PayPal signs webhooks with
+	HTTPClient   *http.Client      // Defaults to a client with a 30 second timeout
+}
+
+// Adapter implements the billing.Provider interface for PayPal
+type Adapter struct {
+	client         *client
+	webhookID      string
+	webhookCert    *x509.Certificate
+	logger         *zap.Logger
+	circuitBreaker *circuitbreaker.CircuitBreaker
+	now            func() time.Time
+}
+
+// NewAdapter creates a new PayPal billing adapter
+func NewAdapter(cfg Config, logger *zap.Logger) *Adapter {
+	baseURL := strings.TrimSuffix(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = SandboxBaseURL
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	return &Adapter{
+		client: &client{
+			baseURL:      baseURL,
+			clientID:     cfg.ClientID,
+			clientSecret: cfg.ClientSecret,
+			httpClient:   httpClient,
+			now:          time.Now,
+		},
+		webhookID:      cfg.WebhookID,
+		webhookCert:    cfg.WebhookCert,
+		logger:         logger,
+		circuitBreaker: circuitbreaker.GetOrCreateGlobal("paypal", circuitbreaker.ExternalAPIConfig),
+		now:            time.Now,
+	}
+}
+
+// CreateCheckoutSession creates a PayPal order, or a subscription when the
+// request names a PayPal billing plan under PlanMetadataKey. The session ID
+// is the order or subscription ID and the URL is where the buyer approves it.
+func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateCheckoutSessionRequest) (*billing.CreateCheckoutSessionResponse, error) {
+	var result *billing.CreateCheckoutSessionResponse
+
+	customID := encodeCustomID(
+		metadataUserID, req.UserID,
+		metadataPlanID, req.PlanID.String(),
+		metadataFamilyID, getStringValue(req.FamilyID),
+	)
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		var (
+			id, status, approve string
+			created             time.Time
+		)
+		if planID := req.Metadata[PlanMetadataKey]; planID != "" {
+			sub := subscription{
+				PlanID:   planID,
+				CustomID: customID,
+				ApplicationContext: &applicationContext{
+					ReturnURL:          req.SuccessURL,
+					CancelURL:          req.CancelURL,
+					UserAction:         "SUBSCRIBE_NOW",
+					ShippingPreference: "NO_SHIPPING",
+				},
+			}
+			var resp subscription
+			if err := a.client.do(ctx, http.MethodPost, "/v1/billing/subscriptions", sub, "", &resp); err != nil {
+				a.logger.Error("Failed to create PayPal subscription",
+					zap.Error(err),
+					zap.String("plan_id", req.PlanID.String()),
+					zap.String("user_id", req.UserID))
+				return nil, fmt.Errorf("failed to create checkout session: %w", err)
+			}
+			id, status, approve, created = resp.ID, resp.Status, approveURL(resp.Links), parseTime(resp.CreateTime)
+		} else {
+			amount := newMoney(req.BasePrice, req.Currency)
+			source := &paypalSource{
+				VaultID: req.PaymentMethodID,
+				ExperienceContext: &experienceContext{
+					ReturnURL:          req.SuccessURL,
+					CancelURL:          req.CancelURL,
+					UserAction:         "PAY_NOW",
+					ShippingPreference: "NO_SHIPPING",
+				},
+			}
+			o := order{
+				Intent: "CAPTURE",
+				PurchaseUnits: []purchaseUnit{{
+					ReferenceID: "default",
+					CustomID:    customID,
+					Description: fmt.Sprintf("Plan ID: %s", req.PlanID.String()),
+					Amount:      &amount,
+				}},
+				PaymentSource: &paymentSource{PayPal: source},
+			}
+			var resp order
+			if err := a.client.do(ctx, http.MethodPost, "/v2/checkout/orders", o, "", &resp); err != nil {
+				a.logger.Error("Failed to create PayPal order",
+					zap.Error(err),
+					zap.String("plan_id", req.PlanID.String()),
+					zap.String("user_id", req.UserID))
+				return nil, fmt.Errorf("failed to create checkout session: %w", err)
+			}
+			id, status, approve, created = resp.ID, resp.Status, approveURL(resp.Links), parseTime(resp.CreateTime)
+		}
+
+		if created.IsZero() {
+			created = a.now()
+		}
+		a.logger.Info("Created PayPal checkout",
+			zap.String("session_id", id),
+			zap.String("status", status),
+			zap.String("plan_id", req.PlanID.String()),
+			zap.String("user_id", req.UserID),
+			zap.String("checkout_url", approve))
+
+		result = &billing.CreateCheckoutSessionResponse{
+			SessionID: id,
+			URL:       approve,
+			ExpiresAt: sessionExpiry(req.ExpiresAt, created),
+		}
+		return result, nil
+	})
+
+	return result, err
+}
+
+// GetSession retrieves a PayPal order or subscription as a checkout session
+func (a *Adapter) GetSession(ctx context.Context, sessionID string) (*billing.Session, error) {
+	var result *billing.Session
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		if isSubscriptionID(sessionID) {
+			sub, err := a.getSubscription(ctx, sessionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve checkout session: %w", err)
+			}
+			result = convertSubscriptionSession(sub, sessionLifetime, a.now())
+			return result, nil
+		}
+
+		o, err := a.getOrder(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve checkout session: %w", err)
+		}
+		result = convertOrderSession(o, sessionLifetime, a.now())
+		return result, nil
+	})
+
+	return result, err
+}
+
+// CancelSession cancels a PayPal checkout. PayPal cannot cancel an order or
+// subscription the buyer has not approved yet, so the checkout is marked
+// cancelled instead: an approved order carrying the mark is never captured.
+// A subscription is charged by PayPal as soon as it is approved, so one
+// approved after cancellation is honoured like any late payment.
+func (a *Adapter) CancelSession(ctx context.Context, sessionID string) error {
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		if isSubscriptionID(sessionID) {
+			sub, err := a.getSubscription(ctx, sessionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+			}
+			if sub.Status != "APPROVAL_PENDING" {
+				return nil, errNotCancellable(sessionID, sub.Status)
+			}
+			if unapprovedExpired(parseTime(sub.CreateTime), sessionLifetime, a.now()) {
+				return nil, errNotCancellable(sessionID, string(billing.SessionStatusExpired))
+			}
+			patch := []map[string]string{{"op": "replace", "path": "/custom_id", "value": withCancelled(sub.CustomID)}}
+			if err := a.client.do(ctx, http.MethodPatch, "/v1/billing/subscriptions/"+pathEscape(sessionID), patch, "", nil); err != nil {
+				return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+			}
+		} else {
+			o, err := a.getOrder(ctx, sessionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+			}
+			if orderSessionStatus(o.Status) != billing.SessionStatusOpen {
+				return nil, errNotCancellable(sessionID, o.Status)
+			}
+			if o.Status != "APPROVED" && unapprovedExpired(parseTime(o.CreateTime), sessionLifetime, a.now()) {
+				return nil, errNotCancellable(sessionID, string(billing.SessionStatusExpired))
+			}
+			patch := []map[string]string{{
+				"op":    "replace",
+				"path":  "/purchase_units/@reference_id=='default'/custom_id",
+				"value": withCancelled(o.unit().CustomID),
+			}}
+			if err := a.client.do(ctx, http.MethodPatch, "/v2/checkout/orders/"+pathEscape(sessionID), patch, "", nil); err != nil {
+				return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+			}
+		}
+
+		a.logger.Info("Cancelled PayPal checkout", zap.String("session_id", sessionID))
+		return nil, nil
+	})
+
+	return err
+}
+
+// SaveCustomer returns the customer ID to vault payment methods under.
+// PayPal keeps no customer records of its own; a customer is an ID chosen
+// by the merchant when saving a payment method, so a new one is generated
+// for new customers and existing IDs are returned unchanged.
+func (a *Adapter) SaveCustomer(ctx context.Context, req billing.SaveCustomerRequest) (string, error) {
+	if req.CustomerID != "" {
+		return req.CustomerID, nil
+	}
+	// PayPal customer IDs are at most 22 characters of [0-9A-Za-z_-]
+	id := uuid.New()
+	return base64.RawURLEncoding.EncodeToString(id[:]), nil
+}
+
+// AttachPaymentMethod saves a payment method the buyer approved through a
+// vault setup token, returning the resulting payment token
+func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerID, token string) (*billing.PaymentMethodDetails, error) {
+	var result *billing.PaymentMethodDetails
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		body := map[string]any{
+			"payment_source": map[string]any{
+				"token": map[string]string{"id": token, "type": "SETUP_TOKEN"},
+			},
+		}
+		var resp paymentToken
+		if err := a.client.do(ctx, http.MethodPost, "/v3/vault/payment-tokens", body, token, &resp); err != nil {
+			a.logger.Error("Failed to save PayPal payment token",
+				zap.Error(err),
+				zap.String("customer_id", customerID))
+			return nil, fmt.Errorf("failed to attach payment method: %w", err)
+		}
+		if resp.Customer.ID != customerID {
+			return nil, fmt.Errorf("failed to attach payment method: setup token belongs to another customer")
+		}
+
+		result = convertPaymentToken(&resp)
+		return result, nil
+	})
+
+	return result, err
+}
+
+// DetachPaymentMethod deletes a vaulted PayPal payment token
+func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		if err := a.client.do(ctx, http.MethodDelete, "/v3/vault/payment-tokens/"+pathEscape(paymentMethodID), nil, "", nil); err != nil {
+			a.logger.Error("Failed to delete PayPal payment token",
+				zap.Error(err),
+				zap.String("payment_method_id", paymentMethodID))
+			return nil, fmt.Errorf("failed to detach payment method: %w", err)
+		}
+		return nil, nil
+	})
+
+	return err
+}
+
+// SetDefaultPaymentMethod is a no-op: PayPal has no default payment method,
+// and charges always name the payment token to use
+func (a *Adapter) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	return nil
+}
+
+// ChargePaymentMethod charges a vaulted payment token by creating an order
+// that PayPal captures immediately. Declines are reported as a failed result
+// rather than an error so that they don't trip the circuit breaker.
+func (a *Adapter) ChargePaymentMethod(ctx context.Context, req billing.ChargeRequest) (*billing.ChargeResult, error) {
+	var result *billing.ChargeResult
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		var token paymentToken
+		if err := a.client.do(ctx, http.MethodGet, "/v3/vault/payment-tokens/"+pathEscape(req.PaymentMethodID), nil, "", &token); err != nil {
+			return nil, fmt.Errorf("failed to charge payment method: %w", err)
+		}
+
+		// Orders name a vaulted card or wallet by the kind of source it is
+		source := &paymentSource{PayPal: &paypalSource{VaultID: req.PaymentMethodID}}
+		if token.PaymentSource.Card != nil {
+			source = &paymentSource{Card: &cardSource{VaultID: req.PaymentMethodID}}
+		}
+		amount := newMoney(req.Amount, req.Currency)
+		o := order{
+			Intent: "CAPTURE",
+			PurchaseUnits: []purchaseUnit{{
+				ReferenceID: "default",
+				CustomID: encodeCustomID(
+					metadataUserID, req.Metadata[metadataUserID],
+					metadataPlanID, req.Metadata[metadataPlanID],
+				),
+				Description: req.Description,
+				Amount:      &amount,
+			}},
+			PaymentSource: source,
+		}
+
+		var resp order
+		if err := a.client.do(ctx, http.MethodPost, "/v2/checkout/orders", o, req.IdempotencyKey, &resp); err != nil {
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+				result = &billing.ChargeResult{Status: billing.ChargeStatusFailed, FailureReason: apiErr.issue()}
+				if apiErr.issue() == "PAYER_ACTION_REQUIRED" {
+					result.Status = billing.ChargeStatusRequiresAction
+				}
+				return result, nil
+			}
+
+			a.logger.Error("Failed to charge PayPal payment token",
+				zap.Error(err),
+				zap.String("customer_id", req.CustomerID),
+				zap.String("payment_method_id", req.PaymentMethodID))
+			return nil, fmt.Errorf("failed to charge payment method: %w", err)
+		}
+
+		result = &billing.ChargeResult{ID: resp.ID, Status: billing.ChargeStatusFailed}
+		switch c := resp.capture(); {
+		case resp.Status == "PAYER_ACTION_REQUIRED":
+			result.Status = billing.ChargeStatusRequiresAction
+		case c != nil:
+			result.ID = c.ID
+			result.Status = captureChargeStatus(c.Status)
+			if result.Status == billing.ChargeStatusFailed && c.StatusDetails != nil {
+				result.FailureReason = c.StatusDetails.Reason
+			}
+		default:
+			result.FailureReason = fmt.Sprintf("order is %s", strings.ToLower(resp.Status))
+		}
+		return result, nil
+	})
+
+	return result, err
+}
+
+// Transaction search limits
+const (
+	defaultListLimit = 100                 // Page size used when a list request sets none
+	maxListLimit     = 500                 // Largest page PayPal returns
+	maxSearchWindow  = 31 * 24 * time.Hour // Longest range one search may cover
+)
+
+// transaction is one row of a transaction search
+type transaction struct {
+	TransactionInfo struct {
+		TransactionID             string `json:"transaction_id"`
+		PayPalReferenceID         string `json:"paypal_reference_id"`
+		PayPalReferenceIDType     string `json:"paypal_reference_id_type"` // ODR for orders, SUB for subscriptions
+		TransactionEventCode      string `json:"transaction_event_code"`
+		TransactionInitiationDate string `json:"transaction_initiation_date"`
+		TransactionAmount         *money `json:"transaction_amount"`
+		TransactionStatus         string `json:"transaction_status"` // S, P, D, F or V
+		CustomField               string `json:"custom_field"`
+	} `json:"transaction_info"`
+	PayerInfo struct {
+		AccountID string `json:"account_id"`
+	} `json:"payer_info"`
+}
+
+type transactionPage struct {
+	TransactionDetails []transaction `json:"transaction_details"`
+	Page               int           `json:"page"`
+	TotalPages         int           `json:"total_pages"`
+}
+
+// ListCharges lists the payments received in a window through transaction
+// search. The cursor is the next page number. PayPal only searches 31 days
+// at a time, and transactions can take up to three hours to appear.
+func (a *Adapter) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	var result *billing.ChargePage
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		txPage, next, err := a.searchTransactions(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list charges: %w", err)
+		}
+
+		page := &billing.ChargePage{Charges: []billing.Charge{}, NextCursor: next}
+		for _, tx := range txPage.TransactionDetails {
+			if charge, ok := convertTransaction(tx); ok && inWindow(charge.CreatedAt, req) {
+				page.Charges = append(page.Charges, charge)
+			}
+		}
+
+		result = page
+		return result, nil
+	})
+
+	return result, err
+}
+
+// ListSubscriptions lists the subscriptions created in a window. PayPal
+// cannot list subscriptions, so they are found through the payments made on
+// them in the window; a subscription that has never been paid is not listed.
+func (a *Adapter) ListSubscriptions(ctx context.Context, req billing.ListRequest) (*billing.SubscriptionPage, error) {
+	var result *billing.SubscriptionPage
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		txPage, next, err := a.searchTransactions(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+
+		page := &billing.SubscriptionPage{Subscriptions: []billing.Subscription{}, NextCursor: next}
+		seen := make(map[string]bool)
+		for _, tx := range txPage.TransactionDetails {
+			info := tx.TransactionInfo
+			if info.PayPalReferenceIDType != "SUB" || seen[info.PayPalReferenceID] {
+				continue
+			}
+			seen[info.PayPalReferenceID] = true
+
+			sub, err := a.getSubscription(ctx, info.PayPalReferenceID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+			}
+			if s := convertSubscription(sub); inWindow(s.CreatedAt, req) {
+				page.Subscriptions = append(page.Subscriptions, s)
+			}
+		}
+
+		result = page
+		return result, nil
+	})
+
+	return result, err
+}
+
+// Close closes the PayPal adapter
+func (a *Adapter) Close() error {
+	a.client.httpClient.CloseIdleConnections()
+	a.logger.Info("PayPal adapter closed")
+	return nil
+}
+
+// getOrder retrieves an order
+func (a *Adapter) getOrder(ctx context.Context, id string) (*order, error) {
+	var o order
+	if err := a.client.do(ctx, http.MethodGet, "/v2/checkout/orders/"+pathEscape(id), nil, "", &o); err != nil {
+		a.logger.Error("Failed to retrieve PayPal order", zap.Error(err), zap.String("order_id", id))
+		return nil, err
+	}
+	return &o, nil
+}
+
+// getSubscription retrieves a subscription
+func (a *Adapter) getSubscription(ctx context.Context, id string) (*subscription, error) {
+	var sub subscription
+	if err := a.client.do(ctx, http.MethodGet, "/v1/billing/subscriptions/"+pathEscape(id), nil, "", &sub); err != nil {
+		a.logger.Error("Failed to retrieve PayPal subscription", zap.Error(err), zap.String("subscription_id", id))
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// searchTransactions returns one page of the transactions in a list
+// request's window, and the cursor of the next page
+func (a *Adapter) searchTransactions(ctx context.Context, req billing.ListRequest) (*transactionPage, string, error) {
+	end := req.CreatedBefore
+	if end.IsZero() {
+		end = a.now()
+	}
+	start := req.CreatedAfter
+	if start.IsZero() {
+		start = end.Add(-maxSearchWindow)
+	}
+	if end.Sub(start) > maxSearchWindow {
+		return nil, "", fmt.Errorf("PayPal searches at most 31 days of transactions, got %s to %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	pageNumber := 1
+	if req.Cursor != "" {
+		n, err := strconv.Atoi(req.Cursor)
+		if err != nil || n < 1 {
+			return nil, "", fmt.Errorf("invalid cursor %q", req.Cursor)
+		}
+		pageNumber = n
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	query := url.Values{
+		"start_date": {start.UTC().Format(time.RFC3339)},
+		"end_date":   {end.UTC().Format(time.RFC3339)},
+		"fields":     {"transaction_info,payer_info"},
+		"page_size":  {strconv.Itoa(limit)},
+		"page":       {strconv.Itoa(pageNumber)},
+	}
+	var page transactionPage
+	if err := a.client.do(ctx, http.MethodGet, "/v1/reporting/transactions?"+query.Encode(), nil, "", &page); err != nil {
+		a.logger.Error("Failed to search PayPal transactions", zap.Error(err))
+		return nil, "", err
+	}
+
+	next := ""
+	if page.TotalPages > pageNumber {
+		next = strconv.Itoa(pageNumber + 1)
+	}
+	return &page, next, nil
+}
+
+// convertTransaction converts a received payment to a charge. Other
+// transactions, such as refunds and fees, are skipped.
+func convertTransaction(tx transaction) (billing.Charge, bool) {
+	info := tx.TransactionInfo
+	// T00xx event codes are payments received
+	if !strings.HasPrefix(info.TransactionEventCode, "T00") {
+		return billing.Charge{}, false
+	}
+
+	values := decodeCustomID(info.CustomField)
+	charge := billing.Charge{
+		ID:         info.TransactionID,
+		CustomerID: tx.PayerInfo.AccountID,
+		UserID:     values.Get(metadataUserID),
+		PlanID:     values.Get(metadataPlanID),
+		Amount:     info.TransactionAmount.amount(),
+		CreatedAt:  parseTime(info.TransactionInitiationDate),
+	}
+	if info.TransactionAmount != nil {
+		charge.Currency = info.TransactionAmount.CurrencyCode
+	}
+	if familyID := values.Get(metadataFamilyID); familyID != "" {
+		charge.FamilyID = &familyID
+	}
+	switch info.PayPalReferenceIDType {
+	case "ODR":
+		charge.SessionID = info.PayPalReferenceID
+	case "SUB":
+		charge.SubscriptionID = info.PayPalReferenceID
+	}
+	switch info.TransactionStatus {
+	case "S":
+		charge.Status = billing.ChargeStatusSucceeded
+	case "P":
+		charge.Status = billing.ChargeStatusPending
+	case "V":
+		charge.Status = billing.ChargeStatusRefunded
+	default: // D (denied) or F (failed)
+		charge.Status = billing.ChargeStatusFailed
+	}
+
+	return charge, true
+}
+
+// inWindow reports whether a time falls in a list request's window
+func inWindow(t time.Time, req billing.ListRequest) bool {
+	if !req.CreatedAfter.IsZero() && t.Before(req.CreatedAfter) {
+		return false
+	}
+	return req.CreatedBefore.IsZero() || t.Before(req.CreatedBefore)
+}
+
+// isSubscriptionID reports whether a checkout session ID is a subscription;
+// PayPal subscription IDs start with "I-"
+func isSubscriptionID(id string) bool {
+	return strings.HasPrefix(id, "I-")
+}
+
+// sessionExpiry caps a requested expiry at the time PayPal stops accepting
+// approval, defaulting to it when none is requested
+func sessionExpiry(requested, created time.Time) time.Time {
+	latest := created.Add(sessionLifetime)
+	if requested.IsZero() || requested.After(latest) {
+		return latest
+	}
+	return requested
+}
+
+// Helper function to safely get string value from pointer
+func getStringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
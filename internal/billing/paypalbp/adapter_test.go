@@ -0,0 +1,262 @@
+package paypalbp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+func TestAdapter_CheckoutOrder(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+	familyID := "family-1"
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{
+		PlanID:     uuid.New(),
+		UserID:     "user-1",
+		FamilyID:   &familyID,
+		SuccessURL: "https://jia.app/success",
+		CancelURL:  "https://jia.app/cancel",
+		BasePrice:  9.99,
+		Currency:   "usd",
+		ExpiresAt:  time.Now().Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(resp.URL, resp.SessionID) {
+		t.Errorf("expected the approval link of %s, got %q", resp.SessionID, resp.URL)
+	}
+	if time.Until(resp.ExpiresAt) > sessionLifetime {
+		t.Errorf("expected the expiry to be capped at PayPal's %s, got %v", sessionLifetime, resp.ExpiresAt)
+	}
+	if amount := fake.orders[resp.SessionID].unit().Amount; amount.Value != "9.99" || amount.CurrencyCode != "USD" {
+		t.Errorf("unexpected order amount %+v", amount)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusOpen) || session.Metadata["user_id"] != "user-1" || session.Metadata["family_id"] != "family-1" {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	if fake.tokenCalls != 1 {
+		t.Errorf("expected the access token to be reused, requested %d times", fake.tokenCalls)
+	}
+}
+
+func TestAdapter_CancelSession(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), UserID: "user-1", BasePrice: 5, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adapter.CancelSession(ctx, resp.SessionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusCancelled) || session.Metadata["user_id"] != "user-1" {
+		t.Errorf("expected a cancelled session keeping its metadata, got %+v", session)
+	}
+
+	// The buyer approves anyway; the order must not be captured
+	approved := fake.approve(resp.SessionID)
+	if _, err := adapter.ParseWebhook(ctx, webhookEvent(t, "CHECKOUT.ORDER.APPROVED", approved)); err == nil {
+		t.Error("expected an approved order of a cancelled checkout to be refused")
+	}
+	if fake.orders[resp.SessionID].Status == "COMPLETED" {
+		t.Error("expected the order not to be captured")
+	}
+
+	fake.captureUnit(fake.orders[resp.SessionID])
+	if err := adapter.CancelSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected an error cancelling a completed order")
+	}
+}
+
+func TestAdapter_CheckoutSubscription(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{
+		PlanID:   uuid.New(),
+		UserID:   "user-1",
+		Currency: "USD",
+		Metadata: map[string]string{PlanMetadataKey: "P-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !isSubscriptionID(resp.SessionID) || fake.subscriptions[resp.SessionID].PlanID != "P-123" {
+		t.Fatalf("expected a subscription on P-123, got %s", resp.SessionID)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(billing.SessionStatusOpen) || session.Metadata["paypal_plan_id"] != "P-123" {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	fake.subscriptions[resp.SessionID].Status = "ACTIVE"
+	if err := adapter.CancelSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected an error cancelling an active subscription")
+	}
+}
+
+func TestAdapter_Vault(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+
+	customerID, err := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(customerID) > 22 {
+		t.Errorf("expected a customer ID PayPal accepts, got %q", customerID)
+	}
+	if id, _ := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{CustomerID: customerID}); id != customerID {
+		t.Errorf("expected existing customers to keep their ID, got %q", id)
+	}
+
+	pm, err := adapter.AttachPaymentMethod(ctx, customerID, customerID+":card")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pm.Type != "credit_card" || pm.Brand != "visa" || pm.Last4 != "1111" || pm.ExpYear != 2030 || pm.ExpMonth != 4 {
+		t.Errorf("unexpected payment method %+v", pm)
+	}
+	if _, err := adapter.AttachPaymentMethod(ctx, customerID, "someone-else:card"); err == nil {
+		t.Error("expected an error attaching another customer's setup token")
+	}
+
+	req := billing.ChargeRequest{CustomerID: customerID, PaymentMethodID: pm.ID, Amount: 12.5, Currency: "USD", IdempotencyKey: "renewal-1"}
+	charge, err := adapter.ChargePaymentMethod(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if charge.Status != billing.ChargeStatusSucceeded || !strings.HasPrefix(charge.ID, "CAPTURE") {
+		t.Errorf("unexpected charge %+v", charge)
+	}
+	if again, _ := adapter.ChargePaymentMethod(ctx, req); again == nil || again.ID != charge.ID {
+		t.Errorf("expected a retry with the same idempotency key to return the first charge, got %+v", again)
+	}
+
+	fake.decline = "INSTRUMENT_DECLINED"
+	req.IdempotencyKey = "renewal-2"
+	declined, err := adapter.ChargePaymentMethod(ctx, req)
+	if err != nil {
+		t.Fatalf("expected a decline to be a result, got %v", err)
+	}
+	if declined.Status != billing.ChargeStatusFailed || declined.FailureReason != "INSTRUMENT_DECLINED" {
+		t.Errorf("unexpected declined charge %+v", declined)
+	}
+
+	if err := adapter.DetachPaymentMethod(ctx, pm.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := fake.tokens[pm.ID]; ok {
+		t.Error("expected the payment token to be deleted")
+	}
+}
+
+func TestAdapter_ListChargesAndSubscriptions(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tx := func(id, refType, ref, code, status string, at time.Time) transaction {
+		var t transaction
+		t.TransactionInfo.TransactionID = id
+		t.TransactionInfo.PayPalReferenceIDType = refType
+		t.TransactionInfo.PayPalReferenceID = ref
+		t.TransactionInfo.TransactionEventCode = code
+		t.TransactionInfo.TransactionStatus = status
+		t.TransactionInfo.TransactionInitiationDate = at.Format(time.RFC3339)
+		t.TransactionInfo.TransactionAmount = &money{CurrencyCode: "USD", Value: "9.99"}
+		t.TransactionInfo.CustomField = encodeCustomID(metadataUserID, "user-"+id, metadataPlanID, "premium")
+		return t
+	}
+	fake.transactions = []transaction{
+		tx("TX1", "ODR", "ORDER1", "T0006", "S", day.Add(time.Hour)),
+		tx("TX2", "SUB", "I-SUB1", "T0002", "P", day.Add(2*time.Hour)),
+		tx("TX3", "", "", "T1107", "S", day.Add(3*time.Hour)), // A refund, not a charge
+		tx("TX4", "SUB", "I-SUB1", "T0002", "S", day.Add(4*time.Hour)),
+	}
+	fake.subscriptions["I-SUB1"] = &subscription{ID: "I-SUB1", Status: "ACTIVE", CustomID: encodeCustomID(metadataUserID, "user-1"), CreateTime: day.Add(time.Hour).Format(time.RFC3339)}
+
+	req := billing.ListRequest{CreatedAfter: day, CreatedBefore: day.Add(24 * time.Hour), Limit: 2}
+	first, err := adapter.ListCharges(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Charges) != 2 || first.NextCursor != "2" {
+		t.Fatalf("expected a first page of two charges, got %+v", first)
+	}
+	if c := first.Charges[0]; c.SessionID != "ORDER1" || c.Status != billing.ChargeStatusSucceeded || c.UserID != "user-TX1" || c.Amount != 9.99 {
+		t.Errorf("unexpected order charge %+v", c)
+	}
+	if c := first.Charges[1]; c.SubscriptionID != "I-SUB1" || c.Status != billing.ChargeStatusPending {
+		t.Errorf("unexpected subscription charge %+v", c)
+	}
+
+	req.Cursor = first.NextCursor
+	second, err := adapter.ListCharges(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Charges) != 1 || second.Charges[0].ID != "TX4" || second.NextCursor != "" {
+		t.Errorf("expected the refund to be skipped on the last page, got %+v", second)
+	}
+
+	subs, err := adapter.ListSubscriptions(ctx, billing.ListRequest{CreatedAfter: day, CreatedBefore: day.Add(24 * time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(subs.Subscriptions) != 1 || subs.Subscriptions[0].ID != "I-SUB1" || subs.Subscriptions[0].Status != "active" {
+		t.Errorf("expected the paid subscription once, got %+v", subs.Subscriptions)
+	}
+
+	if _, err := adapter.ListCharges(ctx, billing.ListRequest{CreatedAfter: day, CreatedBefore: day.AddDate(0, 2, 0)}); err == nil {
+		t.Error("expected an error searching more than 31 days")
+	}
+}
+
+func TestCustomID(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	customID := encodeCustomID(metadataUserID, "user-1", metadataPlanID, "premium", metadataFamilyID, long)
+	if len(customID) > maxCustomIDLength {
+		t.Fatalf("expected at most %d characters, got %d", maxCustomIDLength, len(customID))
+	}
+	values := decodeCustomID(customID)
+	if values.Get(metadataUserID) != "user-1" || values.Get(metadataPlanID) != "premium" || values.Get(metadataFamilyID) != "" {
+		t.Errorf("expected the family ID that does not fit to be left out, got %v", values)
+	}
+
+	if cancelled := withCancelled(customID); !isCancelled(cancelled) || decodeCustomID(cancelled).Get(metadataUserID) != "user-1" {
+		t.Errorf("expected the cancelled mark to keep the user, got %q", cancelled)
+	}
+}
+
+func TestNewMoney(t *testing.T) {
+	if m := newMoney(1000.4, "jpy"); m.Value != "1000" || m.CurrencyCode != "JPY" {
+		t.Errorf("expected a whole yen amount, got %+v", m)
+	}
+	if m := newMoney(19.999, "USD"); m.Value != "20.00" {
+		t.Errorf("expected two decimals, got %+v", m)
+	}
+}
@@ -0,0 +1,172 @@
+package paypalbp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiError is an error response from the PayPal REST API
+type apiError struct {
+	StatusCode int    `json:"-"`
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+	DebugID    string `json:"debug_id"`
+	Details    []struct {
+		Issue       string `json:"issue"`
+		Description string `json:"description"`
+	} `json:"details"`
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("paypal: %d %s: %s", e.StatusCode, e.Name, e.Message)
+	if issue := e.issue(); issue != "" {
+		msg += " (" + issue + ")"
+	}
+	if e.DebugID != "" {
+		msg += " [debug_id " + e.DebugID + "]"
+	}
+	return msg
+}
+
+// issue returns the first detailed issue, e.g. INSTRUMENT_DECLINED
+func (e *apiError) issue() string {
+	if len(e.Details) == 0 {
+		return ""
+	}
+	return e.Details[0].Issue
+}
+
+// client calls the PayPal REST API with an OAuth access token obtained from
+// the app's client credentials and cached until shortly before it expires
+type client struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// tokenRefreshMargin is how long before expiry an access token is replaced
+const tokenRefreshMargin = time.Minute
+
+// accessToken returns a cached access token, requesting a new one when the
+// cached token is missing or about to expire
+func (c *client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Before(c.tokenExpiry.Add(-tokenRefreshMargin)) {
+		return c.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build token request: %w", err)
+	}
+	req.SetBasicAuth(c.clientID, c.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"` // Seconds
+	}
+	if err := c.send(req, &token); err != nil {
+		return "", fmt.Errorf("failed to obtain access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("failed to obtain access token: empty token")
+	}
+
+	c.token = token.AccessToken
+	c.tokenExpiry = c.now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out, which may
+// be nil. A non-empty requestID is sent as PayPal-Request-Id, which makes
+// retries of the same POST return the original result.
+func (c *client) do(ctx context.Context, method, path string, body any, requestID string, out any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Prefer", "return=representation")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if requestID != "" {
+		req.Header.Set("PayPal-Request-Id", requestID)
+	}
+
+	err = c.send(req, out)
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized {
+		// The token was revoked or expired early; fetch a new one next time
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+	}
+	return err
+}
+
+// send executes a request, turning error responses into *apiError
+func (c *client) send(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("paypal: %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("paypal: failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Name == "" {
+			apiErr.Name = http.StatusText(resp.StatusCode)
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return apiErr
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("paypal: failed to decode response: %w", err)
+	}
+	return nil
+}
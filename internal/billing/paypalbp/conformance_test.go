@@ -0,0 +1,78 @@
+package paypalbp
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/conformance"
+)
+
+// paypalFixtures drives the fake PayPal API behind the adapter under test
+// and signs webhooks with the key of the adapter's pinned certificate
+type paypalFixtures struct {
+	t      *testing.T
+	fake   *fakePayPal
+	key    *rsa.PrivateKey
+	forger *rsa.PrivateKey
+}
+
+func (f paypalFixtures) CompleteCheckout(sessionID string) (conformance.Webhook, error) {
+	f.fake.mu.Lock()
+	_, ok := f.fake.orders[sessionID]
+	f.fake.mu.Unlock()
+	if !ok {
+		return conformance.Webhook{}, fmt.Errorf("no such order: %s", sessionID)
+	}
+	payload := webhookEvent(f.t, "CHECKOUT.ORDER.APPROVED", f.fake.approve(sessionID))
+	return f.signed(f.key, payload, time.Now()), nil
+}
+
+func (f paypalFixtures) ExpireSession(sessionID string) error {
+	f.fake.mu.Lock()
+	defer f.fake.mu.Unlock()
+	o, ok := f.fake.orders[sessionID]
+	if !ok {
+		return fmt.Errorf("no such order: %s", sessionID)
+	}
+	o.CreateTime = time.Now().Add(-sessionLifetime - time.Hour).UTC().Format(time.RFC3339)
+	return nil
+}
+
+func (f paypalFixtures) UnknownEvent() (conformance.Webhook, error) {
+	payload := webhookEvent(f.t, "CATALOG.PRODUCT.CREATED", map[string]string{"id": "PROD-1"})
+	return f.signed(f.key, payload, time.Now()), nil
+}
+
+func (f paypalFixtures) SignedAt(payload []byte, at time.Time) (conformance.Webhook, bool, error) {
+	return f.signed(f.key, payload, at), true, nil
+}
+
+func (f paypalFixtures) Forge(payload []byte) (conformance.Webhook, error) {
+	return f.signed(f.forger, payload, time.Now()), nil
+}
+
+func (f paypalFixtures) signed(key *rsa.PrivateKey, payload []byte, at time.Time) conformance.Webhook {
+	return conformance.Webhook{Payload: payload, Signature: SignatureFromHeaders(sign(f.t, key, "WH-1", payload, at))}
+}
+
+func TestAdapter_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (billing.Provider, conformance.Fixtures) {
+		key, certPEM := newSigningCert(t, time.Now())
+		cert, err := ParseCertificate(certPEM)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		forger, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("failed to generate key: %v", err)
+		}
+
+		fake, adapter := newFakePayPal(t)
+		adapter.webhookCert = cert
+		return adapter, paypalFixtures{t: t, fake: fake, key: key, forger: forger}
+	})
+}
@@ -0,0 +1,339 @@
+package paypalbp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// fakePayPal is an in-memory stand-in for the PayPal REST API, serving the
+// endpoints the adapter calls
+type fakePayPal struct {
+	t *testing.T
+
+	mu            sync.Mutex
+	orders        map[string]*order
+	subscriptions map[string]*subscription
+	tokens        map[string]*paymentToken
+	transactions  []transaction
+	requestIDs    map[string]string // PayPal-Request-Id to the ID of the resource it created
+	tokenCalls    int
+	nextID        int
+	decline       string // Issue returned when capturing or charging, if set
+}
+
+// newFakePayPal starts a fake PayPal API and an adapter that talks to it
+func newFakePayPal(t *testing.T) (*fakePayPal, *Adapter) {
+	t.Helper()
+	f := &fakePayPal{
+		t:             t,
+		orders:        make(map[string]*order),
+		subscriptions: make(map[string]*subscription),
+		tokens:        make(map[string]*paymentToken),
+		requestIDs:    make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/oauth2/token", f.token)
+	mux.HandleFunc("POST /v2/checkout/orders", f.authed(f.createOrder))
+	mux.HandleFunc("GET /v2/checkout/orders/{id}", f.authed(f.getOrder))
+	mux.HandleFunc("PATCH /v2/checkout/orders/{id}", f.authed(f.patchOrder))
+	mux.HandleFunc("POST /v2/checkout/orders/{id}/capture", f.authed(f.captureOrder))
+	mux.HandleFunc("POST /v1/billing/subscriptions", f.authed(f.createSubscription))
+	mux.HandleFunc("GET /v1/billing/subscriptions/{id}", f.authed(f.getSubscription))
+	mux.HandleFunc("PATCH /v1/billing/subscriptions/{id}", f.authed(f.patchSubscription))
+	mux.HandleFunc("POST /v3/vault/payment-tokens", f.authed(f.createToken))
+	mux.HandleFunc("GET /v3/vault/payment-tokens/{id}", f.authed(f.getToken))
+	mux.HandleFunc("DELETE /v3/vault/payment-tokens/{id}", f.authed(f.deleteToken))
+	mux.HandleFunc("GET /v1/reporting/transactions", f.authed(f.searchTransactions))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	adapter := NewAdapter(Config{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		BaseURL:      server.URL,
+		WebhookID:    "WH-1",
+	}, zap.NewNop())
+	// Keep failures in one test from opening the shared breaker for others
+	adapter.circuitBreaker = circuitbreaker.NewCircuitBreaker(circuitbreaker.ExternalAPIConfig)
+	return f, adapter
+}
+
+func (f *fakePayPal) token(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "client" || pass != "secret" {
+		writeError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+	f.mu.Lock()
+	f.tokenCalls++
+	f.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"access_token": "A21", "expires_in": 32400})
+}
+
+// authed rejects requests without the access token
+func (f *fakePayPal) authed(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer A21" {
+			writeError(w, http.StatusUnauthorized, "AUTHENTICATION_FAILURE", "")
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		next(w, r)
+	}
+}
+
+func (f *fakePayPal) id(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s%d", prefix, f.nextID)
+}
+
+// replay answers a POST retried with the same PayPal-Request-Id with the
+// resource it created the first time
+func (f *fakePayPal) replay(w http.ResponseWriter, r *http.Request) (string, bool) {
+	requestID := r.Header.Get("PayPal-Request-Id")
+	if requestID == "" {
+		return "", false
+	}
+	id, ok := f.requestIDs[requestID]
+	return id, ok
+}
+
+func (f *fakePayPal) remember(r *http.Request, id string) {
+	if requestID := r.Header.Get("PayPal-Request-Id"); requestID != "" {
+		f.requestIDs[requestID] = id
+	}
+}
+
+func (f *fakePayPal) createOrder(w http.ResponseWriter, r *http.Request) {
+	if id, ok := f.replay(w, r); ok {
+		writeJSON(w, http.StatusOK, f.orders[id])
+		return
+	}
+
+	var o order
+	f.decode(r, &o)
+	o.ID = f.id("ORDER")
+	o.CreateTime = time.Now().UTC().Format(time.RFC3339)
+
+	// Orders paid with a vaulted payment method are captured immediately
+	if source := o.PaymentSource; source != nil && (source.Card != nil || (source.PayPal != nil && source.PayPal.ExperienceContext == nil)) {
+		if f.decline != "" {
+			writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", f.decline)
+			return
+		}
+		f.captureUnit(&o)
+	} else {
+		o.Status = "PAYER_ACTION_REQUIRED"
+		o.Links = []link{{Href: "https://www.sandbox.paypal.com/checkoutnow?token=" + o.ID, Rel: "payer-action"}}
+	}
+
+	f.orders[o.ID] = &o
+	f.remember(r, o.ID)
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (f *fakePayPal) captureUnit(o *order) {
+	unit := o.unit()
+	unit.Payments = &struct {
+		Captures []capture `json:"captures"`
+	}{Captures: []capture{{ID: f.id("CAPTURE"), Status: "COMPLETED", Amount: unit.Amount, CustomID: unit.CustomID}}}
+	o.Status = "COMPLETED"
+}
+
+func (f *fakePayPal) getOrder(w http.ResponseWriter, r *http.Request) {
+	o, ok := f.orders[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (f *fakePayPal) patchOrder(w http.ResponseWriter, r *http.Request) {
+	o, ok := f.orders[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	var patch []map[string]string
+	f.decode(r, &patch)
+	for _, op := range patch {
+		if op["op"] == "replace" && strings.HasSuffix(op["path"], "/custom_id") {
+			o.unit().CustomID = op["value"]
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakePayPal) captureOrder(w http.ResponseWriter, r *http.Request) {
+	o, ok := f.orders[r.PathValue("id")]
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+	case o.Status == "COMPLETED":
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "ORDER_ALREADY_CAPTURED")
+	case o.Status != "APPROVED":
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "ORDER_NOT_APPROVED")
+	case f.decline != "":
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", f.decline)
+	default:
+		f.captureUnit(o)
+		o.PaymentSource = &paymentSource{PayPal: &paypalSource{
+			EmailAddress: "buyer@example.com",
+			Name:         &name{GivenName: "Ada", Surname: "Lovelace"},
+			Address:      &address{CountryCode: "GB"},
+		}}
+		writeJSON(w, http.StatusCreated, o)
+	}
+}
+
+// approve simulates the buyer approving an order
+func (f *fakePayPal) approve(id string) *order {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	o := f.orders[id]
+	o.Status = "APPROVED"
+	copied := *o
+	return &copied
+}
+
+func (f *fakePayPal) createSubscription(w http.ResponseWriter, r *http.Request) {
+	var sub subscription
+	f.decode(r, &sub)
+	sub.ID = f.id("I-SUB")
+	sub.Status = "APPROVAL_PENDING"
+	sub.CreateTime = time.Now().UTC().Format(time.RFC3339)
+	sub.Links = []link{{Href: "https://www.sandbox.paypal.com/webapps/billing/subscriptions?ba_token=" + sub.ID, Rel: "approve"}}
+	sub.ApplicationContext = nil
+	f.subscriptions[sub.ID] = &sub
+	writeJSON(w, http.StatusCreated, sub)
+}
+
+func (f *fakePayPal) getSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := f.subscriptions[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (f *fakePayPal) patchSubscription(w http.ResponseWriter, r *http.Request) {
+	sub, ok := f.subscriptions[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	var patch []map[string]string
+	f.decode(r, &patch)
+	for _, op := range patch {
+		if op["op"] == "replace" && op["path"] == "/custom_id" {
+			sub.CustomID = op["value"]
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakePayPal) createToken(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		PaymentSource struct {
+			Token struct {
+				ID   string `json:"id"`
+				Type string `json:"type"`
+			} `json:"token"`
+		} `json:"payment_source"`
+	}
+	f.decode(r, &body)
+
+	// Setup tokens are named "<customer>:<card|paypal>" in these tests
+	customerID, kind, ok := strings.Cut(body.PaymentSource.Token.ID, ":")
+	if !ok || body.PaymentSource.Token.Type != "SETUP_TOKEN" {
+		writeError(w, http.StatusUnprocessableEntity, "UNPROCESSABLE_ENTITY", "TOKEN_NOT_APPROVED")
+		return
+	}
+	token := &paymentToken{ID: f.id("TOKEN")}
+	token.Customer.ID = customerID
+	if kind == "card" {
+		token.PaymentSource.Card = &cardSource{Brand: "VISA", LastDigits: "1111", Expiry: "2030-04", Type: "CREDIT"}
+	} else {
+		token.PaymentSource.PayPal = &paypalSource{EmailAddress: "buyer@example.com"}
+	}
+	f.tokens[token.ID] = token
+	writeJSON(w, http.StatusCreated, token)
+}
+
+func (f *fakePayPal) getToken(w http.ResponseWriter, r *http.Request) {
+	token, ok := f.tokens[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	writeJSON(w, http.StatusOK, token)
+}
+
+func (f *fakePayPal) deleteToken(w http.ResponseWriter, r *http.Request) {
+	if _, ok := f.tokens[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "RESOURCE_NOT_FOUND", "INVALID_RESOURCE_ID")
+		return
+	}
+	delete(f.tokens, r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakePayPal) searchTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, err1 := time.Parse(time.RFC3339, q.Get("start_date"))
+	end, err2 := time.Parse(time.RFC3339, q.Get("end_date"))
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", "INVALID_DATE")
+		return
+	}
+
+	var matched []transaction
+	for _, tx := range f.transactions {
+		at := parseTime(tx.TransactionInfo.TransactionInitiationDate)
+		if !at.Before(start) && !at.After(end) {
+			matched = append(matched, tx)
+		}
+	}
+
+	var size, page int
+	fmt.Sscan(q.Get("page_size"), &size)
+	fmt.Sscan(q.Get("page"), &page)
+	total := (len(matched) + size - 1) / size
+	from := min((page-1)*size, len(matched))
+	to := min(from+size, len(matched))
+	writeJSON(w, http.StatusOK, transactionPage{TransactionDetails: matched[from:to], Page: page, TotalPages: total})
+}
+
+func (f *fakePayPal) decode(r *http.Request, v any) {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		f.t.Errorf("fake PayPal: malformed %s %s body: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, name, issue string) {
+	body := map[string]any{"name": name, "message": "fake PayPal error", "debug_id": "dbg"}
+	if issue != "" {
+		body["details"] = []map[string]string{{"issue": issue}}
+	}
+	writeJSON(w, status, body)
+}
@@ -0,0 +1,485 @@
+package paypalbp
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// money is a PayPal amount, a decimal string in the currency's minor unit precision
+type money struct {
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+}
+
+// amount returns the value in dollars (or the currency's major unit)
+func (m *money) amount() float64 {
+	if m == nil {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(m.Value, 64)
+	return v
+}
+
+// zeroDecimalCurrencies are the currencies PayPal rejects fractional amounts for
+var zeroDecimalCurrencies = map[string]bool{"HUF": true, "JPY": true, "TWD": true}
+
+// newMoney formats an amount in dollars (or the currency's major unit)
+func newMoney(amount float64, currency string) money {
+	currency = strings.ToUpper(currency)
+	if zeroDecimalCurrencies[currency] {
+		return money{CurrencyCode: currency, Value: strconv.FormatFloat(math.Round(amount), 'f', 0, 64)}
+	}
+	return money{CurrencyCode: currency, Value: strconv.FormatFloat(amount, 'f', 2, 64)}
+}
+
+type link struct {
+	Href   string `json:"href"`
+	Rel    string `json:"rel"`
+	Method string `json:"method,omitempty"`
+}
+
+// approveURL returns the link a buyer follows to approve an order or subscription
+func approveURL(links []link) string {
+	for _, l := range links {
+		if l.Rel == "approve" || l.Rel == "payer-action" {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+type name struct {
+	GivenName string `json:"given_name,omitempty"`
+	Surname   string `json:"surname,omitempty"`
+	FullName  string `json:"full_name,omitempty"`
+}
+
+func (n *name) String() string {
+	if n == nil {
+		return ""
+	}
+	if n.FullName != "" {
+		return n.FullName
+	}
+	return strings.TrimSpace(n.GivenName + " " + n.Surname)
+}
+
+type address struct {
+	AddressLine1 string `json:"address_line_1,omitempty"`
+	AddressLine2 string `json:"address_line_2,omitempty"`
+	AdminArea2   string `json:"admin_area_2,omitempty"` // City
+	AdminArea1   string `json:"admin_area_1,omitempty"` // State or province
+	PostalCode   string `json:"postal_code,omitempty"`
+	CountryCode  string `json:"country_code,omitempty"`
+}
+
+type experienceContext struct {
+	ReturnURL          string `json:"return_url,omitempty"`
+	CancelURL          string `json:"cancel_url,omitempty"`
+	UserAction         string `json:"user_action,omitempty"`
+	ShippingPreference string `json:"shipping_preference,omitempty"`
+}
+
+type paypalSource struct {
+	VaultID           string             `json:"vault_id,omitempty"`
+	EmailAddress      string             `json:"email_address,omitempty"`
+	Name              *name              `json:"name,omitempty"`
+	Address           *address           `json:"address,omitempty"`
+	ExperienceContext *experienceContext `json:"experience_context,omitempty"`
+}
+
+type cardSource struct {
+	VaultID    string `json:"vault_id,omitempty"`
+	Brand      string `json:"brand,omitempty"`
+	LastDigits string `json:"last_digits,omitempty"`
+	Expiry     string `json:"expiry,omitempty"` // YYYY-MM
+	Type       string `json:"type,omitempty"`   // CREDIT, DEBIT or PREPAID
+}
+
+type paymentSource struct {
+	PayPal *paypalSource `json:"paypal,omitempty"`
+	Card   *cardSource   `json:"card,omitempty"`
+}
+
+type capture struct {
+	ID                        string `json:"id"`
+	Status                    string `json:"status"`
+	Amount                    *money `json:"amount,omitempty"`
+	CustomID                  string `json:"custom_id,omitempty"`
+	CreateTime                string `json:"create_time,omitempty"`
+	SellerReceivableBreakdown *struct {
+		PayPalFee *money `json:"paypal_fee,omitempty"`
+	} `json:"seller_receivable_breakdown,omitempty"`
+	StatusDetails *struct {
+		Reason string `json:"reason"`
+	} `json:"status_details,omitempty"`
+	SupplementaryData *struct {
+		RelatedIDs struct {
+			OrderID string `json:"order_id"`
+		} `json:"related_ids"`
+	} `json:"supplementary_data,omitempty"`
+}
+
+type purchaseUnit struct {
+	ReferenceID string `json:"reference_id,omitempty"`
+	CustomID    string `json:"custom_id,omitempty"`
+	Description string `json:"description,omitempty"`
+	Amount      *money `json:"amount,omitempty"`
+	Payments    *struct {
+		Captures []capture `json:"captures"`
+	} `json:"payments,omitempty"`
+}
+
+type order struct {
+	ID            string         `json:"id,omitempty"`
+	Status        string         `json:"status,omitempty"`
+	Intent        string         `json:"intent,omitempty"`
+	PurchaseUnits []purchaseUnit `json:"purchase_units"`
+	PaymentSource *paymentSource `json:"payment_source,omitempty"`
+	Links         []link         `json:"links,omitempty"`
+	CreateTime    string         `json:"create_time,omitempty"`
+	UpdateTime    string         `json:"update_time,omitempty"`
+}
+
+// unit returns the order's purchase unit; checkout orders have exactly one
+func (o *order) unit() *purchaseUnit {
+	if len(o.PurchaseUnits) == 0 {
+		return &purchaseUnit{}
+	}
+	return &o.PurchaseUnits[0]
+}
+
+// capture returns the order's capture, if it has been captured
+func (o *order) capture() *capture {
+	if p := o.unit().Payments; p != nil && len(p.Captures) > 0 {
+		return &p.Captures[0]
+	}
+	return nil
+}
+
+type applicationContext struct {
+	ReturnURL          string `json:"return_url,omitempty"`
+	CancelURL          string `json:"cancel_url,omitempty"`
+	UserAction         string `json:"user_action,omitempty"`
+	ShippingPreference string `json:"shipping_preference,omitempty"`
+}
+
+type subscription struct {
+	ID                 string              `json:"id,omitempty"`
+	PlanID             string              `json:"plan_id"`
+	Status             string              `json:"status,omitempty"`
+	CustomID           string              `json:"custom_id,omitempty"`
+	ApplicationContext *applicationContext `json:"application_context,omitempty"`
+	Subscriber         *struct {
+		Name         *name    `json:"name,omitempty"`
+		EmailAddress string   `json:"email_address,omitempty"`
+		PayerID      string   `json:"payer_id,omitempty"`
+		Address      *address `json:"address,omitempty"`
+	} `json:"subscriber,omitempty"`
+	BillingInfo *struct {
+		LastPayment *struct {
+			Amount *money `json:"amount"`
+			Time   string `json:"time"`
+		} `json:"last_payment,omitempty"`
+		NextBillingTime     string `json:"next_billing_time,omitempty"`
+		FailedPaymentsCount int    `json:"failed_payments_count,omitempty"`
+	} `json:"billing_info,omitempty"`
+	Links      []link `json:"links,omitempty"`
+	CreateTime string `json:"create_time,omitempty"`
+	UpdateTime string `json:"update_time,omitempty"`
+}
+
+// sale is a payment made on a subscription
+type sale struct {
+	ID                 string `json:"id"`
+	State              string `json:"state"`
+	BillingAgreementID string `json:"billing_agreement_id"`
+	CustomID           string `json:"custom"`
+	Amount             struct {
+		Total    string `json:"total"`
+		Currency string `json:"currency"`
+	} `json:"amount"`
+	TransactionFee *struct {
+		Value string `json:"value"`
+	} `json:"transaction_fee,omitempty"`
+}
+
+type paymentToken struct {
+	ID       string `json:"id"`
+	Customer struct {
+		ID string `json:"id"`
+	} `json:"customer"`
+	PaymentSource paymentSource `json:"payment_source"`
+}
+
+// Metadata keys packed into an order's or subscription's custom_id
+const (
+	metadataUserID    = "user_id"
+	metadataPlanID    = "plan_id"
+	metadataFamilyID  = "family_id"
+	metadataCancelled = "cancelled" // Set by CancelSession; approved orders carrying it are not captured
+)
+
+// maxCustomIDLength is the longest custom_id PayPal accepts
+const maxCustomIDLength = 127
+
+// encodeCustomID packs metadata into a custom_id, in the order given.
+// Values that would make it longer than PayPal allows are left out; the
+// recorded checkout session is authoritative, and custom_id only lets
+// webhooks and reports be matched without it.
+func encodeCustomID(pairs ...string) string {
+	values := url.Values{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] == "" {
+			continue
+		}
+		values.Set(pairs[i], pairs[i+1])
+		if len(values.Encode()) > maxCustomIDLength {
+			values.Del(pairs[i])
+		}
+	}
+	return values.Encode()
+}
+
+// decodeCustomID unpacks a custom_id written by encodeCustomID
+func decodeCustomID(customID string) url.Values {
+	values, err := url.ParseQuery(customID)
+	if err != nil {
+		return url.Values{}
+	}
+	return values
+}
+
+// isCancelled reports whether a custom_id was marked by CancelSession
+func isCancelled(customID string) bool {
+	return decodeCustomID(customID).Get(metadataCancelled) != ""
+}
+
+// withCancelled returns a custom_id marked as cancelled, making room for
+// the mark by dropping the family ID if needed
+func withCancelled(customID string) string {
+	values := decodeCustomID(customID)
+	return encodeCustomID(
+		metadataCancelled, "1",
+		metadataUserID, values.Get(metadataUserID),
+		metadataPlanID, values.Get(metadataPlanID),
+		metadataFamilyID, values.Get(metadataFamilyID),
+	)
+}
+
+// parseTime parses a PayPal timestamp, returning the zero time for empty or
+// malformed values
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// orderSessionStatus maps an order status to a checkout session status
+func orderSessionStatus(status string) billing.SessionStatus {
+	switch status {
+	case "COMPLETED":
+		return billing.SessionStatusComplete
+	case "VOIDED":
+		return billing.SessionStatusCancelled
+	default: // CREATED, SAVED, APPROVED, PAYER_ACTION_REQUIRED
+		return billing.SessionStatusOpen
+	}
+}
+
+// subscriptionSessionStatus maps a subscription status to a checkout session
+// status; a subscription's checkout is complete once the buyer approves it
+func subscriptionSessionStatus(status string) billing.SessionStatus {
+	switch status {
+	case "APPROVAL_PENDING":
+		return billing.SessionStatusOpen
+	case "EXPIRED":
+		return billing.SessionStatusExpired
+	case "CANCELLED":
+		return billing.SessionStatusCancelled
+	default: // APPROVED, ACTIVE, SUSPENDED
+		return billing.SessionStatusComplete
+	}
+}
+
+// normalizeSubscriptionStatus maps a subscription to the statuses used for
+// local subscriptions
+func normalizeSubscriptionStatus(sub *subscription) string {
+	switch sub.Status {
+	case "ACTIVE":
+		if sub.BillingInfo != nil && sub.BillingInfo.FailedPaymentsCount > 0 {
+			return "past_due"
+		}
+		return "active"
+	case "SUSPENDED":
+		return "suspended"
+	case "CANCELLED":
+		return "cancelled"
+	default: // APPROVAL_PENDING, APPROVED, EXPIRED
+		return "expired"
+	}
+}
+
+// captureChargeStatus maps a capture status to a charge status
+func captureChargeStatus(status string) billing.ChargeStatus {
+	switch status {
+	case "COMPLETED":
+		return billing.ChargeStatusSucceeded
+	case "PENDING":
+		return billing.ChargeStatusPending
+	case "REFUNDED", "PARTIALLY_REFUNDED":
+		return billing.ChargeStatusRefunded
+	default: // DECLINED, FAILED
+		return billing.ChargeStatusFailed
+	}
+}
+
+// unapprovedExpired reports whether a checkout the buyer has not approved,
+// created at created, has outlived lifetime by now. PayPal stops accepting
+// approval then but leaves the order's status as it was.
+func unapprovedExpired(created time.Time, lifetime time.Duration, now time.Time) bool {
+	return !created.IsZero() && !now.Before(created.Add(lifetime))
+}
+
+// convertOrderSession converts an order to a checkout session
+func convertOrderSession(o *order, lifetime time.Duration, now time.Time) *billing.Session {
+	created := parseTime(o.CreateTime)
+	updated := parseTime(o.UpdateTime)
+	if updated.IsZero() {
+		updated = created
+	}
+	status := orderSessionStatus(o.Status)
+	if status == billing.SessionStatusOpen && isCancelled(o.unit().CustomID) {
+		status = billing.SessionStatusCancelled
+	} else if status == billing.SessionStatusOpen && o.Status != "APPROVED" && unapprovedExpired(created, lifetime, now) {
+		status = billing.SessionStatusExpired
+	}
+	return &billing.Session{
+		ID:        o.ID,
+		Status:    string(status),
+		URL:       approveURL(o.Links),
+		ExpiresAt: created.Add(lifetime),
+		Metadata:  sessionMetadata(o.unit().CustomID, o.Status),
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
+
+// convertSubscriptionSession converts a subscription to a checkout session
+func convertSubscriptionSession(sub *subscription, lifetime time.Duration, now time.Time) *billing.Session {
+	created := parseTime(sub.CreateTime)
+	updated := parseTime(sub.UpdateTime)
+	if updated.IsZero() {
+		updated = created
+	}
+	metadata := sessionMetadata(sub.CustomID, sub.Status)
+	metadata["paypal_plan_id"] = sub.PlanID
+	status := subscriptionSessionStatus(sub.Status)
+	if status == billing.SessionStatusOpen && isCancelled(sub.CustomID) {
+		status = billing.SessionStatusCancelled
+	} else if status == billing.SessionStatusOpen && unapprovedExpired(created, lifetime, now) {
+		status = billing.SessionStatusExpired
+	}
+	return &billing.Session{
+		ID:        sub.ID,
+		Status:    string(status),
+		URL:       approveURL(sub.Links),
+		ExpiresAt: created.Add(lifetime),
+		Metadata:  metadata,
+		CreatedAt: created,
+		UpdatedAt: updated,
+	}
+}
+
+func sessionMetadata(customID, status string) map[string]interface{} {
+	metadata := map[string]interface{}{"paypal_status": status}
+	for k, v := range decodeCustomID(customID) {
+		if len(v) > 0 {
+			metadata[k] = v[0]
+		}
+	}
+	return metadata
+}
+
+// convertSubscription converts a subscription, normalizing its status
+func convertSubscription(sub *subscription) billing.Subscription {
+	values := decodeCustomID(sub.CustomID)
+	result := billing.Subscription{
+		ID:        sub.ID,
+		UserID:    values.Get(metadataUserID),
+		PlanID:    values.Get(metadataPlanID),
+		Status:    normalizeSubscriptionStatus(sub),
+		CreatedAt: parseTime(sub.CreateTime),
+	}
+	if familyID := values.Get(metadataFamilyID); familyID != "" {
+		result.FamilyID = &familyID
+	}
+	if sub.Subscriber != nil {
+		result.CustomerID = sub.Subscriber.PayerID
+	}
+	if info := sub.BillingInfo; info != nil {
+		if info.LastPayment != nil {
+			result.CurrentPeriodStart = parseTime(info.LastPayment.Time)
+		}
+		result.CurrentPeriodEnd = parseTime(info.NextBillingTime)
+	}
+	return result
+}
+
+// convertPaymentToken converts a vaulted payment token
+func convertPaymentToken(token *paymentToken) *billing.PaymentMethodDetails {
+	details := &billing.PaymentMethodDetails{ID: token.ID, Type: "digital_wallet"}
+
+	if card := token.PaymentSource.Card; card != nil {
+		details.Type = "credit_card"
+		if card.Type == "DEBIT" {
+			details.Type = "debit_card"
+		}
+		details.Brand = strings.ToLower(card.Brand)
+		details.Last4 = card.LastDigits
+		if year, month, ok := strings.Cut(card.Expiry, "-"); ok {
+			y, _ := strconv.Atoi(year)
+			m, _ := strconv.Atoi(month)
+			details.ExpYear, details.ExpMonth = int32(y), int32(m)
+		}
+	}
+
+	return details
+}
+
+// convertCustomerDetails converts the payer of an order or subscription
+func convertCustomerDetails(n *name, email string, addr *address) *billing.CustomerDetails {
+	if n == nil && email == "" && addr == nil {
+		return nil
+	}
+	customer := &billing.CustomerDetails{Name: n.String(), Email: email}
+	if addr != nil {
+		customer.Address = billing.CustomerAddress{
+			Line1:      addr.AddressLine1,
+			Line2:      addr.AddressLine2,
+			City:       addr.AdminArea2,
+			Region:     addr.AdminArea1,
+			PostalCode: addr.PostalCode,
+			Country:    addr.CountryCode,
+		}
+	}
+	return customer
+}
+
+// pathEscape escapes a provider ID for use in a URL path
+func pathEscape(id string) string {
+	return url.PathEscape(id)
+}
+
+// errNotCancellable is returned when a checkout can no longer be cancelled
+func errNotCancellable(id, status string) error {
+	return fmt.Errorf("checkout %s cannot be cancelled: it is %s", id, strings.ToLower(status))
+}
@@ -0,0 +1,419 @@
+package paypalbp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// Headers PayPal sends with every webhook to sign it
+const (
+	HeaderTransmissionID   = "Paypal-Transmission-Id"
+	HeaderTransmissionTime = "Paypal-Transmission-Time"
+	HeaderTransmissionSig  = "Paypal-Transmission-Sig"
+	HeaderAuthAlgo         = "Paypal-Auth-Algo"
+	HeaderCertURL          = "Paypal-Cert-Url"
+)
+
+// signatureFields maps the keys of a signature string to the headers they
+// are taken from
+var signatureFields = []struct{ key, header string }{
+	{"transmission_id", HeaderTransmissionID},
+	{"transmission_time", HeaderTransmissionTime},
+	{"transmission_sig", HeaderTransmissionSig},
+	{"auth_algo", HeaderAuthAlgo},
+	{"cert_url", HeaderCertURL},
+}
+
+// signatureTolerance is how far a transmission time may be from now, to
+// prevent replay attacks
+const signatureTolerance = 5 * time.Minute
+
+// authAlgoSHA256RSA is the only signing algorithm PayPal uses
+const authAlgoSHA256RSA = "SHA256withRSA"
+
+// SignatureFromHeaders packs the transmission headers of a PayPal webhook
+// into the signature string ValidateWebhook takes:
+// "transmission_id=…,transmission_time=…,transmission_sig=…,auth_algo=…,cert_url=…"
+func SignatureFromHeaders(h http.Header) string {
+	parts := make([]string, 0, len(signatureFields))
+	for _, f := range signatureFields {
+		if v := h.Get(f.header); v != "" {
+			parts = append(parts, f.key+"="+v)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// transmission holds the signature of one webhook delivery
+type transmission struct {
+	id       string
+	time     string
+	sig      string
+	authAlgo string
+	certURL  string
+}
+
+// parseSignature parses a signature string built by SignatureFromHeaders
+func parseSignature(signature string) (transmission, error) {
+	var t transmission
+	for _, element := range strings.Split(signature, ",") {
+		// Base64 signatures end in '=', so only the first one separates the key
+		key, value, _ := strings.Cut(element, "=")
+		switch key {
+		case "transmission_id":
+			t.id = value
+		case "transmission_time":
+			t.time = value
+		case "transmission_sig":
+			t.sig = value
+		case "auth_algo":
+			t.authAlgo = value
+		case "cert_url":
+			t.certURL = value
+		}
+	}
+	if t.id == "" || t.time == "" || t.sig == "" {
+		return t, fmt.Errorf("invalid signature format")
+	}
+	return t, nil
+}
+
+// transmissionMessage is the message PayPal signs: the transmission ID and
+// time, the webhook ID and the CRC32 of the payload, separated by '|'
+func transmissionMessage(id, sentAt, webhookID string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s|%d", id, sentAt, webhookID, crc32.ChecksumIEEE(payload)))
+}
+
+// ParseCertificate parses the PEM certificate PayPal signs webhooks with
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no PEM certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	if _, ok := cert.PublicKey.(*rsa.PublicKey); !ok {
+		return nil, fmt.Errorf("certificate key is not RSA")
+	}
+	return cert, nil
+}
+
+// ValidateWebhook verifies a PayPal webhook's transmission signature with
+// the locally configured certificate. The certificate URL PayPal sends is
+// not fetched; certificates are rotated by updating the configuration.
+func (a *Adapter) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("missing webhook signature")
+	}
+	if a.webhookCert == nil || a.webhookID == "" {
+		return fmt.Errorf("webhook certificate and webhook ID must be configured")
+	}
+
+	t, err := parseSignature(signature)
+	if err != nil {
+		return err
+	}
+	if t.authAlgo != authAlgoSHA256RSA {
+		return fmt.Errorf("unsupported signing algorithm %q", t.authAlgo)
+	}
+
+	sentAt, err := time.Parse(time.RFC3339, t.time)
+	if err != nil {
+		return fmt.Errorf("invalid transmission time: %w", err)
+	}
+	now := a.now()
+	if d := now.Sub(sentAt); d > signatureTolerance || d < -signatureTolerance {
+		return fmt.Errorf("transmission time %s is outside the %s tolerance", t.time, signatureTolerance)
+	}
+	if now.Before(a.webhookCert.NotBefore) || now.After(a.webhookCert.NotAfter) {
+		return fmt.Errorf("webhook certificate is not valid at %s", now.Format(time.RFC3339))
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(t.sig)
+	if err != nil {
+		return fmt.Errorf("invalid transmission signature encoding: %w", err)
+	}
+	publicKey, ok := a.webhookCert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("webhook certificate key is not RSA")
+	}
+	digest := sha256.Sum256(transmissionMessage(t.id, t.time, a.webhookID, payload))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig); err != nil {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	a.logger.Debug("Validated PayPal webhook signature",
+		zap.String("transmission_id", t.id),
+		zap.String("cert_url", t.certURL))
+	return nil
+}
+
+// event is a PayPal webhook event
+type event struct {
+	ID           string          `json:"id"`
+	EventType    string          `json:"event_type"`
+	ResourceType string          `json:"resource_type"`
+	Resource     json.RawMessage `json:"resource"`
+	CreateTime   string          `json:"create_time"`
+}
+
+// ParseWebhook parses a PayPal webhook event. An approved order is captured
+// here, since PayPal only charges the buyer once the merchant captures; the
+// capture is idempotent by order ID, so redelivered events charge once.
+func (a *Adapter) ParseWebhook(ctx context.Context, payload []byte) (*billing.WebhookResult, error) {
+	var e event
+	if err := json.Unmarshal(payload, &e); err != nil {
+		a.logger.Error("Failed to parse webhook payload", zap.Error(err))
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+	if e.EventType == "" {
+		return nil, fmt.Errorf("failed to parse webhook payload: missing event_type")
+	}
+
+	a.logger.Info("Processing webhook event",
+		zap.String("event_type", e.EventType),
+		zap.String("event_id", e.ID))
+
+	switch e.EventType {
+	case "CHECKOUT.ORDER.APPROVED":
+		return a.handleOrderApproved(ctx, e)
+	case "PAYMENT.CAPTURE.COMPLETED", "PAYMENT.CAPTURE.PENDING", "PAYMENT.CAPTURE.DENIED", "PAYMENT.CAPTURE.DECLINED":
+		return a.handleCapture(e)
+	case "BILLING.SUBSCRIPTION.ACTIVATED", "BILLING.SUBSCRIPTION.UPDATED", "BILLING.SUBSCRIPTION.SUSPENDED",
+		"BILLING.SUBSCRIPTION.PAYMENT.FAILED", "BILLING.SUBSCRIPTION.CANCELLED", "BILLING.SUBSCRIPTION.EXPIRED":
+		return a.handleSubscription(e)
+	case "PAYMENT.SALE.COMPLETED":
+		return a.handleSale(e)
+	default:
+		a.logger.Info("Unhandled webhook event type", zap.String("event_type", e.EventType))
+		return nil, fmt.Errorf("unhandled event type: %s", e.EventType)
+	}
+}
+
+// handleOrderApproved captures an order the buyer approved
+func (a *Adapter) handleOrderApproved(ctx context.Context, e event) (*billing.WebhookResult, error) {
+	var approved order
+	if err := json.Unmarshal(e.Resource, &approved); err != nil {
+		return nil, fmt.Errorf("failed to parse order: %w", err)
+	}
+	if isCancelled(approved.unit().CustomID) {
+		return nil, fmt.Errorf("order %s was approved after its checkout was cancelled; not capturing it", approved.ID)
+	}
+
+	var (
+		captured *order
+		declined string
+	)
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		var o order
+		err := a.client.do(ctx, http.MethodPost, "/v2/checkout/orders/"+pathEscape(approved.ID)+"/capture", struct{}{}, "capture-"+approved.ID, &o)
+		var apiErr *apiError
+		switch {
+		case err == nil:
+			captured = &o
+		case errors.As(err, &apiErr) && apiErr.issue() == "ORDER_ALREADY_CAPTURED":
+			if captured, err = a.getOrder(ctx, approved.ID); err != nil {
+				return nil, fmt.Errorf("failed to retrieve captured order: %w", err)
+			}
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity:
+			// The buyer's funding source was declined; they may approve
+			// the order again with another one
+			declined = apiErr.issue()
+		default:
+			a.logger.Error("Failed to capture PayPal order", zap.Error(err), zap.String("order_id", approved.ID))
+			return nil, fmt.Errorf("failed to capture order: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	customID := approved.unit().CustomID
+	if declined != "" {
+		result := newWebhookResult(customID)
+		result.EventType = string(billing.WebhookEventTypePaymentFailed)
+		result.SessionID = approved.ID
+		result.Status = "failed"
+		result.Amount = approved.unit().Amount.amount()
+		if approved.unit().Amount != nil {
+			result.Currency = approved.unit().Amount.CurrencyCode
+		}
+		result.Metadata = map[string]interface{}{"paypal_order_id": approved.ID, "failure_reason": declined}
+		return result, nil
+	}
+
+	c := captured.capture()
+	if c == nil {
+		return nil, fmt.Errorf("order %s has no capture", approved.ID)
+	}
+	result := captureResult(approved.ID, customID, c)
+	if source := captured.PaymentSource; source != nil && source.PayPal != nil {
+		result.Customer = convertCustomerDetails(source.PayPal.Name, source.PayPal.EmailAddress, source.PayPal.Address)
+	}
+
+	a.logger.Info("Captured PayPal order",
+		zap.String("order_id", approved.ID),
+		zap.String("capture_id", c.ID),
+		zap.String("status", c.Status))
+	return result, nil
+}
+
+// handleCapture handles capture events; the capture's order is the session
+func (a *Adapter) handleCapture(e event) (*billing.WebhookResult, error) {
+	var c capture
+	if err := json.Unmarshal(e.Resource, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse capture: %w", err)
+	}
+	if c.SupplementaryData == nil || c.SupplementaryData.RelatedIDs.OrderID == "" {
+		return nil, fmt.Errorf("capture %s has no order ID", c.ID)
+	}
+	return captureResult(c.SupplementaryData.RelatedIDs.OrderID, c.CustomID, &c), nil
+}
+
+// handleSubscription handles subscription lifecycle events. Activation
+// completes a subscription's checkout.
+func (a *Adapter) handleSubscription(e event) (*billing.WebhookResult, error) {
+	var sub subscription
+	if err := json.Unmarshal(e.Resource, &sub); err != nil {
+		return nil, fmt.Errorf("failed to parse subscription: %w", err)
+	}
+
+	result := newWebhookResult(sub.CustomID)
+	result.SessionID = sub.ID
+	result.SubscriptionID = sub.ID
+	result.Status = normalizeSubscriptionStatus(&sub)
+	result.Metadata = map[string]interface{}{
+		"paypal_subscription_id": sub.ID,
+		"paypal_plan_id":         sub.PlanID,
+		"paypal_status":          sub.Status,
+	}
+	if info := sub.BillingInfo; info != nil && info.LastPayment != nil && info.LastPayment.Amount != nil {
+		result.Amount = info.LastPayment.Amount.amount()
+		result.Currency = info.LastPayment.Amount.CurrencyCode
+	}
+	if s := sub.Subscriber; s != nil {
+		result.Customer = convertCustomerDetails(s.Name, s.EmailAddress, s.Address)
+	}
+
+	switch e.EventType {
+	case "BILLING.SUBSCRIPTION.ACTIVATED":
+		result.EventType = string(billing.WebhookEventTypeCheckoutSessionCompleted)
+		result.Status = "completed"
+	case "BILLING.SUBSCRIPTION.CANCELLED", "BILLING.SUBSCRIPTION.EXPIRED":
+		result.EventType = string(billing.WebhookEventTypeSubscriptionCancelled)
+	default:
+		result.EventType = string(billing.WebhookEventTypeSubscriptionUpdated)
+	}
+	return result, nil
+}
+
+// handleSale handles a recurring payment on a subscription. Renewals carry
+// no session ID: the subscription's ID is its checkout's, which completed
+// with the subscription's activation.
+func (a *Adapter) handleSale(e event) (*billing.WebhookResult, error) {
+	var s sale
+	if err := json.Unmarshal(e.Resource, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse sale: %w", err)
+	}
+	if s.BillingAgreementID == "" {
+		return nil, fmt.Errorf("sale %s is not a subscription payment", s.ID)
+	}
+
+	result := newWebhookResult(s.CustomID)
+	result.EventType = string(billing.WebhookEventTypePaymentSucceeded)
+	result.SubscriptionID = s.BillingAgreementID
+	result.Amount = (&money{Value: s.Amount.Total}).amount()
+	result.Currency = s.Amount.Currency
+	result.Status = "completed"
+	if s.TransactionFee != nil {
+		result.FeeAmount = (&money{Value: s.TransactionFee.Value}).amount()
+	}
+	result.Metadata = map[string]interface{}{
+		"paypal_sale_id":         s.ID,
+		"paypal_subscription_id": s.BillingAgreementID,
+	}
+	return result, nil
+}
+
+// captureResult converts a capture of an order to a webhook result
+func captureResult(orderID, customID string, c *capture) *billing.WebhookResult {
+	result := newWebhookResult(customID)
+	result.SessionID = orderID
+	result.Amount = c.Amount.amount()
+	if c.Amount != nil {
+		result.Currency = c.Amount.CurrencyCode
+	}
+	if b := c.SellerReceivableBreakdown; b != nil {
+		result.FeeAmount = b.PayPalFee.amount()
+	}
+	result.Metadata = map[string]interface{}{
+		"paypal_order_id":   orderID,
+		"paypal_capture_id": c.ID,
+		"paypal_status":     c.Status,
+	}
+
+	switch captureChargeStatus(c.Status) {
+	case billing.ChargeStatusSucceeded:
+		result.EventType = string(billing.WebhookEventTypeCheckoutSessionCompleted)
+		result.Status = "completed"
+	case billing.ChargeStatusPending:
+		// Settles later with PAYMENT.CAPTURE.COMPLETED
+		result.EventType = string(billing.WebhookEventTypePaymentPending)
+		result.Status = "pending"
+	default:
+		result.EventType = string(billing.WebhookEventTypePaymentFailed)
+		result.Status = "failed"
+		if c.StatusDetails != nil {
+			result.Metadata["failure_reason"] = c.StatusDetails.Reason
+		}
+	}
+	return result
+}
+
+// newWebhookResult starts a webhook result from the metadata in a custom_id
+func newWebhookResult(customID string) *billing.WebhookResult {
+	values := decodeCustomID(customID)
+	result := &billing.WebhookResult{
+		UserID:       values.Get(metadataUserID),
+		PlanIDString: values.Get(metadataPlanID),
+		PlanID:       planUUID(values),
+	}
+	if familyID := values.Get(metadataFamilyID); familyID != "" {
+		result.FamilyID = &familyID
+	}
+	return result
+}
+
+// planUUID returns the plan ID as a UUID, deriving a deterministic one from
+// string plan IDs
+func planUUID(values url.Values) uuid.UUID {
+	planID := values.Get(metadataPlanID)
+	if planID == "" {
+		return uuid.Nil
+	}
+	if parsed, err := uuid.Parse(planID); err == nil {
+		return parsed
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(planID))
+}
@@ -0,0 +1,268 @@
+package paypalbp
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+)
+
+// newSigningCert creates a key and a self-signed certificate standing in
+// for the one PayPal signs webhooks with
+func newSigningCert(t *testing.T, now time.Time) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "messageverificationcerts.paypal.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(365 * 24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// sign returns the transmission headers PayPal would send with a payload
+func sign(t *testing.T, key *rsa.PrivateKey, webhookID string, payload []byte, sentAt time.Time) http.Header {
+	t.Helper()
+	id := uuid.NewString()
+	at := sentAt.UTC().Format(time.RFC3339)
+	digest := sha256.Sum256(transmissionMessage(id, at, webhookID, payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+
+	h := http.Header{}
+	h.Set(HeaderTransmissionID, id)
+	h.Set(HeaderTransmissionTime, at)
+	h.Set(HeaderTransmissionSig, base64.StdEncoding.EncodeToString(sig))
+	h.Set(HeaderAuthAlgo, authAlgoSHA256RSA)
+	h.Set(HeaderCertURL, "https://api.paypal.com/v1/notifications/certs/CERT-360caa42")
+	return h
+}
+
+// webhookEvent builds a webhook payload carrying a resource
+func webhookEvent(t *testing.T, eventType string, resource any) []byte {
+	t.Helper()
+	data, err := json.Marshal(resource)
+	if err != nil {
+		t.Fatalf("failed to marshal resource: %v", err)
+	}
+	payload, err := json.Marshal(event{ID: "WH-EVT-" + uuid.NewString(), EventType: eventType, Resource: data})
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	return payload
+}
+
+func TestAdapter_ValidateWebhook(t *testing.T) {
+	now := time.Now()
+	key, certPEM := newSigningCert(t, now)
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, adapter := newFakePayPal(t)
+	adapter.webhookCert = cert
+	adapter.now = func() time.Time { return now }
+	ctx := context.Background()
+	payload := []byte(`{"id":"WH-1","event_type":"PAYMENT.CAPTURE.COMPLETED"}`)
+
+	signature := SignatureFromHeaders(sign(t, key, "WH-1", payload, now))
+	if err := adapter.ValidateWebhook(ctx, payload, signature); err != nil {
+		t.Errorf("expected a valid signature, got %v", err)
+	}
+
+	tests := map[string]string{
+		"missing":          "",
+		"tampered payload": signature,
+		"other webhook":    SignatureFromHeaders(sign(t, key, "WH-2", payload, now)),
+		"replayed":         SignatureFromHeaders(sign(t, key, "WH-1", payload, now.Add(-10*time.Minute))),
+		"malformed":        "transmission_id=1",
+		"unsupported algo": SignatureFromHeaders(func() http.Header {
+			h := sign(t, key, "WH-1", payload, now)
+			h.Set(HeaderAuthAlgo, "SHA1withRSA")
+			return h
+		}()),
+		"garbled signature": "transmission_id=1,transmission_time=" + now.UTC().Format(time.RFC3339) + ",transmission_sig=!!,auth_algo=SHA256withRSA",
+	}
+	for name, sig := range tests {
+		body := payload
+		if name == "tampered payload" {
+			body = []byte(`{"id":"WH-1","event_type":"PAYMENT.CAPTURE.DENIED"}`)
+		}
+		if err := adapter.ValidateWebhook(ctx, body, sig); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	adapter.now = func() time.Time { return cert.NotAfter.Add(time.Minute) }
+	late := SignatureFromHeaders(sign(t, key, "WH-1", payload, cert.NotAfter.Add(time.Minute)))
+	if err := adapter.ValidateWebhook(ctx, payload, late); err == nil {
+		t.Error("expected an error for an expired certificate")
+	}
+
+	adapter.webhookCert = nil
+	if err := adapter.ValidateWebhook(ctx, payload, signature); err == nil {
+		t.Error("expected an error without a configured certificate")
+	}
+
+	if _, err := ParseCertificate([]byte("not a certificate")); err == nil {
+		t.Error("expected an error parsing a malformed certificate")
+	}
+}
+
+func TestAdapter_ParseWebhookOrderApproved(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+	planID := uuid.New()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: planID, UserID: "user-1", BasePrice: 9.99, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload := webhookEvent(t, "CHECKOUT.ORDER.APPROVED", fake.approve(resp.SessionID))
+
+	result, err := adapter.ParseWebhook(ctx, payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EventType != string(billing.WebhookEventTypeCheckoutSessionCompleted) || result.SessionID != resp.SessionID || result.Status != "completed" {
+		t.Errorf("expected a completed checkout, got %+v", result)
+	}
+	if result.UserID != "user-1" || result.PlanID != planID || result.Amount != 9.99 || result.Currency != "USD" {
+		t.Errorf("unexpected checkout details %+v", result)
+	}
+	if result.Customer == nil || result.Customer.Name != "Ada Lovelace" || result.Customer.Address.Country != "GB" {
+		t.Errorf("expected the buyer's details, got %+v", result.Customer)
+	}
+	if fake.orders[resp.SessionID].Status != "COMPLETED" {
+		t.Error("expected the order to be captured")
+	}
+
+	// A redelivered event finds the order already captured
+	again, err := adapter.ParseWebhook(ctx, payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Metadata["paypal_capture_id"] != result.Metadata["paypal_capture_id"] {
+		t.Errorf("expected the same capture, got %v and %v", again.Metadata, result.Metadata)
+	}
+}
+
+func TestAdapter_ParseWebhookOrderDeclined(t *testing.T) {
+	fake, adapter := newFakePayPal(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), UserID: "user-1", BasePrice: 9.99, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fake.decline = "INSTRUMENT_DECLINED"
+
+	result, err := adapter.ParseWebhook(ctx, webhookEvent(t, "CHECKOUT.ORDER.APPROVED", fake.approve(resp.SessionID)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EventType != string(billing.WebhookEventTypePaymentFailed) || result.Metadata["failure_reason"] != "INSTRUMENT_DECLINED" {
+		t.Errorf("expected a failed payment, got %+v", result)
+	}
+}
+
+func TestAdapter_ParseWebhookEvents(t *testing.T) {
+	_, adapter := newFakePayPal(t)
+	ctx := context.Background()
+	customID := encodeCustomID(metadataUserID, "user-1", metadataPlanID, "premium", metadataFamilyID, "family-1")
+
+	pending := capture{ID: "CAP-1", Status: "PENDING", Amount: &money{CurrencyCode: "EUR", Value: "5.00"}, CustomID: customID}
+	pending.SupplementaryData = &struct {
+		RelatedIDs struct {
+			OrderID string `json:"order_id"`
+		} `json:"related_ids"`
+	}{}
+	pending.SupplementaryData.RelatedIDs.OrderID = "ORDER-1"
+	completed := pending
+	completed.Status = "COMPLETED"
+	completed.SellerReceivableBreakdown = &struct {
+		PayPalFee *money `json:"paypal_fee,omitempty"`
+	}{PayPalFee: &money{CurrencyCode: "EUR", Value: "0.50"}}
+
+	activated := subscription{ID: "I-SUB1", PlanID: "P-1", Status: "ACTIVE", CustomID: customID}
+	cancelled := activated
+	cancelled.Status = "CANCELLED"
+
+	renewal := sale{ID: "SALE-1", State: "completed", BillingAgreementID: "I-SUB1", CustomID: customID}
+	renewal.Amount.Total = "9.99"
+	renewal.Amount.Currency = "USD"
+
+	tests := []struct {
+		eventType string
+		resource  any
+		want      billing.WebhookEventType
+		sessionID string
+	}{
+		{"PAYMENT.CAPTURE.PENDING", pending, billing.WebhookEventTypePaymentPending, "ORDER-1"},
+		{"PAYMENT.CAPTURE.COMPLETED", completed, billing.WebhookEventTypeCheckoutSessionCompleted, "ORDER-1"},
+		{"BILLING.SUBSCRIPTION.ACTIVATED", activated, billing.WebhookEventTypeCheckoutSessionCompleted, "I-SUB1"},
+		{"BILLING.SUBSCRIPTION.CANCELLED", cancelled, billing.WebhookEventTypeSubscriptionCancelled, "I-SUB1"},
+		{"PAYMENT.SALE.COMPLETED", renewal, billing.WebhookEventTypePaymentSucceeded, ""},
+	}
+	for _, tt := range tests {
+		result, err := adapter.ParseWebhook(ctx, webhookEvent(t, tt.eventType, tt.resource))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.eventType, err)
+			continue
+		}
+		if result.EventType != string(tt.want) || result.SessionID != tt.sessionID {
+			t.Errorf("%s: expected %s for %s, got %s for %s", tt.eventType, tt.want, tt.sessionID, result.EventType, result.SessionID)
+		}
+		if result.UserID != "user-1" || result.PlanIDString != "premium" || result.FamilyID == nil || *result.FamilyID != "family-1" {
+			t.Errorf("%s: expected the checkout metadata, got %+v", tt.eventType, result)
+		}
+	}
+
+	result, err := adapter.ParseWebhook(ctx, webhookEvent(t, "PAYMENT.SALE.COMPLETED", renewal))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.SubscriptionID != "I-SUB1" {
+		t.Errorf("expected the renewal to name its subscription, got %+v", result)
+	}
+
+	result, err = adapter.ParseWebhook(ctx, webhookEvent(t, "PAYMENT.CAPTURE.COMPLETED", completed))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FeeAmount != 0.5 || result.Amount != 5 {
+		t.Errorf("expected the amount and PayPal's fee, got %+v", result)
+	}
+
+	if _, err := adapter.ParseWebhook(ctx, webhookEvent(t, "CUSTOMER.DISPUTE.CREATED", map[string]string{})); err == nil {
+		t.Error("expected an error for an unhandled event type")
+	}
+	if _, err := adapter.ParseWebhook(ctx, []byte("not json")); err == nil {
+		t.Error("expected an error for a malformed payload")
+	}
+}
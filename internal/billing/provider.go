@@ -2,11 +2,15 @@ package billing
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrNotSupported is returned by providers for operations their API does not offer
+var ErrNotSupported = errors.New("not supported by the billing provider")
+
 // Provider defines the interface for billing providers
 type Provider interface {
 	// CreateCheckoutSession creates a checkout session for a plan
@@ -24,6 +28,27 @@ type Provider interface {
 	// ParseWebhook parses webhook payload into a WebhookResult
 	ParseWebhook(ctx context.Context, payload []byte) (*WebhookResult, error)
 
+	// SaveCustomer creates or updates a customer and returns the provider's customer ID
+	SaveCustomer(ctx context.Context, req SaveCustomerRequest) (string, error)
+
+	// AttachPaymentMethod attaches a tokenized payment method to a customer
+	AttachPaymentMethod(ctx context.Context, customerID, token string) (*PaymentMethodDetails, error)
+
+	// DetachPaymentMethod detaches a payment method from its customer
+	DetachPaymentMethod(ctx context.Context, paymentMethodID string) error
+
+	// SetDefaultPaymentMethod sets the payment method a customer is charged with by default
+	SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error
+
+	// ChargePaymentMethod charges a saved payment method without the customer present
+	ChargePaymentMethod(ctx context.Context, req ChargeRequest) (*ChargeResult, error)
+
+	// ListCharges lists one page of the charges created in a time window
+	ListCharges(ctx context.Context, req ListRequest) (*ChargePage, error)
+
+	// ListSubscriptions lists one page of the subscriptions created in a time window
+	ListSubscriptions(ctx context.Context, req ListRequest) (*SubscriptionPage, error)
+
 	// Close closes the provider connection
 	Close() error
 }
@@ -38,7 +63,12 @@ type CreateCheckoutSessionRequest struct {
 	CountryCode string            `json:"country_code,omitempty"` // ISO country code for pricing
 	BasePrice   float64           `json:"base_price"`             // Base price in dollars
 	Currency    string            `json:"currency"`               // Currency code
+	ExpiresAt   time.Time         `json:"expires_at,omitempty"`   // Requested expiry; zero uses the provider's default
 	Metadata    map[string]string `json:"metadata,omitempty"`
+
+	CustomerID       string `json:"customer_id,omitempty"`       // Provider customer to check out as (optional)
+	PaymentMethodID  string `json:"payment_method_id,omitempty"` // Saved payment method to offer first (optional)
+	CustomerProvider string `json:"customer_provider,omitempty"` // Provider CustomerID and PaymentMethodID belong to (optional)
 }
 
 // CreateCheckoutSessionResponse represents the response from creating a checkout session
@@ -46,6 +76,7 @@ type CreateCheckoutSessionResponse struct {
 	SessionID string    `json:"session_id"`
 	URL       string    `json:"url"`
 	ExpiresAt time.Time `json:"expires_at"`
+	Provider  string    `json:"provider,omitempty"` // Provider the session was created with, set by routing providers
 }
 
 // Session represents a checkout session
@@ -61,12 +92,12 @@ type Session struct {
 
 // WebhookResult represents the result of a billing webhook
 type WebhookResult struct {
+	Provider       string                 `json:"provider,omitempty"` // Provider the webhook came from, when known
 	EventType      string                 `json:"event_type"`
 	SessionID      string                 `json:"session_id"`
 	SubscriptionID string                 `json:"subscription_id"`
 	UserID         string                 `json:"user_id"`
 	FamilyID       *string                `json:"family_id,omitempty"`
-	FeatureCode    string                 `json:"feature_code"`
 	PlanID         uuid.UUID              `json:"plan_id"`
 	PlanIDString   string                 `json:"plan_id_string"` // Original plan ID string for database
 	Amount         float64                `json:"amount"`
@@ -74,6 +105,128 @@ type WebhookResult struct {
 	Status         string                 `json:"status"`
 	ExpiresAt      *time.Time             `json:"expires_at,omitempty"`
 	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	Customer       *CustomerDetails       `json:"customer,omitempty"`        // Buyer details collected at checkout
+	DiscountAmount float64                `json:"discount_amount,omitempty"` // Total discount in dollars
+	TaxAmount      float64                `json:"tax_amount,omitempty"`      // Total tax in dollars
+	FeeAmount      float64                `json:"fee_amount,omitempty"`      // Provider's processing fee in dollars, when reported
+	Reference      string                 `json:"reference,omitempty"`       // Provider's ID of the refund or dispute a payment.refunded or payment.chargeback reports
+}
+
+// CustomerDetails holds the billing details a buyer entered at checkout
+type CustomerDetails struct {
+	Name    string          `json:"name,omitempty"`
+	Email   string          `json:"email,omitempty"`
+	Address CustomerAddress `json:"address"`
+	TaxID   string          `json:"tax_id,omitempty"`
+}
+
+// CustomerAddress represents a buyer's postal address
+type CustomerAddress struct {
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// SaveCustomerRequest represents a customer to create or update
+type SaveCustomerRequest struct {
+	CustomerID string          `json:"customer_id,omitempty"` // Provider customer ID; empty creates a customer
+	UserID     string          `json:"user_id"`
+	Name       string          `json:"name,omitempty"`
+	Email      string          `json:"email,omitempty"`
+	Address    CustomerAddress `json:"address"`
+	TaxID      string          `json:"tax_id,omitempty"`
+}
+
+// PaymentMethodDetails describes a payment method tokenized by the provider
+type PaymentMethodDetails struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`            // credit_card, debit_card, bank_transfer or digital_wallet
+	Brand    string `json:"brand,omitempty"` // e.g. "visa"
+	Last4    string `json:"last4,omitempty"`
+	ExpMonth int32  `json:"exp_month,omitempty"`
+	ExpYear  int32  `json:"exp_year,omitempty"`
+}
+
+// ChargeRequest represents an off-session charge of a saved payment method
+type ChargeRequest struct {
+	CustomerID      string            `json:"customer_id"`
+	PaymentMethodID string            `json:"payment_method_id"`
+	Amount          float64           `json:"amount"` // Amount in dollars
+	Currency        string            `json:"currency"`
+	Description     string            `json:"description,omitempty"`
+	IdempotencyKey  string            `json:"idempotency_key"` // Retrying with the same key never charges twice
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+// ChargeResult represents the outcome of a charge. A declined charge is a
+// result, not an error.
+type ChargeResult struct {
+	ID            string       `json:"id"`
+	Status        ChargeStatus `json:"status"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+}
+
+// ChargeStatus represents the status of a charge
+type ChargeStatus string
+
+const (
+	ChargeStatusSucceeded      ChargeStatus = "succeeded"
+	ChargeStatusFailed         ChargeStatus = "failed"
+	ChargeStatusRequiresAction ChargeStatus = "requires_action" // The customer must authenticate the payment
+	ChargeStatusPending        ChargeStatus = "pending"         // The payment has not settled yet
+	ChargeStatusRefunded       ChargeStatus = "refunded"
+)
+
+// ListRequest selects one page of provider records created in [CreatedAfter, CreatedBefore)
+type ListRequest struct {
+	CreatedAfter  time.Time `json:"created_after"`
+	CreatedBefore time.Time `json:"created_before"`
+	Cursor        string    `json:"cursor,omitempty"` // NextCursor of the previous page; empty for the first page
+	Limit         int       `json:"limit,omitempty"`  // Page size; zero uses the provider's default
+}
+
+// Charge is a charge as recorded by the billing provider
+type Charge struct {
+	ID             string       `json:"id"`
+	SessionID      string       `json:"session_id,omitempty"`      // Checkout session the charge was paid through
+	SubscriptionID string       `json:"subscription_id,omitempty"` // Subscription the charge renewed
+	CustomerID     string       `json:"customer_id,omitempty"`
+	UserID         string       `json:"user_id,omitempty"` // From the metadata set at checkout
+	FamilyID       *string      `json:"family_id,omitempty"`
+	PlanID         string       `json:"plan_id,omitempty"`
+	Amount         float64      `json:"amount"` // Amount in dollars
+	Currency       string       `json:"currency"`
+	Status         ChargeStatus `json:"status"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// ChargePage is one page of charges
+type ChargePage struct {
+	Charges    []Charge `json:"charges"`
+	NextCursor string   `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// Subscription is a subscription as recorded by the billing provider
+type Subscription struct {
+	ID                 string    `json:"id"`
+	CustomerID         string    `json:"customer_id,omitempty"`
+	UserID             string    `json:"user_id,omitempty"`
+	FamilyID           *string   `json:"family_id,omitempty"`
+	PlanID             string    `json:"plan_id,omitempty"`
+	Status             string    `json:"status"` // Normalized to active, past_due, suspended, cancelled or expired
+	CurrentPeriodStart time.Time `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time `json:"current_period_end"`
+	CancelAtPeriodEnd  bool      `json:"cancel_at_period_end"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// SubscriptionPage is one page of subscriptions
+type SubscriptionPage struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	NextCursor    string         `json:"next_cursor,omitempty"` // Empty on the last page
 }
 
 // SessionStatus represents the status of a checkout session
@@ -93,6 +246,9 @@ const (
 	WebhookEventTypeCheckoutSessionCompleted WebhookEventType = "checkout.session.completed"
 	WebhookEventTypePaymentSucceeded         WebhookEventType = "payment.succeeded"
 	WebhookEventTypePaymentFailed            WebhookEventType = "payment.failed"
+	WebhookEventTypePaymentPending           WebhookEventType = "payment.pending" // Payment accepted but not settled yet
+	WebhookEventTypePaymentRefunded          WebhookEventType = "payment.refunded"
+	WebhookEventTypePaymentChargeback        WebhookEventType = "payment.chargeback" // The buyer's bank reversed the payment
 	WebhookEventTypeSubscriptionCreated      WebhookEventType = "subscription.created"
 	WebhookEventTypeSubscriptionUpdated      WebhookEventType = "subscription.updated"
 	WebhookEventTypeSubscriptionCancelled    WebhookEventType = "subscription.cancelled"
@@ -0,0 +1,280 @@
+package billing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// Route is a provider a Router can send calls to, under the name recorded
+// on the sessions, payments and customers it creates
+type Route struct {
+	Name     string
+	Provider Provider
+}
+
+// RoutingRule picks the providers a checkout is tried with. Empty criteria
+// match anything; a rule matches when every non-empty criterion does.
+type RoutingRule struct {
+	Countries  []string // ISO country codes of the pricing zone
+	Currencies []string // ISO currency codes
+	Plans      []string // Plan IDs or plan codes
+	Providers  []string // Route names, tried in order
+}
+
+// matches reports whether a checkout request meets the rule's criteria
+func (r RoutingRule) matches(req CreateCheckoutSessionRequest) bool {
+	return matchesAny(r.Countries, req.CountryCode) &&
+		matchesAny(r.Currencies, req.Currency) &&
+		(matchesAny(r.Plans, req.PlanID.String()) || matchesAny(r.Plans, req.Metadata["plan_code"]))
+}
+
+// matchesAny reports whether value is one of values, ignoring case. An empty
+// list matches anything.
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Router is a Provider that holds several providers and picks one per
+// checkout by country, currency and plan, failing over to the next when a
+// provider's circuit breaker is open. Calls about existing sessions,
+// customers and payment methods go to the provider named in the context by
+// WithProviderName, or to the first route when none is named.
+type Router struct {
+	routes []Route
+	byName map[string]Provider
+	rules  []RoutingRule
+}
+
+// NewRouter creates a router over routes, the first being the default. The
+// first rule matching a checkout decides the providers it is tried with;
+// checkouts no rule matches try every route in order.
+func NewRouter(routes []Route, rules []RoutingRule) (*Router, error) {
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("router needs at least one provider")
+	}
+
+	byName := make(map[string]Provider, len(routes))
+	for _, route := range routes {
+		if route.Name == "" || route.Provider == nil {
+			return nil, fmt.Errorf("route needs a name and a provider")
+		}
+		if _, ok := byName[route.Name]; ok {
+			return nil, fmt.Errorf("duplicate route %q", route.Name)
+		}
+		byName[route.Name] = route.Provider
+	}
+	for i, rule := range rules {
+		if len(rule.Providers) == 0 {
+			return nil, fmt.Errorf("routing rule %d names no providers", i)
+		}
+		for _, name := range rule.Providers {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("routing rule %d names unknown provider %q", i, name)
+			}
+		}
+	}
+
+	return &Router{routes: routes, byName: byName, rules: rules}, nil
+}
+
+// Handles reports whether name is one of the router's providers
+func (r *Router) Handles(name string) bool {
+	_, ok := r.byName[name]
+	return ok
+}
+
+// candidates returns the route names a checkout is tried with, in order
+func (r *Router) candidates(req CreateCheckoutSessionRequest) []string {
+	for _, rule := range r.rules {
+		if rule.matches(req) {
+			return rule.Providers
+		}
+	}
+	names := make([]string, 0, len(r.routes))
+	for _, route := range r.routes {
+		names = append(names, route.Name)
+	}
+	return names
+}
+
+// route returns the provider named in the context, or the default
+func (r *Router) route(ctx context.Context) (Provider, error) {
+	name := ProviderNameFromContext(ctx)
+	if name == "" {
+		return r.routes[0].Provider, nil
+	}
+	provider, ok := r.byName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown billing provider %q", name)
+	}
+	return provider, nil
+}
+
+// CreateCheckoutSession implements Provider. The session is created with
+// the first candidate provider whose circuit breaker is not open, and the
+// response names it. The customer and saved payment method are only passed
+// on to the provider they belong to.
+func (r *Router) CreateCheckoutSession(ctx context.Context, req CreateCheckoutSessionRequest) (*CreateCheckoutSessionResponse, error) {
+	var errs []error
+	for _, name := range r.candidates(req) {
+		routed := req
+		if req.CustomerProvider != name {
+			routed.CustomerID = ""
+			routed.PaymentMethodID = ""
+		}
+
+		resp, err := r.byName[name].CreateCheckoutSession(ctx, routed)
+		if err == nil {
+			resp.Provider = name
+			return resp, nil
+		}
+		if !errors.Is(err, circuitbreaker.ErrOpenState) {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+	return nil, fmt.Errorf("no billing provider available: %w", errors.Join(errs...))
+}
+
+// GetSession implements Provider
+func (r *Router) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.GetSession(ctx, sessionID)
+}
+
+// CancelSession implements Provider
+func (r *Router) CancelSession(ctx context.Context, sessionID string) error {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return err
+	}
+	return provider.CancelSession(ctx, sessionID)
+}
+
+// ValidateWebhook implements Provider
+func (r *Router) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return err
+	}
+	return provider.ValidateWebhook(ctx, payload, signature)
+}
+
+// ParseWebhook implements Provider
+func (r *Router) ParseWebhook(ctx context.Context, payload []byte) (*WebhookResult, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ParseWebhook(ctx, payload)
+}
+
+// SaveCustomer implements Provider
+func (r *Router) SaveCustomer(ctx context.Context, req SaveCustomerRequest) (string, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return "", err
+	}
+	return provider.SaveCustomer(ctx, req)
+}
+
+// AttachPaymentMethod implements Provider
+func (r *Router) AttachPaymentMethod(ctx context.Context, customerID, token string) (*PaymentMethodDetails, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.AttachPaymentMethod(ctx, customerID, token)
+}
+
+// DetachPaymentMethod implements Provider
+func (r *Router) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return err
+	}
+	return provider.DetachPaymentMethod(ctx, paymentMethodID)
+}
+
+// SetDefaultPaymentMethod implements Provider
+func (r *Router) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return err
+	}
+	return provider.SetDefaultPaymentMethod(ctx, customerID, paymentMethodID)
+}
+
+// ChargePaymentMethod implements Provider
+func (r *Router) ChargePaymentMethod(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ChargePaymentMethod(ctx, req)
+}
+
+// ListCharges implements Provider
+func (r *Router) ListCharges(ctx context.Context, req ListRequest) (*ChargePage, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListCharges(ctx, req)
+}
+
+// ListSubscriptions implements Provider
+func (r *Router) ListSubscriptions(ctx context.Context, req ListRequest) (*SubscriptionPage, error) {
+	provider, err := r.route(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListSubscriptions(ctx, req)
+}
+
+// Close implements Provider, closing every route
+func (r *Router) Close() error {
+	var errs []error
+	for _, route := range r.routes {
+		if err := route.Provider.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", route.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// providerNameKey is the context key of the provider name set by WithProviderName
+type providerNameKey struct{}
+
+// WithProviderName returns a context naming the provider that calls about
+// existing sessions, customers and payment methods belong to
+func WithProviderName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, providerNameKey{}, name)
+}
+
+// ProviderNameFromContext returns the provider name set by WithProviderName
+func ProviderNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(providerNameKey{}).(string)
+	return name
+}
+
+// Handles reports whether a provider routes calls to the named provider
+func Handles(provider Provider, name string) bool {
+	h, ok := provider.(interface{ Handles(name string) bool })
+	return ok && h.Handles(name)
+}
@@ -0,0 +1,169 @@
+package billing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/billingtest"
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+// routedProvider records the checkouts routed to it and can fail them
+type routedProvider struct {
+	*billingtest.FakeProvider
+	err  error
+	last *billing.CreateCheckoutSessionRequest
+}
+
+func newRoutedProvider() *routedProvider {
+	return &routedProvider{FakeProvider: billingtest.NewFakeProvider()}
+}
+
+func (p *routedProvider) CreateCheckoutSession(ctx context.Context, req billing.CreateCheckoutSessionRequest) (*billing.CreateCheckoutSessionResponse, error) {
+	p.last = &req
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.FakeProvider.CreateCheckoutSession(ctx, req)
+}
+
+func newTestRouter(t *testing.T) (*billing.Router, map[string]*routedProvider) {
+	t.Helper()
+	providers := map[string]*routedProvider{
+		"stripe": newRoutedProvider(),
+		"adyen":  newRoutedProvider(),
+		"paypal": newRoutedProvider(),
+	}
+	router, err := billing.NewRouter([]billing.Route{
+		{Name: "stripe", Provider: providers["stripe"]},
+		{Name: "adyen", Provider: providers["adyen"]},
+		{Name: "paypal", Provider: providers["paypal"]},
+	}, []billing.RoutingRule{
+		{Countries: []string{"NL", "DE"}, Currencies: []string{"EUR"}, Providers: []string{"adyen", "stripe"}},
+		{Plans: []string{"family_annual"}, Providers: []string{"paypal"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return router, providers
+}
+
+func TestRouter_CreateCheckoutSession(t *testing.T) {
+	router, _ := newTestRouter(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		req     billing.CreateCheckoutSessionRequest
+		routeTo string
+	}{
+		{"country and currency", billing.CreateCheckoutSessionRequest{CountryCode: "nl", Currency: "eur"}, "adyen"},
+		{"country without its currency", billing.CreateCheckoutSessionRequest{CountryCode: "NL", Currency: "USD"}, "stripe"},
+		{"plan code", billing.CreateCheckoutSessionRequest{CountryCode: "US", Currency: "USD", Metadata: map[string]string{"plan_code": "family_annual"}}, "paypal"},
+		{"no rule", billing.CreateCheckoutSessionRequest{CountryCode: "US", Currency: "USD"}, "stripe"},
+	}
+	for _, tt := range tests {
+		tt.req.PlanID = uuid.New()
+		resp, err := router.CreateCheckoutSession(ctx, tt.req)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if resp.Provider != tt.routeTo {
+			t.Errorf("%s: expected the checkout to be routed to %s, got %s", tt.name, tt.routeTo, resp.Provider)
+		}
+	}
+}
+
+func TestRouter_CreateCheckoutSessionFailover(t *testing.T) {
+	router, providers := newTestRouter(t)
+	ctx := context.Background()
+	req := billing.CreateCheckoutSessionRequest{
+		PlanID:           uuid.New(),
+		CountryCode:      "DE",
+		Currency:         "EUR",
+		CustomerID:       "shopper-1",
+		PaymentMethodID:  "shopper-1:M5N7TQ4TG5PFWR50",
+		CustomerProvider: "adyen",
+	}
+
+	providers["adyen"].err = circuitbreaker.ErrOpenState
+	resp, err := router.CreateCheckoutSession(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Provider != "stripe" {
+		t.Errorf("expected failover to stripe, got %s", resp.Provider)
+	}
+	if providers["adyen"].last.CustomerID != "shopper-1" {
+		t.Errorf("expected the customer to be passed to the provider it belongs to, got %+v", providers["adyen"].last)
+	}
+	if last := providers["stripe"].last; last.CustomerID != "" || last.PaymentMethodID != "" {
+		t.Errorf("expected another provider's customer not to be passed on, got %+v", last)
+	}
+
+	// Only an open breaker fails over; other errors are the provider's answer
+	providers["adyen"].err = errors.New("invalid currency")
+	providers["stripe"].last = nil
+	if _, err := router.CreateCheckoutSession(ctx, req); err == nil || providers["stripe"].last != nil {
+		t.Errorf("expected the error without failover, got %v", err)
+	}
+
+	providers["adyen"].err = circuitbreaker.ErrOpenState
+	providers["stripe"].err = circuitbreaker.ErrOpenState
+	if _, err := router.CreateCheckoutSession(ctx, req); !errors.Is(err, circuitbreaker.ErrOpenState) {
+		t.Errorf("expected every breaker to be reported open, got %v", err)
+	}
+}
+
+func TestRouter_RoutesByProviderName(t *testing.T) {
+	router, _ := newTestRouter(t)
+	ctx := context.Background()
+
+	resp, err := router.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), CountryCode: "NL", Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	adyenCtx := billing.WithProviderName(ctx, resp.Provider)
+	if err := router.CancelSession(adyenCtx, resp.SessionID); err != nil {
+		t.Errorf("expected the session to be cancelled with %s, got %v", resp.Provider, err)
+	}
+	if _, err := router.GetSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected calls without a provider name to go to the default provider")
+	}
+	if _, err := router.GetSession(billing.WithProviderName(ctx, "braintree"), resp.SessionID); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+
+	if !router.Handles("paypal") || router.Handles("braintree") {
+		t.Error("expected the router to handle exactly its providers")
+	}
+	traced := billing.WithTracing("routing", router)
+	if !billing.Handles(traced, "adyen") || !billing.Handles(traced, "routing") || billing.Handles(billingtest.NewFakeProvider(), "fake") {
+		t.Error("expected Handles to see through tracing and only report routers")
+	}
+}
+
+func TestNewRouter_Invalid(t *testing.T) {
+	fake := billingtest.NewFakeProvider()
+	tests := map[string]struct {
+		routes []billing.Route
+		rules  []billing.RoutingRule
+	}{
+		"no routes":        {nil, nil},
+		"unnamed route":    {[]billing.Route{{Provider: fake}}, nil},
+		"duplicate route":  {[]billing.Route{{Name: "stripe", Provider: fake}, {Name: "stripe", Provider: fake}}, nil},
+		"empty rule":       {[]billing.Route{{Name: "stripe", Provider: fake}}, []billing.RoutingRule{{Countries: []string{"US"}}}},
+		"unknown provider": {[]billing.Route{{Name: "stripe", Provider: fake}}, []billing.RoutingRule{{Providers: []string{"adyen"}}}},
+	}
+	for name, tt := range tests {
+		if _, err := billing.NewRouter(tt.routes, tt.rules); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -3,31 +3,62 @@ package stripebp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v76"
-	"github.com/stripe/stripe-go/v76/checkout/session"
+	"github.com/stripe/stripe-go/v76/client"
+	"github.com/stripe/stripe-go/v76/webhook"
 	"go.uber.org/zap"
 
 	"github.com/jia-app/paymentservice/internal/billing"
 	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
 )
 
+// Config holds the Stripe adapter configuration
+type Config struct {
+	SecretKey      string
+	PublishableKey string
+	WebhookSecret  string       // Signing secret of the webhook endpoint; webhooks are only checked for a signature when empty
+	BaseURL        string       // API base URL; empty uses Stripe's
+	HTTPClient     *http.Client // Optional; defaults to Stripe's client
+}
+
 // Adapter implements the billing.Provider interface for Stripe
 type Adapter struct {
-	secretKey      string
+	client         *client.API
 	publishableKey string
+	webhookSecret  string
 	logger         *zap.Logger
 	circuitBreaker *circuitbreaker.CircuitBreaker
 }
 
-// NewAdapter creates a new Stripe billing adapter
-func NewAdapter(secretKey, publishableKey string, logger *zap.Logger) *Adapter {
+// NewAdapter creates a new Stripe billing adapter. Each adapter has its own
+// API client, so adapters pointed at different base URLs can coexist.
+func NewAdapter(cfg Config, logger *zap.Logger) *Adapter {
+	var backends *stripe.Backends
+	if cfg.BaseURL != "" || cfg.HTTPClient != nil {
+		backendConfig := &stripe.BackendConfig{HTTPClient: cfg.HTTPClient}
+		if cfg.BaseURL != "" {
+			backendConfig.URL = stripe.String(cfg.BaseURL)
+		}
+		backend := stripe.GetBackendWithConfig(stripe.APIBackend, backendConfig)
+		backends = &stripe.Backends{
+			API:     backend,
+			Connect: backend,
+			Uploads: stripe.GetBackendWithConfig(stripe.UploadsBackend, backendConfig),
+		}
+	}
+
 	return &Adapter{
-		secretKey:      secretKey,
-		publishableKey: publishableKey,
+		client:         client.New(cfg.SecretKey, backends),
+		publishableKey: cfg.PublishableKey,
+		webhookSecret:  cfg.WebhookSecret,
 		logger:         logger,
 		circuitBreaker: circuitbreaker.GetOrCreateGlobal("stripe", circuitbreaker.StripeConfig),
 	}
@@ -39,9 +70,6 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateC
 	var err error
 
 	_, err = a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
-		// Set Stripe API key
-		stripe.Key = a.secretKey
-
 		// Create line items
 		lineItems := []*stripe.CheckoutSessionLineItemParams{
 			{
@@ -51,7 +79,7 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateC
 						Name:        stripe.String("Subscription Plan"),
 						Description: stripe.String(fmt.Sprintf("Plan ID: %s", req.PlanID.String())),
 					},
-					UnitAmount: stripe.Int64(int64(req.BasePrice * 100)), // Convert dollars to cents for Stripe
+					UnitAmount: stripe.Int64(int64(math.Round(req.BasePrice * 100))), // Convert dollars to cents for Stripe
 				},
 				Quantity: stripe.Int64(1),
 			},
@@ -65,6 +93,10 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateC
 			"currency":   req.Currency,
 		}
 
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+
 		if req.FamilyID != nil {
 			metadata["family_id"] = *req.FamilyID
 		}
@@ -81,11 +113,20 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateC
 			SuccessURL:         stripe.String(req.SuccessURL),
 			CancelURL:          stripe.String(req.CancelURL),
 			Metadata:           metadata,
-			ExpiresAt:          stripe.Int64(time.Now().Add(24 * time.Hour).Unix()),
+			ExpiresAt:          stripe.Int64(sessionExpiry(req.ExpiresAt, time.Now()).Unix()),
+		}
+
+		// Check out as the saved customer so that Checkout offers their
+		// saved payment methods
+		if req.CustomerID != "" {
+			params.Customer = stripe.String(req.CustomerID)
+		}
+		if req.PaymentMethodID != "" {
+			params.AddMetadata("payment_method_id", req.PaymentMethodID)
 		}
 
 		// Create the session
-		session, err := session.New(params)
+		session, err := a.client.CheckoutSessions.New(params)
 		if err != nil {
 			a.logger.Error("Failed to create Stripe checkout session",
 				zap.Error(err),
@@ -118,63 +159,63 @@ func (a *Adapter) CreateCheckoutSession(ctx context.Context, req billing.CreateC
 
 // GetSession retrieves a Stripe checkout session
 func (a *Adapter) GetSession(ctx context.Context, sessionID string) (*billing.Session, error) {
-	// TODO: Implement actual Stripe session retrieval
-	// For now, return a mock response
+	var result *billing.Session
 
-	a.logger.Info("Retrieving Stripe checkout session",
-		zap.String("session_id", sessionID))
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		s, err := a.client.CheckoutSessions.Get(sessionID, nil)
+		if err != nil {
+			a.logger.Error("Failed to retrieve Stripe checkout session",
+				zap.Error(err),
+				zap.String("session_id", sessionID))
+			return nil, fmt.Errorf("failed to retrieve checkout session: %w", err)
+		}
 
-	// TODO: Call Stripe API to retrieve actual session
-	// This would involve:
-	// 1. Calling stripe.CheckoutSession.Retrieve(sessionID)
-	// 2. Converting Stripe session to our Session type
+		result = convertSession(s)
+		return result, nil
+	})
 
-	return &billing.Session{
-		ID:        sessionID,
-		Status:    string(billing.SessionStatusOpen),
-		URL:       fmt.Sprintf("https://checkout.stripe.com/pay/%s", sessionID),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}, nil
+	return result, err
 }
 
-// CancelSession cancels a Stripe checkout session
+// CancelSession cancels a Stripe checkout session. Stripe has no cancel
+// operation for checkout sessions; expiring an open session is the
+// equivalent and prevents it from being paid.
 func (a *Adapter) CancelSession(ctx context.Context, sessionID string) error {
-	// TODO: Implement actual Stripe session cancellation
-	// For now, just log the action
-
-	a.logger.Info("Cancelling Stripe checkout session",
-		zap.String("session_id", sessionID))
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		if _, err := a.client.CheckoutSessions.Expire(sessionID, nil); err != nil {
+			a.logger.Error("Failed to expire Stripe checkout session",
+				zap.Error(err),
+				zap.String("session_id", sessionID))
+			return nil, fmt.Errorf("failed to cancel checkout session: %w", err)
+		}
 
-	// TODO: Call Stripe API to cancel actual session
-	// This would involve:
-	// 1. Calling stripe.CheckoutSession.Cancel(sessionID)
-	// 2. Handling any errors appropriately
+		a.logger.Info("Expired Stripe checkout session",
+			zap.String("session_id", sessionID))
+		return nil, nil
+	})
 
-	return nil
+	return err
 }
 
-// ValidateWebhook validates a Stripe webhook signature
+// ValidateWebhook validates a Stripe webhook against its Stripe-Signature
+// header, rejecting signatures older than Stripe's default tolerance. Without
+// a webhook secret only the presence of a signature is checked, as for the
+// POC payloads.
 func (a *Adapter) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
-	// TODO: Implement actual Stripe webhook signature validation
-	// For now, just check that signature is not empty
-
 	if signature == "" {
 		return fmt.Errorf("missing webhook signature")
 	}
 
 	a.logger.Debug("Validating Stripe webhook signature",
-		zap.String("signature", signature),
 		zap.Int("payload_size", len(payload)))
 
-	// TODO: Implement actual Stripe webhook signature validation
-	// This would involve:
-	// 1. Extracting timestamp and signatures from the signature header
-	// 2. Computing HMAC-SHA256 of the payload with the webhook secret
-	// 3. Comparing with the provided signature
-	// 4. Checking timestamp to prevent replay attacks
-
+	if a.webhookSecret == "" {
+		a.logger.Warn("Stripe webhook secret not configured, skipping signature verification")
+		return nil
+	}
+	if err := webhook.ValidatePayload(payload, signature, a.webhookSecret); err != nil {
+		return fmt.Errorf("invalid webhook signature: %w", err)
+	}
 	return nil
 }
 
@@ -208,6 +249,8 @@ func (a *Adapter) ParseWebhook(ctx context.Context, payload []byte) (*billing.We
 		return a.handlePaymentSucceeded(event)
 	case "payment_intent.payment_failed":
 		return a.handlePaymentFailed(event)
+	case "charge.refunded":
+		return a.handleChargeRefunded(ctx, event)
 	default:
 		a.logger.Info("Unhandled webhook event type", zap.String("event_type", string(event.Type)))
 		return nil, fmt.Errorf("unhandled event type: %s", event.Type)
@@ -245,11 +288,10 @@ func (a *Adapter) handleCustomWebhookPayload(payload map[string]interface{}) (*b
 		EventType:    string(billing.WebhookEventTypeCheckoutSessionCompleted),
 		SessionID:    sessionID,
 		UserID:       userID,
-		FeatureCode:  "", // Will be determined from plan
 		PlanID:       planID,
 		PlanIDString: planIDStr, // Store original plan ID string
 		Amount:       amount,
-		Currency:     currency,
+		Currency:     strings.ToUpper(currency),
 		Status:       status,
 		ExpiresAt:    nil, // Lifetime for this POC
 		Metadata:     payload,
@@ -312,18 +354,14 @@ func (a *Adapter) handleCheckoutSessionCompleted(event stripe.Event) (*billing.W
 		currency = "USD"
 	}
 
-	// Determine feature code based on plan
-	featureCode := a.getFeatureCodeForPlan(planIDStr)
-
 	result := &billing.WebhookResult{
 		EventType:    string(billing.WebhookEventTypeCheckoutSessionCompleted),
 		SessionID:    session.ID,
 		UserID:       userID,
-		FeatureCode:  featureCode,
 		PlanID:       planID,
 		PlanIDString: planIDStr, // Store original plan ID string
 		Amount:       basePrice,
-		Currency:     currency,
+		Currency:     strings.ToUpper(currency),
 		Status:       "completed",
 		ExpiresAt:    nil, // Lifetime for this POC
 		Metadata: map[string]interface{}{
@@ -335,16 +373,47 @@ func (a *Adapter) handleCheckoutSessionCompleted(event stripe.Event) (*billing.W
 	if familyID != "" {
 		result.FamilyID = &familyID
 	}
+	if session.CustomerDetails != nil {
+		result.Customer = convertCustomerDetails(session.CustomerDetails)
+	}
+	if session.TotalDetails != nil {
+		result.DiscountAmount = float64(session.TotalDetails.AmountDiscount) / 100.0
+		result.TaxAmount = float64(session.TotalDetails.AmountTax) / 100.0
+	}
 
 	a.logger.Info("Processed checkout session completed",
 		zap.String("session_id", session.ID),
 		zap.String("user_id", userID),
-		zap.String("plan_id", planIDStr),
-		zap.String("feature_code", featureCode))
+		zap.String("plan_id", planIDStr))
 
 	return result, nil
 }
 
+// convertCustomerDetails converts Stripe customer details to billing customer details
+func convertCustomerDetails(details *stripe.CheckoutSessionCustomerDetails) *billing.CustomerDetails {
+	customer := &billing.CustomerDetails{
+		Name:  details.Name,
+		Email: details.Email,
+	}
+	if details.Address != nil {
+		customer.Address = billing.CustomerAddress{
+			Line1:      details.Address.Line1,
+			Line2:      details.Address.Line2,
+			City:       details.Address.City,
+			Region:     details.Address.State,
+			PostalCode: details.Address.PostalCode,
+			Country:    details.Address.Country,
+		}
+	}
+	for _, taxID := range details.TaxIDs {
+		if taxID != nil && taxID.Value != "" {
+			customer.TaxID = taxID.Value
+			break
+		}
+	}
+	return customer
+}
+
 // handlePaymentSucceeded handles payment_intent.succeeded events
 func (a *Adapter) handlePaymentSucceeded(event stripe.Event) (*billing.WebhookResult, error) {
 	var paymentIntent stripe.PaymentIntent
@@ -355,15 +424,14 @@ func (a *Adapter) handlePaymentSucceeded(event stripe.Event) (*billing.WebhookRe
 	// For POC, we'll create a basic result
 	// In production, you'd want to link this to your checkout session
 	result := &billing.WebhookResult{
-		EventType:   string(billing.WebhookEventTypePaymentSucceeded),
-		SessionID:   paymentIntent.ID, // Using payment intent ID as session ID
-		UserID:      "unknown",        // Would need to be extracted from metadata
-		FeatureCode: "premium_feature",
-		PlanID:      uuid.New(),
-		Amount:      float64(paymentIntent.Amount) / 100.0, // Convert cents to dollars
-		Currency:    string(paymentIntent.Currency),
-		Status:      "completed",
-		ExpiresAt:   nil,
+		EventType: string(billing.WebhookEventTypePaymentSucceeded),
+		SessionID: paymentIntent.ID, // Using payment intent ID as session ID
+		UserID:    "unknown",        // Would need to be extracted from metadata
+		PlanID:    uuid.New(),
+		Amount:    float64(paymentIntent.Amount) / 100.0, // Convert cents to dollars
+		Currency:  strings.ToUpper(string(paymentIntent.Currency)),
+		Status:    "completed",
+		ExpiresAt: nil,
 		Metadata: map[string]interface{}{
 			"payment_intent_id": paymentIntent.ID,
 			"status":            paymentIntent.Status,
@@ -386,15 +454,14 @@ func (a *Adapter) handlePaymentFailed(event stripe.Event) (*billing.WebhookResul
 	}
 
 	result := &billing.WebhookResult{
-		EventType:   string(billing.WebhookEventTypePaymentFailed),
-		SessionID:   paymentIntent.ID,
-		UserID:      "unknown",
-		FeatureCode: "premium_feature",
-		PlanID:      uuid.New(),
-		Amount:      float64(paymentIntent.Amount) / 100.0, // Convert cents to dollars
-		Currency:    string(paymentIntent.Currency),
-		Status:      "failed",
-		ExpiresAt:   nil,
+		EventType: string(billing.WebhookEventTypePaymentFailed),
+		SessionID: paymentIntent.ID,
+		UserID:    "unknown",
+		PlanID:    uuid.New(),
+		Amount:    float64(paymentIntent.Amount) / 100.0, // Convert cents to dollars
+		Currency:  strings.ToUpper(string(paymentIntent.Currency)),
+		Status:    "failed",
+		ExpiresAt: nil,
 		Metadata: map[string]interface{}{
 			"payment_intent_id": paymentIntent.ID,
 			"status":            paymentIntent.Status,
@@ -409,32 +476,318 @@ func (a *Adapter) handlePaymentFailed(event stripe.Event) (*billing.WebhookResul
 	return result, nil
 }
 
-// getFeatureCodeForPlan returns the appropriate feature code for a plan
-func (a *Adapter) getFeatureCodeForPlan(planID string) string {
-	switch planID {
-	case "basic_monthly":
-		return "basic_storage"
-	case "pro_monthly":
-		return "pro_storage"
-	case "family_monthly":
-		return "family_storage"
-	// Legacy UUID support
-	case "550e8400-e29b-41d4-a716-446655440001": // Basic Plan UUID
-		return "basic_storage"
-	case "550e8400-e29b-41d4-a716-446655440002": // Pro Plan UUID
-		return "pro_storage"
-	case "550e8400-e29b-41d4-a716-446655440003": // Family Plan UUID
-		return "family_storage"
-	default:
-		return "premium_feature"
+// handleChargeRefunded handles charge.refunded events. The charge carries
+// the total refunded so far, so the refund is the increase over the
+// previous total and is referenced by the charge and the new total. The
+// session is the checkout session that made the charge's payment intent, or
+// the payment intent itself for payments made without a checkout.
+func (a *Adapter) handleChargeRefunded(ctx context.Context, event stripe.Event) (*billing.WebhookResult, error) {
+	var charge stripe.Charge
+	if err := json.Unmarshal(event.Data.Raw, &charge); err != nil {
+		return nil, fmt.Errorf("failed to parse charge: %w", err)
+	}
+
+	refunded := charge.AmountRefunded
+	if previous, ok := event.Data.PreviousAttributes["amount_refunded"].(float64); ok {
+		refunded -= int64(previous)
+	}
+
+	result := &billing.WebhookResult{
+		EventType:    string(billing.WebhookEventTypePaymentRefunded),
+		UserID:       charge.Metadata["user_id"],
+		PlanIDString: charge.Metadata["plan_id"],
+		Amount:       float64(refunded) / 100.0,
+		Currency:     strings.ToUpper(string(charge.Currency)),
+		Status:       string(billing.ChargeStatusRefunded),
+		Reference:    fmt.Sprintf("%s:%d", charge.ID, charge.AmountRefunded),
+		Metadata: map[string]interface{}{
+			"stripe_charge_id": charge.ID,
+		},
 	}
+	if charge.PaymentIntent != nil {
+		result.Metadata["payment_intent_id"] = charge.PaymentIntent.ID
+		sessionID, err := a.paymentIntentSession(ctx, charge.PaymentIntent.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.SessionID = sessionID
+	}
+
+	a.logger.Info("Processed charge refunded",
+		zap.String("charge_id", charge.ID),
+		zap.String("session_id", result.SessionID),
+		zap.Int64("amount_refunded", refunded))
+
+	return result, nil
 }
 
-// Close closes the Stripe adapter
-func (a *Adapter) Close() error {
-	// TODO: Implement cleanup if needed
-	// Stripe doesn't require explicit connection cleanup
+// paymentIntentSession returns the ID of the checkout session that created
+// a payment intent, or the payment intent's ID when no session did
+func (a *Adapter) paymentIntentSession(ctx context.Context, paymentIntentID string) (string, error) {
+	sessionID := paymentIntentID
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		params := &stripe.CheckoutSessionListParams{PaymentIntent: stripe.String(paymentIntentID)}
+		params.Context = ctx
+		params.Single = true
+		params.Limit = stripe.Int64(1)
+
+		it := a.client.CheckoutSessions.List(params)
+		if it.Next() {
+			sessionID = it.CheckoutSession().ID
+		}
+		if err := it.Err(); err != nil {
+			a.logger.Error("Failed to list Stripe checkout sessions",
+				zap.Error(err),
+				zap.String("payment_intent_id", paymentIntentID))
+			return nil, fmt.Errorf("failed to find checkout session of payment intent %s: %w", paymentIntentID, err)
+		}
+		return nil, nil
+	})
+
+	return sessionID, err
+}
+
+// SaveCustomer creates or updates a Stripe customer
+func (a *Adapter) SaveCustomer(ctx context.Context, req billing.SaveCustomerRequest) (string, error) {
+	var customerID string
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		params := &stripe.CustomerParams{
+			Name:  stripe.String(req.Name),
+			Email: stripe.String(req.Email),
+			Address: &stripe.AddressParams{
+				Line1:      stripe.String(req.Address.Line1),
+				Line2:      stripe.String(req.Address.Line2),
+				City:       stripe.String(req.Address.City),
+				State:      stripe.String(req.Address.Region),
+				PostalCode: stripe.String(req.Address.PostalCode),
+				Country:    stripe.String(req.Address.Country),
+			},
+			Metadata: map[string]string{
+				"user_id": req.UserID,
+				"tax_id":  req.TaxID,
+			},
+		}
+
+		var (
+			c   *stripe.Customer
+			err error
+		)
+		if req.CustomerID == "" {
+			c, err = a.client.Customers.New(params)
+		} else {
+			c, err = a.client.Customers.Update(req.CustomerID, params)
+		}
+		if err != nil {
+			a.logger.Error("Failed to save Stripe customer",
+				zap.Error(err),
+				zap.String("customer_id", req.CustomerID),
+				zap.String("user_id", req.UserID))
+			return nil, fmt.Errorf("failed to save customer: %w", err)
+		}
+
+		customerID = c.ID
+		return nil, nil
+	})
+
+	return customerID, err
+}
+
+// AttachPaymentMethod attaches a Stripe payment method to a customer
+func (a *Adapter) AttachPaymentMethod(ctx context.Context, customerID, token string) (*billing.PaymentMethodDetails, error) {
+	var result *billing.PaymentMethodDetails
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		pm, err := a.client.PaymentMethods.Attach(token, &stripe.PaymentMethodAttachParams{
+			Customer: stripe.String(customerID),
+		})
+		if err != nil {
+			a.logger.Error("Failed to attach Stripe payment method",
+				zap.Error(err),
+				zap.String("customer_id", customerID))
+			return nil, fmt.Errorf("failed to attach payment method: %w", err)
+		}
+
+		result = convertPaymentMethod(pm)
+		return result, nil
+	})
+
+	return result, err
+}
+
+// DetachPaymentMethod detaches a Stripe payment method from its customer
+func (a *Adapter) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		if _, err := a.client.PaymentMethods.Detach(paymentMethodID, nil); err != nil {
+			a.logger.Error("Failed to detach Stripe payment method",
+				zap.Error(err),
+				zap.String("payment_method_id", paymentMethodID))
+			return nil, fmt.Errorf("failed to detach payment method: %w", err)
+		}
+		return nil, nil
+	})
+
+	return err
+}
+
+// SetDefaultPaymentMethod sets a Stripe customer's default payment method
+func (a *Adapter) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		_, err := a.client.Customers.Update(customerID, &stripe.CustomerParams{
+			InvoiceSettings: &stripe.CustomerInvoiceSettingsParams{
+				DefaultPaymentMethod: stripe.String(paymentMethodID),
+			},
+		})
+		if err != nil {
+			a.logger.Error("Failed to set Stripe default payment method",
+				zap.Error(err),
+				zap.String("customer_id", customerID),
+				zap.String("payment_method_id", paymentMethodID))
+			return nil, fmt.Errorf("failed to set default payment method: %w", err)
+		}
+		return nil, nil
+	})
+
+	return err
+}
 
+// ChargePaymentMethod charges a saved Stripe payment method off-session by
+// confirming a payment intent. Card declines are reported as a failed
+// result rather than an error so that they don't trip the circuit breaker.
+func (a *Adapter) ChargePaymentMethod(ctx context.Context, req billing.ChargeRequest) (*billing.ChargeResult, error) {
+	var result *billing.ChargeResult
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		params := &stripe.PaymentIntentParams{
+			Amount:        stripe.Int64(int64(math.Round(req.Amount * 100))), // Convert dollars to cents for Stripe
+			Currency:      stripe.String(req.Currency),
+			Customer:      stripe.String(req.CustomerID),
+			PaymentMethod: stripe.String(req.PaymentMethodID),
+			Description:   stripe.String(req.Description),
+			Confirm:       stripe.Bool(true),
+			OffSession:    stripe.Bool(true),
+			Metadata:      req.Metadata,
+		}
+		params.SetIdempotencyKey(req.IdempotencyKey)
+
+		pi, err := a.client.PaymentIntents.New(params)
+		if err != nil {
+			var stripeErr *stripe.Error
+			if errors.As(err, &stripeErr) && stripeErr.Type == stripe.ErrorTypeCard {
+				result = &billing.ChargeResult{Status: billing.ChargeStatusFailed, FailureReason: stripeErr.Msg}
+				if stripeErr.PaymentIntent != nil {
+					result.ID = stripeErr.PaymentIntent.ID
+					if stripeErr.PaymentIntent.Status == stripe.PaymentIntentStatusRequiresAction {
+						result.Status = billing.ChargeStatusRequiresAction
+					}
+				}
+				return result, nil
+			}
+
+			a.logger.Error("Failed to charge Stripe payment method",
+				zap.Error(err),
+				zap.String("customer_id", req.CustomerID),
+				zap.String("payment_method_id", req.PaymentMethodID))
+			return nil, fmt.Errorf("failed to charge payment method: %w", err)
+		}
+
+		result = &billing.ChargeResult{ID: pi.ID, Status: billing.ChargeStatusFailed}
+		switch pi.Status {
+		case stripe.PaymentIntentStatusSucceeded:
+			result.Status = billing.ChargeStatusSucceeded
+		case stripe.PaymentIntentStatusRequiresAction:
+			result.Status = billing.ChargeStatusRequiresAction
+		default:
+			result.FailureReason = fmt.Sprintf("payment intent is %s", pi.Status)
+		}
+		return result, nil
+	})
+
+	return result, err
+}
+
+// defaultListLimit is the page size used when a list request sets none;
+// Stripe allows up to 100
+const defaultListLimit = 100
+
+// ListCharges lists the charges of completed Stripe checkout sessions. Every
+// charge made through checkout is reached through its session, which carries
+// the session ID and metadata local payments are matched by.
+func (a *Adapter) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	var result *billing.ChargePage
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		params := &stripe.CheckoutSessionListParams{
+			CreatedRange: createdRange(req),
+			Status:       stripe.String(string(stripe.CheckoutSessionStatusComplete)),
+		}
+		params.Context = ctx
+		params.Single = true
+		params.Limit = stripe.Int64(listLimit(req))
+		if req.Cursor != "" {
+			params.StartingAfter = stripe.String(req.Cursor)
+		}
+		params.AddExpand("data.payment_intent.latest_charge")
+
+		page := &billing.ChargePage{}
+		it := a.client.CheckoutSessions.List(params)
+		for it.Next() {
+			page.Charges = append(page.Charges, convertSessionCharge(it.CheckoutSession()))
+		}
+		if err := it.Err(); err != nil {
+			a.logger.Error("Failed to list Stripe checkout sessions", zap.Error(err))
+			return nil, fmt.Errorf("failed to list charges: %w", err)
+		}
+		if list := it.CheckoutSessionList(); list != nil && list.HasMore && len(list.Data) > 0 {
+			page.NextCursor = list.Data[len(list.Data)-1].ID
+		}
+
+		result = page
+		return result, nil
+	})
+
+	return result, err
+}
+
+// ListSubscriptions lists Stripe subscriptions in any status
+func (a *Adapter) ListSubscriptions(ctx context.Context, req billing.ListRequest) (*billing.SubscriptionPage, error) {
+	var result *billing.SubscriptionPage
+
+	_, err := a.circuitBreaker.Execute(ctx, func() (interface{}, error) {
+		params := &stripe.SubscriptionListParams{
+			CreatedRange: createdRange(req),
+			Status:       stripe.String("all"),
+		}
+		params.Context = ctx
+		params.Single = true
+		params.Limit = stripe.Int64(listLimit(req))
+		if req.Cursor != "" {
+			params.StartingAfter = stripe.String(req.Cursor)
+		}
+
+		page := &billing.SubscriptionPage{}
+		it := a.client.Subscriptions.List(params)
+		for it.Next() {
+			page.Subscriptions = append(page.Subscriptions, convertSubscription(it.Subscription()))
+		}
+		if err := it.Err(); err != nil {
+			a.logger.Error("Failed to list Stripe subscriptions", zap.Error(err))
+			return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+		}
+		if list := it.SubscriptionList(); list != nil && list.HasMore && len(list.Data) > 0 {
+			page.NextCursor = list.Data[len(list.Data)-1].ID
+		}
+
+		result = page
+		return result, nil
+	})
+
+	return result, err
+}
+
+// Close closes the Stripe adapter. Stripe needs no explicit connection cleanup.
+func (a *Adapter) Close() error {
 	a.logger.Info("Stripe adapter closed")
 	return nil
 }
@@ -446,3 +799,170 @@ func getStringValue(s *string) string {
 	}
 	return *s
 }
+
+// Stripe only accepts checkout session expiries between 30 minutes and 24
+// hours after creation
+const (
+	minSessionLifetime = 30 * time.Minute
+	maxSessionLifetime = 24 * time.Hour
+)
+
+// sessionExpiry clamps a requested expiry to the range Stripe accepts,
+// defaulting to the longest lifetime when none is requested
+func sessionExpiry(requested, now time.Time) time.Time {
+	switch {
+	case requested.IsZero(), requested.After(now.Add(maxSessionLifetime)):
+		return now.Add(maxSessionLifetime)
+	case requested.Before(now.Add(minSessionLifetime)):
+		return now.Add(minSessionLifetime)
+	default:
+		return requested
+	}
+}
+
+// convertSession converts a Stripe checkout session to our Session type
+func convertSession(s *stripe.CheckoutSession) *billing.Session {
+	metadata := make(map[string]interface{}, len(s.Metadata))
+	for k, v := range s.Metadata {
+		metadata[k] = v
+	}
+
+	created := time.Unix(s.Created, 0)
+	return &billing.Session{
+		ID:        s.ID,
+		Status:    string(s.Status),
+		URL:       s.URL,
+		ExpiresAt: time.Unix(s.ExpiresAt, 0),
+		Metadata:  metadata,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+// convertPaymentMethod converts a Stripe payment method to our PaymentMethodDetails type
+func convertPaymentMethod(pm *stripe.PaymentMethod) *billing.PaymentMethodDetails {
+	details := &billing.PaymentMethodDetails{ID: pm.ID, Type: "credit_card"}
+
+	switch pm.Type {
+	case stripe.PaymentMethodTypeCard:
+		if pm.Card == nil {
+			break
+		}
+		details.Brand = string(pm.Card.Brand)
+		details.Last4 = pm.Card.Last4
+		details.ExpMonth = int32(pm.Card.ExpMonth)
+		details.ExpYear = int32(pm.Card.ExpYear)
+		switch {
+		case pm.Card.Wallet != nil:
+			details.Type = "digital_wallet"
+		case pm.Card.Funding == stripe.CardFundingDebit:
+			details.Type = "debit_card"
+		}
+	case stripe.PaymentMethodTypeSEPADebit, stripe.PaymentMethodTypeUSBankAccount:
+		details.Type = "bank_transfer"
+		if pm.SEPADebit != nil {
+			details.Last4 = pm.SEPADebit.Last4
+		}
+		if pm.USBankAccount != nil {
+			details.Last4 = pm.USBankAccount.Last4
+		}
+	default:
+		details.Type = "digital_wallet"
+	}
+
+	return details
+}
+
+// createdRange converts a list request's window to a Stripe created filter
+func createdRange(req billing.ListRequest) *stripe.RangeQueryParams {
+	r := &stripe.RangeQueryParams{}
+	if !req.CreatedAfter.IsZero() {
+		r.GreaterThanOrEqual = req.CreatedAfter.Unix()
+	}
+	if !req.CreatedBefore.IsZero() {
+		r.LesserThan = req.CreatedBefore.Unix()
+	}
+	return r
+}
+
+// listLimit returns the Stripe page size for a list request
+func listLimit(req billing.ListRequest) int64 {
+	if req.Limit <= 0 || req.Limit > defaultListLimit {
+		return defaultListLimit
+	}
+	return int64(req.Limit)
+}
+
+// convertSessionCharge converts a completed Stripe checkout session to the
+// charge it was paid with
+func convertSessionCharge(s *stripe.CheckoutSession) billing.Charge {
+	charge := billing.Charge{
+		ID:        s.ID,
+		SessionID: s.ID,
+		UserID:    s.Metadata["user_id"],
+		PlanID:    s.Metadata["plan_id"],
+		Amount:    float64(s.AmountTotal) / 100.0,
+		Currency:  strings.ToUpper(string(s.Currency)),
+		Status:    billing.ChargeStatusPending,
+		CreatedAt: time.Unix(s.Created, 0),
+	}
+	if familyID := s.Metadata["family_id"]; familyID != "" {
+		charge.FamilyID = &familyID
+	}
+	if s.Customer != nil {
+		charge.CustomerID = s.Customer.ID
+	}
+	if s.Subscription != nil {
+		charge.SubscriptionID = s.Subscription.ID
+	}
+
+	if s.PaymentStatus == stripe.CheckoutSessionPaymentStatusPaid {
+		charge.Status = billing.ChargeStatusSucceeded
+	}
+	if pi := s.PaymentIntent; pi != nil {
+		charge.ID = pi.ID
+		switch {
+		case pi.LatestCharge != nil && pi.LatestCharge.Refunded:
+			charge.Status = billing.ChargeStatusRefunded
+		case pi.Status == stripe.PaymentIntentStatusCanceled, pi.Status == stripe.PaymentIntentStatusRequiresPaymentMethod:
+			charge.Status = billing.ChargeStatusFailed
+		}
+	}
+
+	return charge
+}
+
+// convertSubscription converts a Stripe subscription, normalizing its status
+// to the ones used for local subscriptions
+func convertSubscription(sub *stripe.Subscription) billing.Subscription {
+	result := billing.Subscription{
+		ID:                 sub.ID,
+		UserID:             sub.Metadata["user_id"],
+		PlanID:             sub.Metadata["plan_id"],
+		CurrentPeriodStart: time.Unix(sub.CurrentPeriodStart, 0),
+		CurrentPeriodEnd:   time.Unix(sub.CurrentPeriodEnd, 0),
+		CancelAtPeriodEnd:  sub.CancelAtPeriodEnd,
+		CreatedAt:          time.Unix(sub.Created, 0),
+	}
+	if familyID := sub.Metadata["family_id"]; familyID != "" {
+		result.FamilyID = &familyID
+	}
+	if sub.Customer != nil {
+		result.CustomerID = sub.Customer.ID
+	}
+
+	switch sub.Status {
+	case stripe.SubscriptionStatusActive, stripe.SubscriptionStatusTrialing:
+		result.Status = "active"
+	case stripe.SubscriptionStatusPastDue, stripe.SubscriptionStatusIncomplete:
+		result.Status = "past_due"
+	case stripe.SubscriptionStatusUnpaid, stripe.SubscriptionStatusPaused:
+		result.Status = "suspended"
+	case stripe.SubscriptionStatusCanceled:
+		result.Status = "cancelled"
+	default:
+		result.Status = "expired"
+	}
+
+	return result
+}
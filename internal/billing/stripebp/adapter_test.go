@@ -0,0 +1,320 @@
+package stripebp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v76"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/stripebp/stripetest"
+	"github.com/jia-app/paymentservice/internal/shared/circuitbreaker"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// newTestAdapter starts a fake Stripe API and an adapter that talks to it
+func newTestAdapter(t *testing.T) (*stripetest.Server, *Adapter) {
+	t.Helper()
+	fake := stripetest.NewServer(stripetest.Config{WebhookSecret: testWebhookSecret})
+	t.Cleanup(fake.Close)
+
+	adapter := NewAdapter(Config{
+		SecretKey:     "sk_test_123",
+		WebhookSecret: testWebhookSecret,
+		BaseURL:       fake.URL,
+	}, zap.NewNop())
+	// Keep failures in one test from opening the shared breaker for others
+	adapter.circuitBreaker = circuitbreaker.NewCircuitBreaker(circuitbreaker.ExternalAPIConfig)
+	return fake, adapter
+}
+
+func TestAdapter_CheckoutSession(t *testing.T) {
+	fake, adapter := newTestAdapter(t)
+	ctx := context.Background()
+	planID := uuid.New()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{
+		PlanID:     planID,
+		UserID:     "user-1",
+		SuccessURL: "https://jia.app/success",
+		CancelURL:  "https://jia.app/cancel",
+		BasePrice:  9.99,
+		Currency:   "USD",
+		ExpiresAt:  time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.URL == "" || time.Until(resp.ExpiresAt) > time.Hour {
+		t.Errorf("unexpected session %+v", resp)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(stripe.CheckoutSessionStatusOpen) || session.Metadata["user_id"] != "user-1" {
+		t.Errorf("unexpected session %+v", session)
+	}
+
+	completed, err := fake.CompleteCheckout(resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adapter.ValidateWebhook(ctx, completed.Payload, completed.Signature); err != nil {
+		t.Fatalf("expected the fake's signature to validate, got %v", err)
+	}
+	result, err := adapter.ParseWebhook(ctx, completed.Payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.EventType != string(billing.WebhookEventTypeCheckoutSessionCompleted) || result.SessionID != resp.SessionID ||
+		result.UserID != "user-1" || result.PlanID != planID || result.Amount != 9.99 {
+		t.Errorf("unexpected webhook result %+v", result)
+	}
+
+	page, err := adapter.ListCharges(ctx, billing.ListRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.Charges) != 1 || page.Charges[0].SessionID != resp.SessionID || page.Charges[0].Status != billing.ChargeStatusSucceeded || page.Charges[0].Amount != 9.99 {
+		t.Errorf("expected the paid session's charge, got %+v", page.Charges)
+	}
+}
+
+func TestAdapter_CancelSession(t *testing.T) {
+	fake, adapter := newTestAdapter(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), UserID: "user-1", BasePrice: 5, Currency: "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adapter.CancelSession(ctx, resp.SessionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	session, err := adapter.GetSession(ctx, resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != string(stripe.CheckoutSessionStatusExpired) {
+		t.Errorf("expected an expired session, got %+v", session)
+	}
+	if _, err := fake.CompleteCheckout(resp.SessionID); err == nil {
+		t.Error("expected an expired session not to be payable")
+	}
+	if err := adapter.CancelSession(ctx, resp.SessionID); err == nil {
+		t.Error("expected an error expiring a session twice")
+	}
+}
+
+func TestAdapter_ValidateWebhook(t *testing.T) {
+	fake, adapter := newTestAdapter(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), UserID: "user-1", BasePrice: 5, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wh, err := fake.CompleteCheckout(resp.SessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tampered := append([]byte(nil), wh.Payload...)
+	tampered[len(tampered)-2] = ' '
+	if err := adapter.ValidateWebhook(ctx, tampered, wh.Signature); err == nil {
+		t.Error("expected a tampered payload to be rejected")
+	}
+	if err := adapter.ValidateWebhook(ctx, wh.Payload, ""); err == nil {
+		t.Error("expected a missing signature to be rejected")
+	}
+
+	other := NewAdapter(Config{SecretKey: "sk_test_123", WebhookSecret: "whsec_other", BaseURL: fake.URL}, zap.NewNop())
+	if err := other.ValidateWebhook(ctx, wh.Payload, wh.Signature); err == nil {
+		t.Error("expected a payload signed with another secret to be rejected")
+	}
+	unverified := NewAdapter(Config{SecretKey: "sk_test_123", BaseURL: fake.URL}, zap.NewNop())
+	if err := unverified.ValidateWebhook(ctx, wh.Payload, "t=1,v1=unchecked"); err != nil {
+		t.Errorf("expected signatures not to be verified without a secret, got %v", err)
+	}
+}
+
+func TestAdapter_SavedPaymentMethods(t *testing.T) {
+	fake, adapter := newTestAdapter(t)
+	ctx := context.Background()
+
+	customerID, err := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{
+		UserID:  "user-1",
+		Name:    "Ada Lovelace",
+		Email:   "ada@example.com",
+		Address: billing.CustomerAddress{Line1: "1 Main St", City: "London", Country: "GB"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{CustomerID: customerID, UserID: "user-1", Name: "Ada King"}); err != nil {
+		t.Fatalf("expected the customer to be updated, got %v", err)
+	}
+
+	method, err := adapter.AttachPaymentMethod(ctx, customerID, "pm_card_visa")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if method.Type != "credit_card" || method.Brand != "visa" || method.Last4 != "4242" {
+		t.Errorf("unexpected payment method %+v", method)
+	}
+	if err := adapter.SetDefaultPaymentMethod(ctx, customerID, method.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	charge := billing.ChargeRequest{
+		CustomerID:      customerID,
+		PaymentMethodID: method.ID,
+		Amount:          12.5,
+		Currency:        "USD",
+		IdempotencyKey:  "dunning-1",
+	}
+	result, err := adapter.ChargePaymentMethod(ctx, charge)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != billing.ChargeStatusSucceeded || result.ID == "" {
+		t.Errorf("unexpected charge %+v", result)
+	}
+	if retried, err := adapter.ChargePaymentMethod(ctx, charge); err != nil || retried.ID != result.ID {
+		t.Errorf("expected a retried charge to return the first payment intent, got %+v, %v", retried, err)
+	}
+
+	fake.Decline("Your card was declined.")
+	charge.IdempotencyKey = "dunning-2"
+	declined, err := adapter.ChargePaymentMethod(ctx, charge)
+	if err != nil {
+		t.Fatalf("expected a decline to be reported as a failed charge, got %v", err)
+	}
+	if declined.Status != billing.ChargeStatusFailed || declined.FailureReason != "Your card was declined." || declined.ID == "" {
+		t.Errorf("unexpected declined charge %+v", declined)
+	}
+
+	if err := adapter.DetachPaymentMethod(ctx, method.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := adapter.DetachPaymentMethod(ctx, method.ID); err == nil {
+		t.Error("expected an error detaching a detached payment method")
+	}
+}
+
+func TestAdapter_ParseWebhookChargeRefunded(t *testing.T) {
+	fake, adapter := newTestAdapter(t)
+	ctx := context.Background()
+
+	resp, err := adapter.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{PlanID: uuid.New(), UserID: "user-1", BasePrice: 20, Currency: "USD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := fake.CompleteCheckout(resp.SessionID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, err := adapter.ListCharges(ctx, billing.ListRequest{})
+	if err != nil || len(page.Charges) != 1 {
+		t.Fatalf("expected one charge, got %+v, %v", page, err)
+	}
+
+	// Each refund reports only what it refunded, under its own reference
+	var references []string
+	for _, cents := range []int64{500, 1500} {
+		if _, err := adapter.client.Refunds.New(&stripe.RefundParams{PaymentIntent: stripe.String(page.Charges[0].ID), Amount: stripe.Int64(cents)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		webhooks := fake.Webhooks()
+		refunded := webhooks[len(webhooks)-1]
+		if refunded.Type != "charge.refunded" {
+			t.Fatalf("expected a charge.refunded webhook, got %s", refunded.Type)
+		}
+
+		result, err := adapter.ParseWebhook(ctx, refunded.Payload)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.EventType != string(billing.WebhookEventTypePaymentRefunded) || result.UserID != "user-1" || result.Amount != float64(cents)/100 || result.Currency != "USD" {
+			t.Errorf("unexpected webhook result %+v", result)
+		}
+		if result.SessionID != resp.SessionID || result.Metadata["payment_intent_id"] != page.Charges[0].ID {
+			t.Errorf("expected the refunded session and payment intent, got %s, %+v", result.SessionID, result.Metadata)
+		}
+		references = append(references, result.Reference)
+	}
+	if references[0] == "" || references[0] == references[1] {
+		t.Errorf("expected distinct refund references, got %v", references)
+	}
+
+	page, err = adapter.ListCharges(ctx, billing.ListRequest{})
+	if err != nil || len(page.Charges) != 1 || page.Charges[0].Status != billing.ChargeStatusRefunded {
+		t.Errorf("expected the charge to be listed as refunded, got %+v, %v", page, err)
+	}
+}
+
+func TestAdapter_ListSubscriptions(t *testing.T) {
+	_, adapter := newTestAdapter(t)
+	ctx := context.Background()
+
+	customerID, err := adapter.SaveCustomer(ctx, billing.SaveCustomerRequest{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids []string
+	for _, plan := range []string{"basic_monthly", "pro_monthly", "family_monthly"} {
+		sub, err := adapter.client.Subscriptions.New(&stripe.SubscriptionParams{
+			Customer: stripe.String(customerID),
+			Metadata: map[string]string{"user_id": "user-1", "plan_id": plan},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, sub.ID)
+	}
+	if _, err := adapter.client.Subscriptions.Cancel(ids[0], nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first, err := adapter.ListSubscriptions(ctx, billing.ListRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Subscriptions) != 2 || first.NextCursor == "" || first.Subscriptions[0].PlanID != "family_monthly" {
+		t.Fatalf("expected the two newest subscriptions and a cursor, got %+v", first)
+	}
+	rest, err := adapter.ListSubscriptions(ctx, billing.ListRequest{Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rest.Subscriptions) != 1 || rest.NextCursor != "" {
+		t.Fatalf("expected the last subscription, got %+v", rest)
+	}
+	if sub := rest.Subscriptions[0]; sub.ID != ids[0] || sub.Status != "cancelled" || sub.CustomerID != customerID {
+		t.Errorf("unexpected cancelled subscription %+v", sub)
+	}
+}
+
+func TestSessionExpiry(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		requested time.Time
+		want      time.Time
+	}{
+		{time.Time{}, now.Add(maxSessionLifetime)},
+		{now.Add(48 * time.Hour), now.Add(maxSessionLifetime)},
+		{now.Add(time.Minute), now.Add(minSessionLifetime)},
+		{now.Add(time.Hour), now.Add(time.Hour)},
+	}
+	for _, tt := range tests {
+		if got := sessionExpiry(tt.requested, now); !got.Equal(tt.want) {
+			t.Errorf("sessionExpiry(%v): expected %v, got %v", tt.requested, tt.want, got)
+		}
+	}
+}
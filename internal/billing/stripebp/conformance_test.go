@@ -0,0 +1,63 @@
+package stripebp
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/conformance"
+	"github.com/jia-app/paymentservice/internal/billing/stripebp/stripetest"
+)
+
+// stripeFixtures drives the fake Stripe API behind the adapter under test
+type stripeFixtures struct {
+	fake *stripetest.Server
+}
+
+func (f stripeFixtures) CompleteCheckout(sessionID string) (conformance.Webhook, error) {
+	wh, err := f.fake.CompleteCheckout(sessionID)
+	return conformance.Webhook{Payload: wh.Payload, Signature: wh.Signature}, err
+}
+
+func (f stripeFixtures) ExpireSession(sessionID string) error {
+	_, err := f.fake.ExpireCheckout(sessionID)
+	return err
+}
+
+func (f stripeFixtures) UnknownEvent() (conformance.Webhook, error) {
+	payload, err := json.Marshal(map[string]any{
+		"id":          "evt_test_unknown",
+		"object":      "event",
+		"api_version": stripe.APIVersion,
+		"type":        "customer.created",
+		"data":        map[string]any{"object": map[string]any{"id": "cus_test_1", "object": "customer"}},
+	})
+	if err != nil {
+		return conformance.Webhook{}, err
+	}
+	return signStripe(payload, testWebhookSecret, time.Now()), nil
+}
+
+func (f stripeFixtures) SignedAt(payload []byte, at time.Time) (conformance.Webhook, bool, error) {
+	return signStripe(payload, testWebhookSecret, at), true, nil
+}
+
+func (f stripeFixtures) Forge(payload []byte) (conformance.Webhook, error) {
+	return signStripe(payload, "whsec_forged", time.Now()), nil
+}
+
+func signStripe(payload []byte, secret string, at time.Time) conformance.Webhook {
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: secret, Timestamp: at})
+	return conformance.Webhook{Payload: payload, Signature: signed.Header}
+}
+
+func TestAdapter_Conformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T) (billing.Provider, conformance.Fixtures) {
+		fake, adapter := newTestAdapter(t)
+		return adapter, stripeFixtures{fake: fake}
+	})
+}
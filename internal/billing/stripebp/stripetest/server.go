@@ -0,0 +1,723 @@
+// Package stripetest provides a fake Stripe API server for tests. It serves
+// the checkout session, customer, payment method, payment intent,
+// subscription and refund endpoints the Stripe adapter calls, keeps
+// everything in memory and emits webhooks signed like Stripe's, so that
+// checkouts can be driven end to end without a network.
+package stripetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// Config holds the fake server configuration
+type Config struct {
+	WebhookSecret string // Secret webhooks are signed with
+	WebhookURL    string // Optional endpoint webhooks are POSTed to as they are emitted
+}
+
+// Webhook is an event emitted by the server, with the Stripe-Signature
+// header it was signed with
+type Webhook struct {
+	Type      string
+	Payload   []byte
+	Signature string
+}
+
+// Server is a fake Stripe API. Point the adapter's base URL at URL.
+type Server struct {
+	URL string
+
+	server        *httptest.Server
+	webhookSecret string
+	webhookURL    string
+
+	mu             sync.Mutex
+	seq            int
+	sessions       map[string]*stripe.CheckoutSession
+	customers      map[string]*stripe.Customer
+	paymentMethods map[string]*stripe.PaymentMethod
+	paymentIntents map[string]*stripe.PaymentIntent
+	subscriptions  map[string]*stripe.Subscription
+	idempotent     map[string]*stripe.PaymentIntent // Idempotency-Key -> payment intent it created
+	decline        string                           // Decline message for payments, if set
+	webhooks       []Webhook
+}
+
+// NewServer starts a fake Stripe API. Close it when done.
+func NewServer(cfg Config) *Server {
+	s := &Server{
+		webhookSecret:  cfg.WebhookSecret,
+		webhookURL:     cfg.WebhookURL,
+		sessions:       make(map[string]*stripe.CheckoutSession),
+		customers:      make(map[string]*stripe.Customer),
+		paymentMethods: make(map[string]*stripe.PaymentMethod),
+		paymentIntents: make(map[string]*stripe.PaymentIntent),
+		subscriptions:  make(map[string]*stripe.Subscription),
+		idempotent:     make(map[string]*stripe.PaymentIntent),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/checkout/sessions", s.authed(s.createSession))
+	mux.HandleFunc("GET /v1/checkout/sessions", s.authed(s.listSessions))
+	mux.HandleFunc("GET /v1/checkout/sessions/{id}", s.authed(s.getSession))
+	mux.HandleFunc("POST /v1/checkout/sessions/{id}/expire", s.authed(s.expireSession))
+	mux.HandleFunc("POST /v1/customers", s.authed(s.saveCustomer))
+	mux.HandleFunc("POST /v1/customers/{id}", s.authed(s.saveCustomer))
+	mux.HandleFunc("POST /v1/payment_methods/{id}/attach", s.authed(s.attachPaymentMethod))
+	mux.HandleFunc("POST /v1/payment_methods/{id}/detach", s.authed(s.detachPaymentMethod))
+	mux.HandleFunc("POST /v1/payment_intents", s.authed(s.createPaymentIntent))
+	mux.HandleFunc("GET /v1/payment_intents/{id}", s.authed(s.getPaymentIntent))
+	mux.HandleFunc("POST /v1/subscriptions", s.authed(s.createSubscription))
+	mux.HandleFunc("GET /v1/subscriptions", s.authed(s.listSubscriptions))
+	mux.HandleFunc("GET /v1/subscriptions/{id}", s.authed(s.getSubscription))
+	mux.HandleFunc("DELETE /v1/subscriptions/{id}", s.authed(s.cancelSubscription))
+	mux.HandleFunc("POST /v1/refunds", s.authed(s.createRefund))
+
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Decline makes checkouts and confirmed payment intents fail with a card
+// decline carrying message; an empty message lets them succeed again
+func (s *Server) Decline(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.decline = message
+}
+
+// Webhooks returns the webhooks emitted so far, oldest first
+func (s *Server) Webhooks() []Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Webhook(nil), s.webhooks...)
+}
+
+// CompleteCheckout pays an open checkout session as the customer would on
+// Stripe's hosted page and emits checkout.session.completed. While payments
+// are declined the session is left open and an error returned.
+func (s *Server) CompleteCheckout(sessionID string) (Webhook, error) {
+	s.mu.Lock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return Webhook{}, fmt.Errorf("no such checkout session: %s", sessionID)
+	}
+	if session.Status != stripe.CheckoutSessionStatusOpen {
+		s.mu.Unlock()
+		return Webhook{}, fmt.Errorf("checkout session %s is %s", sessionID, session.Status)
+	}
+
+	if decline := s.decline; decline != "" {
+		// Declined cards are turned away on the hosted page; the session
+		// stays open and nothing is emitted
+		s.mu.Unlock()
+		return Webhook{}, fmt.Errorf("card declined: %s", decline)
+	}
+
+	pi := s.newPaymentIntent(session.AmountTotal, session.Currency, session.Customer, session.Metadata)
+	s.charge(pi)
+	session.PaymentIntent = pi
+	session.Status = stripe.CheckoutSessionStatusComplete
+	session.PaymentStatus = stripe.CheckoutSessionPaymentStatusPaid
+	wh := s.event("checkout.session.completed", session)
+	s.mu.Unlock()
+
+	return wh, s.deliver(wh)
+}
+
+// ExpireCheckout lets an open checkout session run out its time, as Stripe
+// does at expires_at, and emits checkout.session.expired
+func (s *Server) ExpireCheckout(sessionID string) (Webhook, error) {
+	s.mu.Lock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		s.mu.Unlock()
+		return Webhook{}, fmt.Errorf("no such checkout session: %s", sessionID)
+	}
+	if session.Status != stripe.CheckoutSessionStatusOpen {
+		s.mu.Unlock()
+		return Webhook{}, fmt.Errorf("checkout session %s is %s", sessionID, session.Status)
+	}
+
+	session.Status = stripe.CheckoutSessionStatusExpired
+	wh := s.event("checkout.session.expired", session)
+	s.mu.Unlock()
+
+	return wh, s.deliver(wh)
+}
+
+// authed rejects requests without a secret key and serializes the rest
+func (s *Server) authed(next func(r *http.Request) (any, []Webhook, *stripe.Error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+			writeError(w, &stripe.Error{
+				HTTPStatusCode: http.StatusUnauthorized,
+				Type:           stripe.ErrorTypeInvalidRequest,
+				Msg:            "You did not provide an API key.",
+			})
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			writeError(w, invalidRequest("Invalid request body: %v", err))
+			return
+		}
+
+		s.mu.Lock()
+		resp, webhooks, stripeErr := next(r)
+		s.mu.Unlock()
+		if stripeErr != nil {
+			writeError(w, stripeErr)
+			return
+		}
+
+		// Webhooks are delivered once the response is settled, outside the lock
+		for _, wh := range webhooks {
+			if err := s.deliver(wh); err != nil {
+				writeError(w, &stripe.Error{HTTPStatusCode: http.StatusInternalServerError, Type: stripe.ErrorTypeAPI, Msg: err.Error()})
+				return
+			}
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (s *Server) createSession(r *http.Request) (any, []Webhook, *stripe.Error) {
+	now := time.Now()
+	session := &stripe.CheckoutSession{
+		ID:            s.id("cs_test"),
+		Object:        "checkout.session",
+		Created:       now.Unix(),
+		ExpiresAt:     now.Add(24 * time.Hour).Unix(),
+		Mode:          stripe.CheckoutSessionMode(r.Form.Get("mode")),
+		Status:        stripe.CheckoutSessionStatusOpen,
+		PaymentStatus: stripe.CheckoutSessionPaymentStatusUnpaid,
+		SuccessURL:    r.Form.Get("success_url"),
+		CancelURL:     r.Form.Get("cancel_url"),
+		Metadata:      formMap(r.Form, "metadata"),
+	}
+	session.URL = s.URL + "/c/pay/" + session.ID
+	if expiresAt := formInt(r.Form, "expires_at"); expiresAt != 0 {
+		session.ExpiresAt = expiresAt
+	}
+	if customerID := r.Form.Get("customer"); customerID != "" {
+		if _, ok := s.customers[customerID]; !ok {
+			return nil, nil, noSuchResource("customer", customerID)
+		}
+		session.Customer = &stripe.Customer{ID: customerID}
+	}
+
+	for i := 0; ; i++ {
+		prefix := fmt.Sprintf("line_items[%d]", i)
+		if _, ok := r.Form[prefix+"[price_data][currency]"]; !ok {
+			break
+		}
+		quantity := formInt(r.Form, prefix+"[quantity]")
+		if quantity == 0 {
+			quantity = 1
+		}
+		session.Currency = stripe.Currency(strings.ToLower(r.Form.Get(prefix + "[price_data][currency]")))
+		session.AmountSubtotal += formInt(r.Form, prefix+"[price_data][unit_amount]") * quantity
+	}
+	if session.Currency == "" {
+		return nil, nil, invalidRequest("Missing required param: line_items.")
+	}
+	session.AmountTotal = session.AmountSubtotal
+
+	s.sessions[session.ID] = session
+	return session, nil, nil
+}
+
+func (s *Server) getSession(r *http.Request) (any, []Webhook, *stripe.Error) {
+	session, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("checkout.session", r.PathValue("id"))
+	}
+	return session, nil, nil
+}
+
+func (s *Server) expireSession(r *http.Request) (any, []Webhook, *stripe.Error) {
+	session, ok := s.sessions[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("checkout.session", r.PathValue("id"))
+	}
+	if session.Status != stripe.CheckoutSessionStatusOpen {
+		return nil, nil, invalidRequest("Only Checkout Sessions with a status of `open` can be expired.")
+	}
+	session.Status = stripe.CheckoutSessionStatusExpired
+	return session, []Webhook{s.event("checkout.session.expired", session)}, nil
+}
+
+func (s *Server) listSessions(r *http.Request) (any, []Webhook, *stripe.Error) {
+	sessions := make([]*stripe.CheckoutSession, 0, len(s.sessions))
+	status, paymentIntent := r.Form.Get("status"), r.Form.Get("payment_intent")
+	for _, session := range s.sessions {
+		if paymentIntent != "" && (session.PaymentIntent == nil || session.PaymentIntent.ID != paymentIntent) {
+			continue
+		}
+		if (status == "" || string(session.Status) == status) && inCreatedRange(r.Form, session.Created) {
+			sessions = append(sessions, session)
+		}
+	}
+	return page(r, sessions, func(session *stripe.CheckoutSession) (string, int64) { return session.ID, session.Created })
+}
+
+func (s *Server) saveCustomer(r *http.Request) (any, []Webhook, *stripe.Error) {
+	customer := &stripe.Customer{ID: s.id("cus_test"), Object: "customer", Created: time.Now().Unix()}
+	if id := r.PathValue("id"); id != "" {
+		existing, ok := s.customers[id]
+		if !ok {
+			return nil, nil, noSuchResource("customer", id)
+		}
+		customer = existing
+	}
+
+	if _, ok := r.Form["name"]; ok {
+		customer.Name = r.Form.Get("name")
+	}
+	if _, ok := r.Form["email"]; ok {
+		customer.Email = r.Form.Get("email")
+	}
+	if address := formMap(r.Form, "address"); address != nil {
+		customer.Address = &stripe.Address{
+			Line1:      address["line1"],
+			Line2:      address["line2"],
+			City:       address["city"],
+			State:      address["state"],
+			PostalCode: address["postal_code"],
+			Country:    address["country"],
+		}
+	}
+	if metadata := formMap(r.Form, "metadata"); metadata != nil {
+		customer.Metadata = metadata
+	}
+	if pmID := r.Form.Get("invoice_settings[default_payment_method]"); pmID != "" {
+		pm, ok := s.paymentMethods[pmID]
+		if !ok || pm.Customer == nil || pm.Customer.ID != customer.ID {
+			return nil, nil, invalidRequest("The payment method %s is not attached to customer %s.", pmID, customer.ID)
+		}
+		customer.InvoiceSettings = &stripe.CustomerInvoiceSettings{DefaultPaymentMethod: &stripe.PaymentMethod{ID: pmID}}
+	}
+
+	s.customers[customer.ID] = customer
+	return customer, nil, nil
+}
+
+// attachPaymentMethod attaches a payment method to a customer. Stripe's test
+// payment methods, such as pm_card_visa, create a new card when attached.
+func (s *Server) attachPaymentMethod(r *http.Request) (any, []Webhook, *stripe.Error) {
+	customerID := r.Form.Get("customer")
+	if _, ok := s.customers[customerID]; !ok {
+		return nil, nil, noSuchResource("customer", customerID)
+	}
+
+	id := r.PathValue("id")
+	pm, ok := s.paymentMethods[id]
+	if !ok {
+		brand, isTestCard := strings.CutPrefix(id, "pm_card_")
+		if !isTestCard {
+			return nil, nil, noSuchResource("payment_method", id)
+		}
+		pm = &stripe.PaymentMethod{
+			ID:      s.id("pm_test"),
+			Object:  "payment_method",
+			Type:    stripe.PaymentMethodTypeCard,
+			Created: time.Now().Unix(),
+			Card: &stripe.PaymentMethodCard{
+				Brand:    stripe.PaymentMethodCardBrand(brand),
+				Funding:  stripe.CardFundingCredit,
+				Last4:    "4242",
+				ExpMonth: 12,
+				ExpYear:  int64(time.Now().Year() + 2),
+			},
+		}
+		s.paymentMethods[pm.ID] = pm
+	}
+	pm.Customer = &stripe.Customer{ID: customerID}
+	return pm, nil, nil
+}
+
+func (s *Server) detachPaymentMethod(r *http.Request) (any, []Webhook, *stripe.Error) {
+	pm, ok := s.paymentMethods[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("payment_method", r.PathValue("id"))
+	}
+	if pm.Customer == nil {
+		return nil, nil, invalidRequest("The payment method you provided is not attached to a customer so detachment is impossible.")
+	}
+	pm.Customer = nil
+	return pm, nil, nil
+}
+
+// createPaymentIntent creates a payment intent, charging it straight away
+// when it is confirmed. Requests retried with the same Idempotency-Key get
+// the payment intent the first one created.
+func (s *Server) createPaymentIntent(r *http.Request) (any, []Webhook, *stripe.Error) {
+	key := r.Header.Get("Idempotency-Key")
+	if pi, ok := s.idempotent[key]; ok && key != "" {
+		return pi, nil, nil
+	}
+
+	amount := formInt(r.Form, "amount")
+	if amount <= 0 {
+		return nil, nil, invalidRequest("Missing required param: amount.")
+	}
+	var customer *stripe.Customer
+	if customerID := r.Form.Get("customer"); customerID != "" {
+		if _, ok := s.customers[customerID]; !ok {
+			return nil, nil, noSuchResource("customer", customerID)
+		}
+		customer = &stripe.Customer{ID: customerID}
+	}
+
+	pi := s.newPaymentIntent(amount, stripe.Currency(strings.ToLower(r.Form.Get("currency"))), customer, formMap(r.Form, "metadata"))
+	pi.Description = r.Form.Get("description")
+	if pmID := r.Form.Get("payment_method"); pmID != "" {
+		if _, ok := s.paymentMethods[pmID]; !ok {
+			return nil, nil, noSuchResource("payment_method", pmID)
+		}
+		pi.PaymentMethod = &stripe.PaymentMethod{ID: pmID}
+	}
+	if key != "" {
+		s.idempotent[key] = pi
+	}
+
+	if r.Form.Get("confirm") != "true" {
+		return pi, nil, nil
+	}
+	if s.decline != "" {
+		pi.Status = stripe.PaymentIntentStatusRequiresPaymentMethod
+		return nil, []Webhook{s.event("payment_intent.payment_failed", pi)}, &stripe.Error{
+			HTTPStatusCode: http.StatusPaymentRequired,
+			Type:           stripe.ErrorTypeCard,
+			Code:           stripe.ErrorCodeCardDeclined,
+			Msg:            s.decline,
+			PaymentIntent:  pi,
+		}
+	}
+	s.charge(pi)
+	return pi, []Webhook{s.event("payment_intent.succeeded", pi)}, nil
+}
+
+func (s *Server) getPaymentIntent(r *http.Request) (any, []Webhook, *stripe.Error) {
+	pi, ok := s.paymentIntents[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("payment_intent", r.PathValue("id"))
+	}
+	return pi, nil, nil
+}
+
+func (s *Server) createSubscription(r *http.Request) (any, []Webhook, *stripe.Error) {
+	customerID := r.Form.Get("customer")
+	if _, ok := s.customers[customerID]; !ok {
+		return nil, nil, noSuchResource("customer", customerID)
+	}
+
+	now := time.Now()
+	sub := &stripe.Subscription{
+		ID:                 s.id("sub_test"),
+		Object:             "subscription",
+		Customer:           &stripe.Customer{ID: customerID},
+		Status:             stripe.SubscriptionStatusActive,
+		Created:            now.Unix(),
+		CurrentPeriodStart: now.Unix(),
+		CurrentPeriodEnd:   now.AddDate(0, 1, 0).Unix(),
+		Metadata:           formMap(r.Form, "metadata"),
+	}
+	s.subscriptions[sub.ID] = sub
+	return sub, []Webhook{s.event("customer.subscription.created", sub)}, nil
+}
+
+func (s *Server) getSubscription(r *http.Request) (any, []Webhook, *stripe.Error) {
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("subscription", r.PathValue("id"))
+	}
+	return sub, nil, nil
+}
+
+func (s *Server) cancelSubscription(r *http.Request) (any, []Webhook, *stripe.Error) {
+	sub, ok := s.subscriptions[r.PathValue("id")]
+	if !ok {
+		return nil, nil, noSuchResource("subscription", r.PathValue("id"))
+	}
+	sub.Status = stripe.SubscriptionStatusCanceled
+	sub.CanceledAt = time.Now().Unix()
+	return sub, []Webhook{s.event("customer.subscription.deleted", sub)}, nil
+}
+
+// listSubscriptions lists subscriptions; Stripe leaves cancelled ones out
+// unless asked for them or for all
+func (s *Server) listSubscriptions(r *http.Request) (any, []Webhook, *stripe.Error) {
+	subs := make([]*stripe.Subscription, 0, len(s.subscriptions))
+	status := r.Form.Get("status")
+	for _, sub := range s.subscriptions {
+		switch {
+		case !inCreatedRange(r.Form, sub.Created):
+		case status == "all", string(sub.Status) == status,
+			status == "" && sub.Status != stripe.SubscriptionStatusCanceled:
+			subs = append(subs, sub)
+		}
+	}
+	return page(r, subs, func(sub *stripe.Subscription) (string, int64) { return sub.ID, sub.Created })
+}
+
+// createRefund refunds a payment intent's charge, in full unless an amount
+// is given, and emits charge.refunded
+func (s *Server) createRefund(r *http.Request) (any, []Webhook, *stripe.Error) {
+	var pi *stripe.PaymentIntent
+	if id := r.Form.Get("payment_intent"); id != "" {
+		pi = s.paymentIntents[id]
+	} else if id := r.Form.Get("charge"); id != "" {
+		for _, candidate := range s.paymentIntents {
+			if candidate.LatestCharge != nil && candidate.LatestCharge.ID == id {
+				pi = candidate
+			}
+		}
+	}
+	if pi == nil || pi.LatestCharge == nil {
+		return nil, nil, invalidRequest("No charge to refund; provide a succeeded payment_intent or charge.")
+	}
+
+	charge := pi.LatestCharge
+	amount := formInt(r.Form, "amount")
+	if amount == 0 {
+		amount = charge.Amount - charge.AmountRefunded
+	}
+	if amount <= 0 || charge.AmountRefunded+amount > charge.Amount {
+		return nil, nil, invalidRequest("Refund amount (%d) is greater than unrefunded amount on charge (%d).", amount, charge.Amount-charge.AmountRefunded)
+	}
+	previous := charge.AmountRefunded
+	charge.AmountRefunded += amount
+	charge.Refunded = charge.AmountRefunded == charge.Amount
+
+	refund := &stripe.Refund{
+		ID:            s.id("re_test"),
+		Object:        "refund",
+		Amount:        amount,
+		Currency:      charge.Currency,
+		Charge:        &stripe.Charge{ID: charge.ID},
+		PaymentIntent: &stripe.PaymentIntent{ID: pi.ID},
+		Status:        stripe.RefundStatusSucceeded,
+		Created:       time.Now().Unix(),
+	}
+	return refund, []Webhook{s.updateEvent("charge.refunded", charge, map[string]any{"amount_refunded": previous})}, nil
+}
+
+// newPaymentIntent records a payment intent awaiting confirmation
+func (s *Server) newPaymentIntent(amount int64, currency stripe.Currency, customer *stripe.Customer, metadata map[string]string) *stripe.PaymentIntent {
+	pi := &stripe.PaymentIntent{
+		ID:       s.id("pi_test"),
+		Object:   "payment_intent",
+		Amount:   amount,
+		Currency: currency,
+		Customer: customer,
+		Metadata: metadata,
+		Status:   stripe.PaymentIntentStatusRequiresConfirmation,
+		Created:  time.Now().Unix(),
+	}
+	s.paymentIntents[pi.ID] = pi
+	return pi
+}
+
+// charge captures a payment intent in full
+func (s *Server) charge(pi *stripe.PaymentIntent) {
+	pi.Status = stripe.PaymentIntentStatusSucceeded
+	pi.AmountReceived = pi.Amount
+	pi.LatestCharge = &stripe.Charge{
+		ID:             s.id("ch_test"),
+		Object:         "charge",
+		Amount:         pi.Amount,
+		AmountCaptured: pi.Amount,
+		Currency:       pi.Currency,
+		Captured:       true,
+		Paid:           true,
+		Status:         stripe.ChargeStatusSucceeded,
+		Metadata:       pi.Metadata,
+		PaymentIntent:  &stripe.PaymentIntent{ID: pi.ID},
+		Created:        time.Now().Unix(),
+	}
+}
+
+// event records a signed webhook carrying object
+func (s *Server) event(eventType string, object any) Webhook {
+	return s.updateEvent(eventType, object, nil)
+}
+
+// updateEvent records a signed webhook for an object whose listed
+// attributes changed from their previous values
+func (s *Server) updateEvent(eventType string, object any, previous map[string]any) Webhook {
+	data := map[string]any{"object": object}
+	if previous != nil {
+		data["previous_attributes"] = previous
+	}
+	payload, err := json.Marshal(map[string]any{
+		"id":          s.id("evt_test"),
+		"object":      "event",
+		"api_version": stripe.APIVersion,
+		"created":     time.Now().Unix(),
+		"livemode":    false,
+		"type":        eventType,
+		"data":        data,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("stripetest: failed to encode %s event: %v", eventType, err))
+	}
+
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{Payload: payload, Secret: s.webhookSecret})
+	wh := Webhook{Type: eventType, Payload: payload, Signature: signed.Header}
+	s.webhooks = append(s.webhooks, wh)
+	return wh
+}
+
+// deliver POSTs a webhook to the configured endpoint, if any
+func (s *Server) deliver(wh Webhook) error {
+	if s.webhookURL == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.webhookURL, bytes.NewReader(wh.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Stripe-Signature", wh.Signature)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver %s webhook: %w", wh.Type, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook rejected with status %d", wh.Type, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *Server) id(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%d", prefix, s.seq)
+}
+
+// page returns one page of a Stripe list, newest first, honouring limit and
+// starting_after
+func page[T any](r *http.Request, items []T, key func(T) (string, int64)) (any, []Webhook, *stripe.Error) {
+	sort.Slice(items, func(i, j int) bool {
+		idI, createdI := key(items[i])
+		idJ, createdJ := key(items[j])
+		if createdI != createdJ {
+			return createdI > createdJ
+		}
+		return sequence(idI) > sequence(idJ)
+	})
+
+	if cursor := r.Form.Get("starting_after"); cursor != "" {
+		for i, item := range items {
+			if id, _ := key(item); id == cursor {
+				items = items[i+1:]
+				break
+			}
+		}
+	}
+
+	limit := int(formInt(r.Form, "limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	return map[string]any{
+		"object":   "list",
+		"url":      r.URL.Path,
+		"has_more": hasMore,
+		"data":     items,
+	}, nil, nil
+}
+
+// sequence returns the counter an ID was generated with, so that objects
+// created within the same second list in creation order
+func sequence(id string) int {
+	n, _ := strconv.Atoi(id[strings.LastIndex(id, "_")+1:])
+	return n
+}
+
+// inCreatedRange reports whether a creation time is within the created
+// filter of a list request
+func inCreatedRange(form url.Values, created int64) bool {
+	if gte := formInt(form, "created[gte]"); gte != 0 && created < gte {
+		return false
+	}
+	if lt := formInt(form, "created[lt]"); lt != 0 && created >= lt {
+		return false
+	}
+	return true
+}
+
+// formMap collects the form-encoded hash under name, e.g. metadata[user_id]
+func formMap(form url.Values, name string) map[string]string {
+	var m map[string]string
+	for key, values := range form {
+		field, ok := strings.CutPrefix(key, name+"[")
+		if !ok || !strings.HasSuffix(field, "]") || strings.Contains(field, "[") {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[strings.TrimSuffix(field, "]")] = values[0]
+	}
+	return m
+}
+
+func formInt(form url.Values, key string) int64 {
+	n, _ := strconv.ParseInt(form.Get(key), 10, 64)
+	return n
+}
+
+func invalidRequest(format string, args ...any) *stripe.Error {
+	return &stripe.Error{
+		HTTPStatusCode: http.StatusBadRequest,
+		Type:           stripe.ErrorTypeInvalidRequest,
+		Msg:            fmt.Sprintf(format, args...),
+	}
+}
+
+func noSuchResource(resource, id string) *stripe.Error {
+	return &stripe.Error{
+		HTTPStatusCode: http.StatusNotFound,
+		Type:           stripe.ErrorTypeInvalidRequest,
+		Code:           stripe.ErrorCodeResourceMissing,
+		Msg:            fmt.Sprintf("No such %s: '%s'", resource, id),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err *stripe.Error) {
+	writeJSON(w, err.HTTPStatusCode, map[string]any{"error": err})
+}
@@ -0,0 +1,171 @@
+package billing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jia-app/paymentservice/internal/shared/tracing"
+)
+
+// tracedProvider wraps a Provider and records a client span for every call
+type tracedProvider struct {
+	name string
+	next Provider
+}
+
+// WithTracing wraps a provider so that each call is recorded as a span named
+// "billing.<Method>" tagged with the provider name
+func WithTracing(name string, provider Provider) Provider {
+	return &tracedProvider{name: name, next: provider}
+}
+
+func (p *tracedProvider) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("billing.provider", p.name))
+	return tracing.Start(ctx, "billing."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// CreateCheckoutSession implements Provider
+func (p *tracedProvider) CreateCheckoutSession(ctx context.Context, req CreateCheckoutSessionRequest) (*CreateCheckoutSessionResponse, error) {
+	ctx, span := p.start(ctx, "CreateCheckoutSession",
+		attribute.String("billing.plan_id", req.PlanID.String()),
+		attribute.String("billing.currency", req.Currency),
+	)
+	resp, err := p.next.CreateCheckoutSession(ctx, req)
+	if resp != nil {
+		span.SetAttributes(attribute.String("billing.session_id", resp.SessionID))
+		if resp.Provider != "" {
+			span.SetAttributes(attribute.String("billing.routed_provider", resp.Provider))
+		}
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+// GetSession implements Provider
+func (p *tracedProvider) GetSession(ctx context.Context, sessionID string) (*Session, error) {
+	ctx, span := p.start(ctx, "GetSession", attribute.String("billing.session_id", sessionID))
+	session, err := p.next.GetSession(ctx, sessionID)
+	tracing.End(span, err)
+	return session, err
+}
+
+// CancelSession implements Provider
+func (p *tracedProvider) CancelSession(ctx context.Context, sessionID string) error {
+	ctx, span := p.start(ctx, "CancelSession", attribute.String("billing.session_id", sessionID))
+	err := p.next.CancelSession(ctx, sessionID)
+	tracing.End(span, err)
+	return err
+}
+
+// ValidateWebhook implements Provider
+func (p *tracedProvider) ValidateWebhook(ctx context.Context, payload []byte, signature string) error {
+	ctx, span := p.start(ctx, "ValidateWebhook")
+	err := p.next.ValidateWebhook(ctx, payload, signature)
+	tracing.End(span, err)
+	return err
+}
+
+// ParseWebhook implements Provider
+func (p *tracedProvider) ParseWebhook(ctx context.Context, payload []byte) (*WebhookResult, error) {
+	ctx, span := p.start(ctx, "ParseWebhook")
+	result, err := p.next.ParseWebhook(ctx, payload)
+	if result != nil {
+		span.SetAttributes(attribute.String("billing.event_type", result.EventType))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// SaveCustomer implements Provider
+func (p *tracedProvider) SaveCustomer(ctx context.Context, req SaveCustomerRequest) (string, error) {
+	ctx, span := p.start(ctx, "SaveCustomer", attribute.String("billing.customer_id", req.CustomerID))
+	customerID, err := p.next.SaveCustomer(ctx, req)
+	span.SetAttributes(attribute.String("billing.customer_id", customerID))
+	tracing.End(span, err)
+	return customerID, err
+}
+
+// AttachPaymentMethod implements Provider
+func (p *tracedProvider) AttachPaymentMethod(ctx context.Context, customerID, token string) (*PaymentMethodDetails, error) {
+	ctx, span := p.start(ctx, "AttachPaymentMethod", attribute.String("billing.customer_id", customerID))
+	details, err := p.next.AttachPaymentMethod(ctx, customerID, token)
+	if details != nil {
+		span.SetAttributes(attribute.String("billing.payment_method_id", details.ID))
+	}
+	tracing.End(span, err)
+	return details, err
+}
+
+// DetachPaymentMethod implements Provider
+func (p *tracedProvider) DetachPaymentMethod(ctx context.Context, paymentMethodID string) error {
+	ctx, span := p.start(ctx, "DetachPaymentMethod", attribute.String("billing.payment_method_id", paymentMethodID))
+	err := p.next.DetachPaymentMethod(ctx, paymentMethodID)
+	tracing.End(span, err)
+	return err
+}
+
+// SetDefaultPaymentMethod implements Provider
+func (p *tracedProvider) SetDefaultPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	ctx, span := p.start(ctx, "SetDefaultPaymentMethod",
+		attribute.String("billing.customer_id", customerID),
+		attribute.String("billing.payment_method_id", paymentMethodID),
+	)
+	err := p.next.SetDefaultPaymentMethod(ctx, customerID, paymentMethodID)
+	tracing.End(span, err)
+	return err
+}
+
+// ChargePaymentMethod implements Provider
+func (p *tracedProvider) ChargePaymentMethod(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	ctx, span := p.start(ctx, "ChargePaymentMethod",
+		attribute.String("billing.customer_id", req.CustomerID),
+		attribute.String("billing.currency", req.Currency),
+	)
+	result, err := p.next.ChargePaymentMethod(ctx, req)
+	if result != nil {
+		span.SetAttributes(attribute.String("billing.charge_status", string(result.Status)))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// ListCharges implements Provider
+func (p *tracedProvider) ListCharges(ctx context.Context, req ListRequest) (*ChargePage, error) {
+	ctx, span := p.start(ctx, "ListCharges",
+		attribute.Bool("billing.first_page", req.Cursor == ""),
+	)
+	page, err := p.next.ListCharges(ctx, req)
+	if page != nil {
+		span.SetAttributes(attribute.Int("billing.count", len(page.Charges)))
+	}
+	tracing.End(span, err)
+	return page, err
+}
+
+// ListSubscriptions implements Provider
+func (p *tracedProvider) ListSubscriptions(ctx context.Context, req ListRequest) (*SubscriptionPage, error) {
+	ctx, span := p.start(ctx, "ListSubscriptions",
+		attribute.Bool("billing.first_page", req.Cursor == ""),
+	)
+	page, err := p.next.ListSubscriptions(ctx, req)
+	if page != nil {
+		span.SetAttributes(attribute.Int("billing.count", len(page.Subscriptions)))
+	}
+	tracing.End(span, err)
+	return page, err
+}
+
+// Handles reports whether the wrapped provider is or routes to the named provider
+func (p *tracedProvider) Handles(name string) bool {
+	return name == p.name || Handles(p.next, name)
+}
+
+// Close implements Provider
+func (p *tracedProvider) Close() error {
+	return p.next.Close()
+}
@@ -0,0 +1,103 @@
+// Package audit records who changed payments, entitlements, subscriptions
+// and the catalog, what they changed and why.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+type reasonKey struct{}
+
+// WithReason attaches the caller's reason for a change to the context, to
+// be recorded with any audit event written while handling the request
+func WithReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFromContext returns the reason attached by WithReason, if any
+func ReasonFromContext(ctx context.Context) string {
+	reason, _ := ctx.Value(reasonKey{}).(string)
+	return reason
+}
+
+// Change describes one change to an entity. Before is nil for created
+// entities and After for deleted ones.
+type Change struct {
+	EntityType domain.AuditEntityType
+	EntityID   string
+	Action     string
+	Before     any
+	After      any
+	Reason     string // Overrides the reason from the context when set
+}
+
+// Recorder writes audit events for changes made by use cases. The actor
+// and request ID come from the request context, as set by the auth and
+// logging interceptors. A nil Recorder records nothing.
+type Recorder struct {
+	auditRepo repo.AuditRepository
+	now       func() time.Time
+}
+
+// NewRecorder creates a new audit recorder
+func NewRecorder(auditRepo repo.AuditRepository) *Recorder {
+	return &Recorder{
+		auditRepo: auditRepo,
+		now:       time.Now,
+	}
+}
+
+// Record writes an audit event for a change that has been applied. The
+// change cannot be undone at this point, so failures are logged rather
+// than returned.
+func (r *Recorder) Record(ctx context.Context, change Change) {
+	if r == nil {
+		return
+	}
+
+	event, err := r.event(ctx, change)
+	if err == nil {
+		_, err = r.auditRepo.Record(ctx, event)
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to record audit event",
+			zap.String("entity_type", string(change.EntityType)),
+			zap.String("entity_id", change.EntityID),
+			zap.String("action", change.Action),
+			zap.Error(err))
+	}
+}
+
+// event builds the audit event of a change made within a request
+func (r *Recorder) event(ctx context.Context, change Change) (domain.AuditEvent, error) {
+	changes, err := domain.DiffAudit(change.Before, change.After)
+	if err != nil {
+		return domain.AuditEvent{}, err
+	}
+
+	actorID, _ := ctx.Value(log.UserIDKey).(string)
+	requestID, _ := ctx.Value(log.RequestIDKey).(string)
+	reason := change.Reason
+	if reason == "" {
+		reason = ReasonFromContext(ctx)
+	}
+
+	return domain.AuditEvent{
+		EntityType: change.EntityType,
+		EntityID:   change.EntityID,
+		Action:     change.Action,
+		ActorType:  domain.AuditActorFromID(actorID),
+		ActorID:    actorID,
+		Changes:    changes,
+		RequestID:  requestID,
+		Reason:     reason,
+		OccurredAt: r.now(),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// memoryAuditRepo keeps recorded events in memory
+type memoryAuditRepo struct {
+	repo.AuditRepository
+	events []domain.AuditEvent
+	err    error
+}
+
+func (r *memoryAuditRepo) Record(ctx context.Context, event domain.AuditEvent) (*domain.AuditEvent, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	r.events = append(r.events, event)
+	return &event, nil
+}
+
+func TestRecorder_Record(t *testing.T) {
+	events := &memoryAuditRepo{}
+	recorder := NewRecorder(events)
+
+	ctx := log.WithUserID(context.Background(), "spiffe://jia.app/family-service")
+	ctx = log.WithRequestID(ctx, "req-1")
+	ctx = WithReason(ctx, "support ticket 42")
+
+	recorder.Record(ctx, Change{
+		EntityType: domain.AuditEntityPlan,
+		EntityID:   "premium",
+		Action:     domain.AuditActionUpdate,
+		Before:     map[string]any{"price": 999, "name": "Premium"},
+		After:      map[string]any{"price": 1299, "name": "Premium"},
+	})
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	e := events.events[0]
+	if e.ActorType != domain.AuditActorService || e.ActorID != "spiffe://jia.app/family-service" {
+		t.Errorf("expected the service actor from the context, got %s %q", e.ActorType, e.ActorID)
+	}
+	if e.RequestID != "req-1" || e.Reason != "support ticket 42" {
+		t.Errorf("unexpected request ID or reason: %q %q", e.RequestID, e.Reason)
+	}
+	if len(e.Changes) != 1 || string(e.Changes["price"].After) != "1299" {
+		t.Errorf("expected only the price to change, got %v", e.Changes)
+	}
+	if e.OccurredAt.IsZero() {
+		t.Error("expected the event time to be set")
+	}
+}
+
+func TestRecorder_RecordSystemChange(t *testing.T) {
+	events := &memoryAuditRepo{}
+	recorder := NewRecorder(events)
+
+	recorder.Record(WithReason(context.Background(), "ignored"), Change{
+		EntityType: domain.AuditEntitySubscription,
+		EntityID:   "sub-1",
+		Action:     domain.AuditActionStatusChange,
+		Before:     map[string]string{"status": "active"},
+		After:      map[string]string{"status": "past_due"},
+		Reason:     "card_declined",
+	})
+
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	if e := events.events[0]; e.ActorType != domain.AuditActorSystem || e.ActorID != "" || e.Reason != "card_declined" {
+		t.Errorf("expected a system change with the explicit reason, got %+v", e)
+	}
+}
+
+func TestRecorder_RecordFailures(t *testing.T) {
+	// Failures are logged; the change has already been applied
+	recorder := NewRecorder(&memoryAuditRepo{err: errors.New("database is down")})
+	recorder.Record(context.Background(), Change{EntityType: domain.AuditEntityPayment, EntityID: "p1", Action: domain.AuditActionStatusChange})
+
+	var disabled *Recorder
+	disabled.Record(context.Background(), Change{EntityType: domain.AuditEntityPayment, EntityID: "p1", Action: domain.AuditActionStatusChange})
+}
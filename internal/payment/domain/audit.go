@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntityType is the kind of record an audit event describes
+type AuditEntityType string
+
+const (
+	AuditEntityPayment      AuditEntityType = "payment"
+	AuditEntityEntitlement  AuditEntityType = "entitlement"
+	AuditEntitySubscription AuditEntityType = "subscription"
+	AuditEntityPlan         AuditEntityType = "plan"
+	AuditEntityPricingZone  AuditEntityType = "pricing_zone"
+	AuditEntityFeature      AuditEntityType = "feature"
+)
+
+// ParseAuditEntityType validates an entity type name
+func ParseAuditEntityType(s string) (AuditEntityType, error) {
+	switch t := AuditEntityType(s); t {
+	case AuditEntityPayment, AuditEntityEntitlement, AuditEntitySubscription, AuditEntityPlan, AuditEntityPricingZone, AuditEntityFeature:
+		return t, nil
+	default:
+		return "", NewInvalidInputError("invalid audit entity type", fmt.Sprintf("want payment, entitlement, subscription, plan, pricing_zone or feature: %q", s))
+	}
+}
+
+// Audit actions
+const (
+	AuditActionCreate       = "create"
+	AuditActionUpdate       = "update"
+	AuditActionDelete       = "delete"
+	AuditActionArchive      = "archive"
+	AuditActionStatusChange = "status_change"
+	AuditActionRenew        = "renew"
+	AuditActionRevoke       = "revoke"
+	AuditActionGrant        = "grant"
+	AuditActionExtend       = "extend"
+)
+
+// AuditActorType tells who made a change
+type AuditActorType string
+
+const (
+	AuditActorUser    AuditActorType = "user"    // An authenticated end user or admin
+	AuditActorService AuditActorType = "service" // Another service, identified by its SPIFFE ID
+	AuditActorSystem  AuditActorType = "system"  // This service itself, e.g. a webhook or a worker
+)
+
+// AuditActorFromID classifies the authenticated identity of a request.
+// SPIFFE IDs identify services; an empty ID means no caller was
+// authenticated, so the change was made by the system.
+func AuditActorFromID(id string) AuditActorType {
+	switch {
+	case id == "":
+		return AuditActorSystem
+	case strings.HasPrefix(id, "spiffe://"):
+		return AuditActorService
+	default:
+		return AuditActorUser
+	}
+}
+
+// AuditFieldChange is the value of one field before and after a change.
+// Before is empty for created records, After for deleted ones.
+type AuditFieldChange struct {
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// AuditEvent records one change to a payment, entitlement, subscription,
+// plan or pricing zone: who made it, what changed and why
+type AuditEvent struct {
+	ID         uuid.UUID                   `json:"id"`
+	EntityType AuditEntityType             `json:"entity_type"`
+	EntityID   string                      `json:"entity_id"`
+	Action     string                      `json:"action"`
+	ActorType  AuditActorType              `json:"actor_type"`
+	ActorID    string                      `json:"actor_id,omitempty"`
+	Changes    map[string]AuditFieldChange `json:"changes"` // By field name
+	RequestID  string                      `json:"request_id,omitempty"`
+	Reason     string                      `json:"reason,omitempty"`
+	OccurredAt time.Time                   `json:"occurred_at"`
+}
+
+// Validate checks that an event identifies what changed and who changed it
+func (e *AuditEvent) Validate() error {
+	if e.EntityID == "" {
+		return NewInvalidInputError("invalid audit event", "entity_id is required")
+	}
+	if _, err := ParseAuditEntityType(string(e.EntityType)); err != nil {
+		return err
+	}
+	if e.Action == "" {
+		return NewInvalidInputError("invalid audit event", "action is required")
+	}
+	if e.ActorType != AuditActorFromID(e.ActorID) {
+		return NewInvalidInputError("invalid audit event", fmt.Sprintf("actor %q is not a %s", e.ActorID, e.ActorType))
+	}
+	return nil
+}
+
+// auditIgnoredFields change on every write and are left out of diffs
+var auditIgnoredFields = map[string]bool{"updated_at": true}
+
+// DiffAudit compares the JSON form of a record before and after a change
+// and returns the top-level fields that differ. Either side may be nil,
+// for records that were created or deleted.
+func DiffAudit(before, after any) (map[string]AuditFieldChange, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]AuditFieldChange)
+	for field, b := range beforeFields {
+		if a, ok := afterFields[field]; !ok || !bytes.Equal(a, b) {
+			changes[field] = AuditFieldChange{Before: b, After: a}
+		}
+	}
+	for field, a := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = AuditFieldChange{After: a}
+		}
+	}
+	return changes, nil
+}
+
+// auditFields flattens a record into its top-level JSON fields, dropping
+// ignored fields and nulls
+func auditFields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audited record: %w", err)
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("audited record is not a JSON object: %w", err)
+	}
+	for field, value := range fields {
+		if auditIgnoredFields[field] || bytes.Equal(value, []byte("null")) {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// AuditFilter narrows an audit event listing. Zero-valued fields are ignored.
+type AuditFilter struct {
+	EntityType AuditEntityType `json:"entity_type,omitempty"`
+	EntityID   string          `json:"entity_id,omitempty"`
+	ActorID    string          `json:"actor_id,omitempty"`
+}
+
+// AuditCursor identifies the last event of a page. Events are listed
+// newest first by (occurred_at, id).
+type AuditCursor struct {
+	OccurredAt time.Time `json:"t"`
+	ID         uuid.UUID `json:"id"`
+}
+
+// AuditPage is one page of an audit event listing
+type AuditPage struct {
+	Events        []*AuditEvent
+	NextPageToken string // Empty on the last page
+}
+
+// EncodeAuditPageToken serializes a cursor into an opaque page token
+func EncodeAuditPageToken(c *AuditCursor) string {
+	if c == nil {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeAuditPageToken parses a page token produced by EncodeAuditPageToken
+func DecodeAuditPageToken(token string) (*AuditCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token is not valid base64")
+	}
+
+	var c AuditCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token payload is malformed")
+	}
+	if c.OccurredAt.IsZero() || c.ID == uuid.Nil {
+		return nil, NewInvalidInputError("invalid page token", "token is missing an event position")
+	}
+
+	return &c, nil
+}
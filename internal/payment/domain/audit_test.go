@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffAudit(t *testing.T) {
+	before := Payment{ID: uuid.New(), Amount: 9.99, Currency: "USD", Status: "pending", UpdatedAt: time.Now()}
+	after := before
+	after.Status = "completed"
+	after.ExternalPaymentID = "pi_123"
+	after.UpdatedAt = before.UpdatedAt.Add(time.Second)
+
+	changes, err := DiffAudit(before, after)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected status and external_payment_id to change, got %v", changes)
+	}
+	if c := changes["status"]; string(c.Before) != `"pending"` || string(c.After) != `"completed"` {
+		t.Errorf("unexpected status change: before %s, after %s", c.Before, c.After)
+	}
+	if _, ok := changes["updated_at"]; ok {
+		t.Error("expected updated_at to be ignored")
+	}
+}
+
+func TestDiffAudit_CreateAndDelete(t *testing.T) {
+	zone := PricingZone{ISOCode: "DE", Country: "Germany", Zone: "A", PricingMultiplier: 1}
+
+	created, err := DiffAudit(nil, zone)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := created["iso_code"]; c.Before != nil || string(c.After) != `"DE"` {
+		t.Errorf("expected every field of a created record, got %v", created)
+	}
+
+	deleted, err := DiffAudit(&zone, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := deleted["country"]; string(c.Before) != `"Germany"` || c.After != nil {
+		t.Errorf("expected every field of a deleted record, got %v", deleted)
+	}
+
+	if _, err := DiffAudit("not an object", nil); err == nil {
+		t.Error("expected an error for a record that is not an object")
+	}
+}
+
+func TestAuditEvent_Validate(t *testing.T) {
+	event := AuditEvent{EntityType: AuditEntityPlan, EntityID: "premium", Action: AuditActionUpdate, ActorType: AuditActorService, ActorID: "spiffe://jia.app/family-service"}
+	if err := event.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	event.ActorType = AuditActorUser
+	if err := event.Validate(); err == nil {
+		t.Error("expected an error for a SPIFFE ID recorded as a user")
+	}
+
+	event = AuditEvent{EntityType: "invoice", EntityID: "1", Action: AuditActionCreate, ActorType: AuditActorSystem}
+	if err := event.Validate(); err == nil {
+		t.Error("expected an error for an unknown entity type")
+	}
+}
+
+func TestAuditPageToken(t *testing.T) {
+	cursor := &AuditCursor{OccurredAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), ID: uuid.New()}
+	c, err := DecodeAuditPageToken(EncodeAuditPageToken(cursor))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.OccurredAt.Equal(cursor.OccurredAt) || c.ID != cursor.ID {
+		t.Errorf("expected %+v, got %+v", cursor, c)
+	}
+	if _, err := DecodeAuditPageToken("not-a-token!"); err == nil {
+		t.Error("expected an error for a malformed token")
+	}
+}
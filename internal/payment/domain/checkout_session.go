@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutSessionStatus represents the lifecycle state of a checkout session
+type CheckoutSessionStatus string
+
+const (
+	CheckoutSessionStatusOpen        CheckoutSessionStatus = "open"
+	CheckoutSessionStatusCompleting  CheckoutSessionStatus = "completing" // Completion webhook is being applied
+	CheckoutSessionStatusComplete    CheckoutSessionStatus = "complete"
+	CheckoutSessionStatusNeedsReview CheckoutSessionStatus = "needs_review" // Paid a different amount than quoted; nothing granted
+	CheckoutSessionStatusExpired     CheckoutSessionStatus = "expired"
+	CheckoutSessionStatusCancelled   CheckoutSessionStatus = "cancelled"
+)
+
+// CheckoutSession is a checkout opened with a billing provider. It records
+// what was quoted to the customer so that the completion webhook is applied
+// against the plan, user and price the session was created for rather than
+// whatever the provider echoes back.
+type CheckoutSession struct {
+	ID                uuid.UUID             `json:"id"`
+	Provider          string                `json:"provider"`            // Billing provider name, e.g. "stripe"
+	ProviderSessionID string                `json:"provider_session_id"` // Session ID assigned by the provider
+	PlanCode          string                `json:"plan_code"`           // Catalog identifier (plans.id)
+	PlanVersion       int32                 `json:"plan_version"`        // Plan version the price was quoted from
+	UserID            string                `json:"user_id"`
+	FamilyID          *string               `json:"family_id,omitempty"`
+	CountryCode       string                `json:"country_code,omitempty"`
+	Region            string                `json:"region,omitempty"`   // State or province used for tax
+	BasePrice         float64               `json:"base_price"`         // Catalog price in dollars
+	QuotedPrice       float64               `json:"quoted_price"`       // Price after multiplier in dollars
+	PricingMultiplier float64               `json:"pricing_multiplier"` // Applied pricing zone multiplier
+	Currency          string                `json:"currency"`
+	TaxID             string                `json:"tax_id,omitempty"` // Buyer's business tax ID
+	Tax               TaxQuote              `json:"tax"`              // Tax on the quoted price and the amount charged
+	Status            CheckoutSessionStatus `json:"status"`
+	URL               string                `json:"url"`
+	ExpiresAt         time.Time             `json:"expires_at"`
+	CompletedAt       *time.Time            `json:"completed_at,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+	UpdatedAt         time.Time             `json:"updated_at"`
+}
+
+// IsOpen reports whether the session can still be paid
+func (s *CheckoutSession) IsOpen() bool {
+	return s.Status == CheckoutSessionStatusOpen
+}
+
+// CanTransitionTo reports whether the session may move to the given status.
+// Only open sessions change state; terminal states are final.
+func (s *CheckoutSession) CanTransitionTo(status CheckoutSessionStatus) bool {
+	if s.Status == status {
+		return true
+	}
+	return s.IsOpen() && ParseCheckoutSessionStatus(string(status)) != ""
+}
+
+// QuotedPriceCents returns the quoted price in cents
+func (s *CheckoutSession) QuotedPriceCents() int64 {
+	return int64(math.Round(s.QuotedPrice * 100))
+}
+
+// TotalCents returns the amount charged in cents, including tax. Sessions
+// recorded without a tax quote are charged their quoted price.
+func (s *CheckoutSession) TotalCents() int64 {
+	if s.Tax.TotalCents == 0 {
+		return s.QuotedPriceCents()
+	}
+	return s.Tax.TotalCents
+}
+
+// MatchesCharge reports whether the amount and currency a provider reports
+// as paid match the session's total. A zero amount or empty currency is
+// taken as not reported and not checked.
+func (s *CheckoutSession) MatchesCharge(amount float64, currency string) bool {
+	if amount != 0 && int64(math.Round(amount*100)) != s.TotalCents() {
+		return false
+	}
+	return currency == "" || strings.EqualFold(currency, s.Currency)
+}
+
+// ParseCheckoutSessionStatus converts a provider status string into a
+// session status, returning "" when it is not recognised
+func ParseCheckoutSessionStatus(status string) CheckoutSessionStatus {
+	switch s := CheckoutSessionStatus(status); s {
+	case CheckoutSessionStatusOpen, CheckoutSessionStatusComplete, CheckoutSessionStatusExpired, CheckoutSessionStatusCancelled:
+		return s
+	default:
+		return ""
+	}
+}
+
+// QuotePrice applies a pricing multiplier to a base price, rounding to cents
+func QuotePrice(basePrice, multiplier float64) float64 {
+	return math.Round(basePrice*multiplier*100) / 100
+}
+
+// NewCheckoutSessionTransitionError reports an invalid session state change
+func NewCheckoutSessionTransitionError(s *CheckoutSession, to CheckoutSessionStatus) error {
+	return NewInvalidStateError(
+		fmt.Sprintf("checkout session %s is %s", s.ProviderSessionID, s.Status),
+		fmt.Sprintf("cannot move to %s", to),
+	)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import "testing"
+
+func TestCheckoutSession_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to CheckoutSessionStatus
+		want     bool
+	}{
+		{CheckoutSessionStatusOpen, CheckoutSessionStatusComplete, true},
+		{CheckoutSessionStatusOpen, CheckoutSessionStatusCancelled, true},
+		{CheckoutSessionStatusOpen, CheckoutSessionStatusExpired, true},
+		{CheckoutSessionStatusOpen, "refunded", false},
+		{CheckoutSessionStatusComplete, CheckoutSessionStatusComplete, true},
+		{CheckoutSessionStatusComplete, CheckoutSessionStatusCancelled, false},
+		{CheckoutSessionStatusExpired, CheckoutSessionStatusOpen, false},
+		{CheckoutSessionStatusCancelled, CheckoutSessionStatusExpired, false},
+	}
+
+	for _, tt := range tests {
+		s := &CheckoutSession{Status: tt.from}
+		if got := s.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.want, got)
+		}
+	}
+}
+
+func TestQuotePrice(t *testing.T) {
+	tests := []struct {
+		base, multiplier, want float64
+	}{
+		{9.99, 1, 9.99},
+		{9.99, 0.5, 5},
+		{19.99, 0.3, 6},
+		{4.99, 0.75, 3.74},
+	}
+
+	for _, tt := range tests {
+		if got := QuotePrice(tt.base, tt.multiplier); got != tt.want {
+			t.Errorf("QuotePrice(%v, %v): expected %v, got %v", tt.base, tt.multiplier, tt.want, got)
+		}
+	}
+
+	s := &CheckoutSession{QuotedPrice: QuotePrice(4.99, 0.75)}
+	if got := s.QuotedPriceCents(); got != 374 {
+		t.Errorf("expected 374 cents, got %d", got)
+	}
+}
+
+func TestCheckoutSession_MatchesCharge(t *testing.T) {
+	s := &CheckoutSession{QuotedPrice: 9.99, Currency: "USD", Tax: TaxQuote{TaxCents: 200, TotalCents: 1199}}
+	tests := []struct {
+		amount   float64
+		currency string
+		want     bool
+	}{
+		{11.99, "usd", true},
+		{0, "", true},
+		{9.99, "USD", false},
+		{11.99, "EUR", false},
+	}
+
+	for _, tt := range tests {
+		if got := s.MatchesCharge(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("MatchesCharge(%v, %q): expected %v, got %v", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
+
+func TestParseCheckoutSessionStatus(t *testing.T) {
+	if got := ParseCheckoutSessionStatus("expired"); got != CheckoutSessionStatusExpired {
+		t.Errorf("expected expired, got %q", got)
+	}
+	if got := ParseCheckoutSessionStatus("processing"); got != "" {
+		t.Errorf("expected unknown status to parse as empty, got %q", got)
+	}
+}
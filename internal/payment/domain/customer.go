@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Customer is the billing profile of a user
+type Customer struct {
+	ID                 uuid.UUID `json:"id"`
+	UserID             string    `json:"user_id"`
+	Name               string    `json:"name,omitempty"`
+	Email              string    `json:"email,omitempty"`
+	Address            Address   `json:"address"`
+	TaxID              string    `json:"tax_id,omitempty"`             // Business tax ID, normalized
+	PreferredCurrency  string    `json:"preferred_currency,omitempty"` // ISO 4217 code (optional)
+	Provider           string    `json:"provider,omitempty"`           // Billing provider holding the customer
+	ProviderCustomerID string    `json:"provider_customer_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// Validate checks that the billing profile can be stored
+func (c *Customer) Validate() error {
+	if c.UserID == "" {
+		return NewInvalidInputError("invalid customer", "user_id is required")
+	}
+	if c.PreferredCurrency != "" && len(c.PreferredCurrency) != 3 {
+		return NewInvalidInputError("invalid customer", fmt.Sprintf("preferred_currency must be a 3-letter ISO code: %q", c.PreferredCurrency))
+	}
+	if c.Address.Country != "" && len(c.Address.Country) != 2 {
+		return NewInvalidInputError("invalid customer", fmt.Sprintf("address country must be a 2-letter ISO code: %q", c.Address.Country))
+	}
+	if c.TaxID != "" && !ValidTaxID(c.Address.Country, c.TaxID) {
+		return NewInvalidInputError("invalid customer", fmt.Sprintf("%q is not a valid business tax ID for %s", c.TaxID, c.Address.Country))
+	}
+	return nil
+}
+
+// Normalize upper-cases the codes of the profile and strips separators from its tax ID
+func (c *Customer) Normalize() {
+	c.Address.Country = strings.ToUpper(c.Address.Country)
+	c.Address.Region = strings.ToUpper(c.Address.Region)
+	c.PreferredCurrency = strings.ToUpper(c.PreferredCurrency)
+	c.TaxID = NormalizeTaxID(c.TaxID)
+}
+
+// BillingDetails returns the profile as printed on invoices
+func (c *Customer) BillingDetails() BillingDetails {
+	return BillingDetails{
+		Name:    c.Name,
+		Email:   c.Email,
+		Address: c.Address,
+		TaxID:   c.TaxID,
+	}
+}
+
+// SavedPaymentMethod is a payment method tokenized by the billing provider
+// and saved for a customer. Only the details needed to recognise it are
+// kept; the provider holds the card itself.
+type SavedPaymentMethod struct {
+	ID                      uuid.UUID     `json:"id"`
+	CustomerID              uuid.UUID     `json:"customer_id"`
+	Provider                string        `json:"provider"`
+	ProviderPaymentMethodID string        `json:"provider_payment_method_id"`
+	Type                    PaymentMethod `json:"type"`
+	Brand                   string        `json:"brand,omitempty"` // e.g. "visa"
+	Last4                   string        `json:"last4,omitempty"`
+	ExpMonth                int32         `json:"exp_month,omitempty"`
+	ExpYear                 int32         `json:"exp_year,omitempty"`
+	IsDefault               bool          `json:"is_default"`
+	CreatedAt               time.Time     `json:"created_at"`
+	UpdatedAt               time.Time     `json:"updated_at"`
+}
+
+// Expired reports whether the method's expiry date has passed. Cards are
+// valid through the last day of their expiry month; methods without an
+// expiry never expire.
+func (m *SavedPaymentMethod) Expired(now time.Time) bool {
+	if m.ExpYear == 0 || m.ExpMonth == 0 {
+		return false
+	}
+	validUntil := time.Date(int(m.ExpYear), time.Month(m.ExpMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.UTC().Before(validUntil)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCustomer_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		customer Customer
+		wantErr  bool
+	}{
+		{"valid", Customer{UserID: "user-1", PreferredCurrency: "EUR", TaxID: "DE123456789", Address: Address{Country: "DE"}}, false},
+		{"missing user", Customer{}, true},
+		{"bad currency", Customer{UserID: "user-1", PreferredCurrency: "EURO"}, true},
+		{"bad country", Customer{UserID: "user-1", Address: Address{Country: "DEU"}}, true},
+		{"tax ID for another country", Customer{UserID: "user-1", TaxID: "FR12345678901", Address: Address{Country: "DE"}}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.customer.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestSavedPaymentMethod_Expired(t *testing.T) {
+	m := &SavedPaymentMethod{ExpMonth: 2, ExpYear: 2025}
+
+	if m.Expired(time.Date(2025, 2, 28, 23, 59, 0, 0, time.UTC)) {
+		t.Error("expected card to be valid through the end of its expiry month")
+	}
+	if !m.Expired(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("expected card to expire after its expiry month")
+	}
+	if (&SavedPaymentMethod{}).Expired(time.Now()) {
+		t.Error("expected method without an expiry never to expire")
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntitlementFilter narrows an entitlement listing. Zero-valued fields are ignored.
+type EntitlementFilter struct {
+	UserID      string
+	FamilyID    string
+	FeatureCode string
+	Status      string
+	Source      string
+}
+
+// EntitlementCursor identifies the last entitlement returned when iterating
+// in (created_at, id) order
+type EntitlementCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
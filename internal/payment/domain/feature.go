@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// QuotaType is the JSON type of a usage limit a feature governs
+type QuotaType string
+
+// Quota types accepted in a feature's quota schema
+const (
+	QuotaTypeInteger QuotaType = "integer"
+	QuotaTypeNumber  QuotaType = "number"
+	QuotaTypeString  QuotaType = "string"
+	QuotaTypeBoolean QuotaType = "boolean"
+	QuotaTypeArray   QuotaType = "array"
+	QuotaTypeObject  QuotaType = "object"
+)
+
+// Feature is an entry of the feature catalog. Plans may only include
+// catalog features, and entitlement checks reject codes that are not in it.
+type Feature struct {
+	Code          string               `json:"code"`
+	Description   string               `json:"description"`
+	QuotaSchema   map[string]QuotaType `json:"quota_schema"`   // usage_limits keys the feature governs
+	DefaultLimits json.RawMessage      `json:"default_limits"` // Limits granted when a plan sets none
+	Deprecated    bool                 `json:"deprecated"`     // Cannot be added to plans; existing grants keep working
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// FeatureUpdate is a partial update to a feature; nil fields are left unchanged
+type FeatureUpdate struct {
+	Description   *string
+	QuotaSchema   map[string]QuotaType
+	DefaultLimits json.RawMessage
+	Deprecated    *bool
+}
+
+// Apply returns a copy of f with the update's non-nil fields set
+func (u FeatureUpdate) Apply(f Feature) Feature {
+	if u.Description != nil {
+		f.Description = *u.Description
+	}
+	if u.QuotaSchema != nil {
+		f.QuotaSchema = u.QuotaSchema
+	}
+	if u.DefaultLimits != nil {
+		f.DefaultLimits = u.DefaultLimits
+	}
+	if u.Deprecated != nil {
+		f.Deprecated = *u.Deprecated
+	}
+	return f
+}
+
+// Accepts reports whether a decoded JSON value is of the quota type
+func (t QuotaType) Accepts(v interface{}) bool {
+	switch t {
+	case QuotaTypeInteger:
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case QuotaTypeNumber:
+		_, ok := v.(float64)
+		return ok
+	case QuotaTypeString:
+		_, ok := v.(string)
+		return ok
+	case QuotaTypeBoolean:
+		_, ok := v.(bool)
+		return ok
+	case QuotaTypeArray:
+		_, ok := v.([]interface{})
+		return ok
+	case QuotaTypeObject:
+		_, ok := v.(map[string]interface{})
+		return ok
+	default:
+		return false
+	}
+}
+
+// Validate checks a feature before it is written to the catalog
+func (f Feature) Validate() error {
+	if !planCodePattern.MatchString(f.Code) {
+		return NewInvalidInputError("invalid feature code", "feature code must be 1-100 lowercase letters, digits, '_' or '-'")
+	}
+	for key, t := range f.QuotaSchema {
+		if key == "" {
+			return NewInvalidInputError("invalid quota schema", "usage limit names must not be empty")
+		}
+		switch t {
+		case QuotaTypeInteger, QuotaTypeNumber, QuotaTypeString, QuotaTypeBoolean, QuotaTypeArray, QuotaTypeObject:
+		default:
+			return NewInvalidInputError("invalid quota schema", fmt.Sprintf("%s: want integer, number, string, boolean, array or object: %q", key, t))
+		}
+	}
+
+	defaults, err := decodeLimits("default_limits", f.DefaultLimits)
+	if err != nil {
+		return err
+	}
+	for _, key := range slices.Sorted(maps.Keys(defaults)) {
+		t, ok := f.QuotaSchema[key]
+		if !ok {
+			return NewInvalidInputError("invalid default limits", fmt.Sprintf("%s is not in the feature's quota schema", key))
+		}
+		if !t.Accepts(defaults[key]) {
+			return NewInvalidInputError("invalid default limits", fmt.Sprintf("%s must be of type %s", key, t))
+		}
+	}
+	return nil
+}
+
+// Limits returns the usage limits of an entitlement to the feature granted
+// by a plan: the feature's defaults, overridden by the plan's limits for the
+// keys the feature governs
+func (f Feature) Limits(planLimits json.RawMessage) json.RawMessage {
+	limits := make(map[string]json.RawMessage)
+	for _, raw := range []json.RawMessage{f.DefaultLimits, planLimits} {
+		var values map[string]json.RawMessage
+		if len(raw) == 0 || json.Unmarshal(raw, &values) != nil {
+			continue
+		}
+		for key, value := range values {
+			if _, ok := f.QuotaSchema[key]; ok {
+				limits[key] = value
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(limits)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return encoded
+}
+
+// ValidateUsageLimits checks that every key of a plan's usage limits is
+// governed by one of its features and holds a value of the declared type
+func ValidateUsageLimits(limits json.RawMessage, features []Feature) error {
+	values, err := decodeLimits("usage_limits", limits)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		var declared []string
+		accepted := false
+		for _, feature := range features {
+			if t, ok := feature.QuotaSchema[key]; ok {
+				declared = append(declared, fmt.Sprintf("%s (%s)", feature.Code, t))
+				accepted = accepted || t.Accepts(values[key])
+			}
+		}
+		if len(declared) == 0 {
+			return NewInvalidInputError("invalid usage limits", fmt.Sprintf("%s is not governed by any of the plan's features", key))
+		}
+		if !accepted {
+			return NewInvalidInputError("invalid usage limits", fmt.Sprintf("%s does not match the type declared by %s", key, strings.Join(declared, ", ")))
+		}
+	}
+	return nil
+}
+
+// decodeLimits decodes a usage limits document, treating empty input as no limits
+func decodeLimits(name string, raw json.RawMessage) (map[string]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, NewInvalidInputError("invalid "+strings.ReplaceAll(name, "_", " "), name+" must be a JSON object")
+	}
+	return values, nil
+}
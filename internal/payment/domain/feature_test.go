@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func storageFeature() Feature {
+	return Feature{
+		Code:          "pro_storage",
+		QuotaSchema:   map[string]QuotaType{"storage_gb": QuotaTypeInteger},
+		DefaultLimits: json.RawMessage(`{"storage_gb": 100}`),
+	}
+}
+
+func TestFeature_Validate(t *testing.T) {
+	if err := storageFeature().Validate(); err != nil {
+		t.Fatalf("expected valid feature, got %v", err)
+	}
+
+	tests := map[string]func(*Feature){
+		"bad code":              func(f *Feature) { f.Code = "Pro Storage" },
+		"bad quota type":        func(f *Feature) { f.QuotaSchema = map[string]QuotaType{"storage_gb": "int"} },
+		"undeclared default":    func(f *Feature) { f.DefaultLimits = json.RawMessage(`{"seats": 5}`) },
+		"mistyped default":      func(f *Feature) { f.DefaultLimits = json.RawMessage(`{"storage_gb": 1.5}`) },
+		"non-object defaults":   func(f *Feature) { f.DefaultLimits = json.RawMessage(`[100]`) },
+		"empty usage limit key": func(f *Feature) { f.QuotaSchema = map[string]QuotaType{"": QuotaTypeString} },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := storageFeature()
+			mutate(&f)
+			if err := f.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestFeature_Limits(t *testing.T) {
+	f := storageFeature()
+
+	if got := string(f.Limits(nil)); got != `{"storage_gb":100}` {
+		t.Errorf("Limits(nil) = %s, want the defaults", got)
+	}
+	if got := string(f.Limits(json.RawMessage(`{"storage_gb": 500, "support_level": "priority"}`))); got != `{"storage_gb":500}` {
+		t.Errorf("Limits() = %s, want the plan's storage_gb only", got)
+	}
+	if got := string(Feature{Code: "mobile_app"}.Limits(json.RawMessage(`{"storage_gb": 500}`))); got != `{}` {
+		t.Errorf("Limits() of a feature without quotas = %s, want {}", got)
+	}
+}
+
+func TestValidateUsageLimits(t *testing.T) {
+	support := Feature{Code: "priority_support", QuotaSchema: map[string]QuotaType{"support_level": QuotaTypeString}}
+	features := []Feature{storageFeature(), support}
+
+	if err := ValidateUsageLimits(json.RawMessage(`{"storage_gb": 500, "support_level": "priority"}`), features); err != nil {
+		t.Errorf("expected valid usage limits, got %v", err)
+	}
+	if err := ValidateUsageLimits(nil, nil); err != nil {
+		t.Errorf("expected no usage limits to be valid, got %v", err)
+	}
+
+	tests := map[string]string{
+		"ungoverned key": `{"seats": 5}`,
+		"mistyped value": `{"storage_gb": "lots"}`,
+		"not an object":  `"unlimited"`,
+	}
+	for name, limits := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateUsageLimits(json.RawMessage(limits), features)
+			if domainErr := GetDomainError(err); domainErr == nil || domainErr.Code != ErrCodeInvalidInput {
+				t.Errorf("expected an invalid input error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceNumberPrefix prefixes every invoice number
+const InvoiceNumberPrefix = "INV"
+
+// InvoiceStatus represents the status of an invoice
+type InvoiceStatus string
+
+const (
+	InvoiceStatusIssued InvoiceStatus = "issued"
+	InvoiceStatusVoid   InvoiceStatus = "void"
+)
+
+// InvoiceLineKind classifies an invoice line
+type InvoiceLineKind string
+
+const (
+	InvoiceLinePlan           InvoiceLineKind = "plan"            // The plan at its catalog price
+	InvoiceLineZoneAdjustment InvoiceLineKind = "zone_adjustment" // Regional pricing adjustment
+	InvoiceLineDiscount       InvoiceLineKind = "discount"        // Discounts, as a negative amount
+	InvoiceLineTax            InvoiceLineKind = "tax"             // Tax charged on top of the subtotal
+)
+
+// Address represents a postal address
+type Address struct {
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"` // State, province or county
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+}
+
+// IsZero reports whether no part of the address is set
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// BillingDetails identifies the buyer on an invoice
+type BillingDetails struct {
+	Name    string  `json:"name,omitempty"`
+	Email   string  `json:"email,omitempty"`
+	Address Address `json:"address"`
+	TaxID   string  `json:"tax_id,omitempty"` // Business tax ID, e.g. a VAT number
+}
+
+// InvoiceLineItem is one line of an invoice. Amounts are signed cents.
+type InvoiceLineItem struct {
+	Kind            InvoiceLineKind `json:"kind"`
+	Description     string          `json:"description"`
+	Quantity        int32           `json:"quantity"`
+	UnitAmountCents int64           `json:"unit_amount_cents"`
+	AmountCents     int64           `json:"amount_cents"`
+}
+
+// Invoice is an invoice issued for a completed payment
+type Invoice struct {
+	ID                uuid.UUID         `json:"id"`
+	Number            string            `json:"number"`   // e.g. INV-2025-000042
+	Year              int32             `json:"year"`     // Numbering year
+	Sequence          int32             `json:"sequence"` // Gap-free number within the year
+	PaymentID         uuid.UUID         `json:"payment_id"`
+	CheckoutSessionID *uuid.UUID        `json:"checkout_session_id,omitempty"`
+	UserID            string            `json:"user_id"`
+	FamilyID          *string           `json:"family_id,omitempty"`
+	PlanCode          string            `json:"plan_code"`
+	PlanVersion       int32             `json:"plan_version"`
+	Currency          string            `json:"currency"`
+	SubtotalCents     int64             `json:"subtotal_cents"` // Plan and zone adjustment lines
+	DiscountCents     int64             `json:"discount_cents"` // Total discount, as a positive amount
+	TaxCents          int64             `json:"tax_cents"`
+	TotalCents        int64             `json:"total_cents"`
+	TaxInclusive      bool              `json:"tax_inclusive"`  // Line amounts include the tax line
+	ReverseCharge     bool              `json:"reverse_charge"` // The buyer accounts for the tax
+	Billing           BillingDetails    `json:"billing"`
+	Status            InvoiceStatus     `json:"status"`
+	LineItems         []InvoiceLineItem `json:"line_items"`
+	IssuedAt          time.Time         `json:"issued_at"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// AddLine appends a single-quantity line and updates the totals
+func (inv *Invoice) AddLine(kind InvoiceLineKind, description string, amountCents int64) {
+	inv.LineItems = append(inv.LineItems, InvoiceLineItem{
+		Kind:            kind,
+		Description:     description,
+		Quantity:        1,
+		UnitAmountCents: amountCents,
+		AmountCents:     amountCents,
+	})
+	inv.Recalculate()
+}
+
+// Recalculate derives the invoice totals from its lines. Tax included in
+// the line amounts is reported but not added to the total.
+func (inv *Invoice) Recalculate() {
+	inv.SubtotalCents, inv.DiscountCents, inv.TaxCents = 0, 0, 0
+	for _, line := range inv.LineItems {
+		switch line.Kind {
+		case InvoiceLineDiscount:
+			inv.DiscountCents -= line.AmountCents
+		case InvoiceLineTax:
+			inv.TaxCents += line.AmountCents
+		default:
+			inv.SubtotalCents += line.AmountCents
+		}
+	}
+	inv.TotalCents = inv.SubtotalCents - inv.DiscountCents
+	if !inv.TaxInclusive {
+		inv.TotalCents += inv.TaxCents
+	}
+}
+
+// Validate checks that the invoice can be issued
+func (inv *Invoice) Validate() error {
+	if inv.UserID == "" {
+		return NewInvalidInputError("invalid invoice", "user_id is required")
+	}
+	if inv.PaymentID == uuid.Nil {
+		return NewInvalidInputError("invalid invoice", "payment_id is required")
+	}
+	if len(inv.LineItems) == 0 {
+		return NewInvalidInputError("invalid invoice", "at least one line item is required")
+	}
+	if inv.TotalCents < 0 {
+		return NewInvalidInputError("invalid invoice", fmt.Sprintf("total must not be negative: %d", inv.TotalCents))
+	}
+	return nil
+}
+
+// FormatInvoiceNumber formats the invoice number for a sequence within a year
+func FormatInvoiceNumber(year, sequence int32) string {
+	return fmt.Sprintf("%s-%d-%06d", InvoiceNumberPrefix, year, sequence)
+}
+
+// InvoiceFilter narrows an invoice listing. Zero-valued fields are ignored.
+type InvoiceFilter struct {
+	UserID string `json:"user_id,omitempty"`
+}
+
+// InvoiceCursor identifies the last invoice of a page. Invoices are listed
+// newest first by (year, sequence).
+type InvoiceCursor struct {
+	Year     int32 `json:"y"`
+	Sequence int32 `json:"s"`
+}
+
+// InvoicePage is one page of an invoice listing
+type InvoicePage struct {
+	Invoices      []*Invoice
+	NextPageToken string // Empty on the last page
+}
+
+// EncodeInvoicePageToken serializes a cursor into an opaque page token
+func EncodeInvoicePageToken(c *InvoiceCursor) string {
+	if c == nil {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeInvoicePageToken parses a page token produced by EncodeInvoicePageToken
+func DecodeInvoicePageToken(token string) (*InvoiceCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token is not valid base64")
+	}
+
+	var c InvoiceCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token payload is malformed")
+	}
+	if c.Year <= 0 || c.Sequence <= 0 {
+		return nil, NewInvalidInputError("invalid page token", "token is missing an invoice position")
+	}
+
+	return &c, nil
+}
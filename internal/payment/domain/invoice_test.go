@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestInvoice_AddLine(t *testing.T) {
+	inv := &Invoice{UserID: "user-1", PaymentID: uuid.New()}
+	inv.AddLine(InvoiceLinePlan, "Premium", 999)
+	inv.AddLine(InvoiceLineZoneAdjustment, "Regional pricing", -500)
+	inv.AddLine(InvoiceLineDiscount, "Discount", -100)
+	inv.AddLine(InvoiceLineTax, "Tax", 80)
+
+	if inv.SubtotalCents != 499 {
+		t.Errorf("expected subtotal 499, got %d", inv.SubtotalCents)
+	}
+	if inv.DiscountCents != 100 {
+		t.Errorf("expected discount 100, got %d", inv.DiscountCents)
+	}
+	if inv.TaxCents != 80 {
+		t.Errorf("expected tax 80, got %d", inv.TaxCents)
+	}
+	if inv.TotalCents != 479 {
+		t.Errorf("expected total 479, got %d", inv.TotalCents)
+	}
+	if err := inv.Validate(); err != nil {
+		t.Errorf("expected valid invoice, got %v", err)
+	}
+}
+
+func TestInvoice_Validate(t *testing.T) {
+	inv := &Invoice{UserID: "user-1", PaymentID: uuid.New()}
+	if err := inv.Validate(); err == nil {
+		t.Error("expected invoice without lines to be invalid")
+	}
+
+	inv.AddLine(InvoiceLineDiscount, "Discount", -100)
+	if err := inv.Validate(); err == nil {
+		t.Error("expected invoice with a negative total to be invalid")
+	}
+}
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	if got := FormatInvoiceNumber(2025, 42); got != "INV-2025-000042" {
+		t.Errorf("expected INV-2025-000042, got %q", got)
+	}
+}
+
+func TestInvoicePageToken(t *testing.T) {
+	token := EncodeInvoicePageToken(&InvoiceCursor{Year: 2025, Sequence: 17})
+	c, err := DecodeInvoicePageToken(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Year != 2025 || c.Sequence != 17 {
+		t.Errorf("expected cursor 2025/17, got %d/%d", c.Year, c.Sequence)
+	}
+
+	if c, err := DecodeInvoicePageToken(""); err != nil || c != nil {
+		t.Errorf("expected empty token to decode to nil, got %v, %v", c, err)
+	}
+	if _, err := DecodeInvoicePageToken("not-a-token!"); err == nil {
+		t.Error("expected malformed token to fail")
+	}
+}
+
+func TestInvoice_RecalculateTaxInclusive(t *testing.T) {
+	inv := &Invoice{TaxInclusive: true}
+	inv.AddLine(InvoiceLinePlan, "Premium", 1200)
+	inv.AddLine(InvoiceLineTax, "VAT 20% (included)", 200)
+
+	if inv.TaxCents != 200 {
+		t.Errorf("expected tax 200, got %d", inv.TaxCents)
+	}
+	if inv.TotalCents != 1200 {
+		t.Errorf("expected included tax to be left out of the total, got %d", inv.TotalCents)
+	}
+}
@@ -0,0 +1,330 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ledger account codes, seeded by the ledger migration
+const (
+	AccountProviderClearing = "provider_clearing" // Funds held by the billing provider
+	AccountDisputeReserve   = "dispute_reserve"   // Funds withheld for open disputes
+	AccountRevenue          = "revenue"
+	AccountDiscounts        = "discounts" // Contra revenue
+	AccountRefunds          = "refunds"   // Contra revenue
+	AccountTaxPayable       = "tax_payable"
+	AccountProviderFees     = "provider_fees"
+	AccountDisputeLosses    = "dispute_losses"
+)
+
+// AccountType classifies a ledger account
+type AccountType string
+
+const (
+	AccountTypeAsset     AccountType = "asset"
+	AccountTypeLiability AccountType = "liability"
+	AccountTypeRevenue   AccountType = "revenue"
+	AccountTypeExpense   AccountType = "expense"
+)
+
+// PostingDirection is the side of an account a posting goes to
+type PostingDirection string
+
+const (
+	PostingDebit  PostingDirection = "debit"
+	PostingCredit PostingDirection = "credit"
+)
+
+// JournalEntryKind is the money movement a journal entry records
+type JournalEntryKind string
+
+const (
+	JournalEntryCharge        JournalEntryKind = "charge"
+	JournalEntryRefund        JournalEntryKind = "refund"
+	JournalEntryDisputeOpened JournalEntryKind = "dispute_opened"
+	JournalEntryDisputeWon    JournalEntryKind = "dispute_won"
+	JournalEntryDisputeLost   JournalEntryKind = "dispute_lost"
+	JournalEntryProviderFee   JournalEntryKind = "provider_fee"
+)
+
+// JournalEntry is one balanced money movement. Entries are append-only;
+// mistakes are corrected by posting another entry.
+type JournalEntry struct {
+	ID          uuid.UUID        `json:"id"`
+	Reference   string           `json:"reference"` // Idempotency key; a reference is posted at most once
+	Kind        JournalEntryKind `json:"kind"`
+	PaymentID   *uuid.UUID       `json:"payment_id,omitempty"`
+	CustomerID  string           `json:"customer_id,omitempty"`
+	Currency    string           `json:"currency"`
+	Description string           `json:"description,omitempty"`
+	Postings    []Posting        `json:"postings"`
+	OccurredAt  time.Time        `json:"occurred_at"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// Posting debits or credits one account
+type Posting struct {
+	ID          uuid.UUID        `json:"id"`
+	AccountCode string           `json:"account_code"`
+	Direction   PostingDirection `json:"direction"`
+	AmountCents int64            `json:"amount_cents"` // Always positive
+}
+
+// ChargeBreakdown splits a charge into the parts the ledger records on
+// their own accounts. All amounts are positive.
+type ChargeBreakdown struct {
+	DiscountCents int64 `json:"discount_cents"`
+	TaxCents      int64 `json:"tax_cents"`
+	FeeCents      int64 `json:"fee_cents"` // Provider's processing fee, when reported
+}
+
+// Debit adds a debit posting; zero amounts are skipped
+func (e *JournalEntry) Debit(account string, cents int64) {
+	e.post(account, PostingDebit, cents)
+}
+
+// Credit adds a credit posting; zero amounts are skipped
+func (e *JournalEntry) Credit(account string, cents int64) {
+	e.post(account, PostingCredit, cents)
+}
+
+func (e *JournalEntry) post(account string, direction PostingDirection, cents int64) {
+	if cents == 0 {
+		return
+	}
+	e.Postings = append(e.Postings, Posting{AccountCode: account, Direction: direction, AmountCents: cents})
+}
+
+// Sum totals the entry's postings to an account on one side
+func (e *JournalEntry) Sum(account string, direction PostingDirection) int64 {
+	var total int64
+	for _, p := range e.Postings {
+		if p.AccountCode == account && p.Direction == direction {
+			total += p.AmountCents
+		}
+	}
+	return total
+}
+
+// Validate checks that the entry can be posted: every posting is positive
+// and the debits equal the credits
+func (e *JournalEntry) Validate() error {
+	if e.Reference == "" {
+		return NewInvalidInputError("invalid journal entry", "reference is required")
+	}
+	if e.Kind == "" {
+		return NewInvalidInputError("invalid journal entry", "kind is required")
+	}
+	if len(e.Currency) != 3 {
+		return NewInvalidInputError("invalid journal entry", fmt.Sprintf("currency must be a 3-letter code: %q", e.Currency))
+	}
+	if len(e.Postings) < 2 {
+		return NewInvalidInputError("invalid journal entry", fmt.Sprintf("%s: at least two postings are required", e.Reference))
+	}
+
+	var debits, credits int64
+	for _, p := range e.Postings {
+		if p.AccountCode == "" {
+			return NewInvalidInputError("invalid journal entry", fmt.Sprintf("%s: account_code is required", e.Reference))
+		}
+		if p.AmountCents <= 0 {
+			return NewInvalidInputError("invalid journal entry", fmt.Sprintf("%s: %s amount must be positive: %d", e.Reference, p.AccountCode, p.AmountCents))
+		}
+		switch p.Direction {
+		case PostingDebit:
+			debits += p.AmountCents
+		case PostingCredit:
+			credits += p.AmountCents
+		default:
+			return NewInvalidInputError("invalid journal entry", fmt.Sprintf("%s: unknown direction %q", e.Reference, p.Direction))
+		}
+	}
+	if debits != credits {
+		return NewInvalidInputError("unbalanced journal entry", fmt.Sprintf("%s: debits %d, credits %d", e.Reference, debits, credits))
+	}
+	return nil
+}
+
+// ChargeReference is the reference of the charge entry of a payment
+func ChargeReference(paymentID uuid.UUID) string {
+	return fmt.Sprintf("payment:%s:charge", paymentID)
+}
+
+// RefundReference is the reference of a refund of a payment; refundID
+// tells several partial refunds apart
+func RefundReference(paymentID uuid.UUID, refundID string) string {
+	return fmt.Sprintf("payment:%s:refund:%s", paymentID, refundID)
+}
+
+// DisputeReference is the reference of a dispute entry; stage is "opened"
+// or "closed", so that a dispute is closed at most once
+func DisputeReference(disputeID, stage string) string {
+	return fmt.Sprintf("dispute:%s:%s", disputeID, stage)
+}
+
+// NewChargeEntry records a completed payment: the amount collected reaches
+// the provider, the discount reduces revenue and the tax is owed. A fee the
+// provider withheld is booked as an expense.
+func NewChargeEntry(payment *Payment, breakdown ChargeBreakdown) *JournalEntry {
+	totalCents := int64(math.Round(payment.Amount * 100))
+
+	entry := newPaymentEntry(payment, JournalEntryCharge, ChargeReference(payment.ID))
+	entry.Description = payment.Description
+	entry.Debit(AccountProviderClearing, totalCents)
+	entry.Debit(AccountDiscounts, breakdown.DiscountCents)
+	entry.Credit(AccountRevenue, totalCents-breakdown.TaxCents+breakdown.DiscountCents)
+	entry.Credit(AccountTaxPayable, breakdown.TaxCents)
+	entry.Debit(AccountProviderFees, breakdown.FeeCents)
+	entry.Credit(AccountProviderClearing, breakdown.FeeCents)
+	return entry
+}
+
+// NewRefundEntry records a refund of refundCents. The tax refunded is the
+// charge's tax in proportion to the amount refunded; without a charge
+// entry, e.g. for payments completed before the ledger existed, the whole
+// refund is booked against revenue.
+func NewRefundEntry(payment *Payment, charge *JournalEntry, refundID string, refundCents int64) *JournalEntry {
+	var taxCents int64
+	if charge != nil {
+		chargedCents := charge.Sum(AccountProviderClearing, PostingDebit)
+		if chargedCents > 0 {
+			tax := charge.Sum(AccountTaxPayable, PostingCredit)
+			taxCents = int64(math.Round(float64(tax) * float64(refundCents) / float64(chargedCents)))
+		}
+	}
+
+	entry := newPaymentEntry(payment, JournalEntryRefund, RefundReference(payment.ID, refundID))
+	entry.Debit(AccountRefunds, refundCents-taxCents)
+	entry.Debit(AccountTaxPayable, taxCents)
+	entry.Credit(AccountProviderClearing, refundCents)
+	return entry
+}
+
+// NewDisputeOpenedEntry records funds the provider withheld for a dispute,
+// and the dispute fee it charged
+func NewDisputeOpenedEntry(payment *Payment, disputeID string, amountCents, feeCents int64) *JournalEntry {
+	entry := newPaymentEntry(payment, JournalEntryDisputeOpened, DisputeReference(disputeID, "opened"))
+	entry.Debit(AccountDisputeReserve, amountCents)
+	entry.Credit(AccountProviderClearing, amountCents)
+	entry.Debit(AccountProviderFees, feeCents)
+	entry.Credit(AccountProviderClearing, feeCents)
+	return entry
+}
+
+// NewDisputeClosedEntry releases withheld funds: back to the provider
+// balance when the dispute was won, to losses when it was lost
+func NewDisputeClosedEntry(payment *Payment, disputeID string, amountCents int64, won bool) *JournalEntry {
+	kind, account := JournalEntryDisputeLost, AccountDisputeLosses
+	if won {
+		kind, account = JournalEntryDisputeWon, AccountProviderClearing
+	}
+
+	entry := newPaymentEntry(payment, kind, DisputeReference(disputeID, "closed"))
+	entry.Debit(account, amountCents)
+	entry.Credit(AccountDisputeReserve, amountCents)
+	return entry
+}
+
+// NewProviderFeeEntry records a fee the provider charged for a payment
+// outside of its charge, e.g. from a payout report
+func NewProviderFeeEntry(payment *Payment, reference string, feeCents int64) *JournalEntry {
+	entry := newPaymentEntry(payment, JournalEntryProviderFee, "fee:"+reference)
+	entry.Debit(AccountProviderFees, feeCents)
+	entry.Credit(AccountProviderClearing, feeCents)
+	return entry
+}
+
+func newPaymentEntry(payment *Payment, kind JournalEntryKind, reference string) *JournalEntry {
+	paymentID := payment.ID
+	return &JournalEntry{
+		Reference:  reference,
+		Kind:       kind,
+		PaymentID:  &paymentID,
+		CustomerID: payment.CustomerID,
+		Currency:   strings.ToUpper(payment.Currency),
+		OccurredAt: time.Now(),
+	}
+}
+
+// AccountBalance is the total of an account's postings in one currency
+type AccountBalance struct {
+	AccountCode   string           `json:"account_code"`
+	AccountName   string           `json:"account_name"`
+	AccountType   AccountType      `json:"account_type"`
+	NormalBalance PostingDirection `json:"normal_balance"`
+	Currency      string           `json:"currency"`
+	DebitCents    int64            `json:"debit_cents"`
+	CreditCents   int64            `json:"credit_cents"`
+}
+
+// BalanceCents returns the balance on the account's normal side, e.g. the
+// credits less the debits of revenue
+func (b AccountBalance) BalanceCents() int64 {
+	if b.NormalBalance == PostingCredit {
+		return b.CreditCents - b.DebitCents
+	}
+	return b.DebitCents - b.CreditCents
+}
+
+// UnbalancedEntry is a posted entry whose debits and credits differ
+type UnbalancedEntry struct {
+	EntryID     uuid.UUID `json:"entry_id"`
+	Reference   string    `json:"reference"`
+	Currency    string    `json:"currency"`
+	DebitCents  int64     `json:"debit_cents"`
+	CreditCents int64     `json:"credit_cents"`
+}
+
+// TrialBalanceTotal is the sum of all debits and credits in one currency
+type TrialBalanceTotal struct {
+	Currency    string `json:"currency"`
+	DebitCents  int64  `json:"debit_cents"`
+	CreditCents int64  `json:"credit_cents"`
+}
+
+// TrialBalance lists every account balance together with any entries that
+// do not balance
+type TrialBalance struct {
+	Accounts   []AccountBalance  `json:"accounts"`
+	Unbalanced []UnbalancedEntry `json:"unbalanced,omitempty"`
+}
+
+// Totals sums the debits and credits of all accounts per currency
+func (tb TrialBalance) Totals() []TrialBalanceTotal {
+	byCurrency := make(map[string]*TrialBalanceTotal)
+	for _, b := range tb.Accounts {
+		total, ok := byCurrency[b.Currency]
+		if !ok {
+			total = &TrialBalanceTotal{Currency: b.Currency}
+			byCurrency[b.Currency] = total
+		}
+		total.DebitCents += b.DebitCents
+		total.CreditCents += b.CreditCents
+	}
+
+	totals := make([]TrialBalanceTotal, 0, len(byCurrency))
+	for _, total := range byCurrency {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
+
+// Balanced reports whether debits equal credits in every currency and
+// every entry balances on its own
+func (tb TrialBalance) Balanced() bool {
+	if len(tb.Unbalanced) > 0 {
+		return false
+	}
+	for _, total := range tb.Totals() {
+		if total.DebitCents != total.CreditCents {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewChargeEntry(t *testing.T) {
+	payment := &Payment{ID: uuid.New(), Amount: 11.99, Currency: "usd", CustomerID: "user-1"}
+	entry := NewChargeEntry(payment, ChargeBreakdown{DiscountCents: 200, TaxCents: 200, FeeCents: 65})
+
+	if err := entry.Validate(); err != nil {
+		t.Fatalf("expected a balanced entry, got %v", err)
+	}
+	if entry.Currency != "USD" || entry.CustomerID != "user-1" || entry.Reference != ChargeReference(payment.ID) {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	tests := []struct {
+		account   string
+		direction PostingDirection
+		want      int64
+	}{
+		{AccountProviderClearing, PostingDebit, 1199},
+		{AccountProviderClearing, PostingCredit, 65},
+		{AccountDiscounts, PostingDebit, 200},
+		{AccountRevenue, PostingCredit, 1199},
+		{AccountTaxPayable, PostingCredit, 200},
+		{AccountProviderFees, PostingDebit, 65},
+	}
+	for _, tt := range tests {
+		if got := entry.Sum(tt.account, tt.direction); got != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.account, tt.direction, tt.want, got)
+		}
+	}
+
+	// Nothing is posted for parts that are zero
+	if plain := NewChargeEntry(payment, ChargeBreakdown{}); len(plain.Postings) != 2 {
+		t.Errorf("expected 2 postings, got %+v", plain.Postings)
+	}
+}
+
+func TestNewRefundEntry(t *testing.T) {
+	payment := &Payment{ID: uuid.New(), Amount: 12, Currency: "EUR"}
+	charge := NewChargeEntry(payment, ChargeBreakdown{TaxCents: 200})
+
+	full := NewRefundEntry(payment, charge, "full", 1200)
+	if err := full.Validate(); err != nil {
+		t.Fatalf("expected a balanced entry, got %v", err)
+	}
+	if full.Sum(AccountTaxPayable, PostingDebit) != 200 || full.Sum(AccountRefunds, PostingDebit) != 1000 {
+		t.Errorf("unexpected full refund postings: %+v", full.Postings)
+	}
+
+	partial := NewRefundEntry(payment, charge, "re_1", 300)
+	if partial.Sum(AccountTaxPayable, PostingDebit) != 50 || partial.Sum(AccountProviderClearing, PostingCredit) != 300 {
+		t.Errorf("unexpected partial refund postings: %+v", partial.Postings)
+	}
+	if partial.Reference == full.Reference {
+		t.Error("expected refunds to have distinct references")
+	}
+
+	untracked := NewRefundEntry(payment, nil, "full", 1200)
+	if untracked.Sum(AccountRefunds, PostingDebit) != 1200 || untracked.Sum(AccountTaxPayable, PostingDebit) != 0 {
+		t.Errorf("unexpected refund postings without a charge: %+v", untracked.Postings)
+	}
+}
+
+func TestDisputeEntries(t *testing.T) {
+	payment := &Payment{ID: uuid.New(), Amount: 10, Currency: "USD"}
+
+	opened := NewDisputeOpenedEntry(payment, "dp_1", 1000, 1500)
+	won := NewDisputeClosedEntry(payment, "dp_1", 1000, true)
+	lost := NewDisputeClosedEntry(payment, "dp_1", 1000, false)
+	for _, entry := range []*JournalEntry{opened, won, lost} {
+		if err := entry.Validate(); err != nil {
+			t.Errorf("%s: expected a balanced entry, got %v", entry.Kind, err)
+		}
+	}
+
+	if won.Kind != JournalEntryDisputeWon || won.Sum(AccountProviderClearing, PostingDebit) != 1000 {
+		t.Errorf("unexpected won dispute: %+v", won)
+	}
+	if lost.Kind != JournalEntryDisputeLost || lost.Sum(AccountDisputeLosses, PostingDebit) != 1000 {
+		t.Errorf("unexpected lost dispute: %+v", lost)
+	}
+	// A dispute closes once, whatever the outcome
+	if won.Reference != lost.Reference {
+		t.Error("expected the outcomes of a dispute to share a reference")
+	}
+}
+
+func TestJournalEntry_Validate(t *testing.T) {
+	valid := func() *JournalEntry {
+		e := &JournalEntry{Reference: "ref", Kind: JournalEntryCharge, Currency: "USD"}
+		e.Debit(AccountProviderClearing, 100)
+		e.Credit(AccountRevenue, 100)
+		return e
+	}
+
+	tests := []struct {
+		name   string
+		modify func(e *JournalEntry)
+	}{
+		{"missing reference", func(e *JournalEntry) { e.Reference = "" }},
+		{"bad currency", func(e *JournalEntry) { e.Currency = "US" }},
+		{"single posting", func(e *JournalEntry) { e.Postings = e.Postings[:1] }},
+		{"unbalanced", func(e *JournalEntry) { e.Credit(AccountTaxPayable, 1) }},
+		{"negative amount", func(e *JournalEntry) {
+			e.Debit(AccountDiscounts, -5)
+			e.Credit(AccountRevenue, -5)
+		}},
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("expected a valid entry, got %v", err)
+	}
+	for _, tt := range tests {
+		e := valid()
+		tt.modify(e)
+		if err := e.Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestTrialBalance(t *testing.T) {
+	tb := TrialBalance{Accounts: []AccountBalance{
+		{AccountCode: AccountProviderClearing, NormalBalance: PostingDebit, Currency: "USD", DebitCents: 1199, CreditCents: 65},
+		{AccountCode: AccountRevenue, NormalBalance: PostingCredit, Currency: "USD", CreditCents: 999},
+		{AccountCode: AccountTaxPayable, NormalBalance: PostingCredit, Currency: "USD", CreditCents: 200},
+		{AccountCode: AccountProviderFees, NormalBalance: PostingDebit, Currency: "USD", DebitCents: 65},
+		{AccountCode: AccountProviderClearing, NormalBalance: PostingDebit, Currency: "EUR", DebitCents: 500},
+		{AccountCode: AccountRevenue, NormalBalance: PostingCredit, Currency: "EUR", CreditCents: 500},
+	}}
+
+	if !tb.Balanced() {
+		t.Errorf("expected a balanced trial balance, got %+v", tb.Totals())
+	}
+	if totals := tb.Totals(); len(totals) != 2 || totals[0].Currency != "EUR" || totals[1].DebitCents != 1264 {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+	if got := tb.Accounts[0].BalanceCents(); got != 1134 {
+		t.Errorf("expected a clearing balance of 1134, got %d", got)
+	}
+	if got := tb.Accounts[1].BalanceCents(); got != 999 {
+		t.Errorf("expected a revenue balance of 999, got %d", got)
+	}
+
+	tb.Accounts[1].CreditCents++
+	if tb.Balanced() {
+		t.Error("expected an unbalanced trial balance")
+	}
+	tb.Accounts[1].CreditCents--
+	tb.Unbalanced = []UnbalancedEntry{{Reference: "ref", Currency: "USD", DebitCents: 1}}
+	if tb.Balanced() {
+		t.Error("expected unbalanced entries to fail the trial balance")
+	}
+}
@@ -19,6 +19,7 @@ type Payment struct {
 	Description       string    `json:"description"`
 	ExternalPaymentID string    `json:"external_payment_id"` // External payment processor ID
 	FailureReason     string    `json:"failure_reason"`      // Reason for payment failure
+	Provider          string    `json:"provider,omitempty"`  // Billing provider the payment was taken with
 	Metadata          []byte    `json:"metadata"`            // Additional payment metadata
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
@@ -57,15 +58,17 @@ type PaymentRequest struct {
 
 // PaymentResponse represents a payment response
 type PaymentResponse struct {
-	ID            uuid.UUID `json:"id"`
-	Amount        float64   `json:"amount"`
-	Currency      string    `json:"currency"`
-	Status        string    `json:"status"`
-	PaymentMethod string    `json:"payment_method"`
-	CustomerID    string    `json:"customer_id"`
-	OrderID       string    `json:"order_id"`
-	Description   string    `json:"description"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                uuid.UUID `json:"id"`
+	Amount            float64   `json:"amount"`
+	Currency          string    `json:"currency"`
+	Status            string    `json:"status"`
+	PaymentMethod     string    `json:"payment_method"`
+	CustomerID        string    `json:"customer_id"`
+	OrderID           string    `json:"order_id"`
+	Description       string    `json:"description"`
+	ExternalPaymentID string    `json:"external_payment_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
 
 // IsValidStatus checks if the payment status is valid
@@ -97,7 +100,8 @@ func (p Payment) IsValidPaymentMethod() bool {
 
 // Plan represents a subscription plan
 type Plan struct {
-	ID           uuid.UUID       `json:"id"`
+	ID           uuid.UUID       `json:"id"`   // Derived from Code, see PlanUUID
+	Code         string          `json:"code"` // Catalog identifier (plans.id), e.g. "basic_monthly"
 	Name         string          `json:"name"`
 	Description  string          `json:"description"`
 	FeatureCodes []string        `json:"feature_codes"`
@@ -108,6 +112,8 @@ type Plan struct {
 	UsageLimits  json.RawMessage `json:"usage_limits"`
 	Metadata     json.RawMessage `json:"metadata"`
 	Active       bool            `json:"active"`
+	Version      int32           `json:"version"`               // Current pricing/feature version
+	ArchivedAt   *time.Time      `json:"archived_at,omitempty"` // Set once the plan is archived
 	CreatedAt    time.Time       `json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
 }
@@ -125,16 +131,28 @@ type Entitlement struct {
 	ExpiresAt      *time.Time      `json:"expires_at,omitempty"`
 	UsageLimits    json.RawMessage `json:"usage_limits"`
 	Metadata       json.RawMessage `json:"metadata"`
+	Source         string          `json:"source"` // EntitlementSourcePaid unless granted by support
 	CreatedAt      time.Time       `json:"created_at"`
 	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
+// Entitlement sources. Paid entitlements come from checkouts and
+// subscriptions. Support grants entitlements outside of checkout as manual
+// grants, or as complimentary ones when they are given without payment;
+// both are reported apart from paid ones.
+const (
+	EntitlementSourcePaid          = "paid"
+	EntitlementSourceComplimentary = "complimentary"
+	EntitlementSourceManual        = "manual"
+)
+
 // Subscription represents a subscription with lifecycle management
 type Subscription struct {
 	ID                     uuid.UUID              `json:"id"`
 	UserID                 string                 `json:"user_id"`
 	FamilyID               *string                `json:"family_id,omitempty"`
 	PlanID                 uuid.UUID              `json:"plan_id"`
+	PlanVersion            int32                  `json:"plan_version,omitempty"` // Grandfathered plan version; 0 if unknown
 	Status                 string                 `json:"status"`
 	CurrentPeriodStart     time.Time              `json:"current_period_start"`
 	CurrentPeriodEnd       time.Time              `json:"current_period_end"`
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentFilter narrows a payment listing. Zero-valued fields are ignored.
+type PaymentFilter struct {
+	CustomerID        string     `json:"customer_id,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	Currency          string     `json:"currency,omitempty"`
+	PaymentMethod     string     `json:"payment_method,omitempty"`
+	ExternalPaymentID string     `json:"external_payment_id,omitempty"`
+	CreatedAfter      *time.Time `json:"created_after,omitempty"`  // Inclusive
+	CreatedBefore     *time.Time `json:"created_before,omitempty"` // Exclusive
+	MinAmount         *float64   `json:"min_amount,omitempty"`     // Inclusive, in dollars
+	MaxAmount         *float64   `json:"max_amount,omitempty"`     // Inclusive, in dollars
+}
+
+// PaymentSortField is a column payments can be ordered by
+type PaymentSortField string
+
+const (
+	PaymentSortByCreatedAt PaymentSortField = "created_at"
+	PaymentSortByAmount    PaymentSortField = "amount"
+)
+
+// PaymentCursor identifies the last row of a page for keyset pagination.
+// Rows are always ordered by (sort field, id) so the cursor is unambiguous
+// even when several payments share a timestamp or amount.
+type PaymentCursor struct {
+	SortBy    PaymentSortField `json:"s"`
+	SortDesc  bool             `json:"d"`
+	CreatedAt time.Time        `json:"c,omitempty"`
+	Amount    float64          `json:"a,omitempty"`
+	ID        uuid.UUID        `json:"i"`
+}
+
+// PaymentListQuery describes one page of a filtered, sorted payment listing
+type PaymentListQuery struct {
+	Filter   PaymentFilter
+	SortBy   PaymentSortField
+	SortDesc bool
+	Limit    int
+	Offset   int            // Legacy offset paging; ignored when Cursor is set
+	Cursor   *PaymentCursor // Resume after this row
+}
+
+// PaymentPage is one page of a payment listing
+type PaymentPage struct {
+	Payments      []*PaymentResponse
+	Total         int64  // Total rows matching the filter, across all pages
+	NextPageToken string // Empty on the last page
+}
+
+// Validate checks the filter for contradictory bounds
+func (f PaymentFilter) Validate() error {
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return NewInvalidInputError("invalid created_at range", "created_after must be before created_before")
+	}
+	if f.MinAmount != nil && f.MaxAmount != nil && *f.MinAmount > *f.MaxAmount {
+		return NewInvalidInputError("invalid amount range", "min_amount must not exceed max_amount")
+	}
+	if f.Status != "" && !(Payment{Status: f.Status}).IsValidStatus() {
+		return NewInvalidInputError("invalid payment status", fmt.Sprintf("status: %s", f.Status))
+	}
+	return nil
+}
+
+// CursorAfter returns the cursor pointing at payment p for the given ordering
+func CursorAfter(p *Payment, sortBy PaymentSortField, sortDesc bool) *PaymentCursor {
+	return &PaymentCursor{
+		SortBy:    sortBy,
+		SortDesc:  sortDesc,
+		CreatedAt: p.CreatedAt,
+		Amount:    p.Amount,
+		ID:        p.ID,
+	}
+}
+
+// EncodePageToken serializes a cursor into an opaque page token
+func EncodePageToken(c *PaymentCursor) string {
+	if c == nil {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageToken parses a page token produced by EncodePageToken
+func DecodePageToken(token string) (*PaymentCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token is not valid base64")
+	}
+
+	var c PaymentCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, NewInvalidInputError("invalid page token", "token payload is malformed")
+	}
+	if c.ID == uuid.Nil {
+		return nil, NewInvalidInputError("invalid page token", "token is missing a row id")
+	}
+	switch c.SortBy {
+	case PaymentSortByCreatedAt, PaymentSortByAmount:
+	default:
+		return nil, NewInvalidInputError("invalid page token", fmt.Sprintf("unknown sort field: %s", c.SortBy))
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPageToken_RoundTrip(t *testing.T) {
+	want := &PaymentCursor{
+		SortBy:    PaymentSortByAmount,
+		SortDesc:  true,
+		CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Amount:    19.99,
+		ID:        uuid.New(),
+	}
+
+	got, err := DecodePageToken(EncodePageToken(want))
+	if err != nil {
+		t.Fatalf("DecodePageToken failed: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestDecodePageToken_Invalid(t *testing.T) {
+	for _, token := range []string{"not base64!", "bm90IGpzb24", EncodePageToken(&PaymentCursor{SortBy: PaymentSortByAmount})} {
+		if _, err := DecodePageToken(token); err == nil {
+			t.Errorf("expected error for token %q", token)
+		} else if GetDomainError(err) == nil {
+			t.Errorf("expected domain error for token %q, got %v", token, err)
+		}
+	}
+}
+
+func TestPaymentFilter_Validate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	low, high := 5.0, 10.0
+
+	if err := (PaymentFilter{CreatedAfter: &earlier, CreatedBefore: &now, MinAmount: &low, MaxAmount: &high}).Validate(); err != nil {
+		t.Errorf("expected valid filter, got %v", err)
+	}
+	if err := (PaymentFilter{CreatedAfter: &now, CreatedBefore: &earlier}).Validate(); err == nil {
+		t.Error("expected error for inverted created_at range")
+	}
+	if err := (PaymentFilter{MinAmount: &high, MaxAmount: &low}).Validate(); err == nil {
+		t.Error("expected error for inverted amount range")
+	}
+	if err := (PaymentFilter{Status: "bogus"}).Validate(); err == nil {
+		t.Error("expected error for unknown status")
+	}
+}
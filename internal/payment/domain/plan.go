@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Billing cycles accepted for catalog plans
+const (
+	BillingCycleMonthly = "monthly"
+	BillingCycleYearly  = "yearly"
+	BillingCycleOneTime = "one_time"
+)
+
+// planCodePattern matches catalog plan identifiers such as "pro_monthly"
+var planCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// PlanVersion is an immutable revision of a plan's pricing and features.
+// Subscriptions are pinned to the version they were sold at.
+type PlanVersion struct {
+	PlanCode     string          `json:"plan_code"`
+	Version      int32           `json:"version"`
+	FeatureCodes []string        `json:"feature_codes"`
+	BillingCycle string          `json:"billing_cycle"`
+	PriceDollars float64         `json:"price_dollars"`
+	Currency     string          `json:"currency"`
+	MaxUsers     int32           `json:"max_users"`
+	UsageLimits  json.RawMessage `json:"usage_limits"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// PlanUpdate is a partial update to a plan; nil fields are left unchanged
+type PlanUpdate struct {
+	Name         *string
+	Description  *string
+	FeatureCodes []string
+	BillingCycle *string
+	PriceDollars *float64
+	Currency     *string
+	MaxUsers     *int32
+	UsageLimits  json.RawMessage
+	Metadata     json.RawMessage
+}
+
+// Apply returns a copy of p with the update's non-nil fields set
+func (u PlanUpdate) Apply(p Plan) Plan {
+	if u.Name != nil {
+		p.Name = *u.Name
+	}
+	if u.Description != nil {
+		p.Description = *u.Description
+	}
+	if u.FeatureCodes != nil {
+		p.FeatureCodes = u.FeatureCodes
+	}
+	if u.BillingCycle != nil {
+		p.BillingCycle = *u.BillingCycle
+	}
+	if u.PriceDollars != nil {
+		p.PriceDollars = *u.PriceDollars
+	}
+	if u.Currency != nil {
+		p.Currency = *u.Currency
+	}
+	if u.MaxUsers != nil {
+		p.MaxUsers = *u.MaxUsers
+	}
+	if u.UsageLimits != nil {
+		p.UsageLimits = u.UsageLimits
+	}
+	if u.Metadata != nil {
+		p.Metadata = u.Metadata
+	}
+	return p
+}
+
+// PlanUUID returns the UUID the service uses for a string plan code
+func PlanUUID(code string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(code))
+}
+
+// PriceCents returns the plan price in cents
+func (p Plan) PriceCents() int64 {
+	return int64(math.Round(p.PriceDollars * 100))
+}
+
+// PriceCents returns the version's price in cents
+func (v PlanVersion) PriceCents() int64 {
+	return int64(math.Round(v.PriceDollars * 100))
+}
+
+// PeriodEnd returns when a billing period starting at start ends. One-time
+// plans have no periods; their period ends when it starts.
+func (p Plan) PeriodEnd(start time.Time) time.Time {
+	switch p.BillingCycle {
+	case BillingCycleMonthly:
+		return start.AddDate(0, 1, 0)
+	case BillingCycleYearly:
+		return start.AddDate(1, 0, 0)
+	default:
+		return start
+	}
+}
+
+// IsArchived reports whether the plan has been archived
+func (p Plan) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+// SameVersion reports whether p and o agree on every versioned field
+// (features, billing cycle, price, currency, seats and usage limits). Name,
+// description and metadata can change without creating a new version.
+func (p Plan) SameVersion(o Plan) bool {
+	return slices.Equal(p.FeatureCodes, o.FeatureCodes) &&
+		p.BillingCycle == o.BillingCycle &&
+		p.PriceCents() == o.PriceCents() &&
+		p.Currency == o.Currency &&
+		p.MaxUsers == o.MaxUsers &&
+		jsonEqual(p.UsageLimits, o.UsageLimits)
+}
+
+// VersionSnapshot returns the versioned fields of p as a PlanVersion
+func (p Plan) VersionSnapshot() PlanVersion {
+	return PlanVersion{
+		PlanCode:     p.Code,
+		Version:      p.Version,
+		FeatureCodes: p.FeatureCodes,
+		BillingCycle: p.BillingCycle,
+		PriceDollars: p.PriceDollars,
+		Currency:     p.Currency,
+		MaxUsers:     p.MaxUsers,
+		UsageLimits:  p.UsageLimits,
+	}
+}
+
+// AtVersion returns a copy of p with its versioned fields taken from v, as
+// the plan was sold at that version
+func (p Plan) AtVersion(v PlanVersion) Plan {
+	p.Version = v.Version
+	p.FeatureCodes = v.FeatureCodes
+	p.BillingCycle = v.BillingCycle
+	p.PriceDollars = v.PriceDollars
+	p.Currency = v.Currency
+	p.MaxUsers = v.MaxUsers
+	p.UsageLimits = v.UsageLimits
+	return p
+}
+
+// Validate checks a plan before it is written to the catalog
+func (p Plan) Validate() error {
+	if !planCodePattern.MatchString(p.Code) {
+		return NewInvalidInputError("invalid plan ID", "plan ID must be 1-100 lowercase letters, digits, '_' or '-'")
+	}
+	if p.Name == "" || len(p.Name) > 255 {
+		return NewInvalidInputError("invalid plan name", "name is required and must be at most 255 characters")
+	}
+	switch p.BillingCycle {
+	case BillingCycleMonthly, BillingCycleYearly, BillingCycleOneTime:
+	default:
+		return NewInvalidInputError("invalid billing cycle", fmt.Sprintf("billing_cycle: %s", p.BillingCycle))
+	}
+	if p.PriceDollars < 0 {
+		return NewInvalidInputError("invalid price", "price must not be negative")
+	}
+	if len(p.Currency) != 3 {
+		return NewInvalidInputError("invalid currency", "currency must be 3 characters")
+	}
+	if p.MaxUsers < 0 {
+		return NewInvalidInputError("invalid max users", "max_users must not be negative")
+	}
+	if len(p.FeatureCodes) == 0 {
+		return NewInvalidInputError("invalid feature codes", "a plan must include at least one feature")
+	}
+	seen := make(map[string]bool, len(p.FeatureCodes))
+	for _, code := range p.FeatureCodes {
+		if code == "" {
+			return NewInvalidInputError("invalid feature codes", "feature codes must not be empty")
+		}
+		if seen[code] {
+			return NewInvalidInputError("invalid feature codes", fmt.Sprintf("duplicate feature code: %s", code))
+		}
+		seen[code] = true
+	}
+	for name, raw := range map[string]json.RawMessage{"usage_limits": p.UsageLimits, "metadata": p.Metadata} {
+		if len(raw) > 0 && !json.Valid(raw) {
+			return NewInvalidInputError("invalid plan "+name, name+" must be valid JSON")
+		}
+	}
+	return nil
+}
+
+// jsonEqual compares two JSON documents, treating empty input as null
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if len(a) > 0 {
+		if err := json.Unmarshal(a, &va); err != nil {
+			return false
+		}
+	}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &vb); err != nil {
+			return false
+		}
+	}
+	ja, _ := json.Marshal(va)
+	jb, _ := json.Marshal(vb)
+	return bytes.Equal(ja, jb)
+}
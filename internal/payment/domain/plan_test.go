@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func validPlan() Plan {
+	return Plan{
+		Code:         "pro_monthly",
+		Name:         "Pro Plan - Monthly",
+		FeatureCodes: []string{"advanced_storage", "priority_support"},
+		BillingCycle: BillingCycleMonthly,
+		PriceDollars: 19.99,
+		Currency:     "USD",
+		MaxUsers:     1,
+		UsageLimits:  json.RawMessage(`{"storage_gb": 100}`),
+	}
+}
+
+// TestPlanUUID pins the derivation shared with the plan_uuid() SQL function
+// in migration 0010; both must agree for plan IDs to resolve.
+func TestPlanUUID(t *testing.T) {
+	if got := PlanUUID("basic_monthly").String(); got != "f6845cd0-dda8-57af-8d1a-27a45d76b96a" {
+		t.Errorf("unexpected plan UUID %s", got)
+	}
+}
+
+func TestPlan_Validate(t *testing.T) {
+	if err := validPlan().Validate(); err != nil {
+		t.Fatalf("expected valid plan, got %v", err)
+	}
+
+	tests := map[string]func(*Plan){
+		"bad code":          func(p *Plan) { p.Code = "Pro Monthly" },
+		"missing name":      func(p *Plan) { p.Name = "" },
+		"bad billing cycle": func(p *Plan) { p.BillingCycle = "weekly" },
+		"negative price":    func(p *Plan) { p.PriceDollars = -1 },
+		"bad currency":      func(p *Plan) { p.Currency = "US" },
+		"no features":       func(p *Plan) { p.FeatureCodes = nil },
+		"duplicate feature": func(p *Plan) { p.FeatureCodes = []string{"a", "a"} },
+		"bad usage limits":  func(p *Plan) { p.UsageLimits = json.RawMessage(`{`) },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := validPlan()
+			mutate(&p)
+			if err := p.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestPlan_SameVersion(t *testing.T) {
+	base := validPlan()
+
+	name := "Renamed"
+	if !base.SameVersion(PlanUpdate{Name: &name}.Apply(base)) {
+		t.Error("renaming a plan should not create a new version")
+	}
+
+	reordered := base
+	reordered.UsageLimits = json.RawMessage(`{ "storage_gb" : 100 }`)
+	if !base.SameVersion(reordered) {
+		t.Error("equivalent usage limits should not create a new version")
+	}
+
+	price := 24.99
+	if base.SameVersion(PlanUpdate{PriceDollars: &price}.Apply(base)) {
+		t.Error("a price change should create a new version")
+	}
+
+	if base.SameVersion(PlanUpdate{FeatureCodes: []string{"advanced_storage"}}.Apply(base)) {
+		t.Error("a feature change should create a new version")
+	}
+}
+
+func TestPlan_AtVersion(t *testing.T) {
+	v1 := validPlan()
+	v1.Version = 1
+	snapshot := v1.VersionSnapshot()
+
+	price := 24.99
+	current := PlanUpdate{PriceDollars: &price, FeatureCodes: []string{"advanced_storage"}}.Apply(v1)
+	current.Version = 2
+	current.Name = "Pro"
+
+	grandfathered := current.AtVersion(snapshot)
+	if !grandfathered.SameVersion(v1) || grandfathered.Version != 1 {
+		t.Errorf("expected the plan as sold at version 1, got %+v", grandfathered)
+	}
+	if grandfathered.Name != "Pro" {
+		t.Errorf("expected unversioned fields to stay current, got name %q", grandfathered.Name)
+	}
+}
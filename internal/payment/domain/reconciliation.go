@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// DiscrepancyKind classifies a difference between the billing provider's
+// records and ours
+type DiscrepancyKind string
+
+const (
+	DiscrepancyMissingLocal    DiscrepancyKind = "missing_local"    // The provider has a record we don't
+	DiscrepancyMissingProvider DiscrepancyKind = "missing_provider" // We have a settled record the provider doesn't
+	DiscrepancyAmountMismatch  DiscrepancyKind = "amount_mismatch"
+	DiscrepancyStatusMismatch  DiscrepancyKind = "status_mismatch"
+)
+
+// ReconciledRecord is the kind of record a discrepancy is about
+type ReconciledRecord string
+
+const (
+	ReconciledPayment      ReconciledRecord = "payment"
+	ReconciledSubscription ReconciledRecord = "subscription"
+)
+
+// Discrepancy is one record on which the billing provider and the local
+// database disagree
+type Discrepancy struct {
+	Kind                DiscrepancyKind  `json:"kind"`
+	Record              ReconciledRecord `json:"record"`
+	ProviderID          string           `json:"provider_id,omitempty"` // Charge or subscription ID at the provider
+	SessionID           string           `json:"session_id,omitempty"`  // Checkout session the charge was paid through
+	LocalID             string           `json:"local_id,omitempty"`    // Payment or subscription ID
+	UserID              string           `json:"user_id,omitempty"`
+	Currency            string           `json:"currency,omitempty"`
+	ProviderAmountCents int64            `json:"provider_amount_cents,omitempty"`
+	LocalAmountCents    int64            `json:"local_amount_cents,omitempty"`
+	ProviderStatus      string           `json:"provider_status,omitempty"`
+	LocalStatus         string           `json:"local_status,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`           // When the record was created
+	Healed              bool             `json:"healed"`               // The missed event was re-applied
+	HealError           string           `json:"heal_error,omitempty"` // Why re-applying the event failed
+}
+
+// ReconciliationReport is the outcome of reconciling one time window
+type ReconciliationReport struct {
+	Provider             string        `json:"provider"`
+	From                 time.Time     `json:"from"`
+	To                   time.Time     `json:"to"`
+	ChargesChecked       int           `json:"charges_checked"`
+	PaymentsChecked      int           `json:"payments_checked"`
+	SubscriptionsChecked int           `json:"subscriptions_checked"` // Provider and local subscriptions
+	Discrepancies        []Discrepancy `json:"discrepancies"`
+	Healed               int           `json:"healed"`
+	StartedAt            time.Time     `json:"started_at"`
+	FinishedAt           time.Time     `json:"finished_at"`
+}
+
+// Count returns the number of discrepancies of a kind
+func (r *ReconciliationReport) Count(kind DiscrepancyKind) int {
+	n := 0
+	for _, d := range r.Discrepancies {
+		if d.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevenueSnapshot is a subscription paying at the end of a day and its
+// monthly recurring revenue
+type RevenueSnapshot struct {
+	Date           time.Time `json:"date"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	UserID         string    `json:"user_id"`
+	PlanID         string    `json:"plan_id"`
+	PricingZone    string    `json:"pricing_zone,omitempty"` // Empty when unknown
+	Currency       string    `json:"currency"`
+	MRRCents       int64     `json:"mrr_cents"`
+}
+
+// RevenueDimension is an attribute revenue reports can be sliced by.
+// Reports are always sliced by currency, since amounts in different
+// currencies cannot be added up.
+type RevenueDimension string
+
+const (
+	RevenueByPlan        RevenueDimension = "plan"
+	RevenueByPricingZone RevenueDimension = "pricing_zone"
+	RevenueByCurrency    RevenueDimension = "currency"
+)
+
+// ParseRevenueDimension validates a dimension name
+func ParseRevenueDimension(s string) (RevenueDimension, error) {
+	switch d := RevenueDimension(s); d {
+	case RevenueByPlan, RevenueByPricingZone, RevenueByCurrency:
+		return d, nil
+	default:
+		return "", NewInvalidInputError("invalid revenue dimension", fmt.Sprintf("want plan, pricing_zone or currency: %q", s))
+	}
+}
+
+// RevenueSlice identifies the subscriptions a movement covers. Fields
+// the report is not sliced by are empty.
+type RevenueSlice struct {
+	PlanID      string `json:"plan_id,omitempty"`
+	PricingZone string `json:"pricing_zone,omitempty"`
+	Currency    string `json:"currency"`
+}
+
+// RevenueMovement is how recurring revenue and customers changed within a
+// month for one slice. Starting MRR plus new and expansion, less
+// contraction and churn, equals ending MRR.
+type RevenueMovement struct {
+	Month               time.Time    `json:"month"` // First day of the month
+	Slice               RevenueSlice `json:"slice"`
+	StartingMRRCents    int64        `json:"starting_mrr_cents"`
+	NewMRRCents         int64        `json:"new_mrr_cents"`
+	ExpansionMRRCents   int64        `json:"expansion_mrr_cents"`
+	ContractionMRRCents int64        `json:"contraction_mrr_cents"`
+	ChurnedMRRCents     int64        `json:"churned_mrr_cents"`
+	EndingMRRCents      int64        `json:"ending_mrr_cents"`
+	StartingCustomers   int          `json:"starting_customers"`
+	NewCustomers        int          `json:"new_customers"`     // Paying at the end but not at the start
+	ChurnedCustomers    int          `json:"churned_customers"` // Paying at the start but not at the end
+	EndingCustomers     int          `json:"ending_customers"`
+}
+
+// ARRCents returns the ending annual recurring revenue
+func (m RevenueMovement) ARRCents() int64 {
+	return m.EndingMRRCents * 12
+}
+
+// NetNewMRRCents returns the change in MRR over the month
+func (m RevenueMovement) NetNewMRRCents() int64 {
+	return m.EndingMRRCents - m.StartingMRRCents
+}
+
+// LogoChurnRate returns the share of starting customers lost in the month
+func (m RevenueMovement) LogoChurnRate() float64 {
+	if m.StartingCustomers == 0 {
+		return 0
+	}
+	return float64(m.ChurnedCustomers) / float64(m.StartingCustomers)
+}
+
+// RevenueReport holds the monthly revenue movements of a range of months
+type RevenueReport struct {
+	From      time.Time          `json:"from"` // First month
+	To        time.Time          `json:"to"`   // Last month
+	GroupBy   []RevenueDimension `json:"group_by"`
+	Movements []RevenueMovement  `json:"movements"` // By month, then slice
+}
+
+// MonthStart returns the first day of t's month, in UTC
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ComputeRevenueMovements compares the subscriptions paying at the start
+// and at the end of a month. A subscription whose slice changed, e.g. an
+// upgrade when slicing by plan, contracts in its old slice and expands in
+// its new one, so that every slice adds up on its own.
+func ComputeRevenueMovements(month time.Time, start, end []RevenueSnapshot, groupBy []RevenueDimension) []RevenueMovement {
+	sliceOf := func(s RevenueSnapshot) RevenueSlice {
+		slice := RevenueSlice{Currency: s.Currency}
+		for _, d := range groupBy {
+			switch d {
+			case RevenueByPlan:
+				slice.PlanID = s.PlanID
+			case RevenueByPricingZone:
+				slice.PricingZone = s.PricingZone
+			}
+		}
+		return slice
+	}
+
+	movements := make(map[RevenueSlice]*RevenueMovement)
+	movement := func(slice RevenueSlice) *RevenueMovement {
+		m, ok := movements[slice]
+		if !ok {
+			m = &RevenueMovement{Month: MonthStart(month), Slice: slice}
+			movements[slice] = m
+		}
+		return m
+	}
+
+	startSubs := make(map[uuid.UUID]RevenueSnapshot, len(start))
+	startUsers := make(map[string]bool)
+	for _, s := range start {
+		startSubs[s.SubscriptionID] = s
+		startUsers[s.UserID] = true
+		movement(sliceOf(s)).StartingMRRCents += s.MRRCents
+	}
+	endSubs := make(map[uuid.UUID]RevenueSnapshot, len(end))
+	endUsers := make(map[string]bool)
+	for _, e := range end {
+		endSubs[e.SubscriptionID] = e
+		endUsers[e.UserID] = true
+		movement(sliceOf(e)).EndingMRRCents += e.MRRCents
+	}
+
+	for id, e := range endSubs {
+		s, existed := startSubs[id]
+		switch {
+		case !existed:
+			movement(sliceOf(e)).NewMRRCents += e.MRRCents
+		case sliceOf(s) != sliceOf(e):
+			movement(sliceOf(s)).ContractionMRRCents += s.MRRCents
+			movement(sliceOf(e)).ExpansionMRRCents += e.MRRCents
+		case e.MRRCents > s.MRRCents:
+			movement(sliceOf(e)).ExpansionMRRCents += e.MRRCents - s.MRRCents
+		case e.MRRCents < s.MRRCents:
+			movement(sliceOf(e)).ContractionMRRCents += s.MRRCents - e.MRRCents
+		}
+	}
+	for id, s := range startSubs {
+		if _, ok := endSubs[id]; !ok {
+			movement(sliceOf(s)).ChurnedMRRCents += s.MRRCents
+		}
+	}
+
+	// A customer churns when no subscription of theirs is paying at the
+	// end, and is new when none was paying at the start
+	countCustomers(start, sliceOf, func(slice RevenueSlice, userID string) {
+		m := movement(slice)
+		m.StartingCustomers++
+		if !endUsers[userID] {
+			m.ChurnedCustomers++
+		}
+	})
+	countCustomers(end, sliceOf, func(slice RevenueSlice, userID string) {
+		m := movement(slice)
+		m.EndingCustomers++
+		if !startUsers[userID] {
+			m.NewCustomers++
+		}
+	})
+
+	result := make([]RevenueMovement, 0, len(movements))
+	for _, m := range movements {
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Slice, result[j].Slice
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.PlanID != b.PlanID {
+			return a.PlanID < b.PlanID
+		}
+		return a.PricingZone < b.PricingZone
+	})
+	return result
+}
+
+// countCustomers calls fn once per customer and slice they have a
+// subscription in
+func countCustomers(snapshots []RevenueSnapshot, sliceOf func(RevenueSnapshot) RevenueSlice, fn func(RevenueSlice, string)) {
+	type key struct {
+		slice  RevenueSlice
+		userID string
+	}
+	seen := make(map[key]bool)
+	for _, s := range snapshots {
+		k := key{sliceOf(s), s.UserID}
+		if !seen[k] {
+			seen[k] = true
+			fn(k.slice, k.userID)
+		}
+	}
+}
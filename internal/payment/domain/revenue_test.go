@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestComputeRevenueMovements(t *testing.T) {
+	month := time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)
+	kept, upgraded, downgraded, churned, moved := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	snap := func(id uuid.UUID, user, plan string, mrr int64) RevenueSnapshot {
+		return RevenueSnapshot{SubscriptionID: id, UserID: user, PlanID: plan, PricingZone: "A", Currency: "USD", MRRCents: mrr}
+	}
+
+	start := []RevenueSnapshot{
+		snap(kept, "u1", "basic", 500),
+		snap(upgraded, "u2", "basic", 500),
+		snap(downgraded, "u3", "premium", 1000),
+		snap(churned, "u4", "premium", 1000),
+		snap(moved, "u5", "basic", 500),
+	}
+	end := []RevenueSnapshot{
+		snap(kept, "u1", "basic", 500),
+		snap(upgraded, "u2", "basic", 800),
+		snap(downgraded, "u3", "premium", 600),
+		snap(moved, "u5", "premium", 1000),
+		snap(uuid.New(), "u6", "basic", 500),
+		snap(uuid.New(), "u1", "premium", 1000), // Existing customer's second subscription
+	}
+
+	total := ComputeRevenueMovements(month, start, end, nil)
+	if len(total) != 1 {
+		t.Fatalf("expected a single USD slice, got %+v", total)
+	}
+	m := total[0]
+	if !m.Month.Equal(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)) || m.Slice != (RevenueSlice{Currency: "USD"}) {
+		t.Errorf("unexpected month or slice: %+v", m)
+	}
+	want := RevenueMovement{
+		Month:               m.Month,
+		Slice:               m.Slice,
+		StartingMRRCents:    3500,
+		NewMRRCents:         1500,
+		ExpansionMRRCents:   800,
+		ContractionMRRCents: 400,
+		ChurnedMRRCents:     1000,
+		EndingMRRCents:      4400,
+		StartingCustomers:   5,
+		NewCustomers:        1,
+		ChurnedCustomers:    1,
+		EndingCustomers:     5,
+	}
+	if m != want {
+		t.Errorf("unexpected movement:\n got %+v\nwant %+v", m, want)
+	}
+	if m.ARRCents() != 52800 || m.NetNewMRRCents() != 900 || m.LogoChurnRate() != 0.2 {
+		t.Errorf("unexpected derived figures: %d %d %v", m.ARRCents(), m.NetNewMRRCents(), m.LogoChurnRate())
+	}
+
+	// Sliced by plan, the plan change contracts basic and expands premium,
+	// and every slice still adds up
+	byPlan := ComputeRevenueMovements(month, start, end, []RevenueDimension{RevenueByPlan})
+	if len(byPlan) != 2 || byPlan[0].Slice.PlanID != "basic" || byPlan[1].Slice.PlanID != "premium" {
+		t.Fatalf("unexpected slices: %+v", byPlan)
+	}
+	basic, premium := byPlan[0], byPlan[1]
+	if basic.ContractionMRRCents != 500 || premium.ExpansionMRRCents != 1000 {
+		t.Errorf("expected the plan change to move revenue between slices: %+v %+v", basic, premium)
+	}
+	for _, s := range byPlan {
+		if got := s.StartingMRRCents + s.NewMRRCents + s.ExpansionMRRCents - s.ContractionMRRCents - s.ChurnedMRRCents; got != s.EndingMRRCents {
+			t.Errorf("%s: movements add up to %d, ending MRR is %d", s.Slice.PlanID, got, s.EndingMRRCents)
+		}
+	}
+	if premium.ChurnedCustomers != 1 || premium.StartingCustomers != 2 || premium.EndingCustomers != 3 {
+		t.Errorf("unexpected premium customers: %+v", premium)
+	}
+}
+
+func TestComputeRevenueMovements_Currencies(t *testing.T) {
+	start := []RevenueSnapshot{{SubscriptionID: uuid.New(), UserID: "u1", Currency: "EUR", MRRCents: 900}}
+	end := []RevenueSnapshot{{SubscriptionID: uuid.New(), UserID: "u2", Currency: "USD", MRRCents: 1000}}
+
+	movements := ComputeRevenueMovements(time.Now(), start, end, nil)
+	if len(movements) != 2 || movements[0].Slice.Currency != "EUR" || movements[1].Slice.Currency != "USD" {
+		t.Fatalf("expected one slice per currency, got %+v", movements)
+	}
+	if movements[0].ChurnedMRRCents != 900 || movements[1].NewMRRCents != 1000 {
+		t.Errorf("unexpected movements: %+v", movements)
+	}
+}
+
+func TestParseRevenueDimension(t *testing.T) {
+	if d, err := ParseRevenueDimension("pricing_zone"); err != nil || d != RevenueByPricingZone {
+		t.Errorf("unexpected result: %v, %v", d, err)
+	}
+	if _, err := ParseRevenueDimension("country"); err == nil {
+		t.Error("expected an error for an unknown dimension")
+	}
+}
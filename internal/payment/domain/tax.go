@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TaxRate is the VAT, GST or sales tax rate of a country or region
+type TaxRate struct {
+	ID              string    `json:"id"`
+	CountryCode     string    `json:"country_code"`     // ISO 3166-1 alpha-2 code
+	Region          string    `json:"region,omitempty"` // State or province code; empty for the whole country
+	Name            string    `json:"name"`             // e.g. "VAT", "GST", "Sales tax"
+	RatePercent     float64   `json:"rate_percent"`
+	Inclusive       bool      `json:"inclusive"`        // Catalog prices already include the tax
+	DigitalServices bool      `json:"digital_services"` // The tax applies to digital services
+	ReverseCharge   bool      `json:"reverse_charge"`   // Business buyers with a tax ID self-account the tax
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate checks that the tax rate can be stored
+func (r *TaxRate) Validate() error {
+	if len(r.CountryCode) != 2 {
+		return NewInvalidInputError("invalid tax rate", fmt.Sprintf("country_code must be a 2-letter ISO code: %q", r.CountryCode))
+	}
+	if r.Name == "" {
+		return NewInvalidInputError("invalid tax rate", "name is required")
+	}
+	if r.RatePercent < 0 || r.RatePercent >= 100 {
+		return NewInvalidInputError("invalid tax rate", fmt.Sprintf("rate_percent must be in [0, 100): %v", r.RatePercent))
+	}
+	return nil
+}
+
+// TaxQuote is the tax charged on a price
+type TaxQuote struct {
+	Name          string  `json:"name,omitempty"`
+	RatePercent   float64 `json:"rate_percent"`
+	Inclusive     bool    `json:"inclusive"`      // TaxCents is included in the quoted price
+	ReverseCharge bool    `json:"reverse_charge"` // The buyer accounts for the tax
+	TaxCents      int64   `json:"tax_cents"`
+	TotalCents    int64   `json:"total_cents"` // Amount charged
+}
+
+// NetCents returns the amount charged before tax
+func (q TaxQuote) NetCents() int64 {
+	return q.TotalCents - q.TaxCents
+}
+
+// Label describes the tax as printed on invoices, e.g. "VAT 20%"
+func (q TaxQuote) Label() string {
+	label := q.Name
+	if q.RatePercent > 0 {
+		label = fmt.Sprintf("%s %s%%", q.Name, formatPercent(q.RatePercent))
+	}
+	switch {
+	case q.ReverseCharge:
+		return fmt.Sprintf("%s 0%% (reverse charge)", q.Name)
+	case q.Inclusive:
+		return label + " (included)"
+	default:
+		return label
+	}
+}
+
+// CalculateTax computes the tax on a price in cents. A nil rate, or a rate
+// that does not apply to digital services, charges no tax. Under the
+// reverse charge no tax is charged and tax-inclusive prices are reduced to
+// their net amount.
+func CalculateTax(priceCents int64, rate *TaxRate, reverseCharge bool) TaxQuote {
+	if rate == nil || !rate.DigitalServices || rate.RatePercent == 0 {
+		return TaxQuote{TotalCents: priceCents}
+	}
+
+	quote := TaxQuote{
+		Name:        rate.Name,
+		RatePercent: rate.RatePercent,
+		Inclusive:   rate.Inclusive,
+	}
+	factor := 1 + rate.RatePercent/100
+
+	switch {
+	case reverseCharge && rate.ReverseCharge:
+		quote.ReverseCharge = true
+		quote.Inclusive = false
+		quote.TotalCents = priceCents
+		if rate.Inclusive {
+			quote.TotalCents = int64(math.Round(float64(priceCents) / factor))
+		}
+	case rate.Inclusive:
+		quote.TotalCents = priceCents
+		quote.TaxCents = priceCents - int64(math.Round(float64(priceCents)/factor))
+	default:
+		quote.TaxCents = int64(math.Round(float64(priceCents) * rate.RatePercent / 100))
+		quote.TotalCents = priceCents + quote.TaxCents
+	}
+
+	return quote
+}
+
+// euVATPrefixes maps EU member states to the prefix of their VAT numbers
+var euVATPrefixes = map[string]string{
+	"AT": "AT", "BE": "BE", "BG": "BG", "CY": "CY", "CZ": "CZ", "DE": "DE", "DK": "DK",
+	"EE": "EE", "ES": "ES", "FI": "FI", "FR": "FR", "GR": "EL", "HR": "HR", "HU": "HU",
+	"IE": "IE", "IT": "IT", "LT": "LT", "LU": "LU", "LV": "LV", "MT": "MT", "NL": "NL",
+	"PL": "PL", "PT": "PT", "RO": "RO", "SE": "SE", "SI": "SI", "SK": "SK",
+}
+
+// NormalizeTaxID strips separators from a tax ID and upper-cases it
+func NormalizeTaxID(taxID string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '.' || r == '/' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, taxID)
+}
+
+// ValidTaxID reports whether taxID is well-formed for a business in the
+// given country. EU VAT numbers must carry the member state's prefix. Only
+// the format is checked; registration is not verified.
+func ValidTaxID(countryCode, taxID string) bool {
+	taxID = NormalizeTaxID(taxID)
+	for _, r := range taxID {
+		if !unicode.IsDigit(r) && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+
+	if prefix, ok := euVATPrefixes[strings.ToUpper(countryCode)]; ok {
+		return strings.HasPrefix(taxID, prefix) && len(taxID) >= len(prefix)+8 && len(taxID) <= len(prefix)+12
+	}
+	return len(taxID) >= 5 && len(taxID) <= 20
+}
+
+// formatPercent formats a percentage without trailing zeros, e.g. 20 or 7.25
+func formatPercent(percent float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", percent), "0"), ".")
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "testing"
+
+func TestCalculateTax(t *testing.T) {
+	vat := &TaxRate{Name: "VAT", RatePercent: 20, DigitalServices: true, ReverseCharge: true}
+	vatInclusive := &TaxRate{Name: "VAT", RatePercent: 20, Inclusive: true, DigitalServices: true, ReverseCharge: true}
+	exempt := &TaxRate{Name: "Sales tax", RatePercent: 7.25, DigitalServices: false}
+
+	tests := []struct {
+		name          string
+		rate          *TaxRate
+		reverseCharge bool
+		wantTax       int64
+		wantTotal     int64
+	}{
+		{"no rate", nil, false, 0, 999},
+		{"exclusive", vat, false, 200, 1199},
+		{"inclusive", vatInclusive, false, 166, 999},
+		{"reverse charge", vat, true, 0, 999},
+		{"reverse charge inclusive", vatInclusive, true, 0, 833},
+		{"not applicable to digital services", exempt, false, 0, 999},
+	}
+
+	for _, tt := range tests {
+		q := CalculateTax(999, tt.rate, tt.reverseCharge)
+		if q.TaxCents != tt.wantTax || q.TotalCents != tt.wantTotal {
+			t.Errorf("%s: expected tax %d total %d, got tax %d total %d", tt.name, tt.wantTax, tt.wantTotal, q.TaxCents, q.TotalCents)
+		}
+	}
+
+	if got := CalculateTax(1000, vat, true).Label(); got != "VAT 0% (reverse charge)" {
+		t.Errorf("unexpected reverse charge label %q", got)
+	}
+	if got := CalculateTax(1000, &TaxRate{Name: "Sales tax", RatePercent: 7.25, DigitalServices: true}, false).Label(); got != "Sales tax 7.25%" {
+		t.Errorf("unexpected label %q", got)
+	}
+}
+
+func TestValidTaxID(t *testing.T) {
+	tests := []struct {
+		country, taxID string
+		want           bool
+	}{
+		{"DE", "DE123456789", true},
+		{"DE", "de 123 456 789", true},
+		{"DE", "123456789", false},
+		{"GR", "EL123456789", true},
+		{"GR", "GR123456789", false},
+		{"AU", "51 824 753 556", true},
+		{"AU", "1234", false},
+		{"US", "12-3456789", true},
+		{"US", "12#3456789", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidTaxID(tt.country, tt.taxID); got != tt.want {
+			t.Errorf("ValidTaxID(%q, %q): expected %v, got %v", tt.country, tt.taxID, tt.want, got)
+		}
+	}
+}
+
+func TestTaxRate_Validate(t *testing.T) {
+	if err := (&TaxRate{CountryCode: "DE", Name: "VAT", RatePercent: 19}).Validate(); err != nil {
+		t.Errorf("expected valid rate, got %v", err)
+	}
+	if err := (&TaxRate{CountryCode: "DEU", Name: "VAT", RatePercent: 19}).Validate(); err == nil {
+		t.Error("expected 3-letter country code to be rejected")
+	}
+	if err := (&TaxRate{CountryCode: "DE", Name: "VAT", RatePercent: 100}).Validate(); err == nil {
+		t.Error("expected rate of 100% to be rejected")
+	}
+}
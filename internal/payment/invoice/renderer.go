@@ -0,0 +1,170 @@
+// Package invoice renders issued invoices into customer-facing documents.
+package invoice
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// Content types of rendered invoice documents
+const (
+	ContentTypeHTML = "text/html; charset=utf-8"
+	ContentTypePDF  = "application/pdf"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Seller identifies the issuer printed on every invoice
+type Seller struct {
+	Name    string   `json:"name"`
+	Address []string `json:"address"` // Address lines as printed
+	Email   string   `json:"email,omitempty"`
+	TaxID   string   `json:"tax_id,omitempty"`
+}
+
+// Renderer renders invoices from the embedded templates
+type Renderer struct {
+	seller Seller
+	html   *htmltemplate.Template
+	text   *texttemplate.Template
+}
+
+// templateData is the data passed to the invoice templates
+type templateData struct {
+	Seller  Seller
+	Invoice *domain.Invoice
+}
+
+// NewRenderer creates a new invoice renderer
+func NewRenderer(seller Seller) (*Renderer, error) {
+	html, err := htmltemplate.New("invoice.html.tmpl").Funcs(htmltemplate.FuncMap(templateFuncs)).
+		ParseFS(templateFS, "templates/invoice.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML invoice template: %w", err)
+	}
+
+	text, err := texttemplate.New("invoice.txt.tmpl").Funcs(templateFuncs).
+		ParseFS(templateFS, "templates/invoice.txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text invoice template: %w", err)
+	}
+
+	return &Renderer{
+		seller: seller,
+		html:   html,
+		text:   text,
+	}, nil
+}
+
+// RenderHTML renders an invoice as an HTML document
+func (r *Renderer) RenderHTML(invoice *domain.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.html.Execute(&buf, templateData{Seller: r.seller, Invoice: invoice}); err != nil {
+		return nil, fmt.Errorf("failed to render invoice %s as HTML: %w", invoice.Number, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderText renders an invoice as fixed-width plain text
+func (r *Renderer) RenderText(invoice *domain.Invoice) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := r.text.Execute(&buf, templateData{Seller: r.seller, Invoice: invoice}); err != nil {
+		return nil, fmt.Errorf("failed to render invoice %s as text: %w", invoice.Number, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPDF renders an invoice as a PDF document. The plain text layout is
+// set in a monospaced font so both documents share a single template.
+func (r *Renderer) RenderPDF(invoice *domain.Invoice) ([]byte, error) {
+	text, err := r.RenderText(invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Invoice "+invoice.Number, true)
+	pdf.SetAuthor(r.seller.Name, true)
+	pdf.SetCreationDate(invoice.IssuedAt)
+	pdf.SetModificationDate(invoice.IssuedAt)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+	pdf.SetFont("Courier", "", 10)
+
+	// Core fonts are cp1252 encoded
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	for scanner.Scan() {
+		pdf.CellFormat(0, 4.5, translate(scanner.Text()), "", 1, "L", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice %s as PDF: %w", invoice.Number, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// templateFuncs are the helpers available to both templates
+var templateFuncs = texttemplate.FuncMap{
+	"money":        formatMoney,
+	"neg":          func(cents int64) int64 { return -cents },
+	"date":         func(t time.Time) string { return t.UTC().Format("2006-01-02") },
+	"addressLines": addressLines,
+	"rule":         func(n int) string { return strings.Repeat("-", n) },
+}
+
+// formatMoney formats an amount in cents, e.g. "-5.00 USD"
+func formatMoney(cents int64, currency string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, cents/100, cents%100, currency)
+}
+
+// addressLines formats an address as printed lines
+func addressLines(a domain.Address) []string {
+	var lines []string
+	for _, line := range []string{a.Line1, a.Line2} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	locality := strings.TrimSpace(strings.Join(nonEmpty(a.PostalCode, a.City), " "))
+	if a.Region != "" {
+		locality = strings.Join(nonEmpty(locality, a.Region), ", ")
+	}
+	if locality != "" {
+		lines = append(lines, locality)
+	}
+	if a.Country != "" {
+		lines = append(lines, a.Country)
+	}
+	return lines
+}
+
+// nonEmpty returns the non-empty values
+func nonEmpty(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
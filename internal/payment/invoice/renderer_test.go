@@ -0,0 +1,103 @@
+package invoice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func testInvoice() *domain.Invoice {
+	inv := &domain.Invoice{
+		Number:    domain.FormatInvoiceNumber(2025, 7),
+		PaymentID: uuid.New(),
+		UserID:    "user-1",
+		Currency:  "USD",
+		Status:    domain.InvoiceStatusIssued,
+		Billing: domain.BillingDetails{
+			Name:    "Ada <Lovelace>",
+			Email:   "ada@example.com",
+			Address: domain.Address{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"},
+		},
+		IssuedAt: time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC),
+	}
+	inv.AddLine(domain.InvoiceLinePlan, "Premium (v2)", 999)
+	inv.AddLine(domain.InvoiceLineDiscount, "Discount", -100)
+	return inv
+}
+
+func TestRenderer_RenderHTML(t *testing.T) {
+	r, err := NewRenderer(Seller{Name: "Jia Inc.", Address: []string{"2 Market St"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := r.RenderHTML(testInvoice())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	html := string(out)
+	for _, want := range []string{"INV-2025-000007", "2025-03-14", "Jia Inc.", "12345 Springfield", "9.99 USD", "-1.00 USD", "8.99 USD"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<Lovelace>") {
+		t.Error("expected billing name to be escaped")
+	}
+}
+
+func TestRenderer_RenderPDF(t *testing.T) {
+	r, err := NewRenderer(Seller{Name: "Jia Inc."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := r.RenderPDF(testInvoice())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out, []byte("%PDF-")) {
+		t.Errorf("expected a PDF document, got %q", out[:min(len(out), 16)])
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := map[int64]string{
+		0:     "0.00 EUR",
+		5:     "0.05 EUR",
+		1999:  "19.99 EUR",
+		-1050: "-10.50 EUR",
+	}
+	for cents, want := range tests {
+		if got := formatMoney(cents, "EUR"); got != want {
+			t.Errorf("formatMoney(%d): expected %q, got %q", cents, want, got)
+		}
+	}
+}
+
+func TestRenderer_RenderHTMLReverseCharge(t *testing.T) {
+	r, err := NewRenderer(Seller{Name: "Jia Inc."})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	inv := testInvoice()
+	inv.ReverseCharge = true
+	inv.AddLine(domain.InvoiceLineTax, "VAT 0% (reverse charge)", 0)
+
+	out, err := r.RenderHTML(inv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"VAT 0% (reverse charge)", "Reverse charge:"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected HTML to contain %q", want)
+		}
+	}
+}
@@ -11,16 +11,56 @@ import (
 type PlanRepository interface {
 	GetByID(ctx context.Context, id string) (domain.Plan, error)
 	ListActive(ctx context.Context) ([]domain.Plan, error)
+
+	// GetByIDIncludingArchived retrieves a plan by ID whether or not it is archived
+	GetByIDIncludingArchived(ctx context.Context, id string) (domain.Plan, error)
+
+	// List retrieves catalog plans, optionally including archived ones
+	List(ctx context.Context, includeArchived bool) ([]domain.Plan, error)
+
+	// Create inserts a plan together with its first version
+	Create(ctx context.Context, plan domain.Plan) (domain.Plan, error)
+
+	// Update writes a plan, recording a new version when pricing or features change
+	Update(ctx context.Context, plan domain.Plan) (domain.Plan, error)
+
+	// Archive deactivates a plan so it can no longer be sold
+	Archive(ctx context.Context, id string) (domain.Plan, error)
+
+	// GetVersion retrieves a specific version of a plan
+	GetVersion(ctx context.Context, id string, version int32) (domain.PlanVersion, error)
+
+	// ListVersions retrieves every version of a plan, newest first
+	ListVersions(ctx context.Context, id string) ([]domain.PlanVersion, error)
+}
+
+type FeatureRepository interface {
+	// List retrieves every catalog feature, deprecated ones included, by code
+	List(ctx context.Context) ([]domain.Feature, error)
+
+	// GetByCode retrieves a feature, returning a not-found domain error for unknown codes
+	GetByCode(ctx context.Context, code string) (domain.Feature, error)
+
+	// Create adds a feature to the catalog
+	Create(ctx context.Context, feature domain.Feature) (domain.Feature, error)
+
+	// Update writes a feature's description, quota schema, default limits and deprecation
+	Update(ctx context.Context, feature domain.Feature) (domain.Feature, error)
 }
 
 type EntitlementRepository interface {
 	Check(ctx context.Context, userID, featureCode string) (domain.Entitlement, bool, error)
+	// GetByID returns a not-found domain error for unknown entitlements
+	GetByID(ctx context.Context, id string) (domain.Entitlement, error)
 	ListByUser(ctx context.Context, userID string) ([]domain.Entitlement, error)
 	Insert(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error)
 	UpdateStatus(ctx context.Context, id, status string) error
 	UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error
 	GetBySubscriptionID(ctx context.Context, subscriptionID string) ([]domain.Entitlement, error)
 	Update(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error)
+	// ListAfter returns up to limit entitlements matching the filter in
+	// (created_at, id) order, starting after the cursor when set
+	ListAfter(ctx context.Context, filter domain.EntitlementFilter, after *domain.EntitlementCursor, limit int) ([]domain.Entitlement, error)
 }
 
 type PaymentRepository interface {
@@ -50,6 +90,108 @@ type PaymentRepository interface {
 
 	// Count returns the total number of payments
 	Count(ctx context.Context) (int64, error)
+
+	// ListFiltered retrieves payments matching the query's filter, ordered by
+	// (sort field, id) and resuming after the query's cursor when set
+	ListFiltered(ctx context.Context, query domain.PaymentListQuery) ([]*domain.Payment, error)
+
+	// CountFiltered returns the number of payments matching the filter
+	CountFiltered(ctx context.Context, filter domain.PaymentFilter) (int64, error)
+}
+
+type CheckoutSessionRepository interface {
+	// Create persists a checkout session opened with a billing provider
+	Create(ctx context.Context, session domain.CheckoutSession) (domain.CheckoutSession, error)
+
+	// GetByProviderSessionID retrieves a checkout session by its provider and
+	// the provider's session ID; session IDs are only unique per provider
+	GetByProviderSessionID(ctx context.Context, provider, providerSessionID string) (domain.CheckoutSession, error)
+
+	// ListByProviderSessionID retrieves the checkout sessions recorded under
+	// a provider's session ID with any provider, oldest first
+	ListByProviderSessionID(ctx context.Context, providerSessionID string) ([]domain.CheckoutSession, error)
+
+	// UpdateStatus moves a checkout session to a new status
+	UpdateStatus(ctx context.Context, id uuid.UUID, status domain.CheckoutSessionStatus) (domain.CheckoutSession, error)
+
+	// CloseIfOpen moves an open checkout session to a terminal status. The
+	// boolean is false, and the session unchanged, if it was no longer open.
+	CloseIfOpen(ctx context.Context, id uuid.UUID, status domain.CheckoutSessionStatus) (domain.CheckoutSession, bool, error)
+
+	// ClaimForCompletion moves a checkout session to completing so that only
+	// one delivery of its completion webhook applies it. Open, expired and
+	// cancelled sessions can be claimed, as can sessions whose claim was
+	// taken before staleBefore. The boolean is false, and the session
+	// unchanged, if it could not be claimed.
+	ClaimForCompletion(ctx context.Context, id uuid.UUID, staleBefore time.Time) (domain.CheckoutSession, bool, error)
+
+	// ListExpired retrieves up to limit open sessions that expired before the given time, oldest first
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.CheckoutSession, error)
+}
+
+type InvoiceRepository interface {
+	// Create issues an invoice, assigning the next gap-free number for the
+	// year of its issue date, and stores its line items
+	Create(ctx context.Context, invoice domain.Invoice) (domain.Invoice, error)
+
+	// GetByID retrieves an invoice with its line items
+	GetByID(ctx context.Context, id uuid.UUID) (domain.Invoice, error)
+
+	// GetByNumber retrieves an invoice by its invoice number
+	GetByNumber(ctx context.Context, number string) (domain.Invoice, error)
+
+	// GetByPaymentID retrieves the invoice issued for a payment
+	GetByPaymentID(ctx context.Context, paymentID uuid.UUID) (domain.Invoice, error)
+
+	// List retrieves up to limit invoices matching the filter, newest first,
+	// starting after the cursor when set
+	List(ctx context.Context, filter domain.InvoiceFilter, after *domain.InvoiceCursor, limit int) ([]domain.Invoice, error)
+}
+
+type CustomerRepository interface {
+	// GetByUserID retrieves the billing profile of a user
+	GetByUserID(ctx context.Context, userID string) (domain.Customer, error)
+
+	// Upsert creates or updates the billing profile of a user
+	Upsert(ctx context.Context, customer domain.Customer) (domain.Customer, error)
+
+	// SetProviderCustomerID records the customer's ID at the billing provider
+	SetProviderCustomerID(ctx context.Context, id uuid.UUID, provider, providerCustomerID string) (domain.Customer, error)
+
+	// AddPaymentMethod saves a tokenized payment method for a customer
+	AddPaymentMethod(ctx context.Context, method domain.SavedPaymentMethod) (domain.SavedPaymentMethod, error)
+
+	// GetPaymentMethod retrieves a saved payment method by ID
+	GetPaymentMethod(ctx context.Context, id uuid.UUID) (domain.SavedPaymentMethod, error)
+
+	// GetDefaultPaymentMethod retrieves a customer's default payment method
+	GetDefaultPaymentMethod(ctx context.Context, customerID uuid.UUID) (domain.SavedPaymentMethod, error)
+
+	// ListPaymentMethods retrieves a customer's saved payment methods, default first
+	ListPaymentMethods(ctx context.Context, customerID uuid.UUID) ([]domain.SavedPaymentMethod, error)
+
+	// SetDefaultPaymentMethod makes a method the customer's only default
+	SetDefaultPaymentMethod(ctx context.Context, customerID, methodID uuid.UUID) (domain.SavedPaymentMethod, error)
+
+	// DeletePaymentMethod removes a saved payment method
+	DeletePaymentMethod(ctx context.Context, id uuid.UUID) error
+}
+
+type TaxRateRepository interface {
+	// GetRate retrieves the rate for a region, falling back to the country-wide rate
+	GetRate(ctx context.Context, countryCode, region string) (domain.TaxRate, error)
+
+	// List retrieves all tax rates
+	List(ctx context.Context) ([]domain.TaxRate, error)
+
+	// Upsert creates or updates the rate of a country or region
+	Upsert(ctx context.Context, rate domain.TaxRate) (domain.TaxRate, error)
+
+	// BulkUpsert creates or updates multiple rates in one transaction
+	BulkUpsert(ctx context.Context, rates []domain.TaxRate) error
+
+	// Delete deletes the rate of a country or region
+	Delete(ctx context.Context, countryCode, region string) error
 }
 
 type PricingZoneRepository interface {
@@ -94,3 +236,51 @@ type UsageRepository interface {
 	// ListUsageByUser gets usage records for a user
 	ListUsageByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Usage, error)
 }
+
+type LedgerRepository interface {
+	// Post records a journal entry and its postings in one transaction. An
+	// entry whose reference was already posted is skipped; the boolean
+	// reports whether the entry was new.
+	Post(ctx context.Context, entry *domain.JournalEntry) (bool, error)
+
+	// UpdatePaymentStatus changes a payment's status and posts the entry
+	// the change implies in the same transaction. A nil entry only updates
+	// the status.
+	UpdatePaymentStatus(ctx context.Context, paymentID, status string, entry *domain.JournalEntry) error
+
+	// GetEntryByReference retrieves a posted entry with its postings
+	GetEntryByReference(ctx context.Context, reference string) (*domain.JournalEntry, error)
+
+	// ListEntriesByPayment retrieves the entries posted for a payment, oldest first
+	ListEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]*domain.JournalEntry, error)
+
+	// Balances totals postings per account and currency, optionally for
+	// one customer or one account
+	Balances(ctx context.Context, customerID, accountCode string) ([]domain.AccountBalance, error)
+
+	// ListUnbalancedEntries retrieves the entries whose debits and credits differ
+	ListUnbalancedEntries(ctx context.Context) ([]domain.UnbalancedEntry, error)
+}
+
+type RevenueRepository interface {
+	// Snapshot records the subscriptions paying at the end of a day,
+	// replacing any earlier snapshot of that day; it returns the number of
+	// subscriptions recorded
+	Snapshot(ctx context.Context, date time.Time) (int64, error)
+
+	// LatestSnapshotDate returns the most recent snapshot day on or before
+	// a day, or nil when there is none
+	LatestSnapshotDate(ctx context.Context, onOrBefore time.Time) (*time.Time, error)
+
+	// ListSnapshot retrieves the subscriptions recorded for a day
+	ListSnapshot(ctx context.Context, date time.Time) ([]domain.RevenueSnapshot, error)
+}
+
+type AuditRepository interface {
+	// Record appends an audit event; events are never updated or deleted
+	Record(ctx context.Context, event domain.AuditEvent) (*domain.AuditEvent, error)
+
+	// List retrieves audit events newest first, starting after the cursor
+	// when one is given
+	List(ctx context.Context, filter domain.AuditFilter, after *domain.AuditCursor, limit int) ([]*domain.AuditEvent, error)
+}
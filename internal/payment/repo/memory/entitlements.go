@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// entitlementRepository implements repository.EntitlementRepository
+type entitlementRepository struct {
+	store *Store
+}
+
+// Check checks if a user has an active, unexpired entitlement for a feature,
+// returning the most recently granted one
+func (r *entitlementRepository) Check(ctx context.Context, userID, featureCode string) (domain.Entitlement, bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := r.store.now()
+	matches := r.store.listEntitlements(func(e domain.Entitlement) bool {
+		return e.UserID == userID &&
+			e.FeatureCode == featureCode &&
+			e.Status == "active" &&
+			(e.ExpiresAt == nil || e.ExpiresAt.After(now))
+	})
+	if len(matches) == 0 {
+		return domain.Entitlement{}, false, nil
+	}
+	return matches[0], true, nil
+}
+
+// GetByID retrieves an entitlement by ID
+func (r *entitlementRepository) GetByID(ctx context.Context, id string) (domain.Entitlement, error) {
+	entitlementUUID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Entitlement{}, domain.NewNotFoundError("entitlement", id)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.entitlements[entitlementUUID]
+	if !ok {
+		return domain.Entitlement{}, domain.NewNotFoundError("entitlement", id)
+	}
+	return cloneEntitlement(row), nil
+}
+
+// ListByUser retrieves all entitlements for a user, most recently granted first
+func (r *entitlementRepository) ListByUser(ctx context.Context, userID string) ([]domain.Entitlement, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listEntitlements(func(e domain.Entitlement) bool {
+		return e.UserID == userID
+	}), nil
+}
+
+// ListAfter retrieves a keyset-paginated batch of entitlements matching the filter
+func (r *entitlementRepository) ListAfter(ctx context.Context, filter domain.EntitlementFilter, after *domain.EntitlementCursor, limit int) ([]domain.Entitlement, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var result []domain.Entitlement
+	for _, e := range r.store.entitlements {
+		switch {
+		case filter.UserID != "" && e.UserID != filter.UserID,
+			filter.FamilyID != "" && (e.FamilyID == nil || *e.FamilyID != filter.FamilyID),
+			filter.FeatureCode != "" && e.FeatureCode != filter.FeatureCode,
+			filter.Status != "" && e.Status != filter.Status,
+			filter.Source != "" && e.Source != filter.Source,
+			after != nil && compareEntitlementKey(e, after.CreatedAt, after.ID) <= 0:
+			continue
+		}
+		result = append(result, cloneEntitlement(e))
+	}
+
+	slices.SortFunc(result, func(a, b domain.Entitlement) int {
+		return compareEntitlementKey(a, b.CreatedAt, b.ID)
+	})
+	return result[:min(max(limit, 0), len(result))], nil
+}
+
+// Insert creates a new entitlement
+func (r *entitlementRepository) Insert(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// entitlements.plan_id references plans(id)
+	if _, err := r.store.planCode(e.PlanID); err != nil {
+		return domain.Entitlement{}, fmt.Errorf("failed to insert entitlement: unknown plan %s", e.PlanID)
+	}
+
+	now := r.store.tick()
+	row := cloneEntitlement(e)
+	row.ID = newID(r.store.entitlements)
+	row.GrantedAt = dbTime(row.GrantedAt)
+	row.ExpiresAt = dbTimePtr(row.ExpiresAt)
+	if len(row.UsageLimits) == 0 {
+		row.UsageLimits = json.RawMessage("{}")
+	}
+	row.Metadata = json.RawMessage("{}")
+	if row.Source == "" {
+		row.Source = domain.EntitlementSourcePaid
+	}
+	row.CreatedAt = now
+	row.UpdatedAt = now
+	r.store.entitlements[row.ID] = row
+
+	return cloneEntitlement(row), nil
+}
+
+// UpdateStatus updates the status of an entitlement
+func (r *entitlementRepository) UpdateStatus(ctx context.Context, id, status string) error {
+	entitlementUUID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid entitlement ID: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.entitlements[entitlementUUID]
+	if !ok {
+		return fmt.Errorf("failed to update entitlement status: %w", errNoRows)
+	}
+	row.Status = status
+	row.UpdatedAt = r.store.tick()
+	r.store.entitlements[entitlementUUID] = row
+	return nil
+}
+
+// UpdateExpiry updates the expiry time of an entitlement
+func (r *entitlementRepository) UpdateExpiry(ctx context.Context, id string, expiresAt *time.Time) error {
+	entitlementUUID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid entitlement ID: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.entitlements[entitlementUUID]
+	if !ok {
+		return fmt.Errorf("failed to update entitlement expiry: %w", errNoRows)
+	}
+	row.ExpiresAt = dbTimePtr(expiresAt)
+	row.UpdatedAt = r.store.tick()
+	r.store.entitlements[entitlementUUID] = row
+	return nil
+}
+
+// GetBySubscriptionID retrieves entitlements by subscription ID, most recently granted first
+func (r *entitlementRepository) GetBySubscriptionID(ctx context.Context, subscriptionID string) ([]domain.Entitlement, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listEntitlements(func(e domain.Entitlement) bool {
+		return e.SubscriptionID != nil && *e.SubscriptionID == subscriptionID
+	}), nil
+}
+
+// Update overwrites an entitlement's grant; usage limits and metadata are kept
+func (r *entitlementRepository) Update(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.entitlements[e.ID]
+	if !ok {
+		return domain.Entitlement{}, fmt.Errorf("failed to update entitlement: %w", errNoRows)
+	}
+	if _, err := r.store.planCode(e.PlanID); err != nil {
+		return domain.Entitlement{}, fmt.Errorf("failed to update entitlement: unknown plan %s", e.PlanID)
+	}
+	row.UserID = e.UserID
+	row.FamilyID = cloneString(e.FamilyID)
+	row.FeatureCode = e.FeatureCode
+	row.PlanID = e.PlanID
+	row.SubscriptionID = cloneString(e.SubscriptionID)
+	row.Status = e.Status
+	row.GrantedAt = dbTime(e.GrantedAt)
+	row.ExpiresAt = dbTimePtr(e.ExpiresAt)
+	row.UpdatedAt = r.store.tick()
+	r.store.entitlements[row.ID] = row
+
+	return cloneEntitlement(row), nil
+}
+
+// listEntitlements returns the entitlements matching keep, most recently
+// granted first. Callers must hold the lock.
+func (s *Store) listEntitlements(keep func(domain.Entitlement) bool) []domain.Entitlement {
+	var result []domain.Entitlement
+	for _, e := range s.entitlements {
+		if keep(e) {
+			result = append(result, cloneEntitlement(e))
+		}
+	}
+	slices.SortFunc(result, func(a, b domain.Entitlement) int {
+		if c := b.GrantedAt.Compare(a.GrantedAt); c != 0 {
+			return c
+		}
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return result
+}
+
+// compareEntitlementKey compares e with a (created_at, id) key in ascending order
+func compareEntitlementKey(e domain.Entitlement, createdAt time.Time, id uuid.UUID) int {
+	if c := e.CreatedAt.Compare(createdAt); c != 0 {
+		return c
+	}
+	return compareUUID(e.ID, id)
+}
+
+// dbTime returns t at the database's microsecond precision
+func dbTime(t time.Time) time.Time {
+	return t.Truncate(time.Microsecond)
+}
+
+// dbTimePtr returns a copy of an optional time at the database's precision
+func dbTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := dbTime(*t)
+	return &c
+}
+
+// cloneEntitlement copies an entitlement so callers cannot change stored rows
+func cloneEntitlement(e domain.Entitlement) domain.Entitlement {
+	e.FamilyID = cloneString(e.FamilyID)
+	e.SubscriptionID = cloneString(e.SubscriptionID)
+	e.ExpiresAt = cloneTime(e.ExpiresAt)
+	e.UsageLimits = json.RawMessage(cloneBytes(e.UsageLimits))
+	e.Metadata = json.RawMessage(cloneBytes(e.Metadata))
+	return e
+}
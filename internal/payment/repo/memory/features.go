@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// featureRepository implements repository.FeatureRepository
+type featureRepository struct {
+	store *Store
+}
+
+// List retrieves every catalog feature by code
+func (r *featureRepository) List(ctx context.Context) ([]domain.Feature, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	features := make([]domain.Feature, 0, len(r.store.features))
+	for _, code := range slices.Sorted(maps.Keys(r.store.features)) {
+		features = append(features, cloneFeature(r.store.features[code]))
+	}
+	return features, nil
+}
+
+// GetByCode retrieves a feature by code
+func (r *featureRepository) GetByCode(ctx context.Context, code string) (domain.Feature, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	feature, ok := r.store.features[code]
+	if !ok {
+		return domain.Feature{}, domain.NewNotFoundError("feature", code)
+	}
+	return cloneFeature(feature), nil
+}
+
+// Create adds a feature to the catalog
+func (r *featureRepository) Create(ctx context.Context, feature domain.Feature) (domain.Feature, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.features[feature.Code]; exists {
+		return domain.Feature{}, domain.NewAlreadyExistsError("feature", feature.Code)
+	}
+
+	now := r.store.tick()
+	created := storedFeature(feature)
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.features[created.Code] = created
+
+	return cloneFeature(created), nil
+}
+
+// Update writes a feature's description, quota schema, default limits and deprecation
+func (r *featureRepository) Update(ctx context.Context, feature domain.Feature) (domain.Feature, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.features[feature.Code]
+	if !ok {
+		return domain.Feature{}, domain.NewNotFoundError("feature", feature.Code)
+	}
+
+	updated := storedFeature(feature)
+	updated.CreatedAt = current.CreatedAt
+	updated.UpdatedAt = r.store.tick()
+	r.store.features[updated.Code] = updated
+
+	return cloneFeature(updated), nil
+}
+
+// storedFeature returns a feature as the database stores it, with empty
+// JSON documents written as {}
+func storedFeature(f domain.Feature) domain.Feature {
+	f = cloneFeature(f)
+	if f.QuotaSchema == nil {
+		f.QuotaSchema = map[string]domain.QuotaType{}
+	}
+	if len(f.DefaultLimits) == 0 {
+		f.DefaultLimits = json.RawMessage("{}")
+	}
+	return f
+}
+
+// cloneFeature copies a feature so callers cannot change stored rows
+func cloneFeature(f domain.Feature) domain.Feature {
+	f.QuotaSchema = maps.Clone(f.QuotaSchema)
+	f.DefaultLimits = json.RawMessage(cloneBytes(f.DefaultLimits))
+	return f
+}
@@ -0,0 +1,294 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// paymentRepository implements repository.PaymentRepository
+type paymentRepository struct {
+	store *Store
+}
+
+// Create creates a new payment
+func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := r.store.tick()
+	row := clonePayment(*payment)
+	row.ID = newID(r.store.payments)
+	row.Amount = roundCents(row.Amount)
+	row.CreatedAt = now
+	row.UpdatedAt = now
+	r.store.payments[row.ID] = row
+
+	// Update the payment with the generated ID and timestamps
+	payment.ID = row.ID
+	payment.CreatedAt = row.CreatedAt
+	payment.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// GetByID retrieves a payment by ID
+func (r *paymentRepository) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
+	paymentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payment ID: %w", err)
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.payments[paymentUUID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get payment: %w", errNoRows)
+	}
+	return paymentOut(row), nil
+}
+
+// GetByOrderID retrieves a payment by order ID
+func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, row := range r.store.sortedPayments() {
+		if row.OrderID == orderID {
+			return paymentOut(row), nil
+		}
+	}
+	return nil, domain.NewNotFoundError("payment", orderID)
+}
+
+// GetByCustomerID retrieves payments by customer ID, newest first
+func (r *paymentRepository) GetByCustomerID(ctx context.Context, customerID string, limit, offset int) ([]*domain.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	payments := []*domain.Payment{}
+	for _, row := range r.store.sortedPayments() {
+		if row.CustomerID == customerID {
+			payments = append(payments, paymentOut(row))
+		}
+	}
+	return paginate(payments, limit, offset), nil
+}
+
+// Update updates an existing payment. Like the postgres store it leaves
+// the provider the payment was taken with unchanged.
+func (r *paymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.payments[payment.ID]
+	if !ok {
+		return fmt.Errorf("failed to update payment: %w", errNoRows)
+	}
+
+	row := clonePayment(*payment)
+	row.Amount = roundCents(row.Amount)
+	row.Provider = current.Provider
+	row.CreatedAt = current.CreatedAt
+	row.UpdatedAt = r.store.tick()
+	r.store.payments[row.ID] = row
+	return nil
+}
+
+// UpdateStatus updates only the status of a payment, clearing any failure reason
+func (r *paymentRepository) UpdateStatus(ctx context.Context, id string, status string) error {
+	paymentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid payment ID: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.payments[paymentUUID]
+	if !ok {
+		return fmt.Errorf("failed to update payment status: %w", errNoRows)
+	}
+	row.Status = status
+	row.FailureReason = ""
+	row.UpdatedAt = r.store.tick()
+	r.store.payments[paymentUUID] = row
+	return nil
+}
+
+// Delete deletes a payment (hard delete)
+func (r *paymentRepository) Delete(ctx context.Context, id string) error {
+	paymentUUID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid payment ID: %w", err)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.payments, paymentUUID)
+	return nil
+}
+
+// List retrieves a list of payments with pagination, newest first
+func (r *paymentRepository) List(ctx context.Context, limit, offset int) ([]*domain.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := r.store.sortedPayments()
+	payments := make([]*domain.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = paymentOut(row)
+	}
+	return paginate(payments, limit, offset), nil
+}
+
+// Count returns the total number of payments
+func (r *paymentRepository) Count(ctx context.Context) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return int64(len(r.store.payments)), nil
+}
+
+// ListFiltered retrieves a filtered, keyset-paginated list of payments
+func (r *paymentRepository) ListFiltered(ctx context.Context, query domain.PaymentListQuery) ([]*domain.Payment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rows []domain.Payment
+	for _, row := range r.store.payments {
+		if matchesPaymentFilter(row, query.Filter) && afterPaymentCursor(row, query) {
+			rows = append(rows, row)
+		}
+	}
+
+	slices.SortFunc(rows, func(a, b domain.Payment) int {
+		c := comparePaymentKey(a, b, query.SortBy)
+		if query.SortDesc {
+			return -c
+		}
+		return c
+	})
+
+	// Offset only applies to the first page; later pages resume from the cursor
+	if query.Cursor == nil && query.Offset > 0 {
+		rows = rows[min(query.Offset, len(rows)):]
+	}
+	rows = rows[:min(max(query.Limit, 0), len(rows))]
+
+	payments := make([]*domain.Payment, len(rows))
+	for i, row := range rows {
+		payments[i] = paymentOut(row)
+	}
+	return payments, nil
+}
+
+// CountFiltered returns the number of payments matching the filter
+func (r *paymentRepository) CountFiltered(ctx context.Context, filter domain.PaymentFilter) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, row := range r.store.payments {
+		if matchesPaymentFilter(row, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// sortedPayments returns every payment, newest first. Callers must hold the lock.
+func (s *Store) sortedPayments() []domain.Payment {
+	rows := make([]domain.Payment, 0, len(s.payments))
+	for _, row := range s.payments {
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b domain.Payment) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return rows
+}
+
+// matchesPaymentFilter applies the same predicates as ListPaymentsFiltered
+func matchesPaymentFilter(p domain.Payment, f domain.PaymentFilter) bool {
+	switch {
+	case f.CustomerID != "" && p.CustomerID != f.CustomerID,
+		f.Status != "" && p.Status != f.Status,
+		f.Currency != "" && p.Currency != f.Currency,
+		f.PaymentMethod != "" && p.PaymentMethod != f.PaymentMethod,
+		f.ExternalPaymentID != "" && p.ExternalPaymentID != f.ExternalPaymentID,
+		f.CreatedAfter != nil && p.CreatedAt.Before(*f.CreatedAfter),
+		f.CreatedBefore != nil && !p.CreatedAt.Before(*f.CreatedBefore),
+		f.MinAmount != nil && p.Amount < roundCents(*f.MinAmount),
+		f.MaxAmount != nil && p.Amount > roundCents(*f.MaxAmount):
+		return false
+	}
+	return true
+}
+
+// afterPaymentCursor reports whether p sorts strictly after the query's cursor
+func afterPaymentCursor(p domain.Payment, query domain.PaymentListQuery) bool {
+	if query.Cursor == nil {
+		return true
+	}
+	cursor := domain.Payment{
+		ID:        query.Cursor.ID,
+		Amount:    roundCents(query.Cursor.Amount),
+		CreatedAt: query.Cursor.CreatedAt,
+	}
+	c := comparePaymentKey(p, cursor, query.SortBy)
+	if query.SortDesc {
+		return c < 0
+	}
+	return c > 0
+}
+
+// comparePaymentKey compares two payments by (sort field, id) in ascending order
+func comparePaymentKey(a, b domain.Payment, sortBy domain.PaymentSortField) int {
+	var c int
+	switch sortBy {
+	case domain.PaymentSortByAmount:
+		c = cmp.Compare(a.Amount, b.Amount)
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c != 0 {
+		return c
+	}
+	return compareUUID(a.ID, b.ID)
+}
+
+// paginate applies an offset and limit the way the postgres store does,
+// ignoring either when it is out of range
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset > 0 && offset < len(items) {
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// roundCents rounds a dollar amount to the two decimal places stored by Postgres
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// clonePayment copies a payment so callers cannot change stored rows
+func clonePayment(p domain.Payment) domain.Payment {
+	p.Metadata = cloneBytes(p.Metadata)
+	return p
+}
+
+// paymentOut copies a stored payment for a caller
+func paymentOut(p domain.Payment) *domain.Payment {
+	out := clonePayment(p)
+	return &out
+}
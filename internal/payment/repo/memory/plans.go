@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// planRepository implements repository.PlanRepository
+type planRepository struct {
+	store *Store
+}
+
+// GetByID retrieves an active plan by ID
+func (r *planRepository) GetByID(ctx context.Context, id string) (domain.Plan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	plan, ok := r.store.plans[id]
+	if !ok || !plan.Active {
+		return domain.Plan{}, domain.NewNotFoundError("plan", id)
+	}
+	return clonePlan(plan), nil
+}
+
+// GetByIDIncludingArchived retrieves a plan by ID whether or not it is archived
+func (r *planRepository) GetByIDIncludingArchived(ctx context.Context, id string) (domain.Plan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	plan, ok := r.store.plans[id]
+	if !ok {
+		return domain.Plan{}, domain.NewNotFoundError("plan", id)
+	}
+	return clonePlan(plan), nil
+}
+
+// ListActive retrieves all active plans, newest first
+func (r *planRepository) ListActive(ctx context.Context) ([]domain.Plan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listPlans(func(p domain.Plan) bool { return p.Active }), nil
+}
+
+// List retrieves catalog plans, optionally including archived ones, newest first
+func (r *planRepository) List(ctx context.Context, includeArchived bool) ([]domain.Plan, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listPlans(func(p domain.Plan) bool { return includeArchived || p.ArchivedAt == nil }), nil
+}
+
+// Create inserts a plan together with its first version
+func (r *planRepository) Create(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.plans[plan.Code]; exists {
+		return domain.Plan{}, domain.NewAlreadyExistsError("plan", plan.Code)
+	}
+
+	now := r.store.tick()
+	created := storedPlan(plan)
+	created.Version = 1
+	created.ArchivedAt = nil
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	r.store.plans[created.Code] = created
+	r.store.insertPlanVersion(created.VersionSnapshot(), now)
+
+	return clonePlan(created), nil
+}
+
+// Update writes the plan's fields. When any versioned field differs from the
+// stored plan, the version is bumped and the new version is recorded;
+// existing subscriptions stay on the version they were sold at.
+func (r *planRepository) Update(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	current, ok := r.store.plans[plan.Code]
+	if !ok {
+		return domain.Plan{}, domain.NewNotFoundError("plan", plan.Code)
+	}
+
+	now := r.store.tick()
+	updated := storedPlan(plan)
+	updated.Active = current.Active
+	updated.ArchivedAt = current.ArchivedAt
+	updated.CreatedAt = current.CreatedAt
+	updated.UpdatedAt = now
+	updated.Version = current.Version
+	newVersion := !updated.SameVersion(current)
+	if newVersion {
+		updated.Version++
+	}
+	r.store.plans[updated.Code] = updated
+	if newVersion {
+		r.store.insertPlanVersion(updated.VersionSnapshot(), now)
+	}
+
+	return clonePlan(updated), nil
+}
+
+// Archive deactivates a plan so it can no longer be sold
+func (r *planRepository) Archive(ctx context.Context, id string) (domain.Plan, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	plan, ok := r.store.plans[id]
+	if !ok {
+		return domain.Plan{}, domain.NewNotFoundError("plan", id)
+	}
+
+	now := r.store.tick()
+	plan.Active = false
+	if plan.ArchivedAt == nil {
+		plan.ArchivedAt = &now
+	}
+	plan.UpdatedAt = now
+	r.store.plans[id] = plan
+
+	return clonePlan(plan), nil
+}
+
+// GetVersion retrieves a specific version of a plan
+func (r *planRepository) GetVersion(ctx context.Context, id string, version int32) (domain.PlanVersion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, v := range r.store.planVersions[id] {
+		if v.Version == version {
+			return clonePlanVersion(v), nil
+		}
+	}
+	return domain.PlanVersion{}, domain.NewNotFoundError("plan", fmt.Sprintf("%s@%d", id, version))
+}
+
+// ListVersions retrieves every version of a plan, newest first
+func (r *planRepository) ListVersions(ctx context.Context, id string) ([]domain.PlanVersion, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.planVersions[id]
+	versions := make([]domain.PlanVersion, len(stored))
+	for i, v := range stored {
+		versions[len(stored)-1-i] = clonePlanVersion(v)
+	}
+	return versions, nil
+}
+
+// listPlans returns the plans matching keep, newest first. Callers must hold the lock.
+func (s *Store) listPlans(keep func(domain.Plan) bool) []domain.Plan {
+	plans := make([]domain.Plan, 0, len(s.plans))
+	for _, plan := range s.plans {
+		if keep(plan) {
+			plans = append(plans, clonePlan(plan))
+		}
+	}
+	slices.SortFunc(plans, func(a, b domain.Plan) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return plans
+}
+
+// insertPlanVersion records a plan version. Versions are appended in
+// increasing order. Callers must hold the write lock.
+func (s *Store) insertPlanVersion(v domain.PlanVersion, now time.Time) {
+	v = storedPlanVersion(v)
+	v.CreatedAt = now
+	s.planVersions[v.PlanCode] = append(s.planVersions[v.PlanCode], v)
+}
+
+// storedPlan returns a copy of plan as the plans table would hold it: keyed
+// by code, priced in whole cents and without a negative seat count
+func storedPlan(plan domain.Plan) domain.Plan {
+	plan = clonePlan(plan)
+	plan.ID = domain.PlanUUID(plan.Code)
+	plan.PriceDollars = float64(plan.PriceCents()) / 100
+	plan.MaxUsers = max(plan.MaxUsers, 0)
+	return plan
+}
+
+// storedPlanVersion returns a copy of v as the plan_versions table would hold it
+func storedPlanVersion(v domain.PlanVersion) domain.PlanVersion {
+	v = clonePlanVersion(v)
+	v.PriceDollars = float64(v.PriceCents()) / 100
+	v.MaxUsers = max(v.MaxUsers, 0)
+	return v
+}
+
+// clonePlan copies a plan so callers cannot change stored rows
+func clonePlan(p domain.Plan) domain.Plan {
+	p.FeatureCodes = slices.Clone(p.FeatureCodes)
+	p.UsageLimits = json.RawMessage(cloneBytes(p.UsageLimits))
+	p.Metadata = json.RawMessage(cloneBytes(p.Metadata))
+	p.ArchivedAt = cloneTime(p.ArchivedAt)
+	return p
+}
+
+// clonePlanVersion copies a plan version so callers cannot change stored rows
+func clonePlanVersion(v domain.PlanVersion) domain.PlanVersion {
+	v.FeatureCodes = slices.Clone(v.FeatureCodes)
+	v.UsageLimits = json.RawMessage(cloneBytes(v.UsageLimits))
+	return v
+}
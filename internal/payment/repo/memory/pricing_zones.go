@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// pricingZoneRepository implements repository.PricingZoneRepository
+type pricingZoneRepository struct {
+	store *Store
+}
+
+// GetByISOCode retrieves a pricing zone by ISO country code
+func (r *pricingZoneRepository) GetByISOCode(ctx context.Context, isoCode string) (domain.PricingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	zone, ok := r.store.pricingZones[isoCode]
+	if !ok {
+		return domain.PricingZone{}, domain.NewNotFoundError("pricing zone", isoCode)
+	}
+	return zone, nil
+}
+
+// GetByCountry retrieves a pricing zone by country name, ignoring case
+func (r *pricingZoneRepository) GetByCountry(ctx context.Context, country string) (domain.PricingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, zone := range r.store.sortedPricingZones() {
+		if strings.EqualFold(zone.Country, country) {
+			return zone, nil
+		}
+	}
+	return domain.PricingZone{}, domain.NewNotFoundError("pricing zone", country)
+}
+
+// GetByZone retrieves all pricing zones for a specific zone type, by country
+func (r *pricingZoneRepository) GetByZone(ctx context.Context, zone string) ([]domain.PricingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	zones := []domain.PricingZone{}
+	for _, z := range r.store.sortedPricingZones() {
+		if z.Zone == zone {
+			zones = append(zones, z)
+		}
+	}
+	return zones, nil
+}
+
+// List retrieves all pricing zones by zone and country
+func (r *pricingZoneRepository) List(ctx context.Context) ([]domain.PricingZone, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.sortedPricingZones(), nil
+}
+
+// Upsert creates or updates a pricing zone, keyed by ISO code
+func (r *pricingZoneRepository) Upsert(ctx context.Context, zone domain.PricingZone) (domain.PricingZone, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.upsertPricingZone(zone), nil
+}
+
+// BulkUpsert creates or updates multiple pricing zones
+func (r *pricingZoneRepository) BulkUpsert(ctx context.Context, zones []domain.PricingZone) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, zone := range zones {
+		r.store.upsertPricingZone(zone)
+	}
+	return nil
+}
+
+// Delete deletes a pricing zone by ISO code
+func (r *pricingZoneRepository) Delete(ctx context.Context, isoCode string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.pricingZones, isoCode)
+	return nil
+}
+
+// upsertPricingZone writes a zone, keeping the ID and creation time of an
+// existing zone with the same ISO code. Callers must hold the write lock.
+func (s *Store) upsertPricingZone(zone domain.PricingZone) domain.PricingZone {
+	now := s.tick()
+	zone.PricingMultiplier = math.Round(zone.PricingMultiplier*100) / 100
+	zone.UpdatedAt = now
+	if existing, ok := s.pricingZones[zone.ISOCode]; ok {
+		zone.ID = existing.ID
+		zone.CreatedAt = existing.CreatedAt
+	} else {
+		zone.ID = uuid.NewString()
+		zone.CreatedAt = now
+	}
+	s.pricingZones[zone.ISOCode] = zone
+	return zone
+}
+
+// sortedPricingZones returns every zone ordered by zone, then country.
+// Callers must hold the lock.
+func (s *Store) sortedPricingZones() []domain.PricingZone {
+	zones := make([]domain.PricingZone, 0, len(s.pricingZones))
+	for _, zone := range s.pricingZones {
+		zones = append(zones, zone)
+	}
+	slices.SortFunc(zones, func(a, b domain.PricingZone) int {
+		return cmp.Or(cmp.Compare(a.Zone, b.Zone), cmp.Compare(a.Country, b.Country))
+	})
+	return zones
+}
@@ -0,0 +1,143 @@
+// Package memory implements the payment repositories in process memory.
+//
+// The store mirrors the semantics of the postgres store, including ordering,
+// not-found errors and the rounding of stored amounts, so usecases can be
+// tested without a database and the service can run a demo without one.
+// Nothing is persisted across restarts.
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// errNoRows is returned where the postgres store surfaces a missing row as a
+// plain error rather than a domain not-found error
+var errNoRows = errors.New("no rows in result set")
+
+// Store holds every repository's data behind a single lock so that
+// repositories referring to each other, such as subscriptions resolving
+// their plan, see a consistent view
+type Store struct {
+	mu   sync.RWMutex
+	now  func() time.Time
+	last time.Time
+
+	payments      map[uuid.UUID]domain.Payment
+	plans         map[string]domain.Plan
+	planVersions  map[string][]domain.PlanVersion
+	features      map[string]domain.Feature
+	entitlements  map[uuid.UUID]domain.Entitlement
+	subscriptions map[uuid.UUID]subscriptionRow
+	pricingZones  map[string]domain.PricingZone
+	usage         map[uuid.UUID]usageRow
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		now:           time.Now,
+		payments:      make(map[uuid.UUID]domain.Payment),
+		plans:         make(map[string]domain.Plan),
+		planVersions:  make(map[string][]domain.PlanVersion),
+		features:      make(map[string]domain.Feature),
+		entitlements:  make(map[uuid.UUID]domain.Entitlement),
+		subscriptions: make(map[uuid.UUID]subscriptionRow),
+		pricingZones:  make(map[string]domain.PricingZone),
+		usage:         make(map[uuid.UUID]usageRow),
+	}
+}
+
+// Payment returns the payment repository implementation
+func (s *Store) Payment() repo.PaymentRepository {
+	return &paymentRepository{store: s}
+}
+
+// Plan returns the plan repository implementation
+func (s *Store) Plan() repo.PlanRepository {
+	return &planRepository{store: s}
+}
+
+// Feature returns the feature catalog repository implementation
+func (s *Store) Feature() repo.FeatureRepository {
+	return &featureRepository{store: s}
+}
+
+// Entitlement returns the entitlement repository implementation
+func (s *Store) Entitlement() repo.EntitlementRepository {
+	return &entitlementRepository{store: s}
+}
+
+// Subscription returns the subscription repository implementation
+func (s *Store) Subscription() repo.SubscriptionRepository {
+	return &subscriptionRepository{store: s}
+}
+
+// PricingZone returns the pricing zone repository implementation
+func (s *Store) PricingZone() repo.PricingZoneRepository {
+	return &pricingZoneRepository{store: s}
+}
+
+// Usage returns the usage repository implementation
+func (s *Store) Usage() repo.UsageRepository {
+	return &usageRepository{store: s}
+}
+
+// tick returns the current time at the database's microsecond precision.
+// Successive calls never return the same instant, so rows written in a burst
+// still have a strict created_at order. Callers must hold the write lock.
+func (s *Store) tick() time.Time {
+	t := s.now().UTC().Truncate(time.Microsecond)
+	if !t.After(s.last) {
+		t = s.last.Add(time.Microsecond)
+	}
+	s.last = t
+	return t
+}
+
+// newID returns a random row ID that is not yet used by any table
+func newID[T any](rows map[uuid.UUID]T) uuid.UUID {
+	for {
+		id := uuid.New()
+		if _, taken := rows[id]; !taken {
+			return id
+		}
+	}
+}
+
+// compareUUID orders UUIDs the way Postgres does, byte by byte
+func compareUUID(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// cloneBytes copies a byte slice, keeping nil as nil
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+// cloneTime copies an optional time so callers cannot change stored rows
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// cloneString copies an optional string so callers cannot change stored rows
+func cloneString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/repo/repotest"
+)
+
+func TestStore_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Store {
+		return NewStore()
+	})
+}
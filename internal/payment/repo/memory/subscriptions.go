@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// subscriptionRow is a stored subscription. Like the subscriptions table it
+// references the plan by code and keeps metadata as encoded JSON, so reads
+// return the same values the postgres store would.
+type subscriptionRow struct {
+	sub      domain.Subscription
+	planCode string
+	metadata []byte
+}
+
+// subscriptionRepository implements repository.SubscriptionRepository
+type subscriptionRepository struct {
+	store *Store
+}
+
+// Create creates a new subscription
+func (r *subscriptionRepository) Create(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	planCode, err := r.store.planCode(sub.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := encodeSubscriptionMetadata(sub.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if sub.ExternalSubscriptionID != "" {
+		if _, ok := r.store.subscriptionByExternalID(sub.ExternalSubscriptionID); ok {
+			return nil, domain.NewAlreadyExistsError("subscription", sub.ExternalSubscriptionID)
+		}
+	}
+
+	now := r.store.tick()
+	row := subscriptionRow{
+		sub: domain.Subscription{
+			ID:                     newID(r.store.subscriptions),
+			UserID:                 sub.UserID,
+			FamilyID:               cloneString(sub.FamilyID),
+			PlanVersion:            max(sub.PlanVersion, 0),
+			Status:                 sub.Status,
+			CurrentPeriodStart:     dbTime(sub.CurrentPeriodStart),
+			CurrentPeriodEnd:       dbTime(sub.CurrentPeriodEnd),
+			CancelAtPeriodEnd:      sub.CancelAtPeriodEnd,
+			ExternalSubscriptionID: sub.ExternalSubscriptionID,
+			CreatedAt:              now,
+			UpdatedAt:              now,
+		},
+		planCode: planCode,
+		metadata: metadata,
+	}
+	r.store.subscriptions[row.sub.ID] = row
+
+	return row.out(), nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *subscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.subscriptions[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("subscription", id.String())
+	}
+	return row.out(), nil
+}
+
+// GetByExternalID retrieves a subscription by external subscription ID
+func (r *subscriptionRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.subscriptionByExternalID(externalID)
+	if !ok {
+		return nil, domain.NewNotFoundError("subscription", externalID)
+	}
+	return row.out(), nil
+}
+
+// GetByUserID retrieves all subscriptions for a user, newest first
+func (r *subscriptionRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return row.sub.UserID == userID
+	}, newestFirst), nil
+}
+
+// GetByStatus retrieves subscriptions with a specific status, newest first
+func (r *subscriptionRepository) GetByStatus(ctx context.Context, status string) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return row.sub.Status == status
+	}, newestFirst), nil
+}
+
+// Update updates the status, period and metadata of a subscription
+func (r *subscriptionRepository) Update(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
+	metadata, err := encodeSubscriptionMetadata(sub.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.subscriptions[sub.ID]
+	if !ok {
+		return nil, domain.NewNotFoundError("subscription", sub.ID.String())
+	}
+	row.sub.Status = sub.Status
+	row.sub.CurrentPeriodStart = dbTime(sub.CurrentPeriodStart)
+	row.sub.CurrentPeriodEnd = dbTime(sub.CurrentPeriodEnd)
+	row.sub.CancelAtPeriodEnd = sub.CancelAtPeriodEnd
+	row.sub.CancelledAt = dbTimePtr(sub.CancelledAt)
+	row.sub.UpdatedAt = r.store.tick()
+	row.metadata = metadata
+	r.store.subscriptions[sub.ID] = row
+
+	return row.out(), nil
+}
+
+// Delete deletes a subscription
+func (r *subscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.subscriptions, id)
+	return nil
+}
+
+// GetExpiringSubscriptions retrieves active subscriptions whose period ends
+// before a given date, soonest first
+func (r *subscriptionRepository) GetExpiringSubscriptions(ctx context.Context, beforeDate time.Time) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return row.sub.Status == domain.SubscriptionStatusActive && !row.sub.CurrentPeriodEnd.After(beforeDate)
+	}, func(a, b subscriptionRow) int {
+		return a.sub.CurrentPeriodEnd.Compare(b.sub.CurrentPeriodEnd)
+	}), nil
+}
+
+// GetActiveSubscriptions retrieves all active subscriptions, newest first
+func (r *subscriptionRepository) GetActiveSubscriptions(ctx context.Context) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return row.sub.Status == domain.SubscriptionStatusActive
+	}, newestFirst), nil
+}
+
+// GetSubscriptionsByPlan retrieves subscriptions for a specific plan, newest first
+func (r *subscriptionRepository) GetSubscriptionsByPlan(ctx context.Context, planID uuid.UUID) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	code, err := r.store.planCode(planID)
+	if err != nil {
+		return nil, err
+	}
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return row.planCode == code
+	}, newestFirst), nil
+}
+
+// ListCreatedBetween retrieves subscriptions created in [from, to), oldest first
+func (r *subscriptionRepository) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*domain.Subscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listSubscriptions(func(row subscriptionRow) bool {
+		return !row.sub.CreatedAt.Before(from) && row.sub.CreatedAt.Before(to)
+	}, func(a, b subscriptionRow) int {
+		if c := a.sub.CreatedAt.Compare(b.sub.CreatedAt); c != 0 {
+			return c
+		}
+		return compareUUID(a.sub.ID, b.sub.ID)
+	}), nil
+}
+
+// planCode resolves a plan UUID to the plan's code. Callers must hold the lock.
+func (s *Store) planCode(planUUID uuid.UUID) (string, error) {
+	for code := range s.plans {
+		if domain.PlanUUID(code) == planUUID {
+			return code, nil
+		}
+	}
+	return "", domain.NewNotFoundError("plan", planUUID.String())
+}
+
+// subscriptionByExternalID finds a subscription by its provider's ID.
+// Callers must hold the lock.
+func (s *Store) subscriptionByExternalID(externalID string) (subscriptionRow, bool) {
+	if externalID == "" {
+		return subscriptionRow{}, false
+	}
+	for _, row := range s.subscriptions {
+		if row.sub.ExternalSubscriptionID == externalID {
+			return row, true
+		}
+	}
+	return subscriptionRow{}, false
+}
+
+// listSubscriptions returns the subscriptions matching keep in the given
+// order. Callers must hold the lock.
+func (s *Store) listSubscriptions(keep func(subscriptionRow) bool, order func(a, b subscriptionRow) int) []*domain.Subscription {
+	var rows []subscriptionRow
+	for _, row := range s.subscriptions {
+		if keep(row) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, order)
+
+	subs := make([]*domain.Subscription, len(rows))
+	for i, row := range rows {
+		subs[i] = row.out()
+	}
+	return subs
+}
+
+// newestFirst orders subscriptions by creation time, newest first
+func newestFirst(a, b subscriptionRow) int {
+	return b.sub.CreatedAt.Compare(a.sub.CreatedAt)
+}
+
+// out returns a copy of the stored subscription for a caller
+func (row subscriptionRow) out() *domain.Subscription {
+	sub := row.sub
+	sub.PlanID = domain.PlanUUID(row.planCode)
+	sub.FamilyID = cloneString(sub.FamilyID)
+	sub.CancelledAt = cloneTime(sub.CancelledAt)
+	if len(row.metadata) > 0 {
+		_ = json.Unmarshal(row.metadata, &sub.Metadata)
+	}
+	return &sub
+}
+
+// encodeSubscriptionMetadata encodes subscription metadata, storing nothing when there is none
+func encodeSubscriptionMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode subscription metadata: %w", err)
+	}
+	return data, nil
+}
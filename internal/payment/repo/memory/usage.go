@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// usageRow is a stored usage record with its metadata kept as encoded JSON
+type usageRow struct {
+	usage    domain.Usage
+	metadata []byte
+}
+
+// usageRepository implements repository.UsageRepository
+type usageRepository struct {
+	store *Store
+}
+
+// Create creates a new usage record
+func (r *usageRepository) Create(ctx context.Context, usage domain.Usage) error {
+	metadata, err := encodeUsageMetadata(usage.Metadata)
+	if err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row := usageRow{usage: usage, metadata: metadata}
+	row.usage.ID = newID(r.store.usage)
+	row.usage.FamilyID = cloneString(usage.FamilyID)
+	row.usage.Metadata = nil
+	row.usage.CreatedAt = r.store.tick()
+	r.store.usage[row.usage.ID] = row
+	return nil
+}
+
+// GetCurrentUsage sums a user's usage of a resource recorded within the period
+func (r *usageRepository) GetCurrentUsage(ctx context.Context, userID, featureCode, resourceType string, period time.Duration) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total int64
+	for _, u := range r.store.usageWithin(userID, featureCode, resourceType, period) {
+		total += u.ResourceSize
+	}
+	return total, nil
+}
+
+// GetUsageHistory lists a user's usage of a resource recorded within the period, newest first
+func (r *usageRepository) GetUsageHistory(ctx context.Context, userID, featureCode, resourceType string, period time.Duration) ([]domain.Usage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.usageWithin(userID, featureCode, resourceType, period), nil
+}
+
+// DeleteUsage deletes usage records for a user, feature, and resource type
+func (r *usageRepository) DeleteUsage(ctx context.Context, userID, featureCode, resourceType string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, row := range r.store.usage {
+		if row.usage.UserID == userID && row.usage.FeatureCode == featureCode && row.usage.ResourceType == resourceType {
+			delete(r.store.usage, id)
+		}
+	}
+	return nil
+}
+
+// GetUsageByID gets a usage record by ID
+func (r *usageRepository) GetUsageByID(ctx context.Context, id uuid.UUID) (*domain.Usage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.usage[id]
+	if !ok {
+		return nil, domain.NewNotFoundError("usage", id.String())
+	}
+	u := row.out()
+	return &u, nil
+}
+
+// ListUsageByUser gets usage records for a user, newest first
+func (r *usageRepository) ListUsageByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Usage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	records := r.store.listUsage(func(u domain.Usage) bool { return u.UserID == userID })
+	records = records[min(max(offset, 0), len(records)):]
+	return records[:min(max(limit, 0), len(records))], nil
+}
+
+// usageWithin returns a user's usage of a resource recorded since now minus
+// the period, newest first. Callers must hold the lock.
+func (s *Store) usageWithin(userID, featureCode, resourceType string, period time.Duration) []domain.Usage {
+	since := s.now().Add(-period)
+	return s.listUsage(func(u domain.Usage) bool {
+		return u.UserID == userID &&
+			u.FeatureCode == featureCode &&
+			u.ResourceType == resourceType &&
+			!u.CreatedAt.Before(since)
+	})
+}
+
+// listUsage returns the usage records matching keep, newest first.
+// Callers must hold the lock.
+func (s *Store) listUsage(keep func(domain.Usage) bool) []domain.Usage {
+	records := []domain.Usage{}
+	for _, row := range s.usage {
+		if keep(row.usage) {
+			records = append(records, row.out())
+		}
+	}
+	slices.SortFunc(records, func(a, b domain.Usage) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return records
+}
+
+// out returns a copy of the stored usage record for a caller
+func (row usageRow) out() domain.Usage {
+	u := row.usage
+	u.FamilyID = cloneString(u.FamilyID)
+	if len(row.metadata) > 0 {
+		_ = json.Unmarshal(row.metadata, &u.Metadata)
+	}
+	return u
+}
+
+// encodeUsageMetadata encodes usage metadata, storing nothing when there is none
+func encodeUsageMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode usage metadata: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/repo/repotest"
+)
+
+// TestStore_Contract runs the repository contract against a migrated,
+// disposable database named by PAYMENT_TEST_POSTGRES_DSN. Every subtest
+// truncates the tables it covers.
+func TestStore_Contract(t *testing.T) {
+	dsn := os.Getenv("PAYMENT_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("PAYMENT_TEST_POSTGRES_DSN is not set")
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Store {
+		store, err := NewStore(dsn)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+
+		_, err = store.db.Exec(context.Background(),
+			"TRUNCATE payments, entitlements, subscriptions, plan_versions, plans, features, pricing_zones, usage CASCADE")
+		if err != nil {
+			t.Fatalf("failed to truncate tables: %v", err)
+		}
+		return store
+	})
+}
@@ -12,7 +12,7 @@ import (
 )
 
 const CheckEntitlement = `-- name: CheckEntitlement :one
-SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at FROM entitlements 
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements 
 WHERE user_id = $1 
   AND feature_code = $2
   AND status = 'active'
@@ -43,12 +43,13 @@ func (q *Queries) CheckEntitlement(ctx context.Context, db DBTX, arg CheckEntitl
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
 
 const GetEntitlementByID = `-- name: GetEntitlementByID :one
-SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at FROM entitlements 
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements 
 WHERE id = $1
 `
 
@@ -69,12 +70,13 @@ func (q *Queries) GetEntitlementByID(ctx context.Context, db DBTX, id pgtype.UUI
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
 
 const GetEntitlementsBySubscriptionID = `-- name: GetEntitlementsBySubscriptionID :many
-SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at FROM entitlements 
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements 
 WHERE subscription_id = $1
 ORDER BY granted_at DESC
 `
@@ -102,6 +104,7 @@ func (q *Queries) GetEntitlementsBySubscriptionID(ctx context.Context, db DBTX,
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -116,13 +119,13 @@ func (q *Queries) GetEntitlementsBySubscriptionID(ctx context.Context, db DBTX,
 const InsertEntitlement = `-- name: InsertEntitlement :one
 INSERT INTO entitlements (
     user_id, family_id, feature_code, plan_id, subscription_id,
-    status, granted_at, expires_at, usage_limits, metadata
+    status, granted_at, expires_at, usage_limits, metadata, source
 ) VALUES (
     $1, $2, $3,
     $4, $5,
     $6, $7, $8,
-    $9, $10
-) RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at
+    $9, $10, $11
+) RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source
 `
 
 type InsertEntitlementParams struct {
@@ -136,6 +139,7 @@ type InsertEntitlementParams struct {
 	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
 	UsageLimits    []byte           `json:"usage_limits"`
 	Metadata       []byte           `json:"metadata"`
+	Source         string           `json:"source"`
 }
 
 func (q *Queries) InsertEntitlement(ctx context.Context, db DBTX, arg InsertEntitlementParams) (*Entitlement, error) {
@@ -150,6 +154,7 @@ func (q *Queries) InsertEntitlement(ctx context.Context, db DBTX, arg InsertEnti
 		arg.ExpiresAt,
 		arg.UsageLimits,
 		arg.Metadata,
+		arg.Source,
 	)
 	var i Entitlement
 	err := row.Scan(
@@ -166,12 +171,83 @@ func (q *Queries) InsertEntitlement(ctx context.Context, db DBTX, arg InsertEnti
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
 
+const ListEntitlementsAfter = `-- name: ListEntitlementsAfter :many
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements
+WHERE ($1::text IS NULL OR user_id = $1)
+  AND ($2::text IS NULL OR family_id = $2)
+  AND ($3::text IS NULL OR feature_code = $3)
+  AND ($4::text IS NULL OR status = $4)
+  AND ($5::text IS NULL OR source = $5)
+  AND (
+    $6::uuid IS NULL
+    OR (created_at, id) > ($7::timestamp, $6::uuid)
+  )
+ORDER BY created_at ASC, id ASC
+LIMIT $8
+`
+
+type ListEntitlementsAfterParams struct {
+	UserID          pgtype.Text      `json:"user_id"`
+	FamilyID        pgtype.Text      `json:"family_id"`
+	FeatureCode     pgtype.Text      `json:"feature_code"`
+	Status          pgtype.Text      `json:"status"`
+	Source          pgtype.Text      `json:"source"`
+	CursorID        pgtype.UUID      `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamp `json:"cursor_created_at"`
+	PageLimit       int32            `json:"page_limit"`
+}
+
+func (q *Queries) ListEntitlementsAfter(ctx context.Context, db DBTX, arg ListEntitlementsAfterParams) ([]*Entitlement, error) {
+	rows, err := db.Query(ctx, ListEntitlementsAfter,
+		arg.UserID,
+		arg.FamilyID,
+		arg.FeatureCode,
+		arg.Status,
+		arg.Source,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Entitlement{}
+	for rows.Next() {
+		var i Entitlement
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FamilyID,
+			&i.FeatureCode,
+			&i.PlanID,
+			&i.SubscriptionID,
+			&i.Status,
+			&i.GrantedAt,
+			&i.ExpiresAt,
+			&i.UsageLimits,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Source,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListEntitlementsByUser = `-- name: ListEntitlementsByUser :many
-SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at FROM entitlements 
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements 
 WHERE user_id = $1
 ORDER BY granted_at DESC
 `
@@ -199,6 +275,7 @@ func (q *Queries) ListEntitlementsByUser(ctx context.Context, db DBTX, userID st
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -211,7 +288,7 @@ func (q *Queries) ListEntitlementsByUser(ctx context.Context, db DBTX, userID st
 }
 
 const ListExpiringEntitlements = `-- name: ListExpiringEntitlements :many
-SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at FROM entitlements 
+SELECT id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source FROM entitlements 
 WHERE expires_at IS NOT NULL 
   AND expires_at <= NOW() 
   AND status = 'active'
@@ -241,6 +318,7 @@ func (q *Queries) ListExpiringEntitlements(ctx context.Context, db DBTX) ([]*Ent
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Source,
 		); err != nil {
 			return nil, err
 		}
@@ -264,7 +342,7 @@ SET user_id = $1,
     expires_at = $8,
     updated_at = NOW()
 WHERE id = $9
-RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source
 `
 
 type UpdateEntitlementParams struct {
@@ -306,6 +384,7 @@ func (q *Queries) UpdateEntitlement(ctx context.Context, db DBTX, arg UpdateEnti
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
@@ -314,7 +393,7 @@ const UpdateEntitlementExpiry = `-- name: UpdateEntitlementExpiry :one
 UPDATE entitlements 
 SET expires_at = $1, updated_at = NOW()
 WHERE id = $2
-RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source
 `
 
 type UpdateEntitlementExpiryParams struct {
@@ -339,6 +418,7 @@ func (q *Queries) UpdateEntitlementExpiry(ctx context.Context, db DBTX, arg Upda
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
@@ -347,7 +427,7 @@ const UpdateEntitlementStatus = `-- name: UpdateEntitlementStatus :one
 UPDATE entitlements 
 SET status = $1, updated_at = NOW()
 WHERE id = $2
-RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, feature_code, plan_id, subscription_id, status, granted_at, expires_at, usage_limits, metadata, created_at, updated_at, source
 `
 
 type UpdateEntitlementStatusParams struct {
@@ -372,6 +452,7 @@ func (q *Queries) UpdateEntitlementStatus(ctx context.Context, db DBTX, arg Upda
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Source,
 	)
 	return &i, err
 }
@@ -8,6 +8,77 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// Who changed what and why; never updated or deleted
+type AuditEvent struct {
+	ID         pgtype.UUID `json:"id"`
+	EntityType string      `json:"entity_type"`
+	EntityID   string      `json:"entity_id"`
+	Action     string      `json:"action"`
+	ActorType  string      `json:"actor_type"`
+	// User ID or SPIFFE ID from the auth context; empty for system changes such as webhooks and workers
+	ActorID string `json:"actor_id"`
+	// Changed fields as {"field": {"before": ..., "after": ...}}
+	Changes    []byte           `json:"changes"`
+	RequestID  string           `json:"request_id"`
+	Reason     string           `json:"reason"`
+	OccurredAt pgtype.Timestamp `json:"occurred_at"`
+}
+
+// Checkout sessions opened with a billing provider; the source of truth for completion webhooks
+type CheckoutSession struct {
+	ID                pgtype.UUID `json:"id"`
+	Provider          string      `json:"provider"`
+	ProviderSessionID string      `json:"provider_session_id"`
+	PlanID            string      `json:"plan_id"`
+	PlanVersion       int32       `json:"plan_version"`
+	UserID            string      `json:"user_id"`
+	FamilyID          pgtype.Text `json:"family_id"`
+	CountryCode       pgtype.Text `json:"country_code"`
+	// Catalog price of the plan version at checkout, in cents
+	BasePriceCents int32 `json:"base_price_cents"`
+	// Price quoted to the customer after the pricing zone multiplier, in cents
+	QuotedPriceCents int32 `json:"quoted_price_cents"`
+	// Pricing zone multiplier applied to the base price
+	PricingMultiplier pgtype.Numeric   `json:"pricing_multiplier"`
+	Currency          string           `json:"currency"`
+	Status            string           `json:"status"`
+	Url               pgtype.Text      `json:"url"`
+	ExpiresAt         pgtype.Timestamp `json:"expires_at"`
+	CompletedAt       pgtype.Timestamp `json:"completed_at"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+	Region            pgtype.Text      `json:"region"`
+	// Business tax ID supplied by the buyer
+	TaxID          pgtype.Text    `json:"tax_id"`
+	TaxName        pgtype.Text    `json:"tax_name"`
+	TaxRatePercent pgtype.Numeric `json:"tax_rate_percent"`
+	TaxInclusive   bool           `json:"tax_inclusive"`
+	ReverseCharge  bool           `json:"reverse_charge"`
+	// Tax on the quoted price in cents, included in total_cents
+	TaxCents int32 `json:"tax_cents"`
+	// Amount charged by the billing provider in cents
+	TotalCents int32 `json:"total_cents"`
+}
+
+// Billing profile of a user, mirrored to the billing provider as a customer
+type Customer struct {
+	ID     pgtype.UUID `json:"id"`
+	UserID string      `json:"user_id"`
+	Name   pgtype.Text `json:"name"`
+	Email  pgtype.Text `json:"email"`
+	// Billing address as JSON: line1, line2, city, region, postal_code, country
+	Address []byte `json:"address"`
+	// Business tax ID, normalized; applies the reverse charge at checkout where valid
+	TaxID pgtype.Text `json:"tax_id"`
+	// Currency the customer prefers to be billed in (optional)
+	PreferredCurrency pgtype.Text `json:"preferred_currency"`
+	Provider          pgtype.Text `json:"provider"`
+	// Customer ID at the billing provider, set once the customer is created there
+	ProviderCustomerID pgtype.Text      `json:"provider_customer_id"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
+	UpdatedAt          pgtype.Timestamp `json:"updated_at"`
+}
+
 type Entitlement struct {
 	ID             pgtype.UUID      `json:"id"`
 	UserID         string           `json:"user_id"`
@@ -22,6 +93,107 @@ type Entitlement struct {
 	Metadata       []byte           `json:"metadata"`
 	CreatedAt      pgtype.Timestamp `json:"created_at"`
 	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	// paid, or complimentary for entitlements granted by support without payment
+	Source string `json:"source"`
+}
+
+type Feature struct {
+	Code          string `json:"code"`
+	Description   string `json:"description"`
+	QuotaSchema   []byte `json:"quota_schema"`
+	DefaultLimits []byte `json:"default_limits"`
+	// Deprecated features can no longer be added to plans; existing plans and entitlements keep them
+	Deprecated bool             `json:"deprecated"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+// Invoices issued for completed payments
+type Invoice struct {
+	ID pgtype.UUID `json:"id"`
+	// Human-readable invoice number, e.g. INV-2025-000042
+	Number string `json:"number"`
+	Year   int32  `json:"year"`
+	// Gap-free sequence number within the year
+	Sequence          int32       `json:"sequence"`
+	PaymentID         pgtype.UUID `json:"payment_id"`
+	CheckoutSessionID pgtype.UUID `json:"checkout_session_id"`
+	UserID            string      `json:"user_id"`
+	FamilyID          pgtype.Text `json:"family_id"`
+	PlanID            string      `json:"plan_id"`
+	PlanVersion       int32       `json:"plan_version"`
+	Currency          string      `json:"currency"`
+	SubtotalCents     int64       `json:"subtotal_cents"`
+	DiscountCents     int64       `json:"discount_cents"`
+	TaxCents          int64       `json:"tax_cents"`
+	TotalCents        int64       `json:"total_cents"`
+	BillingName       pgtype.Text `json:"billing_name"`
+	BillingEmail      pgtype.Text `json:"billing_email"`
+	// Buyer postal address as a JSON object
+	BillingAddress []byte           `json:"billing_address"`
+	BillingTaxID   pgtype.Text      `json:"billing_tax_id"`
+	Status         string           `json:"status"`
+	IssuedAt       pgtype.Timestamp `json:"issued_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	// Whether the line amounts include the tax line
+	TaxInclusive bool `json:"tax_inclusive"`
+	// Whether the buyer accounts for the tax under the reverse charge
+	ReverseCharge bool `json:"reverse_charge"`
+}
+
+type InvoiceLineItem struct {
+	InvoiceID       pgtype.UUID `json:"invoice_id"`
+	Position        int32       `json:"position"`
+	Kind            string      `json:"kind"`
+	Description     string      `json:"description"`
+	Quantity        int32       `json:"quantity"`
+	UnitAmountCents int64       `json:"unit_amount_cents"`
+	// Signed line amount in cents; discounts and downward zone adjustments are negative
+	AmountCents int64 `json:"amount_cents"`
+}
+
+type InvoiceSequence struct {
+	Year       int32 `json:"year"`
+	LastNumber int32 `json:"last_number"`
+}
+
+// One balanced money movement, e.g. a charge or a refund; never updated or deleted
+type JournalEntry struct {
+	ID pgtype.UUID `json:"id"`
+	// Idempotency key, e.g. payment:<id>:charge; replays of a movement post nothing
+	Reference string `json:"reference"`
+	Kind      string `json:"kind"`
+	// Payment the movement belongs to; not a foreign key so the ledger outlives deleted payments
+	PaymentID   pgtype.UUID      `json:"payment_id"`
+	CustomerID  pgtype.Text      `json:"customer_id"`
+	Currency    string           `json:"currency"`
+	Description pgtype.Text      `json:"description"`
+	OccurredAt  pgtype.Timestamp `json:"occurred_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+// Chart of accounts the ledger posts to
+type LedgerAccount struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Side that increases the account; contra accounts such as discounts are debit-normal revenue
+	NormalBalance string           `json:"normal_balance"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+// Debit or credit of one account; the postings of an entry balance per currency
+type LedgerPosting struct {
+	ID          pgtype.UUID `json:"id"`
+	EntryID     pgtype.UUID `json:"entry_id"`
+	AccountCode string      `json:"account_code"`
+	Direction   string      `json:"direction"`
+	AmountCents int64       `json:"amount_cents"`
+	Currency    string      `json:"currency"`
+	// Copied from the entry so balances per customer need no join
+	CustomerID pgtype.Text      `json:"customer_id"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
 }
 
 type Payment struct {
@@ -39,6 +211,26 @@ type Payment struct {
 	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
 	// Amount in dollars (e.g., 19.99)
 	Amount pgtype.Numeric `json:"amount"`
+	// Billing provider the payment was taken with; NULL for payments made before routing
+	Provider pgtype.Text `json:"provider"`
+}
+
+// Payment methods tokenized by the billing provider; card details are never stored beyond brand, last4 and expiry
+type PaymentMethod struct {
+	ID         pgtype.UUID `json:"id"`
+	CustomerID pgtype.UUID `json:"customer_id"`
+	Provider   string      `json:"provider"`
+	// Payment method ID at the billing provider, used to charge it
+	ProviderPaymentMethodID string      `json:"provider_payment_method_id"`
+	Type                    string      `json:"type"`
+	Brand                   pgtype.Text `json:"brand"`
+	Last4                   pgtype.Text `json:"last4"`
+	ExpMonth                pgtype.Int4 `json:"exp_month"`
+	ExpYear                 pgtype.Int4 `json:"exp_year"`
+	// Method reused by checkout and dunning retries; at most one per customer
+	IsDefault bool             `json:"is_default"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
 type Plan struct {
@@ -55,6 +247,23 @@ type Plan struct {
 	Active       bool             `json:"active"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	// Current plan version; new subscriptions are pinned to it
+	Version int32 `json:"version"`
+	// When the plan was archived; archived plans are not sold but keep existing subscriptions
+	ArchivedAt pgtype.Timestamp `json:"archived_at"`
+}
+
+// Immutable revisions of plan pricing and features
+type PlanVersion struct {
+	PlanID       string           `json:"plan_id"`
+	Version      int32            `json:"version"`
+	FeatureCodes []string         `json:"feature_codes"`
+	BillingCycle pgtype.Text      `json:"billing_cycle"`
+	PriceCents   int32            `json:"price_cents"`
+	Currency     string           `json:"currency"`
+	MaxUsers     pgtype.Int4      `json:"max_users"`
+	UsageLimits  []byte           `json:"usage_limits"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
 }
 
 type PricingZone struct {
@@ -70,12 +279,26 @@ type PricingZone struct {
 	UpdatedAt               pgtype.Timestamptz `json:"updated_at"`
 }
 
+// Subscriptions paying at the end of each day and their monthly recurring revenue; rebuilt per day by the snapshot worker and cmd/revenue-report
+type RevenueSnapshot struct {
+	SnapshotDate   pgtype.Date `json:"snapshot_date"`
+	SubscriptionID pgtype.UUID `json:"subscription_id"`
+	UserID         string      `json:"user_id"`
+	PlanID         string      `json:"plan_id"`
+	// Pricing zone of the country the subscription was bought from; empty when unknown
+	PricingZone string `json:"pricing_zone"`
+	Currency    string `json:"currency"`
+	// Price paid normalized to a month: yearly prices are divided by 12
+	MrrCents  int64            `json:"mrr_cents"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
 // Stores subscription information and lifecycle state
 type Subscription struct {
 	ID       pgtype.UUID `json:"id"`
 	UserID   string      `json:"user_id"`
 	FamilyID pgtype.Text `json:"family_id"`
-	PlanID   pgtype.UUID `json:"plan_id"`
+	PlanID   string      `json:"plan_id"`
 	// Current subscription status: active, past_due, suspended, cancelled, expired
 	Status string `json:"status"`
 	// Start of current billing period
@@ -90,6 +313,27 @@ type Subscription struct {
 	Metadata               []byte             `json:"metadata"`
 	CreatedAt              pgtype.Timestamptz `json:"created_at"`
 	UpdatedAt              pgtype.Timestamptz `json:"updated_at"`
+	// Plan version the subscription was sold at (grandfathered)
+	PlanVersion pgtype.Int4 `json:"plan_version"`
+}
+
+// VAT, GST and sales tax rates applied at checkout, imported with cmd/import-tax-rates
+type TaxRate struct {
+	ID          pgtype.UUID `json:"id"`
+	CountryCode string      `json:"country_code"`
+	// State or province code; a regional rate takes precedence over the country-wide rate
+	Region string `json:"region"`
+	Name   string `json:"name"`
+	// Tax rate in percent, e.g. 20.000
+	RatePercent pgtype.Numeric `json:"rate_percent"`
+	// Whether catalog prices in this jurisdiction already include the tax
+	Inclusive bool `json:"inclusive"`
+	// Whether the tax applies to digital services; when false subscriptions are not taxed
+	DigitalServices bool `json:"digital_services"`
+	// Whether business buyers with a tax ID account for the tax themselves
+	ReverseCharge bool             `json:"reverse_charge"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
 }
 
 // Tracks resource usage for quota management
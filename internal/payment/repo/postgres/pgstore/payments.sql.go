@@ -22,14 +22,56 @@ func (q *Queries) CountPayments(ctx context.Context, db DBTX) (int64, error) {
 	return count, err
 }
 
+const CountPaymentsFiltered = `-- name: CountPaymentsFiltered :one
+SELECT COUNT(*) FROM payments
+WHERE ($1::text IS NULL OR customer_id = $1::text)
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR currency = $3::text)
+  AND ($4::text IS NULL OR payment_method = $4::text)
+  AND ($5::text IS NULL OR external_payment_id = $5::text)
+  AND ($6::timestamp IS NULL OR created_at >= $6::timestamp)
+  AND ($7::timestamp IS NULL OR created_at < $7::timestamp)
+  AND ($8::numeric IS NULL OR amount >= $8::numeric)
+  AND ($9::numeric IS NULL OR amount <= $9::numeric)
+`
+
+type CountPaymentsFilteredParams struct {
+	CustomerID        pgtype.Text      `json:"customer_id"`
+	Status            pgtype.Text      `json:"status"`
+	Currency          pgtype.Text      `json:"currency"`
+	PaymentMethod     pgtype.Text      `json:"payment_method"`
+	ExternalPaymentID pgtype.Text      `json:"external_payment_id"`
+	CreatedAfter      pgtype.Timestamp `json:"created_after"`
+	CreatedBefore     pgtype.Timestamp `json:"created_before"`
+	MinAmount         pgtype.Numeric   `json:"min_amount"`
+	MaxAmount         pgtype.Numeric   `json:"max_amount"`
+}
+
+func (q *Queries) CountPaymentsFiltered(ctx context.Context, db DBTX, arg CountPaymentsFilteredParams) (int64, error) {
+	row := db.QueryRow(ctx, CountPaymentsFiltered,
+		arg.CustomerID,
+		arg.Status,
+		arg.Currency,
+		arg.PaymentMethod,
+		arg.ExternalPaymentID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MinAmount,
+		arg.MaxAmount,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const CreatePayment = `-- name: CreatePayment :one
 INSERT INTO payments (
-    amount, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata
+    amount, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, provider
 ) VALUES (
     $1, $2, $3, $4,
     $5, $6, $7, $8,
-    $9, $10
-) RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount
+    $9, $10, $11
+) RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider
 `
 
 type CreatePaymentParams struct {
@@ -43,6 +85,7 @@ type CreatePaymentParams struct {
 	ExternalPaymentID pgtype.Text    `json:"external_payment_id"`
 	FailureReason     pgtype.Text    `json:"failure_reason"`
 	Metadata          []byte         `json:"metadata"`
+	Provider          pgtype.Text    `json:"provider"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, db DBTX, arg CreatePaymentParams) (*Payment, error) {
@@ -57,6 +100,7 @@ func (q *Queries) CreatePayment(ctx context.Context, db DBTX, arg CreatePaymentP
 		arg.ExternalPaymentID,
 		arg.FailureReason,
 		arg.Metadata,
+		arg.Provider,
 	)
 	var i Payment
 	err := row.Scan(
@@ -73,6 +117,7 @@ func (q *Queries) CreatePayment(ctx context.Context, db DBTX, arg CreatePaymentP
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Amount,
+		&i.Provider,
 	)
 	return &i, err
 }
@@ -87,7 +132,7 @@ func (q *Queries) DeletePayment(ctx context.Context, db DBTX, id pgtype.UUID) er
 }
 
 const GetPaymentByID = `-- name: GetPaymentByID :one
-SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount FROM payments WHERE id = $1
+SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider FROM payments WHERE id = $1
 `
 
 func (q *Queries) GetPaymentByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Payment, error) {
@@ -107,12 +152,13 @@ func (q *Queries) GetPaymentByID(ctx context.Context, db DBTX, id pgtype.UUID) (
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Amount,
+		&i.Provider,
 	)
 	return &i, err
 }
 
 const GetPaymentByOrderID = `-- name: GetPaymentByOrderID :one
-SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount FROM payments WHERE order_id = $1
+SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider FROM payments WHERE order_id = $1
 `
 
 func (q *Queries) GetPaymentByOrderID(ctx context.Context, db DBTX, orderID string) (*Payment, error) {
@@ -132,12 +178,13 @@ func (q *Queries) GetPaymentByOrderID(ctx context.Context, db DBTX, orderID stri
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Amount,
+		&i.Provider,
 	)
 	return &i, err
 }
 
 const GetPaymentsByCustomerID = `-- name: GetPaymentsByCustomerID :many
-SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount FROM payments 
+SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider FROM payments 
 WHERE customer_id = $1
 ORDER BY created_at DESC
 `
@@ -165,6 +212,7 @@ func (q *Queries) GetPaymentsByCustomerID(ctx context.Context, db DBTX, customer
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Amount,
+			&i.Provider,
 		); err != nil {
 			return nil, err
 		}
@@ -177,7 +225,7 @@ func (q *Queries) GetPaymentsByCustomerID(ctx context.Context, db DBTX, customer
 }
 
 const ListPayments = `-- name: ListPayments :many
-SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount FROM payments 
+SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider FROM payments 
 ORDER BY created_at DESC
 `
 
@@ -204,6 +252,113 @@ func (q *Queries) ListPayments(ctx context.Context, db DBTX) ([]*Payment, error)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Amount,
+			&i.Provider,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPaymentsFiltered = `-- name: ListPaymentsFiltered :many
+SELECT id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider FROM payments
+WHERE ($1::text IS NULL OR customer_id = $1::text)
+  AND ($2::text IS NULL OR status = $2::text)
+  AND ($3::text IS NULL OR currency = $3::text)
+  AND ($4::text IS NULL OR payment_method = $4::text)
+  AND ($5::text IS NULL OR external_payment_id = $5::text)
+  AND ($6::timestamp IS NULL OR created_at >= $6::timestamp)
+  AND ($7::timestamp IS NULL OR created_at < $7::timestamp)
+  AND ($8::numeric IS NULL OR amount >= $8::numeric)
+  AND ($9::numeric IS NULL OR amount <= $9::numeric)
+  AND (
+    $10::uuid IS NULL
+    OR ($11::text = 'created_at' AND $12::boolean
+        AND (created_at, id) < ($13::timestamp, $10::uuid))
+    OR ($11::text = 'created_at' AND NOT $12::boolean
+        AND (created_at, id) > ($13::timestamp, $10::uuid))
+    OR ($11::text = 'amount' AND $12::boolean
+        AND (amount, id) < ($14::numeric, $10::uuid))
+    OR ($11::text = 'amount' AND NOT $12::boolean
+        AND (amount, id) > ($14::numeric, $10::uuid))
+  )
+ORDER BY
+  CASE WHEN $11::text = 'created_at' AND NOT $12::boolean THEN created_at END ASC,
+  CASE WHEN $11::text = 'created_at' AND $12::boolean THEN created_at END DESC,
+  CASE WHEN $11::text = 'amount' AND NOT $12::boolean THEN amount END ASC,
+  CASE WHEN $11::text = 'amount' AND $12::boolean THEN amount END DESC,
+  CASE WHEN NOT $12::boolean THEN id END ASC,
+  CASE WHEN $12::boolean THEN id END DESC
+LIMIT $16 OFFSET $15
+`
+
+type ListPaymentsFilteredParams struct {
+	CustomerID        pgtype.Text      `json:"customer_id"`
+	Status            pgtype.Text      `json:"status"`
+	Currency          pgtype.Text      `json:"currency"`
+	PaymentMethod     pgtype.Text      `json:"payment_method"`
+	ExternalPaymentID pgtype.Text      `json:"external_payment_id"`
+	CreatedAfter      pgtype.Timestamp `json:"created_after"`
+	CreatedBefore     pgtype.Timestamp `json:"created_before"`
+	MinAmount         pgtype.Numeric   `json:"min_amount"`
+	MaxAmount         pgtype.Numeric   `json:"max_amount"`
+	CursorID          pgtype.UUID      `json:"cursor_id"`
+	SortBy            string           `json:"sort_by"`
+	SortDesc          bool             `json:"sort_desc"`
+	CursorCreatedAt   pgtype.Timestamp `json:"cursor_created_at"`
+	CursorAmount      pgtype.Numeric   `json:"cursor_amount"`
+	PageOffset        int32            `json:"page_offset"`
+	PageLimit         int32            `json:"page_limit"`
+}
+
+// Keyset pagination: when a cursor is supplied only rows strictly after the
+// (sort key, id) pair of the last row on the previous page are returned, so
+// concurrent inserts never shift pages the way OFFSET does.
+func (q *Queries) ListPaymentsFiltered(ctx context.Context, db DBTX, arg ListPaymentsFilteredParams) ([]*Payment, error) {
+	rows, err := db.Query(ctx, ListPaymentsFiltered,
+		arg.CustomerID,
+		arg.Status,
+		arg.Currency,
+		arg.PaymentMethod,
+		arg.ExternalPaymentID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CursorID,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.CursorCreatedAt,
+		arg.CursorAmount,
+		arg.PageOffset,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Status,
+			&i.PaymentMethod,
+			&i.CustomerID,
+			&i.OrderID,
+			&i.Description,
+			&i.ExternalPaymentID,
+			&i.FailureReason,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Amount,
+			&i.Provider,
 		); err != nil {
 			return nil, err
 		}
@@ -229,7 +384,7 @@ SET amount = $1,
     metadata = $10,
     updated_at = NOW()
 WHERE id = $11
-RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount
+RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider
 `
 
 type UpdatePaymentParams struct {
@@ -275,6 +430,7 @@ func (q *Queries) UpdatePayment(ctx context.Context, db DBTX, arg UpdatePaymentP
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Amount,
+		&i.Provider,
 	)
 	return &i, err
 }
@@ -285,7 +441,7 @@ SET status = $1,
     failure_reason = $2,
     updated_at = NOW()
 WHERE id = $3
-RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount
+RETURNING id, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, created_at, updated_at, amount, provider
 `
 
 type UpdatePaymentStatusParams struct {
@@ -311,6 +467,7 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, db DBTX, arg UpdatePa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Amount,
+		&i.Provider,
 	)
 	return &i, err
 }
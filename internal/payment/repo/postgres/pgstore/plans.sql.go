@@ -11,8 +11,40 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const ArchivePlan = `-- name: ArchivePlan :one
+UPDATE plans
+SET active = false,
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at
+`
+
+func (q *Queries) ArchivePlan(ctx context.Context, db DBTX, id string) (*Plan, error) {
+	row := db.QueryRow(ctx, ArchivePlan, id)
+	var i Plan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.Metadata,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
 const GetPlanByID = `-- name: GetPlanByID :one
-SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at FROM plans 
+SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at FROM plans 
 WHERE id = $1 AND active = true
 `
 
@@ -33,6 +65,104 @@ func (q *Queries) GetPlanByID(ctx context.Context, db DBTX, id string) (*Plan, e
 		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
+const GetPlanByIDForUpdate = `-- name: GetPlanByIDForUpdate :one
+SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at FROM plans
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPlanByIDForUpdate(ctx context.Context, db DBTX, id string) (*Plan, error) {
+	row := db.QueryRow(ctx, GetPlanByIDForUpdate, id)
+	var i Plan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.Metadata,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
+const GetPlanByIDIncludingArchived = `-- name: GetPlanByIDIncludingArchived :one
+SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at FROM plans
+WHERE id = $1
+`
+
+func (q *Queries) GetPlanByIDIncludingArchived(ctx context.Context, db DBTX, id string) (*Plan, error) {
+	row := db.QueryRow(ctx, GetPlanByIDIncludingArchived, id)
+	var i Plan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.Metadata,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
+const GetPlanIDByUUID = `-- name: GetPlanIDByUUID :one
+SELECT id FROM plans
+WHERE plan_uuid(id) = $1::uuid
+`
+
+func (q *Queries) GetPlanIDByUUID(ctx context.Context, db DBTX, planUuid pgtype.UUID) (string, error) {
+	row := db.QueryRow(ctx, GetPlanIDByUUID, planUuid)
+	var id string
+	err := row.Scan(&id)
+	return id, err
+}
+
+const GetPlanVersion = `-- name: GetPlanVersion :one
+SELECT plan_id, version, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, created_at FROM plan_versions
+WHERE plan_id = $1 AND version = $2
+`
+
+type GetPlanVersionParams struct {
+	PlanID  string `json:"plan_id"`
+	Version int32  `json:"version"`
+}
+
+func (q *Queries) GetPlanVersion(ctx context.Context, db DBTX, arg GetPlanVersionParams) (*PlanVersion, error) {
+	row := db.QueryRow(ctx, GetPlanVersion, arg.PlanID, arg.Version)
+	var i PlanVersion
+	err := row.Scan(
+		&i.PlanID,
+		&i.Version,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.CreatedAt,
 	)
 	return &i, err
 }
@@ -46,7 +176,7 @@ INSERT INTO plans (
     $4, $5,
     $6, $7, $8,
     $9, $10, $11
-) RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at
+) RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at
 `
 
 type InsertPlanParams struct {
@@ -92,12 +222,61 @@ func (q *Queries) InsertPlan(ctx context.Context, db DBTX, arg InsertPlanParams)
 		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
+const InsertPlanVersion = `-- name: InsertPlanVersion :one
+INSERT INTO plan_versions (
+    plan_id, version, feature_codes, billing_cycle,
+    price_cents, currency, max_users, usage_limits
+) VALUES (
+    $1, $2, $3, $4,
+    $5, $6, $7, $8
+) RETURNING plan_id, version, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, created_at
+`
+
+type InsertPlanVersionParams struct {
+	PlanID       string      `json:"plan_id"`
+	Version      int32       `json:"version"`
+	FeatureCodes []string    `json:"feature_codes"`
+	BillingCycle pgtype.Text `json:"billing_cycle"`
+	PriceCents   int32       `json:"price_cents"`
+	Currency     string      `json:"currency"`
+	MaxUsers     pgtype.Int4 `json:"max_users"`
+	UsageLimits  []byte      `json:"usage_limits"`
+}
+
+func (q *Queries) InsertPlanVersion(ctx context.Context, db DBTX, arg InsertPlanVersionParams) (*PlanVersion, error) {
+	row := db.QueryRow(ctx, InsertPlanVersion,
+		arg.PlanID,
+		arg.Version,
+		arg.FeatureCodes,
+		arg.BillingCycle,
+		arg.PriceCents,
+		arg.Currency,
+		arg.MaxUsers,
+		arg.UsageLimits,
+	)
+	var i PlanVersion
+	err := row.Scan(
+		&i.PlanID,
+		&i.Version,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.CreatedAt,
 	)
 	return &i, err
 }
 
 const ListActivePlans = `-- name: ListActivePlans :many
-SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at FROM plans 
+SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at FROM plans 
 WHERE active = true 
 ORDER BY created_at DESC
 `
@@ -125,6 +304,8 @@ func (q *Queries) ListActivePlans(ctx context.Context, db DBTX) ([]*Plan, error)
 			&i.Active,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -136,11 +317,155 @@ func (q *Queries) ListActivePlans(ctx context.Context, db DBTX) ([]*Plan, error)
 	return items, nil
 }
 
+const ListPlanVersions = `-- name: ListPlanVersions :many
+SELECT plan_id, version, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, created_at FROM plan_versions
+WHERE plan_id = $1
+ORDER BY version DESC
+`
+
+func (q *Queries) ListPlanVersions(ctx context.Context, db DBTX, planID string) ([]*PlanVersion, error) {
+	rows, err := db.Query(ctx, ListPlanVersions, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*PlanVersion{}
+	for rows.Next() {
+		var i PlanVersion
+		if err := rows.Scan(
+			&i.PlanID,
+			&i.Version,
+			&i.FeatureCodes,
+			&i.BillingCycle,
+			&i.PriceCents,
+			&i.Currency,
+			&i.MaxUsers,
+			&i.UsageLimits,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListPlans = `-- name: ListPlans :many
+SELECT id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at FROM plans
+WHERE $1::bool OR archived_at IS NULL
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPlans(ctx context.Context, db DBTX, includeArchived bool) ([]*Plan, error) {
+	rows, err := db.Query(ctx, ListPlans, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Plan{}
+	for rows.Next() {
+		var i Plan
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.FeatureCodes,
+			&i.BillingCycle,
+			&i.PriceCents,
+			&i.Currency,
+			&i.MaxUsers,
+			&i.UsageLimits,
+			&i.Metadata,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Version,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const UpdatePlan = `-- name: UpdatePlan :one
+UPDATE plans
+SET name = $1,
+    description = $2,
+    feature_codes = $3,
+    billing_cycle = $4,
+    price_cents = $5,
+    currency = $6,
+    max_users = $7,
+    usage_limits = $8,
+    metadata = $9,
+    version = $10,
+    updated_at = NOW()
+WHERE id = $11
+RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at
+`
+
+type UpdatePlanParams struct {
+	Name         string      `json:"name"`
+	Description  pgtype.Text `json:"description"`
+	FeatureCodes []string    `json:"feature_codes"`
+	BillingCycle pgtype.Text `json:"billing_cycle"`
+	PriceCents   int32       `json:"price_cents"`
+	Currency     string      `json:"currency"`
+	MaxUsers     pgtype.Int4 `json:"max_users"`
+	UsageLimits  []byte      `json:"usage_limits"`
+	Metadata     []byte      `json:"metadata"`
+	Version      int32       `json:"version"`
+	ID           string      `json:"id"`
+}
+
+func (q *Queries) UpdatePlan(ctx context.Context, db DBTX, arg UpdatePlanParams) (*Plan, error) {
+	row := db.QueryRow(ctx, UpdatePlan,
+		arg.Name,
+		arg.Description,
+		arg.FeatureCodes,
+		arg.BillingCycle,
+		arg.PriceCents,
+		arg.Currency,
+		arg.MaxUsers,
+		arg.UsageLimits,
+		arg.Metadata,
+		arg.Version,
+		arg.ID,
+	)
+	var i Plan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.FeatureCodes,
+		&i.BillingCycle,
+		&i.PriceCents,
+		&i.Currency,
+		&i.MaxUsers,
+		&i.UsageLimits,
+		&i.Metadata,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
+	)
+	return &i, err
+}
+
 const UpdatePlanActive = `-- name: UpdatePlanActive :one
 UPDATE plans 
 SET active = $1, updated_at = NOW()
 WHERE id = $2
-RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at
+RETURNING id, name, description, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, metadata, active, created_at, updated_at, version, archived_at
 `
 
 type UpdatePlanActiveParams struct {
@@ -165,6 +490,8 @@ func (q *Queries) UpdatePlanActive(ctx context.Context, db DBTX, arg UpdatePlanA
 		&i.Active,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Version,
+		&i.ArchivedAt,
 	)
 	return &i, err
 }
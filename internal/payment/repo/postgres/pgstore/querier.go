@@ -11,54 +11,143 @@ import (
 )
 
 type Querier interface {
+	ArchivePlan(ctx context.Context, db DBTX, id string) (*Plan, error)
 	CheckEntitlement(ctx context.Context, db DBTX, arg CheckEntitlementParams) (*Entitlement, error)
+	ClearDefaultPaymentMethod(ctx context.Context, db DBTX, customerID pgtype.UUID) error
+	// Only one delivery of a completion webhook claims the session (no row is
+	// returned to the others). Sessions closed without payment can be claimed,
+	// as the customer paid anyway, and so can a claim left behind by a delivery
+	// that died before finishing.
+	ClaimCheckoutSessionForCompletion(ctx context.Context, db DBTX, arg ClaimCheckoutSessionForCompletionParams) (*CheckoutSession, error)
+	// Only open sessions are closed, so a session completed by its webhook in
+	// the meantime is left alone (no row is returned)
+	CloseOpenCheckoutSession(ctx context.Context, db DBTX, arg CloseOpenCheckoutSessionParams) (*CheckoutSession, error)
 	CountPayments(ctx context.Context, db DBTX) (int64, error)
+	CountPaymentsFiltered(ctx context.Context, db DBTX, arg CountPaymentsFilteredParams) (int64, error)
 	CountPricingZones(ctx context.Context, db DBTX) (int64, error)
+	CreateCheckoutSession(ctx context.Context, db DBTX, arg CreateCheckoutSessionParams) (*CheckoutSession, error)
+	CreateInvoice(ctx context.Context, db DBTX, arg CreateInvoiceParams) (*Invoice, error)
 	CreatePayment(ctx context.Context, db DBTX, arg CreatePaymentParams) (*Payment, error)
+	CreatePaymentMethod(ctx context.Context, db DBTX, arg CreatePaymentMethodParams) (*PaymentMethod, error)
 	CreateSubscription(ctx context.Context, db DBTX, arg CreateSubscriptionParams) (*Subscription, error)
 	CreateUsage(ctx context.Context, db DBTX, arg CreateUsageParams) error
 	DeletePayment(ctx context.Context, db DBTX, id pgtype.UUID) error
+	DeletePaymentMethod(ctx context.Context, db DBTX, id pgtype.UUID) error
 	DeletePricingZone(ctx context.Context, db DBTX, isoCode string) error
+	DeleteRevenueSnapshot(ctx context.Context, db DBTX, snapshotDate pgtype.Date) error
 	DeleteSubscription(ctx context.Context, db DBTX, id pgtype.UUID) error
+	DeleteTaxRate(ctx context.Context, db DBTX, arg DeleteTaxRateParams) error
 	DeleteUsage(ctx context.Context, db DBTX, arg DeleteUsageParams) error
 	GetActiveSubscriptions(ctx context.Context, db DBTX) ([]*Subscription, error)
+	GetCheckoutSessionByID(ctx context.Context, db DBTX, id pgtype.UUID) (*CheckoutSession, error)
+	GetCheckoutSessionByProviderSessionID(ctx context.Context, db DBTX, arg GetCheckoutSessionByProviderSessionIDParams) (*CheckoutSession, error)
 	GetCurrentUsage(ctx context.Context, db DBTX, arg GetCurrentUsageParams) (interface{}, error)
+	GetCustomerByUserID(ctx context.Context, db DBTX, userID string) (*Customer, error)
+	GetDefaultPaymentMethod(ctx context.Context, db DBTX, customerID pgtype.UUID) (*PaymentMethod, error)
 	GetEntitlementByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Entitlement, error)
 	GetEntitlementsBySubscriptionID(ctx context.Context, db DBTX, subscriptionID pgtype.Text) ([]*Entitlement, error)
 	GetExpiringSubscriptions(ctx context.Context, db DBTX, beforeDate pgtype.Timestamptz) ([]*Subscription, error)
+	GetFeature(ctx context.Context, db DBTX, code string) (*Feature, error)
+	GetInvoiceByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Invoice, error)
+	GetInvoiceByNumber(ctx context.Context, db DBTX, number string) (*Invoice, error)
+	GetInvoiceByPaymentID(ctx context.Context, db DBTX, paymentID pgtype.UUID) (*Invoice, error)
+	GetJournalEntryByReference(ctx context.Context, db DBTX, reference string) (*JournalEntry, error)
+	// The most recent snapshot taken on or before a day; NULL when there is none
+	GetLatestRevenueSnapshotDate(ctx context.Context, db DBTX, onOrBefore pgtype.Date) (pgtype.Date, error)
+	// Debit and credit totals per account and currency, optionally limited to
+	// one customer or one account
+	GetLedgerBalances(ctx context.Context, db DBTX, arg GetLedgerBalancesParams) ([]*GetLedgerBalancesRow, error)
 	GetPaymentByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Payment, error)
 	GetPaymentByOrderID(ctx context.Context, db DBTX, orderID string) (*Payment, error)
+	GetPaymentMethodByID(ctx context.Context, db DBTX, id pgtype.UUID) (*PaymentMethod, error)
 	GetPaymentsByCustomerID(ctx context.Context, db DBTX, customerID string) ([]*Payment, error)
 	GetPlanByID(ctx context.Context, db DBTX, id string) (*Plan, error)
+	GetPlanByIDForUpdate(ctx context.Context, db DBTX, id string) (*Plan, error)
+	GetPlanByIDIncludingArchived(ctx context.Context, db DBTX, id string) (*Plan, error)
+	GetPlanIDByUUID(ctx context.Context, db DBTX, planUuid pgtype.UUID) (string, error)
+	GetPlanVersion(ctx context.Context, db DBTX, arg GetPlanVersionParams) (*PlanVersion, error)
 	GetPricingZoneByCountry(ctx context.Context, db DBTX, lower string) (*PricingZone, error)
 	GetPricingZoneByISOCode(ctx context.Context, db DBTX, isoCode string) (*PricingZone, error)
 	GetPricingZonesByZone(ctx context.Context, db DBTX, zone string) ([]*PricingZone, error)
 	GetSubscriptionByExternalID(ctx context.Context, db DBTX, externalID pgtype.Text) (*Subscription, error)
 	GetSubscriptionByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Subscription, error)
-	GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID pgtype.UUID) ([]*Subscription, error)
+	GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID string) ([]*Subscription, error)
 	GetSubscriptionsByStatus(ctx context.Context, db DBTX, status string) ([]*Subscription, error)
 	GetSubscriptionsByUserID(ctx context.Context, db DBTX, userID string) ([]*Subscription, error)
+	// Prefers the regional rate and falls back to the country-wide rate
+	GetTaxRate(ctx context.Context, db DBTX, arg GetTaxRateParams) (*TaxRate, error)
 	GetUsageByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Usage, error)
 	GetUsageHistory(ctx context.Context, db DBTX, arg GetUsageHistoryParams) ([]*Usage, error)
 	GetUsageStats(ctx context.Context, db DBTX, arg GetUsageStatsParams) ([]*GetUsageStatsRow, error)
+	InsertAuditEvent(ctx context.Context, db DBTX, arg InsertAuditEventParams) (*AuditEvent, error)
 	InsertEntitlement(ctx context.Context, db DBTX, arg InsertEntitlementParams) (*Entitlement, error)
+	InsertFeature(ctx context.Context, db DBTX, arg InsertFeatureParams) (*Feature, error)
+	InsertInvoiceLineItem(ctx context.Context, db DBTX, arg InsertInvoiceLineItemParams) error
+	// Returns no row when an entry with the same reference was already posted
+	InsertJournalEntry(ctx context.Context, db DBTX, arg InsertJournalEntryParams) (*JournalEntry, error)
+	InsertLedgerPosting(ctx context.Context, db DBTX, arg InsertLedgerPostingParams) (*LedgerPosting, error)
 	InsertPlan(ctx context.Context, db DBTX, arg InsertPlanParams) (*Plan, error)
+	InsertPlanVersion(ctx context.Context, db DBTX, arg InsertPlanVersionParams) (*PlanVersion, error)
+	// Records every subscription paying at as_of (the end of snapshot_date).
+	// Subscriptions are priced at their grandfathered plan version and, when
+	// bought through a checkout, at the zone-adjusted price quoted there.
+	// Status history is not kept, so for past days a subscription counts until
+	// it was cancelled, its period ended or, when suspended, it was last
+	// updated.
+	InsertRevenueSnapshot(ctx context.Context, db DBTX, arg InsertRevenueSnapshotParams) (int64, error)
 	ListActivePlans(ctx context.Context, db DBTX) ([]*Plan, error)
+	// Newest first by (occurred_at, id); the cursor is the last event of the
+	// previous page
+	ListAuditEvents(ctx context.Context, db DBTX, arg ListAuditEventsParams) ([]*AuditEvent, error)
+	ListCheckoutSessionsByProviderSessionID(ctx context.Context, db DBTX, providerSessionID string) ([]*CheckoutSession, error)
+	ListEntitlementsAfter(ctx context.Context, db DBTX, arg ListEntitlementsAfterParams) ([]*Entitlement, error)
 	ListEntitlementsByUser(ctx context.Context, db DBTX, userID string) ([]*Entitlement, error)
+	ListExpiredCheckoutSessions(ctx context.Context, db DBTX, arg ListExpiredCheckoutSessionsParams) ([]*CheckoutSession, error)
 	ListExpiringEntitlements(ctx context.Context, db DBTX) ([]*Entitlement, error)
+	ListFeatures(ctx context.Context, db DBTX) ([]*Feature, error)
+	ListInvoiceLineItems(ctx context.Context, db DBTX, invoiceIds []pgtype.UUID) ([]*InvoiceLineItem, error)
+	// Newest first by (year, sequence); the cursor is the last invoice of the
+	// previous page
+	ListInvoices(ctx context.Context, db DBTX, arg ListInvoicesParams) ([]*Invoice, error)
+	ListJournalEntriesByPayment(ctx context.Context, db DBTX, paymentID pgtype.UUID) ([]*JournalEntry, error)
+	ListLedgerPostingsByEntries(ctx context.Context, db DBTX, entryIds []pgtype.UUID) ([]*LedgerPosting, error)
+	ListPaymentMethodsByCustomer(ctx context.Context, db DBTX, customerID pgtype.UUID) ([]*PaymentMethod, error)
 	ListPayments(ctx context.Context, db DBTX) ([]*Payment, error)
+	// Keyset pagination: when a cursor is supplied only rows strictly after the
+	// (sort key, id) pair of the last row on the previous page are returned, so
+	// concurrent inserts never shift pages the way OFFSET does.
+	ListPaymentsFiltered(ctx context.Context, db DBTX, arg ListPaymentsFilteredParams) ([]*Payment, error)
+	ListPlanVersions(ctx context.Context, db DBTX, planID string) ([]*PlanVersion, error)
+	ListPlans(ctx context.Context, db DBTX, includeArchived bool) ([]*Plan, error)
 	ListPricingZones(ctx context.Context, db DBTX) ([]*PricingZone, error)
+	ListRevenueSnapshot(ctx context.Context, db DBTX, snapshotDate pgtype.Date) ([]*RevenueSnapshot, error)
+	ListSubscriptionsCreatedBetween(ctx context.Context, db DBTX, arg ListSubscriptionsCreatedBetweenParams) ([]*Subscription, error)
+	ListTaxRates(ctx context.Context, db DBTX) ([]*TaxRate, error)
+	// Entries whose postings do not balance in some currency; the trial
+	// balance check expects none
+	ListUnbalancedJournalEntries(ctx context.Context, db DBTX) ([]*ListUnbalancedJournalEntriesRow, error)
 	ListUsageByUser(ctx context.Context, db DBTX, arg ListUsageByUserParams) ([]*Usage, error)
+	// Must run in the transaction that inserts the invoice; the row lock taken
+	// by the upsert serialises numbering within a year
+	NextInvoiceSequence(ctx context.Context, db DBTX, year int32) (int32, error)
 	RenewSubscription(ctx context.Context, db DBTX, arg RenewSubscriptionParams) (*Subscription, error)
+	SetCustomerProviderID(ctx context.Context, db DBTX, arg SetCustomerProviderIDParams) (*Customer, error)
+	SetDefaultPaymentMethod(ctx context.Context, db DBTX, arg SetDefaultPaymentMethodParams) (*PaymentMethod, error)
+	// Sets completed_at the first time a session transitions to complete
+	UpdateCheckoutSessionStatus(ctx context.Context, db DBTX, arg UpdateCheckoutSessionStatusParams) (*CheckoutSession, error)
 	UpdateEntitlement(ctx context.Context, db DBTX, arg UpdateEntitlementParams) (*Entitlement, error)
 	UpdateEntitlementExpiry(ctx context.Context, db DBTX, arg UpdateEntitlementExpiryParams) (*Entitlement, error)
 	UpdateEntitlementStatus(ctx context.Context, db DBTX, arg UpdateEntitlementStatusParams) (*Entitlement, error)
+	UpdateFeature(ctx context.Context, db DBTX, arg UpdateFeatureParams) (*Feature, error)
 	UpdatePayment(ctx context.Context, db DBTX, arg UpdatePaymentParams) (*Payment, error)
 	UpdatePaymentStatus(ctx context.Context, db DBTX, arg UpdatePaymentStatusParams) (*Payment, error)
+	UpdatePlan(ctx context.Context, db DBTX, arg UpdatePlanParams) (*Plan, error)
 	UpdatePlanActive(ctx context.Context, db DBTX, arg UpdatePlanActiveParams) (*Plan, error)
 	UpdateSubscription(ctx context.Context, db DBTX, arg UpdateSubscriptionParams) (*Subscription, error)
 	UpdateSubscriptionStatus(ctx context.Context, db DBTX, arg UpdateSubscriptionStatusParams) (*Subscription, error)
+	UpsertCustomer(ctx context.Context, db DBTX, arg UpsertCustomerParams) (*Customer, error)
 	UpsertPricingZone(ctx context.Context, db DBTX, arg UpsertPricingZoneParams) (*PricingZone, error)
+	UpsertTaxRate(ctx context.Context, db DBTX, arg UpsertTaxRateParams) (*TaxRate, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -13,19 +13,20 @@ import (
 
 const CreateSubscription = `-- name: CreateSubscription :one
 INSERT INTO subscriptions (
-    user_id, family_id, plan_id, status, current_period_start, 
+    user_id, family_id, plan_id, plan_version, status, current_period_start, 
     current_period_end, cancel_at_period_end, external_subscription_id, metadata
 ) VALUES (
-    $1, $2, $3, 
-    $4, $5, $6,
-    $7, $8, $9
-) RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at
+    $1, $2, $3, $4,
+    $5, $6, $7,
+    $8, $9, $10
+) RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version
 `
 
 type CreateSubscriptionParams struct {
 	UserID                 string             `json:"user_id"`
 	FamilyID               pgtype.Text        `json:"family_id"`
-	PlanID                 pgtype.UUID        `json:"plan_id"`
+	PlanID                 string             `json:"plan_id"`
+	PlanVersion            pgtype.Int4        `json:"plan_version"`
 	Status                 string             `json:"status"`
 	CurrentPeriodStart     pgtype.Timestamptz `json:"current_period_start"`
 	CurrentPeriodEnd       pgtype.Timestamptz `json:"current_period_end"`
@@ -39,6 +40,7 @@ func (q *Queries) CreateSubscription(ctx context.Context, db DBTX, arg CreateSub
 		arg.UserID,
 		arg.FamilyID,
 		arg.PlanID,
+		arg.PlanVersion,
 		arg.Status,
 		arg.CurrentPeriodStart,
 		arg.CurrentPeriodEnd,
@@ -61,6 +63,7 @@ func (q *Queries) CreateSubscription(ctx context.Context, db DBTX, arg CreateSub
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
@@ -75,7 +78,7 @@ func (q *Queries) DeleteSubscription(ctx context.Context, db DBTX, id pgtype.UUI
 }
 
 const GetActiveSubscriptions = `-- name: GetActiveSubscriptions :many
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE status = 'active' ORDER BY created_at DESC
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE status = 'active' ORDER BY created_at DESC
 `
 
 func (q *Queries) GetActiveSubscriptions(ctx context.Context, db DBTX) ([]*Subscription, error) {
@@ -101,6 +104,7 @@ func (q *Queries) GetActiveSubscriptions(ctx context.Context, db DBTX) ([]*Subsc
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PlanVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -113,7 +117,7 @@ func (q *Queries) GetActiveSubscriptions(ctx context.Context, db DBTX) ([]*Subsc
 }
 
 const GetExpiringSubscriptions = `-- name: GetExpiringSubscriptions :many
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions 
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions 
 WHERE current_period_end <= $1 
   AND status = 'active'
 ORDER BY current_period_end ASC
@@ -142,6 +146,7 @@ func (q *Queries) GetExpiringSubscriptions(ctx context.Context, db DBTX, beforeD
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PlanVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -154,7 +159,7 @@ func (q *Queries) GetExpiringSubscriptions(ctx context.Context, db DBTX, beforeD
 }
 
 const GetSubscriptionByExternalID = `-- name: GetSubscriptionByExternalID :one
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE external_subscription_id = $1
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE external_subscription_id = $1
 `
 
 func (q *Queries) GetSubscriptionByExternalID(ctx context.Context, db DBTX, externalID pgtype.Text) (*Subscription, error) {
@@ -174,12 +179,13 @@ func (q *Queries) GetSubscriptionByExternalID(ctx context.Context, db DBTX, exte
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
 
 const GetSubscriptionByID = `-- name: GetSubscriptionByID :one
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE id = $1
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE id = $1
 `
 
 func (q *Queries) GetSubscriptionByID(ctx context.Context, db DBTX, id pgtype.UUID) (*Subscription, error) {
@@ -199,15 +205,16 @@ func (q *Queries) GetSubscriptionByID(ctx context.Context, db DBTX, id pgtype.UU
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
 
 const GetSubscriptionsByPlan = `-- name: GetSubscriptionsByPlan :many
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE plan_id = $1 ORDER BY created_at DESC
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE plan_id = $1 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID pgtype.UUID) ([]*Subscription, error) {
+func (q *Queries) GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID string) ([]*Subscription, error) {
 	rows, err := db.Query(ctx, GetSubscriptionsByPlan, planID)
 	if err != nil {
 		return nil, err
@@ -230,6 +237,7 @@ func (q *Queries) GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID pg
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PlanVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -242,7 +250,7 @@ func (q *Queries) GetSubscriptionsByPlan(ctx context.Context, db DBTX, planID pg
 }
 
 const GetSubscriptionsByStatus = `-- name: GetSubscriptionsByStatus :many
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE status = $1 ORDER BY created_at DESC
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE status = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetSubscriptionsByStatus(ctx context.Context, db DBTX, status string) ([]*Subscription, error) {
@@ -268,6 +276,7 @@ func (q *Queries) GetSubscriptionsByStatus(ctx context.Context, db DBTX, status
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PlanVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -280,7 +289,7 @@ func (q *Queries) GetSubscriptionsByStatus(ctx context.Context, db DBTX, status
 }
 
 const GetSubscriptionsByUserID = `-- name: GetSubscriptionsByUserID :many
-SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at FROM subscriptions WHERE user_id = $1 ORDER BY created_at DESC
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions WHERE user_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetSubscriptionsByUserID(ctx context.Context, db DBTX, userID string) ([]*Subscription, error) {
@@ -306,6 +315,53 @@ func (q *Queries) GetSubscriptionsByUserID(ctx context.Context, db DBTX, userID
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.PlanVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListSubscriptionsCreatedBetween = `-- name: ListSubscriptionsCreatedBetween :many
+SELECT id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version FROM subscriptions
+WHERE created_at >= $1 AND created_at < $2
+ORDER BY created_at, id
+`
+
+type ListSubscriptionsCreatedBetweenParams struct {
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+}
+
+func (q *Queries) ListSubscriptionsCreatedBetween(ctx context.Context, db DBTX, arg ListSubscriptionsCreatedBetweenParams) ([]*Subscription, error) {
+	rows, err := db.Query(ctx, ListSubscriptionsCreatedBetween, arg.CreatedAfter, arg.CreatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*Subscription{}
+	for rows.Next() {
+		var i Subscription
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FamilyID,
+			&i.PlanID,
+			&i.Status,
+			&i.CurrentPeriodStart,
+			&i.CurrentPeriodEnd,
+			&i.CancelAtPeriodEnd,
+			&i.CancelledAt,
+			&i.ExternalSubscriptionID,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PlanVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -323,7 +379,7 @@ UPDATE subscriptions SET
     current_period_end = $2,
     updated_at = NOW()
 WHERE id = $3
-RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version
 `
 
 type RenewSubscriptionParams struct {
@@ -349,6 +405,7 @@ func (q *Queries) RenewSubscription(ctx context.Context, db DBTX, arg RenewSubsc
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
@@ -363,7 +420,7 @@ UPDATE subscriptions SET
     metadata = $6,
     updated_at = NOW()
 WHERE id = $7
-RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version
 `
 
 type UpdateSubscriptionParams struct {
@@ -401,6 +458,7 @@ func (q *Queries) UpdateSubscription(ctx context.Context, db DBTX, arg UpdateSub
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
@@ -411,7 +469,7 @@ UPDATE subscriptions SET
     cancelled_at = $2,
     updated_at = NOW()
 WHERE id = $3
-RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at
+RETURNING id, user_id, family_id, plan_id, status, current_period_start, current_period_end, cancel_at_period_end, cancelled_at, external_subscription_id, metadata, created_at, updated_at, plan_version
 `
 
 type UpdateSubscriptionStatusParams struct {
@@ -437,6 +495,7 @@ func (q *Queries) UpdateSubscriptionStatus(ctx context.Context, db DBTX, arg Upd
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.PlanVersion,
 	)
 	return &i, err
 }
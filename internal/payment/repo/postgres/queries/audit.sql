@@ -0,0 +1,19 @@
+-- name: InsertAuditEvent :one
+INSERT INTO audit_events (
+    entity_type, entity_id, action, actor_type, actor_id, changes, request_id, reason, occurred_at
+) VALUES (
+    sqlc.arg(entity_type), sqlc.arg(entity_id), sqlc.arg(action), sqlc.arg(actor_type), sqlc.arg(actor_id),
+    sqlc.arg(changes), sqlc.arg(request_id), sqlc.arg(reason), sqlc.arg(occurred_at)
+) RETURNING *;
+
+-- name: ListAuditEvents :many
+-- Newest first by (occurred_at, id); the cursor is the last event of the
+-- previous page
+SELECT * FROM audit_events
+WHERE (sqlc.narg(entity_type)::text IS NULL OR entity_type = sqlc.narg(entity_type)::text)
+  AND (sqlc.narg(entity_id)::text IS NULL OR entity_id = sqlc.narg(entity_id)::text)
+  AND (sqlc.narg(actor_id)::text IS NULL OR actor_id = sqlc.narg(actor_id)::text)
+  AND (sqlc.narg(cursor_occurred_at)::timestamp IS NULL
+       OR (occurred_at, id) < (sqlc.narg(cursor_occurred_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+ORDER BY occurred_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
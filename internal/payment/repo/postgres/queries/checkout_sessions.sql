@@ -0,0 +1,67 @@
+-- name: CreateCheckoutSession :one
+INSERT INTO checkout_sessions (
+    provider, provider_session_id, plan_id, plan_version, user_id, family_id, country_code, region,
+    base_price_cents, quoted_price_cents, pricing_multiplier, currency,
+    tax_id, tax_name, tax_rate_percent, tax_inclusive, reverse_charge, tax_cents, total_cents,
+    status, url, expires_at
+) VALUES (
+    sqlc.arg(provider), sqlc.arg(provider_session_id), sqlc.arg(plan_id), sqlc.arg(plan_version),
+    sqlc.arg(user_id), sqlc.narg(family_id), sqlc.narg(country_code), sqlc.narg(region),
+    sqlc.arg(base_price_cents), sqlc.arg(quoted_price_cents), sqlc.arg(pricing_multiplier), sqlc.arg(currency),
+    sqlc.narg(tax_id), sqlc.narg(tax_name), sqlc.arg(tax_rate_percent), sqlc.arg(tax_inclusive),
+    sqlc.arg(reverse_charge), sqlc.arg(tax_cents), sqlc.arg(total_cents),
+    sqlc.arg(status), sqlc.narg(url), sqlc.arg(expires_at)
+) RETURNING *;
+
+-- name: GetCheckoutSessionByID :one
+SELECT * FROM checkout_sessions WHERE id = sqlc.arg(id);
+
+-- name: GetCheckoutSessionByProviderSessionID :one
+SELECT * FROM checkout_sessions WHERE provider = sqlc.arg(provider) AND provider_session_id = sqlc.arg(provider_session_id);
+
+-- name: ListCheckoutSessionsByProviderSessionID :many
+-- Session IDs are only unique per provider, so one may be recorded for
+-- several providers
+SELECT * FROM checkout_sessions
+WHERE provider_session_id = sqlc.arg(provider_session_id)
+ORDER BY created_at;
+
+-- name: UpdateCheckoutSessionStatus :one
+-- Sets completed_at the first time a session transitions to complete
+UPDATE checkout_sessions
+SET status = sqlc.arg(status),
+    completed_at = CASE
+        WHEN sqlc.arg(status) = 'complete' THEN COALESCE(completed_at, NOW())
+        ELSE completed_at
+    END,
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ClaimCheckoutSessionForCompletion :one
+-- Only one delivery of a completion webhook claims the session (no row is
+-- returned to the others). Sessions closed without payment can be claimed,
+-- as the customer paid anyway, and so can a claim left behind by a delivery
+-- that died before finishing.
+UPDATE checkout_sessions
+SET status = 'completing',
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND (status IN ('open', 'expired', 'cancelled')
+       OR (status = 'completing' AND updated_at < sqlc.arg(stale_before)))
+RETURNING *;
+
+-- name: CloseOpenCheckoutSession :one
+-- Only open sessions are closed, so a session completed by its webhook in
+-- the meantime is left alone (no row is returned)
+UPDATE checkout_sessions
+SET status = sqlc.arg(status),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'open'
+RETURNING *;
+
+-- name: ListExpiredCheckoutSessions :many
+SELECT * FROM checkout_sessions
+WHERE status = 'open' AND expires_at <= sqlc.arg(expires_before)
+ORDER BY expires_at
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,60 @@
+-- name: GetCustomerByUserID :one
+SELECT * FROM customers WHERE user_id = $1;
+
+-- name: UpsertCustomer :one
+INSERT INTO customers (
+    user_id, name, email, address, tax_id, preferred_currency
+) VALUES (
+    sqlc.arg(user_id), sqlc.narg(name), sqlc.narg(email), sqlc.narg(address),
+    sqlc.narg(tax_id), sqlc.narg(preferred_currency)
+)
+ON CONFLICT (user_id) DO UPDATE SET
+    name = EXCLUDED.name,
+    email = EXCLUDED.email,
+    address = EXCLUDED.address,
+    tax_id = EXCLUDED.tax_id,
+    preferred_currency = EXCLUDED.preferred_currency,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: SetCustomerProviderID :one
+UPDATE customers
+SET provider = sqlc.arg(provider),
+    provider_customer_id = sqlc.arg(provider_customer_id),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePaymentMethod :one
+INSERT INTO payment_methods (
+    customer_id, provider, provider_payment_method_id, type, brand, last4, exp_month, exp_year
+) VALUES (
+    sqlc.arg(customer_id), sqlc.arg(provider), sqlc.arg(provider_payment_method_id), sqlc.arg(type),
+    sqlc.narg(brand), sqlc.narg(last4), sqlc.narg(exp_month), sqlc.narg(exp_year)
+)
+RETURNING *;
+
+-- name: GetPaymentMethodByID :one
+SELECT * FROM payment_methods WHERE id = $1;
+
+-- name: GetDefaultPaymentMethod :one
+SELECT * FROM payment_methods WHERE customer_id = $1 AND is_default;
+
+-- name: ListPaymentMethodsByCustomer :many
+SELECT * FROM payment_methods
+WHERE customer_id = $1
+ORDER BY is_default DESC, created_at DESC, id;
+
+-- name: ClearDefaultPaymentMethod :exec
+UPDATE payment_methods
+SET is_default = FALSE, updated_at = NOW()
+WHERE customer_id = $1 AND is_default;
+
+-- name: SetDefaultPaymentMethod :one
+UPDATE payment_methods
+SET is_default = TRUE, updated_at = NOW()
+WHERE id = sqlc.arg(id) AND customer_id = sqlc.arg(customer_id)
+RETURNING *;
+
+-- name: DeletePaymentMethod :exec
+DELETE FROM payment_methods WHERE id = $1;
@@ -15,12 +15,12 @@ ORDER BY granted_at DESC;
 -- name: InsertEntitlement :one
 INSERT INTO entitlements (
     user_id, family_id, feature_code, plan_id, subscription_id,
-    status, granted_at, expires_at, usage_limits, metadata
+    status, granted_at, expires_at, usage_limits, metadata, source
 ) VALUES (
     sqlc.arg(user_id), sqlc.narg(family_id), sqlc.arg(feature_code),
     sqlc.arg(plan_id), sqlc.narg(subscription_id),
     sqlc.arg(status), sqlc.arg(granted_at), sqlc.narg(expires_at),
-    sqlc.arg(usage_limits), sqlc.arg(metadata)
+    sqlc.arg(usage_limits), sqlc.arg(metadata), sqlc.arg(source)
 ) RETURNING *;
 
 -- name: UpdateEntitlementStatus :one
@@ -64,3 +64,17 @@ SET user_id = sqlc.arg(user_id),
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
+
+-- name: ListEntitlementsAfter :many
+SELECT * FROM entitlements
+WHERE (sqlc.narg(user_id)::text IS NULL OR user_id = sqlc.narg(user_id))
+  AND (sqlc.narg(family_id)::text IS NULL OR family_id = sqlc.narg(family_id))
+  AND (sqlc.narg(feature_code)::text IS NULL OR feature_code = sqlc.narg(feature_code))
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(source)::text IS NULL OR source = sqlc.narg(source))
+  AND (
+    sqlc.narg(cursor_id)::uuid IS NULL
+    OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg(page_limit);
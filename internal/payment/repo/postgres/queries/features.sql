@@ -0,0 +1,25 @@
+-- name: ListFeatures :many
+SELECT * FROM features
+ORDER BY code;
+
+-- name: GetFeature :one
+SELECT * FROM features
+WHERE code = sqlc.arg(code);
+
+-- name: InsertFeature :one
+INSERT INTO features (
+    code, description, quota_schema, default_limits, deprecated
+) VALUES (
+    sqlc.arg(code), sqlc.arg(description), sqlc.arg(quota_schema),
+    sqlc.arg(default_limits), sqlc.arg(deprecated)
+) RETURNING *;
+
+-- name: UpdateFeature :one
+UPDATE features
+SET description = sqlc.arg(description),
+    quota_schema = sqlc.arg(quota_schema),
+    default_limits = sqlc.arg(default_limits),
+    deprecated = sqlc.arg(deprecated),
+    updated_at = NOW()
+WHERE code = sqlc.arg(code)
+RETURNING *;
@@ -0,0 +1,52 @@
+-- name: NextInvoiceSequence :one
+-- Must run in the transaction that inserts the invoice; the row lock taken
+-- by the upsert serialises numbering within a year
+INSERT INTO invoice_sequences (year, last_number)
+VALUES (sqlc.arg(year), 1)
+ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+RETURNING last_number;
+
+-- name: CreateInvoice :one
+INSERT INTO invoices (
+    number, year, sequence, payment_id, checkout_session_id, user_id, family_id, plan_id, plan_version,
+    currency, subtotal_cents, discount_cents, tax_cents, total_cents,
+    billing_name, billing_email, billing_address, billing_tax_id, tax_inclusive, reverse_charge, status, issued_at
+) VALUES (
+    sqlc.arg(number), sqlc.arg(year), sqlc.arg(sequence), sqlc.arg(payment_id), sqlc.narg(checkout_session_id),
+    sqlc.arg(user_id), sqlc.narg(family_id), sqlc.arg(plan_id), sqlc.arg(plan_version),
+    sqlc.arg(currency), sqlc.arg(subtotal_cents), sqlc.arg(discount_cents), sqlc.arg(tax_cents), sqlc.arg(total_cents),
+    sqlc.narg(billing_name), sqlc.narg(billing_email), sqlc.narg(billing_address), sqlc.narg(billing_tax_id),
+    sqlc.arg(tax_inclusive), sqlc.arg(reverse_charge), sqlc.arg(status), sqlc.arg(issued_at)
+) RETURNING *;
+
+-- name: InsertInvoiceLineItem :exec
+INSERT INTO invoice_line_items (
+    invoice_id, position, kind, description, quantity, unit_amount_cents, amount_cents
+) VALUES (
+    sqlc.arg(invoice_id), sqlc.arg(position), sqlc.arg(kind), sqlc.arg(description),
+    sqlc.arg(quantity), sqlc.arg(unit_amount_cents), sqlc.arg(amount_cents)
+);
+
+-- name: GetInvoiceByID :one
+SELECT * FROM invoices WHERE id = sqlc.arg(id);
+
+-- name: GetInvoiceByNumber :one
+SELECT * FROM invoices WHERE number = sqlc.arg(number);
+
+-- name: GetInvoiceByPaymentID :one
+SELECT * FROM invoices WHERE payment_id = sqlc.arg(payment_id);
+
+-- name: ListInvoices :many
+-- Newest first by (year, sequence); the cursor is the last invoice of the
+-- previous page
+SELECT * FROM invoices
+WHERE (sqlc.narg(user_id)::text IS NULL OR user_id = sqlc.narg(user_id)::text)
+  AND (sqlc.narg(cursor_year)::integer IS NULL
+       OR (year, sequence) < (sqlc.narg(cursor_year)::integer, sqlc.narg(cursor_sequence)::integer))
+ORDER BY year DESC, sequence DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListInvoiceLineItems :many
+SELECT * FROM invoice_line_items
+WHERE invoice_id = ANY(sqlc.arg(invoice_ids)::uuid[])
+ORDER BY invoice_id, position;
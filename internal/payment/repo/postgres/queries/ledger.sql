@@ -0,0 +1,57 @@
+-- name: InsertJournalEntry :one
+-- Returns no row when an entry with the same reference was already posted
+INSERT INTO journal_entries (
+    reference, kind, payment_id, customer_id, currency, description, occurred_at
+) VALUES (
+    sqlc.arg(reference), sqlc.arg(kind), sqlc.narg(payment_id), sqlc.narg(customer_id),
+    sqlc.arg(currency), sqlc.narg(description), sqlc.arg(occurred_at)
+)
+ON CONFLICT (reference) DO NOTHING
+RETURNING *;
+
+-- name: InsertLedgerPosting :one
+INSERT INTO ledger_postings (
+    entry_id, account_code, direction, amount_cents, currency, customer_id
+) VALUES (
+    sqlc.arg(entry_id), sqlc.arg(account_code), sqlc.arg(direction), sqlc.arg(amount_cents),
+    sqlc.arg(currency), sqlc.narg(customer_id)
+) RETURNING *;
+
+-- name: GetJournalEntryByReference :one
+SELECT * FROM journal_entries WHERE reference = sqlc.arg(reference);
+
+-- name: ListJournalEntriesByPayment :many
+SELECT * FROM journal_entries
+WHERE payment_id = sqlc.arg(payment_id)
+ORDER BY occurred_at, created_at;
+
+-- name: ListLedgerPostingsByEntries :many
+SELECT * FROM ledger_postings
+WHERE entry_id = ANY(sqlc.arg(entry_ids)::uuid[])
+ORDER BY entry_id, created_at, id;
+
+-- name: GetLedgerBalances :many
+-- Debit and credit totals per account and currency, optionally limited to
+-- one customer or one account
+SELECT a.code, a.name, a.type, a.normal_balance, p.currency,
+       COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'debit'), 0)::bigint AS debit_cents,
+       COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'credit'), 0)::bigint AS credit_cents
+FROM ledger_postings p
+JOIN ledger_accounts a ON a.code = p.account_code
+WHERE (sqlc.narg(customer_id)::text IS NULL OR p.customer_id = sqlc.narg(customer_id)::text)
+  AND (sqlc.narg(account_code)::text IS NULL OR p.account_code = sqlc.narg(account_code)::text)
+GROUP BY a.code, a.name, a.type, a.normal_balance, p.currency
+ORDER BY a.code, p.currency;
+
+-- name: ListUnbalancedJournalEntries :many
+-- Entries whose postings do not balance in some currency; the trial
+-- balance check expects none
+SELECT e.id, e.reference, p.currency,
+       COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'debit'), 0)::bigint AS debit_cents,
+       COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'credit'), 0)::bigint AS credit_cents
+FROM journal_entries e
+JOIN ledger_postings p ON p.entry_id = e.id
+GROUP BY e.id, e.reference, p.currency
+HAVING COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'debit'), 0)
+    <> COALESCE(SUM(p.amount_cents) FILTER (WHERE p.direction = 'credit'), 0)
+ORDER BY e.reference;
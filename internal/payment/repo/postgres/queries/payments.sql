@@ -1,10 +1,10 @@
 -- name: CreatePayment :one
 INSERT INTO payments (
-    amount, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata
+    amount, currency, status, payment_method, customer_id, order_id, description, external_payment_id, failure_reason, metadata, provider
 ) VALUES (
     sqlc.arg(amount), sqlc.arg(currency), sqlc.arg(status), sqlc.arg(payment_method),
     sqlc.arg(customer_id), sqlc.arg(order_id), sqlc.narg(description), sqlc.narg(external_payment_id),
-    sqlc.narg(failure_reason), sqlc.narg(metadata)
+    sqlc.narg(failure_reason), sqlc.narg(metadata), sqlc.narg(provider)
 ) RETURNING *;
 
 -- name: GetPaymentByID :one
@@ -51,3 +51,49 @@ ORDER BY created_at DESC;
 
 -- name: CountPayments :one
 SELECT COUNT(*) FROM payments;
+
+-- name: ListPaymentsFiltered :many
+-- Keyset pagination: when a cursor is supplied only rows strictly after the
+-- (sort key, id) pair of the last row on the previous page are returned, so
+-- concurrent inserts never shift pages the way OFFSET does.
+SELECT * FROM payments
+WHERE (sqlc.narg(customer_id)::text IS NULL OR customer_id = sqlc.narg(customer_id)::text)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(payment_method)::text IS NULL OR payment_method = sqlc.narg(payment_method)::text)
+  AND (sqlc.narg(external_payment_id)::text IS NULL OR external_payment_id = sqlc.narg(external_payment_id)::text)
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR created_at >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(min_amount)::numeric IS NULL OR amount >= sqlc.narg(min_amount)::numeric)
+  AND (sqlc.narg(max_amount)::numeric IS NULL OR amount <= sqlc.narg(max_amount)::numeric)
+  AND (
+    sqlc.narg(cursor_id)::uuid IS NULL
+    OR (sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(sort_desc)::boolean
+        AND (created_at, id) < (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::boolean
+        AND (created_at, id) > (sqlc.narg(cursor_created_at)::timestamp, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'amount' AND sqlc.arg(sort_desc)::boolean
+        AND (amount, id) < (sqlc.narg(cursor_amount)::numeric, sqlc.narg(cursor_id)::uuid))
+    OR (sqlc.arg(sort_by)::text = 'amount' AND NOT sqlc.arg(sort_desc)::boolean
+        AND (amount, id) > (sqlc.narg(cursor_amount)::numeric, sqlc.narg(cursor_id)::uuid))
+  )
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(sort_desc)::boolean THEN created_at END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(sort_desc)::boolean THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'amount' AND NOT sqlc.arg(sort_desc)::boolean THEN amount END ASC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'amount' AND sqlc.arg(sort_desc)::boolean THEN amount END DESC,
+  CASE WHEN NOT sqlc.arg(sort_desc)::boolean THEN id END ASC,
+  CASE WHEN sqlc.arg(sort_desc)::boolean THEN id END DESC
+LIMIT sqlc.arg(page_limit) OFFSET sqlc.arg(page_offset);
+
+-- name: CountPaymentsFiltered :one
+SELECT COUNT(*) FROM payments
+WHERE (sqlc.narg(customer_id)::text IS NULL OR customer_id = sqlc.narg(customer_id)::text)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(currency)::text IS NULL OR currency = sqlc.narg(currency)::text)
+  AND (sqlc.narg(payment_method)::text IS NULL OR payment_method = sqlc.narg(payment_method)::text)
+  AND (sqlc.narg(external_payment_id)::text IS NULL OR external_payment_id = sqlc.narg(external_payment_id)::text)
+  AND (sqlc.narg(created_after)::timestamp IS NULL OR created_at >= sqlc.narg(created_after)::timestamp)
+  AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before)::timestamp)
+  AND (sqlc.narg(min_amount)::numeric IS NULL OR amount >= sqlc.narg(min_amount)::numeric)
+  AND (sqlc.narg(max_amount)::numeric IS NULL OR amount <= sqlc.narg(max_amount)::numeric);
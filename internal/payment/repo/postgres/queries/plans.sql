@@ -23,3 +23,63 @@ UPDATE plans
 SET active = sqlc.arg(active), updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
+
+-- name: GetPlanByIDIncludingArchived :one
+SELECT * FROM plans
+WHERE id = sqlc.arg(id);
+
+-- name: GetPlanByIDForUpdate :one
+SELECT * FROM plans
+WHERE id = sqlc.arg(id)
+FOR UPDATE;
+
+-- name: GetPlanIDByUUID :one
+SELECT id FROM plans
+WHERE plan_uuid(id) = sqlc.arg(plan_uuid)::uuid;
+
+-- name: ListPlans :many
+SELECT * FROM plans
+WHERE sqlc.arg(include_archived)::bool OR archived_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: UpdatePlan :one
+UPDATE plans
+SET name = sqlc.arg(name),
+    description = sqlc.arg(description),
+    feature_codes = sqlc.arg(feature_codes),
+    billing_cycle = sqlc.arg(billing_cycle),
+    price_cents = sqlc.arg(price_cents),
+    currency = sqlc.arg(currency),
+    max_users = sqlc.arg(max_users),
+    usage_limits = sqlc.arg(usage_limits),
+    metadata = sqlc.arg(metadata),
+    version = sqlc.arg(version),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ArchivePlan :one
+UPDATE plans
+SET active = false,
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: InsertPlanVersion :one
+INSERT INTO plan_versions (
+    plan_id, version, feature_codes, billing_cycle,
+    price_cents, currency, max_users, usage_limits
+) VALUES (
+    sqlc.arg(plan_id), sqlc.arg(version), sqlc.arg(feature_codes), sqlc.arg(billing_cycle),
+    sqlc.arg(price_cents), sqlc.arg(currency), sqlc.arg(max_users), sqlc.arg(usage_limits)
+) RETURNING *;
+
+-- name: GetPlanVersion :one
+SELECT * FROM plan_versions
+WHERE plan_id = sqlc.arg(plan_id) AND version = sqlc.arg(version);
+
+-- name: ListPlanVersions :many
+SELECT * FROM plan_versions
+WHERE plan_id = sqlc.arg(plan_id)
+ORDER BY version DESC;
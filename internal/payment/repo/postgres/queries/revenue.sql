@@ -0,0 +1,55 @@
+-- name: DeleteRevenueSnapshot :exec
+DELETE FROM revenue_snapshots WHERE snapshot_date = sqlc.arg(snapshot_date);
+
+-- name: InsertRevenueSnapshot :execrows
+-- Records every subscription paying at as_of (the end of snapshot_date).
+-- Subscriptions are priced at their grandfathered plan version and, when
+-- bought through a checkout, at the zone-adjusted price quoted there.
+-- Status history is not kept, so for past days a subscription counts until
+-- it was cancelled, its period ended or, when suspended, it was last
+-- updated.
+INSERT INTO revenue_snapshots (snapshot_date, subscription_id, user_id, plan_id, pricing_zone, currency, mrr_cents)
+SELECT sqlc.arg(snapshot_date)::date,
+       s.id,
+       s.user_id,
+       s.plan_id,
+       COALESCE(z.zone, ''),
+       COALESCE(cs.currency, pv.currency),
+       CASE pv.billing_cycle
+           WHEN 'yearly' THEN ROUND(COALESCE(cs.quoted_price_cents, pv.price_cents) / 12.0)::bigint
+           ELSE COALESCE(cs.quoted_price_cents, pv.price_cents)::bigint
+       END
+FROM subscriptions s
+JOIN plans p ON p.id = s.plan_id
+JOIN plan_versions pv ON pv.plan_id = p.id AND pv.version = COALESCE(s.plan_version, p.version)
+LEFT JOIN LATERAL (
+    SELECT c.quoted_price_cents, c.currency, c.country_code
+    FROM checkout_sessions c
+    WHERE c.user_id = s.user_id
+      AND c.plan_id = s.plan_id
+      AND c.status = 'complete'
+      AND c.created_at <= s.created_at + INTERVAL '1 day'
+    ORDER BY c.created_at DESC
+    LIMIT 1
+) cs ON TRUE
+LEFT JOIN pricing_zones z ON z.iso_code = cs.country_code
+WHERE pv.billing_cycle IN ('monthly', 'yearly')
+  AND s.created_at < sqlc.arg(as_of)::timestamptz
+  AND CASE s.status
+          WHEN 'active' THEN TRUE
+          WHEN 'past_due' THEN TRUE
+          WHEN 'cancelled' THEN COALESCE(s.cancelled_at, s.current_period_end) >= sqlc.arg(as_of)::timestamptz
+          WHEN 'expired' THEN s.current_period_end >= sqlc.arg(as_of)::timestamptz
+          ELSE s.updated_at >= sqlc.arg(as_of)::timestamptz
+      END;
+
+-- name: GetLatestRevenueSnapshotDate :one
+-- The most recent snapshot taken on or before a day; NULL when there is none
+SELECT MAX(snapshot_date)::date AS snapshot_date
+FROM revenue_snapshots
+WHERE snapshot_date <= sqlc.arg(on_or_before)::date;
+
+-- name: ListRevenueSnapshot :many
+SELECT * FROM revenue_snapshots
+WHERE snapshot_date = sqlc.arg(snapshot_date)
+ORDER BY subscription_id;
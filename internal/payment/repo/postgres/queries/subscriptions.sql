@@ -1,9 +1,9 @@
 -- name: CreateSubscription :one
 INSERT INTO subscriptions (
-    user_id, family_id, plan_id, status, current_period_start, 
+    user_id, family_id, plan_id, plan_version, status, current_period_start, 
     current_period_end, cancel_at_period_end, external_subscription_id, metadata
 ) VALUES (
-    sqlc.arg(user_id), sqlc.narg(family_id), sqlc.arg(plan_id), 
+    sqlc.arg(user_id), sqlc.narg(family_id), sqlc.arg(plan_id), sqlc.narg(plan_version),
     sqlc.arg(status), sqlc.arg(current_period_start), sqlc.arg(current_period_end),
     sqlc.arg(cancel_at_period_end), sqlc.narg(external_subscription_id), sqlc.arg(metadata)
 ) RETURNING *;
@@ -62,3 +62,8 @@ UPDATE subscriptions SET
     updated_at = NOW()
 WHERE id = sqlc.arg(id)
 RETURNING *;
+
+-- name: ListSubscriptionsCreatedBetween :many
+SELECT * FROM subscriptions
+WHERE created_at >= sqlc.arg(created_after) AND created_at < sqlc.arg(created_before)
+ORDER BY created_at, id;
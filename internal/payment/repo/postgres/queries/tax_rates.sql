@@ -0,0 +1,30 @@
+-- name: GetTaxRate :one
+-- Prefers the regional rate and falls back to the country-wide rate
+SELECT * FROM tax_rates
+WHERE country_code = sqlc.arg(country_code)
+  AND region IN (sqlc.arg(region)::text, '')
+ORDER BY region DESC
+LIMIT 1;
+
+-- name: ListTaxRates :many
+SELECT * FROM tax_rates
+ORDER BY country_code, region;
+
+-- name: UpsertTaxRate :one
+INSERT INTO tax_rates (
+    country_code, region, name, rate_percent, inclusive, digital_services, reverse_charge
+) VALUES (
+    sqlc.arg(country_code), sqlc.arg(region), sqlc.arg(name), sqlc.arg(rate_percent),
+    sqlc.arg(inclusive), sqlc.arg(digital_services), sqlc.arg(reverse_charge)
+)
+ON CONFLICT (country_code, region) DO UPDATE SET
+    name = EXCLUDED.name,
+    rate_percent = EXCLUDED.rate_percent,
+    inclusive = EXCLUDED.inclusive,
+    digital_services = EXCLUDED.digital_services,
+    reverse_charge = EXCLUDED.reverse_charge,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteTaxRate :exec
+DELETE FROM tax_rates WHERE country_code = sqlc.arg(country_code) AND region = sqlc.arg(region);
@@ -2,11 +2,16 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
@@ -70,16 +75,66 @@ func (s *Store) Plan() repo.PlanRepository {
 	return &planRepository{store: s}
 }
 
+// Feature returns the feature catalog repository implementation
+func (s *Store) Feature() repo.FeatureRepository {
+	return &featureRepository{store: s}
+}
+
 // Entitlement returns the entitlement repository implementation
 func (s *Store) Entitlement() repo.EntitlementRepository {
 	return &entitlementRepository{store: s}
 }
 
+// CheckoutSession returns the checkout session repository implementation
+func (s *Store) CheckoutSession() repo.CheckoutSessionRepository {
+	return &checkoutSessionRepository{store: s}
+}
+
+// Invoice returns the invoice repository implementation
+func (s *Store) Invoice() repo.InvoiceRepository {
+	return &invoiceRepository{store: s}
+}
+
+// TaxRate returns the tax rate repository implementation
+func (s *Store) TaxRate() repo.TaxRateRepository {
+	return &taxRateRepository{store: s}
+}
+
+// Customer returns the customer repository implementation
+func (s *Store) Customer() repo.CustomerRepository {
+	return &customerRepository{store: s}
+}
+
+// Subscription returns the subscription repository implementation
+func (s *Store) Subscription() repo.SubscriptionRepository {
+	return &subscriptionRepository{store: s}
+}
+
+// Ledger returns the ledger repository implementation
+func (s *Store) Ledger() repo.LedgerRepository {
+	return &ledgerRepository{store: s}
+}
+
+// Revenue returns the revenue snapshot repository implementation
+func (s *Store) Revenue() repo.RevenueRepository {
+	return &revenueRepository{store: s}
+}
+
+// Audit returns the audit event repository implementation
+func (s *Store) Audit() repo.AuditRepository {
+	return &auditRepository{store: s}
+}
+
 // PricingZone returns the pricing zone repository implementation
 func (s *Store) PricingZone() repo.PricingZoneRepository {
 	return &pricingZoneRepository{store: s}
 }
 
+// Usage returns the usage repository implementation
+func (s *Store) Usage() repo.UsageRepository {
+	return &usageRepository{store: s}
+}
+
 // paymentRepository implements repository.PaymentRepository
 type paymentRepository struct {
 	store *Store
@@ -88,7 +143,7 @@ type paymentRepository struct {
 // Create creates a new payment
 func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
 	params := pgstore.CreatePaymentParams{
-		Amount:            pgtype.Numeric{Int: big.NewInt(int64(payment.Amount * 100)), Exp: -2, Valid: true}, // Store as dollars with 2 decimal places
+		Amount:            dollarsToNumeric(payment.Amount), // Store as dollars with 2 decimal places
 		Currency:          payment.Currency,
 		Status:            payment.Status,
 		PaymentMethod:     payment.PaymentMethod,
@@ -98,6 +153,7 @@ func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment)
 		ExternalPaymentID: pgtype.Text{String: payment.ExternalPaymentID, Valid: payment.ExternalPaymentID != ""},
 		FailureReason:     pgtype.Text{String: payment.FailureReason, Valid: payment.FailureReason != ""},
 		Metadata:          payment.Metadata,
+		Provider:          pgtype.Text{String: payment.Provider, Valid: payment.Provider != ""},
 	}
 
 	dbPayment, err := r.store.queries.CreatePayment(ctx, r.store.db, params)
@@ -135,6 +191,9 @@ func (r *paymentRepository) GetByID(ctx context.Context, id string) (*domain.Pay
 func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
 	dbPayment, err := r.store.queries.GetPaymentByOrderID(ctx, r.store.db, orderID)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("payment", orderID)
+		}
 		return nil, fmt.Errorf("failed to get payment by order ID: %w", err)
 	}
 
@@ -168,7 +227,7 @@ func (r *paymentRepository) GetByCustomerID(ctx context.Context, customerID stri
 func (r *paymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
 	params := pgstore.UpdatePaymentParams{
 		ID:                pgtype.UUID{Bytes: payment.ID, Valid: true},
-		Amount:            pgtype.Numeric{Int: big.NewInt(int64(payment.Amount * 100)), Exp: -2, Valid: true}, // Store as dollars with 2 decimal places
+		Amount:            dollarsToNumeric(payment.Amount), // Store as dollars with 2 decimal places
 		Currency:          payment.Currency,
 		Status:            payment.Status,
 		PaymentMethod:     payment.PaymentMethod,
@@ -255,42 +314,388 @@ func (r *paymentRepository) Count(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// ListFiltered retrieves a filtered, keyset-paginated list of payments
+func (r *paymentRepository) ListFiltered(ctx context.Context, query domain.PaymentListQuery) ([]*domain.Payment, error) {
+	filter := paymentFilterParams(query.Filter)
+	params := pgstore.ListPaymentsFilteredParams{
+		CustomerID:        filter.CustomerID,
+		Status:            filter.Status,
+		Currency:          filter.Currency,
+		PaymentMethod:     filter.PaymentMethod,
+		ExternalPaymentID: filter.ExternalPaymentID,
+		CreatedAfter:      filter.CreatedAfter,
+		CreatedBefore:     filter.CreatedBefore,
+		MinAmount:         filter.MinAmount,
+		MaxAmount:         filter.MaxAmount,
+		SortBy:            string(query.SortBy),
+		SortDesc:          query.SortDesc,
+		PageLimit:         int32(query.Limit),
+	}
+
+	if query.Cursor != nil {
+		params.CursorID = pgtype.UUID{Bytes: query.Cursor.ID, Valid: true}
+		params.CursorCreatedAt = pgtype.Timestamp{Time: query.Cursor.CreatedAt, Valid: true}
+		params.CursorAmount = dollarsToNumeric(query.Cursor.Amount)
+	} else {
+		params.PageOffset = int32(query.Offset)
+	}
+
+	dbPayments, err := r.store.queries.ListPaymentsFiltered(ctx, r.store.db, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	payments := make([]*domain.Payment, len(dbPayments))
+	for i, dbPayment := range dbPayments {
+		payments[i] = convertPaymentFromDB(dbPayment)
+	}
+
+	return payments, nil
+}
+
+// CountFiltered returns the number of payments matching the filter
+func (r *paymentRepository) CountFiltered(ctx context.Context, filter domain.PaymentFilter) (int64, error) {
+	count, err := r.store.queries.CountPaymentsFiltered(ctx, r.store.db, paymentFilterParams(filter))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count payments: %w", err)
+	}
+
+	return count, nil
+}
+
+// paymentFilterParams converts a domain filter into nullable query parameters
+func paymentFilterParams(f domain.PaymentFilter) pgstore.CountPaymentsFilteredParams {
+	params := pgstore.CountPaymentsFilteredParams{
+		CustomerID:        pgtype.Text{String: f.CustomerID, Valid: f.CustomerID != ""},
+		Status:            pgtype.Text{String: f.Status, Valid: f.Status != ""},
+		Currency:          pgtype.Text{String: f.Currency, Valid: f.Currency != ""},
+		PaymentMethod:     pgtype.Text{String: f.PaymentMethod, Valid: f.PaymentMethod != ""},
+		ExternalPaymentID: pgtype.Text{String: f.ExternalPaymentID, Valid: f.ExternalPaymentID != ""},
+	}
+	if f.CreatedAfter != nil {
+		params.CreatedAfter = pgtype.Timestamp{Time: *f.CreatedAfter, Valid: true}
+	}
+	if f.CreatedBefore != nil {
+		params.CreatedBefore = pgtype.Timestamp{Time: *f.CreatedBefore, Valid: true}
+	}
+	if f.MinAmount != nil {
+		params.MinAmount = dollarsToNumeric(*f.MinAmount)
+	}
+	if f.MaxAmount != nil {
+		params.MaxAmount = dollarsToNumeric(*f.MaxAmount)
+	}
+	return params
+}
+
+// dollarsToNumeric converts a dollar amount to a NUMERIC with 2 decimal places
+func dollarsToNumeric(amount float64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(int64(math.Round(amount * 100))), Exp: -2, Valid: true}
+}
+
 // planRepository implements repository.PlanRepository
 type planRepository struct {
 	store *Store
 }
 
-// GetByID retrieves a plan by ID
+// GetByID retrieves an active plan by ID
 func (r *planRepository) GetByID(ctx context.Context, id string) (domain.Plan, error) {
 	dbPlan, err := r.store.queries.GetPlanByID(ctx, r.store.db, id)
 	if err != nil {
-		return domain.Plan{}, fmt.Errorf("failed to get plan by ID: %w", err)
+		return domain.Plan{}, planError(err, id, "failed to get plan by ID")
 	}
 
-	// Convert to domain model
-	planID := uuid.NewSHA1(uuid.NameSpaceOID, []byte(dbPlan.ID))
+	return convertPlanFromDB(dbPlan), nil
+}
 
-	return domain.Plan{
-		ID:           planID,
-		Name:         dbPlan.Name,
-		Description:  dbPlan.Description.String,
-		FeatureCodes: dbPlan.FeatureCodes,
-		BillingCycle: dbPlan.BillingCycle.String,
-		PriceDollars: float64(dbPlan.PriceCents) / 100, // Convert cents to dollars
-		Currency:     dbPlan.Currency,
-		MaxUsers:     dbPlan.MaxUsers.Int32,
-		UsageLimits:  dbPlan.UsageLimits,
-		Metadata:     dbPlan.Metadata,
-		Active:       dbPlan.Active,
-		CreatedAt:    dbPlan.CreatedAt.Time,
-		UpdatedAt:    dbPlan.UpdatedAt.Time,
-	}, nil
+// GetByIDIncludingArchived retrieves a plan by ID whether or not it is archived
+func (r *planRepository) GetByIDIncludingArchived(ctx context.Context, id string) (domain.Plan, error) {
+	dbPlan, err := r.store.queries.GetPlanByIDIncludingArchived(ctx, r.store.db, id)
+	if err != nil {
+		return domain.Plan{}, planError(err, id, "failed to get plan")
+	}
+
+	return convertPlanFromDB(dbPlan), nil
 }
 
 // ListActive retrieves all active plans
 func (r *planRepository) ListActive(ctx context.Context) ([]domain.Plan, error) {
-	// TODO: Implement with sqlc generated queries
-	return nil, fmt.Errorf("not implemented")
+	dbPlans, err := r.store.queries.ListActivePlans(ctx, r.store.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active plans: %w", err)
+	}
+
+	return convertPlansFromDB(dbPlans), nil
+}
+
+// List retrieves catalog plans, optionally including archived ones
+func (r *planRepository) List(ctx context.Context, includeArchived bool) ([]domain.Plan, error) {
+	dbPlans, err := r.store.queries.ListPlans(ctx, r.store.db, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	return convertPlansFromDB(dbPlans), nil
+}
+
+// Create inserts a plan together with its first version
+func (r *planRepository) Create(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	dbPlan, err := r.store.queries.InsertPlan(ctx, tx, pgstore.InsertPlanParams{
+		ID:           plan.Code,
+		Name:         plan.Name,
+		Description:  pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		FeatureCodes: plan.FeatureCodes,
+		BillingCycle: pgtype.Text{String: plan.BillingCycle, Valid: plan.BillingCycle != ""},
+		PriceCents:   int32(plan.PriceCents()),
+		Currency:     plan.Currency,
+		MaxUsers:     pgtype.Int4{Int32: plan.MaxUsers, Valid: plan.MaxUsers > 0},
+		UsageLimits:  plan.UsageLimits,
+		Metadata:     plan.Metadata,
+		Active:       plan.Active,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Plan{}, domain.NewAlreadyExistsError("plan", plan.Code)
+		}
+		return domain.Plan{}, fmt.Errorf("failed to insert plan: %w", err)
+	}
+
+	created := convertPlanFromDB(dbPlan)
+	if err := r.insertVersion(ctx, tx, created.VersionSnapshot()); err != nil {
+		return domain.Plan{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to commit plan: %w", err)
+	}
+
+	return created, nil
+}
+
+// Update writes the plan's fields. When any versioned field differs from the
+// stored plan, the version is bumped and the new version is recorded;
+// existing subscriptions stay on the version they were sold at.
+func (r *planRepository) Update(ctx context.Context, plan domain.Plan) (domain.Plan, error) {
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := r.store.queries.GetPlanByIDForUpdate(ctx, tx, plan.Code)
+	if err != nil {
+		return domain.Plan{}, planError(err, plan.Code, "failed to lock plan")
+	}
+
+	version := current.Version
+	newVersion := !plan.SameVersion(convertPlanFromDB(current))
+	if newVersion {
+		version++
+	}
+
+	dbPlan, err := r.store.queries.UpdatePlan(ctx, tx, pgstore.UpdatePlanParams{
+		ID:           plan.Code,
+		Name:         plan.Name,
+		Description:  pgtype.Text{String: plan.Description, Valid: plan.Description != ""},
+		FeatureCodes: plan.FeatureCodes,
+		BillingCycle: pgtype.Text{String: plan.BillingCycle, Valid: plan.BillingCycle != ""},
+		PriceCents:   int32(plan.PriceCents()),
+		Currency:     plan.Currency,
+		MaxUsers:     pgtype.Int4{Int32: plan.MaxUsers, Valid: plan.MaxUsers > 0},
+		UsageLimits:  plan.UsageLimits,
+		Metadata:     plan.Metadata,
+		Version:      version,
+	})
+	if err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to update plan: %w", err)
+	}
+
+	updated := convertPlanFromDB(dbPlan)
+	if newVersion {
+		if err := r.insertVersion(ctx, tx, updated.VersionSnapshot()); err != nil {
+			return domain.Plan{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to commit plan: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Archive deactivates a plan so it can no longer be sold
+func (r *planRepository) Archive(ctx context.Context, id string) (domain.Plan, error) {
+	dbPlan, err := r.store.queries.ArchivePlan(ctx, r.store.db, id)
+	if err != nil {
+		return domain.Plan{}, planError(err, id, "failed to archive plan")
+	}
+
+	return convertPlanFromDB(dbPlan), nil
+}
+
+// GetVersion retrieves a specific version of a plan
+func (r *planRepository) GetVersion(ctx context.Context, id string, version int32) (domain.PlanVersion, error) {
+	dbVersion, err := r.store.queries.GetPlanVersion(ctx, r.store.db, pgstore.GetPlanVersionParams{
+		PlanID:  id,
+		Version: version,
+	})
+	if err != nil {
+		return domain.PlanVersion{}, planError(err, fmt.Sprintf("%s@%d", id, version), "failed to get plan version")
+	}
+
+	return convertPlanVersionFromDB(dbVersion), nil
+}
+
+// ListVersions retrieves every version of a plan, newest first
+func (r *planRepository) ListVersions(ctx context.Context, id string) ([]domain.PlanVersion, error) {
+	dbVersions, err := r.store.queries.ListPlanVersions(ctx, r.store.db, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plan versions: %w", err)
+	}
+
+	versions := make([]domain.PlanVersion, len(dbVersions))
+	for i, v := range dbVersions {
+		versions[i] = convertPlanVersionFromDB(v)
+	}
+
+	return versions, nil
+}
+
+// insertVersion records a plan version inside the given transaction
+func (r *planRepository) insertVersion(ctx context.Context, tx pgx.Tx, v domain.PlanVersion) error {
+	_, err := r.store.queries.InsertPlanVersion(ctx, tx, pgstore.InsertPlanVersionParams{
+		PlanID:       v.PlanCode,
+		Version:      v.Version,
+		FeatureCodes: v.FeatureCodes,
+		BillingCycle: pgtype.Text{String: v.BillingCycle, Valid: v.BillingCycle != ""},
+		PriceCents:   int32(v.PriceCents()),
+		Currency:     v.Currency,
+		MaxUsers:     pgtype.Int4{Int32: v.MaxUsers, Valid: v.MaxUsers > 0},
+		UsageLimits:  v.UsageLimits,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert plan version: %w", err)
+	}
+	return nil
+}
+
+// planError converts a missing row into a domain not-found error
+func planError(err error, id, message string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("plan", id)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// featureRepository implements repository.FeatureRepository
+type featureRepository struct {
+	store *Store
+}
+
+// List retrieves every catalog feature by code
+func (r *featureRepository) List(ctx context.Context) ([]domain.Feature, error) {
+	dbFeatures, err := r.store.queries.ListFeatures(ctx, r.store.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+
+	features := make([]domain.Feature, 0, len(dbFeatures))
+	for _, dbFeature := range dbFeatures {
+		feature, err := convertFeatureFromDB(dbFeature)
+		if err != nil {
+			return nil, err
+		}
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+// GetByCode retrieves a feature by code
+func (r *featureRepository) GetByCode(ctx context.Context, code string) (domain.Feature, error) {
+	dbFeature, err := r.store.queries.GetFeature(ctx, r.store.db, code)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Feature{}, domain.NewNotFoundError("feature", code)
+		}
+		return domain.Feature{}, fmt.Errorf("failed to get feature: %w", err)
+	}
+	return convertFeatureFromDB(dbFeature)
+}
+
+// Create adds a feature to the catalog
+func (r *featureRepository) Create(ctx context.Context, feature domain.Feature) (domain.Feature, error) {
+	quotaSchema, defaultLimits, err := featureDocuments(feature)
+	if err != nil {
+		return domain.Feature{}, err
+	}
+
+	dbFeature, err := r.store.queries.InsertFeature(ctx, r.store.db, pgstore.InsertFeatureParams{
+		Code:          feature.Code,
+		Description:   feature.Description,
+		QuotaSchema:   quotaSchema,
+		DefaultLimits: defaultLimits,
+		Deprecated:    feature.Deprecated,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Feature{}, domain.NewAlreadyExistsError("feature", feature.Code)
+		}
+		return domain.Feature{}, fmt.Errorf("failed to insert feature: %w", err)
+	}
+	return convertFeatureFromDB(dbFeature)
+}
+
+// Update writes a feature's description, quota schema, default limits and deprecation
+func (r *featureRepository) Update(ctx context.Context, feature domain.Feature) (domain.Feature, error) {
+	quotaSchema, defaultLimits, err := featureDocuments(feature)
+	if err != nil {
+		return domain.Feature{}, err
+	}
+
+	dbFeature, err := r.store.queries.UpdateFeature(ctx, r.store.db, pgstore.UpdateFeatureParams{
+		Code:          feature.Code,
+		Description:   feature.Description,
+		QuotaSchema:   quotaSchema,
+		DefaultLimits: defaultLimits,
+		Deprecated:    feature.Deprecated,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Feature{}, domain.NewNotFoundError("feature", feature.Code)
+		}
+		return domain.Feature{}, fmt.Errorf("failed to update feature: %w", err)
+	}
+	return convertFeatureFromDB(dbFeature)
+}
+
+// featureDocuments encodes a feature's JSONB columns, storing empty ones as {}
+func featureDocuments(feature domain.Feature) ([]byte, []byte, error) {
+	quotaSchema := []byte("{}")
+	if len(feature.QuotaSchema) > 0 {
+		encoded, err := json.Marshal(feature.QuotaSchema)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode quota schema: %w", err)
+		}
+		quotaSchema = encoded
+	}
+
+	defaultLimits := []byte("{}")
+	if len(feature.DefaultLimits) > 0 {
+		defaultLimits = feature.DefaultLimits
+	}
+	return quotaSchema, defaultLimits, nil
 }
 
 // entitlementRepository implements repository.EntitlementRepository
@@ -329,7 +734,9 @@ func (r *entitlementRepository) Check(ctx context.Context, userID, featureCode s
 		PlanID:      planID,
 		Status:      entitlement.Status,
 		GrantedAt:   entitlement.GrantedAt.Time,
+		UsageLimits: entitlement.UsageLimits,
 		CreatedAt:   entitlement.CreatedAt.Time,
+		Source:      entitlement.Source,
 		UpdatedAt:   entitlement.UpdatedAt.Time,
 	}
 
@@ -347,6 +754,24 @@ func (r *entitlementRepository) Check(ctx context.Context, userID, featureCode s
 	return result, true, nil
 }
 
+// GetByID retrieves an entitlement by ID
+func (r *entitlementRepository) GetByID(ctx context.Context, id string) (domain.Entitlement, error) {
+	entitlementUUID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.Entitlement{}, domain.NewNotFoundError("entitlement", id)
+	}
+
+	entitlement, err := r.store.queries.GetEntitlementByID(ctx, r.store.db, pgtype.UUID{Bytes: entitlementUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Entitlement{}, domain.NewNotFoundError("entitlement", id)
+		}
+		return domain.Entitlement{}, fmt.Errorf("failed to get entitlement: %w", err)
+	}
+
+	return convertEntitlementFromDB(entitlement), nil
+}
+
 // ListByUser retrieves all entitlements for a user
 func (r *entitlementRepository) ListByUser(ctx context.Context, userID string) ([]domain.Entitlement, error) {
 	entitlements, err := r.store.queries.ListEntitlementsByUser(ctx, r.store.db, userID)
@@ -373,6 +798,7 @@ func (r *entitlementRepository) ListByUser(ctx context.Context, userID string) (
 			Status:      ent.Status,
 			GrantedAt:   ent.GrantedAt.Time,
 			CreatedAt:   ent.CreatedAt.Time,
+			Source:      ent.Source,
 			UpdatedAt:   ent.UpdatedAt.Time,
 		}
 
@@ -393,20 +819,43 @@ func (r *entitlementRepository) ListByUser(ctx context.Context, userID string) (
 	return result, nil
 }
 
+// ListAfter retrieves a keyset-paginated batch of entitlements matching the filter
+func (r *entitlementRepository) ListAfter(ctx context.Context, filter domain.EntitlementFilter, after *domain.EntitlementCursor, limit int) ([]domain.Entitlement, error) {
+	params := pgstore.ListEntitlementsAfterParams{
+		UserID:      pgtype.Text{String: filter.UserID, Valid: filter.UserID != ""},
+		FamilyID:    pgtype.Text{String: filter.FamilyID, Valid: filter.FamilyID != ""},
+		FeatureCode: pgtype.Text{String: filter.FeatureCode, Valid: filter.FeatureCode != ""},
+		Status:      pgtype.Text{String: filter.Status, Valid: filter.Status != ""},
+		Source:      pgtype.Text{String: filter.Source, Valid: filter.Source != ""},
+		PageLimit:   int32(limit),
+	}
+	if after != nil {
+		params.CursorID = pgtype.UUID{Bytes: after.ID, Valid: true}
+		params.CursorCreatedAt = pgtype.Timestamp{Time: after.CreatedAt, Valid: true}
+	}
+
+	entitlements, err := r.store.queries.ListEntitlementsAfter(ctx, r.store.db, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+
+	result := make([]domain.Entitlement, len(entitlements))
+	for i, ent := range entitlements {
+		result[i] = convertEntitlementFromDB(ent)
+	}
+
+	return result, nil
+}
+
 // Insert creates a new entitlement
 func (r *entitlementRepository) Insert(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error) {
-	// For now, we'll use a hardcoded mapping since the domain model uses UUID but DB expects string
-	// TODO: Refactor to use string plan IDs in domain model
+	// The domain model identifies plans by their derived UUID while the
+	// database references plans.id, so resolve the UUID back to the plan
 	planIDString := e.PlanID.String()
-
-	// Map UUID-based plan IDs to string plan IDs for database foreign key constraint
-	switch e.PlanID.String() {
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("basic_monthly")).String():
-		planIDString = "basic_monthly"
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("pro_monthly")).String():
-		planIDString = "pro_monthly"
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("family_monthly")).String():
-		planIDString = "family_monthly"
+	if code, err := r.store.queries.GetPlanIDByUUID(ctx, r.store.db, pgtype.UUID{Bytes: e.PlanID, Valid: true}); err == nil {
+		planIDString = code
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Entitlement{}, fmt.Errorf("failed to resolve plan ID: %w", err)
 	}
 
 	params := pgstore.InsertEntitlementParams{
@@ -417,6 +866,13 @@ func (r *entitlementRepository) Insert(ctx context.Context, e domain.Entitlement
 		GrantedAt:   pgtype.Timestamp{Time: e.GrantedAt, Valid: true},
 		UsageLimits: []byte("{}"),
 		Metadata:    []byte("{}"),
+		Source:      e.Source,
+	}
+	if len(e.UsageLimits) > 0 {
+		params.UsageLimits = e.UsageLimits
+	}
+	if params.Source == "" {
+		params.Source = domain.EntitlementSourcePaid
 	}
 
 	// Handle optional fields
@@ -453,6 +909,7 @@ func (r *entitlementRepository) Insert(ctx context.Context, e domain.Entitlement
 		Status:      entitlement.Status,
 		GrantedAt:   entitlement.GrantedAt.Time,
 		CreatedAt:   entitlement.CreatedAt.Time,
+		Source:      entitlement.Source,
 		UpdatedAt:   entitlement.UpdatedAt.Time,
 	}
 
@@ -537,6 +994,7 @@ func (r *entitlementRepository) GetBySubscriptionID(ctx context.Context, subscri
 			Status:      ent.Status,
 			GrantedAt:   ent.GrantedAt.Time,
 			CreatedAt:   ent.CreatedAt.Time,
+			Source:      ent.Source,
 			UpdatedAt:   ent.UpdatedAt.Time,
 		}
 
@@ -564,15 +1022,12 @@ func (r *entitlementRepository) Update(ctx context.Context, e domain.Entitlement
 		return domain.Entitlement{}, fmt.Errorf("invalid entitlement ID: %w", err)
 	}
 
-	// For now, we'll use a hardcoded mapping since the domain model uses UUID but DB expects string
+	// Resolve the plan UUID back to plans.id, as Insert does
 	planIDString := e.PlanID.String()
-	switch e.PlanID.String() {
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("basic_monthly")).String():
-		planIDString = "basic_monthly"
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("pro_monthly")).String():
-		planIDString = "pro_monthly"
-	case uuid.NewSHA1(uuid.NameSpaceOID, []byte("family_monthly")).String():
-		planIDString = "family_monthly"
+	if code, err := r.store.queries.GetPlanIDByUUID(ctx, r.store.db, pgtype.UUID{Bytes: e.PlanID, Valid: true}); err == nil {
+		planIDString = code
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.Entitlement{}, fmt.Errorf("failed to resolve plan ID: %w", err)
 	}
 
 	params := pgstore.UpdateEntitlementParams{
@@ -616,6 +1071,7 @@ func (r *entitlementRepository) Update(ctx context.Context, e domain.Entitlement
 		Status:      entitlement.Status,
 		GrantedAt:   entitlement.GrantedAt.Time,
 		CreatedAt:   entitlement.CreatedAt.Time,
+		Source:      entitlement.Source,
 		UpdatedAt:   entitlement.UpdatedAt.Time,
 	}
 
@@ -633,82 +1089,1304 @@ func (r *entitlementRepository) Update(ctx context.Context, e domain.Entitlement
 	return result, nil
 }
 
-// pricingZoneRepository implements repository.PricingZoneRepository
-type pricingZoneRepository struct {
+// checkoutSessionRepository implements repository.CheckoutSessionRepository
+type checkoutSessionRepository struct {
 	store *Store
 }
 
-// GetByISOCode retrieves a pricing zone by ISO country code
-func (r *pricingZoneRepository) GetByISOCode(ctx context.Context, isoCode string) (domain.PricingZone, error) {
-	pricingZone, err := r.store.queries.GetPricingZoneByISOCode(ctx, r.store.db, isoCode)
+// Create persists a checkout session opened with a billing provider
+func (r *checkoutSessionRepository) Create(ctx context.Context, session domain.CheckoutSession) (domain.CheckoutSession, error) {
+	params := pgstore.CreateCheckoutSessionParams{
+		Provider:          session.Provider,
+		ProviderSessionID: session.ProviderSessionID,
+		PlanID:            session.PlanCode,
+		PlanVersion:       session.PlanVersion,
+		UserID:            session.UserID,
+		CountryCode:       pgtype.Text{String: session.CountryCode, Valid: session.CountryCode != ""},
+		Region:            pgtype.Text{String: session.Region, Valid: session.Region != ""},
+		BasePriceCents:    int32(math.Round(session.BasePrice * 100)),
+		QuotedPriceCents:  int32(session.QuotedPriceCents()),
+		PricingMultiplier: pgtype.Numeric{Int: big.NewInt(int64(math.Round(session.PricingMultiplier * 100))), Exp: -2, Valid: true},
+		Currency:          session.Currency,
+		TaxID:             pgtype.Text{String: session.TaxID, Valid: session.TaxID != ""},
+		TaxName:           pgtype.Text{String: session.Tax.Name, Valid: session.Tax.Name != ""},
+		TaxRatePercent:    percentToNumeric(session.Tax.RatePercent),
+		TaxInclusive:      session.Tax.Inclusive,
+		ReverseCharge:     session.Tax.ReverseCharge,
+		TaxCents:          int32(session.Tax.TaxCents),
+		TotalCents:        int32(session.Tax.TotalCents),
+		Status:            string(session.Status),
+		Url:               pgtype.Text{String: session.URL, Valid: session.URL != ""},
+		ExpiresAt:         pgtype.Timestamp{Time: session.ExpiresAt, Valid: true},
+	}
+	if session.FamilyID != nil {
+		params.FamilyID = pgtype.Text{String: *session.FamilyID, Valid: true}
+	}
+
+	dbSession, err := r.store.queries.CreateCheckoutSession(ctx, r.store.db, params)
 	if err != nil {
-		return domain.PricingZone{}, err
+		if isUniqueViolation(err) {
+			return domain.CheckoutSession{}, domain.NewAlreadyExistsError("checkout session", session.ProviderSessionID)
+		}
+		return domain.CheckoutSession{}, fmt.Errorf("failed to create checkout session: %w", err)
 	}
-	return convertPricingZoneFromDB(pricingZone), nil
+
+	return convertCheckoutSessionFromDB(dbSession), nil
 }
 
-// GetByCountry retrieves a pricing zone by country name
-func (r *pricingZoneRepository) GetByCountry(ctx context.Context, country string) (domain.PricingZone, error) {
-	pricingZone, err := r.store.queries.GetPricingZoneByCountry(ctx, r.store.db, country)
+// GetByProviderSessionID retrieves a checkout session by its provider and the provider's session ID
+func (r *checkoutSessionRepository) GetByProviderSessionID(ctx context.Context, provider, providerSessionID string) (domain.CheckoutSession, error) {
+	dbSession, err := r.store.queries.GetCheckoutSessionByProviderSessionID(ctx, r.store.db, pgstore.GetCheckoutSessionByProviderSessionIDParams{
+		Provider:          provider,
+		ProviderSessionID: providerSessionID,
+	})
 	if err != nil {
-		return domain.PricingZone{}, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CheckoutSession{}, domain.NewNotFoundError("checkout session", providerSessionID)
+		}
+		return domain.CheckoutSession{}, fmt.Errorf("failed to get checkout session: %w", err)
 	}
-	return convertPricingZoneFromDB(pricingZone), nil
+
+	return convertCheckoutSessionFromDB(dbSession), nil
 }
 
-// GetByZone retrieves all pricing zones for a specific zone type
-func (r *pricingZoneRepository) GetByZone(ctx context.Context, zone string) ([]domain.PricingZone, error) {
-	pricingZones, err := r.store.queries.GetPricingZonesByZone(ctx, r.store.db, zone)
+// ListByProviderSessionID retrieves the checkout sessions recorded under a
+// provider's session ID with any provider, oldest first
+func (r *checkoutSessionRepository) ListByProviderSessionID(ctx context.Context, providerSessionID string) ([]domain.CheckoutSession, error) {
+	dbSessions, err := r.store.queries.ListCheckoutSessionsByProviderSessionID(ctx, r.store.db, providerSessionID)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to list checkout sessions: %w", err)
 	}
-	return convertPricingZonesFromDB(pricingZones), nil
+
+	sessions := make([]domain.CheckoutSession, len(dbSessions))
+	for i, dbSession := range dbSessions {
+		sessions[i] = convertCheckoutSessionFromDB(dbSession)
+	}
+	return sessions, nil
 }
 
-// List retrieves all pricing zones
-func (r *pricingZoneRepository) List(ctx context.Context) ([]domain.PricingZone, error) {
-	pricingZones, err := r.store.queries.ListPricingZones(ctx, r.store.db)
+// UpdateStatus moves a checkout session to a new status
+func (r *checkoutSessionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.CheckoutSessionStatus) (domain.CheckoutSession, error) {
+	dbSession, err := r.store.queries.UpdateCheckoutSessionStatus(ctx, r.store.db, pgstore.UpdateCheckoutSessionStatusParams{
+		ID:     pgtype.UUID{Bytes: id, Valid: true},
+		Status: string(status),
+	})
 	if err != nil {
-		return nil, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CheckoutSession{}, domain.NewNotFoundError("checkout session", id.String())
+		}
+		return domain.CheckoutSession{}, fmt.Errorf("failed to update checkout session status: %w", err)
 	}
-	return convertPricingZonesFromDB(pricingZones), nil
+
+	return convertCheckoutSessionFromDB(dbSession), nil
 }
 
-// Upsert creates or updates a pricing zone
-func (r *pricingZoneRepository) Upsert(ctx context.Context, zone domain.PricingZone) (domain.PricingZone, error) {
-	params := pgstore.UpsertPricingZoneParams{
-		Country:                 zone.Country,
-		IsoCode:                 zone.ISOCode,
-		Zone:                    zone.Zone,
-		ZoneName:                zone.ZoneName,
-		WorldBankClassification: pgtype.Text{String: zone.WorldBankClassification, Valid: zone.WorldBankClassification != ""},
-		GniPerCapitaThreshold:   pgtype.Text{String: zone.GNIPerCapitaThreshold, Valid: zone.GNIPerCapitaThreshold != ""},
-		PricingMultiplier:       pgtype.Numeric{Int: big.NewInt(int64(zone.PricingMultiplier * 100)), Valid: true, Exp: -2},
+// CloseIfOpen moves an open checkout session to a terminal status
+func (r *checkoutSessionRepository) CloseIfOpen(ctx context.Context, id uuid.UUID, status domain.CheckoutSessionStatus) (domain.CheckoutSession, bool, error) {
+	dbSession, err := r.store.queries.CloseOpenCheckoutSession(ctx, r.store.db, pgstore.CloseOpenCheckoutSessionParams{
+		ID:     pgtype.UUID{Bytes: id, Valid: true},
+		Status: string(status),
+	})
+	if err == nil {
+		return convertCheckoutSessionFromDB(dbSession), true, nil
 	}
-
-	pricingZone, err := r.store.queries.UpsertPricingZone(ctx, r.store.db, params)
-	if err != nil {
-		return domain.PricingZone{}, err
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.CheckoutSession{}, false, fmt.Errorf("failed to close checkout session: %w", err)
 	}
-	return convertPricingZoneFromDB(pricingZone), nil
-}
 
-// BulkUpsert creates or updates multiple pricing zones
-func (r *pricingZoneRepository) BulkUpsert(ctx context.Context, zones []domain.PricingZone) error {
-	for _, zone := range zones {
-		_, err := r.Upsert(ctx, zone)
-		if err != nil {
-			return fmt.Errorf("failed to upsert zone %s: %w", zone.ISOCode, err)
+	// Not open any more (or gone); report its current state
+	dbSession, err = r.store.queries.GetCheckoutSessionByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CheckoutSession{}, false, domain.NewNotFoundError("checkout session", id.String())
 		}
+		return domain.CheckoutSession{}, false, fmt.Errorf("failed to get checkout session: %w", err)
 	}
-	return nil
+
+	return convertCheckoutSessionFromDB(dbSession), false, nil
 }
 
-// Delete deletes a pricing zone by ISO code
+// ClaimForCompletion moves a checkout session to completing for its completion webhook
+func (r *checkoutSessionRepository) ClaimForCompletion(ctx context.Context, id uuid.UUID, staleBefore time.Time) (domain.CheckoutSession, bool, error) {
+	dbSession, err := r.store.queries.ClaimCheckoutSessionForCompletion(ctx, r.store.db, pgstore.ClaimCheckoutSessionForCompletionParams{
+		ID:          pgtype.UUID{Bytes: id, Valid: true},
+		StaleBefore: pgtype.Timestamp{Time: staleBefore, Valid: true},
+	})
+	if err == nil {
+		return convertCheckoutSessionFromDB(dbSession), true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.CheckoutSession{}, false, fmt.Errorf("failed to claim checkout session: %w", err)
+	}
+
+	// Claimed by another delivery, already complete (or gone); report its
+	// current state
+	dbSession, err = r.store.queries.GetCheckoutSessionByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.CheckoutSession{}, false, domain.NewNotFoundError("checkout session", id.String())
+		}
+		return domain.CheckoutSession{}, false, fmt.Errorf("failed to get checkout session: %w", err)
+	}
+
+	return convertCheckoutSessionFromDB(dbSession), false, nil
+}
+
+// ListExpired retrieves open sessions that expired before the given time, oldest first
+func (r *checkoutSessionRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]domain.CheckoutSession, error) {
+	dbSessions, err := r.store.queries.ListExpiredCheckoutSessions(ctx, r.store.db, pgstore.ListExpiredCheckoutSessionsParams{
+		ExpiresBefore: pgtype.Timestamp{Time: before, Valid: true},
+		PageLimit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired checkout sessions: %w", err)
+	}
+
+	sessions := make([]domain.CheckoutSession, len(dbSessions))
+	for i, dbSession := range dbSessions {
+		sessions[i] = convertCheckoutSessionFromDB(dbSession)
+	}
+	return sessions, nil
+}
+
+// invoiceRepository implements repository.InvoiceRepository
+type invoiceRepository struct {
+	store *Store
+}
+
+// Create issues an invoice. The sequence increment, invoice and line items
+// are written in one transaction so that a failure leaves no gap in the
+// numbering.
+func (r *invoiceRepository) Create(ctx context.Context, invoice domain.Invoice) (domain.Invoice, error) {
+	billingAddress, err := json.Marshal(invoice.Billing.Address)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to encode billing address: %w", err)
+	}
+
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	year := int32(invoice.IssuedAt.UTC().Year())
+	sequence, err := r.store.queries.NextInvoiceSequence(ctx, tx, year)
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to allocate invoice number: %w", err)
+	}
+
+	params := pgstore.CreateInvoiceParams{
+		Number:        domain.FormatInvoiceNumber(year, sequence),
+		Year:          year,
+		Sequence:      sequence,
+		PaymentID:     pgtype.UUID{Bytes: invoice.PaymentID, Valid: true},
+		UserID:        invoice.UserID,
+		PlanID:        invoice.PlanCode,
+		PlanVersion:   invoice.PlanVersion,
+		Currency:      invoice.Currency,
+		SubtotalCents: invoice.SubtotalCents,
+		DiscountCents: invoice.DiscountCents,
+		TaxCents:      invoice.TaxCents,
+		TotalCents:    invoice.TotalCents,
+		BillingName:   pgtype.Text{String: invoice.Billing.Name, Valid: invoice.Billing.Name != ""},
+		BillingEmail:  pgtype.Text{String: invoice.Billing.Email, Valid: invoice.Billing.Email != ""},
+		BillingTaxID:  pgtype.Text{String: invoice.Billing.TaxID, Valid: invoice.Billing.TaxID != ""},
+		TaxInclusive:  invoice.TaxInclusive,
+		ReverseCharge: invoice.ReverseCharge,
+		Status:        string(invoice.Status),
+		IssuedAt:      pgtype.Timestamp{Time: invoice.IssuedAt, Valid: true},
+	}
+	if !invoice.Billing.Address.IsZero() {
+		params.BillingAddress = billingAddress
+	}
+	if invoice.CheckoutSessionID != nil {
+		params.CheckoutSessionID = pgtype.UUID{Bytes: *invoice.CheckoutSessionID, Valid: true}
+	}
+	if invoice.FamilyID != nil {
+		params.FamilyID = pgtype.Text{String: *invoice.FamilyID, Valid: true}
+	}
+
+	dbInvoice, err := r.store.queries.CreateInvoice(ctx, tx, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.Invoice{}, domain.NewAlreadyExistsError("invoice for payment", invoice.PaymentID.String())
+		}
+		return domain.Invoice{}, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	for i, line := range invoice.LineItems {
+		if err := r.store.queries.InsertInvoiceLineItem(ctx, tx, pgstore.InsertInvoiceLineItemParams{
+			InvoiceID:       dbInvoice.ID,
+			Position:        int32(i + 1),
+			Kind:            string(line.Kind),
+			Description:     line.Description,
+			Quantity:        line.Quantity,
+			UnitAmountCents: line.UnitAmountCents,
+			AmountCents:     line.AmountCents,
+		}); err != nil {
+			return domain.Invoice{}, fmt.Errorf("failed to create invoice line item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to commit invoice: %w", err)
+	}
+
+	created := convertInvoiceFromDB(dbInvoice)
+	created.LineItems = invoice.LineItems
+	return created, nil
+}
+
+// GetByID retrieves an invoice with its line items
+func (r *invoiceRepository) GetByID(ctx context.Context, id uuid.UUID) (domain.Invoice, error) {
+	dbInvoice, err := r.store.queries.GetInvoiceByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return domain.Invoice{}, invoiceError(err, id.String())
+	}
+	return r.withLineItems(ctx, dbInvoice)
+}
+
+// GetByNumber retrieves an invoice by its invoice number
+func (r *invoiceRepository) GetByNumber(ctx context.Context, number string) (domain.Invoice, error) {
+	dbInvoice, err := r.store.queries.GetInvoiceByNumber(ctx, r.store.db, number)
+	if err != nil {
+		return domain.Invoice{}, invoiceError(err, number)
+	}
+	return r.withLineItems(ctx, dbInvoice)
+}
+
+// GetByPaymentID retrieves the invoice issued for a payment
+func (r *invoiceRepository) GetByPaymentID(ctx context.Context, paymentID uuid.UUID) (domain.Invoice, error) {
+	dbInvoice, err := r.store.queries.GetInvoiceByPaymentID(ctx, r.store.db, pgtype.UUID{Bytes: paymentID, Valid: true})
+	if err != nil {
+		return domain.Invoice{}, invoiceError(err, paymentID.String())
+	}
+	return r.withLineItems(ctx, dbInvoice)
+}
+
+// List retrieves invoices matching the filter, newest first
+func (r *invoiceRepository) List(ctx context.Context, filter domain.InvoiceFilter, after *domain.InvoiceCursor, limit int) ([]domain.Invoice, error) {
+	params := pgstore.ListInvoicesParams{
+		UserID:    pgtype.Text{String: filter.UserID, Valid: filter.UserID != ""},
+		PageLimit: int32(limit),
+	}
+	if after != nil {
+		params.CursorYear = pgtype.Int4{Int32: after.Year, Valid: true}
+		params.CursorSequence = pgtype.Int4{Int32: after.Sequence, Valid: true}
+	}
+
+	dbInvoices, err := r.store.queries.ListInvoices(ctx, r.store.db, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+	if len(dbInvoices) == 0 {
+		return []domain.Invoice{}, nil
+	}
+
+	ids := make([]pgtype.UUID, len(dbInvoices))
+	for i, dbInvoice := range dbInvoices {
+		ids[i] = dbInvoice.ID
+	}
+	dbLines, err := r.store.queries.ListInvoiceLineItems(ctx, r.store.db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoice line items: %w", err)
+	}
+	lines := make(map[uuid.UUID][]domain.InvoiceLineItem, len(dbInvoices))
+	for _, line := range dbLines {
+		lines[line.InvoiceID.Bytes] = append(lines[line.InvoiceID.Bytes], convertInvoiceLineItemFromDB(line))
+	}
+
+	invoices := make([]domain.Invoice, len(dbInvoices))
+	for i, dbInvoice := range dbInvoices {
+		invoices[i] = convertInvoiceFromDB(dbInvoice)
+		invoices[i].LineItems = lines[invoices[i].ID]
+	}
+	return invoices, nil
+}
+
+// withLineItems loads the line items of an invoice
+func (r *invoiceRepository) withLineItems(ctx context.Context, dbInvoice *pgstore.Invoice) (domain.Invoice, error) {
+	dbLines, err := r.store.queries.ListInvoiceLineItems(ctx, r.store.db, []pgtype.UUID{dbInvoice.ID})
+	if err != nil {
+		return domain.Invoice{}, fmt.Errorf("failed to list invoice line items: %w", err)
+	}
+
+	invoice := convertInvoiceFromDB(dbInvoice)
+	invoice.LineItems = make([]domain.InvoiceLineItem, len(dbLines))
+	for i, line := range dbLines {
+		invoice.LineItems[i] = convertInvoiceLineItemFromDB(line)
+	}
+	return invoice, nil
+}
+
+// invoiceError converts a missing row into a domain not-found error
+func invoiceError(err error, id string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("invoice", id)
+	}
+	return fmt.Errorf("failed to get invoice: %w", err)
+}
+
+// taxRateRepository implements repository.TaxRateRepository
+type taxRateRepository struct {
+	store *Store
+}
+
+// GetRate retrieves the rate for a region, falling back to the country-wide rate
+func (r *taxRateRepository) GetRate(ctx context.Context, countryCode, region string) (domain.TaxRate, error) {
+	dbRate, err := r.store.queries.GetTaxRate(ctx, r.store.db, pgstore.GetTaxRateParams{
+		CountryCode: countryCode,
+		Region:      region,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			id := countryCode
+			if region != "" {
+				id += "-" + region
+			}
+			return domain.TaxRate{}, domain.NewNotFoundError("tax rate", id)
+		}
+		return domain.TaxRate{}, fmt.Errorf("failed to get tax rate: %w", err)
+	}
+	return convertTaxRateFromDB(dbRate), nil
+}
+
+// List retrieves all tax rates
+func (r *taxRateRepository) List(ctx context.Context) ([]domain.TaxRate, error) {
+	dbRates, err := r.store.queries.ListTaxRates(ctx, r.store.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tax rates: %w", err)
+	}
+
+	rates := make([]domain.TaxRate, len(dbRates))
+	for i, dbRate := range dbRates {
+		rates[i] = convertTaxRateFromDB(dbRate)
+	}
+	return rates, nil
+}
+
+// Upsert creates or updates the rate of a country or region
+func (r *taxRateRepository) Upsert(ctx context.Context, rate domain.TaxRate) (domain.TaxRate, error) {
+	return r.upsert(ctx, r.store.db, rate)
+}
+
+// BulkUpsert creates or updates multiple rates in one transaction so that a
+// failed import leaves the previous table in place
+func (r *taxRateRepository) BulkUpsert(ctx context.Context, rates []domain.TaxRate) error {
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, rate := range rates {
+		if _, err := r.upsert(ctx, tx, rate); err != nil {
+			return fmt.Errorf("failed to upsert tax rate %s %s: %w", rate.CountryCode, rate.Region, err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Delete deletes the rate of a country or region
+func (r *taxRateRepository) Delete(ctx context.Context, countryCode, region string) error {
+	return r.store.queries.DeleteTaxRate(ctx, r.store.db, pgstore.DeleteTaxRateParams{
+		CountryCode: countryCode,
+		Region:      region,
+	})
+}
+
+func (r *taxRateRepository) upsert(ctx context.Context, db pgstore.DBTX, rate domain.TaxRate) (domain.TaxRate, error) {
+	dbRate, err := r.store.queries.UpsertTaxRate(ctx, db, pgstore.UpsertTaxRateParams{
+		CountryCode:     rate.CountryCode,
+		Region:          rate.Region,
+		Name:            rate.Name,
+		RatePercent:     percentToNumeric(rate.RatePercent),
+		Inclusive:       rate.Inclusive,
+		DigitalServices: rate.DigitalServices,
+		ReverseCharge:   rate.ReverseCharge,
+	})
+	if err != nil {
+		return domain.TaxRate{}, err
+	}
+	return convertTaxRateFromDB(dbRate), nil
+}
+
+// customerRepository implements repository.CustomerRepository
+type customerRepository struct {
+	store *Store
+}
+
+// GetByUserID retrieves the billing profile of a user
+func (r *customerRepository) GetByUserID(ctx context.Context, userID string) (domain.Customer, error) {
+	dbCustomer, err := r.store.queries.GetCustomerByUserID(ctx, r.store.db, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Customer{}, domain.NewNotFoundError("customer", userID)
+		}
+		return domain.Customer{}, fmt.Errorf("failed to get customer: %w", err)
+	}
+	return convertCustomerFromDB(dbCustomer), nil
+}
+
+// Upsert creates or updates the billing profile of a user
+func (r *customerRepository) Upsert(ctx context.Context, customer domain.Customer) (domain.Customer, error) {
+	params := pgstore.UpsertCustomerParams{
+		UserID:            customer.UserID,
+		Name:              pgtype.Text{String: customer.Name, Valid: customer.Name != ""},
+		Email:             pgtype.Text{String: customer.Email, Valid: customer.Email != ""},
+		TaxID:             pgtype.Text{String: customer.TaxID, Valid: customer.TaxID != ""},
+		PreferredCurrency: pgtype.Text{String: customer.PreferredCurrency, Valid: customer.PreferredCurrency != ""},
+	}
+	if !customer.Address.IsZero() {
+		address, err := json.Marshal(customer.Address)
+		if err != nil {
+			return domain.Customer{}, fmt.Errorf("failed to encode customer address: %w", err)
+		}
+		params.Address = address
+	}
+
+	dbCustomer, err := r.store.queries.UpsertCustomer(ctx, r.store.db, params)
+	if err != nil {
+		return domain.Customer{}, fmt.Errorf("failed to upsert customer: %w", err)
+	}
+	return convertCustomerFromDB(dbCustomer), nil
+}
+
+// SetProviderCustomerID records the customer's ID at the billing provider
+func (r *customerRepository) SetProviderCustomerID(ctx context.Context, id uuid.UUID, provider, providerCustomerID string) (domain.Customer, error) {
+	dbCustomer, err := r.store.queries.SetCustomerProviderID(ctx, r.store.db, pgstore.SetCustomerProviderIDParams{
+		ID:                 pgtype.UUID{Bytes: id, Valid: true},
+		Provider:           pgtype.Text{String: provider, Valid: provider != ""},
+		ProviderCustomerID: pgtype.Text{String: providerCustomerID, Valid: providerCustomerID != ""},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Customer{}, domain.NewNotFoundError("customer", id.String())
+		}
+		if isUniqueViolation(err) {
+			return domain.Customer{}, domain.NewAlreadyExistsError("provider customer", providerCustomerID)
+		}
+		return domain.Customer{}, fmt.Errorf("failed to set provider customer ID: %w", err)
+	}
+	return convertCustomerFromDB(dbCustomer), nil
+}
+
+// AddPaymentMethod saves a tokenized payment method for a customer
+func (r *customerRepository) AddPaymentMethod(ctx context.Context, method domain.SavedPaymentMethod) (domain.SavedPaymentMethod, error) {
+	dbMethod, err := r.store.queries.CreatePaymentMethod(ctx, r.store.db, pgstore.CreatePaymentMethodParams{
+		CustomerID:              pgtype.UUID{Bytes: method.CustomerID, Valid: true},
+		Provider:                method.Provider,
+		ProviderPaymentMethodID: method.ProviderPaymentMethodID,
+		Type:                    string(method.Type),
+		Brand:                   pgtype.Text{String: method.Brand, Valid: method.Brand != ""},
+		Last4:                   pgtype.Text{String: method.Last4, Valid: method.Last4 != ""},
+		ExpMonth:                pgtype.Int4{Int32: method.ExpMonth, Valid: method.ExpMonth != 0},
+		ExpYear:                 pgtype.Int4{Int32: method.ExpYear, Valid: method.ExpYear != 0},
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.SavedPaymentMethod{}, domain.NewAlreadyExistsError("payment method", method.ProviderPaymentMethodID)
+		}
+		return domain.SavedPaymentMethod{}, fmt.Errorf("failed to add payment method: %w", err)
+	}
+	return convertPaymentMethodFromDB(dbMethod), nil
+}
+
+// GetPaymentMethod retrieves a saved payment method by ID
+func (r *customerRepository) GetPaymentMethod(ctx context.Context, id uuid.UUID) (domain.SavedPaymentMethod, error) {
+	dbMethod, err := r.store.queries.GetPaymentMethodByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return domain.SavedPaymentMethod{}, paymentMethodError(err, id.String(), "failed to get payment method")
+	}
+	return convertPaymentMethodFromDB(dbMethod), nil
+}
+
+// GetDefaultPaymentMethod retrieves a customer's default payment method
+func (r *customerRepository) GetDefaultPaymentMethod(ctx context.Context, customerID uuid.UUID) (domain.SavedPaymentMethod, error) {
+	dbMethod, err := r.store.queries.GetDefaultPaymentMethod(ctx, r.store.db, pgtype.UUID{Bytes: customerID, Valid: true})
+	if err != nil {
+		return domain.SavedPaymentMethod{}, paymentMethodError(err, "default for customer "+customerID.String(), "failed to get default payment method")
+	}
+	return convertPaymentMethodFromDB(dbMethod), nil
+}
+
+// ListPaymentMethods retrieves a customer's saved payment methods, default first
+func (r *customerRepository) ListPaymentMethods(ctx context.Context, customerID uuid.UUID) ([]domain.SavedPaymentMethod, error) {
+	dbMethods, err := r.store.queries.ListPaymentMethodsByCustomer(ctx, r.store.db, pgtype.UUID{Bytes: customerID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+
+	methods := make([]domain.SavedPaymentMethod, len(dbMethods))
+	for i, dbMethod := range dbMethods {
+		methods[i] = convertPaymentMethodFromDB(dbMethod)
+	}
+	return methods, nil
+}
+
+// SetDefaultPaymentMethod clears the customer's current default and sets the
+// new one in one transaction, so there is never more than one default
+func (r *customerRepository) SetDefaultPaymentMethod(ctx context.Context, customerID, methodID uuid.UUID) (domain.SavedPaymentMethod, error) {
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return domain.SavedPaymentMethod{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.store.queries.ClearDefaultPaymentMethod(ctx, tx, pgtype.UUID{Bytes: customerID, Valid: true}); err != nil {
+		return domain.SavedPaymentMethod{}, fmt.Errorf("failed to clear default payment method: %w", err)
+	}
+
+	dbMethod, err := r.store.queries.SetDefaultPaymentMethod(ctx, tx, pgstore.SetDefaultPaymentMethodParams{
+		ID:         pgtype.UUID{Bytes: methodID, Valid: true},
+		CustomerID: pgtype.UUID{Bytes: customerID, Valid: true},
+	})
+	if err != nil {
+		return domain.SavedPaymentMethod{}, paymentMethodError(err, methodID.String(), "failed to set default payment method")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return domain.SavedPaymentMethod{}, fmt.Errorf("failed to commit default payment method: %w", err)
+	}
+	return convertPaymentMethodFromDB(dbMethod), nil
+}
+
+// DeletePaymentMethod removes a saved payment method
+func (r *customerRepository) DeletePaymentMethod(ctx context.Context, id uuid.UUID) error {
+	if err := r.store.queries.DeletePaymentMethod(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to delete payment method: %w", err)
+	}
+	return nil
+}
+
+// paymentMethodError converts a missing row into a domain not-found error
+func paymentMethodError(err error, id, message string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("payment method", id)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// subscriptionRepository implements repository.SubscriptionRepository.
+// Subscriptions store the plan's string ID; the domain's plan UUID is
+// translated with plan_uuid().
+type subscriptionRepository struct {
+	store *Store
+}
+
+// Create creates a new subscription
+func (r *subscriptionRepository) Create(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
+	planID, err := r.planID(ctx, sub.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := encodeSubscriptionMetadata(sub.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pgstore.CreateSubscriptionParams{
+		UserID:                 sub.UserID,
+		PlanID:                 planID,
+		PlanVersion:            pgtype.Int4{Int32: sub.PlanVersion, Valid: sub.PlanVersion > 0},
+		Status:                 sub.Status,
+		CurrentPeriodStart:     pgtype.Timestamptz{Time: sub.CurrentPeriodStart, Valid: true},
+		CurrentPeriodEnd:       pgtype.Timestamptz{Time: sub.CurrentPeriodEnd, Valid: true},
+		CancelAtPeriodEnd:      sub.CancelAtPeriodEnd,
+		ExternalSubscriptionID: pgtype.Text{String: sub.ExternalSubscriptionID, Valid: sub.ExternalSubscriptionID != ""},
+		Metadata:               metadata,
+	}
+	if sub.FamilyID != nil {
+		params.FamilyID = pgtype.Text{String: *sub.FamilyID, Valid: true}
+	}
+
+	dbSub, err := r.store.queries.CreateSubscription(ctx, r.store.db, params)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, domain.NewAlreadyExistsError("subscription", sub.ExternalSubscriptionID)
+		}
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return convertSubscriptionFromDB(dbSub), nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *subscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	dbSub, err := r.store.queries.GetSubscriptionByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		return nil, subscriptionError(err, id.String(), "failed to get subscription")
+	}
+	return convertSubscriptionFromDB(dbSub), nil
+}
+
+// GetByExternalID retrieves a subscription by external subscription ID
+func (r *subscriptionRepository) GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error) {
+	dbSub, err := r.store.queries.GetSubscriptionByExternalID(ctx, r.store.db, pgtype.Text{String: externalID, Valid: true})
+	if err != nil {
+		return nil, subscriptionError(err, externalID, "failed to get subscription")
+	}
+	return convertSubscriptionFromDB(dbSub), nil
+}
+
+// GetByUserID retrieves all subscriptions for a user
+func (r *subscriptionRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Subscription, error) {
+	dbSubs, err := r.store.queries.GetSubscriptionsByUserID(ctx, r.store.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions by user: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// GetByStatus retrieves subscriptions with a specific status
+func (r *subscriptionRepository) GetByStatus(ctx context.Context, status string) ([]*domain.Subscription, error) {
+	dbSubs, err := r.store.queries.GetSubscriptionsByStatus(ctx, r.store.db, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions by status: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// Update updates the status, period and metadata of a subscription
+func (r *subscriptionRepository) Update(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
+	metadata, err := encodeSubscriptionMetadata(sub.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pgstore.UpdateSubscriptionParams{
+		ID:                 pgtype.UUID{Bytes: sub.ID, Valid: true},
+		Status:             sub.Status,
+		CurrentPeriodStart: pgtype.Timestamptz{Time: sub.CurrentPeriodStart, Valid: true},
+		CurrentPeriodEnd:   pgtype.Timestamptz{Time: sub.CurrentPeriodEnd, Valid: true},
+		CancelAtPeriodEnd:  sub.CancelAtPeriodEnd,
+		Metadata:           metadata,
+	}
+	if sub.CancelledAt != nil {
+		params.CancelledAt = pgtype.Timestamptz{Time: *sub.CancelledAt, Valid: true}
+	}
+
+	dbSub, err := r.store.queries.UpdateSubscription(ctx, r.store.db, params)
+	if err != nil {
+		return nil, subscriptionError(err, sub.ID.String(), "failed to update subscription")
+	}
+	return convertSubscriptionFromDB(dbSub), nil
+}
+
+// Delete deletes a subscription
+func (r *subscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.store.queries.DeleteSubscription(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
+
+// GetExpiringSubscriptions retrieves active subscriptions whose period ends before a given date
+func (r *subscriptionRepository) GetExpiringSubscriptions(ctx context.Context, beforeDate time.Time) ([]*domain.Subscription, error) {
+	dbSubs, err := r.store.queries.GetExpiringSubscriptions(ctx, r.store.db, pgtype.Timestamptz{Time: beforeDate, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring subscriptions: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// GetActiveSubscriptions retrieves all active subscriptions
+func (r *subscriptionRepository) GetActiveSubscriptions(ctx context.Context) ([]*domain.Subscription, error) {
+	dbSubs, err := r.store.queries.GetActiveSubscriptions(ctx, r.store.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active subscriptions: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// GetSubscriptionsByPlan retrieves subscriptions for a specific plan
+func (r *subscriptionRepository) GetSubscriptionsByPlan(ctx context.Context, planID uuid.UUID) ([]*domain.Subscription, error) {
+	code, err := r.planID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	dbSubs, err := r.store.queries.GetSubscriptionsByPlan(ctx, r.store.db, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions by plan: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// ListCreatedBetween retrieves subscriptions created in [from, to), oldest first
+func (r *subscriptionRepository) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*domain.Subscription, error) {
+	dbSubs, err := r.store.queries.ListSubscriptionsCreatedBetween(ctx, r.store.db, pgstore.ListSubscriptionsCreatedBetweenParams{
+		CreatedAfter:  pgtype.Timestamptz{Time: from, Valid: true},
+		CreatedBefore: pgtype.Timestamptz{Time: to, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	return convertSubscriptionsFromDB(dbSubs), nil
+}
+
+// planID resolves a plan UUID to the plan's string ID
+func (r *subscriptionRepository) planID(ctx context.Context, planUUID uuid.UUID) (string, error) {
+	id, err := r.store.queries.GetPlanIDByUUID(ctx, r.store.db, pgtype.UUID{Bytes: planUUID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.NewNotFoundError("plan", planUUID.String())
+		}
+		return "", fmt.Errorf("failed to resolve plan: %w", err)
+	}
+	return id, nil
+}
+
+// subscriptionError converts a missing row into a domain not-found error
+func subscriptionError(err error, id, message string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("subscription", id)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// encodeSubscriptionMetadata encodes subscription metadata, storing NULL when there is none
+func encodeSubscriptionMetadata(metadata map[string]interface{}) ([]byte, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode subscription metadata: %w", err)
+	}
+	return data, nil
+}
+
+// pricingZoneRepository implements repository.PricingZoneRepository
+type pricingZoneRepository struct {
+	store *Store
+}
+
+// GetByISOCode retrieves a pricing zone by ISO country code
+func (r *pricingZoneRepository) GetByISOCode(ctx context.Context, isoCode string) (domain.PricingZone, error) {
+	pricingZone, err := r.store.queries.GetPricingZoneByISOCode(ctx, r.store.db, isoCode)
+	if err != nil {
+		return domain.PricingZone{}, pricingZoneError(err, isoCode, "failed to get pricing zone")
+	}
+	return convertPricingZoneFromDB(pricingZone), nil
+}
+
+// GetByCountry retrieves a pricing zone by country name
+func (r *pricingZoneRepository) GetByCountry(ctx context.Context, country string) (domain.PricingZone, error) {
+	pricingZone, err := r.store.queries.GetPricingZoneByCountry(ctx, r.store.db, country)
+	if err != nil {
+		return domain.PricingZone{}, pricingZoneError(err, country, "failed to get pricing zone")
+	}
+	return convertPricingZoneFromDB(pricingZone), nil
+}
+
+// GetByZone retrieves all pricing zones for a specific zone type
+func (r *pricingZoneRepository) GetByZone(ctx context.Context, zone string) ([]domain.PricingZone, error) {
+	pricingZones, err := r.store.queries.GetPricingZonesByZone(ctx, r.store.db, zone)
+	if err != nil {
+		return nil, err
+	}
+	return convertPricingZonesFromDB(pricingZones), nil
+}
+
+// List retrieves all pricing zones
+func (r *pricingZoneRepository) List(ctx context.Context) ([]domain.PricingZone, error) {
+	pricingZones, err := r.store.queries.ListPricingZones(ctx, r.store.db)
+	if err != nil {
+		return nil, err
+	}
+	return convertPricingZonesFromDB(pricingZones), nil
+}
+
+// Upsert creates or updates a pricing zone
+func (r *pricingZoneRepository) Upsert(ctx context.Context, zone domain.PricingZone) (domain.PricingZone, error) {
+	params := pgstore.UpsertPricingZoneParams{
+		Country:                 zone.Country,
+		IsoCode:                 zone.ISOCode,
+		Zone:                    zone.Zone,
+		ZoneName:                zone.ZoneName,
+		WorldBankClassification: pgtype.Text{String: zone.WorldBankClassification, Valid: zone.WorldBankClassification != ""},
+		GniPerCapitaThreshold:   pgtype.Text{String: zone.GNIPerCapitaThreshold, Valid: zone.GNIPerCapitaThreshold != ""},
+		PricingMultiplier:       pgtype.Numeric{Int: big.NewInt(int64(math.Round(zone.PricingMultiplier * 100))), Valid: true, Exp: -2},
+	}
+
+	pricingZone, err := r.store.queries.UpsertPricingZone(ctx, r.store.db, params)
+	if err != nil {
+		return domain.PricingZone{}, err
+	}
+	return convertPricingZoneFromDB(pricingZone), nil
+}
+
+// BulkUpsert creates or updates multiple pricing zones
+func (r *pricingZoneRepository) BulkUpsert(ctx context.Context, zones []domain.PricingZone) error {
+	for _, zone := range zones {
+		_, err := r.Upsert(ctx, zone)
+		if err != nil {
+			return fmt.Errorf("failed to upsert zone %s: %w", zone.ISOCode, err)
+		}
+	}
+	return nil
+}
+
+// Delete deletes a pricing zone by ISO code
 func (r *pricingZoneRepository) Delete(ctx context.Context, isoCode string) error {
 	return r.store.queries.DeletePricingZone(ctx, r.store.db, isoCode)
 }
 
+// pricingZoneError converts a missing row into a domain not-found error
+func pricingZoneError(err error, id, message string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.NewNotFoundError("pricing zone", id)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// usageRepository implements repository.UsageRepository
+type usageRepository struct {
+	store *Store
+}
+
+// Create creates a new usage record
+func (r *usageRepository) Create(ctx context.Context, usage domain.Usage) error {
+	var metadata []byte
+	if len(usage.Metadata) > 0 {
+		data, err := json.Marshal(usage.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode usage metadata: %w", err)
+		}
+		metadata = data
+	}
+
+	params := pgstore.CreateUsageParams{
+		UserID:       usage.UserID,
+		FeatureCode:  usage.FeatureCode,
+		ResourceType: usage.ResourceType,
+		ResourceSize: usage.ResourceSize,
+		Operation:    pgtype.Text{String: usage.Operation, Valid: usage.Operation != ""},
+		Metadata:     metadata,
+	}
+	if usage.FamilyID != nil {
+		params.FamilyID = pgtype.Text{String: *usage.FamilyID, Valid: true}
+	}
+
+	if err := r.store.queries.CreateUsage(ctx, r.store.db, params); err != nil {
+		return fmt.Errorf("failed to create usage: %w", err)
+	}
+	return nil
+}
+
+// GetCurrentUsage sums a user's usage of a resource recorded within the period
+func (r *usageRepository) GetCurrentUsage(ctx context.Context, userID, featureCode, resourceType string, period time.Duration) (int64, error) {
+	total, err := r.store.queries.GetCurrentUsage(ctx, r.store.db, pgstore.GetCurrentUsageParams{
+		UserID:       userID,
+		FeatureCode:  featureCode,
+		ResourceType: resourceType,
+		Since:        pgtype.Timestamptz{Time: time.Now().Add(-period), Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current usage: %w", err)
+	}
+
+	// SUM over a BIGINT column is a NUMERIC
+	switch v := total.(type) {
+	case int64:
+		return v, nil
+	case pgtype.Numeric:
+		n, err := v.Int64Value()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read current usage: %w", err)
+		}
+		return n.Int64, nil
+	default:
+		return 0, fmt.Errorf("unexpected usage total type %T", total)
+	}
+}
+
+// GetUsageHistory lists a user's usage of a resource recorded within the period, newest first
+func (r *usageRepository) GetUsageHistory(ctx context.Context, userID, featureCode, resourceType string, period time.Duration) ([]domain.Usage, error) {
+	dbUsage, err := r.store.queries.GetUsageHistory(ctx, r.store.db, pgstore.GetUsageHistoryParams{
+		UserID:       userID,
+		FeatureCode:  featureCode,
+		ResourceType: resourceType,
+		Since:        pgtype.Timestamptz{Time: time.Now().Add(-period), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage history: %w", err)
+	}
+	return convertUsagesFromDB(dbUsage), nil
+}
+
+// DeleteUsage deletes usage records for a user, feature, and resource type
+func (r *usageRepository) DeleteUsage(ctx context.Context, userID, featureCode, resourceType string) error {
+	err := r.store.queries.DeleteUsage(ctx, r.store.db, pgstore.DeleteUsageParams{
+		UserID:       userID,
+		FeatureCode:  featureCode,
+		ResourceType: resourceType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete usage: %w", err)
+	}
+	return nil
+}
+
+// GetUsageByID gets a usage record by ID
+func (r *usageRepository) GetUsageByID(ctx context.Context, id uuid.UUID) (*domain.Usage, error) {
+	dbUsage, err := r.store.queries.GetUsageByID(ctx, r.store.db, pgtype.UUID{Bytes: id, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("usage", id.String())
+		}
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	usage := convertUsageFromDB(dbUsage)
+	return &usage, nil
+}
+
+// ListUsageByUser gets usage records for a user, newest first
+func (r *usageRepository) ListUsageByUser(ctx context.Context, userID string, limit, offset int) ([]domain.Usage, error) {
+	dbUsage, err := r.store.queries.ListUsageByUser(ctx, r.store.db, pgstore.ListUsageByUserParams{
+		UserID:      userID,
+		LimitCount:  int32(limit),
+		OffsetCount: int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	return convertUsagesFromDB(dbUsage), nil
+}
+
+// ledgerRepository implements repository.LedgerRepository
+type ledgerRepository struct {
+	store *Store
+}
+
+// Post records a journal entry and its postings in one transaction
+func (r *ledgerRepository) Post(ctx context.Context, entry *domain.JournalEntry) (bool, error) {
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	posted, err := r.postEntry(ctx, tx, entry)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit journal entry: %w", err)
+	}
+	return posted, nil
+}
+
+// UpdatePaymentStatus changes a payment's status and posts the entry the
+// change implies in one transaction, so that neither is recorded without
+// the other
+func (r *ledgerRepository) UpdatePaymentStatus(ctx context.Context, paymentID, status string, entry *domain.JournalEntry) error {
+	paymentUUID, err := uuid.Parse(paymentID)
+	if err != nil {
+		return fmt.Errorf("invalid payment ID: %w", err)
+	}
+
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.store.queries.UpdatePaymentStatus(ctx, tx, pgstore.UpdatePaymentStatusParams{
+		ID:     pgtype.UUID{Bytes: paymentUUID, Valid: true},
+		Status: status,
+	}); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NewNotFoundError("payment", paymentID)
+		}
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	if entry != nil {
+		if _, err := r.postEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit payment status: %w", err)
+	}
+	return nil
+}
+
+// postEntry writes an entry and its postings within a transaction. The
+// entry is validated first so that an unbalanced entry is never stored.
+func (r *ledgerRepository) postEntry(ctx context.Context, tx pgx.Tx, entry *domain.JournalEntry) (bool, error) {
+	if err := entry.Validate(); err != nil {
+		return false, err
+	}
+
+	params := pgstore.InsertJournalEntryParams{
+		Reference:   entry.Reference,
+		Kind:        string(entry.Kind),
+		CustomerID:  pgtype.Text{String: entry.CustomerID, Valid: entry.CustomerID != ""},
+		Currency:    entry.Currency,
+		Description: pgtype.Text{String: entry.Description, Valid: entry.Description != ""},
+		OccurredAt:  pgtype.Timestamp{Time: entry.OccurredAt, Valid: true},
+	}
+	if entry.PaymentID != nil {
+		params.PaymentID = pgtype.UUID{Bytes: *entry.PaymentID, Valid: true}
+	}
+
+	dbEntry, err := r.store.queries.InsertJournalEntry(ctx, tx, params)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Already posted, e.g. by a replayed webhook
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create journal entry: %w", err)
+	}
+
+	for _, posting := range entry.Postings {
+		if _, err := r.store.queries.InsertLedgerPosting(ctx, tx, pgstore.InsertLedgerPostingParams{
+			EntryID:     dbEntry.ID,
+			AccountCode: posting.AccountCode,
+			Direction:   string(posting.Direction),
+			AmountCents: posting.AmountCents,
+			Currency:    entry.Currency,
+			CustomerID:  params.CustomerID,
+		}); err != nil {
+			return false, fmt.Errorf("failed to create ledger posting: %w", err)
+		}
+	}
+
+	entry.ID = dbEntry.ID.Bytes
+	entry.CreatedAt = dbEntry.CreatedAt.Time
+	return true, nil
+}
+
+// GetEntryByReference retrieves a posted entry with its postings
+func (r *ledgerRepository) GetEntryByReference(ctx context.Context, reference string) (*domain.JournalEntry, error) {
+	dbEntry, err := r.store.queries.GetJournalEntryByReference(ctx, r.store.db, reference)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.NewNotFoundError("journal entry", reference)
+		}
+		return nil, fmt.Errorf("failed to get journal entry: %w", err)
+	}
+
+	entries, err := r.withPostings(ctx, []*pgstore.JournalEntry{dbEntry})
+	if err != nil {
+		return nil, err
+	}
+	return entries[0], nil
+}
+
+// ListEntriesByPayment retrieves the entries posted for a payment, oldest first
+func (r *ledgerRepository) ListEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]*domain.JournalEntry, error) {
+	dbEntries, err := r.store.queries.ListJournalEntriesByPayment(ctx, r.store.db, pgtype.UUID{Bytes: paymentID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+	return r.withPostings(ctx, dbEntries)
+}
+
+// withPostings loads the postings of a batch of entries
+func (r *ledgerRepository) withPostings(ctx context.Context, dbEntries []*pgstore.JournalEntry) ([]*domain.JournalEntry, error) {
+	if len(dbEntries) == 0 {
+		return []*domain.JournalEntry{}, nil
+	}
+
+	ids := make([]pgtype.UUID, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		ids[i] = dbEntry.ID
+	}
+	dbPostings, err := r.store.queries.ListLedgerPostingsByEntries(ctx, r.store.db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger postings: %w", err)
+	}
+	postings := make(map[uuid.UUID][]domain.Posting, len(dbEntries))
+	for _, posting := range dbPostings {
+		postings[posting.EntryID.Bytes] = append(postings[posting.EntryID.Bytes], convertPostingFromDB(posting))
+	}
+
+	entries := make([]*domain.JournalEntry, len(dbEntries))
+	for i, dbEntry := range dbEntries {
+		entries[i] = convertJournalEntryFromDB(dbEntry)
+		entries[i].Postings = postings[entries[i].ID]
+	}
+	return entries, nil
+}
+
+// Balances totals postings per account and currency
+func (r *ledgerRepository) Balances(ctx context.Context, customerID, accountCode string) ([]domain.AccountBalance, error) {
+	rows, err := r.store.queries.GetLedgerBalances(ctx, r.store.db, pgstore.GetLedgerBalancesParams{
+		CustomerID:  pgtype.Text{String: customerID, Valid: customerID != ""},
+		AccountCode: pgtype.Text{String: accountCode, Valid: accountCode != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger balances: %w", err)
+	}
+
+	balances := make([]domain.AccountBalance, len(rows))
+	for i, row := range rows {
+		balances[i] = domain.AccountBalance{
+			AccountCode:   row.Code,
+			AccountName:   row.Name,
+			AccountType:   domain.AccountType(row.Type),
+			NormalBalance: domain.PostingDirection(row.NormalBalance),
+			Currency:      row.Currency,
+			DebitCents:    row.DebitCents,
+			CreditCents:   row.CreditCents,
+		}
+	}
+	return balances, nil
+}
+
+// ListUnbalancedEntries retrieves the entries whose debits and credits differ
+func (r *ledgerRepository) ListUnbalancedEntries(ctx context.Context) ([]domain.UnbalancedEntry, error) {
+	rows, err := r.store.queries.ListUnbalancedJournalEntries(ctx, r.store.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unbalanced journal entries: %w", err)
+	}
+
+	entries := make([]domain.UnbalancedEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = domain.UnbalancedEntry{
+			EntryID:     row.ID.Bytes,
+			Reference:   row.Reference,
+			Currency:    row.Currency,
+			DebitCents:  row.DebitCents,
+			CreditCents: row.CreditCents,
+		}
+	}
+	return entries, nil
+}
+
+// revenueRepository implements repository.RevenueRepository
+type revenueRepository struct {
+	store *Store
+}
+
+// Snapshot rebuilds the snapshot of a day in one transaction, so that
+// reports never see a partial day
+func (r *revenueRepository) Snapshot(ctx context.Context, date time.Time) (int64, error) {
+	day := snapshotDay(date)
+
+	tx, err := r.store.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.store.queries.DeleteRevenueSnapshot(ctx, tx, pgtype.Date{Time: day, Valid: true}); err != nil {
+		return 0, fmt.Errorf("failed to delete revenue snapshot: %w", err)
+	}
+	count, err := r.store.queries.InsertRevenueSnapshot(ctx, tx, pgstore.InsertRevenueSnapshotParams{
+		SnapshotDate: pgtype.Date{Time: day, Valid: true},
+		AsOf:         pgtype.Timestamptz{Time: day.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create revenue snapshot: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit revenue snapshot: %w", err)
+	}
+	return count, nil
+}
+
+// LatestSnapshotDate returns the most recent snapshot day on or before a day
+func (r *revenueRepository) LatestSnapshotDate(ctx context.Context, onOrBefore time.Time) (*time.Time, error) {
+	date, err := r.store.queries.GetLatestRevenueSnapshotDate(ctx, r.store.db, pgtype.Date{Time: snapshotDay(onOrBefore), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest revenue snapshot: %w", err)
+	}
+	if !date.Valid {
+		return nil, nil
+	}
+	return &date.Time, nil
+}
+
+// ListSnapshot retrieves the subscriptions recorded for a day
+func (r *revenueRepository) ListSnapshot(ctx context.Context, date time.Time) ([]domain.RevenueSnapshot, error) {
+	rows, err := r.store.queries.ListRevenueSnapshot(ctx, r.store.db, pgtype.Date{Time: snapshotDay(date), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revenue snapshot: %w", err)
+	}
+
+	snapshots := make([]domain.RevenueSnapshot, len(rows))
+	for i, row := range rows {
+		snapshots[i] = convertRevenueSnapshotFromDB(row)
+	}
+	return snapshots, nil
+}
+
+// snapshotDay truncates a time to its UTC day
+func snapshotDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// auditRepository implements repository.AuditRepository
+type auditRepository struct {
+	store *Store
+}
+
+// Record appends an audit event
+func (r *auditRepository) Record(ctx context.Context, event domain.AuditEvent) (*domain.AuditEvent, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	if event.Changes == nil {
+		event.Changes = map[string]domain.AuditFieldChange{}
+	}
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit changes: %w", err)
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	dbEvent, err := r.store.queries.InsertAuditEvent(ctx, r.store.db, pgstore.InsertAuditEventParams{
+		EntityType: string(event.EntityType),
+		EntityID:   event.EntityID,
+		Action:     event.Action,
+		ActorType:  string(event.ActorType),
+		ActorID:    event.ActorID,
+		Changes:    changes,
+		RequestID:  event.RequestID,
+		Reason:     event.Reason,
+		OccurredAt: pgtype.Timestamp{Time: event.OccurredAt, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert audit event: %w", err)
+	}
+	return convertAuditEventFromDB(dbEvent), nil
+}
+
+// List retrieves one page of audit events, newest first
+func (r *auditRepository) List(ctx context.Context, filter domain.AuditFilter, after *domain.AuditCursor, limit int) ([]*domain.AuditEvent, error) {
+	params := pgstore.ListAuditEventsParams{
+		EntityType: pgtype.Text{String: string(filter.EntityType), Valid: filter.EntityType != ""},
+		EntityID:   pgtype.Text{String: filter.EntityID, Valid: filter.EntityID != ""},
+		ActorID:    pgtype.Text{String: filter.ActorID, Valid: filter.ActorID != ""},
+		PageLimit:  int32(limit),
+	}
+	if after != nil {
+		params.CursorOccurredAt = pgtype.Timestamp{Time: after.OccurredAt, Valid: true}
+		params.CursorID = pgtype.UUID{Bytes: after.ID, Valid: true}
+	}
+
+	dbEvents, err := r.store.queries.ListAuditEvents(ctx, r.store.db, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	events := make([]*domain.AuditEvent, len(dbEvents))
+	for i, dbEvent := range dbEvents {
+		events[i] = convertAuditEventFromDB(dbEvent)
+	}
+	return events, nil
+}
+
 // Helper functions to convert between domain and database models
 func convertPricingZoneFromDB(dbZone *pgstore.PricingZone) domain.PricingZone {
 	var multiplier float64
@@ -756,6 +2434,310 @@ func convertPricingZonesFromDB(dbZones []*pgstore.PricingZone) []domain.PricingZ
 	return zones
 }
 
+// convertFeatureFromDB converts a database feature to a domain feature
+func convertFeatureFromDB(dbFeature *pgstore.Feature) (domain.Feature, error) {
+	feature := domain.Feature{
+		Code:          dbFeature.Code,
+		Description:   dbFeature.Description,
+		DefaultLimits: dbFeature.DefaultLimits,
+		Deprecated:    dbFeature.Deprecated,
+		CreatedAt:     dbFeature.CreatedAt.Time,
+		UpdatedAt:     dbFeature.UpdatedAt.Time,
+	}
+	if err := json.Unmarshal(dbFeature.QuotaSchema, &feature.QuotaSchema); err != nil {
+		return domain.Feature{}, fmt.Errorf("failed to decode quota schema of feature %s: %w", dbFeature.Code, err)
+	}
+	return feature, nil
+}
+
+// convertPlanFromDB converts a database plan to a domain plan
+func convertPlanFromDB(dbPlan *pgstore.Plan) domain.Plan {
+	plan := domain.Plan{
+		ID:           domain.PlanUUID(dbPlan.ID),
+		Code:         dbPlan.ID,
+		Name:         dbPlan.Name,
+		Description:  dbPlan.Description.String,
+		FeatureCodes: dbPlan.FeatureCodes,
+		BillingCycle: dbPlan.BillingCycle.String,
+		PriceDollars: float64(dbPlan.PriceCents) / 100, // Convert cents to dollars
+		Currency:     dbPlan.Currency,
+		MaxUsers:     dbPlan.MaxUsers.Int32,
+		UsageLimits:  dbPlan.UsageLimits,
+		Metadata:     dbPlan.Metadata,
+		Active:       dbPlan.Active,
+		Version:      dbPlan.Version,
+		CreatedAt:    dbPlan.CreatedAt.Time,
+		UpdatedAt:    dbPlan.UpdatedAt.Time,
+	}
+	if dbPlan.ArchivedAt.Valid {
+		plan.ArchivedAt = &dbPlan.ArchivedAt.Time
+	}
+	return plan
+}
+
+// convertPlansFromDB converts database plans to domain plans
+func convertPlansFromDB(dbPlans []*pgstore.Plan) []domain.Plan {
+	plans := make([]domain.Plan, len(dbPlans))
+	for i, dbPlan := range dbPlans {
+		plans[i] = convertPlanFromDB(dbPlan)
+	}
+	return plans
+}
+
+// convertPlanVersionFromDB converts a database plan version to a domain plan version
+func convertPlanVersionFromDB(v *pgstore.PlanVersion) domain.PlanVersion {
+	return domain.PlanVersion{
+		PlanCode:     v.PlanID,
+		Version:      v.Version,
+		FeatureCodes: v.FeatureCodes,
+		BillingCycle: v.BillingCycle.String,
+		PriceDollars: float64(v.PriceCents) / 100,
+		Currency:     v.Currency,
+		MaxUsers:     v.MaxUsers.Int32,
+		UsageLimits:  v.UsageLimits,
+		CreatedAt:    v.CreatedAt.Time,
+	}
+}
+
+// convertCheckoutSessionFromDB converts a database checkout session to a domain checkout session
+func convertCheckoutSessionFromDB(dbSession *pgstore.CheckoutSession) domain.CheckoutSession {
+	var multiplier float64
+	if val, err := dbSession.PricingMultiplier.Float64Value(); err == nil && val.Valid {
+		multiplier = val.Float64
+	}
+
+	session := domain.CheckoutSession{
+		ID:                dbSession.ID.Bytes,
+		Provider:          dbSession.Provider,
+		ProviderSessionID: dbSession.ProviderSessionID,
+		PlanCode:          dbSession.PlanID,
+		PlanVersion:       dbSession.PlanVersion,
+		UserID:            dbSession.UserID,
+		CountryCode:       dbSession.CountryCode.String,
+		Region:            dbSession.Region.String,
+		BasePrice:         float64(dbSession.BasePriceCents) / 100,
+		QuotedPrice:       float64(dbSession.QuotedPriceCents) / 100,
+		PricingMultiplier: multiplier,
+		Currency:          dbSession.Currency,
+		TaxID:             dbSession.TaxID.String,
+		Status:            domain.CheckoutSessionStatus(dbSession.Status),
+		URL:               dbSession.Url.String,
+		ExpiresAt:         dbSession.ExpiresAt.Time,
+		CreatedAt:         dbSession.CreatedAt.Time,
+		UpdatedAt:         dbSession.UpdatedAt.Time,
+		Tax: domain.TaxQuote{
+			Name:          dbSession.TaxName.String,
+			RatePercent:   numericToFloat(dbSession.TaxRatePercent),
+			Inclusive:     dbSession.TaxInclusive,
+			ReverseCharge: dbSession.ReverseCharge,
+			TaxCents:      int64(dbSession.TaxCents),
+			TotalCents:    int64(dbSession.TotalCents),
+		},
+	}
+	if dbSession.FamilyID.Valid {
+		session.FamilyID = &dbSession.FamilyID.String
+	}
+	if dbSession.CompletedAt.Valid {
+		session.CompletedAt = &dbSession.CompletedAt.Time
+	}
+	return session
+}
+
+// convertTaxRateFromDB converts a database tax rate to a domain tax rate
+func convertTaxRateFromDB(dbRate *pgstore.TaxRate) domain.TaxRate {
+	return domain.TaxRate{
+		ID:              uuid.UUID(dbRate.ID.Bytes).String(),
+		CountryCode:     dbRate.CountryCode,
+		Region:          dbRate.Region,
+		Name:            dbRate.Name,
+		RatePercent:     numericToFloat(dbRate.RatePercent),
+		Inclusive:       dbRate.Inclusive,
+		DigitalServices: dbRate.DigitalServices,
+		ReverseCharge:   dbRate.ReverseCharge,
+		CreatedAt:       dbRate.CreatedAt.Time,
+		UpdatedAt:       dbRate.UpdatedAt.Time,
+	}
+}
+
+// percentToNumeric converts a percentage to a DECIMAL(6,3) value
+func percentToNumeric(percent float64) pgtype.Numeric {
+	return pgtype.Numeric{Int: big.NewInt(int64(math.Round(percent * 1000))), Exp: -3, Valid: true}
+}
+
+// numericToFloat converts a DECIMAL value, treating NULL as zero
+func numericToFloat(n pgtype.Numeric) float64 {
+	if val, err := n.Float64Value(); err == nil && val.Valid {
+		return val.Float64
+	}
+	return 0
+}
+
+// convertCustomerFromDB converts a database customer to a domain customer
+func convertCustomerFromDB(dbCustomer *pgstore.Customer) domain.Customer {
+	customer := domain.Customer{
+		ID:                 dbCustomer.ID.Bytes,
+		UserID:             dbCustomer.UserID,
+		Name:               dbCustomer.Name.String,
+		Email:              dbCustomer.Email.String,
+		TaxID:              dbCustomer.TaxID.String,
+		PreferredCurrency:  dbCustomer.PreferredCurrency.String,
+		Provider:           dbCustomer.Provider.String,
+		ProviderCustomerID: dbCustomer.ProviderCustomerID.String,
+		CreatedAt:          dbCustomer.CreatedAt.Time,
+		UpdatedAt:          dbCustomer.UpdatedAt.Time,
+	}
+	if len(dbCustomer.Address) > 0 {
+		_ = json.Unmarshal(dbCustomer.Address, &customer.Address)
+	}
+	return customer
+}
+
+// convertPaymentMethodFromDB converts a database payment method to a domain saved payment method
+func convertPaymentMethodFromDB(dbMethod *pgstore.PaymentMethod) domain.SavedPaymentMethod {
+	return domain.SavedPaymentMethod{
+		ID:                      dbMethod.ID.Bytes,
+		CustomerID:              dbMethod.CustomerID.Bytes,
+		Provider:                dbMethod.Provider,
+		ProviderPaymentMethodID: dbMethod.ProviderPaymentMethodID,
+		Type:                    domain.PaymentMethod(dbMethod.Type),
+		Brand:                   dbMethod.Brand.String,
+		Last4:                   dbMethod.Last4.String,
+		ExpMonth:                dbMethod.ExpMonth.Int32,
+		ExpYear:                 dbMethod.ExpYear.Int32,
+		IsDefault:               dbMethod.IsDefault,
+		CreatedAt:               dbMethod.CreatedAt.Time,
+		UpdatedAt:               dbMethod.UpdatedAt.Time,
+	}
+}
+
+// convertSubscriptionFromDB converts a database subscription to a domain subscription
+func convertSubscriptionFromDB(dbSub *pgstore.Subscription) *domain.Subscription {
+	// Legacy rows may still hold a plan UUID rather than the plan's string ID
+	planID, err := uuid.Parse(dbSub.PlanID)
+	if err != nil {
+		planID = domain.PlanUUID(dbSub.PlanID)
+	}
+
+	sub := &domain.Subscription{
+		ID:                     dbSub.ID.Bytes,
+		UserID:                 dbSub.UserID,
+		PlanID:                 planID,
+		PlanVersion:            dbSub.PlanVersion.Int32,
+		Status:                 dbSub.Status,
+		CurrentPeriodStart:     dbSub.CurrentPeriodStart.Time,
+		CurrentPeriodEnd:       dbSub.CurrentPeriodEnd.Time,
+		CancelAtPeriodEnd:      dbSub.CancelAtPeriodEnd,
+		ExternalSubscriptionID: dbSub.ExternalSubscriptionID.String,
+		CreatedAt:              dbSub.CreatedAt.Time,
+		UpdatedAt:              dbSub.UpdatedAt.Time,
+	}
+	if dbSub.FamilyID.Valid {
+		sub.FamilyID = &dbSub.FamilyID.String
+	}
+	if dbSub.CancelledAt.Valid {
+		sub.CancelledAt = &dbSub.CancelledAt.Time
+	}
+	if len(dbSub.Metadata) > 0 {
+		_ = json.Unmarshal(dbSub.Metadata, &sub.Metadata)
+	}
+	return sub
+}
+
+// convertSubscriptionsFromDB converts database subscriptions to domain subscriptions
+func convertSubscriptionsFromDB(dbSubs []*pgstore.Subscription) []*domain.Subscription {
+	subs := make([]*domain.Subscription, len(dbSubs))
+	for i, dbSub := range dbSubs {
+		subs[i] = convertSubscriptionFromDB(dbSub)
+	}
+	return subs
+}
+
+// convertInvoiceFromDB converts a database invoice to a domain invoice without its line items
+func convertInvoiceFromDB(dbInvoice *pgstore.Invoice) domain.Invoice {
+	invoice := domain.Invoice{
+		ID:            dbInvoice.ID.Bytes,
+		Number:        dbInvoice.Number,
+		Year:          dbInvoice.Year,
+		Sequence:      dbInvoice.Sequence,
+		PaymentID:     dbInvoice.PaymentID.Bytes,
+		UserID:        dbInvoice.UserID,
+		PlanCode:      dbInvoice.PlanID,
+		PlanVersion:   dbInvoice.PlanVersion,
+		Currency:      dbInvoice.Currency,
+		SubtotalCents: dbInvoice.SubtotalCents,
+		DiscountCents: dbInvoice.DiscountCents,
+		TaxCents:      dbInvoice.TaxCents,
+		TotalCents:    dbInvoice.TotalCents,
+		TaxInclusive:  dbInvoice.TaxInclusive,
+		ReverseCharge: dbInvoice.ReverseCharge,
+		Billing: domain.BillingDetails{
+			Name:  dbInvoice.BillingName.String,
+			Email: dbInvoice.BillingEmail.String,
+			TaxID: dbInvoice.BillingTaxID.String,
+		},
+		Status:    domain.InvoiceStatus(dbInvoice.Status),
+		IssuedAt:  dbInvoice.IssuedAt.Time,
+		CreatedAt: dbInvoice.CreatedAt.Time,
+		UpdatedAt: dbInvoice.UpdatedAt.Time,
+	}
+	if len(dbInvoice.BillingAddress) > 0 {
+		_ = json.Unmarshal(dbInvoice.BillingAddress, &invoice.Billing.Address)
+	}
+	if dbInvoice.CheckoutSessionID.Valid {
+		sessionID := uuid.UUID(dbInvoice.CheckoutSessionID.Bytes)
+		invoice.CheckoutSessionID = &sessionID
+	}
+	if dbInvoice.FamilyID.Valid {
+		invoice.FamilyID = &dbInvoice.FamilyID.String
+	}
+	return invoice
+}
+
+// convertInvoiceLineItemFromDB converts a database invoice line to a domain invoice line
+func convertInvoiceLineItemFromDB(line *pgstore.InvoiceLineItem) domain.InvoiceLineItem {
+	return domain.InvoiceLineItem{
+		Kind:            domain.InvoiceLineKind(line.Kind),
+		Description:     line.Description,
+		Quantity:        line.Quantity,
+		UnitAmountCents: line.UnitAmountCents,
+		AmountCents:     line.AmountCents,
+	}
+}
+
+// convertEntitlementFromDB converts a database entitlement to a domain entitlement
+func convertEntitlementFromDB(ent *pgstore.Entitlement) domain.Entitlement {
+	// Handle both UUID and string plan IDs
+	planID, err := uuid.Parse(ent.PlanID)
+	if err != nil {
+		planID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(ent.PlanID))
+	}
+
+	domainEnt := domain.Entitlement{
+		ID:          ent.ID.Bytes,
+		UserID:      ent.UserID,
+		FeatureCode: ent.FeatureCode,
+		PlanID:      planID,
+		Status:      ent.Status,
+		GrantedAt:   ent.GrantedAt.Time,
+		UsageLimits: ent.UsageLimits,
+		Metadata:    ent.Metadata,
+		Source:      ent.Source,
+		CreatedAt:   ent.CreatedAt.Time,
+		UpdatedAt:   ent.UpdatedAt.Time,
+	}
+	if ent.FamilyID.Valid {
+		domainEnt.FamilyID = &ent.FamilyID.String
+	}
+	if ent.SubscriptionID.Valid {
+		domainEnt.SubscriptionID = &ent.SubscriptionID.String
+	}
+	if ent.ExpiresAt.Valid {
+		domainEnt.ExpiresAt = &ent.ExpiresAt.Time
+	}
+
+	return domainEnt
+}
+
 // Helper function to convert payment from database model to domain model
 func convertPaymentFromDB(dbPayment *pgstore.Payment) *domain.Payment {
 	var description, externalPaymentID, failureReason string
@@ -796,8 +2778,93 @@ func convertPaymentFromDB(dbPayment *pgstore.Payment) *domain.Payment {
 		Description:       description,
 		ExternalPaymentID: externalPaymentID,
 		FailureReason:     failureReason,
+		Provider:          dbPayment.Provider.String,
 		Metadata:          dbPayment.Metadata,
 		CreatedAt:         createdAt,
 		UpdatedAt:         updatedAt,
 	}
 }
+
+// convertUsageFromDB converts a database usage record to a domain usage record
+func convertUsageFromDB(dbUsage *pgstore.Usage) domain.Usage {
+	usage := domain.Usage{
+		ID:           dbUsage.ID.Bytes,
+		UserID:       dbUsage.UserID,
+		FeatureCode:  dbUsage.FeatureCode,
+		ResourceType: dbUsage.ResourceType,
+		ResourceSize: dbUsage.ResourceSize,
+		Operation:    dbUsage.Operation.String,
+		CreatedAt:    dbUsage.CreatedAt.Time,
+	}
+	if dbUsage.FamilyID.Valid {
+		usage.FamilyID = &dbUsage.FamilyID.String
+	}
+	if len(dbUsage.Metadata) > 0 {
+		_ = json.Unmarshal(dbUsage.Metadata, &usage.Metadata)
+	}
+	return usage
+}
+
+// convertUsagesFromDB converts database usage records to domain usage records
+func convertUsagesFromDB(dbUsage []*pgstore.Usage) []domain.Usage {
+	usage := make([]domain.Usage, len(dbUsage))
+	for i, u := range dbUsage {
+		usage[i] = convertUsageFromDB(u)
+	}
+	return usage
+}
+
+func convertJournalEntryFromDB(dbEntry *pgstore.JournalEntry) *domain.JournalEntry {
+	entry := &domain.JournalEntry{
+		ID:          dbEntry.ID.Bytes,
+		Reference:   dbEntry.Reference,
+		Kind:        domain.JournalEntryKind(dbEntry.Kind),
+		CustomerID:  dbEntry.CustomerID.String,
+		Currency:    dbEntry.Currency,
+		Description: dbEntry.Description.String,
+		OccurredAt:  dbEntry.OccurredAt.Time,
+		CreatedAt:   dbEntry.CreatedAt.Time,
+	}
+	if dbEntry.PaymentID.Valid {
+		paymentID := uuid.UUID(dbEntry.PaymentID.Bytes)
+		entry.PaymentID = &paymentID
+	}
+	return entry
+}
+
+func convertPostingFromDB(dbPosting *pgstore.LedgerPosting) domain.Posting {
+	return domain.Posting{
+		ID:          dbPosting.ID.Bytes,
+		AccountCode: dbPosting.AccountCode,
+		Direction:   domain.PostingDirection(dbPosting.Direction),
+		AmountCents: dbPosting.AmountCents,
+	}
+}
+
+func convertRevenueSnapshotFromDB(row *pgstore.RevenueSnapshot) domain.RevenueSnapshot {
+	return domain.RevenueSnapshot{
+		Date:           row.SnapshotDate.Time,
+		SubscriptionID: row.SubscriptionID.Bytes,
+		UserID:         row.UserID,
+		PlanID:         row.PlanID,
+		PricingZone:    row.PricingZone,
+		Currency:       row.Currency,
+		MRRCents:       row.MrrCents,
+	}
+}
+
+func convertAuditEventFromDB(dbEvent *pgstore.AuditEvent) *domain.AuditEvent {
+	event := &domain.AuditEvent{
+		ID:         dbEvent.ID.Bytes,
+		EntityType: domain.AuditEntityType(dbEvent.EntityType),
+		EntityID:   dbEvent.EntityID,
+		Action:     dbEvent.Action,
+		ActorType:  domain.AuditActorType(dbEvent.ActorType),
+		ActorID:    dbEvent.ActorID,
+		RequestID:  dbEvent.RequestID,
+		Reason:     dbEvent.Reason,
+		OccurredAt: dbEvent.OccurredAt.Time,
+	}
+	_ = json.Unmarshal(dbEvent.Changes, &event.Changes)
+	return event
+}
@@ -0,0 +1,210 @@
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newEntitlement(userID, featureCode string, plan domain.Plan, grantedAt time.Time) domain.Entitlement {
+	return domain.Entitlement{
+		UserID:      userID,
+		FeatureCode: featureCode,
+		PlanID:      plan.ID,
+		Status:      "active",
+		GrantedAt:   grantedAt,
+	}
+}
+
+func entitlementID(e domain.Entitlement) string { return e.ID.String() }
+
+func testEntitlements(t *testing.T, newStore Factory) {
+	t.Run("InsertAndCheck", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		entitlements := store.Entitlement()
+		plan := createPlan(t, store.Plan(), "contract_basic")
+
+		subscriptionID := "sub_contract_1"
+		e := newEntitlement("user-1", "storage", plan, testTime(-30))
+		e.SubscriptionID = &subscriptionID
+		e.UsageLimits = json.RawMessage(`{"storage_gb": 10}`)
+		inserted, err := entitlements.Insert(ctx, e)
+		requireNoError(t, err)
+		if inserted.ID == uuid.Nil || inserted.CreatedAt.IsZero() {
+			t.Fatalf("expected Insert to assign an ID and timestamps, got %+v", inserted)
+		}
+		if inserted.PlanID != plan.ID || !inserted.GrantedAt.Equal(testTime(-30)) {
+			t.Errorf("unexpected inserted entitlement: %+v", inserted)
+		}
+		if inserted.SubscriptionID == nil || *inserted.SubscriptionID != subscriptionID {
+			t.Errorf("expected subscription ID %q, got %v", subscriptionID, inserted.SubscriptionID)
+		}
+		if !jsonEqual(inserted.UsageLimits, `{"storage_gb": 10}`) {
+			t.Errorf("expected the usage limits to be stored, got %s", inserted.UsageLimits)
+		}
+		if inserted.Source != domain.EntitlementSourcePaid {
+			t.Errorf("expected entitlements to default to source %q, got %q", domain.EntitlementSourcePaid, inserted.Source)
+		}
+
+		byID, err := entitlements.GetByID(ctx, inserted.ID.String())
+		requireNoError(t, err)
+		if byID.ID != inserted.ID || byID.FeatureCode != "storage" {
+			t.Errorf("expected GetByID to return %s, got %+v", inserted.ID, byID)
+		}
+		_, err = entitlements.GetByID(ctx, uuid.NewString())
+		expectCode(t, err, domain.ErrCodeNotFound)
+		_, err = entitlements.GetByID(ctx, "not-a-uuid")
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		got, found, err := entitlements.Check(ctx, "user-1", "storage")
+		requireNoError(t, err)
+		if !found || got.ID != inserted.ID {
+			t.Errorf("expected Check to find %s, got %+v (found=%v)", inserted.ID, got, found)
+		}
+
+		_, found, err = entitlements.Check(ctx, "user-1", "sharing")
+		requireNoError(t, err)
+		if found {
+			t.Error("expected no entitlement for a feature never granted")
+		}
+
+		bySub, err := entitlements.GetBySubscriptionID(ctx, subscriptionID)
+		requireNoError(t, err)
+		expectOrder(t, "GetBySubscriptionID", ids(bySub, entitlementID), []string{inserted.ID.String()})
+	})
+
+	t.Run("CheckSkipsInactiveAndExpired", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		entitlements := store.Entitlement()
+		plan := createPlan(t, store.Plan(), "contract_basic")
+
+		older, err := entitlements.Insert(ctx, newEntitlement("user-1", "storage", plan, testTime(-60)))
+		requireNoError(t, err)
+		newer, err := entitlements.Insert(ctx, newEntitlement("user-1", "storage", plan, testTime(-30)))
+		requireNoError(t, err)
+
+		got, found, err := entitlements.Check(ctx, "user-1", "storage")
+		requireNoError(t, err)
+		if !found || got.ID != newer.ID {
+			t.Errorf("expected Check to return the latest grant %s, got %s", newer.ID, got.ID)
+		}
+
+		requireNoError(t, entitlements.UpdateStatus(ctx, newer.ID.String(), "revoked"))
+		got, found, err = entitlements.Check(ctx, "user-1", "storage")
+		requireNoError(t, err)
+		if !found || got.ID != older.ID {
+			t.Errorf("expected Check to skip the revoked grant, got %s", got.ID)
+		}
+
+		expired := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+		requireNoError(t, entitlements.UpdateExpiry(ctx, older.ID.String(), &expired))
+		_, found, err = entitlements.Check(ctx, "user-1", "storage")
+		requireNoError(t, err)
+		if found {
+			t.Error("expected Check to skip an expired grant")
+		}
+
+		requireNoError(t, entitlements.UpdateExpiry(ctx, older.ID.String(), nil))
+		_, found, err = entitlements.Check(ctx, "user-1", "storage")
+		requireNoError(t, err)
+		if !found {
+			t.Error("expected clearing the expiry to restore the grant")
+		}
+
+		expectError(t, entitlements.UpdateStatus(ctx, "not-a-uuid", "revoked"), "an invalid entitlement ID")
+		expectError(t, entitlements.UpdateStatus(ctx, uuid.NewString(), "revoked"), "a missing entitlement")
+		expectError(t, entitlements.UpdateExpiry(ctx, "not-a-uuid", nil), "an invalid entitlement ID")
+		expectError(t, entitlements.UpdateExpiry(ctx, uuid.NewString(), nil), "a missing entitlement")
+	})
+
+	t.Run("UpdateAndList", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		entitlements := store.Entitlement()
+		basic := createPlan(t, store.Plan(), "contract_basic")
+		pro := createPlan(t, store.Plan(), "contract_pro")
+
+		first, err := entitlements.Insert(ctx, newEntitlement("user-1", "storage", basic, testTime(-10)))
+		requireNoError(t, err)
+		second, err := entitlements.Insert(ctx, newEntitlement("user-1", "sharing", basic, testTime(-5)))
+		requireNoError(t, err)
+		familyID := "family-1"
+		shared := newEntitlement("user-2", "storage", basic, testTime(-1))
+		shared.FamilyID = &familyID
+		third, err := entitlements.Insert(ctx, shared)
+		requireNoError(t, err)
+		if third.FamilyID == nil || *third.FamilyID != familyID {
+			t.Errorf("expected family ID %q, got %v", familyID, third.FamilyID)
+		}
+
+		byUser, err := entitlements.ListByUser(ctx, "user-1")
+		requireNoError(t, err)
+		expectOrder(t, "ListByUser", ids(byUser, entitlementID), []string{second.ID.String(), first.ID.String()})
+
+		expiresAt := testTime(30)
+		first.PlanID = pro.ID
+		first.Status = "suspended"
+		first.ExpiresAt = &expiresAt
+		updated, err := entitlements.Update(ctx, first)
+		requireNoError(t, err)
+		if updated.PlanID != pro.ID || updated.Status != "suspended" || updated.ExpiresAt == nil || !updated.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("expected Update to overwrite the grant, got %+v", updated)
+		}
+
+		missing := newEntitlement("user-1", "storage", basic, testTime(0))
+		missing.ID = uuid.New()
+		_, err = entitlements.Update(ctx, missing)
+		expectError(t, err, "a missing entitlement")
+
+		var walked []string
+		var cursor *domain.EntitlementCursor
+		for range 3 {
+			page, err := entitlements.ListAfter(ctx, domain.EntitlementFilter{}, cursor, 2)
+			requireNoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			walked = append(walked, ids(page, entitlementID)...)
+			last := page[len(page)-1]
+			cursor = &domain.EntitlementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+		expectOrder(t, "ListAfter pages", walked, []string{first.ID.String(), second.ID.String(), third.ID.String()})
+
+		filtered, err := entitlements.ListAfter(ctx, domain.EntitlementFilter{FeatureCode: "storage", Status: "active"}, nil, 10)
+		requireNoError(t, err)
+		expectOrder(t, "ListAfter filtered", ids(filtered, entitlementID), []string{third.ID.String()})
+
+		family, err := entitlements.ListAfter(ctx, domain.EntitlementFilter{FamilyID: familyID}, nil, 10)
+		requireNoError(t, err)
+		expectOrder(t, "ListAfter by family", ids(family, entitlementID), []string{third.ID.String()})
+
+		comp := newEntitlement("user-3", "storage", basic, testTime(0))
+		comp.Source = domain.EntitlementSourceComplimentary
+		fourth, err := entitlements.Insert(ctx, comp)
+		requireNoError(t, err)
+		if fourth.Source != domain.EntitlementSourceComplimentary {
+			t.Errorf("expected source %q, got %q", domain.EntitlementSourceComplimentary, fourth.Source)
+		}
+
+		// Updates keep the source
+		fourth.Status = "revoked"
+		updated, err = entitlements.Update(ctx, fourth)
+		requireNoError(t, err)
+		if updated.Source != domain.EntitlementSourceComplimentary {
+			t.Errorf("expected Update to keep source %q, got %q", domain.EntitlementSourceComplimentary, updated.Source)
+		}
+
+		comps, err := entitlements.ListAfter(ctx, domain.EntitlementFilter{Source: domain.EntitlementSourceComplimentary}, nil, 10)
+		requireNoError(t, err)
+		expectOrder(t, "ListAfter complimentary", ids(comps, entitlementID), []string{fourth.ID.String()})
+		paid, err := entitlements.ListAfter(ctx, domain.EntitlementFilter{Source: domain.EntitlementSourcePaid}, nil, 10)
+		requireNoError(t, err)
+		expectOrder(t, "ListAfter paid", ids(paid, entitlementID), []string{first.ID.String(), second.ID.String(), third.ID.String()})
+	})
+}
@@ -0,0 +1,98 @@
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newFeature(code string) domain.Feature {
+	return domain.Feature{
+		Code:          code,
+		Description:   "Feature " + code,
+		QuotaSchema:   map[string]domain.QuotaType{"storage_gb": domain.QuotaTypeInteger},
+		DefaultLimits: json.RawMessage(`{"storage_gb": 10}`),
+	}
+}
+
+func featureCode(f domain.Feature) string { return f.Code }
+
+func testFeatures(t *testing.T, newStore Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		ctx := context.Background()
+		features := newStore(t).Feature()
+
+		created, err := features.Create(ctx, newFeature("contract_storage"))
+		requireNoError(t, err)
+		if created.CreatedAt.IsZero() || created.Deprecated {
+			t.Errorf("unexpected created feature: %+v", created)
+		}
+		if created.QuotaSchema["storage_gb"] != domain.QuotaTypeInteger || !jsonEqual(created.DefaultLimits, `{"storage_gb": 10}`) {
+			t.Errorf("expected the quota schema and default limits to be stored, got %+v", created)
+		}
+
+		_, err = features.Create(ctx, newFeature("contract_storage"))
+		expectCode(t, err, domain.ErrCodeAlreadyExists)
+
+		got, err := features.GetByCode(ctx, "contract_storage")
+		requireNoError(t, err)
+		if got.Description != "Feature contract_storage" || !got.CreatedAt.Equal(created.CreatedAt) {
+			t.Errorf("unexpected feature: %+v", got)
+		}
+		_, err = features.GetByCode(ctx, "contract_missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		bare, err := features.Create(ctx, domain.Feature{Code: "contract_bare"})
+		requireNoError(t, err)
+		if len(bare.QuotaSchema) != 0 || !jsonEqual(bare.DefaultLimits, `{}`) {
+			t.Errorf("expected a feature without limits to store empty documents, got %+v", bare)
+		}
+	})
+
+	t.Run("UpdateAndList", func(t *testing.T) {
+		ctx := context.Background()
+		features := newStore(t).Feature()
+		for _, code := range []string{"contract_sso", "contract_analytics", "contract_storage"} {
+			_, err := features.Create(ctx, newFeature(code))
+			requireNoError(t, err)
+		}
+
+		feature, err := features.GetByCode(ctx, "contract_storage")
+		requireNoError(t, err)
+		feature.Description = "Cloud storage"
+		feature.Deprecated = true
+		feature.QuotaSchema = map[string]domain.QuotaType{"storage_gb": domain.QuotaTypeNumber}
+		updated, err := features.Update(ctx, feature)
+		requireNoError(t, err)
+		if updated.Description != "Cloud storage" || !updated.Deprecated || updated.QuotaSchema["storage_gb"] != domain.QuotaTypeNumber {
+			t.Errorf("expected the update to be stored, got %+v", updated)
+		}
+		if !updated.CreatedAt.Equal(feature.CreatedAt) || updated.UpdatedAt.Before(feature.UpdatedAt) {
+			t.Errorf("expected an update to keep the creation time, got %+v", updated)
+		}
+
+		_, err = features.Update(ctx, newFeature("contract_missing"))
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		all, err := features.List(ctx)
+		requireNoError(t, err)
+		expectOrder(t, "List", ids(all, featureCode), []string{"contract_analytics", "contract_sso", "contract_storage"})
+		if !all[2].Deprecated {
+			t.Error("expected List to include deprecated features")
+		}
+	})
+}
+
+// jsonEqual compares a stored JSON document with the expected one,
+// ignoring formatting
+func jsonEqual(got json.RawMessage, want string) bool {
+	var g, w interface{}
+	if json.Unmarshal(got, &g) != nil || json.Unmarshal([]byte(want), &w) != nil {
+		return false
+	}
+	gb, _ := json.Marshal(g)
+	wb, _ := json.Marshal(w)
+	return string(gb) == string(wb)
+}
@@ -0,0 +1,244 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newPayment(customerID, orderID string, amount float64) *domain.Payment {
+	return &domain.Payment{
+		Amount:        amount,
+		Currency:      "USD",
+		Status:        string(domain.PaymentStatusPending),
+		PaymentMethod: "card",
+		CustomerID:    customerID,
+		OrderID:       orderID,
+		Provider:      "stripe",
+	}
+}
+
+func paymentOrderID(p *domain.Payment) string { return p.OrderID }
+
+func testPayments(t *testing.T, newStore Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		ctx := context.Background()
+		payments := newStore(t).Payment()
+
+		p := newPayment("cust-1", "order-1", 19.99)
+		p.Description = "Pro plan"
+		requireNoError(t, payments.Create(ctx, p))
+		if p.ID == uuid.Nil || p.CreatedAt.IsZero() || p.UpdatedAt.IsZero() {
+			t.Fatalf("expected Create to assign an ID and timestamps, got %+v", p)
+		}
+
+		got, err := payments.GetByID(ctx, p.ID.String())
+		requireNoError(t, err)
+		if got.Amount != 19.99 || got.OrderID != "order-1" || got.Description != "Pro plan" || got.Provider != "stripe" {
+			t.Errorf("unexpected payment: %+v", got)
+		}
+		if !got.CreatedAt.Equal(p.CreatedAt) {
+			t.Errorf("expected created_at %v, got %v", p.CreatedAt, got.CreatedAt)
+		}
+
+		byOrder, err := payments.GetByOrderID(ctx, "order-1")
+		requireNoError(t, err)
+		if byOrder.ID != p.ID {
+			t.Errorf("expected payment %s by order ID, got %s", p.ID, byOrder.ID)
+		}
+
+		_, err = payments.GetByID(ctx, "not-a-uuid")
+		expectError(t, err, "an invalid payment ID")
+		_, err = payments.GetByID(ctx, uuid.NewString())
+		expectError(t, err, "a missing payment")
+		_, err = payments.GetByOrderID(ctx, "order-missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		ctx := context.Background()
+		payments := newStore(t).Payment()
+
+		p := newPayment("cust-1", "order-1", 10)
+		requireNoError(t, payments.Create(ctx, p))
+
+		p.Status = string(domain.PaymentStatusFailed)
+		p.FailureReason = "card_declined"
+		p.Amount = 12.5
+		p.Provider = "paypal"
+		requireNoError(t, payments.Update(ctx, p))
+
+		got, err := payments.GetByID(ctx, p.ID.String())
+		requireNoError(t, err)
+		if got.Status != string(domain.PaymentStatusFailed) || got.FailureReason != "card_declined" || got.Amount != 12.5 {
+			t.Errorf("expected the update to be stored, got %+v", got)
+		}
+		if got.Provider != "stripe" {
+			t.Errorf("expected Update to keep the provider, got %q", got.Provider)
+		}
+		if got.UpdatedAt.Before(p.CreatedAt) {
+			t.Errorf("expected updated_at %v not to precede created_at %v", got.UpdatedAt, p.CreatedAt)
+		}
+
+		requireNoError(t, payments.UpdateStatus(ctx, p.ID.String(), string(domain.PaymentStatusCompleted)))
+		got, err = payments.GetByID(ctx, p.ID.String())
+		requireNoError(t, err)
+		if got.Status != string(domain.PaymentStatusCompleted) || got.FailureReason != "" {
+			t.Errorf("expected UpdateStatus to set the status and clear the failure reason, got %+v", got)
+		}
+
+		expectError(t, payments.UpdateStatus(ctx, "not-a-uuid", "completed"), "an invalid payment ID")
+		expectError(t, payments.UpdateStatus(ctx, uuid.NewString(), "completed"), "a missing payment")
+		missing := newPayment("cust-1", "order-2", 1)
+		missing.ID = uuid.New()
+		expectError(t, payments.Update(ctx, missing), "a missing payment")
+
+		requireNoError(t, payments.Delete(ctx, p.ID.String()))
+		_, err = payments.GetByID(ctx, p.ID.String())
+		expectError(t, err, "a deleted payment")
+		count, err := payments.Count(ctx)
+		requireNoError(t, err)
+		if count != 0 {
+			t.Errorf("expected no payments after delete, got %d", count)
+		}
+	})
+
+	t.Run("ListNewestFirst", func(t *testing.T) {
+		ctx := context.Background()
+		payments := newStore(t).Payment()
+
+		for _, p := range []*domain.Payment{
+			newPayment("cust-1", "order-1", 1),
+			newPayment("cust-2", "order-2", 2),
+			newPayment("cust-1", "order-3", 3),
+			newPayment("cust-1", "order-4", 4),
+		} {
+			requireNoError(t, payments.Create(ctx, p))
+		}
+
+		all, err := payments.List(ctx, 0, 0)
+		requireNoError(t, err)
+		expectOrder(t, "List", ids(all, paymentOrderID), []string{"order-4", "order-3", "order-2", "order-1"})
+
+		page, err := payments.List(ctx, 2, 1)
+		requireNoError(t, err)
+		expectOrder(t, "List(2, 1)", ids(page, paymentOrderID), []string{"order-3", "order-2"})
+
+		byCustomer, err := payments.GetByCustomerID(ctx, "cust-1", 0, 0)
+		requireNoError(t, err)
+		expectOrder(t, "GetByCustomerID", ids(byCustomer, paymentOrderID), []string{"order-4", "order-3", "order-1"})
+
+		byCustomer, err = payments.GetByCustomerID(ctx, "cust-1", 1, 1)
+		requireNoError(t, err)
+		expectOrder(t, "GetByCustomerID(1, 1)", ids(byCustomer, paymentOrderID), []string{"order-3"})
+
+		none, err := payments.GetByCustomerID(ctx, "cust-none", 10, 0)
+		requireNoError(t, err)
+		if len(none) != 0 {
+			t.Errorf("expected no payments for an unknown customer, got %d", len(none))
+		}
+
+		count, err := payments.Count(ctx)
+		requireNoError(t, err)
+		if count != 4 {
+			t.Errorf("expected 4 payments, got %d", count)
+		}
+	})
+
+	t.Run("ListFiltered", func(t *testing.T) {
+		ctx := context.Background()
+		payments := newStore(t).Payment()
+
+		created := make([]*domain.Payment, 0, 5)
+		for i, amount := range []float64{30, 10, 20, 10, 50} {
+			p := newPayment("cust-1", "order-"+string(rune('a'+i)), amount)
+			if i == 4 {
+				p.CustomerID = "cust-2"
+				p.Status = string(domain.PaymentStatusCompleted)
+				p.Currency = "EUR"
+			}
+			requireNoError(t, payments.Create(ctx, p))
+			created = append(created, p)
+		}
+
+		filter := domain.PaymentFilter{CustomerID: "cust-1"}
+		count, err := payments.CountFiltered(ctx, filter)
+		requireNoError(t, err)
+		if count != 4 {
+			t.Errorf("expected 4 payments for cust-1, got %d", count)
+		}
+
+		minAmount, maxAmount := 10.0, 20.0
+		ranged, err := payments.CountFiltered(ctx, domain.PaymentFilter{MinAmount: &minAmount, MaxAmount: &maxAmount})
+		requireNoError(t, err)
+		if ranged != 3 {
+			t.Errorf("expected 3 payments between 10 and 20 inclusive, got %d", ranged)
+		}
+
+		after, before := created[1].CreatedAt, created[3].CreatedAt
+		window, err := payments.ListFiltered(ctx, domain.PaymentListQuery{
+			Filter: domain.PaymentFilter{CreatedAfter: &after, CreatedBefore: &before},
+			SortBy: domain.PaymentSortByCreatedAt,
+			Limit:  10,
+		})
+		requireNoError(t, err)
+		expectOrder(t, "created window", ids(window, paymentOrderID), []string{"order-b", "order-c"})
+
+		other, err := payments.ListFiltered(ctx, domain.PaymentListQuery{
+			Filter: domain.PaymentFilter{Status: "completed", Currency: "EUR"},
+			SortBy: domain.PaymentSortByCreatedAt,
+			Limit:  10,
+		})
+		requireNoError(t, err)
+		expectOrder(t, "status and currency", ids(other, paymentOrderID), []string{"order-e"})
+
+		// Walk cust-1's payments two at a time by amount, descending; the two
+		// payments of 10 are ordered by ID
+		tied := []*domain.Payment{created[1], created[3]}
+		if tied[0].ID.String() < tied[1].ID.String() {
+			tied[0], tied[1] = tied[1], tied[0]
+		}
+		want := []string{"order-a", "order-c", tied[0].OrderID, tied[1].OrderID}
+
+		var walked []string
+		query := domain.PaymentListQuery{Filter: filter, SortBy: domain.PaymentSortByAmount, SortDesc: true, Limit: 2}
+		for range 3 {
+			page, err := payments.ListFiltered(ctx, query)
+			requireNoError(t, err)
+			if len(page) == 0 {
+				break
+			}
+			walked = append(walked, ids(page, paymentOrderID)...)
+			last := page[len(page)-1]
+			query.Cursor = &domain.PaymentCursor{
+				SortBy:    query.SortBy,
+				SortDesc:  query.SortDesc,
+				CreatedAt: last.CreatedAt,
+				Amount:    last.Amount,
+				ID:        last.ID,
+			}
+		}
+		expectOrder(t, "amount descending pages", walked, want)
+
+		ascending, err := payments.ListFiltered(ctx, domain.PaymentListQuery{
+			Filter: filter,
+			SortBy: domain.PaymentSortByCreatedAt,
+			Limit:  2,
+			Offset: 1,
+		})
+		requireNoError(t, err)
+		expectOrder(t, "created ascending with offset", ids(ascending, paymentOrderID), []string{"order-b", "order-c"})
+
+		resumed, err := payments.ListFiltered(ctx, domain.PaymentListQuery{
+			Filter: filter,
+			SortBy: domain.PaymentSortByCreatedAt,
+			Limit:  10,
+			Offset: 3, // ignored once a cursor is set
+			Cursor: &domain.PaymentCursor{SortBy: domain.PaymentSortByCreatedAt, CreatedAt: created[1].CreatedAt, ID: created[1].ID},
+		})
+		requireNoError(t, err)
+		expectOrder(t, "created ascending after cursor", ids(resumed, paymentOrderID), []string{"order-c", "order-d"})
+	})
+}
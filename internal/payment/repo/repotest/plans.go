@@ -0,0 +1,135 @@
+package repotest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+func newPlan(code string) domain.Plan {
+	return domain.Plan{
+		Code:         code,
+		Name:         "Plan " + code,
+		FeatureCodes: []string{"storage", "sharing"},
+		BillingCycle: domain.BillingCycleMonthly,
+		PriceDollars: 9.99,
+		Currency:     "USD",
+		UsageLimits:  json.RawMessage(`{"storage": 10}`),
+		Active:       true,
+	}
+}
+
+// createPlan stores a plan for tests of the repositories that reference plans
+func createPlan(t *testing.T, plans repo.PlanRepository, code string) domain.Plan {
+	t.Helper()
+	plan, err := plans.Create(context.Background(), newPlan(code))
+	requireNoError(t, err)
+	return plan
+}
+
+func planCode(p domain.Plan) string { return p.Code }
+
+func testPlans(t *testing.T, newStore Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		ctx := context.Background()
+		plans := newStore(t).Plan()
+
+		created, err := plans.Create(ctx, newPlan("contract_basic"))
+		requireNoError(t, err)
+		if created.Version != 1 || created.ID != domain.PlanUUID("contract_basic") || created.CreatedAt.IsZero() {
+			t.Errorf("unexpected created plan: %+v", created)
+		}
+		if created.PriceDollars != 9.99 || created.IsArchived() {
+			t.Errorf("unexpected created plan: %+v", created)
+		}
+
+		_, err = plans.Create(ctx, newPlan("contract_basic"))
+		expectCode(t, err, domain.ErrCodeAlreadyExists)
+
+		got, err := plans.GetByID(ctx, "contract_basic")
+		requireNoError(t, err)
+		if got.Name != "Plan contract_basic" || len(got.FeatureCodes) != 2 {
+			t.Errorf("unexpected plan: %+v", got)
+		}
+
+		_, err = plans.GetByID(ctx, "contract_missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+		_, err = plans.GetByIDIncludingArchived(ctx, "contract_missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		v1, err := plans.GetVersion(ctx, "contract_basic", 1)
+		requireNoError(t, err)
+		if v1.PlanCode != "contract_basic" || v1.PriceDollars != 9.99 {
+			t.Errorf("unexpected first version: %+v", v1)
+		}
+		_, err = plans.GetVersion(ctx, "contract_basic", 2)
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("UpdateVersions", func(t *testing.T) {
+		ctx := context.Background()
+		plans := newStore(t).Plan()
+		plan := createPlan(t, plans, "contract_pro")
+
+		plan.Name = "Renamed"
+		renamed, err := plans.Update(ctx, plan)
+		requireNoError(t, err)
+		if renamed.Name != "Renamed" || renamed.Version != 1 {
+			t.Errorf("expected a rename to keep version 1, got %+v", renamed)
+		}
+
+		renamed.PriceDollars = 12.49
+		repriced, err := plans.Update(ctx, renamed)
+		requireNoError(t, err)
+		if repriced.Version != 2 || repriced.PriceDollars != 12.49 {
+			t.Errorf("expected a price change to create version 2, got %+v", repriced)
+		}
+
+		versions, err := plans.ListVersions(ctx, "contract_pro")
+		requireNoError(t, err)
+		if len(versions) != 2 || versions[0].Version != 2 || versions[1].Version != 1 {
+			t.Fatalf("expected versions 2 then 1, got %+v", versions)
+		}
+		if versions[0].PriceDollars != 12.49 || versions[1].PriceDollars != 9.99 {
+			t.Errorf("expected each version to keep its price, got %+v", versions)
+		}
+
+		_, err = plans.Update(ctx, newPlan("contract_missing"))
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		ctx := context.Background()
+		plans := newStore(t).Plan()
+		createPlan(t, plans, "contract_old")
+		createPlan(t, plans, "contract_new")
+
+		archived, err := plans.Archive(ctx, "contract_old")
+		requireNoError(t, err)
+		if archived.Active || !archived.IsArchived() {
+			t.Errorf("expected an archived, inactive plan, got %+v", archived)
+		}
+		_, err = plans.Archive(ctx, "contract_missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		_, err = plans.GetByID(ctx, "contract_old")
+		expectCode(t, err, domain.ErrCodeNotFound)
+		_, err = plans.GetByIDIncludingArchived(ctx, "contract_old")
+		requireNoError(t, err)
+
+		active, err := plans.ListActive(ctx)
+		requireNoError(t, err)
+		expectOrder(t, "ListActive", ids(active, planCode), []string{"contract_new"})
+
+		current, err := plans.List(ctx, false)
+		requireNoError(t, err)
+		expectOrder(t, "List without archived", ids(current, planCode), []string{"contract_new"})
+
+		all, err := plans.List(ctx, true)
+		requireNoError(t, err)
+		expectOrder(t, "List with archived", ids(all, planCode), []string{"contract_new", "contract_old"})
+	})
+}
@@ -0,0 +1,89 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newPricingZone(isoCode, country, zone string, multiplier float64) domain.PricingZone {
+	return domain.PricingZone{
+		Country:           country,
+		ISOCode:           isoCode,
+		Zone:              zone,
+		ZoneName:          "Zone " + zone,
+		PricingMultiplier: multiplier,
+	}
+}
+
+func pricingZoneISOCode(z domain.PricingZone) string { return z.ISOCode }
+
+func testPricingZones(t *testing.T, newStore Factory) {
+	t.Run("UpsertAndGet", func(t *testing.T) {
+		ctx := context.Background()
+		zones := newStore(t).PricingZone()
+
+		created, err := zones.Upsert(ctx, newPricingZone("BR", "Brazil", "B", 0.7))
+		requireNoError(t, err)
+		if created.ID == "" || created.CreatedAt.IsZero() || created.PricingMultiplier != 0.7 {
+			t.Fatalf("unexpected created zone: %+v", created)
+		}
+
+		updated, err := zones.Upsert(ctx, newPricingZone("BR", "Brazil", "C", 1.15))
+		requireNoError(t, err)
+		if updated.ID != created.ID || !updated.CreatedAt.Equal(created.CreatedAt) {
+			t.Errorf("expected an upsert to keep the zone's ID and creation time, got %+v", updated)
+		}
+		if updated.Zone != "C" || updated.PricingMultiplier != 1.15 {
+			t.Errorf("expected the upsert to be stored, got %+v", updated)
+		}
+
+		byISO, err := zones.GetByISOCode(ctx, "BR")
+		requireNoError(t, err)
+		if byISO.Zone != "C" {
+			t.Errorf("expected zone C, got %+v", byISO)
+		}
+
+		byCountry, err := zones.GetByCountry(ctx, "bRaZiL")
+		requireNoError(t, err)
+		if byCountry.ISOCode != "BR" {
+			t.Errorf("expected a case-insensitive country match, got %+v", byCountry)
+		}
+
+		_, err = zones.GetByISOCode(ctx, "ZZ")
+		expectCode(t, err, domain.ErrCodeNotFound)
+		_, err = zones.GetByCountry(ctx, "Atlantis")
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("ListAndDelete", func(t *testing.T) {
+		ctx := context.Background()
+		zones := newStore(t).PricingZone()
+
+		requireNoError(t, zones.BulkUpsert(ctx, []domain.PricingZone{
+			newPricingZone("US", "United States", "A", 1),
+			newPricingZone("IN", "India", "D", 0.4),
+			newPricingZone("DE", "Germany", "A", 1),
+			newPricingZone("KE", "Kenya", "D", 0.4),
+		}))
+
+		all, err := zones.List(ctx)
+		requireNoError(t, err)
+		expectOrder(t, "List", ids(all, pricingZoneISOCode), []string{"DE", "US", "IN", "KE"})
+
+		zoneD, err := zones.GetByZone(ctx, "D")
+		requireNoError(t, err)
+		expectOrder(t, "GetByZone", ids(zoneD, pricingZoneISOCode), []string{"IN", "KE"})
+
+		requireNoError(t, zones.Delete(ctx, "IN"))
+		_, err = zones.GetByISOCode(ctx, "IN")
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		none, err := zones.GetByZone(ctx, "X")
+		requireNoError(t, err)
+		if len(none) != 0 {
+			t.Errorf("expected no zones for an unknown zone, got %d", len(none))
+		}
+	})
+}
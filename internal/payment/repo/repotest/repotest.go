@@ -0,0 +1,98 @@
+// Package repotest holds the contract every implementation of the payment
+// repositories is expected to honour: ordering, pagination, not-found and
+// already-exists errors, and which fields each write changes. The postgres
+// and in-memory stores both run it with Run, so usecase tests written
+// against the in-memory store see the behaviour production will.
+package repotest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// Store is the set of repositories the contract covers
+type Store interface {
+	Payment() repo.PaymentRepository
+	Plan() repo.PlanRepository
+	Feature() repo.FeatureRepository
+	Entitlement() repo.EntitlementRepository
+	Subscription() repo.SubscriptionRepository
+	PricingZone() repo.PricingZoneRepository
+	Usage() repo.UsageRepository
+}
+
+// Factory returns an empty store
+type Factory func(t *testing.T) Store
+
+// Run runs the contract suite against the stores newStore returns. Every
+// subtest gets a store of its own.
+func Run(t *testing.T, newStore Factory) {
+	t.Run("Payment", func(t *testing.T) { testPayments(t, newStore) })
+	t.Run("Plan", func(t *testing.T) { testPlans(t, newStore) })
+	t.Run("Feature", func(t *testing.T) { testFeatures(t, newStore) })
+	t.Run("Entitlement", func(t *testing.T) { testEntitlements(t, newStore) })
+	t.Run("Subscription", func(t *testing.T) { testSubscriptions(t, newStore) })
+	t.Run("PricingZone", func(t *testing.T) { testPricingZones(t, newStore) })
+	t.Run("Usage", func(t *testing.T) { testUsage(t, newStore) })
+}
+
+// requireNoError fails the test immediately on an unexpected error
+func requireNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// expectCode checks that err is a domain error with the given code
+func expectCode(t *testing.T, err error, code string) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expected a %s error, got nil", code)
+		return
+	}
+	if de := domain.GetDomainError(err); de == nil || de.Code != code {
+		t.Errorf("expected a %s error, got %v", code, err)
+	}
+}
+
+// expectError checks that err is set without caring what kind it is
+func expectError(t *testing.T, err error, what string) {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expected an error for %s, got nil", what)
+	}
+}
+
+// ids returns the values of key for each item, in order
+func ids[T any](items []T, key func(T) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = key(item)
+	}
+	return out
+}
+
+// expectOrder checks that got lists exactly the keys in want, in order
+func expectOrder(t *testing.T, what string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: expected %v, got %v", what, want, got)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s: expected %v, got %v", what, want, got)
+			return
+		}
+	}
+}
+
+// testTime returns a fixed instant, offset by days, at the microsecond
+// precision every store keeps
+func testTime(days int) time.Time {
+	return time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+}
@@ -0,0 +1,159 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newSubscription(userID, externalID string, plan domain.Plan, periodEnd time.Time) domain.Subscription {
+	return domain.Subscription{
+		UserID:                 userID,
+		PlanID:                 plan.ID,
+		PlanVersion:            plan.Version,
+		Status:                 domain.SubscriptionStatusActive,
+		CurrentPeriodStart:     periodEnd.AddDate(0, -1, 0),
+		CurrentPeriodEnd:       periodEnd,
+		ExternalSubscriptionID: externalID,
+	}
+}
+
+func subscriptionExternalID(s *domain.Subscription) string { return s.ExternalSubscriptionID }
+
+func testSubscriptions(t *testing.T, newStore Factory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		subs := store.Subscription()
+		plan := createPlan(t, store.Plan(), "contract_basic")
+
+		sub := newSubscription("user-1", "sub_contract_1", plan, testTime(0))
+		sub.Metadata = map[string]interface{}{"source": "contract"}
+		created, err := subs.Create(ctx, sub)
+		requireNoError(t, err)
+		if created.ID == uuid.Nil || created.CreatedAt.IsZero() {
+			t.Fatalf("expected Create to assign an ID and timestamps, got %+v", created)
+		}
+		if created.PlanID != plan.ID || created.PlanVersion != 1 || !created.CurrentPeriodEnd.Equal(testTime(0)) {
+			t.Errorf("unexpected created subscription: %+v", created)
+		}
+		if created.Metadata["source"] != "contract" {
+			t.Errorf("expected metadata to round-trip, got %v", created.Metadata)
+		}
+
+		_, err = subs.Create(ctx, sub)
+		expectCode(t, err, domain.ErrCodeAlreadyExists)
+
+		unknownPlan := newSubscription("user-1", "sub_contract_2", newPlan("contract_missing"), testTime(0))
+		unknownPlan.PlanID = domain.PlanUUID("contract_missing")
+		_, err = subs.Create(ctx, unknownPlan)
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		byID, err := subs.GetByID(ctx, created.ID)
+		requireNoError(t, err)
+		if byID.ExternalSubscriptionID != "sub_contract_1" || byID.CancelledAt != nil {
+			t.Errorf("unexpected subscription: %+v", byID)
+		}
+
+		byExternal, err := subs.GetByExternalID(ctx, "sub_contract_1")
+		requireNoError(t, err)
+		if byExternal.ID != created.ID {
+			t.Errorf("expected subscription %s by external ID, got %s", created.ID, byExternal.ID)
+		}
+
+		_, err = subs.GetByID(ctx, uuid.New())
+		expectCode(t, err, domain.ErrCodeNotFound)
+		_, err = subs.GetByExternalID(ctx, "sub_missing")
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		subs := store.Subscription()
+		plan := createPlan(t, store.Plan(), "contract_basic")
+
+		created, err := subs.Create(ctx, newSubscription("user-1", "sub_contract_1", plan, testTime(0)))
+		requireNoError(t, err)
+		if created.Metadata != nil {
+			t.Errorf("expected no metadata, got %v", created.Metadata)
+		}
+
+		cancelledAt := testTime(-1)
+		created.Status = domain.SubscriptionStatusCancelled
+		created.CancelAtPeriodEnd = true
+		created.CancelledAt = &cancelledAt
+		created.CurrentPeriodEnd = testTime(5)
+		created.Metadata = map[string]interface{}{"reason": "too_expensive"}
+		updated, err := subs.Update(ctx, *created)
+		requireNoError(t, err)
+		if updated.Status != domain.SubscriptionStatusCancelled || !updated.CancelAtPeriodEnd || !updated.CurrentPeriodEnd.Equal(testTime(5)) {
+			t.Errorf("expected the update to be stored, got %+v", updated)
+		}
+		if updated.CancelledAt == nil || !updated.CancelledAt.Equal(cancelledAt) || updated.Metadata["reason"] != "too_expensive" {
+			t.Errorf("expected the update to be stored, got %+v", updated)
+		}
+
+		missing := *created
+		missing.ID = uuid.New()
+		_, err = subs.Update(ctx, missing)
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		requireNoError(t, subs.Delete(ctx, created.ID))
+		_, err = subs.GetByID(ctx, created.ID)
+		expectCode(t, err, domain.ErrCodeNotFound)
+	})
+
+	t.Run("Queries", func(t *testing.T) {
+		ctx := context.Background()
+		store := newStore(t)
+		subs := store.Subscription()
+		basic := createPlan(t, store.Plan(), "contract_basic")
+		pro := createPlan(t, store.Plan(), "contract_pro")
+
+		var created []*domain.Subscription
+		for _, s := range []domain.Subscription{
+			newSubscription("user-1", "sub_a", basic, testTime(10)),
+			newSubscription("user-1", "sub_b", pro, testTime(2)),
+			newSubscription("user-2", "sub_c", basic, testTime(5)),
+			newSubscription("user-2", "sub_d", basic, testTime(1)),
+		} {
+			sub, err := subs.Create(ctx, s)
+			requireNoError(t, err)
+			created = append(created, sub)
+		}
+		created[3].Status = domain.SubscriptionStatusPastDue
+		_, err := subs.Update(ctx, *created[3])
+		requireNoError(t, err)
+
+		byUser, err := subs.GetByUserID(ctx, "user-1")
+		requireNoError(t, err)
+		expectOrder(t, "GetByUserID", ids(byUser, subscriptionExternalID), []string{"sub_b", "sub_a"})
+
+		pastDue, err := subs.GetByStatus(ctx, domain.SubscriptionStatusPastDue)
+		requireNoError(t, err)
+		expectOrder(t, "GetByStatus", ids(pastDue, subscriptionExternalID), []string{"sub_d"})
+
+		active, err := subs.GetActiveSubscriptions(ctx)
+		requireNoError(t, err)
+		expectOrder(t, "GetActiveSubscriptions", ids(active, subscriptionExternalID), []string{"sub_c", "sub_b", "sub_a"})
+
+		expiring, err := subs.GetExpiringSubscriptions(ctx, testTime(5))
+		requireNoError(t, err)
+		expectOrder(t, "GetExpiringSubscriptions", ids(expiring, subscriptionExternalID), []string{"sub_b", "sub_c"})
+
+		byPlan, err := subs.GetSubscriptionsByPlan(ctx, basic.ID)
+		requireNoError(t, err)
+		expectOrder(t, "GetSubscriptionsByPlan", ids(byPlan, subscriptionExternalID), []string{"sub_d", "sub_c", "sub_a"})
+
+		_, err = subs.GetSubscriptionsByPlan(ctx, domain.PlanUUID("contract_missing"))
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		between, err := subs.ListCreatedBetween(ctx, created[1].CreatedAt, created[3].CreatedAt)
+		requireNoError(t, err)
+		expectOrder(t, "ListCreatedBetween", ids(between, subscriptionExternalID), []string{"sub_b", "sub_c"})
+	})
+}
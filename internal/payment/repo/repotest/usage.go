@@ -0,0 +1,96 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func newUsage(userID, resourceType string, size int64) domain.Usage {
+	return domain.Usage{
+		UserID:       userID,
+		FeatureCode:  "storage",
+		ResourceType: resourceType,
+		ResourceSize: size,
+		Operation:    "upload",
+	}
+}
+
+func testUsage(t *testing.T, newStore Factory) {
+	t.Run("CurrentUsageAndHistory", func(t *testing.T) {
+		ctx := context.Background()
+		usage := newStore(t).Usage()
+
+		first := newUsage("user-1", "bytes", 100)
+		first.Metadata = map[string]interface{}{"file": "a.jpg"}
+		requireNoError(t, usage.Create(ctx, first))
+		requireNoError(t, usage.Create(ctx, newUsage("user-1", "bytes", 250)))
+		requireNoError(t, usage.Create(ctx, newUsage("user-1", "files", 1)))
+		requireNoError(t, usage.Create(ctx, newUsage("user-2", "bytes", 999)))
+
+		total, err := usage.GetCurrentUsage(ctx, "user-1", "storage", "bytes", time.Hour)
+		requireNoError(t, err)
+		if total != 350 {
+			t.Errorf("expected 350 bytes in the last hour, got %d", total)
+		}
+
+		none, err := usage.GetCurrentUsage(ctx, "user-3", "storage", "bytes", time.Hour)
+		requireNoError(t, err)
+		if none != 0 {
+			t.Errorf("expected no usage for an unknown user, got %d", none)
+		}
+
+		history, err := usage.GetUsageHistory(ctx, "user-1", "storage", "bytes", time.Hour)
+		requireNoError(t, err)
+		if len(history) != 2 || history[0].ResourceSize != 250 || history[1].ResourceSize != 100 {
+			t.Fatalf("expected the two byte records newest first, got %+v", history)
+		}
+		if history[1].Metadata["file"] != "a.jpg" || history[1].Operation != "upload" || history[1].ID == uuid.Nil {
+			t.Errorf("unexpected usage record: %+v", history[1])
+		}
+
+		byID, err := usage.GetUsageByID(ctx, history[0].ID)
+		requireNoError(t, err)
+		if byID.ResourceSize != 250 || !byID.CreatedAt.Equal(history[0].CreatedAt) {
+			t.Errorf("unexpected usage record: %+v", byID)
+		}
+		_, err = usage.GetUsageByID(ctx, uuid.New())
+		expectCode(t, err, domain.ErrCodeNotFound)
+
+		requireNoError(t, usage.DeleteUsage(ctx, "user-1", "storage", "bytes"))
+		total, err = usage.GetCurrentUsage(ctx, "user-1", "storage", "bytes", time.Hour)
+		requireNoError(t, err)
+		if total != 0 {
+			t.Errorf("expected no bytes after delete, got %d", total)
+		}
+		files, err := usage.GetCurrentUsage(ctx, "user-1", "storage", "files", time.Hour)
+		requireNoError(t, err)
+		if files != 1 {
+			t.Errorf("expected delete to leave other resource types alone, got %d files", files)
+		}
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		ctx := context.Background()
+		usage := newStore(t).Usage()
+
+		for _, size := range []int64{1, 2, 3} {
+			requireNoError(t, usage.Create(ctx, newUsage("user-1", "bytes", size)))
+		}
+
+		page, err := usage.ListUsageByUser(ctx, "user-1", 2, 0)
+		requireNoError(t, err)
+		if len(page) != 2 || page[0].ResourceSize != 3 || page[1].ResourceSize != 2 {
+			t.Errorf("expected the two newest records, got %+v", page)
+		}
+
+		page, err = usage.ListUsageByUser(ctx, "user-1", 2, 2)
+		requireNoError(t, err)
+		if len(page) != 1 || page[0].ResourceSize != 1 {
+			t.Errorf("expected the oldest record, got %+v", page)
+		}
+	})
+}
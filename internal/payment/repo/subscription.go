@@ -39,4 +39,7 @@ type SubscriptionRepository interface {
 
 	// GetSubscriptionsByPlan retrieves subscriptions for a specific plan
 	GetSubscriptionsByPlan(ctx context.Context, planID uuid.UUID) ([]*domain.Subscription, error)
+
+	// ListCreatedBetween retrieves subscriptions created in [from, to), oldest first
+	ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*domain.Subscription, error)
 }
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/jia-app/paymentservice/internal/payment/repo"
 	"github.com/jia-app/paymentservice/internal/shared/events"
@@ -17,9 +18,11 @@ import (
 
 // LifecycleManager handles subscription lifecycle operations
 type LifecycleManager struct {
-	subscriptionRepo repo.SubscriptionRepository
-	entitlementRepo  repo.EntitlementRepository
-	eventPublisher   events.SubscriptionPublisher
+	subscriptionRepo     repo.SubscriptionRepository
+	entitlementRepo      repo.EntitlementRepository
+	eventPublisher       events.SubscriptionPublisher
+	entitlementPublisher events.EntitlementPublisher // Can be nil if entitlement events are disabled
+	auditor              *audit.Recorder             // Can be nil if auditing is disabled
 }
 
 // NewLifecycleManager creates a new subscription lifecycle manager
@@ -27,11 +30,15 @@ func NewLifecycleManager(
 	subscriptionRepo repo.SubscriptionRepository,
 	entitlementRepo repo.EntitlementRepository,
 	eventPublisher events.SubscriptionPublisher,
+	entitlementPublisher events.EntitlementPublisher,
+	auditor *audit.Recorder,
 ) *LifecycleManager {
 	return &LifecycleManager{
-		subscriptionRepo: subscriptionRepo,
-		entitlementRepo:  entitlementRepo,
-		eventPublisher:   eventPublisher,
+		subscriptionRepo:     subscriptionRepo,
+		entitlementRepo:      entitlementRepo,
+		eventPublisher:       eventPublisher,
+		entitlementPublisher: entitlementPublisher,
+		auditor:              auditor,
 	}
 }
 
@@ -42,6 +49,7 @@ func (lm *LifecycleManager) CreateSubscription(ctx context.Context, req CreateSu
 		UserID:                 req.UserID,
 		FamilyID:               req.FamilyID,
 		PlanID:                 req.PlanID,
+		PlanVersion:            req.PlanVersion,
 		Status:                 domain.SubscriptionStatusActive,
 		CurrentPeriodStart:     req.CurrentPeriodStart,
 		CurrentPeriodEnd:       req.CurrentPeriodEnd,
@@ -72,6 +80,11 @@ func (lm *LifecycleManager) CreateSubscription(ctx context.Context, req CreateSu
 	return savedSubscription, nil
 }
 
+// GetByExternalID retrieves the subscription recorded for a provider subscription ID
+func (lm *LifecycleManager) GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error) {
+	return lm.subscriptionRepo.GetByExternalID(ctx, externalID)
+}
+
 // UpdateStatus updates subscription status with proper lifecycle transitions
 func (lm *LifecycleManager) UpdateStatus(ctx context.Context, subscriptionID uuid.UUID, newStatus string, reason string) error {
 	subscription, err := lm.subscriptionRepo.GetByID(ctx, subscriptionID)
@@ -84,6 +97,7 @@ func (lm *LifecycleManager) UpdateStatus(ctx context.Context, subscriptionID uui
 		return status.Errorf(codes.InvalidArgument, "invalid status transition from %s to %s", subscription.Status, newStatus)
 	}
 
+	before := *subscription
 	oldStatus := subscription.Status
 	subscription.Status = newStatus
 	subscription.UpdatedAt = time.Now()
@@ -94,7 +108,7 @@ func (lm *LifecycleManager) UpdateStatus(ctx context.Context, subscriptionID uui
 		subscription.CancelledAt = &subscription.UpdatedAt
 	case domain.SubscriptionStatusExpired:
 		// Expired subscriptions should have their entitlements revoked
-		if err := lm.revokeEntitlements(ctx, subscription); err != nil {
+		if err := lm.revokeEntitlements(ctx, subscription, reason); err != nil {
 			log.Error(ctx, "Failed to revoke entitlements for expired subscription", zap.Error(err))
 		}
 	}
@@ -105,6 +119,15 @@ func (lm *LifecycleManager) UpdateStatus(ctx context.Context, subscriptionID uui
 		return status.Errorf(codes.Internal, "failed to update subscription: %v", err)
 	}
 
+	lm.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntitySubscription,
+		EntityID:   subscriptionID.String(),
+		Action:     domain.AuditActionStatusChange,
+		Before:     &before,
+		After:      updatedSubscription,
+		Reason:     reason,
+	})
+
 	// Publish status change event
 	if lm.eventPublisher != nil {
 		if err := lm.eventPublisher.PublishSubscriptionStatusChanged(ctx, updatedSubscription, oldStatus, reason); err != nil {
@@ -180,6 +203,7 @@ func (lm *LifecycleManager) RenewSubscription(ctx context.Context, subscriptionI
 		return status.Errorf(codes.InvalidArgument, "cannot renew subscription with status %s", subscription.Status)
 	}
 
+	before := *subscription
 	subscription.CurrentPeriodStart = subscription.CurrentPeriodEnd
 	subscription.CurrentPeriodEnd = newPeriodEnd
 	subscription.UpdatedAt = time.Now()
@@ -189,6 +213,14 @@ func (lm *LifecycleManager) RenewSubscription(ctx context.Context, subscriptionI
 		return status.Errorf(codes.Internal, "failed to renew subscription: %v", err)
 	}
 
+	lm.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntitySubscription,
+		EntityID:   subscriptionID.String(),
+		Action:     domain.AuditActionRenew,
+		Before:     &before,
+		After:      updatedSubscription,
+	})
+
 	// Publish renewal event
 	if lm.eventPublisher != nil {
 		if err := lm.eventPublisher.PublishSubscriptionRenewed(ctx, updatedSubscription); err != nil {
@@ -241,7 +273,7 @@ func (lm *LifecycleManager) isValidStatusTransition(from, to string) bool {
 }
 
 // revokeEntitlements revokes all entitlements for a subscription
-func (lm *LifecycleManager) revokeEntitlements(ctx context.Context, subscription *domain.Subscription) error {
+func (lm *LifecycleManager) revokeEntitlements(ctx context.Context, subscription *domain.Subscription, reason string) error {
 	// Get all entitlements for this subscription
 	entitlements, err := lm.entitlementRepo.GetBySubscriptionID(ctx, subscription.ExternalSubscriptionID)
 	if err != nil {
@@ -250,14 +282,32 @@ func (lm *LifecycleManager) revokeEntitlements(ctx context.Context, subscription
 
 	// Revoke each entitlement
 	for _, entitlement := range entitlements {
+		before := entitlement
 		entitlement.Status = "revoked"
 		entitlement.UpdatedAt = time.Now()
 
-		if _, err := lm.entitlementRepo.Update(ctx, entitlement); err != nil {
+		updated, err := lm.entitlementRepo.Update(ctx, entitlement)
+		if err != nil {
 			log.Error(ctx, "Failed to revoke entitlement",
 				zap.String("entitlement_id", entitlement.ID.String()),
 				zap.Error(err))
+			continue
 		}
+
+		if lm.entitlementPublisher != nil {
+			if err := lm.entitlementPublisher.PublishEntitlementUpdated(ctx, updated, events.EntitlementChangeRevoked); err != nil {
+				log.Warn(ctx, "Failed to publish entitlement revoked event", zap.Error(err))
+			}
+		}
+
+		lm.auditor.Record(ctx, audit.Change{
+			EntityType: domain.AuditEntityEntitlement,
+			EntityID:   entitlement.ID.String(),
+			Action:     domain.AuditActionRevoke,
+			Before:     &before,
+			After:      updated,
+			Reason:     reason,
+		})
 	}
 
 	return nil
@@ -269,6 +319,7 @@ type CreateSubscriptionRequest struct {
 	UserID                 string                 `json:"user_id"`
 	FamilyID               *string                `json:"family_id,omitempty"`
 	PlanID                 uuid.UUID              `json:"plan_id"`
+	PlanVersion            int32                  `json:"plan_version,omitempty"` // Plan version the subscription is sold at
 	CurrentPeriodStart     time.Time              `json:"current_period_start"`
 	CurrentPeriodEnd       time.Time              `json:"current_period_end"`
 	ExternalSubscriptionID string                 `json:"external_subscription_id"`
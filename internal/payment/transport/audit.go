@@ -0,0 +1,67 @@
+package transport
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// ListAuditEvents lists recorded changes newest first
+func (s *PaymentService) ListAuditEvents(ctx context.Context, req *paymentv1.ListAuditEventsRequest) (*paymentv1.ListAuditEventsResponse, error) {
+	filter := domain.AuditFilter{
+		EntityType: domain.AuditEntityType(req.EntityType),
+		EntityID:   req.EntityId,
+		ActorID:    req.ActorId,
+	}
+
+	page, err := s.auditUseCase.ListAuditEvents(ctx, filter, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbEvents := make([]*paymentv1.AuditEvent, len(page.Events))
+	for i, event := range page.Events {
+		pbEvents[i] = auditEventToProto(event)
+	}
+
+	return &paymentv1.ListAuditEventsResponse{
+		Events:        pbEvents,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// auditEventToProto converts a domain audit event to protobuf
+func auditEventToProto(event *domain.AuditEvent) *paymentv1.AuditEvent {
+	fields := make([]string, 0, len(event.Changes))
+	for field := range event.Changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	changes := make([]*paymentv1.AuditFieldChange, len(fields))
+	for i, field := range fields {
+		change := event.Changes[field]
+		changes[i] = &paymentv1.AuditFieldChange{
+			Field:      field,
+			BeforeJson: string(change.Before),
+			AfterJson:  string(change.After),
+		}
+	}
+
+	return &paymentv1.AuditEvent{
+		Id:         event.ID.String(),
+		EntityType: string(event.EntityType),
+		EntityId:   event.EntityID,
+		Action:     event.Action,
+		ActorType:  string(event.ActorType),
+		ActorId:    event.ActorID,
+		Changes:    changes,
+		RequestId:  event.RequestID,
+		Reason:     event.Reason,
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+}
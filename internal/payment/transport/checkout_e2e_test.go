@@ -0,0 +1,518 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/billing/paypalbp"
+	"github.com/jia-app/paymentservice/internal/billing/stripebp"
+	"github.com/jia-app/paymentservice/internal/billing/stripebp/stripetest"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+	"github.com/jia-app/paymentservice/internal/shared/config"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// e2ePlanRepo serves a fixed plan catalog
+type e2ePlanRepo struct {
+	repo.PlanRepository
+	plans map[string]domain.Plan
+}
+
+func (r *e2ePlanRepo) GetByID(ctx context.Context, id string) (domain.Plan, error) {
+	plan, ok := r.plans[id]
+	if !ok {
+		return domain.Plan{}, domain.NewNotFoundError("plan", id)
+	}
+	return plan, nil
+}
+
+func (r *e2ePlanRepo) GetByIDIncludingArchived(ctx context.Context, id string) (domain.Plan, error) {
+	return r.GetByID(ctx, id)
+}
+
+// e2ePricingZoneRepo has no pricing zones, so every country pays the base price
+type e2ePricingZoneRepo struct {
+	repo.PricingZoneRepository
+}
+
+func (r *e2ePricingZoneRepo) GetByISOCode(ctx context.Context, isoCode string) (domain.PricingZone, error) {
+	return domain.PricingZone{}, domain.NewNotFoundError("pricing zone", isoCode)
+}
+
+// e2eStore keeps the checkout sessions, payments and entitlements the
+// checkout flow records
+type e2eStore struct {
+	mu           sync.Mutex
+	sessions     map[uuid.UUID]domain.CheckoutSession
+	payments     map[string]*domain.Payment
+	entitlements []domain.Entitlement
+	failPayments bool // Fail payment status updates, as a database outage would
+	failGrants   bool // Fail entitlement inserts, as a database outage would
+}
+
+type e2eSessionRepo struct {
+	repo.CheckoutSessionRepository
+	*e2eStore
+}
+
+func (r e2eSessionRepo) Create(ctx context.Context, session domain.CheckoutSession) (domain.CheckoutSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session.ID = uuid.New()
+	r.sessions[session.ID] = session
+	return session, nil
+}
+
+func (r e2eSessionRepo) GetByProviderSessionID(ctx context.Context, provider, providerSessionID string) (domain.CheckoutSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.Provider == provider && session.ProviderSessionID == providerSessionID {
+			return session, nil
+		}
+	}
+	return domain.CheckoutSession{}, domain.NewNotFoundError("checkout session", providerSessionID)
+}
+
+func (r e2eSessionRepo) ListByProviderSessionID(ctx context.Context, providerSessionID string) ([]domain.CheckoutSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []domain.CheckoutSession
+	for _, session := range r.sessions {
+		if session.ProviderSessionID == providerSessionID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r e2eSessionRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status domain.CheckoutSessionStatus) (domain.CheckoutSession, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	session.Status = status
+	r.sessions[id] = session
+	return session, nil
+}
+
+func (r e2eSessionRepo) ClaimForCompletion(ctx context.Context, id uuid.UUID, staleBefore time.Time) (domain.CheckoutSession, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session := r.sessions[id]
+	switch session.Status {
+	case domain.CheckoutSessionStatusComplete, domain.CheckoutSessionStatusCompleting:
+		return session, false, nil
+	}
+	session.Status = domain.CheckoutSessionStatusCompleting
+	r.sessions[id] = session
+	return session, true, nil
+}
+
+type e2ePaymentRepo struct {
+	repo.PaymentRepository
+	*e2eStore
+}
+
+func (r e2ePaymentRepo) Create(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payments[payment.ID.String()] = payment
+	return nil
+}
+
+func (r e2ePaymentRepo) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			return payment, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("payment", orderID)
+}
+
+func (r e2ePaymentRepo) UpdateStatus(ctx context.Context, id string, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failPayments {
+		return errors.New("connection refused")
+	}
+	r.payments[id].Status = status
+	return nil
+}
+
+type e2eEntitlementRepo struct {
+	repo.EntitlementRepository
+	*e2eStore
+}
+
+func (r e2eEntitlementRepo) Check(ctx context.Context, userID, featureCode string) (domain.Entitlement, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.entitlements {
+		if e.UserID == userID && e.FeatureCode == featureCode {
+			return e, true, nil
+		}
+	}
+	return domain.Entitlement{}, false, nil
+}
+
+func (r e2eEntitlementRepo) Insert(ctx context.Context, e domain.Entitlement) (domain.Entitlement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failGrants {
+		return domain.Entitlement{}, errors.New("connection refused")
+	}
+	r.entitlements = append(r.entitlements, e)
+	return e, nil
+}
+
+// TestCheckoutEndToEnd drives a checkout through PaymentService against the
+// fake Stripe API: the session is opened with Stripe, paid on the fake's
+// hosted page, and the signed completion webhook grants the plan's features
+func TestCheckoutEndToEnd(t *testing.T) {
+	const webhookSecret = "whsec_e2e"
+	fake := stripetest.NewServer(stripetest.Config{WebhookSecret: webhookSecret})
+	t.Cleanup(fake.Close)
+
+	cfg := &config.Config{}
+	cfg.Billing.StripeWebhookSecret = webhookSecret
+	provider := stripebp.NewAdapter(stripebp.Config{
+		SecretKey:     "sk_test_e2e",
+		WebhookSecret: webhookSecret,
+		BaseURL:       fake.URL,
+	}, zap.NewNop())
+
+	store := &e2eStore{sessions: make(map[uuid.UUID]domain.CheckoutSession), payments: make(map[string]*domain.Payment)}
+	plans := &e2ePlanRepo{plans: map[string]domain.Plan{
+		"pro_monthly": {
+			ID:           domain.PlanUUID("pro_monthly"),
+			Code:         "pro_monthly",
+			FeatureCodes: []string{"pro_storage", "priority_support"},
+			UsageLimits:  json.RawMessage(`{"storage_gb": 50}`),
+			PriceDollars: 9.99,
+			Currency:     "USD",
+			Active:       true,
+			Version:      1,
+		},
+	}}
+	catalog := memory.NewStore()
+	for _, feature := range []domain.Feature{
+		{Code: "pro_storage", QuotaSchema: map[string]domain.QuotaType{"storage_gb": domain.QuotaTypeInteger}, DefaultLimits: json.RawMessage(`{"storage_gb": 100}`)},
+		{Code: "priority_support"},
+	} {
+		if _, err := catalog.Feature().Create(context.Background(), feature); err != nil {
+			t.Fatalf("failed to create feature: %v", err)
+		}
+	}
+	features := usecase.NewFeatureCatalogUseCase(catalog.Feature(), plans, nil)
+	entitlements := e2eEntitlementRepo{e2eStore: store}
+	checkout := usecase.NewCheckoutUseCase(plans, entitlements, &e2ePricingZoneRepo{}, e2ePaymentRepo{e2eStore: store}, e2eSessionRepo{e2eStore: store},
+		features, provider, "stripe", usecase.DefaultCheckoutExpiryConfig(), nil, nil, nil, usecase.CheckoutOptions{})
+	svc := NewPaymentService(cfg, nil, usecase.NewEntitlementUseCase(entitlements, features, nil, nil, nil, nil, nil), nil, checkout,
+		nil, nil, features, nil, nil, nil, nil, nil, nil, nil, provider, nil)
+	ctx := context.Background()
+	user1 := log.WithUserID(ctx, "user-1")
+	user2 := log.WithUserID(ctx, "user-2")
+
+	created, err := svc.CreateCheckoutSession(ctx, &paymentv1.CreateCheckoutSessionRequest{
+		PlanId:      "pro_monthly",
+		UserId:      "user-1",
+		CountryCode: "US",
+		SuccessUrl:  "https://jia.app/success",
+		CancelUrl:   "https://jia.app/cancel",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check := func() bool {
+		t.Helper()
+		resp, err := svc.CheckEntitlement(ctx, &paymentv1.CheckEntitlementRequest{UserId: "user-1", FeatureCode: "pro_storage"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp.Allowed
+	}
+	if check() {
+		t.Fatal("expected no entitlement before the checkout is paid")
+	}
+
+	completed, err := fake.CompleteCheckout(created.SessionId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A forged webhook is turned away before it can grant anything
+	_, err = svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: "t=1,v1=forged", Provider: "stripe"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected a forged signature to be rejected, got %v", err)
+	}
+	if check() {
+		t.Fatal("expected no entitlement from a forged webhook")
+	}
+
+	// A payment that cannot be completed fails the webhook, so that Stripe
+	// retries it, and leaves the session open
+	store.failPayments = true
+	if _, err := svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: completed.Signature, Provider: "stripe"}); err == nil {
+		t.Fatal("expected the webhook to fail while its payment cannot be completed")
+	}
+	if check() {
+		t.Fatal("expected no entitlement before the payment is completed")
+	}
+	if session, err := checkout.GetCheckoutSession(user1, created.SessionId); err != nil || session.Status != domain.CheckoutSessionStatusOpen {
+		t.Fatalf("expected the failed webhook to leave the session open, got %+v, %v", session, err)
+	}
+	store.failPayments = false
+
+	// So does an entitlement that cannot be granted
+	store.failGrants = true
+	if _, err := svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: completed.Signature, Provider: "stripe"}); err == nil {
+		t.Fatal("expected the webhook to fail while its entitlements cannot be granted")
+	}
+	if session, err := checkout.GetCheckoutSession(user1, created.SessionId); err != nil || session.Status != domain.CheckoutSessionStatusOpen {
+		t.Fatalf("expected the failed grant to leave the session open, got %+v, %v", session, err)
+	}
+	store.failGrants = false
+
+	for i := 0; i < 2; i++ { // Stripe redelivers webhooks; the second is a no-op
+		if _, err := svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: completed.Signature, Provider: "stripe"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !check() {
+		t.Error("expected the paid checkout to grant the plan's features")
+	}
+	if len(store.entitlements) != 2 {
+		t.Errorf("expected one entitlement per plan feature, got %+v", store.entitlements)
+	}
+	for _, e := range store.entitlements {
+		if e.FeatureCode == "pro_storage" && string(e.UsageLimits) != `{"storage_gb":50}` {
+			t.Errorf("expected the plan's storage limit, got %s", e.UsageLimits)
+		}
+	}
+	if _, err := svc.CheckEntitlement(ctx, &paymentv1.CheckEntitlementRequest{UserId: "user-1", FeatureCode: "premium_feature"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected a feature missing from the catalog to be rejected, got %v", err)
+	}
+
+	session, err := checkout.GetCheckoutSession(user1, created.SessionId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if session.Status != domain.CheckoutSessionStatusComplete {
+		t.Errorf("expected the session to be complete, got %s", session.Status)
+	}
+	if _, err := svc.GetCheckoutSession(user2, &paymentv1.GetCheckoutSessionRequest{SessionId: created.SessionId}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected another user's session to be hidden, got %v", err)
+	}
+
+	// Sessions routed to another provider are read with the provider they
+	// were opened with
+	routed := domain.CheckoutSession{ID: uuid.New(), Provider: "paypal", ProviderSessionID: "I-ROUTED1", UserID: "user-1", Status: domain.CheckoutSessionStatusComplete}
+	store.mu.Lock()
+	store.sessions[routed.ID] = routed
+	store.mu.Unlock()
+	if session, err := checkout.GetCheckoutSession(user1, routed.ProviderSessionID); err != nil || session.Provider != "paypal" {
+		t.Errorf("expected the routed session to be found, got %+v, %v", session, err)
+	}
+	for _, payment := range store.payments {
+		if payment.Status != string(domain.PaymentStatusCompleted) || payment.Provider != "stripe" || payment.Amount != 9.99 {
+			t.Errorf("unexpected payment %+v", payment)
+		}
+	}
+
+	// A charge that differs from the quote is held for review and grants nothing
+	held, err := svc.CreateCheckoutSession(ctx, &paymentv1.CreateCheckoutSessionRequest{
+		PlanId:      "pro_monthly",
+		UserId:      "user-2",
+		CountryCode: "US",
+		SuccessUrl:  "https://jia.app/success",
+		CancelUrl:   "https://jia.app/cancel",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.CancelCheckoutSession(user1, &paymentv1.CancelCheckoutSessionRequest{SessionId: held.SessionId}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected another user's session not to be cancellable, got %v", err)
+	}
+	store.mu.Lock()
+	for id, session := range store.sessions {
+		if session.ProviderSessionID == held.SessionId {
+			session.Tax.TotalCents = 1999
+			store.sessions[id] = session
+		}
+	}
+	store.mu.Unlock()
+	completed, err = fake.CompleteCheckout(held.SessionId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: completed.Signature, Provider: "stripe"}); err != nil {
+			t.Fatalf("expected the held webhook to be acknowledged, got %v", err)
+		}
+	}
+	if session, err := checkout.GetCheckoutSession(user2, held.SessionId); err != nil || session.Status != domain.CheckoutSessionStatusNeedsReview {
+		t.Errorf("expected the session to need review, got %+v, %v", session, err)
+	}
+	if len(store.entitlements) != 2 {
+		t.Errorf("expected nothing granted for the held session, got %+v", store.entitlements)
+	}
+	for _, payment := range store.payments {
+		if payment.OrderID == held.SessionId && payment.Status != string(domain.PaymentStatusPending) {
+			t.Errorf("expected the held payment to stay pending, got %s", payment.Status)
+		}
+	}
+}
+
+// TestCheckoutEndToEnd_GrandfatheredPlanVersion changes a plan while its
+// customer is on the checkout page: the paid checkout grants the features
+// and limits of the version it was quoted at, not the plan's current ones
+func TestCheckoutEndToEnd_GrandfatheredPlanVersion(t *testing.T) {
+	const webhookSecret = "whsec_e2e"
+	fake := stripetest.NewServer(stripetest.Config{WebhookSecret: webhookSecret})
+	t.Cleanup(fake.Close)
+
+	cfg := &config.Config{}
+	cfg.Billing.StripeWebhookSecret = webhookSecret
+	provider := stripebp.NewAdapter(stripebp.Config{
+		SecretKey:     "sk_test_e2e",
+		WebhookSecret: webhookSecret,
+		BaseURL:       fake.URL,
+	}, zap.NewNop())
+	ctx := context.Background()
+
+	catalog := memory.NewStore()
+	features := usecase.NewFeatureCatalogUseCase(catalog.Feature(), catalog.Plan(), nil)
+	for _, feature := range []domain.Feature{
+		{Code: "pro_storage", QuotaSchema: map[string]domain.QuotaType{"storage_gb": domain.QuotaTypeInteger}, DefaultLimits: json.RawMessage(`{"storage_gb": 100}`)},
+		{Code: "priority_support"},
+	} {
+		if _, err := features.CreateFeature(ctx, feature); err != nil {
+			t.Fatalf("failed to create feature: %v", err)
+		}
+	}
+	plans := usecase.NewPlanCatalogUseCase(catalog.Plan(), features, nil)
+	if _, err := plans.CreatePlan(ctx, domain.Plan{
+		Code:         "pro_monthly",
+		Name:         "Pro",
+		FeatureCodes: []string{"pro_storage", "priority_support"},
+		BillingCycle: domain.BillingCycleMonthly,
+		PriceDollars: 9.99,
+		Currency:     "USD",
+		MaxUsers:     1,
+		UsageLimits:  json.RawMessage(`{"storage_gb": 50}`),
+	}); err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+
+	store := &e2eStore{sessions: make(map[uuid.UUID]domain.CheckoutSession), payments: make(map[string]*domain.Payment)}
+	entitlements := e2eEntitlementRepo{e2eStore: store}
+	checkout := usecase.NewCheckoutUseCase(catalog.Plan(), entitlements, &e2ePricingZoneRepo{}, e2ePaymentRepo{e2eStore: store}, e2eSessionRepo{e2eStore: store},
+		features, provider, "stripe", usecase.DefaultCheckoutExpiryConfig(), nil, nil, nil, usecase.CheckoutOptions{})
+	svc := NewPaymentService(cfg, nil, usecase.NewEntitlementUseCase(entitlements, features, nil, nil, nil, nil, nil), nil, checkout,
+		nil, nil, features, nil, nil, nil, nil, nil, nil, nil, provider, nil)
+
+	created, err := svc.CreateCheckoutSession(ctx, &paymentv1.CreateCheckoutSessionRequest{
+		PlanId:      "pro_monthly",
+		UserId:      "user-1",
+		CountryCode: "US",
+		SuccessUrl:  "https://jia.app/success",
+		CancelUrl:   "https://jia.app/cancel",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated, versioned, err := plans.UpdatePlan(ctx, "pro_monthly", domain.PlanUpdate{
+		FeatureCodes: []string{"pro_storage"},
+		UsageLimits:  json.RawMessage(`{"storage_gb": 20}`),
+	})
+	if err != nil || !versioned || updated.Version != 2 {
+		t.Fatalf("expected the update to create version 2, got %+v, %v, %v", updated, versioned, err)
+	}
+
+	completed, err := fake.CompleteCheckout(created.SessionId)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ProcessWebhook(ctx, &paymentv1.ProcessWebhookRequest{Payload: completed.Payload, Signature: completed.Signature, Provider: "stripe"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	granted := make(map[string]string, len(store.entitlements))
+	for _, e := range store.entitlements {
+		granted[e.FeatureCode] = string(e.UsageLimits)
+	}
+	if len(granted) != 2 || granted["pro_storage"] != `{"storage_gb":50}` || granted["priority_support"] != `{}` {
+		t.Errorf("expected version 1's features and limits, got %v", granted)
+	}
+}
+
+// TestCheckoutEndToEnd_PayPalRenewal renews a PayPal subscription whose
+// checkout completed long ago: the renewal's sale names the subscription,
+// not the checkout, so it is not mistaken for a redelivery of the completion
+func TestCheckoutEndToEnd_PayPalRenewal(t *testing.T) {
+	ctx := context.Background()
+	provider := paypalbp.NewAdapter(paypalbp.Config{}, zap.NewNop())
+
+	store := &e2eStore{sessions: make(map[uuid.UUID]domain.CheckoutSession), payments: make(map[string]*domain.Payment)}
+	plans := &e2ePlanRepo{plans: map[string]domain.Plan{
+		"pro_monthly": {
+			ID:           domain.PlanUUID("pro_monthly"),
+			Code:         "pro_monthly",
+			FeatureCodes: []string{"pro_storage"},
+			PriceDollars: 9.99,
+			Currency:     "USD",
+			Active:       true,
+			Version:      1,
+		},
+	}}
+	catalog := memory.NewStore()
+	if _, err := catalog.Feature().Create(ctx, domain.Feature{Code: "pro_storage"}); err != nil {
+		t.Fatalf("failed to create feature: %v", err)
+	}
+	features := usecase.NewFeatureCatalogUseCase(catalog.Feature(), plans, nil)
+	checkout := usecase.NewCheckoutUseCase(plans, e2eEntitlementRepo{e2eStore: store}, &e2ePricingZoneRepo{}, e2ePaymentRepo{e2eStore: store}, e2eSessionRepo{e2eStore: store},
+		features, provider, "paypal", usecase.DefaultCheckoutExpiryConfig(), nil, nil, nil, usecase.CheckoutOptions{})
+
+	// The subscription's checkout completed and its first period lapsed
+	// before the renewal came in
+	checkoutSession := domain.CheckoutSession{ID: uuid.New(), Provider: "paypal", ProviderSessionID: "I-SUB1", PlanCode: "pro_monthly", PlanVersion: 1, UserID: "user-1", Status: domain.CheckoutSessionStatusComplete}
+	store.sessions[checkoutSession.ID] = checkoutSession
+	store.entitlements = []domain.Entitlement{{ID: uuid.New(), UserID: "user-1", FeatureCode: "pro_storage", Status: "expired"}}
+
+	payload := `{"id":"WH-1","event_type":"PAYMENT.SALE.COMPLETED","resource":{"id":"SALE-1","state":"completed",` +
+		`"billing_agreement_id":"I-SUB1","custom":"plan_id=pro_monthly&user_id=user-1","amount":{"total":"9.99","currency":"USD"}}}`
+	renewal, err := provider.ParseWebhook(ctx, []byte(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	renewal.Provider = "paypal"
+	if err := checkout.ApplyWebhook(ctx, *renewal); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	renewed := false
+	for _, e := range store.entitlements {
+		if e.UserID == "user-1" && e.FeatureCode == "pro_storage" && e.Status == "active" {
+			renewed = true
+		}
+	}
+	if !renewed {
+		t.Errorf("expected the renewal to grant the plan's features again, got %+v", store.entitlements)
+	}
+}
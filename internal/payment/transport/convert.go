@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+)
+
+// paymentListQueryFromProto converts the listing fields shared by
+// ListPayments and GetPaymentsByCustomer into a domain query
+func paymentListQueryFromProto(filter *paymentv1.PaymentFilter, sortBy paymentv1.PaymentSortField, ascending bool, limit, offset int32) domain.PaymentListQuery {
+	query := domain.PaymentListQuery{
+		SortBy:   domain.PaymentSortByCreatedAt,
+		SortDesc: !ascending,
+		Limit:    int(limit),
+		Offset:   int(offset),
+	}
+	if sortBy == paymentv1.PaymentSortField_PAYMENT_SORT_FIELD_AMOUNT {
+		query.SortBy = domain.PaymentSortByAmount
+	}
+
+	if filter == nil {
+		return query
+	}
+
+	query.Filter = domain.PaymentFilter{
+		Status:            filter.Status,
+		Currency:          filter.Currency,
+		PaymentMethod:     filter.PaymentMethod,
+		ExternalPaymentID: filter.ExternalPaymentId,
+		MinAmount:         filter.MinAmount,
+		MaxAmount:         filter.MaxAmount,
+	}
+	if filter.CreatedAfter != nil {
+		t := filter.CreatedAfter.AsTime()
+		query.Filter.CreatedAfter = &t
+	}
+	if filter.CreatedBefore != nil {
+		t := filter.CreatedBefore.AsTime()
+		query.Filter.CreatedBefore = &t
+	}
+
+	return query
+}
+
+// paymentsToProto converts domain payment responses to protobuf payments
+func paymentsToProto(resps []*domain.PaymentResponse) []*paymentv1.Payment {
+	payments := make([]*paymentv1.Payment, len(resps))
+	for i, resp := range resps {
+		payments[i] = paymentToProto(resp)
+	}
+	return payments
+}
+
+// paymentToProto converts a domain payment response to a protobuf payment
+func paymentToProto(resp *domain.PaymentResponse) *paymentv1.Payment {
+	payment := &paymentv1.Payment{
+		Id:                resp.ID.String(),
+		Amount:            resp.Amount,
+		Currency:          resp.Currency,
+		Status:            resp.Status,
+		PaymentMethod:     resp.PaymentMethod,
+		CustomerId:        resp.CustomerID,
+		OrderId:           resp.OrderID,
+		Description:       resp.Description,
+		ExternalPaymentId: resp.ExternalPaymentID,
+		CreatedAt:         timestamppb.New(resp.CreatedAt),
+	}
+	if !resp.UpdatedAt.IsZero() {
+		payment.UpdatedAt = timestamppb.New(resp.UpdatedAt)
+	}
+	return payment
+}
+
+// entitlementToProto converts a domain entitlement to a protobuf entitlement
+func entitlementToProto(ent *domain.Entitlement) *paymentv1.Entitlement {
+	pbEntitlement := &paymentv1.Entitlement{
+		Id:          ent.ID.String(),
+		UserId:      ent.UserID,
+		FeatureCode: ent.FeatureCode,
+		PlanId:      ent.PlanID.String(),
+		Status:      ent.Status,
+		GrantedAt:   timestamppb.New(ent.GrantedAt),
+		CreatedAt:   timestamppb.New(ent.CreatedAt),
+		UpdatedAt:   timestamppb.New(ent.UpdatedAt),
+		Source:      ent.Source,
+	}
+
+	// Add optional fields
+	if ent.FamilyID != nil && *ent.FamilyID != "" {
+		pbEntitlement.FamilyId = *ent.FamilyID
+	}
+	if ent.SubscriptionID != nil && *ent.SubscriptionID != "" {
+		pbEntitlement.SubscriptionId = *ent.SubscriptionID
+	}
+	if ent.ExpiresAt != nil {
+		pbEntitlement.ExpiresAt = timestamppb.New(*ent.ExpiresAt)
+	}
+
+	return pbEntitlement
+}
+
+// entitlementChangeToProto converts a watched entitlement change to its protobuf form
+func entitlementChangeToProto(change *events.EntitlementChange) *paymentv1.EntitlementChange {
+	changeType := paymentv1.EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_UPDATED
+	switch change.Type {
+	case events.EntitlementChangeGranted:
+		changeType = paymentv1.EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_GRANTED
+	case events.EntitlementChangeRevoked:
+		changeType = paymentv1.EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_REVOKED
+	case events.EntitlementChangeExpired:
+		changeType = paymentv1.EntitlementChangeType_ENTITLEMENT_CHANGE_TYPE_EXPIRED
+	}
+
+	return &paymentv1.EntitlementChange{
+		Type:        changeType,
+		Entitlement: entitlementToProto(&change.Entitlement),
+		OccurredAt:  timestamppb.New(change.OccurredAt),
+	}
+}
+
+// checkoutSessionToProto converts a domain checkout session to its protobuf form
+func checkoutSessionToProto(session *domain.CheckoutSession) *paymentv1.CheckoutSession {
+	pbSession := &paymentv1.CheckoutSession{
+		SessionId:         session.ProviderSessionID,
+		Provider:          session.Provider,
+		PlanId:            session.PlanCode,
+		PlanVersion:       session.PlanVersion,
+		UserId:            session.UserID,
+		CountryCode:       session.CountryCode,
+		BasePrice:         session.BasePrice,
+		QuotedPrice:       session.QuotedPrice,
+		PricingMultiplier: session.PricingMultiplier,
+		Currency:          session.Currency,
+		Status:            string(session.Status),
+		Url:               session.URL,
+		ExpiresAt:         timestamppb.New(session.ExpiresAt),
+		CreatedAt:         timestamppb.New(session.CreatedAt),
+		Region:            session.Region,
+		TaxId:             session.TaxID,
+		TaxName:           session.Tax.Name,
+		TaxRatePercent:    session.Tax.RatePercent,
+		TaxInclusive:      session.Tax.Inclusive,
+		ReverseCharge:     session.Tax.ReverseCharge,
+		TaxAmount:         float64(session.Tax.TaxCents) / 100,
+		TotalPrice:        float64(session.TotalCents()) / 100,
+	}
+	if session.FamilyID != nil {
+		pbSession.FamilyId = *session.FamilyID
+	}
+	if session.CompletedAt != nil {
+		pbSession.CompletedAt = timestamppb.New(*session.CompletedAt)
+	}
+
+	return pbSession
+}
+
+// domainErrorToStatus maps domain errors to gRPC status errors. Errors that
+// already carry a status pass through; anything else is reported as Internal.
+func domainErrorToStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	domainErr := domain.GetDomainError(err)
+	if domainErr == nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	switch domainErr.Code {
+	case domain.ErrCodeInvalidInput:
+		return status.Error(codes.InvalidArgument, domainErr.Error())
+	case domain.ErrCodeNotFound:
+		return status.Error(codes.NotFound, domainErr.Error())
+	case domain.ErrCodeAlreadyExists:
+		return status.Error(codes.AlreadyExists, domainErr.Error())
+	case domain.ErrCodeInvalidState:
+		return status.Error(codes.FailedPrecondition, domainErr.Error())
+	case domain.ErrCodeUnauthorized:
+		return status.Error(codes.PermissionDenied, domainErr.Error())
+	default:
+		return status.Error(codes.Internal, domainErr.Error())
+	}
+}
@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+)
+
+// UpsertCustomer creates or updates a user's billing profile
+func (s *PaymentService) UpsertCustomer(ctx context.Context, req *paymentv1.UpsertCustomerRequest) (*paymentv1.UpsertCustomerResponse, error) {
+	customer, err := s.customerUseCase.UpsertBillingProfile(ctx, usecase.BillingProfileRequest{
+		UserID:            req.UserId,
+		Name:              req.Name,
+		Email:             req.Email,
+		Address:           addressFromProto(req.Address),
+		TaxID:             req.TaxId,
+		PreferredCurrency: req.PreferredCurrency,
+	})
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.UpsertCustomerResponse{Customer: customerToProto(customer)}, nil
+}
+
+// GetCustomer retrieves a user's billing profile
+func (s *PaymentService) GetCustomer(ctx context.Context, req *paymentv1.GetCustomerRequest) (*paymentv1.GetCustomerResponse, error) {
+	customer, err := s.customerUseCase.GetCustomer(ctx, req.UserId)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.GetCustomerResponse{Customer: customerToProto(customer)}, nil
+}
+
+// AddPaymentMethod saves a payment method tokenized by the billing provider
+func (s *PaymentService) AddPaymentMethod(ctx context.Context, req *paymentv1.AddPaymentMethodRequest) (*paymentv1.AddPaymentMethodResponse, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	method, err := s.customerUseCase.AddPaymentMethod(ctx, req.UserId, req.Token, req.SetDefault)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.AddPaymentMethodResponse{PaymentMethod: savedPaymentMethodToProto(method)}, nil
+}
+
+// ListPaymentMethods lists a user's saved payment methods, default first
+func (s *PaymentService) ListPaymentMethods(ctx context.Context, req *paymentv1.ListPaymentMethodsRequest) (*paymentv1.ListPaymentMethodsResponse, error) {
+	methods, err := s.customerUseCase.ListPaymentMethods(ctx, req.UserId)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbMethods := make([]*paymentv1.SavedPaymentMethod, len(methods))
+	for i := range methods {
+		pbMethods[i] = savedPaymentMethodToProto(&methods[i])
+	}
+
+	return &paymentv1.ListPaymentMethodsResponse{PaymentMethods: pbMethods}, nil
+}
+
+// SetDefaultPaymentMethod sets the method reused by checkout and dunning retries
+func (s *PaymentService) SetDefaultPaymentMethod(ctx context.Context, req *paymentv1.SetDefaultPaymentMethodRequest) (*paymentv1.SetDefaultPaymentMethodResponse, error) {
+	methodID, err := parsePaymentMethodID(req.PaymentMethodId)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := s.customerUseCase.SetDefaultPaymentMethod(ctx, req.UserId, methodID)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.SetDefaultPaymentMethodResponse{PaymentMethod: savedPaymentMethodToProto(method)}, nil
+}
+
+// DetachPaymentMethod detaches a saved payment method and removes it
+func (s *PaymentService) DetachPaymentMethod(ctx context.Context, req *paymentv1.DetachPaymentMethodRequest) (*paymentv1.DetachPaymentMethodResponse, error) {
+	methodID, err := parsePaymentMethodID(req.PaymentMethodId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.customerUseCase.DetachPaymentMethod(ctx, req.UserId, methodID); err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.DetachPaymentMethodResponse{Success: true}, nil
+}
+
+// parsePaymentMethodID parses a saved payment method ID
+func parsePaymentMethodID(id string) (uuid.UUID, error) {
+	if id == "" {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "payment_method_id is required")
+	}
+	methodID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid payment_method_id: %s", id)
+	}
+	return methodID, nil
+}
+
+// customerToProto converts a domain customer to protobuf
+func customerToProto(customer *domain.Customer) *paymentv1.Customer {
+	return &paymentv1.Customer{
+		Id:                 customer.ID.String(),
+		UserId:             customer.UserID,
+		Name:               customer.Name,
+		Email:              customer.Email,
+		Address:            addressToProto(customer.Address),
+		TaxId:              customer.TaxID,
+		PreferredCurrency:  customer.PreferredCurrency,
+		Provider:           customer.Provider,
+		ProviderCustomerId: customer.ProviderCustomerID,
+		CreatedAt:          timestamppb.New(customer.CreatedAt),
+		UpdatedAt:          timestamppb.New(customer.UpdatedAt),
+	}
+}
+
+// savedPaymentMethodToProto converts a domain saved payment method to protobuf
+func savedPaymentMethodToProto(method *domain.SavedPaymentMethod) *paymentv1.SavedPaymentMethod {
+	return &paymentv1.SavedPaymentMethod{
+		Id:        method.ID.String(),
+		Provider:  method.Provider,
+		Type:      paymentMethodToProto(method.Type),
+		Brand:     method.Brand,
+		Last4:     method.Last4,
+		ExpMonth:  method.ExpMonth,
+		ExpYear:   method.ExpYear,
+		IsDefault: method.IsDefault,
+		Expired:   method.Expired(time.Now()),
+		CreatedAt: timestamppb.New(method.CreatedAt),
+	}
+}
+
+// paymentMethodToProto converts a domain payment method to its protobuf enum
+func paymentMethodToProto(method domain.PaymentMethod) paymentv1.PaymentMethod {
+	switch method {
+	case domain.PaymentMethodCreditCard:
+		return paymentv1.PaymentMethod_PAYMENT_METHOD_CREDIT_CARD
+	case domain.PaymentMethodDebitCard:
+		return paymentv1.PaymentMethod_PAYMENT_METHOD_DEBIT_CARD
+	case domain.PaymentMethodBankTransfer:
+		return paymentv1.PaymentMethod_PAYMENT_METHOD_BANK_TRANSFER
+	case domain.PaymentMethodDigitalWallet:
+		return paymentv1.PaymentMethod_PAYMENT_METHOD_DIGITAL_WALLET
+	default:
+		return paymentv1.PaymentMethod_PAYMENT_METHOD_UNSPECIFIED
+	}
+}
+
+// addressToProto converts a domain address to protobuf
+func addressToProto(address domain.Address) *paymentv1.Address {
+	return &paymentv1.Address{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+// addressFromProto converts a protobuf address, treating nil as empty
+func addressFromProto(address *paymentv1.Address) domain.Address {
+	if address == nil {
+		return domain.Address{}
+	}
+	return domain.Address{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
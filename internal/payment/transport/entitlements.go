@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+)
+
+// GrantEntitlement grants a user a feature outside of checkout
+func (s *PaymentService) GrantEntitlement(ctx context.Context, req *paymentv1.GrantEntitlementRequest) (*paymentv1.GrantEntitlementResponse, error) {
+	grant := usecase.GrantEntitlementRequest{
+		UserID:        req.UserId,
+		FeatureCode:   req.FeatureCode,
+		Complimentary: req.Complimentary,
+		Reason:        req.Reason,
+	}
+	if req.PlanId != "" {
+		grant.PlanID = planUUIDFromProto(req.PlanId)
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.AsTime()
+		grant.ExpiresAt = &expiresAt
+	}
+
+	granted, err := s.entitlementUseCase.GrantEntitlement(ctx, grant)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.GrantEntitlementResponse{Entitlement: entitlementToProto(granted)}, nil
+}
+
+// RevokeEntitlement revokes an entitlement
+func (s *PaymentService) RevokeEntitlement(ctx context.Context, req *paymentv1.RevokeEntitlementRequest) (*paymentv1.RevokeEntitlementResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	revoked, err := s.entitlementUseCase.RevokeEntitlement(ctx, req.Id, req.Reason)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.RevokeEntitlementResponse{Entitlement: entitlementToProto(revoked)}, nil
+}
+
+// ExtendEntitlement moves an entitlement's expiry later
+func (s *PaymentService) ExtendEntitlement(ctx context.Context, req *paymentv1.ExtendEntitlementRequest) (*paymentv1.ExtendEntitlementResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if req.ExpiresAt == nil {
+		return nil, status.Error(codes.InvalidArgument, "expires_at is required")
+	}
+
+	extended, err := s.entitlementUseCase.ExtendEntitlement(ctx, req.Id, req.ExpiresAt.AsTime(), req.Reason)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.ExtendEntitlementResponse{Entitlement: entitlementToProto(extended)}, nil
+}
+
+// ExplainEntitlement traces why CheckEntitlement allows or denies a user a feature
+func (s *PaymentService) ExplainEntitlement(ctx context.Context, req *paymentv1.ExplainEntitlementRequest) (*paymentv1.ExplainEntitlementResponse, error) {
+	explanation, err := s.explainUseCase.ExplainEntitlement(ctx, req.UserId, req.FeatureCode, req.FamilyId)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	resp := &paymentv1.ExplainEntitlementResponse{
+		Allowed:          explanation.Allowed,
+		Rule:             explanation.Rule,
+		Reason:           explanation.Reason,
+		PlansWithFeature: explanation.PlansWithFeature,
+		Cache: &paymentv1.EntitlementCacheState{
+			Enabled:  explanation.Cache.Enabled,
+			NotFound: explanation.Cache.NotFound,
+			Error:    explanation.Cache.Error,
+		},
+	}
+	if explanation.Feature != nil {
+		resp.Feature = featureToProto(explanation.Feature)
+	}
+	if explanation.Cache.Entitlement != nil {
+		resp.Cache.Entitlement = entitlementToProto(explanation.Cache.Entitlement)
+	}
+	for i := range explanation.Candidates {
+		candidate := &explanation.Candidates[i]
+		resp.Candidates = append(resp.Candidates, &paymentv1.EntitlementCandidate{
+			Entitlement:        entitlementToProto(&candidate.Entitlement),
+			Valid:              candidate.Valid,
+			Reason:             candidate.Reason,
+			PlanCode:           candidate.PlanCode,
+			PlanGrantsFeature:  candidate.PlanGrantsFeature,
+			SubscriptionStatus: candidate.SubscriptionStatus,
+		})
+	}
+
+	return resp, nil
+}
+
+// planUUIDFromProto accepts a plan's UUID, as entitlements report it, or
+// its catalog code
+func planUUIDFromProto(planID string) uuid.UUID {
+	if id, err := uuid.Parse(planID); err == nil {
+		return id
+	}
+	return domain.PlanUUID(planID)
+}
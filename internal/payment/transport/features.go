@@ -0,0 +1,129 @@
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// ListFeatures lists the feature catalog
+func (s *PaymentService) ListFeatures(ctx context.Context, req *paymentv1.ListFeaturesRequest) (*paymentv1.ListFeaturesResponse, error) {
+	features, err := s.featureCatalogUseCase.ListFeatures(ctx)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbFeatures := make([]*paymentv1.Feature, len(features))
+	for i := range features {
+		pbFeatures[i] = featureToProto(&features[i])
+	}
+
+	return &paymentv1.ListFeaturesResponse{Features: pbFeatures}, nil
+}
+
+// CreateFeature adds a feature to the catalog
+func (s *PaymentService) CreateFeature(ctx context.Context, req *paymentv1.CreateFeatureRequest) (*paymentv1.CreateFeatureResponse, error) {
+	if req.Feature == nil {
+		return nil, status.Error(codes.InvalidArgument, "feature is required")
+	}
+
+	defaultLimits, err := jsonField("default_limits", req.Feature.DefaultLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.featureCatalogUseCase.CreateFeature(ctx, domain.Feature{
+		Code:          req.Feature.Code,
+		Description:   req.Feature.Description,
+		QuotaSchema:   quotaSchemaFromProto(req.Feature.QuotaSchema),
+		DefaultLimits: defaultLimits,
+	})
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.CreateFeatureResponse{Feature: featureToProto(created)}, nil
+}
+
+// UpdateFeature applies the fields named in update_mask to a feature
+func (s *PaymentService) UpdateFeature(ctx context.Context, req *paymentv1.UpdateFeatureRequest) (*paymentv1.UpdateFeatureResponse, error) {
+	if req.Feature == nil || req.Feature.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "feature.code is required")
+	}
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	update, err := featureUpdateFromProto(req.Feature, req.UpdateMask.GetPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.featureCatalogUseCase.UpdateFeature(ctx, req.Feature.Code, update)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.UpdateFeatureResponse{Feature: featureToProto(updated)}, nil
+}
+
+// featureUpdateFromProto builds a partial update from the masked fields of f
+func featureUpdateFromProto(f *paymentv1.Feature, paths []string) (domain.FeatureUpdate, error) {
+	var update domain.FeatureUpdate
+	for _, path := range paths {
+		switch path {
+		case "description":
+			update.Description = &f.Description
+		case "quota_schema":
+			update.QuotaSchema = quotaSchemaFromProto(f.QuotaSchema)
+			if update.QuotaSchema == nil {
+				update.QuotaSchema = map[string]domain.QuotaType{}
+			}
+		case "default_limits":
+			raw, err := jsonField("default_limits", f.DefaultLimits)
+			if err != nil {
+				return domain.FeatureUpdate{}, err
+			}
+			update.DefaultLimits = orEmptyObject(raw)
+		case "deprecated":
+			update.Deprecated = &f.Deprecated
+		default:
+			return domain.FeatureUpdate{}, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+	}
+	return update, nil
+}
+
+// quotaSchemaFromProto converts a protobuf quota schema, nil if it is empty
+func quotaSchemaFromProto(schema map[string]string) map[string]domain.QuotaType {
+	if len(schema) == 0 {
+		return nil
+	}
+	converted := make(map[string]domain.QuotaType, len(schema))
+	for key, t := range schema {
+		converted[key] = domain.QuotaType(t)
+	}
+	return converted
+}
+
+// featureToProto converts a domain feature to a protobuf feature
+func featureToProto(f *domain.Feature) *paymentv1.Feature {
+	schema := make(map[string]string, len(f.QuotaSchema))
+	for key, t := range f.QuotaSchema {
+		schema[key] = string(t)
+	}
+	return &paymentv1.Feature{
+		Code:          f.Code,
+		Description:   f.Description,
+		QuotaSchema:   schema,
+		DefaultLimits: string(f.DefaultLimits),
+		Deprecated:    f.Deprecated,
+		CreatedAt:     timestamppb.New(f.CreatedAt),
+		UpdatedAt:     timestamppb.New(f.UpdatedAt),
+	}
+}
@@ -2,10 +2,8 @@ package transport
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -32,6 +30,13 @@ type PaymentService struct {
 	bulkEntitlementUseCase *usecase.BulkEntitlementUseCase
 	checkoutUseCase        *usecase.CheckoutUseCase
 	pricingZoneUseCase     *usecase.PricingZoneUseCase
+	planCatalogUseCase     *usecase.PlanCatalogUseCase
+	featureCatalogUseCase  *usecase.FeatureCatalogUseCase
+	invoiceUseCase         *usecase.InvoiceUseCase
+	customerUseCase        *usecase.CustomerUseCase
+	revenueUseCase         *usecase.RevenueUseCase
+	auditUseCase           *usecase.AuditUseCase
+	explainUseCase         *usecase.EntitlementExplainUseCase
 	cache                  *cache.Cache
 	entitlementPublisher   events.EntitlementPublisher
 	billingProvider        billing.Provider
@@ -48,6 +53,13 @@ func NewPaymentService(
 	bulkEntitlementUseCase *usecase.BulkEntitlementUseCase,
 	checkoutUseCase *usecase.CheckoutUseCase,
 	pricingZoneUseCase *usecase.PricingZoneUseCase,
+	planCatalogUseCase *usecase.PlanCatalogUseCase,
+	featureCatalogUseCase *usecase.FeatureCatalogUseCase,
+	invoiceUseCase *usecase.InvoiceUseCase,
+	customerUseCase *usecase.CustomerUseCase,
+	revenueUseCase *usecase.RevenueUseCase,
+	auditUseCase *usecase.AuditUseCase,
+	explainUseCase *usecase.EntitlementExplainUseCase,
 	cache *cache.Cache,
 	entitlementPublisher events.EntitlementPublisher,
 	billingProvider billing.Provider,
@@ -64,6 +76,13 @@ func NewPaymentService(
 		bulkEntitlementUseCase: bulkEntitlementUseCase,
 		checkoutUseCase:        checkoutUseCase,
 		pricingZoneUseCase:     pricingZoneUseCase,
+		planCatalogUseCase:     planCatalogUseCase,
+		featureCatalogUseCase:  featureCatalogUseCase,
+		invoiceUseCase:         invoiceUseCase,
+		customerUseCase:        customerUseCase,
+		revenueUseCase:         revenueUseCase,
+		auditUseCase:           auditUseCase,
+		explainUseCase:         explainUseCase,
 		cache:                  cache,
 		entitlementPublisher:   entitlementPublisher,
 		billingProvider:        billingProvider,
@@ -75,8 +94,6 @@ func NewPaymentService(
 
 // CreatePayment creates a new payment
 func (s *PaymentService) CreatePayment(ctx context.Context, req *paymentv1.CreatePaymentRequest) (*paymentv1.CreatePaymentResponse, error) {
-	start := time.Now()
-
 	// Convert proto request to domain request
 	domainReq := &domain.PaymentRequest{
 		Amount:        req.Amount,
@@ -89,11 +106,6 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req *paymentv1.Creat
 
 	// Call use case
 	domainResp, err := s.paymentUseCase.CreatePayment(ctx, domainReq)
-	duration := time.Since(start)
-
-	// Record metrics
-	s.metricsCollector.RecordPayment(ctx, err == nil, float64(req.Amount)/100, duration)
-
 	if err != nil {
 		return nil, err
 	}
@@ -154,169 +166,172 @@ func (s *PaymentService) UpdatePaymentStatus(ctx context.Context, req *paymentv1
 
 // GetPaymentsByCustomer retrieves payments for a customer
 func (s *PaymentService) GetPaymentsByCustomer(ctx context.Context, req *paymentv1.GetPaymentsByCustomerRequest) (*paymentv1.GetPaymentsByCustomerResponse, error) {
-	// Call use case
-	domainResps, err := s.paymentUseCase.GetPaymentsByCustomer(ctx, req.CustomerId, int(req.Limit), int(req.Offset))
-	if err != nil {
-		return nil, err
+	if req.CustomerId == "" {
+		return nil, status.Error(codes.InvalidArgument, "customer_id is required")
 	}
 
-	// Convert domain responses to proto responses
-	payments := make([]*paymentv1.Payment, len(domainResps))
-	for i, domainResp := range domainResps {
-		payments[i] = &paymentv1.Payment{
-			Id:            domainResp.ID.String(),
-			Amount:        domainResp.Amount,
-			Currency:      domainResp.Currency,
-			Status:        domainResp.Status,
-			PaymentMethod: domainResp.PaymentMethod,
-			CustomerId:    domainResp.CustomerID,
-			OrderId:       domainResp.OrderID,
-			Description:   domainResp.Description,
-			CreatedAt:     timestamppb.New(domainResp.CreatedAt),
-		}
+	query := paymentListQueryFromProto(req.Filter, req.SortBy, req.SortAscending, req.Limit, req.Offset)
+	query.Filter.CustomerID = req.CustomerId
+
+	// Call use case
+	page, err := s.paymentUseCase.SearchPayments(ctx, query, req.PageToken)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
 	}
 
 	// Return response
 	return &paymentv1.GetPaymentsByCustomerResponse{
-		Payments: payments,
-		Total:    int32(len(payments)), // TODO: Get actual total count
+		Payments:      paymentsToProto(page.Payments),
+		Total:         int32(page.Total),
+		NextPageToken: page.NextPageToken,
 	}, nil
 }
 
-// ListPayments retrieves a list of payments with pagination
+// ListPayments retrieves a filtered, sorted list of payments with pagination
 func (s *PaymentService) ListPayments(ctx context.Context, req *paymentv1.ListPaymentsRequest) (*paymentv1.ListPaymentsResponse, error) {
+	query := paymentListQueryFromProto(req.Filter, req.SortBy, req.SortAscending, req.Limit, req.Offset)
+
 	// Call use case
-	domainResps, err := s.paymentUseCase.ListPayments(ctx, int(req.Limit), int(req.Offset))
+	page, err := s.paymentUseCase.SearchPayments(ctx, query, req.PageToken)
 	if err != nil {
-		return nil, err
+		return nil, domainErrorToStatus(err)
 	}
 
-	// Get total count
-	total, err := s.paymentUseCase.CountPayments(ctx)
+	// Return response
+	return &paymentv1.ListPaymentsResponse{
+		Payments:      paymentsToProto(page.Payments),
+		Total:         int32(page.Total),
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// ExportPayments streams every payment matching the request's filters
+func (s *PaymentService) ExportPayments(req *paymentv1.ExportPaymentsRequest, stream paymentv1.PaymentService_ExportPaymentsServer) error {
+	query := paymentListQueryFromProto(req.Filter, req.SortBy, req.SortAscending, 0, 0)
+	query.Filter.CustomerID = req.CustomerId
+
+	err := s.paymentUseCase.ExportPayments(stream.Context(), query, func(payment *domain.PaymentResponse) error {
+		return stream.Send(paymentToProto(payment))
+	})
 	if err != nil {
-		return nil, err
+		return domainErrorToStatus(err)
 	}
 
-	// Convert domain responses to proto responses
-	payments := make([]*paymentv1.Payment, len(domainResps))
-	for i, domainResp := range domainResps {
-		payments[i] = &paymentv1.Payment{
-			Id:            domainResp.ID.String(),
-			Amount:        domainResp.Amount,
-			Currency:      domainResp.Currency,
-			Status:        domainResp.Status,
-			PaymentMethod: domainResp.PaymentMethod,
-			CustomerId:    domainResp.CustomerID,
-			OrderId:       domainResp.OrderID,
-			Description:   domainResp.Description,
-			CreatedAt:     timestamppb.New(domainResp.CreatedAt),
-		}
+	return nil
+}
+
+// ExportEntitlements streams every entitlement matching the request's filters
+func (s *PaymentService) ExportEntitlements(req *paymentv1.ExportEntitlementsRequest, stream paymentv1.PaymentService_ExportEntitlementsServer) error {
+	switch req.Source {
+	case "", domain.EntitlementSourcePaid, domain.EntitlementSourceComplimentary, domain.EntitlementSourceManual:
+	default:
+		return status.Errorf(codes.InvalidArgument, "source must be %s, %s or %s",
+			domain.EntitlementSourcePaid, domain.EntitlementSourceComplimentary, domain.EntitlementSourceManual)
 	}
 
-	// Return response
-	return &paymentv1.ListPaymentsResponse{
-		Payments: payments,
-		Total:    int32(total),
-	}, nil
+	filter := domain.EntitlementFilter{
+		UserID:      req.UserId,
+		FeatureCode: req.FeatureCode,
+		Status:      req.Status,
+		Source:      req.Source,
+	}
+
+	return s.entitlementUseCase.ExportEntitlements(stream.Context(), filter, func(ent *domain.Entitlement) error {
+		return stream.Send(entitlementToProto(ent))
+	})
 }
 
-// CreateCheckoutSession creates a checkout session for payment
-func (s *PaymentService) CreateCheckoutSession(ctx context.Context, req *paymentv1.CreateCheckoutSessionRequest) (*paymentv1.CreateCheckoutSessionResponse, error) {
-	// Convert plan ID to UUID (handle both UUID and string plan IDs)
-	var planID uuid.UUID
-	if parsedUUID, err := uuid.Parse(req.PlanId); err == nil {
-		// It's a valid UUID
-		planID = parsedUUID
-	} else {
-		// It's a string plan ID, generate a deterministic UUID
-		planID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(req.PlanId))
-		log.Info(ctx, "Converted string plan ID to UUID",
-			zap.String("plan_id_string", req.PlanId),
-			zap.String("plan_id_uuid", planID.String()))
-	}
-
-	// Calculate adjusted price based on country code
-	adjustedPrice := req.BasePrice
-	if req.CountryCode != "" {
-		// Get pricing zone for the country
-		pricingZone, err := s.pricingZoneUseCase.GetPricingZoneByISOCode(ctx, req.CountryCode)
-		if err == nil {
-			adjustedPrice = pricingZone.CalculateAdjustedPrice(req.BasePrice)
-			log.Info(ctx, "Applied dynamic pricing for checkout",
-				zap.String("country_code", req.CountryCode),
-				zap.String("zone", pricingZone.Zone),
-				zap.String("zone_name", pricingZone.ZoneName),
-				zap.Float64("multiplier", pricingZone.PricingMultiplier),
-				zap.Float64("base_price", req.BasePrice),
-				zap.Float64("adjusted_price", adjustedPrice))
+// WatchEntitlements streams a user's entitlements, then every change to them
+func (s *PaymentService) WatchEntitlements(req *paymentv1.WatchEntitlementsRequest, stream paymentv1.PaymentService_WatchEntitlementsServer) error {
+	return s.entitlementUseCase.WatchEntitlements(stream.Context(), req.UserId, req.FamilyId, func(event usecase.EntitlementWatchEvent) error {
+		resp := &paymentv1.WatchEntitlementsResponse{}
+		if event.Change != nil {
+			resp.Change = entitlementChangeToProto(event.Change)
 		} else {
-			log.Warn(ctx, "Pricing zone not found for checkout, using base price",
-				zap.String("country_code", req.CountryCode),
-				zap.Error(err))
+			resp.Snapshot = make([]*paymentv1.Entitlement, len(event.Snapshot))
+			for i, ent := range event.Snapshot {
+				resp.Snapshot[i] = entitlementToProto(ent)
+			}
 		}
-	}
+		return stream.Send(resp)
+	})
+}
 
-	// Prepare billing request with adjusted price
-	billingReq := billing.CreateCheckoutSessionRequest{
-		PlanID:      planID,
+// CreateCheckoutSession creates a checkout session for payment
+func (s *PaymentService) CreateCheckoutSession(ctx context.Context, req *paymentv1.CreateCheckoutSessionRequest) (*paymentv1.CreateCheckoutSessionResponse, error) {
+	checkoutReq := usecase.CheckoutRequest{
+		PlanID:      req.PlanId,
 		UserID:      req.UserId,
 		CountryCode: req.CountryCode,
-		BasePrice:   adjustedPrice, // Use adjusted price instead of base price
+		Region:      req.Region,
+		TaxID:       req.TaxId,
 		Currency:    req.Currency,
 		SuccessURL:  req.SuccessUrl,
 		CancelURL:   req.CancelUrl,
 	}
-
-	// Add family ID if provided
 	if req.FamilyId != "" {
-		billingReq.FamilyID = &req.FamilyId
+		checkoutReq.FamilyID = &req.FamilyId
 	}
 
-	// Create checkout session using billing provider
-	session, err := s.billingProvider.CreateCheckoutSession(ctx, billingReq)
+	session, err := s.checkoutUseCase.CreateCheckoutSession(ctx, checkoutReq)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create checkout session: %v", err)
+		return nil, domainErrorToStatus(err)
 	}
 
-	// Create a payment record in the database with adjusted price
-	paymentReq := &domain.PaymentRequest{
-		Amount:        adjustedPrice, // Use adjusted price for payment record
-		Currency:      req.Currency,
-		PaymentMethod: "credit_card",
-		CustomerID:    req.UserId,
-		OrderID:       session.SessionID, // Use session ID as order ID
-		Description:   fmt.Sprintf("Checkout session for plan %s", req.PlanId),
+	return &paymentv1.CreateCheckoutSessionResponse{
+		SessionId: session.ProviderSessionID,
+		Url:       session.URL,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		Session:   checkoutSessionToProto(session),
+	}, nil
+}
+
+// GetCheckoutSession retrieves a checkout session
+func (s *PaymentService) GetCheckoutSession(ctx context.Context, req *paymentv1.GetCheckoutSessionRequest) (*paymentv1.GetCheckoutSessionResponse, error) {
+	session, err := s.checkoutUseCase.GetCheckoutSession(ctx, req.SessionId)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
 	}
 
-	_, err = s.paymentUseCase.CreatePayment(ctx, paymentReq)
+	return &paymentv1.GetCheckoutSessionResponse{Session: checkoutSessionToProto(session)}, nil
+}
+
+// CancelCheckoutSession cancels an open checkout session
+func (s *PaymentService) CancelCheckoutSession(ctx context.Context, req *paymentv1.CancelCheckoutSessionRequest) (*paymentv1.CancelCheckoutSessionResponse, error) {
+	session, err := s.checkoutUseCase.CancelCheckoutSession(ctx, req.SessionId)
 	if err != nil {
-		// Log error but don't fail the checkout session creation
-		// The payment record can be created later via webhook
-		fmt.Printf("Warning: Failed to create payment record: %v\n", err)
+		return nil, domainErrorToStatus(err)
 	}
 
-	// Convert to protobuf response
-	return &paymentv1.CreateCheckoutSessionResponse{
-		SessionId: session.SessionID,
-		Url:       session.URL,
-		ExpiresAt: timestamppb.New(session.ExpiresAt),
-	}, nil
+	return &paymentv1.CancelCheckoutSessionResponse{Session: checkoutSessionToProto(session)}, nil
 }
 
 // ProcessWebhook processes webhook events from payment providers
 func (s *PaymentService) ProcessWebhook(ctx context.Context, req *paymentv1.ProcessWebhookRequest) (*paymentv1.ProcessWebhookResponse, error) {
-	// Validate webhook signature
+	start := time.Now()
+
+	// A routing billing provider hands the webhook to the provider it came from
+	if req.Provider != "" {
+		ctx = billing.WithProviderName(ctx, req.Provider)
+	}
+
+	// Validate webhook signature. Webhooks rejected here never reach the
+	// checkout use case, so their metrics are recorded in the transport.
 	if err := s.billingProvider.ValidateWebhook(ctx, req.Payload, req.Signature); err != nil {
+		s.metricsCollector.RecordWebhook(ctx, "", false, time.Since(start))
 		return nil, status.Errorf(codes.Unauthenticated, "webhook validation failed: %v", err)
 	}
 
 	// Parse webhook payload
 	webhookResult, err := s.billingProvider.ParseWebhook(ctx, req.Payload)
 	if err != nil {
+		s.metricsCollector.RecordWebhook(ctx, "", false, time.Since(start))
 		return nil, status.Errorf(codes.Internal, "failed to parse webhook: %v", err)
 	}
 
-	// Apply webhook result (create entitlement)
+	// Apply webhook result (create entitlement). Provider session IDs are
+	// only unique per provider.
+	webhookResult.Provider = req.Provider
 	if err := s.checkoutUseCase.ApplyWebhook(ctx, *webhookResult); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to apply webhook: %v", err)
 	}
@@ -344,7 +359,7 @@ func (s *PaymentService) PaymentSuccessWebhook(ctx context.Context, payload []by
 	// Validate webhook signature
 	if err := s.webhookValidator.ValidateStripeWebhook(payload, signature); err != nil {
 		log.Error(ctx, "Webhook signature validation failed", zap.Error(err))
-		s.metricsCollector.RecordWebhook(ctx, false, time.Since(start))
+		s.metricsCollector.RecordWebhook(ctx, "", false, time.Since(start))
 		return status.Error(codes.Unauthenticated, "invalid webhook signature")
 	}
 
@@ -352,7 +367,7 @@ func (s *PaymentService) PaymentSuccessWebhook(ctx context.Context, payload []by
 	webhookResult, err := s.webhookParser.ParseStripeWebhook(payload)
 	if err != nil {
 		log.Error(ctx, "Failed to parse webhook payload", zap.Error(err))
-		s.metricsCollector.RecordWebhook(ctx, false, time.Since(start))
+		s.metricsCollector.RecordWebhook(ctx, "", false, time.Since(start))
 		return status.Error(codes.InvalidArgument, "invalid webhook payload")
 	}
 
@@ -362,7 +377,6 @@ func (s *PaymentService) PaymentSuccessWebhook(ctx context.Context, payload []by
 		SessionID:    "",                  // Will be set if available
 		UserID:       webhookResult.UserID,
 		FamilyID:     webhookResult.FamilyID,
-		FeatureCode:  webhookResult.FeatureCode,
 		PlanID:       webhookResult.PlanID,
 		PlanIDString: webhookResult.PlanIDString,
 		Amount:       float64(webhookResult.Amount),
@@ -375,16 +389,11 @@ func (s *PaymentService) PaymentSuccessWebhook(ctx context.Context, payload []by
 	// Apply webhook result using checkout use case
 	if err := s.checkoutUseCase.ApplyWebhook(ctx, billingResult); err != nil {
 		log.Error(ctx, "Failed to apply webhook result", zap.Error(err))
-		s.metricsCollector.RecordWebhook(ctx, false, time.Since(start))
 		return status.Errorf(codes.Internal, "failed to apply webhook: %v", err)
 	}
 
-	// Record successful webhook processing
-	s.metricsCollector.RecordWebhook(ctx, true, time.Since(start))
-
 	log.Info(ctx, "Webhook processed successfully",
 		zap.String("user_id", webhookResult.UserID),
-		zap.String("feature_code", webhookResult.FeatureCode),
 		zap.String("status", webhookResult.Status))
 
 	return nil
@@ -410,6 +419,7 @@ func (s *PaymentService) ListEntitlements(ctx context.Context, req *paymentv1.Li
 			GrantedAt:   timestamppb.New(ent.GrantedAt),
 			CreatedAt:   timestamppb.New(ent.CreatedAt),
 			UpdatedAt:   timestamppb.New(ent.UpdatedAt),
+			Source:      ent.Source,
 		}
 
 		// Add optional fields
@@ -434,17 +444,10 @@ func (s *PaymentService) ListEntitlements(ctx context.Context, req *paymentv1.Li
 
 // CheckEntitlement checks if a user has access to a feature
 func (s *PaymentService) CheckEntitlement(ctx context.Context, req *paymentv1.CheckEntitlementRequest) (*paymentv1.CheckEntitlementResponse, error) {
-	start := time.Now()
-
 	// Check entitlement using use case
 	response, err := s.entitlementUseCase.CheckEntitlement(ctx, req.UserId, req.FeatureCode)
-	duration := time.Since(start)
-
-	// Record metrics (assuming cache miss for now - this would be improved with actual cache hit detection)
-	s.metricsCollector.RecordEntitlementCheck(ctx, false, duration)
-
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to check entitlement: %v", err)
+		return nil, domainErrorToStatus(err)
 	}
 
 	// Convert to protobuf
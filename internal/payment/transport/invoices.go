@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+)
+
+// GetInvoice retrieves an invoice by ID or number, rendering it when a format is requested
+func (s *PaymentService) GetInvoice(ctx context.Context, req *paymentv1.GetInvoiceRequest) (*paymentv1.GetInvoiceResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	format, err := invoiceFormatFromProto(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.invoiceUseCase.GetInvoice(ctx, req.Id)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	document, contentType, err := s.invoiceUseCase.RenderInvoice(invoice, format)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.GetInvoiceResponse{
+		Invoice:     invoiceToProto(invoice),
+		Document:    document,
+		ContentType: contentType,
+	}, nil
+}
+
+// ListInvoices lists invoices newest first
+func (s *PaymentService) ListInvoices(ctx context.Context, req *paymentv1.ListInvoicesRequest) (*paymentv1.ListInvoicesResponse, error) {
+	page, err := s.invoiceUseCase.ListInvoices(ctx, domain.InvoiceFilter{UserID: req.UserId}, int(req.PageSize), req.PageToken)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbInvoices := make([]*paymentv1.Invoice, len(page.Invoices))
+	for i, invoice := range page.Invoices {
+		pbInvoices[i] = invoiceToProto(invoice)
+	}
+
+	return &paymentv1.ListInvoicesResponse{
+		Invoices:      pbInvoices,
+		NextPageToken: page.NextPageToken,
+	}, nil
+}
+
+// invoiceFormatFromProto converts a protobuf invoice format
+func invoiceFormatFromProto(format paymentv1.InvoiceFormat) (usecase.InvoiceFormat, error) {
+	switch format {
+	case paymentv1.InvoiceFormat_INVOICE_FORMAT_UNSPECIFIED:
+		return usecase.InvoiceFormatNone, nil
+	case paymentv1.InvoiceFormat_INVOICE_FORMAT_HTML:
+		return usecase.InvoiceFormatHTML, nil
+	case paymentv1.InvoiceFormat_INVOICE_FORMAT_PDF:
+		return usecase.InvoiceFormatPDF, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported invoice format: %s", format)
+	}
+}
+
+// invoiceToProto converts a domain invoice to protobuf
+func invoiceToProto(invoice *domain.Invoice) *paymentv1.Invoice {
+	pbInvoice := &paymentv1.Invoice{
+		Id:            invoice.ID.String(),
+		Number:        invoice.Number,
+		PaymentId:     invoice.PaymentID.String(),
+		UserId:        invoice.UserID,
+		PlanId:        invoice.PlanCode,
+		PlanVersion:   invoice.PlanVersion,
+		Currency:      invoice.Currency,
+		SubtotalCents: invoice.SubtotalCents,
+		DiscountCents: invoice.DiscountCents,
+		TaxCents:      invoice.TaxCents,
+		TotalCents:    invoice.TotalCents,
+		Billing: &paymentv1.BillingDetails{
+			Name:    invoice.Billing.Name,
+			Email:   invoice.Billing.Email,
+			TaxId:   invoice.Billing.TaxID,
+			Address: addressToProto(invoice.Billing.Address),
+		},
+		Status:        string(invoice.Status),
+		IssuedAt:      timestamppb.New(invoice.IssuedAt),
+		TaxInclusive:  invoice.TaxInclusive,
+		ReverseCharge: invoice.ReverseCharge,
+	}
+	if invoice.CheckoutSessionID != nil {
+		pbInvoice.CheckoutSessionId = invoice.CheckoutSessionID.String()
+	}
+	if invoice.FamilyID != nil {
+		pbInvoice.FamilyId = *invoice.FamilyID
+	}
+
+	pbInvoice.LineItems = make([]*paymentv1.InvoiceLineItem, len(invoice.LineItems))
+	for i, line := range invoice.LineItems {
+		pbInvoice.LineItems[i] = &paymentv1.InvoiceLineItem{
+			Kind:            string(line.Kind),
+			Description:     line.Description,
+			Quantity:        line.Quantity,
+			UnitAmountCents: line.UnitAmountCents,
+			AmountCents:     line.AmountCents,
+		}
+	}
+
+	return pbInvoice
+}
@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// CreatePlan adds a plan to the catalog
+func (s *PaymentService) CreatePlan(ctx context.Context, req *paymentv1.CreatePlanRequest) (*paymentv1.CreatePlanResponse, error) {
+	if req.Plan == nil {
+		return nil, status.Error(codes.InvalidArgument, "plan is required")
+	}
+
+	plan, err := planFromProto(req.Plan)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.planCatalogUseCase.CreatePlan(ctx, plan)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.CreatePlanResponse{Plan: planToProto(created)}, nil
+}
+
+// UpdatePlan applies the fields named in update_mask to a plan
+func (s *PaymentService) UpdatePlan(ctx context.Context, req *paymentv1.UpdatePlanRequest) (*paymentv1.UpdatePlanResponse, error) {
+	if req.Plan == nil || req.Plan.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "plan.id is required")
+	}
+	if len(req.UpdateMask.GetPaths()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+
+	update, err := planUpdateFromProto(req.Plan, req.UpdateMask.GetPaths())
+	if err != nil {
+		return nil, err
+	}
+
+	updated, newVersion, err := s.planCatalogUseCase.UpdatePlan(ctx, req.Plan.Id, update)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.UpdatePlanResponse{
+		Plan:       planToProto(updated),
+		NewVersion: newVersion,
+	}, nil
+}
+
+// ArchivePlan stops a plan from being sold
+func (s *PaymentService) ArchivePlan(ctx context.Context, req *paymentv1.ArchivePlanRequest) (*paymentv1.ArchivePlanResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	archived, err := s.planCatalogUseCase.ArchivePlan(ctx, req.Id)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	return &paymentv1.ArchivePlanResponse{Plan: planToProto(archived)}, nil
+}
+
+// ListPlans lists catalog plans
+func (s *PaymentService) ListPlans(ctx context.Context, req *paymentv1.ListPlansRequest) (*paymentv1.ListPlansResponse, error) {
+	plans, err := s.planCatalogUseCase.ListPlans(ctx, req.IncludeArchived)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbPlans := make([]*paymentv1.Plan, len(plans))
+	for i := range plans {
+		pbPlans[i] = planToProto(&plans[i])
+	}
+
+	return &paymentv1.ListPlansResponse{Plans: pbPlans}, nil
+}
+
+// GetPlan retrieves a plan with its version history
+func (s *PaymentService) GetPlan(ctx context.Context, req *paymentv1.GetPlanRequest) (*paymentv1.GetPlanResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	plan, versions, err := s.planCatalogUseCase.GetPlan(ctx, req.Id)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	pbVersions := make([]*paymentv1.PlanVersion, len(versions))
+	for i, v := range versions {
+		pbVersions[i] = &paymentv1.PlanVersion{
+			PlanId:       v.PlanCode,
+			Version:      v.Version,
+			FeatureCodes: v.FeatureCodes,
+			BillingCycle: v.BillingCycle,
+			PriceCents:   v.PriceCents(),
+			Currency:     v.Currency,
+			MaxUsers:     v.MaxUsers,
+			UsageLimits:  string(v.UsageLimits),
+			CreatedAt:    timestamppb.New(v.CreatedAt),
+		}
+	}
+
+	return &paymentv1.GetPlanResponse{
+		Plan:     planToProto(plan),
+		Versions: pbVersions,
+	}, nil
+}
+
+// planFromProto converts the writable fields of a protobuf plan
+func planFromProto(p *paymentv1.Plan) (domain.Plan, error) {
+	usageLimits, err := jsonField("usage_limits", p.UsageLimits)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+	metadata, err := jsonField("metadata", p.Metadata)
+	if err != nil {
+		return domain.Plan{}, err
+	}
+
+	return domain.Plan{
+		ID:           domain.PlanUUID(p.Id),
+		Code:         p.Id,
+		Name:         p.Name,
+		Description:  p.Description,
+		FeatureCodes: p.FeatureCodes,
+		BillingCycle: p.BillingCycle,
+		PriceDollars: float64(p.PriceCents) / 100,
+		Currency:     p.Currency,
+		MaxUsers:     p.MaxUsers,
+		UsageLimits:  usageLimits,
+		Metadata:     metadata,
+	}, nil
+}
+
+// planUpdateFromProto builds a partial update from the masked fields of p
+func planUpdateFromProto(p *paymentv1.Plan, paths []string) (domain.PlanUpdate, error) {
+	var update domain.PlanUpdate
+	for _, path := range paths {
+		switch path {
+		case "name":
+			update.Name = &p.Name
+		case "description":
+			update.Description = &p.Description
+		case "feature_codes":
+			update.FeatureCodes = append([]string{}, p.FeatureCodes...)
+		case "billing_cycle":
+			update.BillingCycle = &p.BillingCycle
+		case "price_cents":
+			price := float64(p.PriceCents) / 100
+			update.PriceDollars = &price
+		case "currency":
+			update.Currency = &p.Currency
+		case "max_users":
+			update.MaxUsers = &p.MaxUsers
+		case "usage_limits":
+			raw, err := jsonField("usage_limits", p.UsageLimits)
+			if err != nil {
+				return domain.PlanUpdate{}, err
+			}
+			update.UsageLimits = orEmptyObject(raw)
+		case "metadata":
+			raw, err := jsonField("metadata", p.Metadata)
+			if err != nil {
+				return domain.PlanUpdate{}, err
+			}
+			update.Metadata = orEmptyObject(raw)
+		default:
+			return domain.PlanUpdate{}, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+	}
+	return update, nil
+}
+
+// planToProto converts a domain plan to a protobuf plan
+func planToProto(p *domain.Plan) *paymentv1.Plan {
+	pbPlan := &paymentv1.Plan{
+		Id:           p.Code,
+		Name:         p.Name,
+		Description:  p.Description,
+		FeatureCodes: p.FeatureCodes,
+		BillingCycle: p.BillingCycle,
+		PriceCents:   p.PriceCents(),
+		Currency:     p.Currency,
+		MaxUsers:     p.MaxUsers,
+		UsageLimits:  string(p.UsageLimits),
+		Metadata:     string(p.Metadata),
+		Active:       p.Active,
+		Version:      p.Version,
+		CreatedAt:    timestamppb.New(p.CreatedAt),
+		UpdatedAt:    timestamppb.New(p.UpdatedAt),
+	}
+	if p.ArchivedAt != nil {
+		pbPlan.ArchivedAt = timestamppb.New(*p.ArchivedAt)
+	}
+	return pbPlan
+}
+
+// jsonField validates an optional JSON object field
+func jsonField(name, value string) (json.RawMessage, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !json.Valid([]byte(value)) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be valid JSON", name)
+	}
+	return json.RawMessage(value), nil
+}
+
+// orEmptyObject lets a masked-but-empty JSON field clear the stored value
+func orEmptyObject(raw json.RawMessage) json.RawMessage {
+	if raw == nil {
+		return json.RawMessage("{}")
+	}
+	return raw
+}
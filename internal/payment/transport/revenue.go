@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/usecase"
+)
+
+// GetRevenueReport reports MRR, ARR and churn by month
+func (s *PaymentService) GetRevenueReport(ctx context.Context, req *paymentv1.GetRevenueReportRequest) (*paymentv1.GetRevenueReportResponse, error) {
+	if req.From == nil {
+		return nil, status.Error(codes.InvalidArgument, "from is required")
+	}
+
+	reportReq := usecase.RevenueReportRequest{
+		From: req.From.AsTime(),
+		To:   time.Now(),
+	}
+	if req.To != nil {
+		reportReq.To = req.To.AsTime()
+	}
+	for _, name := range req.GroupBy {
+		dimension, err := domain.ParseRevenueDimension(name)
+		if err != nil {
+			return nil, domainErrorToStatus(err)
+		}
+		reportReq.GroupBy = append(reportReq.GroupBy, dimension)
+	}
+
+	report, err := s.revenueUseCase.GetRevenueReport(ctx, reportReq)
+	if err != nil {
+		return nil, domainErrorToStatus(err)
+	}
+
+	movements := make([]*paymentv1.RevenueMovement, len(report.Movements))
+	for i, m := range report.Movements {
+		movements[i] = revenueMovementToProto(m)
+	}
+	return &paymentv1.GetRevenueReportResponse{Movements: movements}, nil
+}
+
+// revenueMovementToProto converts a domain revenue movement to protobuf
+func revenueMovementToProto(m domain.RevenueMovement) *paymentv1.RevenueMovement {
+	return &paymentv1.RevenueMovement{
+		Month:               timestamppb.New(m.Month),
+		PlanId:              m.Slice.PlanID,
+		PricingZone:         m.Slice.PricingZone,
+		Currency:            m.Slice.Currency,
+		StartingMrrCents:    m.StartingMRRCents,
+		NewMrrCents:         m.NewMRRCents,
+		ExpansionMrrCents:   m.ExpansionMRRCents,
+		ContractionMrrCents: m.ContractionMRRCents,
+		ChurnedMrrCents:     m.ChurnedMRRCents,
+		EndingMrrCents:      m.EndingMRRCents,
+		EndingArrCents:      m.ARRCents(),
+		StartingCustomers:   int32(m.StartingCustomers),
+		NewCustomers:        int32(m.NewCustomers),
+		ChurnedCustomers:    int32(m.ChurnedCustomers),
+		EndingCustomers:     int32(m.EndingCustomers),
+		LogoChurnRate:       m.LogoChurnRate(),
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// AuditUseCase reads the audit log. Events are written by audit.Recorder
+// from the use cases that make the changes.
+type AuditUseCase struct {
+	auditRepo repo.AuditRepository
+}
+
+// NewAuditUseCase creates a new audit use case
+func NewAuditUseCase(auditRepo repo.AuditRepository) *AuditUseCase {
+	return &AuditUseCase{
+		auditRepo: auditRepo,
+	}
+}
+
+// ListAuditEvents returns one page of audit events, newest first
+func (uc *AuditUseCase) ListAuditEvents(ctx context.Context, filter domain.AuditFilter, pageSize int, pageToken string) (*domain.AuditPage, error) {
+	if filter.EntityType != "" {
+		if _, err := domain.ParseAuditEntityType(string(filter.EntityType)); err != nil {
+			return nil, err
+		}
+	}
+	if filter.EntityID != "" && filter.EntityType == "" {
+		return nil, domain.NewInvalidInputError("invalid audit filter", "entity_type is required with entity_id")
+	}
+	if pageSize <= 0 {
+		pageSize = defaultAuditPageSize
+	}
+	if pageSize > maxAuditPageSize {
+		pageSize = maxAuditPageSize
+	}
+
+	cursor, err := domain.DecodeAuditPageToken(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	events, err := uc.auditRepo.List(ctx, filter, cursor, pageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	page := &domain.AuditPage{}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		last := events[pageSize-1]
+		page.NextPageToken = domain.EncodeAuditPageToken(&domain.AuditCursor{OccurredAt: last.OccurredAt, ID: last.ID})
+	}
+	page.Events = events
+
+	return page, nil
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// memoryAuditRepo lists events held in memory, newest first
+type memoryAuditRepo struct {
+	repo.AuditRepository
+	events []*domain.AuditEvent
+}
+
+func (r *memoryAuditRepo) Record(ctx context.Context, event domain.AuditEvent) (*domain.AuditEvent, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	r.events = append([]*domain.AuditEvent{&event}, r.events...)
+	return &event, nil
+}
+
+func (r *memoryAuditRepo) List(ctx context.Context, filter domain.AuditFilter, after *domain.AuditCursor, limit int) ([]*domain.AuditEvent, error) {
+	var events []*domain.AuditEvent
+	for _, e := range r.events {
+		if filter.ActorID != "" && e.ActorID != filter.ActorID {
+			continue
+		}
+		if after != nil && !e.OccurredAt.Before(after.OccurredAt) {
+			continue
+		}
+		if len(events) == limit {
+			break
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func TestAuditUseCase_ListAuditEvents(t *testing.T) {
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := &memoryAuditRepo{}
+	for i := 5; i > 0; i-- {
+		events.events = append(events.events, &domain.AuditEvent{
+			ID:         uuid.New(),
+			EntityType: domain.AuditEntityPlan,
+			EntityID:   fmt.Sprintf("plan-%d", i),
+			Action:     domain.AuditActionUpdate,
+			ActorType:  domain.AuditActorUser,
+			ActorID:    "admin-1",
+			OccurredAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	uc := NewAuditUseCase(events)
+
+	first, err := uc.ListAuditEvents(context.Background(), domain.AuditFilter{ActorID: "admin-1"}, 3, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Events) != 3 || first.NextPageToken == "" {
+		t.Fatalf("expected a full first page with a next page, got %d events", len(first.Events))
+	}
+
+	second, err := uc.ListAuditEvents(context.Background(), domain.AuditFilter{ActorID: "admin-1"}, 3, first.NextPageToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Events) != 2 || second.NextPageToken != "" || second.Events[0].EntityID != "plan-2" {
+		t.Errorf("expected the last two events, got %+v", second.Events)
+	}
+}
+
+func TestAuditUseCase_ListAuditEventsInvalid(t *testing.T) {
+	uc := NewAuditUseCase(&memoryAuditRepo{})
+
+	tests := []struct {
+		filter domain.AuditFilter
+		token  string
+	}{
+		{filter: domain.AuditFilter{EntityType: "invoice"}},
+		{filter: domain.AuditFilter{EntityID: "premium"}},
+		{token: "not-a-token!"},
+	}
+	for _, tt := range tests {
+		if _, err := uc.ListAuditEvents(context.Background(), tt.filter, 10, tt.token); err == nil {
+			t.Errorf("expected an error for filter %+v, token %q", tt.filter, tt.token)
+		}
+	}
+}
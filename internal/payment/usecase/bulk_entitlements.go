@@ -19,16 +19,19 @@ import (
 // BulkEntitlementUseCase provides business logic for bulk entitlement operations
 type BulkEntitlementUseCase struct {
 	entitlementRepo repo.EntitlementRepository
+	features        *FeatureCatalogUseCase // Can be nil if feature codes are not checked
 	cache           *cache.Cache
 }
 
 // NewBulkEntitlementUseCase creates a new bulk entitlement use case
 func NewBulkEntitlementUseCase(
 	entitlementRepo repo.EntitlementRepository,
+	features *FeatureCatalogUseCase,
 	cache *cache.Cache,
 ) *BulkEntitlementUseCase {
 	return &BulkEntitlementUseCase{
 		entitlementRepo: entitlementRepo,
+		features:        features,
 		cache:           cache,
 	}
 }
@@ -109,11 +112,11 @@ func (uc *BulkEntitlementUseCase) BulkCheckEntitlements(ctx context.Context, req
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			result := uc.checkSingleEntitlement(ctx, req.UserID, checkItem)
+			result, hit := uc.checkSingleEntitlement(ctx, req.UserID, checkItem)
 
 			mu.Lock()
 			results[index] = result
-			if result.Entitlement != nil {
+			if hit {
 				cacheHits++
 			} else {
 				cacheMisses++
@@ -159,51 +162,62 @@ func (uc *BulkEntitlementUseCase) BulkCheckEntitlements(ctx context.Context, req
 	return response, nil
 }
 
-// checkSingleEntitlement checks a single entitlement
-func (uc *BulkEntitlementUseCase) checkSingleEntitlement(ctx context.Context, userID string, check BulkCheckItem) BulkCheckResult {
+// checkSingleEntitlement checks a single entitlement and reports whether the
+// answer came from the cache
+func (uc *BulkEntitlementUseCase) checkSingleEntitlement(ctx context.Context, userID string, check BulkCheckItem) (BulkCheckResult, bool) {
 	// Validate feature code
 	if check.FeatureCode == "" {
 		return BulkCheckResult{
 			FeatureCode: check.FeatureCode,
 			Authorized:  false,
 			Reason:      "feature_code is required",
-		}
+		}, false
 	}
 
-	// Try cache first
-	if uc.cache != nil {
-		cachedEnt, found, err := uc.cache.GetEntitlement(ctx, userID, check.FeatureCode)
-		if err != nil {
-			log.Warn(ctx, "Failed to get entitlement from cache",
-				zap.Error(err), zap.String("user_id", userID), zap.String("feature_code", check.FeatureCode))
-		} else if found {
-			// Check if it's a negative cache result
-			if isNegative, err := uc.cache.IsEntitlementNotFound(ctx, userID, check.FeatureCode); err == nil && isNegative {
-				return BulkCheckResult{
-					FeatureCode: check.FeatureCode,
-					Authorized:  false,
-					Reason:      "No active entitlement found",
-					UpgradeURL:  uc.generateUpgradeURL(check.FeatureCode),
-				}
-			}
-
-			// Validate cached entitlement is still active and not expired
-			if uc.isValidEntitlement(cachedEnt) {
-				return BulkCheckResult{
-					FeatureCode: check.FeatureCode,
-					Authorized:  true,
-					Entitlement: cachedEnt,
-					Metadata:    check.Metadata,
-				}
+	// Codes missing from the feature catalog are rejected
+	if uc.features != nil {
+		if _, err := uc.features.Resolve(ctx, check.FeatureCode); err != nil {
+			reason := "Internal error checking entitlement"
+			if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeNotFound {
+				reason = "Unknown feature code"
+			} else {
+				log.Error(ctx, "Failed to resolve feature",
+					zap.Error(err), zap.String("feature_code", check.FeatureCode))
 			}
+			return BulkCheckResult{
+				FeatureCode: check.FeatureCode,
+				Authorized:  false,
+				Reason:      reason,
+			}, false
+		}
+	}
 
-			// Cached entitlement is invalid, evict from cache and fallback to repo
-			uc.cache.DeleteEntitlement(ctx, userID, check.FeatureCode)
+	// Set by the loader when the repository has an entitlement that is no
+	// longer valid, to tell it apart from having none at all
+	inactive := false
+	load := func(ctx context.Context) (*domain.Entitlement, error) {
+		entitlement, found, err := uc.entitlementRepo.Check(ctx, userID, check.FeatureCode)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, nil
+		}
+		if !uc.isValidEntitlement(&entitlement) {
+			inactive = true
+			return nil, nil
 		}
+		return &entitlement, nil
 	}
 
-	// Fallback to repository
-	entitlement, found, err := uc.entitlementRepo.Check(ctx, userID, check.FeatureCode)
+	var entitlement *domain.Entitlement
+	var hit bool
+	var err error
+	if uc.cache != nil {
+		entitlement, hit, err = uc.cache.LookupEntitlement(ctx, userID, check.FeatureCode, load)
+	} else {
+		entitlement, err = load(ctx)
+	}
 	if err != nil {
 		log.Error(ctx, "Failed to check entitlement",
 			zap.Error(err), zap.String("user_id", userID), zap.String("feature_code", check.FeatureCode))
@@ -211,47 +225,28 @@ func (uc *BulkEntitlementUseCase) checkSingleEntitlement(ctx context.Context, us
 			FeatureCode: check.FeatureCode,
 			Authorized:  false,
 			Reason:      "Internal error checking entitlement",
-		}
+		}, false
 	}
 
-	if !found {
-		// Cache negative result
-		if uc.cache != nil {
-			uc.cache.SetEntitlementNotFound(ctx, userID, check.FeatureCode)
+	if entitlement == nil {
+		reason := "No active entitlement found"
+		if inactive {
+			reason = "Entitlement expired or inactive"
 		}
 		return BulkCheckResult{
 			FeatureCode: check.FeatureCode,
 			Authorized:  false,
-			Reason:      "No active entitlement found",
+			Reason:      reason,
 			UpgradeURL:  uc.generateUpgradeURL(check.FeatureCode),
-		}
-	}
-
-	// Validate entitlement is active and not expired
-	if !uc.isValidEntitlement(&entitlement) {
-		// Cache negative result for invalid entitlements
-		if uc.cache != nil {
-			uc.cache.SetEntitlementNotFound(ctx, userID, check.FeatureCode)
-		}
-		return BulkCheckResult{
-			FeatureCode: check.FeatureCode,
-			Authorized:  false,
-			Reason:      "Entitlement expired or inactive",
-			UpgradeURL:  uc.generateUpgradeURL(check.FeatureCode),
-		}
-	}
-
-	// Cache the valid entitlement
-	if uc.cache != nil {
-		uc.cache.SetEntitlement(ctx, entitlement, 300*time.Second) // 5 minutes
+		}, hit
 	}
 
 	return BulkCheckResult{
 		FeatureCode: check.FeatureCode,
 		Authorized:  true,
-		Entitlement: &entitlement,
+		Entitlement: entitlement,
 		Metadata:    check.Metadata,
-	}
+	}, hit
 }
 
 // isValidEntitlement checks if an entitlement is valid
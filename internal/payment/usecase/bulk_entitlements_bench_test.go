@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/shared/cache"
+	"github.com/jia-app/paymentservice/internal/shared/cache/redistest"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// benchmarkRedisLatency stands in for the round trip to a Redis server in the
+// same region
+const benchmarkRedisLatency = 200 * time.Microsecond
+
+// BenchmarkBulkCheckEntitlements compares warm bulk checks served by Redis
+// alone with checks served by the local tier in front of it:
+//
+//	go test -bench BulkCheckEntitlements -run '^$' ./internal/payment/usecase/
+func BenchmarkBulkCheckEntitlements(b *testing.B) {
+	// Keep the per-request summary logs out of the results
+	_ = log.Init("warn")
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	plan, err := store.Plan().Create(ctx, domain.Plan{Code: "family_monthly", Name: "Family", Currency: "USD", Active: true})
+	if err != nil {
+		b.Fatalf("failed to create plan: %v", err)
+	}
+
+	req := BulkCheckRequest{UserID: "user-1"}
+	for i := range 20 {
+		featureCode := fmt.Sprintf("feature-%d", i)
+		req.Checks = append(req.Checks, BulkCheckItem{FeatureCode: featureCode})
+
+		// Leave every other feature without an entitlement to exercise negative caching
+		if i%2 == 1 {
+			continue
+		}
+		if _, err := store.Entitlement().Insert(ctx, domain.Entitlement{
+			UserID:      req.UserID,
+			FeatureCode: featureCode,
+			PlanID:      plan.ID,
+			Status:      "active",
+			GrantedAt:   time.Now(),
+		}); err != nil {
+			b.Fatalf("failed to insert entitlement: %v", err)
+		}
+	}
+
+	tiers := []struct {
+		name  string
+		local cache.LocalConfig
+	}{
+		{"redis", cache.LocalConfig{}},
+		{"two-tier", cache.LocalConfig{Size: 1000, TTL: time.Minute}},
+	}
+	for _, tier := range tiers {
+		b.Run(tier.name, func(b *testing.B) {
+			srv := redistest.NewServer(redistest.Config{Latency: benchmarkRedisLatency})
+			defer srv.Close()
+			client := redis.NewClient(&redis.Options{Addr: srv.Addr})
+			defer client.Close()
+
+			uc := NewBulkEntitlementUseCase(store.Entitlement(), nil, cache.NewTieredCache(client, tier.local, nil))
+			if _, err := uc.BulkCheckEntitlements(ctx, req); err != nil {
+				b.Fatalf("BulkCheckEntitlements() error = %v", err)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				resp, err := uc.BulkCheckEntitlements(ctx, req)
+				if err != nil {
+					b.Fatalf("BulkCheckEntitlements() error = %v", err)
+				}
+				if resp.Summary.CacheHits != len(req.Checks) {
+					b.Fatalf("expected a warm cache, got %d of %d hits", resp.Summary.CacheHits, len(req.Checks))
+				}
+			}
+		})
+	}
+}
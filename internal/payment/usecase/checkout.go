@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,11 +14,14 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/payment/subscription"
 	"github.com/jia-app/paymentservice/internal/shared/cache"
 	"github.com/jia-app/paymentservice/internal/shared/events"
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
 // CheckoutUseCase provides business logic for checkout operations
@@ -25,9 +30,33 @@ type CheckoutUseCase struct {
 	entitlementRepo      repo.EntitlementRepository
 	pricingZoneRepo      repo.PricingZoneRepository
 	paymentRepo          repo.PaymentRepository
+	checkoutSessionRepo  repo.CheckoutSessionRepository
+	billingProvider      billing.Provider
+	providerName         string // Name recorded on sessions created through billingProvider, unless it routes them
+	expiryConfig         CheckoutExpiryConfig
 	cache                *cache.Cache // Can be nil if Redis is not available
 	entitlementPublisher events.EntitlementPublisher
+	checkoutPublisher    events.CheckoutPublisher
+	invoiceUseCase       *InvoiceUseCase                // Can be nil if invoicing is disabled
+	taxEngine            *TaxEngine                     // Can be nil if tax is not charged
+	customerUseCase      *CustomerUseCase               // Can be nil if billing profiles are disabled
+	ledgerUseCase        *LedgerUseCase                 // Can be nil if the ledger is disabled
+	subscriptions        *subscription.LifecycleManager // Can be nil if subscriptions are not tracked
+	auditor              *audit.Recorder                // Can be nil if auditing is disabled
 	planFeatureService   *PlanFeatureService
+	metrics              *metrics.MetricsCollector // Can be nil if metrics are disabled
+}
+
+// CheckoutOptions holds the optional collaborators of a checkout use case;
+// each may be left nil to disable what it does
+type CheckoutOptions struct {
+	Invoices      *InvoiceUseCase                // Issues invoices for completed checkouts
+	Tax           *TaxEngine                     // Charges tax on checkouts
+	Customers     *CustomerUseCase               // Keeps the billing profiles of customers
+	Ledger        *LedgerUseCase                 // Posts payments to the ledger
+	Subscriptions *subscription.LifecycleManager // Tracks subscriptions started by checkouts
+	Auditor       *audit.Recorder                // Records changes to the audit log
+	Metrics       *metrics.MetricsCollector      // Records checkout and webhook metrics
 }
 
 // NewCheckoutUseCase creates a new checkout use case
@@ -36,96 +65,528 @@ func NewCheckoutUseCase(
 	entitlementRepo repo.EntitlementRepository,
 	pricingZoneRepo repo.PricingZoneRepository,
 	paymentRepo repo.PaymentRepository,
+	checkoutSessionRepo repo.CheckoutSessionRepository,
+	features *FeatureCatalogUseCase,
+	billingProvider billing.Provider,
+	providerName string,
+	expiryConfig CheckoutExpiryConfig,
 	cache *cache.Cache,
 	entitlementPublisher events.EntitlementPublisher,
+	checkoutPublisher events.CheckoutPublisher,
+	opts CheckoutOptions,
 ) *CheckoutUseCase {
-	planFeatureService := NewPlanFeatureService(planRepo)
+	planFeatureService := NewPlanFeatureService(planRepo, features)
 	return &CheckoutUseCase{
 		planRepo:             planRepo,
 		entitlementRepo:      entitlementRepo,
 		pricingZoneRepo:      pricingZoneRepo,
 		paymentRepo:          paymentRepo,
+		checkoutSessionRepo:  checkoutSessionRepo,
+		billingProvider:      billingProvider,
+		providerName:         providerName,
+		expiryConfig:         expiryConfig,
 		cache:                cache,
 		entitlementPublisher: entitlementPublisher,
+		checkoutPublisher:    checkoutPublisher,
+		invoiceUseCase:       opts.Invoices,
+		taxEngine:            opts.Tax,
+		customerUseCase:      opts.Customers,
+		ledgerUseCase:        opts.Ledger,
+		subscriptions:        opts.Subscriptions,
+		auditor:              opts.Auditor,
 		planFeatureService:   planFeatureService,
+		metrics:              opts.Metrics,
 	}
 }
 
-// CreateCheckoutSession creates a checkout session for a plan
-func (uc *CheckoutUseCase) CreateCheckoutSession(ctx context.Context, planID, userID string, familyID *string, countryCode string) (*CheckoutSessionResponse, error) {
+// CheckoutRequest describes a checkout to open for a plan
+type CheckoutRequest struct {
+	PlanID      string
+	UserID      string
+	FamilyID    *string
+	CountryCode string
+	Region      string // State or province, for regional sales tax
+	TaxID       string // Business tax ID; triggers the reverse charge where it applies
+	Currency    string // Optional; must match the plan's currency when set
+	SuccessURL  string
+	CancelURL   string
+}
+
+// CreateCheckoutSession quotes the plan's price for the customer's pricing
+// zone, adds the tax due in the customer's jurisdiction, opens a session
+// with the billing provider for the total and records it together with a
+// pending payment. The quote is taken from the plan catalog, never from the
+// caller. A saved billing profile fills in the country, region and tax ID
+// the caller leaves empty, and its default payment method is offered first.
+func (uc *CheckoutUseCase) CreateCheckoutSession(ctx context.Context, req CheckoutRequest) (*domain.CheckoutSession, error) {
 	// Validate input
-	if planID == "" {
+	if req.PlanID == "" {
 		return nil, status.Error(codes.InvalidArgument, "plan_id is required")
 	}
-	if userID == "" {
+	if req.UserID == "" {
 		if contextUserID := extractUserIDFromContext(ctx); contextUserID != "" {
-			userID = contextUserID
+			req.UserID = contextUserID
 		} else {
 			return nil, status.Error(codes.InvalidArgument, "user_id is required")
 		}
 	}
 
 	// Validate plan exists
-	plan, err := uc.planRepo.GetByID(ctx, planID)
+	plan, err := uc.planRepo.GetByID(ctx, req.PlanID)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get plan: %v", err)
+		return nil, err
 	}
-	if plan.ID.String() == "" {
-		return nil, status.Error(codes.NotFound, "plan not found")
+	if req.Currency != "" && !strings.EqualFold(req.Currency, plan.Currency) {
+		return nil, status.Errorf(codes.InvalidArgument, "plan %s is priced in %s, not %s", plan.Code, plan.Currency, req.Currency)
 	}
 
+	checkoutCustomer := uc.checkoutCustomer(ctx, &req)
+
 	// Calculate pricing based on country code
 	basePrice := plan.PriceDollars // Plan price in dollars
-	adjustedPrice := basePrice
 	pricingMultiplier := 1.0
+	countryCode := strings.ToUpper(req.CountryCode)
 
 	if countryCode != "" {
 		// Get pricing zone for the country
-		pricingZone, err := uc.pricingZoneRepo.GetByISOCode(ctx, strings.ToUpper(countryCode))
+		pricingZone, err := uc.pricingZoneRepo.GetByISOCode(ctx, countryCode)
 		if err == nil {
-			adjustedPrice = pricingZone.CalculateAdjustedPrice(basePrice)
-			pricingMultiplier = pricingZone.PricingMultiplier
+			if pricingZone.PricingMultiplier > 0 {
+				pricingMultiplier = pricingZone.PricingMultiplier
+			}
 
 			log.Info(ctx, "Applied dynamic pricing",
 				zap.String("country_code", countryCode),
 				zap.String("zone", pricingZone.Zone),
 				zap.String("zone_name", pricingZone.ZoneName),
 				zap.Float64("multiplier", pricingMultiplier),
-				zap.Float64("base_price", basePrice),
-				zap.Float64("adjusted_price", adjustedPrice))
+				zap.Float64("base_price", basePrice))
 		} else {
 			log.Warn(ctx, "Pricing zone not found, using base price",
 				zap.String("country_code", countryCode),
 				zap.Error(err))
 		}
 	}
+	quotedPrice := domain.QuotePrice(basePrice, pricingMultiplier)
+
+	tax := domain.CalculateTax(dollarsToCents(quotedPrice), nil, false)
+	if uc.taxEngine != nil {
+		tax, err = uc.taxEngine.Quote(ctx, TaxRequest{
+			CountryCode: countryCode,
+			Region:      req.Region,
+			TaxID:       req.TaxID,
+			PriceCents:  dollarsToCents(quotedPrice),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	totalPrice := float64(tax.TotalCents) / 100
 
-	// Generate placeholder session
-	sessionID := fmt.Sprintf("sess_%s", uuid.New().String()[:8])
-	redirectURL := fmt.Sprintf("https://checkout.stripe.com/pay/%s", sessionID)
+	// Open the session with the billing provider
+	resp, err := uc.billingProvider.CreateCheckoutSession(ctx, billing.CreateCheckoutSessionRequest{
+		PlanID:      plan.ID,
+		UserID:      req.UserID,
+		FamilyID:    req.FamilyID,
+		SuccessURL:  req.SuccessURL,
+		CancelURL:   req.CancelURL,
+		CountryCode: countryCode,
+		BasePrice:   totalPrice,
+		Currency:    plan.Currency,
+		ExpiresAt:   time.Now().Add(uc.expiryConfig.Window(uc.providerName)),
+		Metadata: map[string]string{
+			"plan_code":    plan.Code,
+			"plan_version": strconv.Itoa(int(plan.Version)),
+			"tax_cents":    strconv.FormatInt(tax.TaxCents, 10),
+		},
+		CustomerID:       checkoutCustomer.providerCustomerID,
+		PaymentMethodID:  checkoutCustomer.paymentMethodID,
+		CustomerProvider: checkoutCustomer.provider,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to create checkout session with %s: %v", uc.providerName, err)
+	}
 
-	log.Info(ctx, "Creating checkout session",
-		zap.String("plan_id", planID),
-		zap.String("user_id", userID),
-		zap.String("family_id", getStringValue(familyID)),
+	// A routing provider names the provider it picked; the session, its
+	// payment and its webhooks belong to that one from now on
+	providerName := uc.providerName
+	expiresAt := resp.ExpiresAt
+	if resp.Provider != "" && resp.Provider != providerName {
+		providerName = resp.Provider
+		if limit := time.Now().Add(uc.expiryConfig.Window(providerName)); expiresAt.After(limit) {
+			expiresAt = limit
+		}
+	}
+
+	session, err := uc.checkoutSessionRepo.Create(ctx, domain.CheckoutSession{
+		Provider:          providerName,
+		ProviderSessionID: resp.SessionID,
+		PlanCode:          plan.Code,
+		PlanVersion:       plan.Version,
+		UserID:            req.UserID,
+		FamilyID:          req.FamilyID,
+		CountryCode:       countryCode,
+		Region:            strings.ToUpper(req.Region),
+		BasePrice:         basePrice,
+		QuotedPrice:       quotedPrice,
+		PricingMultiplier: pricingMultiplier,
+		Currency:          plan.Currency,
+		TaxID:             domain.NormalizeTaxID(req.TaxID),
+		Tax:               tax,
+		Status:            domain.CheckoutSessionStatusOpen,
+		URL:               resp.URL,
+		ExpiresAt:         expiresAt,
+	})
+	if err != nil {
+		// Without a record the completion webhook could not be trusted, so
+		// don't leave the provider session payable
+		if cancelErr := uc.billingProvider.CancelSession(billing.WithProviderName(ctx, providerName), resp.SessionID); cancelErr != nil {
+			log.Error(ctx, "Failed to cancel unrecorded checkout session",
+				zap.String("session_id", resp.SessionID),
+				zap.Error(cancelErr))
+		}
+		return nil, status.Errorf(codes.Internal, "failed to record checkout session: %v", err)
+	}
+
+	// Track the checkout as a pending payment keyed by the session ID, with
+	// the tax charged recorded alongside the amount
+	paymentMetadata, _ := json.Marshal(map[string]domain.TaxQuote{"tax": tax})
+	payment := &domain.Payment{
+		ID:            uuid.New(),
+		Amount:        totalPrice,
+		Currency:      plan.Currency,
+		Status:        string(domain.PaymentStatusPending),
+		PaymentMethod: string(checkoutCustomer.paymentMethod),
+		CustomerID:    req.UserID,
+		OrderID:       session.ProviderSessionID,
+		Description:   fmt.Sprintf("Checkout session for plan %s", plan.Code),
+		Provider:      providerName,
+		Metadata:      paymentMetadata,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := uc.paymentRepo.Create(ctx, payment); err != nil {
+		// The session record is authoritative; the payment row is bookkeeping
+		log.Warn(ctx, "Failed to create payment record for checkout session",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.Error(err))
+	}
+
+	log.Info(ctx, "Created checkout session",
+		zap.String("session_id", session.ProviderSessionID),
+		zap.String("plan_id", plan.Code),
+		zap.Int32("plan_version", plan.Version),
+		zap.String("user_id", req.UserID),
+		zap.String("family_id", getStringValue(req.FamilyID)),
 		zap.String("country_code", countryCode),
 		zap.Float64("base_price", basePrice),
-		zap.Float64("adjusted_price", adjustedPrice),
+		zap.Float64("quoted_price", quotedPrice),
 		zap.Float64("pricing_multiplier", pricingMultiplier),
-		zap.String("provider", "stripe"))
+		zap.Int64("tax_cents", tax.TaxCents),
+		zap.Int64("total_cents", tax.TotalCents),
+		zap.Bool("reverse_charge", tax.ReverseCharge),
+		zap.Bool("saved_payment_method", checkoutCustomer.paymentMethodID != ""),
+		zap.String("provider", providerName))
 
-	return &CheckoutSessionResponse{
-		Provider:          "stripe",
-		SessionID:         sessionID,
-		RedirectURL:       redirectURL,
-		BasePrice:         basePrice,
-		AdjustedPrice:     adjustedPrice,
-		PricingMultiplier: pricingMultiplier,
-	}, nil
+	return &session, nil
+}
+
+// handles reports whether sessions and customers recorded with a provider
+// can be managed through the billing provider
+func (uc *CheckoutUseCase) handles(provider string) bool {
+	return provider == uc.providerName || billing.Handles(uc.billingProvider, provider)
+}
+
+// checkoutCustomerDetails is what checkout reuses from a saved billing profile
+type checkoutCustomerDetails struct {
+	provider           string // Provider the customer and payment method were saved with
+	providerCustomerID string
+	paymentMethodID    string
+	paymentMethod      domain.PaymentMethod
+}
+
+// checkoutCustomer looks up the user's billing profile and default payment
+// method. Country, region and tax ID the request leaves empty are taken
+// from the profile's address, the latter two only when it is in the
+// checkout country. A missing profile is not an error.
+func (uc *CheckoutUseCase) checkoutCustomer(ctx context.Context, req *CheckoutRequest) checkoutCustomerDetails {
+	details := checkoutCustomerDetails{paymentMethod: domain.PaymentMethodCreditCard}
+	if uc.customerUseCase == nil {
+		return details
+	}
+
+	customer, method, err := uc.customerUseCase.DefaultPaymentMethod(ctx, req.UserID)
+	if err != nil && !isNotFound(err) {
+		log.Warn(ctx, "Failed to load billing profile for checkout",
+			zap.String("user_id", req.UserID),
+			zap.Error(err))
+	}
+	if customer == nil {
+		return details
+	}
+
+	if req.CountryCode == "" {
+		req.CountryCode = customer.Address.Country
+	}
+	if strings.EqualFold(req.CountryCode, customer.Address.Country) {
+		if req.Region == "" {
+			req.Region = customer.Address.Region
+		}
+		if req.TaxID == "" {
+			req.TaxID = customer.TaxID
+		}
+	}
+
+	if uc.handles(customer.Provider) {
+		details.provider = customer.Provider
+		details.providerCustomerID = customer.ProviderCustomerID
+	}
+	if method != nil {
+		details.paymentMethodID = method.ProviderPaymentMethodID
+		details.paymentMethod = method.Type
+	}
+	return details
+}
+
+// GetCheckoutSession retrieves a checkout session. Open sessions are
+// refreshed from the billing provider so that sessions which expired or were
+// cancelled on the provider's side are reported as such. Completion is only
+// ever recorded by the webhook, which is what grants entitlements. Only
+// admins may read another user's session.
+func (uc *CheckoutUseCase) GetCheckoutSession(ctx context.Context, sessionID string) (*domain.CheckoutSession, error) {
+	if sessionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	session, err := uc.ownedSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsOpen() {
+		return &session, nil
+	}
+
+	remote, err := uc.billingProvider.GetSession(billing.WithProviderName(ctx, session.Provider), sessionID)
+	if err != nil {
+		// Serve the stored state rather than failing the read
+		log.Warn(ctx, "Failed to refresh checkout session from provider",
+			zap.String("session_id", sessionID),
+			zap.String("provider", session.Provider),
+			zap.Error(err))
+		return &session, nil
+	}
+
+	switch remoteStatus := domain.ParseCheckoutSessionStatus(remote.Status); remoteStatus {
+	case domain.CheckoutSessionStatusExpired, domain.CheckoutSessionStatusCancelled:
+		current, _, err := uc.closeSession(ctx, session, remoteStatus)
+		if err != nil {
+			return nil, err
+		}
+		return current, nil
+	}
+
+	return &session, nil
+}
+
+// CancelCheckoutSession cancels an open checkout session with the billing
+// provider and cancels its pending payment. Only admins may cancel another
+// user's session.
+func (uc *CheckoutUseCase) CancelCheckoutSession(ctx context.Context, sessionID string) (*domain.CheckoutSession, error) {
+	if sessionID == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	session, err := uc.ownedSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == domain.CheckoutSessionStatusCancelled {
+		return &session, nil
+	}
+	if !session.CanTransitionTo(domain.CheckoutSessionStatusCancelled) {
+		return nil, domain.NewCheckoutSessionTransitionError(&session, domain.CheckoutSessionStatusCancelled)
+	}
+
+	if err := uc.billingProvider.CancelSession(billing.WithProviderName(ctx, session.Provider), sessionID); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to cancel checkout session with %s: %v", session.Provider, err)
+	}
+
+	current, closed, err := uc.closeSession(ctx, session, domain.CheckoutSessionStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if !closed && current.Status != domain.CheckoutSessionStatusCancelled {
+		return nil, domain.NewCheckoutSessionTransitionError(current, domain.CheckoutSessionStatusCancelled)
+	}
+	return current, nil
+}
+
+// ownedSession loads a checkout session by the provider's session ID, from
+// whichever provider it was opened with, for a caller who may access it.
+// Only admins may access another user's session.
+func (uc *CheckoutUseCase) ownedSession(ctx context.Context, sessionID string) (domain.CheckoutSession, error) {
+	sessions, err := uc.checkoutSessionRepo.ListByProviderSessionID(ctx, sessionID)
+	if err != nil {
+		return domain.CheckoutSession{}, err
+	}
+
+	var (
+		owned  []domain.CheckoutSession
+		denied error
+	)
+	for _, session := range sessions {
+		if _, err := authorizeOwner(ctx, session.UserID); err != nil {
+			denied = err
+			continue
+		}
+		owned = append(owned, session)
+	}
+
+	switch {
+	case len(owned) == 1:
+		return owned[0], nil
+	case len(owned) > 1:
+		return domain.CheckoutSession{}, domain.NewInvalidInputError("ambiguous checkout session", "session_id was issued by more than one provider")
+	case denied != nil:
+		return domain.CheckoutSession{}, denied
+	default:
+		return domain.CheckoutSession{}, domain.NewNotFoundError("checkout session", sessionID)
+	}
+}
+
+// sessionProvider returns the provider that webhooks which do not name one
+// are addressed to: the one named with billing.WithProviderName, or else the
+// one sessions are opened with
+func (uc *CheckoutUseCase) sessionProvider(ctx context.Context) string {
+	if name := billing.ProviderNameFromContext(ctx); name != "" {
+		return name
+	}
+	return uc.providerName
+}
+
+// closeSession records that an open session ended without payment, cancels
+// the pending payment created for it and, for expired sessions, announces
+// the abandoned checkout. The boolean is false when the session was no
+// longer open, e.g. because its completion webhook won the race; the
+// returned session then reflects its current state.
+func (uc *CheckoutUseCase) closeSession(ctx context.Context, session domain.CheckoutSession, to domain.CheckoutSessionStatus) (*domain.CheckoutSession, bool, error) {
+	current, closed, err := uc.checkoutSessionRepo.CloseIfOpen(ctx, session.ID, to)
+	if err != nil {
+		return nil, false, err
+	}
+	if !closed {
+		return &current, false, nil
+	}
+
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, session.ProviderSessionID)
+	if err == nil && payment != nil && payment.Status == string(domain.PaymentStatusPending) {
+		if err := uc.paymentRepo.UpdateStatus(ctx, payment.ID.String(), string(domain.PaymentStatusCancelled)); err != nil {
+			log.Error(ctx, "Failed to cancel payment for checkout session",
+				zap.String("payment_id", payment.ID.String()),
+				zap.String("session_id", session.ProviderSessionID),
+				zap.Error(err))
+		} else {
+			recordPaymentStatusChange(ctx, uc.auditor, payment, string(domain.PaymentStatusCancelled))
+		}
+	}
+
+	if to == domain.CheckoutSessionStatusExpired && uc.checkoutPublisher != nil {
+		if err := uc.checkoutPublisher.PublishCheckoutAbandoned(ctx, events.NewCheckoutAbandonedEvent(current)); err != nil {
+			log.Error(ctx, "Failed to publish checkout.abandoned event",
+				zap.String("session_id", session.ProviderSessionID),
+				zap.Error(err))
+		}
+	}
+
+	log.Info(ctx, "Checkout session closed",
+		zap.String("session_id", session.ProviderSessionID),
+		zap.String("status", string(to)))
+
+	return &current, true, nil
 }
 
-// ApplyWebhook applies a webhook result from billing provider
-func (uc *CheckoutUseCase) ApplyWebhook(ctx context.Context, wr billing.WebhookResult) error {
+// ExpireStaleSessions expires open checkout sessions whose expiry has
+// passed, up to the configured batch size, and returns how many it expired.
+// Sessions are also expired with the provider where possible so that a
+// stale checkout page can no longer be paid.
+func (uc *CheckoutUseCase) ExpireStaleSessions(ctx context.Context) (int, error) {
+	batchSize := uc.expiryConfig.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultCheckoutExpiryConfig().BatchSize
+	}
+
+	sessions, err := uc.checkoutSessionRepo.ListExpired(ctx, time.Now(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, session := range sessions {
+		if uc.handles(session.Provider) {
+			if err := uc.billingProvider.CancelSession(billing.WithProviderName(ctx, session.Provider), session.ProviderSessionID); err != nil {
+				// The provider usually expired it already
+				log.Debug(ctx, "Failed to expire checkout session with provider",
+					zap.String("session_id", session.ProviderSessionID),
+					zap.Error(err))
+			}
+		}
+
+		_, closed, err := uc.closeSession(ctx, session, domain.CheckoutSessionStatusExpired)
+		if err != nil {
+			log.Error(ctx, "Failed to expire checkout session",
+				zap.String("session_id", session.ProviderSessionID),
+				zap.Error(err))
+			continue
+		}
+		if closed {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// checkoutCompletionLease is how long a delivery's claim on a checkout
+// session lasts before another delivery may take it over, e.g. after the
+// process applying it crashed
+const checkoutCompletionLease = 5 * time.Minute
+
+// ApplyWebhook applies a webhook result from billing provider. A checkout
+// session is only marked complete once its payment has been completed;
+// webhooks that fail to complete it return an error so the provider retries.
+func (uc *CheckoutUseCase) ApplyWebhook(ctx context.Context, wr billing.WebhookResult) (err error) {
+	start := time.Now()
+	defer func() {
+		uc.metrics.RecordWebhook(ctx, wr.EventType, err == nil, time.Since(start))
+	}()
+
+	// Failed, unsettled and reversed payments and ended subscriptions grant
+	// nothing; reversals are posted to the ledger
+	switch billing.WebhookEventType(wr.EventType) {
+	case billing.WebhookEventTypePaymentRefunded, billing.WebhookEventTypePaymentChargeback:
+		return uc.applyReversal(ctx, wr)
+	case billing.WebhookEventTypePaymentFailed, billing.WebhookEventTypePaymentPending,
+		billing.WebhookEventTypeSubscriptionUpdated, billing.WebhookEventTypeSubscriptionCancelled:
+		log.Info(ctx, "Webhook grants no entitlements, acknowledging",
+			zap.String("event_type", wr.EventType),
+			zap.String("session_id", wr.SessionID),
+			zap.String("status", wr.Status))
+		return nil
+	}
+
+	// The recorded checkout session, when there is one, decides who is
+	// granted what; the provider's echo of it is only cross-checked
+	session, err := uc.resolveCheckoutSession(ctx, &wr)
+	if err != nil {
+		return err
+	}
+	if session != nil && isSettledCheckoutSession(session) {
+		log.Info(ctx, "Checkout session already settled, ignoring duplicate webhook",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.String("status", string(session.Status)))
+		return nil
+	}
+
 	// Validate webhook result
 	if wr.UserID == "" {
 		return status.Error(codes.InvalidArgument, "user_id is required in webhook result")
@@ -134,11 +595,64 @@ func (uc *CheckoutUseCase) ApplyWebhook(ctx context.Context, wr billing.WebhookR
 		return status.Error(codes.InvalidArgument, "plan_id_string is required in webhook result")
 	}
 
-	// Grant all entitlements for the plan
-	grantedFeatures, err := uc.planFeatureService.GrantEntitlementsForPlan(
+	// Claim the session so that concurrent deliveries of the webhook apply
+	// it once. A failed delivery releases its claim for the provider's retry.
+	if session != nil {
+		claimed, ok, claimErr := uc.checkoutSessionRepo.ClaimForCompletion(ctx, session.ID, time.Now().Add(-checkoutCompletionLease))
+		if claimErr != nil {
+			return status.Errorf(codes.Internal, "failed to claim checkout session: %v", claimErr)
+		}
+		if !ok {
+			if isSettledCheckoutSession(&claimed) {
+				log.Info(ctx, "Checkout session already settled, ignoring duplicate webhook",
+					zap.String("session_id", session.ProviderSessionID),
+					zap.String("status", string(claimed.Status)))
+				return nil
+			}
+			return status.Errorf(codes.Aborted, "checkout session %s is being completed by another delivery", session.ProviderSessionID)
+		}
+
+		previous := session.Status
+		if previous == domain.CheckoutSessionStatusCompleting {
+			// Taking over a stale claim
+			previous = domain.CheckoutSessionStatusOpen
+		}
+		defer func() {
+			if err != nil {
+				uc.releaseCheckoutSession(ctx, claimed, previous)
+			}
+		}()
+
+		// A charge that differs from the quote grants nothing and leaves the
+		// payment pending until the session has been reviewed
+		if !session.MatchesCharge(wr.Amount, wr.Currency) {
+			if _, err := uc.checkoutSessionRepo.UpdateStatus(ctx, session.ID, domain.CheckoutSessionStatusNeedsReview); err != nil {
+				return status.Errorf(codes.Internal, "failed to hold checkout session %s for review: %v", session.ProviderSessionID, err)
+			}
+			log.Error(ctx, "Webhook charge differs from checkout total, holding the session for review",
+				zap.String("session_id", session.ProviderSessionID),
+				zap.Float64("webhook_amount", wr.Amount),
+				zap.String("webhook_currency", wr.Currency),
+				zap.Int64("total_cents", session.TotalCents()),
+				zap.String("currency", session.Currency))
+			return nil
+		}
+	}
+
+	// Complete the payment before anything is granted; a webhook whose
+	// payment cannot be completed fails so that the provider retries it
+	payment, err := uc.completeCheckoutPayment(ctx, wr)
+	if err != nil {
+		return err
+	}
+
+	// Grant the plan's features at the version it was sold at, as the
+	// feature catalog resolves them
+	entitlements, err := uc.planFeatureService.EntitlementsForPlan(
 		ctx,
 		wr.UserID,
 		wr.PlanIDString,
+		uc.planVersion(ctx, session, wr),
 		wr.FamilyID,
 		&wr.SubscriptionID,
 		wr.ExpiresAt,
@@ -147,57 +661,36 @@ func (uc *CheckoutUseCase) ApplyWebhook(ctx context.Context, wr billing.WebhookR
 		return status.Errorf(codes.Internal, "failed to grant entitlements for plan %s: %v", wr.PlanIDString, err)
 	}
 
-	// Create entitlements for each feature
-	for _, featureCode := range grantedFeatures {
-		// Convert string plan ID to UUID for domain model
-		planID := uuid.NewSHA1(uuid.NameSpaceOID, []byte(wr.PlanIDString))
+	grantedFeatures := make([]string, 0, len(entitlements))
+	for _, entitlement := range entitlements {
+		featureCode := entitlement.FeatureCode
+		grantedFeatures = append(grantedFeatures, featureCode)
 
-		// Check if entitlement already exists
+		// A failed grant fails the webhook, leaving the session claimable
+		// for the provider's retry
 		existingEntitlement, found, err := uc.entitlementRepo.Check(ctx, wr.UserID, featureCode)
 		if err != nil {
-			log.Error(ctx, "Failed to check existing entitlement",
-				zap.String("user_id", wr.UserID),
-				zap.String("feature_code", featureCode),
-				zap.Error(err))
-			continue
+			return status.Errorf(codes.Internal, "failed to check entitlement %s of user %s: %v", featureCode, wr.UserID, err)
 		}
 
-		// If entitlement already exists and is active, skip creation
+		// An active entitlement is kept; a renewal moves its expiry forward
 		if found && existingEntitlement.Status == "active" {
-			log.Info(ctx, "Entitlement already exists, skipping creation",
-				zap.String("user_id", wr.UserID),
-				zap.String("feature_code", featureCode),
-				zap.String("plan_id", wr.PlanIDString))
+			if !extendsExpiry(existingEntitlement.ExpiresAt, entitlement.ExpiresAt) {
+				log.Info(ctx, "Entitlement already exists, skipping creation",
+					zap.String("user_id", wr.UserID),
+					zap.String("feature_code", featureCode),
+					zap.String("plan_id", wr.PlanIDString))
+				continue
+			}
+			if err := uc.extendEntitlement(ctx, existingEntitlement, *entitlement.ExpiresAt); err != nil {
+				return err
+			}
 			continue
 		}
 
-		entitlement := domain.Entitlement{
-			ID:          uuid.New(),
-			UserID:      wr.UserID,
-			FamilyID:    wr.FamilyID,
-			FeatureCode: featureCode,
-			PlanID:      planID,
-			Status:      "active",
-			GrantedAt:   time.Now(),
-			ExpiresAt:   wr.ExpiresAt,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
-		}
-
-		// Set subscription ID if provided
-		if wr.SubscriptionID != "" {
-			entitlement.SubscriptionID = &wr.SubscriptionID
-		}
-
-		// Insert entitlement
 		savedEntitlement, err := uc.entitlementRepo.Insert(ctx, entitlement)
 		if err != nil {
-			log.Error(ctx, "Failed to insert entitlement",
-				zap.String("user_id", wr.UserID),
-				zap.String("feature_code", featureCode),
-				zap.String("plan_id", wr.PlanIDString),
-				zap.Error(err))
-			continue // Continue with other entitlements even if one fails
+			return status.Errorf(codes.Internal, "failed to insert entitlement %s for user %s: %v", featureCode, wr.UserID, err)
 		}
 
 		// Publish entitlement.updated event for each entitlement
@@ -219,60 +712,366 @@ func (uc *CheckoutUseCase) ApplyWebhook(ctx context.Context, wr billing.WebhookR
 			zap.String("family_id", getStringValue(wr.FamilyID)))
 	}
 
-	// Update payment status to completed if session ID is provided
-	if wr.SessionID != "" {
-		log.Info(ctx, "Attempting to update payment status",
+	if payment != nil {
+		uc.issueInvoice(ctx, payment, session, wr)
+	}
+	uc.recordSubscription(ctx, session, wr)
+
+	if session != nil {
+		if _, err := uc.checkoutSessionRepo.UpdateStatus(ctx, session.ID, domain.CheckoutSessionStatusComplete); err != nil {
+			return status.Errorf(codes.Internal, "failed to mark checkout session %s complete: %v", session.ProviderSessionID, err)
+		}
+	}
+
+	log.Info(ctx, "Webhook applied successfully",
+		zap.String("user_id", wr.UserID),
+		zap.String("plan_id", wr.PlanIDString),
+		zap.String("granted_features", strings.Join(grantedFeatures, ",")),
+		zap.String("family_id", getStringValue(wr.FamilyID)))
+
+	return nil
+}
+
+// extendsExpiry reports whether a grant expiring at granted outlasts an
+// entitlement expiring at current. Entitlements that never expire are
+// never extended, and grants without an expiry do not extend them.
+func extendsExpiry(current, granted *time.Time) bool {
+	return current != nil && granted != nil && granted.After(*current)
+}
+
+// extendEntitlement moves an active entitlement's expiry forward for a renewal
+func (uc *CheckoutUseCase) extendEntitlement(ctx context.Context, current domain.Entitlement, expiresAt time.Time) error {
+	extend := current
+	extend.ExpiresAt = &expiresAt
+	extended, err := uc.entitlementRepo.Update(ctx, extend)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to extend entitlement %s: %v", current.ID, err)
+	}
+
+	if uc.entitlementPublisher != nil {
+		if err := uc.entitlementPublisher.PublishEntitlementUpdated(ctx, extended, events.EntitlementChangeUpdated); err != nil {
+			log.Error(ctx, "Failed to publish entitlement.updated event", zap.Error(err))
+		}
+	}
+	if uc.cache != nil {
+		uc.cache.DeleteEntitlement(ctx, extended.UserID, extended.FeatureCode)
+	}
+
+	log.Info(ctx, "Entitlement extended",
+		zap.String("entitlement_id", extended.ID.String()),
+		zap.String("user_id", extended.UserID),
+		zap.String("feature_code", extended.FeatureCode),
+		zap.Time("expires_at", expiresAt))
+	return nil
+}
+
+// applyReversal records a refund or chargeback of a checkout's payment. A
+// refund is posted to the ledger and marks the payment refunded once nothing
+// is left of it; a chargeback posts the funds the provider withheld and its
+// fee. Reversals of payments that were not recorded, and refunds that failed
+// at the provider, are acknowledged without posting anything.
+func (uc *CheckoutUseCase) applyReversal(ctx context.Context, wr billing.WebhookResult) error {
+	if wr.Status == string(billing.ChargeStatusFailed) || wr.SessionID == "" || wr.Reference == "" {
+		log.Info(ctx, "Webhook reverses no recorded payment, acknowledging",
+			zap.String("event_type", wr.EventType),
 			zap.String("session_id", wr.SessionID),
-			zap.String("user_id", wr.UserID),
-			zap.String("plan_id", wr.PlanIDString))
+			zap.String("reference", wr.Reference),
+			zap.String("status", wr.Status))
+		return nil
+	}
 
-		// Find payment by order ID (session ID)
-		payment, err := uc.paymentRepo.GetByOrderID(ctx, wr.SessionID)
-		if err == nil && payment != nil {
-			log.Info(ctx, "Found payment for session ID",
-				zap.String("payment_id", payment.ID.String()),
-				zap.String("session_id", wr.SessionID),
-				zap.String("current_status", payment.Status))
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, wr.SessionID)
+	if err != nil {
+		if isNotFound(err) {
+			log.Warn(ctx, "Payment not found for reversed session",
+				zap.String("event_type", wr.EventType),
+				zap.String("session_id", wr.SessionID))
+			return nil
+		}
+		return status.Errorf(codes.Internal, "failed to load payment for session %s: %v", wr.SessionID, err)
+	}
+	amountCents := dollarsToCents(wr.Amount)
 
-			// Update payment status to completed
-			if err := uc.paymentRepo.UpdateStatus(ctx, payment.ID.String(), "completed"); err != nil {
-				log.Error(ctx, "Failed to update payment status",
-					zap.String("payment_id", payment.ID.String()),
-					zap.String("session_id", wr.SessionID),
-					zap.Error(err))
-			} else {
-				log.Info(ctx, "Payment status updated to completed",
-					zap.String("payment_id", payment.ID.String()),
-					zap.String("session_id", wr.SessionID))
-			}
-		} else {
-			log.Warn(ctx, "Payment not found for session ID",
-				zap.String("session_id", wr.SessionID),
-				zap.Error(err))
+	if billing.WebhookEventType(wr.EventType) == billing.WebhookEventTypePaymentChargeback {
+		if uc.ledgerUseCase == nil {
+			return nil
+		}
+		if _, err := uc.ledgerUseCase.OpenDispute(ctx, payment.ID.String(), wr.Reference, amountCents, dollarsToCents(wr.FeeAmount)); err != nil {
+			return status.Errorf(codes.Internal, "failed to record chargeback %s of payment %s: %v", wr.Reference, payment.ID, err)
+		}
+		log.Info(ctx, "Chargeback recorded",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("dispute_id", wr.Reference),
+			zap.Int64("amount_cents", amountCents))
+		return nil
+	}
+
+	var refunded bool
+	if uc.ledgerUseCase == nil {
+		if refunded = amountCents >= dollarsToCents(payment.Amount) && payment.Status != string(domain.PaymentStatusRefunded); refunded {
+			err = uc.paymentRepo.UpdateStatus(ctx, payment.ID.String(), string(domain.PaymentStatusRefunded))
 		}
 	} else {
+		refunded, err = uc.ledgerUseCase.ApplyRefund(ctx, payment, wr.Reference, amountCents)
+	}
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to record refund %s of payment %s: %v", wr.Reference, payment.ID, err)
+	}
+	if refunded {
+		recordPaymentStatusChange(ctx, uc.auditor, payment, string(domain.PaymentStatusRefunded))
+	}
+
+	log.Info(ctx, "Refund recorded",
+		zap.String("payment_id", payment.ID.String()),
+		zap.String("refund_id", wr.Reference),
+		zap.Int64("amount_cents", amountCents),
+		zap.Bool("refunded_in_full", refunded))
+	return nil
+}
+
+// completeCheckoutPayment completes the pending payment recorded for a
+// webhook's checkout session and returns it. Webhooks without a session ID,
+// or for sessions with no recorded payment, have nothing to complete.
+func (uc *CheckoutUseCase) completeCheckoutPayment(ctx context.Context, wr billing.WebhookResult) (*domain.Payment, error) {
+	if wr.SessionID == "" {
 		log.Warn(ctx, "No session ID provided in webhook result",
 			zap.String("user_id", wr.UserID),
 			zap.String("plan_id", wr.PlanIDString))
+		return nil, nil
 	}
 
-	log.Info(ctx, "Webhook applied successfully",
-		zap.String("user_id", wr.UserID),
-		zap.String("plan_id", wr.PlanIDString),
-		zap.String("granted_features", strings.Join(grantedFeatures, ",")),
-		zap.String("family_id", getStringValue(wr.FamilyID)))
+	// Find payment by order ID (session ID)
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, wr.SessionID)
+	if err != nil {
+		if isNotFound(err) {
+			log.Warn(ctx, "Payment not found for session ID",
+				zap.String("session_id", wr.SessionID))
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "failed to load payment for session %s: %v", wr.SessionID, err)
+	}
+	if payment.Status == string(domain.PaymentStatusCompleted) {
+		// Completed by an earlier delivery that failed later on
+		return payment, nil
+	}
+
+	if err := uc.completePayment(ctx, payment, wr); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to complete payment %s: %v", payment.ID, err)
+	}
+
+	uc.metrics.RecordPayment(ctx, true, payment.Amount, time.Since(payment.CreatedAt))
+	log.Info(ctx, "Payment status updated to completed",
+		zap.String("payment_id", payment.ID.String()),
+		zap.String("session_id", wr.SessionID))
+
+	return payment, nil
+}
+
+// isSettledCheckoutSession reports whether a session's completion webhook
+// has been applied, or held for review, so that redeliveries are ignored
+func isSettledCheckoutSession(session *domain.CheckoutSession) bool {
+	return session.Status == domain.CheckoutSessionStatusComplete || session.Status == domain.CheckoutSessionStatusNeedsReview
+}
+
+// releaseCheckoutSession hands a claimed checkout session back to the
+// status it was claimed from after its webhook failed, so that the
+// provider's retry can claim it again
+func (uc *CheckoutUseCase) releaseCheckoutSession(ctx context.Context, session domain.CheckoutSession, to domain.CheckoutSessionStatus) {
+	if _, err := uc.checkoutSessionRepo.UpdateStatus(ctx, session.ID, to); err != nil {
+		// The claim lapses after checkoutCompletionLease
+		log.Error(ctx, "Failed to release checkout session",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.Error(err))
+	}
+}
+
+// planVersion returns the plan version a webhook grants: the version its
+// checkout was quoted at or, for renewals, the version the subscription is
+// pinned to. Zero grants the plan's current version.
+func (uc *CheckoutUseCase) planVersion(ctx context.Context, session *domain.CheckoutSession, wr billing.WebhookResult) int32 {
+	if session != nil {
+		return session.PlanVersion
+	}
+	if wr.SubscriptionID == "" || uc.subscriptions == nil {
+		return 0
+	}
+
+	sub, err := uc.subscriptions.GetByExternalID(ctx, wr.SubscriptionID)
+	if err != nil {
+		if !isNotFound(err) {
+			log.Warn(ctx, "Failed to load subscription for webhook, granting the current plan version",
+				zap.String("subscription_id", wr.SubscriptionID),
+				zap.Error(err))
+		}
+		return 0
+	}
+	if sub.PlanID != domain.PlanUUID(wr.PlanIDString) {
+		// The subscription moved to another plan
+		return 0
+	}
+	return sub.PlanVersion
+}
+
+// recordSubscription records the subscription a completed checkout started,
+// pinned to the plan version the checkout was quoted at, so that renewals
+// keep granting that version. Failures are logged rather than failing the
+// webhook; the entitlements have been granted.
+func (uc *CheckoutUseCase) recordSubscription(ctx context.Context, session *domain.CheckoutSession, wr billing.WebhookResult) {
+	if uc.subscriptions == nil || session == nil || wr.SubscriptionID == "" {
+		return
+	}
+
+	if _, err := uc.subscriptions.GetByExternalID(ctx, wr.SubscriptionID); err == nil {
+		return
+	} else if !isNotFound(err) {
+		log.Error(ctx, "Failed to look up subscription for checkout",
+			zap.String("subscription_id", wr.SubscriptionID),
+			zap.Error(err))
+		return
+	}
+
+	plan, err := uc.planFeatureService.PlanAtVersion(ctx, session.PlanCode, session.PlanVersion)
+	if err != nil {
+		log.Error(ctx, "Failed to record subscription for checkout",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.String("subscription_id", wr.SubscriptionID),
+			zap.Error(err))
+		return
+	}
 
+	periodStart := time.Now()
+	periodEnd := plan.PeriodEnd(periodStart)
+	if wr.ExpiresAt != nil {
+		periodEnd = *wr.ExpiresAt
+	}
+	if _, err := uc.subscriptions.CreateSubscription(ctx, subscription.CreateSubscriptionRequest{
+		UserID:                 session.UserID,
+		FamilyID:               session.FamilyID,
+		PlanID:                 plan.ID,
+		PlanVersion:            session.PlanVersion,
+		CurrentPeriodStart:     periodStart,
+		CurrentPeriodEnd:       periodEnd,
+		ExternalSubscriptionID: wr.SubscriptionID,
+		Metadata:               map[string]interface{}{"checkout_session_id": session.ProviderSessionID},
+	}); err != nil {
+		log.Error(ctx, "Failed to record subscription for checkout",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.String("subscription_id", wr.SubscriptionID),
+			zap.Error(err))
+	}
+}
+
+// completePayment marks a checkout's payment completed, posting its charge
+// to the ledger in the same transaction when the ledger is enabled
+func (uc *CheckoutUseCase) completePayment(ctx context.Context, payment *domain.Payment, wr billing.WebhookResult) error {
+	status := string(domain.PaymentStatusCompleted)
+	var err error
+	if uc.ledgerUseCase == nil {
+		err = uc.paymentRepo.UpdateStatus(ctx, payment.ID.String(), status)
+	} else {
+		err = uc.ledgerUseCase.UpdatePaymentStatus(ctx, payment, status, domain.ChargeBreakdown{
+			DiscountCents: dollarsToCents(wr.DiscountAmount),
+			TaxCents:      dollarsToCents(wr.TaxAmount),
+			FeeCents:      dollarsToCents(wr.FeeAmount),
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	recordPaymentStatusChange(ctx, uc.auditor, payment, status)
 	return nil
 }
 
-// Helper types for responses
-type CheckoutSessionResponse struct {
-	Provider          string  `json:"provider"`
-	SessionID         string  `json:"session_id"`
-	RedirectURL       string  `json:"redirect_url"`
-	BasePrice         float64 `json:"base_price"`         // Base price in dollars
-	AdjustedPrice     float64 `json:"adjusted_price"`     // Price after multiplier in dollars
-	PricingMultiplier float64 `json:"pricing_multiplier"` // Applied multiplier
+// issueInvoice invoices a completed payment. Invoicing failures are logged
+// rather than failing the webhook; the entitlements have been granted.
+func (uc *CheckoutUseCase) issueInvoice(ctx context.Context, payment *domain.Payment, session *domain.CheckoutSession, wr billing.WebhookResult) {
+	if uc.invoiceUseCase == nil {
+		return
+	}
+
+	req := InvoiceRequest{
+		Payment:       payment,
+		Session:       session,
+		PlanCode:      wr.PlanIDString,
+		FamilyID:      wr.FamilyID,
+		DiscountCents: dollarsToCents(wr.DiscountAmount),
+		TaxCents:      dollarsToCents(wr.TaxAmount),
+	}
+	if c := wr.Customer; c != nil {
+		req.Billing = domain.BillingDetails{
+			Name:  c.Name,
+			Email: c.Email,
+			TaxID: c.TaxID,
+			Address: domain.Address{
+				Line1:      c.Address.Line1,
+				Line2:      c.Address.Line2,
+				City:       c.Address.City,
+				Region:     c.Address.Region,
+				PostalCode: c.Address.PostalCode,
+				Country:    c.Address.Country,
+			},
+		}
+	} else if uc.customerUseCase != nil {
+		// The provider collected no details; bill the saved profile
+		if customer, err := uc.customerUseCase.customer(ctx, payment.CustomerID); err == nil {
+			req.Billing = customer.BillingDetails()
+		}
+	}
+
+	if _, err := uc.invoiceUseCase.GenerateForPayment(ctx, req); err != nil {
+		log.Error(ctx, "Failed to issue invoice",
+			zap.String("payment_id", payment.ID.String()),
+			zap.String("session_id", wr.SessionID),
+			zap.Error(err))
+	}
+}
+
+// resolveCheckoutSession loads the checkout session a webhook refers to,
+// looked up by the provider the webhook came from, and overwrites the
+// webhook's plan, user and family with the recorded values. The charge the
+// webhook reports is checked against the session once it has been claimed.
+// Webhooks for sessions created before sessions were recorded fall back to
+// the provider's metadata.
+func (uc *CheckoutUseCase) resolveCheckoutSession(ctx context.Context, wr *billing.WebhookResult) (*domain.CheckoutSession, error) {
+	if wr.SessionID == "" || uc.checkoutSessionRepo == nil {
+		return nil, nil
+	}
+
+	provider := wr.Provider
+	if provider == "" {
+		provider = uc.sessionProvider(ctx)
+	}
+	session, err := uc.checkoutSessionRepo.GetByProviderSessionID(ctx, provider, wr.SessionID)
+	if err != nil {
+		if isNotFound(err) {
+			log.Warn(ctx, "No checkout session recorded for webhook, using provider metadata",
+				zap.String("session_id", wr.SessionID),
+				zap.String("provider", provider))
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "failed to load checkout session: %v", err)
+	}
+
+	if wr.UserID != "" && wr.UserID != session.UserID {
+		log.Warn(ctx, "Webhook user differs from checkout session",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.String("webhook_user_id", wr.UserID),
+			zap.String("session_user_id", session.UserID))
+	}
+	if session.Status == domain.CheckoutSessionStatusExpired || session.Status == domain.CheckoutSessionStatusCancelled {
+		// The customer paid anyway; honour the payment
+		log.Warn(ctx, "Completion webhook for closed checkout session",
+			zap.String("session_id", session.ProviderSessionID),
+			zap.String("status", string(session.Status)))
+	}
+
+	wr.UserID = session.UserID
+	wr.FamilyID = session.FamilyID
+	wr.PlanIDString = session.PlanCode
+	wr.PlanID = domain.PlanUUID(session.PlanCode)
+
+	return &session, nil
 }
 
 // Helper functions
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// CheckoutExpiryConfig holds configuration for checkout session expiry
+type CheckoutExpiryConfig struct {
+	Interval        time.Duration            `json:"interval"`         // How often the worker looks for expired sessions
+	BatchSize       int                      `json:"batch_size"`       // Sessions expired per pass
+	DefaultWindow   time.Duration            `json:"default_window"`   // Session lifetime for providers without an override
+	ProviderWindows map[string]time.Duration `json:"provider_windows"` // Session lifetime per provider
+}
+
+// DefaultCheckoutExpiryConfig returns a default checkout expiry configuration
+func DefaultCheckoutExpiryConfig() CheckoutExpiryConfig {
+	return CheckoutExpiryConfig{
+		Interval:      1 * time.Minute,
+		BatchSize:     100,
+		DefaultWindow: 1 * time.Hour,
+		ProviderWindows: map[string]time.Duration{
+			"stripe": 24 * time.Hour, // Stripe's maximum session lifetime
+		},
+	}
+}
+
+// Window returns how long a checkout session opened with provider stays open
+func (c CheckoutExpiryConfig) Window(provider string) time.Duration {
+	if window, ok := c.ProviderWindows[provider]; ok && window > 0 {
+		return window
+	}
+	if c.DefaultWindow > 0 {
+		return c.DefaultWindow
+	}
+	return DefaultCheckoutExpiryConfig().DefaultWindow
+}
+
+// CheckoutExpiryWorker periodically expires checkout sessions that were
+// never paid, cancelling their pending payments
+type CheckoutExpiryWorker struct {
+	checkoutUseCase *CheckoutUseCase
+	interval        time.Duration
+	ticker          *time.Ticker
+	stopChan        chan bool
+}
+
+// NewCheckoutExpiryWorker creates a new checkout expiry worker
+func NewCheckoutExpiryWorker(checkoutUseCase *CheckoutUseCase) *CheckoutExpiryWorker {
+	interval := checkoutUseCase.expiryConfig.Interval
+	if interval <= 0 {
+		interval = DefaultCheckoutExpiryConfig().Interval
+	}
+
+	return &CheckoutExpiryWorker{
+		checkoutUseCase: checkoutUseCase,
+		interval:        interval,
+		stopChan:        make(chan bool),
+	}
+}
+
+// Start starts the checkout expiry worker
+func (w *CheckoutExpiryWorker) Start(ctx context.Context) {
+	w.ticker = time.NewTicker(w.interval)
+	log.L(ctx).Info("Starting checkout expiry worker", zap.Duration("interval", w.interval))
+
+	go func() {
+		for {
+			select {
+			case <-w.ticker.C:
+				w.expireSessions(ctx)
+			case <-w.stopChan:
+				log.L(ctx).Info("Stopping checkout expiry worker")
+				return
+			case <-ctx.Done():
+				log.L(ctx).Info("Checkout expiry worker context cancelled")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the checkout expiry worker
+func (w *CheckoutExpiryWorker) Stop() {
+	if w.ticker != nil {
+		w.ticker.Stop()
+	}
+	w.stopChan <- true
+}
+
+// expireSessions runs one expiry pass
+func (w *CheckoutExpiryWorker) expireSessions(ctx context.Context) {
+	expired, err := w.checkoutUseCase.ExpireStaleSessions(ctx)
+	if err != nil {
+		log.Error(ctx, "Failed to expire checkout sessions", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		log.Info(ctx, "Expired abandoned checkout sessions", zap.Int("count", expired))
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/payment/subscription"
+)
+
+func TestCheckoutUseCase_RenewalGrantsPinnedPlanVersion(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	features := newFeatureCatalog(t, store)
+	plans := NewPlanCatalogUseCase(store.Plan(), features, nil)
+	plan, err := plans.CreatePlan(ctx, catalogPlan("pro_storage", "priority_support"))
+	if err != nil {
+		t.Fatalf("CreatePlan() error = %v", err)
+	}
+
+	subscriptions := subscription.NewLifecycleManager(store.Subscription(), store.Entitlement(), nil, nil, nil)
+	if _, err := subscriptions.CreateSubscription(ctx, subscription.CreateSubscriptionRequest{
+		UserID:                 "user-1",
+		PlanID:                 plan.ID,
+		PlanVersion:            plan.Version,
+		CurrentPeriodStart:     time.Now(),
+		CurrentPeriodEnd:       time.Now().AddDate(0, 1, 0),
+		ExternalSubscriptionID: "sub_1",
+	}); err != nil {
+		t.Fatalf("CreateSubscription() error = %v", err)
+	}
+
+	// New customers get less storage and no priority support from version 2
+	if _, versioned, err := plans.UpdatePlan(ctx, plan.Code, domain.PlanUpdate{
+		FeatureCodes: []string{"pro_storage"},
+		UsageLimits:  json.RawMessage(`{"storage_gb": 50}`),
+	}); err != nil || !versioned {
+		t.Fatalf("UpdatePlan() = %v, %v, want a new version", versioned, err)
+	}
+
+	uc := NewCheckoutUseCase(store.Plan(), store.Entitlement(), store.PricingZone(), store.Payment(), nil, features,
+		nil, "stripe", DefaultCheckoutExpiryConfig(), nil, nil, nil, CheckoutOptions{Subscriptions: subscriptions})
+	if err := uc.ApplyWebhook(ctx, billing.WebhookResult{
+		EventType:      string(billing.WebhookEventTypePaymentSucceeded),
+		SubscriptionID: "sub_1",
+		UserID:         "user-1",
+		PlanIDString:   plan.Code,
+	}); err != nil {
+		t.Fatalf("ApplyWebhook() error = %v", err)
+	}
+
+	for code, want := range map[string]string{"pro_storage": `{"storage_gb":500}`, "priority_support": `{}`} {
+		entitlement, found, err := store.Entitlement().Check(ctx, "user-1", code)
+		if err != nil || !found {
+			t.Errorf("expected the renewal to grant %s, got found = %v, err = %v", code, found, err)
+			continue
+		}
+		if got := string(entitlement.UsageLimits); got != want {
+			t.Errorf("%s usage limits = %s, want version 1's %s", code, got, want)
+		}
+	}
+}
+
+func TestCheckoutUseCase_RenewalExtendsActiveEntitlements(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	features := newFeatureCatalog(t, store)
+	plans := NewPlanCatalogUseCase(store.Plan(), features, nil)
+	plan, err := plans.CreatePlan(ctx, catalogPlan("pro_storage"))
+	if err != nil {
+		t.Fatalf("CreatePlan() error = %v", err)
+	}
+
+	periodEnd := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	current, err := store.Entitlement().Insert(ctx, domain.Entitlement{
+		UserID:      "user-1",
+		FeatureCode: "pro_storage",
+		PlanID:      plan.ID,
+		Status:      "active",
+		GrantedAt:   time.Now(),
+		ExpiresAt:   &periodEnd,
+	})
+	if err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	uc := NewCheckoutUseCase(store.Plan(), store.Entitlement(), store.PricingZone(), store.Payment(), nil, features,
+		nil, "stripe", DefaultCheckoutExpiryConfig(), nil, nil, nil, CheckoutOptions{})
+	renew := func(expiresAt time.Time) {
+		t.Helper()
+		if err := uc.ApplyWebhook(ctx, billing.WebhookResult{
+			EventType:      string(billing.WebhookEventTypePaymentSucceeded),
+			SubscriptionID: "sub_1",
+			UserID:         "user-1",
+			PlanIDString:   plan.Code,
+			ExpiresAt:      &expiresAt,
+		}); err != nil {
+			t.Fatalf("ApplyWebhook() error = %v", err)
+		}
+	}
+
+	nextPeriodEnd := periodEnd.AddDate(0, 1, 0)
+	renew(nextPeriodEnd)
+	renewed, err := store.Entitlement().GetByID(ctx, current.ID.String())
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if renewed.ExpiresAt == nil || !renewed.ExpiresAt.Equal(nextPeriodEnd) {
+		t.Errorf("expected the renewal to extend the entitlement to %s, got %v", nextPeriodEnd, renewed.ExpiresAt)
+	}
+
+	// A late redelivery of an earlier renewal does not shorten it
+	renew(periodEnd)
+	if again, _ := store.Entitlement().GetByID(ctx, current.ID.String()); again.ExpiresAt == nil || !again.ExpiresAt.Equal(nextPeriodEnd) {
+		t.Errorf("expected the expiry to stay at %s, got %v", nextPeriodEnd, again.ExpiresAt)
+	}
+	if all, _ := store.Entitlement().ListByUser(ctx, "user-1"); len(all) != 1 {
+		t.Errorf("expected the renewal to extend rather than add entitlements, got %+v", all)
+	}
+}
@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// CustomerUseCase provides business logic for billing profiles and the
+// vault of saved payment methods. Payment methods are tokenized by the
+// billing provider; only their display details are stored here.
+type CustomerUseCase struct {
+	customerRepo    repo.CustomerRepository
+	billingProvider billing.Provider
+	providerName    string // Name recorded on customers and methods saved with billingProvider
+}
+
+// NewCustomerUseCase creates a new customer use case
+func NewCustomerUseCase(customerRepo repo.CustomerRepository, billingProvider billing.Provider, providerName string) *CustomerUseCase {
+	return &CustomerUseCase{
+		customerRepo:    customerRepo,
+		billingProvider: billingProvider,
+		providerName:    providerName,
+	}
+}
+
+// BillingProfileRequest describes the billing profile to save for a user
+type BillingProfileRequest struct {
+	UserID            string
+	Name              string
+	Email             string
+	Address           domain.Address
+	TaxID             string
+	PreferredCurrency string
+}
+
+// UpsertBillingProfile creates or updates a user's billing profile and
+// mirrors it to the billing provider's customer
+func (uc *CustomerUseCase) UpsertBillingProfile(ctx context.Context, req BillingProfileRequest) (*domain.Customer, error) {
+	userID, err := resolveUserID(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer := domain.Customer{
+		UserID:            userID,
+		Name:              req.Name,
+		Email:             req.Email,
+		Address:           req.Address,
+		TaxID:             req.TaxID,
+		PreferredCurrency: req.PreferredCurrency,
+	}
+	customer.Normalize()
+	if err := customer.Validate(); err != nil {
+		return nil, err
+	}
+
+	saved, err := uc.customerRepo.Upsert(ctx, customer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save billing profile: %w", err)
+	}
+
+	synced, err := uc.syncProviderCustomer(ctx, saved)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info(ctx, "Billing profile saved",
+		zap.String("user_id", synced.UserID),
+		zap.String("provider", synced.Provider),
+		zap.String("provider_customer_id", synced.ProviderCustomerID))
+
+	return synced, nil
+}
+
+// GetCustomer retrieves a user's billing profile. Only admins may read
+// another user's profile.
+func (uc *CustomerUseCase) GetCustomer(ctx context.Context, userID string) (*domain.Customer, error) {
+	userID, err := resolveUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.customer(ctx, userID)
+}
+
+// customer retrieves a user's billing profile without checking the caller,
+// for use on behalf of webhooks and workers
+func (uc *CustomerUseCase) customer(ctx context.Context, userID string) (*domain.Customer, error) {
+	if userID == "" {
+		return nil, domain.NewInvalidInputError("invalid customer", "user_id is required")
+	}
+
+	customer, err := uc.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// AddPaymentMethod saves a payment method tokenized by the billing
+// provider's client-side SDK. A user without a billing profile gets an empty
+// one. The first method saved, or any method added with makeDefault, becomes
+// the default.
+func (uc *CustomerUseCase) AddPaymentMethod(ctx context.Context, userID, token string, makeDefault bool) (*domain.SavedPaymentMethod, error) {
+	userID, err := resolveUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, domain.NewInvalidInputError("invalid payment method", "user_id is required")
+	}
+	if token == "" {
+		return nil, domain.NewInvalidInputError("invalid payment method", "token is required")
+	}
+
+	customer, err := uc.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get customer: %w", err)
+		}
+		if customer, err = uc.customerRepo.Upsert(ctx, domain.Customer{UserID: userID}); err != nil {
+			return nil, fmt.Errorf("failed to create customer: %w", err)
+		}
+	}
+
+	synced, err := uc.ensureProviderCustomer(ctx, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := uc.billingProvider.AttachPaymentMethod(uc.providerContext(ctx), synced.ProviderCustomerID, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to attach payment method with %s: %v", uc.providerName, err)
+	}
+
+	method, err := uc.customerRepo.AddPaymentMethod(ctx, domain.SavedPaymentMethod{
+		CustomerID:              synced.ID,
+		Provider:                uc.providerName,
+		ProviderPaymentMethodID: details.ID,
+		Type:                    domain.PaymentMethod(details.Type),
+		Brand:                   details.Brand,
+		Last4:                   details.Last4,
+		ExpMonth:                details.ExpMonth,
+		ExpYear:                 details.ExpYear,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !makeDefault {
+		if _, err := uc.customerRepo.GetDefaultPaymentMethod(ctx, synced.ID); isNotFound(err) {
+			makeDefault = true
+		}
+	}
+	if makeDefault {
+		if method, err = uc.setDefault(ctx, *synced, method); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Info(ctx, "Payment method saved",
+		zap.String("user_id", userID),
+		zap.String("payment_method_id", method.ID.String()),
+		zap.String("brand", method.Brand),
+		zap.Bool("default", method.IsDefault))
+
+	return &method, nil
+}
+
+// ListPaymentMethods lists a user's saved payment methods, default first
+func (uc *CustomerUseCase) ListPaymentMethods(ctx context.Context, userID string) ([]domain.SavedPaymentMethod, error) {
+	customer, err := uc.GetCustomer(ctx, userID)
+	if err != nil {
+		if isNotFound(err) {
+			return []domain.SavedPaymentMethod{}, nil
+		}
+		return nil, err
+	}
+
+	return uc.customerRepo.ListPaymentMethods(ctx, customer.ID)
+}
+
+// SetDefaultPaymentMethod makes one of a user's saved methods the default
+func (uc *CustomerUseCase) SetDefaultPaymentMethod(ctx context.Context, userID string, methodID uuid.UUID) (*domain.SavedPaymentMethod, error) {
+	customer, method, err := uc.ownedPaymentMethod(ctx, userID, methodID)
+	if err != nil {
+		return nil, err
+	}
+	if method.IsDefault {
+		return method, nil
+	}
+
+	updated, err := uc.setDefault(ctx, customer, *method)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DetachPaymentMethod detaches a saved method from the user at the billing
+// provider and removes it from the vault. Detaching the default leaves the
+// customer without one until another is chosen.
+func (uc *CustomerUseCase) DetachPaymentMethod(ctx context.Context, userID string, methodID uuid.UUID) error {
+	_, method, err := uc.ownedPaymentMethod(ctx, userID, methodID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.billingProvider.DetachPaymentMethod(billing.WithProviderName(ctx, method.Provider), method.ProviderPaymentMethodID); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to detach payment method with %s: %v", uc.providerName, err)
+	}
+	if err := uc.customerRepo.DeletePaymentMethod(ctx, method.ID); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "Payment method detached",
+		zap.String("user_id", userID),
+		zap.String("payment_method_id", method.ID.String()),
+		zap.Bool("was_default", method.IsDefault))
+
+	return nil
+}
+
+// DefaultPaymentMethod returns a user's billing profile and default payment
+// method, for reuse by checkout and dunning retries. It returns a not-found
+// error when the user has no usable default: none was saved, it was saved
+// with another provider, or it has expired.
+func (uc *CustomerUseCase) DefaultPaymentMethod(ctx context.Context, userID string) (*domain.Customer, *domain.SavedPaymentMethod, error) {
+	customer, err := uc.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	method, err := uc.customerRepo.GetDefaultPaymentMethod(ctx, customer.ID)
+	if err != nil {
+		return &customer, nil, err
+	}
+	if method.Provider != uc.providerName || customer.ProviderCustomerID == "" {
+		return &customer, nil, domain.NewNotFoundError("default payment method for provider", uc.providerName)
+	}
+	if method.Expired(time.Now()) {
+		return &customer, nil, domain.NewNotFoundError("unexpired default payment method", userID)
+	}
+
+	return &customer, &method, nil
+}
+
+// ownedPaymentMethod loads a saved method, reporting methods of other users as not found
+func (uc *CustomerUseCase) ownedPaymentMethod(ctx context.Context, userID string, methodID uuid.UUID) (domain.Customer, *domain.SavedPaymentMethod, error) {
+	customer, err := uc.GetCustomer(ctx, userID)
+	if err != nil {
+		return domain.Customer{}, nil, err
+	}
+
+	method, err := uc.customerRepo.GetPaymentMethod(ctx, methodID)
+	if err != nil {
+		return domain.Customer{}, nil, err
+	}
+	if method.CustomerID != customer.ID {
+		return domain.Customer{}, nil, domain.NewNotFoundError("payment method", methodID.String())
+	}
+
+	return *customer, &method, nil
+}
+
+// setDefault makes a method the customer's default at the provider and in the vault
+func (uc *CustomerUseCase) setDefault(ctx context.Context, customer domain.Customer, method domain.SavedPaymentMethod) (domain.SavedPaymentMethod, error) {
+	if err := uc.billingProvider.SetDefaultPaymentMethod(uc.providerContext(ctx), customer.ProviderCustomerID, method.ProviderPaymentMethodID); err != nil {
+		return domain.SavedPaymentMethod{}, status.Errorf(codes.Unavailable, "failed to set default payment method with %s: %v", uc.providerName, err)
+	}
+	return uc.customerRepo.SetDefaultPaymentMethod(ctx, customer.ID, method.ID)
+}
+
+// ensureProviderCustomer creates the customer at the billing provider if it
+// does not exist there yet
+func (uc *CustomerUseCase) ensureProviderCustomer(ctx context.Context, customer domain.Customer) (*domain.Customer, error) {
+	if customer.Provider == uc.providerName && customer.ProviderCustomerID != "" {
+		return &customer, nil
+	}
+	return uc.syncProviderCustomer(ctx, customer)
+}
+
+// syncProviderCustomer creates or updates the customer at the billing
+// provider and records the provider's customer ID
+func (uc *CustomerUseCase) syncProviderCustomer(ctx context.Context, customer domain.Customer) (*domain.Customer, error) {
+	providerCustomerID := ""
+	if customer.Provider == uc.providerName {
+		providerCustomerID = customer.ProviderCustomerID
+	}
+
+	savedID, err := uc.billingProvider.SaveCustomer(uc.providerContext(ctx), billing.SaveCustomerRequest{
+		CustomerID: providerCustomerID,
+		UserID:     customer.UserID,
+		Name:       customer.Name,
+		Email:      customer.Email,
+		Address: billing.CustomerAddress{
+			Line1:      customer.Address.Line1,
+			Line2:      customer.Address.Line2,
+			City:       customer.Address.City,
+			Region:     customer.Address.Region,
+			PostalCode: customer.Address.PostalCode,
+			Country:    customer.Address.Country,
+		},
+		TaxID: customer.TaxID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to save customer with %s: %v", uc.providerName, err)
+	}
+
+	if savedID == providerCustomerID {
+		return &customer, nil
+	}
+	updated, err := uc.customerRepo.SetProviderCustomerID(ctx, customer.ID, uc.providerName, savedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record provider customer: %w", err)
+	}
+	return &updated, nil
+}
+
+// providerContext names the provider customers are saved with, for billing
+// providers that route between several
+func (uc *CustomerUseCase) providerContext(ctx context.Context) context.Context {
+	return billing.WithProviderName(ctx, uc.providerName)
+}
+
+// resolveUserID returns the user a customer request acts on, falling back to
+// the authenticated user when userID is empty. Only admins may act on
+// another user.
+func resolveUserID(ctx context.Context, userID string) (string, error) {
+	owner, err := authorizeOwner(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if owner == "" {
+		owner = extractUserIDFromContext(ctx)
+	}
+	return owner, nil
+}
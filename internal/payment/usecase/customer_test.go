@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/auth"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// memoryCustomerRepo holds billing profiles in memory
+type memoryCustomerRepo struct {
+	repo.CustomerRepository
+	customers map[string]domain.Customer
+	methods   []domain.SavedPaymentMethod
+}
+
+func (r *memoryCustomerRepo) GetByUserID(ctx context.Context, userID string) (domain.Customer, error) {
+	customer, ok := r.customers[userID]
+	if !ok {
+		return domain.Customer{}, domain.NewNotFoundError("customer", userID)
+	}
+	return customer, nil
+}
+
+func (r *memoryCustomerRepo) ListPaymentMethods(ctx context.Context, customerID uuid.UUID) ([]domain.SavedPaymentMethod, error) {
+	var methods []domain.SavedPaymentMethod
+	for _, method := range r.methods {
+		if method.CustomerID == customerID {
+			methods = append(methods, method)
+		}
+	}
+	return methods, nil
+}
+
+func TestCustomerUseCase_OwnerAccess(t *testing.T) {
+	customers := &memoryCustomerRepo{customers: map[string]domain.Customer{
+		"user-1": {ID: uuid.New(), UserID: "user-1"},
+		"user-2": {ID: uuid.New(), UserID: "user-2"},
+	}}
+	customers.methods = []domain.SavedPaymentMethod{
+		{ID: uuid.New(), CustomerID: customers.customers["user-2"].ID},
+	}
+	uc := NewCustomerUseCase(customers, nil, "stripe")
+	user := log.WithUserID(context.Background(), "user-1")
+	admin := auth.WithAdmin(log.WithUserID(context.Background(), "support-1"))
+
+	if customer, err := uc.GetCustomer(user, ""); err != nil || customer.UserID != "user-1" {
+		t.Errorf("GetCustomer() of the caller = %+v, %v, want user-1", customer, err)
+	}
+	if _, err := uc.GetCustomer(user, "user-2"); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("GetCustomer() of another user error = %v, want unauthorized", err)
+	}
+	if _, err := uc.ListPaymentMethods(user, "user-2"); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("ListPaymentMethods() of another user error = %v, want unauthorized", err)
+	}
+	if err := uc.DetachPaymentMethod(user, "user-2", customers.methods[0].ID); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("DetachPaymentMethod() of another user's method error = %v, want unauthorized", err)
+	}
+	if _, err := uc.UpsertBillingProfile(user, BillingProfileRequest{UserID: "user-2", Name: "Mallory"}); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("UpsertBillingProfile() for another user error = %v, want unauthorized", err)
+	}
+	if _, err := uc.AddPaymentMethod(user, "user-2", "tok_visa", false); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("AddPaymentMethod() for another user error = %v, want unauthorized", err)
+	}
+
+	// Admins act on any user's billing profile
+	if customer, err := uc.GetCustomer(admin, "user-2"); err != nil || customer.UserID != "user-2" {
+		t.Errorf("GetCustomer() as admin = %+v, %v, want user-2", customer, err)
+	}
+	if methods, err := uc.ListPaymentMethods(admin, "user-2"); err != nil || len(methods) != 1 {
+		t.Errorf("ListPaymentMethods() as admin = %+v, %v, want one method", methods, err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jia-app/paymentservice/internal/payment/repo"
 	"github.com/jia-app/paymentservice/internal/shared/events"
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
 // DunningManager handles dunning management for failed payments
@@ -20,6 +21,7 @@ type DunningManager struct {
 	paymentRepo      repo.PaymentRepository
 	subscriptionRepo repo.SubscriptionRepository
 	eventPublisher   events.DunningPublisher
+	metrics          *metrics.MetricsCollector // Can be nil if metrics are disabled
 }
 
 // NewDunningManager creates a new dunning manager
@@ -27,11 +29,13 @@ func NewDunningManager(
 	paymentRepo repo.PaymentRepository,
 	subscriptionRepo repo.SubscriptionRepository,
 	eventPublisher events.DunningPublisher,
+	metricsCollector *metrics.MetricsCollector,
 ) *DunningManager {
 	return &DunningManager{
 		paymentRepo:      paymentRepo,
 		subscriptionRepo: subscriptionRepo,
 		eventPublisher:   eventPublisher,
+		metrics:          metricsCollector,
 	}
 }
 
@@ -413,6 +417,7 @@ func (dm *DunningManager) storeDunningEvent(ctx context.Context, event DunningEv
 		zap.String("event_id", event.ID.String()),
 		zap.String("event_type", string(event.EventType)),
 		zap.String("status", string(event.Status)))
+	dm.metrics.RecordDunningEvent(ctx, string(event.EventType))
 	return nil
 }
 
@@ -2,10 +2,16 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 	"time"
 
-	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/shared/log"
 )
 
 // Scheduler handles scheduling of dunning retry attempts
@@ -62,29 +68,104 @@ func (s *Scheduler) processScheduledRetries(ctx context.Context) {
 // RetryProcessor handles the actual retry processing
 type RetryProcessor struct {
 	dunningManager  *DunningManager
-	billingProvider interface{} // Would be billing.Provider
+	billingProvider billing.Provider
+	customerUseCase *CustomerUseCase
 }
 
 // NewRetryProcessor creates a new retry processor
-func NewRetryProcessor(dunningManager *DunningManager, billingProvider interface{}) *RetryProcessor {
+func NewRetryProcessor(dunningManager *DunningManager, billingProvider billing.Provider, customerUseCase *CustomerUseCase) *RetryProcessor {
 	return &RetryProcessor{
 		dunningManager:  dunningManager,
 		billingProvider: billingProvider,
+		customerUseCase: customerUseCase,
 	}
 }
 
-// ProcessRetry processes a retry attempt for a dunning event
+// ProcessRetry processes a retry attempt for a dunning event by charging the
+// customer's default saved payment method off-session
 func (rp *RetryProcessor) ProcessRetry(ctx context.Context, dunningEventID string) error {
 	log.L(ctx).Info("Processing retry attempt",
 		zap.String("dunning_event_id", dunningEventID))
 
-	// This would typically:
-	// 1. Get the dunning event
-	// 2. Attempt to retry the payment
-	// 3. Process the result
-	// 4. Update the dunning event status
+	eventID, err := uuid.Parse(dunningEventID)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid dunning event ID: %s", dunningEventID)
+	}
 
-	return nil
+	dunningEvent, err := rp.dunningManager.getDunningEvent(ctx, eventID)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get dunning event: %v", err)
+	}
+	if dunningEvent == nil {
+		return status.Errorf(codes.NotFound, "dunning event not found: %s", dunningEventID)
+	}
+
+	if err := rp.dunningManager.ProcessRetryAttempt(ctx, ProcessRetryAttemptRequest{DunningEventID: eventID}); err != nil {
+		return err
+	}
+
+	failureReason, err := rp.chargeDefaultPaymentMethod(ctx, dunningEvent)
+	if err != nil {
+		return err
+	}
+
+	return rp.dunningManager.ProcessRetryResult(ctx, ProcessRetryResultRequest{
+		DunningEventID: eventID,
+		Success:        failureReason == "",
+		FailureReason:  failureReason,
+	})
+}
+
+// chargeDefaultPaymentMethod charges the failed amount to the user's default
+// payment method, returning why the charge failed or "" on success. The
+// idempotency key is derived from the attempt so that a retried call never
+// charges twice. Only provider outages are returned as errors.
+func (rp *RetryProcessor) chargeDefaultPaymentMethod(ctx context.Context, dunningEvent *DunningEvent) (string, error) {
+	if rp.customerUseCase == nil || rp.billingProvider == nil {
+		return "no saved payment methods", nil
+	}
+
+	customer, method, err := rp.customerUseCase.DefaultPaymentMethod(ctx, dunningEvent.UserID)
+	if err != nil {
+		if isNotFound(err) {
+			return "no usable default payment method", nil
+		}
+		return "", status.Errorf(codes.Internal, "failed to get default payment method: %v", err)
+	}
+
+	result, err := rp.billingProvider.ChargePaymentMethod(billing.WithProviderName(ctx, method.Provider), billing.ChargeRequest{
+		CustomerID:      customer.ProviderCustomerID,
+		PaymentMethodID: method.ProviderPaymentMethodID,
+		Amount:          dunningEvent.Amount,
+		Currency:        dunningEvent.Currency,
+		Description:     fmt.Sprintf("Retry of payment %s", dunningEvent.PaymentID),
+		IdempotencyKey:  fmt.Sprintf("dunning-%s-%d", dunningEvent.ID, dunningEvent.RetryCount+1),
+		Metadata: map[string]string{
+			"payment_id":       dunningEvent.PaymentID,
+			"dunning_event_id": dunningEvent.ID.String(),
+		},
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "failed to charge payment method: %v", err)
+	}
+
+	log.Info(ctx, "Charged default payment method for retry",
+		zap.String("dunning_event_id", dunningEvent.ID.String()),
+		zap.String("payment_method_id", method.ID.String()),
+		zap.String("charge_id", result.ID),
+		zap.String("status", string(result.Status)))
+
+	switch result.Status {
+	case billing.ChargeStatusSucceeded:
+		return "", nil
+	case billing.ChargeStatusRequiresAction:
+		return "payment requires customer authentication", nil
+	default:
+		if result.FailureReason == "" {
+			return "charge declined", nil
+		}
+		return result.FailureReason, nil
+	}
 }
 
 // EscalationManager handles escalation of failed payments
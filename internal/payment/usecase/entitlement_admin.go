@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// GrantEntitlementRequest describes an entitlement granted by support
+type GrantEntitlementRequest struct {
+	UserID        string
+	FeatureCode   string
+	PlanID        uuid.UUID
+	ExpiresAt     *time.Time // Nil for a grant that does not expire
+	Complimentary bool       // Granted without payment, e.g. a comp account
+	Reason        string     // Required; recorded in the audit log
+}
+
+// GrantEntitlement grants a user a feature outside of checkout
+func (uc *EntitlementUseCase) GrantEntitlement(ctx context.Context, req GrantEntitlementRequest) (*domain.Entitlement, error) {
+	switch {
+	case req.UserID == "":
+		return nil, domain.NewInvalidInputError("invalid entitlement grant", "user_id is required")
+	case req.FeatureCode == "":
+		return nil, domain.NewInvalidInputError("invalid entitlement grant", "feature_code is required")
+	case req.PlanID == uuid.Nil:
+		return nil, domain.NewInvalidInputError("invalid entitlement grant", "plan_id is required")
+	case strings.TrimSpace(req.Reason) == "":
+		return nil, domain.NewInvalidInputError("invalid entitlement grant", "reason is required")
+	case req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()):
+		return nil, domain.NewInvalidInputError("invalid entitlement grant", "expires_at must be in the future")
+	}
+
+	// Grants go through the feature catalog like checkout's: deprecated
+	// features can no longer be granted
+	var usageLimits json.RawMessage
+	if uc.features != nil {
+		feature, err := uc.features.Resolve(ctx, req.FeatureCode)
+		if err != nil {
+			if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeNotFound {
+				return nil, domain.NewInvalidInputError("invalid entitlement grant", fmt.Sprintf("unknown feature_code: %s", req.FeatureCode))
+			}
+			return nil, err
+		}
+		if feature.Deprecated {
+			return nil, domain.NewInvalidInputError("invalid entitlement grant", fmt.Sprintf("feature %s is deprecated", req.FeatureCode))
+		}
+		usageLimits = feature.Limits(nil)
+	}
+
+	// Nothing was paid through us for a grant, even one that is not a comp
+	source := domain.EntitlementSourceManual
+	if req.Complimentary {
+		source = domain.EntitlementSourceComplimentary
+	}
+
+	granted, err := uc.entitlementRepo.Insert(ctx, domain.Entitlement{
+		UserID:      req.UserID,
+		FeatureCode: req.FeatureCode,
+		PlanID:      req.PlanID,
+		Status:      "active",
+		GrantedAt:   time.Now(),
+		ExpiresAt:   req.ExpiresAt,
+		UsageLimits: usageLimits,
+		Source:      source,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant entitlement: %w", err)
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityEntitlement,
+		EntityID:   granted.ID.String(),
+		Action:     domain.AuditActionGrant,
+		After:      granted,
+		Reason:     req.Reason,
+	})
+	uc.entitlementChanged(ctx, granted, events.EntitlementChangeGranted)
+	log.Info(ctx, "Entitlement granted",
+		zap.String("entitlement_id", granted.ID.String()),
+		zap.String("user_id", granted.UserID),
+		zap.String("feature_code", granted.FeatureCode),
+		zap.String("source", granted.Source))
+
+	return &granted, nil
+}
+
+// RevokeEntitlement revokes an active entitlement
+func (uc *EntitlementUseCase) RevokeEntitlement(ctx context.Context, id, reason string) (*domain.Entitlement, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, domain.NewInvalidInputError("invalid entitlement revoke", "reason is required")
+	}
+
+	current, err := uc.entitlementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == "revoked" {
+		return nil, domain.NewInvalidStateError("entitlement is already revoked", fmt.Sprintf("entitlement_id: %s", id))
+	}
+
+	revoke := current
+	revoke.Status = "revoked"
+	revoked, err := uc.entitlementRepo.Update(ctx, revoke)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke entitlement: %w", err)
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityEntitlement,
+		EntityID:   revoked.ID.String(),
+		Action:     domain.AuditActionRevoke,
+		Before:     current,
+		After:      revoked,
+		Reason:     reason,
+	})
+	uc.entitlementChanged(ctx, revoked, events.EntitlementChangeRevoked)
+	log.Info(ctx, "Entitlement revoked",
+		zap.String("entitlement_id", revoked.ID.String()),
+		zap.String("user_id", revoked.UserID),
+		zap.String("feature_code", revoked.FeatureCode))
+
+	return &revoked, nil
+}
+
+// ExtendEntitlement moves an entitlement's expiry later. Expired entitlements
+// can be extended; revoked ones cannot.
+func (uc *EntitlementUseCase) ExtendEntitlement(ctx context.Context, id string, expiresAt time.Time, reason string) (*domain.Entitlement, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, domain.NewInvalidInputError("invalid entitlement extension", "reason is required")
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, domain.NewInvalidInputError("invalid entitlement extension", "expires_at must be in the future")
+	}
+
+	current, err := uc.entitlementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == "revoked" {
+		return nil, domain.NewInvalidStateError("entitlement is revoked", fmt.Sprintf("entitlement_id: %s", id))
+	}
+	if current.ExpiresAt == nil {
+		return nil, domain.NewInvalidStateError("entitlement does not expire", fmt.Sprintf("entitlement_id: %s", id))
+	}
+	if !expiresAt.After(*current.ExpiresAt) {
+		return nil, domain.NewInvalidInputError("invalid entitlement extension",
+			fmt.Sprintf("expires_at must be after the current expiry %s", current.ExpiresAt.Format(time.RFC3339)))
+	}
+
+	extend := current
+	extend.ExpiresAt = &expiresAt
+	extended, err := uc.entitlementRepo.Update(ctx, extend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extend entitlement: %w", err)
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityEntitlement,
+		EntityID:   extended.ID.String(),
+		Action:     domain.AuditActionExtend,
+		Before:     current,
+		After:      extended,
+		Reason:     reason,
+	})
+	uc.entitlementChanged(ctx, extended, events.EntitlementChangeUpdated)
+	log.Info(ctx, "Entitlement extended",
+		zap.String("entitlement_id", extended.ID.String()),
+		zap.String("user_id", extended.UserID),
+		zap.String("feature_code", extended.FeatureCode),
+		zap.Time("expires_at", expiresAt))
+
+	return &extended, nil
+}
+
+// entitlementChanged evicts a changed entitlement from the cache on every
+// replica and publishes the change. The change has been saved, so failures
+// are logged rather than returned.
+func (uc *EntitlementUseCase) entitlementChanged(ctx context.Context, e domain.Entitlement, action string) {
+	if uc.cache != nil {
+		if err := uc.cache.DeleteEntitlement(ctx, e.UserID, e.FeatureCode); err != nil {
+			log.Warn(ctx, "Failed to evict entitlement from cache",
+				zap.Error(err), zap.String("user_id", e.UserID), zap.String("feature_code", e.FeatureCode))
+		}
+	}
+	if uc.entitlementPublisher != nil {
+		if err := uc.entitlementPublisher.PublishEntitlementUpdated(ctx, e, action); err != nil {
+			log.Error(ctx, "Failed to publish entitlement.updated event", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+)
+
+// errorCode returns the domain error code of err, or "" if it has none
+func errorCode(err error) string {
+	if domainErr := domain.GetDomainError(err); domainErr != nil {
+		return domainErr.Code
+	}
+	return ""
+}
+
+func TestEntitlementUseCase_AdminEntitlements(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	plan, err := store.Plan().Create(ctx, domain.Plan{Code: "family_monthly", Name: "Family", Currency: "USD", Active: true})
+	if err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+
+	hub := events.NewEntitlementHub()
+	watch := hub.Watch("user-1")
+	defer watch.Close()
+	auditEvents := &memoryAuditRepo{}
+	uc := NewEntitlementUseCase(store.Entitlement(), nil, nil, hub, hub, audit.NewRecorder(auditEvents), nil)
+
+	nextChange := func() events.EntitlementChange {
+		t.Helper()
+		select {
+		case change := <-watch.Changes():
+			return change
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for an entitlement change")
+			return events.EntitlementChange{}
+		}
+	}
+
+	expiresAt := time.Now().Add(24 * time.Hour)
+	granted, err := uc.GrantEntitlement(ctx, GrantEntitlementRequest{
+		UserID:        "user-1",
+		FeatureCode:   "storage",
+		PlanID:        plan.ID,
+		ExpiresAt:     &expiresAt,
+		Complimentary: true,
+		Reason:        "press account",
+	})
+	if err != nil {
+		t.Fatalf("GrantEntitlement() error = %v", err)
+	}
+	if granted.Source != domain.EntitlementSourceComplimentary || granted.Status != "active" {
+		t.Errorf("expected an active complimentary entitlement, got %+v", granted)
+	}
+	if change := nextChange(); change.Type != events.EntitlementChangeGranted || change.Entitlement.ID != granted.ID {
+		t.Errorf("expected the grant of %s, got %+v", granted.ID, change)
+	}
+	if len(auditEvents.events) != 1 || auditEvents.events[0].Action != domain.AuditActionGrant || auditEvents.events[0].Reason != "press account" {
+		t.Fatalf("expected a grant audit event with its reason, got %+v", auditEvents.events)
+	}
+
+	// Stores keep expiries to the microsecond
+	later := expiresAt.Add(30 * 24 * time.Hour).Truncate(time.Microsecond)
+	extended, err := uc.ExtendEntitlement(ctx, granted.ID.String(), later, "goodwill")
+	if err != nil {
+		t.Fatalf("ExtendEntitlement() error = %v", err)
+	}
+	if !extended.ExpiresAt.Equal(later) || extended.Source != domain.EntitlementSourceComplimentary {
+		t.Errorf("expected the comp to expire at %s, got %+v", later, extended)
+	}
+	if change := nextChange(); change.Type != events.EntitlementChangeUpdated || !change.Entitlement.ExpiresAt.Equal(later) {
+		t.Errorf("expected the extension to be published, got %+v", change)
+	}
+	if _, err := uc.ExtendEntitlement(ctx, granted.ID.String(), expiresAt, "goodwill"); errorCode(err) != domain.ErrCodeInvalidInput {
+		t.Errorf("expected InvalidInput for an earlier expiry, got %v", err)
+	}
+
+	revoked, err := uc.RevokeEntitlement(ctx, granted.ID.String(), "press tour over")
+	if err != nil {
+		t.Fatalf("RevokeEntitlement() error = %v", err)
+	}
+	if revoked.Status != "revoked" {
+		t.Errorf("expected the entitlement to be revoked, got %q", revoked.Status)
+	}
+	if change := nextChange(); change.Type != events.EntitlementChangeRevoked || change.Entitlement.ID != granted.ID {
+		t.Errorf("expected the revoke of %s, got %+v", granted.ID, change)
+	}
+	if last := auditEvents.events[0]; last.Action != domain.AuditActionRevoke || last.Reason != "press tour over" {
+		t.Errorf("expected a revoke audit event with its reason, got %+v", last)
+	}
+
+	if _, err := uc.RevokeEntitlement(ctx, granted.ID.String(), "again"); errorCode(err) != domain.ErrCodeInvalidState {
+		t.Errorf("expected InvalidState revoking twice, got %v", err)
+	}
+	if _, err := uc.ExtendEntitlement(ctx, granted.ID.String(), later.Add(time.Hour), "goodwill"); errorCode(err) != domain.ErrCodeInvalidState {
+		t.Errorf("expected InvalidState extending a revoked entitlement, got %v", err)
+	}
+	if len(auditEvents.events) != 3 {
+		t.Errorf("expected 3 audit events, got %d", len(auditEvents.events))
+	}
+}
+
+func TestEntitlementUseCase_AdminEntitlementsValidation(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	plan, err := store.Plan().Create(ctx, domain.Plan{Code: "family_monthly", Name: "Family", Currency: "USD", Active: true})
+	if err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+	uc := NewEntitlementUseCase(store.Entitlement(), nil, nil, nil, nil, nil, nil)
+
+	past := time.Now().Add(-time.Hour)
+	valid := GrantEntitlementRequest{UserID: "user-1", FeatureCode: "storage", PlanID: plan.ID, Reason: "support ticket 42"}
+
+	grants := map[string]func(*GrantEntitlementRequest){
+		"missing user":    func(r *GrantEntitlementRequest) { r.UserID = "" },
+		"missing feature": func(r *GrantEntitlementRequest) { r.FeatureCode = "" },
+		"missing plan":    func(r *GrantEntitlementRequest) { r.PlanID = uuid.Nil },
+		"missing reason":  func(r *GrantEntitlementRequest) { r.Reason = "  " },
+		"past expiry":     func(r *GrantEntitlementRequest) { r.ExpiresAt = &past },
+	}
+	for name, mutate := range grants {
+		t.Run(name, func(t *testing.T) {
+			req := valid
+			mutate(&req)
+			if _, err := uc.GrantEntitlement(ctx, req); errorCode(err) != domain.ErrCodeInvalidInput {
+				t.Errorf("expected InvalidInput, got %v", err)
+			}
+		})
+	}
+
+	granted, err := uc.GrantEntitlement(ctx, valid)
+	if err != nil {
+		t.Fatalf("GrantEntitlement() error = %v", err)
+	}
+	if granted.Source != domain.EntitlementSourceManual {
+		t.Errorf("expected a manual grant by default, got %q", granted.Source)
+	}
+
+	if _, err := uc.RevokeEntitlement(ctx, granted.ID.String(), ""); errorCode(err) != domain.ErrCodeInvalidInput {
+		t.Errorf("expected InvalidInput revoking without a reason, got %v", err)
+	}
+	if _, err := uc.RevokeEntitlement(ctx, uuid.NewString(), "support ticket 42"); errorCode(err) != domain.ErrCodeNotFound {
+		t.Errorf("expected NotFound revoking a missing entitlement, got %v", err)
+	}
+	if _, err := uc.ExtendEntitlement(ctx, granted.ID.String(), time.Now().Add(time.Hour), "support ticket 42"); errorCode(err) != domain.ErrCodeInvalidState {
+		t.Errorf("expected InvalidState extending an entitlement that never expires, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/cache"
+)
+
+// Rules that decide an entitlement check, in the order they are applied
+const (
+	EntitlementRuleUnknownFeature    = "unknown_feature"     // The feature is not in the feature catalog
+	EntitlementRuleCachedEntitlement = "cached_entitlement"  // Redis holds a valid entitlement
+	EntitlementRuleNegativeCache     = "negative_cache"      // Redis holds a negative result
+	EntitlementRuleActiveEntitlement = "active_entitlement"  // The user has a valid entitlement
+	EntitlementRuleRevoked           = "revoked"             // The user's latest entitlement was revoked
+	EntitlementRuleExpired           = "expired"             // The user's latest entitlement has expired
+	EntitlementRuleInactive          = "inactive"            // The user's latest entitlement has another status
+	EntitlementRuleFamilyEntitlement = "family_entitlement"  // Only another family member is entitled
+	EntitlementRuleNoPlanForFeature  = "no_plan_for_feature" // No catalog plan grants the feature
+	EntitlementRuleNoEntitlement     = "no_entitlement"      // The user was never granted the feature
+)
+
+// explainFamilyLimit bounds the family entitlements listed in an explanation
+const explainFamilyLimit = 50
+
+// EntitlementExplanation is the decision trace of an entitlement check
+type EntitlementExplanation struct {
+	Allowed          bool   // What CheckEntitlement returns
+	Rule             string // One of the EntitlementRule constants
+	Reason           string
+	Candidates       []EntitlementCandidate // The user's entitlements to the feature, then the family's
+	Cache            EntitlementCacheState
+	PlansWithFeature []string        // Codes of catalog plans granting the feature, archived ones included
+	Feature          *domain.Feature // Catalog entry; nil if unknown or the catalog is not checked
+}
+
+// EntitlementCandidate is an entitlement considered by an entitlement check
+type EntitlementCandidate struct {
+	Entitlement        domain.Entitlement
+	Valid              bool   // Whether CheckEntitlement honours it
+	Reason             string // Why it is not honoured, e.g. "was revoked"; empty when valid
+	PlanCode           string // Empty if the plan is not in the catalog
+	PlanGrantsFeature  bool   // Whether the plan still includes the feature
+	SubscriptionStatus string // Empty if there is no subscription or it is not known
+
+	rule string // Rule deciding the check when this is the user's latest entitlement
+}
+
+// EntitlementCacheState is what Redis holds for an entitlement check
+type EntitlementCacheState struct {
+	Enabled     bool
+	NotFound    bool                // A negative result is cached
+	Entitlement *domain.Entitlement // Cached entitlement, if any
+	Error       string              // Set when Redis could not be read; checks then skip the cache
+}
+
+// EntitlementExplainUseCase explains entitlement checks to support
+type EntitlementExplainUseCase struct {
+	entitlementRepo    repo.EntitlementRepository
+	subscriptionRepo   repo.SubscriptionRepository // Can be nil if subscriptions are not tracked
+	planFeatureService *PlanFeatureService
+	features           *FeatureCatalogUseCase // Can be nil if feature codes are not checked
+	cache              *cache.Cache           // Can be nil if Redis is not available
+}
+
+// NewEntitlementExplainUseCase creates a new entitlement explain use case
+func NewEntitlementExplainUseCase(
+	entitlementRepo repo.EntitlementRepository,
+	subscriptionRepo repo.SubscriptionRepository,
+	planRepo repo.PlanRepository,
+	features *FeatureCatalogUseCase,
+	cache *cache.Cache,
+) *EntitlementExplainUseCase {
+	return &EntitlementExplainUseCase{
+		entitlementRepo:    entitlementRepo,
+		subscriptionRepo:   subscriptionRepo,
+		planFeatureService: NewPlanFeatureService(planRepo, nil),
+		features:           features,
+		cache:              cache,
+	}
+}
+
+// ExplainEntitlement traces why CheckEntitlement allows or denies a user a
+// feature. Entitlements of familyID, if set, are listed too: they are not
+// honoured for the user but explain "my family paid" reports. The local cache
+// tier is not inspected; it follows Redis within redis.local_cache_ttl_seconds.
+func (uc *EntitlementExplainUseCase) ExplainEntitlement(ctx context.Context, userID, featureCode, familyID string) (*EntitlementExplanation, error) {
+	if userID == "" {
+		return nil, domain.NewInvalidInputError("invalid entitlement explain request", "user_id is required")
+	}
+	if featureCode == "" {
+		return nil, domain.NewInvalidInputError("invalid entitlement explain request", "feature_code is required")
+	}
+
+	now := time.Now()
+	plans, err := uc.planFeatureService.CatalogPlans(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &EntitlementExplanation{Cache: uc.cacheState(ctx, userID, featureCode)}
+	unknownFeature := false
+	if uc.features != nil {
+		feature, err := uc.features.Resolve(ctx, featureCode)
+		if err == nil {
+			explanation.Feature = &feature
+		} else if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeNotFound {
+			unknownFeature = true
+		} else {
+			return nil, err
+		}
+	}
+	for _, plan := range plans {
+		if slices.Contains(plan.FeatureCodes, featureCode) {
+			explanation.PlansWithFeature = append(explanation.PlansWithFeature, plan.Code)
+		}
+	}
+	slices.Sort(explanation.PlansWithFeature)
+
+	owned, err := uc.entitlementRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user entitlements: %w", err)
+	}
+	for _, ent := range owned {
+		if ent.FeatureCode != featureCode {
+			continue
+		}
+		candidate, err := uc.candidate(ctx, ent, plans, now)
+		if err != nil {
+			return nil, err
+		}
+		explanation.Candidates = append(explanation.Candidates, candidate)
+	}
+
+	if familyID != "" {
+		shared, err := uc.entitlementRepo.ListAfter(ctx, domain.EntitlementFilter{FamilyID: familyID, FeatureCode: featureCode}, nil, explainFamilyLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list family entitlements: %w", err)
+		}
+		for _, ent := range shared {
+			if ent.UserID == userID {
+				continue
+			}
+			candidate, err := uc.candidate(ctx, ent, plans, now)
+			if err != nil {
+				return nil, err
+			}
+			candidate.Valid, candidate.rule = false, EntitlementRuleFamilyEntitlement
+			candidate.Reason = fmt.Sprintf("was granted to user %s; only the user's own entitlements are checked", ent.UserID)
+			explanation.Candidates = append(explanation.Candidates, candidate)
+		}
+	}
+
+	explanation.decide(userID, unknownFeature, now)
+	return explanation, nil
+}
+
+// candidate explains whether CheckEntitlement honours one of the user's entitlements
+func (uc *EntitlementExplainUseCase) candidate(ctx context.Context, ent domain.Entitlement, plans map[uuid.UUID]domain.Plan, now time.Time) (EntitlementCandidate, error) {
+	candidate := EntitlementCandidate{Entitlement: ent, Valid: true, rule: EntitlementRuleActiveEntitlement}
+	switch {
+	case ent.Status == "revoked":
+		candidate.Valid, candidate.rule, candidate.Reason = false, EntitlementRuleRevoked, "was revoked"
+	case ent.Status == "expired":
+		candidate.Valid, candidate.rule, candidate.Reason = false, EntitlementRuleExpired, "expired"
+	case ent.Status != "active":
+		candidate.Valid, candidate.rule, candidate.Reason = false, EntitlementRuleInactive, fmt.Sprintf("has status %s", ent.Status)
+	case ent.ExpiresAt != nil && !ent.ExpiresAt.After(now):
+		candidate.Valid, candidate.rule = false, EntitlementRuleExpired
+		candidate.Reason = fmt.Sprintf("expired at %s", ent.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if plan, ok := plans[ent.PlanID]; ok {
+		candidate.PlanCode = plan.Code
+		candidate.PlanGrantsFeature = slices.Contains(plan.FeatureCodes, ent.FeatureCode)
+	}
+
+	if ent.SubscriptionID != nil && *ent.SubscriptionID != "" && uc.subscriptionRepo != nil {
+		sub, err := uc.subscriptionRepo.GetByExternalID(ctx, *ent.SubscriptionID)
+		if err == nil {
+			candidate.SubscriptionStatus = sub.Status
+		} else if domainErr := domain.GetDomainError(err); domainErr == nil || domainErr.Code != domain.ErrCodeNotFound {
+			return EntitlementCandidate{}, fmt.Errorf("failed to get subscription %s: %w", *ent.SubscriptionID, err)
+		}
+	}
+
+	return candidate, nil
+}
+
+// cacheState reads what Redis holds for the check without filling it
+func (uc *EntitlementExplainUseCase) cacheState(ctx context.Context, userID, featureCode string) EntitlementCacheState {
+	if uc.cache == nil {
+		return EntitlementCacheState{}
+	}
+
+	state := EntitlementCacheState{Enabled: true}
+	notFound, err := uc.cache.IsEntitlementNotFound(ctx, userID, featureCode)
+	if err != nil {
+		state.Error = err.Error()
+		return state
+	}
+	if notFound {
+		state.NotFound = true
+		return state
+	}
+
+	ent, found, err := uc.cache.GetEntitlement(ctx, userID, featureCode)
+	if err != nil {
+		state.Error = err.Error()
+	} else if found {
+		state.Entitlement = ent
+	}
+	return state
+}
+
+// decide applies the rules of CheckEntitlement: the feature catalog, Redis,
+// then the user's own entitlements. The remaining rules explain a denial.
+func (e *EntitlementExplanation) decide(userID string, unknownFeature bool, now time.Time) {
+	// The user's own candidates come first, newest first
+	var valid, latest, family *EntitlementCandidate
+	for i := range e.Candidates {
+		candidate := &e.Candidates[i]
+		switch {
+		case candidate.Entitlement.UserID != userID:
+			if family == nil {
+				family = candidate
+			}
+		case candidate.Valid:
+			if valid == nil {
+				valid = candidate
+			}
+		case latest == nil:
+			latest = candidate
+		}
+	}
+
+	cached := e.Cache.Entitlement
+	switch {
+	case unknownFeature:
+		e.Rule = EntitlementRuleUnknownFeature
+		e.Reason = "the feature is not in the feature catalog, so checks for it are rejected"
+	case cached != nil && cached.Status == "active" && (cached.ExpiresAt == nil || cached.ExpiresAt.After(now)):
+		e.Allowed, e.Rule = true, EntitlementRuleCachedEntitlement
+		e.Reason = "Redis holds a valid entitlement"
+		if valid == nil {
+			e.Reason += "; it is stale, as the user has no valid entitlement, and is served until it is evicted or expires"
+		}
+	case e.Cache.NotFound:
+		e.Rule = EntitlementRuleNegativeCache
+		e.Reason = "Redis holds a negative result"
+		if valid != nil {
+			e.Reason += fmt.Sprintf("; it is stale, as entitlement %s is valid, and expires within 10 seconds", valid.Entitlement.ID)
+		}
+	case valid != nil:
+		e.Allowed, e.Rule = true, valid.rule
+		e.Reason = fmt.Sprintf("entitlement %s is valid", valid.Entitlement.ID)
+	case latest != nil:
+		e.Rule = latest.rule
+		e.Reason = fmt.Sprintf("entitlement %s %s", latest.Entitlement.ID, latest.Reason)
+	case family != nil:
+		e.Rule = family.rule
+		e.Reason = fmt.Sprintf("entitlement %s %s", family.Entitlement.ID, family.Reason)
+	case len(e.PlansWithFeature) == 0:
+		e.Rule = EntitlementRuleNoPlanForFeature
+		e.Reason = "no catalog plan grants the feature"
+	default:
+		e.Rule = EntitlementRuleNoEntitlement
+		e.Reason = "the user was never granted the feature"
+	}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/shared/cache"
+	"github.com/jia-app/paymentservice/internal/shared/cache/redistest"
+)
+
+func TestEntitlementExplainUseCase_ExplainEntitlement(t *testing.T) {
+	ctx := context.Background()
+	family := "family-1"
+	past := time.Now().Add(-time.Hour)
+	subscriptionID := "sub_123"
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan)
+		noPlan  bool
+		allowed bool
+		rule    string
+		reason  string
+	}{
+		{
+			name:   "no plan grants the feature",
+			noPlan: true,
+			rule:   EntitlementRuleNoPlanForFeature,
+		},
+		{
+			name: "never granted",
+			rule: EntitlementRuleNoEntitlement,
+		},
+		{
+			name: "valid entitlement",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "active"})
+			},
+			allowed: true,
+			rule:    EntitlementRuleActiveEntitlement,
+		},
+		{
+			name: "latest entitlement revoked",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "expired", ExpiresAt: &past})
+				if _, err := store.Subscription().Create(ctx, domain.Subscription{
+					UserID: "user-1", PlanID: plan.ID, Status: domain.SubscriptionStatusCancelled, ExternalSubscriptionID: subscriptionID,
+				}); err != nil {
+					t.Fatalf("failed to create subscription: %v", err)
+				}
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "revoked", SubscriptionID: &subscriptionID})
+			},
+			rule:   EntitlementRuleRevoked,
+			reason: "was revoked",
+		},
+		{
+			name: "expired",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "active", ExpiresAt: &past})
+			},
+			rule:   EntitlementRuleExpired,
+			reason: "expired at",
+		},
+		{
+			name: "only another family member is entitled",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-2", FamilyID: &family, PlanID: plan.ID, Status: "active"})
+			},
+			rule:   EntitlementRuleFamilyEntitlement,
+			reason: "was granted to user user-2",
+		},
+		{
+			name: "stale negative cache",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				if err := c.SetEntitlementNotFound(ctx, "user-1", "storage"); err != nil {
+					t.Fatalf("SetEntitlementNotFound() error = %v", err)
+				}
+				insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "active"})
+			},
+			rule:   EntitlementRuleNegativeCache,
+			reason: "it is stale",
+		},
+		{
+			name: "stale cached entitlement",
+			setup: func(t *testing.T, store *memory.Store, c *cache.Cache, plan domain.Plan) {
+				ent := insertExplainEntitlement(t, store, domain.Entitlement{UserID: "user-1", PlanID: plan.ID, Status: "active"})
+				if err := c.SetEntitlement(ctx, ent, time.Minute); err != nil {
+					t.Fatalf("SetEntitlement() error = %v", err)
+				}
+				ent.Status = "revoked"
+				if _, err := store.Entitlement().Update(ctx, ent); err != nil {
+					t.Fatalf("failed to revoke entitlement: %v", err)
+				}
+			},
+			allowed: true,
+			rule:    EntitlementRuleCachedEntitlement,
+			reason:  "it is stale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := redistest.NewServer(redistest.Config{})
+			defer srv.Close()
+			client := redis.NewClient(&redis.Options{Addr: srv.Addr})
+			defer client.Close()
+			c := cache.NewTieredCache(client, cache.LocalConfig{}, nil)
+
+			store := memory.NewStore()
+			features := []string{"storage"}
+			if tt.noPlan {
+				features = []string{"sharing"}
+			}
+			plan, err := store.Plan().Create(ctx, domain.Plan{Code: "family_monthly", Name: "Family", Currency: "USD", FeatureCodes: features, Active: true})
+			if err != nil {
+				t.Fatalf("failed to create plan: %v", err)
+			}
+			if tt.setup != nil {
+				tt.setup(t, store, c, plan)
+			}
+
+			uc := NewEntitlementExplainUseCase(store.Entitlement(), store.Subscription(), store.Plan(), nil, c)
+			explanation, err := uc.ExplainEntitlement(ctx, "user-1", "storage", family)
+			if err != nil {
+				t.Fatalf("ExplainEntitlement() error = %v", err)
+			}
+			if explanation.Allowed != tt.allowed || explanation.Rule != tt.rule {
+				t.Errorf("got allowed %v by %q, want %v by %q (%s)", explanation.Allowed, explanation.Rule, tt.allowed, tt.rule, explanation.Reason)
+			}
+			if !strings.Contains(explanation.Reason, tt.reason) {
+				t.Errorf("expected the reason to mention %q, got %q", tt.reason, explanation.Reason)
+			}
+
+			// The trace reports what the check was decided from
+			if !explanation.Cache.Enabled || explanation.Cache.Error != "" {
+				t.Errorf("expected a readable cache, got %+v", explanation.Cache)
+			}
+			if tt.rule == EntitlementRuleNegativeCache && !explanation.Cache.NotFound {
+				t.Error("expected the negative result to be reported")
+			}
+			if got := len(explanation.PlansWithFeature) == 1; got == tt.noPlan {
+				t.Errorf("unexpected plans with the feature: %v", explanation.PlansWithFeature)
+			}
+			for _, candidate := range explanation.Candidates {
+				if candidate.PlanCode != plan.Code || candidate.PlanGrantsFeature == tt.noPlan {
+					t.Errorf("expected the candidate's plan mapping, got %q granting %v", candidate.PlanCode, candidate.PlanGrantsFeature)
+				}
+				if candidate.Entitlement.SubscriptionID != nil && candidate.SubscriptionStatus != domain.SubscriptionStatusCancelled {
+					t.Errorf("expected the subscription status, got %q", candidate.SubscriptionStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestEntitlementExplainUseCase_ExplainEntitlementValidation(t *testing.T) {
+	store := memory.NewStore()
+	uc := NewEntitlementExplainUseCase(store.Entitlement(), nil, store.Plan(), nil, nil)
+
+	if _, err := uc.ExplainEntitlement(context.Background(), "", "storage", ""); errorCode(err) != domain.ErrCodeInvalidInput {
+		t.Errorf("expected InvalidInput without a user, got %v", err)
+	}
+	if _, err := uc.ExplainEntitlement(context.Background(), "user-1", "", ""); errorCode(err) != domain.ErrCodeInvalidInput {
+		t.Errorf("expected InvalidInput without a feature, got %v", err)
+	}
+
+	explanation, err := uc.ExplainEntitlement(context.Background(), "user-1", "storage", "")
+	if err != nil {
+		t.Fatalf("ExplainEntitlement() error = %v", err)
+	}
+	if explanation.Cache.Enabled {
+		t.Error("expected the cache to be reported as disabled")
+	}
+}
+
+func insertExplainEntitlement(t *testing.T, store *memory.Store, ent domain.Entitlement) domain.Entitlement {
+	t.Helper()
+	ent.FeatureCode = "storage"
+	ent.GrantedAt = time.Now()
+	saved, err := store.Entitlement().Insert(context.Background(), ent)
+	if err != nil {
+		t.Fatalf("failed to insert entitlement: %v", err)
+	}
+	return saved
+}
@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/auth"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+)
+
+// EntitlementWatchEvent is one message of an entitlement watch. The first
+// event carries the snapshot and every later one a single change.
+type EntitlementWatchEvent struct {
+	Snapshot []*domain.Entitlement
+	Change   *events.EntitlementChange
+}
+
+// WatchEntitlements calls fn with the user's current entitlements, including
+// those shared with their family, and then with every grant, revoke or expiry
+// affecting them. It returns when ctx is cancelled or fn returns an error. A
+// watcher that falls behind is dropped with codes.Aborted and should
+// reconnect for a fresh snapshot. Only admins may watch another user, or a
+// family the user has no entitlement with.
+func (uc *EntitlementUseCase) WatchEntitlements(ctx context.Context, userID, familyID string, fn func(EntitlementWatchEvent) error) error {
+	// Use authenticated user_id from context if userID is empty
+	caller := extractUserIDFromContext(ctx)
+	if userID == "" {
+		if caller == "" {
+			return status.Error(codes.InvalidArgument, "user_id is required")
+		}
+		userID = caller
+	}
+	if userID != caller && !auth.IsAdmin(ctx) {
+		return status.Error(codes.PermissionDenied, "entitlements can only be watched by their owner")
+	}
+
+	if uc.watchHub == nil {
+		return status.Error(codes.Unavailable, "entitlement watching is not enabled")
+	}
+
+	// Watch before loading the snapshot so that no change made in between is
+	// missed. A user's family is only watched once the snapshot has found it
+	// on their entitlements.
+	watchFamilyID := familyID
+	if !auth.IsAdmin(ctx) {
+		watchFamilyID = ""
+	}
+	watch := uc.watchHub.Watch(userID, watchFamilyID)
+	defer watch.Close()
+
+	snapshot, err := uc.entitlementSnapshot(ctx, userID, familyID, watch)
+	if err != nil {
+		return err
+	}
+
+	expiries := make(map[uuid.UUID]domain.Entitlement)
+	inSnapshot := make(map[uuid.UUID]bool, len(snapshot))
+	for _, ent := range snapshot {
+		inSnapshot[ent.ID] = true
+		trackExpiry(expiries, *ent)
+	}
+
+	if err := fn(EntitlementWatchEvent{Snapshot: snapshot}); err != nil {
+		return err
+	}
+
+	for {
+		var expiry <-chan time.Time
+		var timer *time.Timer
+		if next, ok := nextExpiry(expiries); ok {
+			timer = time.NewTimer(time.Until(next))
+			expiry = timer.C
+		}
+
+		var changes []events.EntitlementChange
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case change, ok := <-watch.Changes():
+			if !ok {
+				if watch.Err() != nil {
+					return status.Error(codes.Aborted, "entitlement watch fell behind, reconnect to resume")
+				}
+				return nil
+			}
+
+			// Grants racing the snapshot are already in it
+			if change.Type == events.EntitlementChangeGranted && inSnapshot[change.Entitlement.ID] {
+				delete(inSnapshot, change.Entitlement.ID)
+				break
+			}
+			if change.Entitlement.UserID == userID && change.Entitlement.FamilyID != nil {
+				watch.AddFamily(*change.Entitlement.FamilyID)
+			}
+			trackExpiry(expiries, change.Entitlement)
+			changes = append(changes, change)
+
+		case now := <-expiry:
+			for id, ent := range expiries {
+				if !ent.ExpiresAt.After(now) {
+					delete(expiries, id)
+					changes = append(changes, events.NewEntitlementChange(ent, events.EntitlementChangeExpired))
+				}
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+
+		for i := range changes {
+			if err := fn(EntitlementWatchEvent{Change: &changes[i]}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// entitlementSnapshot returns the valid entitlements of a user and of their
+// families, and adds every family found on the user's entitlements to watch.
+// Unless the caller is an admin, familyID must be one of those families.
+func (uc *EntitlementUseCase) entitlementSnapshot(ctx context.Context, userID, familyID string, watch *events.EntitlementWatch) ([]*domain.Entitlement, error) {
+	owned, err := uc.entitlementRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list user entitlements: %v", err)
+	}
+
+	snapshot := make([]*domain.Entitlement, 0, len(owned))
+	seen := make(map[uuid.UUID]bool)
+	var familyIDs []string
+	if familyID != "" && auth.IsAdmin(ctx) {
+		familyIDs = append(familyIDs, familyID)
+	}
+	for i := range owned {
+		ent := &owned[i]
+		if ent.FamilyID != nil && *ent.FamilyID != "" && !slices.Contains(familyIDs, *ent.FamilyID) {
+			familyIDs = append(familyIDs, *ent.FamilyID)
+			watch.AddFamily(*ent.FamilyID)
+		}
+		if isValidEntitlement(ent) {
+			seen[ent.ID] = true
+			snapshot = append(snapshot, ent)
+		}
+	}
+	if familyID != "" && !slices.Contains(familyIDs, familyID) {
+		return nil, status.Error(codes.PermissionDenied, "family_id is not a family of the user")
+	}
+
+	for _, fid := range familyIDs {
+		filter := domain.EntitlementFilter{FamilyID: fid, Status: "active"}
+		err := uc.ExportEntitlements(ctx, filter, func(ent *domain.Entitlement) error {
+			if !seen[ent.ID] && isValidEntitlement(ent) {
+				seen[ent.ID] = true
+				copied := *ent
+				snapshot = append(snapshot, &copied)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// trackExpiry records when an active entitlement expires, and forgets
+// entitlements that are no longer active or no longer expire
+func trackExpiry(expiries map[uuid.UUID]domain.Entitlement, ent domain.Entitlement) {
+	if ent.Status == "active" && ent.ExpiresAt != nil {
+		expiries[ent.ID] = ent
+		return
+	}
+	delete(expiries, ent.ID)
+}
+
+// nextExpiry returns the earliest tracked expiry
+func nextExpiry(expiries map[uuid.UUID]domain.Entitlement) (time.Time, bool) {
+	var next time.Time
+	found := false
+	for _, ent := range expiries {
+		if !found || ent.ExpiresAt.Before(next) {
+			next = *ent.ExpiresAt
+			found = true
+		}
+	}
+	return next, found
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+	"github.com/jia-app/paymentservice/internal/shared/events"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// startWatch runs WatchEntitlements in the background and returns its events
+func startWatch(t *testing.T, uc *EntitlementUseCase, userID, familyID string) <-chan EntitlementWatchEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(log.WithUserID(context.Background(), userID))
+	received := make(chan EntitlementWatchEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- uc.WatchEntitlements(ctx, userID, familyID, func(event EntitlementWatchEvent) error {
+			received <- event
+			return nil
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("WatchEntitlements() error = %v", err)
+		}
+	})
+	return received
+}
+
+func nextWatchEvent(t *testing.T, received <-chan EntitlementWatchEvent) EntitlementWatchEvent {
+	t.Helper()
+	select {
+	case event := <-received:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a watch event")
+		return EntitlementWatchEvent{}
+	}
+}
+
+func TestEntitlementUseCase_WatchEntitlements(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	plan, err := store.Plan().Create(ctx, domain.Plan{Code: "family_monthly", Name: "Family", Currency: "USD", Active: true})
+	if err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+
+	family := "family-1"
+	insert := func(userID, featureCode string, familyID *string, status string, expiresAt *time.Time) domain.Entitlement {
+		t.Helper()
+		ent, err := store.Entitlement().Insert(ctx, domain.Entitlement{
+			UserID:      userID,
+			FamilyID:    familyID,
+			FeatureCode: featureCode,
+			PlanID:      plan.ID,
+			Status:      status,
+			GrantedAt:   time.Now(),
+			ExpiresAt:   expiresAt,
+		})
+		if err != nil {
+			t.Fatalf("failed to insert entitlement: %v", err)
+		}
+		return ent
+	}
+
+	past := time.Now().Add(-time.Hour)
+	owned := insert("user-1", "storage", &family, "active", nil)
+	insert("user-1", "sharing", nil, "revoked", nil)
+	insert("user-1", "export", nil, "active", &past)
+	shared := insert("user-2", "albums", &family, "active", nil)
+	insert("user-3", "storage", nil, "active", nil)
+
+	hub := events.NewEntitlementHub()
+	uc := NewEntitlementUseCase(store.Entitlement(), nil, nil, hub, hub, nil, nil)
+	received := startWatch(t, uc, "user-1", "")
+
+	snapshot := nextWatchEvent(t, received)
+	if snapshot.Change != nil {
+		t.Fatalf("expected the snapshot first, got change %+v", snapshot.Change)
+	}
+	got := map[string]bool{}
+	for _, ent := range snapshot.Snapshot {
+		got[ent.ID.String()] = true
+	}
+	if len(got) != 2 || !got[owned.ID.String()] || !got[shared.ID.String()] {
+		t.Errorf("expected the user's and their family's valid entitlements, got %+v", snapshot.Snapshot)
+	}
+
+	granted, err := uc.CreateEntitlement(ctx, "user-1", "exports", plan.ID, nil)
+	if err != nil {
+		t.Fatalf("CreateEntitlement() error = %v", err)
+	}
+	event := nextWatchEvent(t, received)
+	if event.Change == nil || event.Change.Type != events.EntitlementChangeGranted || event.Change.Entitlement.ID != granted.ID {
+		t.Errorf("expected the grant of %s, got %+v", granted.ID, event.Change)
+	}
+
+	// A family member's change reaches the watcher through the family
+	revoked := shared
+	revoked.Status = "revoked"
+	if err := hub.PublishEntitlementUpdated(ctx, revoked, events.EntitlementChangeRevoked); err != nil {
+		t.Fatalf("PublishEntitlementUpdated() error = %v", err)
+	}
+	event = nextWatchEvent(t, received)
+	if event.Change == nil || event.Change.Type != events.EntitlementChangeRevoked || event.Change.Entitlement.ID != shared.ID {
+		t.Errorf("expected the revoke of %s, got %+v", shared.ID, event.Change)
+	}
+
+	expiresAt := time.Now().Add(50 * time.Millisecond)
+	expiring, err := uc.CreateEntitlement(ctx, "user-1", "trial", plan.ID, &expiresAt)
+	if err != nil {
+		t.Fatalf("CreateEntitlement() error = %v", err)
+	}
+	event = nextWatchEvent(t, received)
+	if event.Change == nil || event.Change.Type != events.EntitlementChangeGranted {
+		t.Fatalf("expected the trial grant, got %+v", event.Change)
+	}
+	event = nextWatchEvent(t, received)
+	if event.Change == nil || event.Change.Type != events.EntitlementChangeExpired || event.Change.Entitlement.ID != expiring.ID {
+		t.Errorf("expected the trial to expire, got %+v", event.Change)
+	}
+}
+
+func TestEntitlementUseCase_WatchEntitlementsErrors(t *testing.T) {
+	store := memory.NewStore()
+	noop := func(EntitlementWatchEvent) error { return nil }
+
+	user := log.WithUserID(context.Background(), "user-1")
+
+	uc := NewEntitlementUseCase(store.Entitlement(), nil, nil, nil, nil, nil, nil)
+	err := uc.WatchEntitlements(user, "user-1", "", noop)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("expected Unavailable without a hub, got %v", err)
+	}
+
+	uc = NewEntitlementUseCase(store.Entitlement(), nil, nil, nil, events.NewEntitlementHub(), nil, nil)
+	err = uc.WatchEntitlements(context.Background(), "", "", noop)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without a user, got %v", err)
+	}
+
+	err = uc.WatchEntitlements(user, "user-2", "", noop)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied watching another user, got %v", err)
+	}
+
+	err = uc.WatchEntitlements(user, "", "family-1", noop)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied watching a family the user is not in, got %v", err)
+	}
+}
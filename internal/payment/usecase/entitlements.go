@@ -9,30 +9,45 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/auth"
 	"github.com/jia-app/paymentservice/internal/shared/cache"
 	"github.com/jia-app/paymentservice/internal/shared/events"
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
 // EntitlementUseCase provides business logic for entitlement operations
 type EntitlementUseCase struct {
 	entitlementRepo      repo.EntitlementRepository
-	cache                *cache.Cache // Can be nil if Redis is not available
+	features             *FeatureCatalogUseCase // Can be nil if feature codes are not checked
+	cache                *cache.Cache           // Can be nil if Redis is not available
 	entitlementPublisher events.EntitlementPublisher
+	watchHub             *events.EntitlementHub    // Can be nil if watching is disabled
+	auditor              *audit.Recorder           // Can be nil if auditing is disabled
+	metrics              *metrics.MetricsCollector // Can be nil if metrics are disabled
 }
 
 // NewEntitlementUseCase creates a new entitlement use case
 func NewEntitlementUseCase(
 	entitlementRepo repo.EntitlementRepository,
+	features *FeatureCatalogUseCase,
 	cache *cache.Cache,
 	entitlementPublisher events.EntitlementPublisher,
+	watchHub *events.EntitlementHub,
+	auditor *audit.Recorder,
+	metricsCollector *metrics.MetricsCollector,
 ) *EntitlementUseCase {
 	return &EntitlementUseCase{
 		entitlementRepo:      entitlementRepo,
+		features:             features,
 		cache:                cache,
 		entitlementPublisher: entitlementPublisher,
+		watchHub:             watchHub,
+		auditor:              auditor,
+		metrics:              metricsCollector,
 	}
 }
 
@@ -52,74 +67,61 @@ func (uc *EntitlementUseCase) CheckEntitlement(ctx context.Context, userID, feat
 		return nil, status.Error(codes.InvalidArgument, "feature_code is required")
 	}
 
-	// Try Redis cache first
-	if uc.cache != nil {
-		cachedEnt, found, err := uc.cache.GetEntitlement(ctx, userID, featureCode)
-		if err != nil {
-			log.Warn(ctx, "Failed to get entitlement from cache",
-				zap.Error(err), zap.String("user_id", userID), zap.String("feature_code", featureCode))
-		} else if found {
-			// Check if it's a negative cache result
-			if isNegative, err := uc.cache.IsEntitlementNotFound(ctx, userID, featureCode); err == nil && isNegative {
-				return &CheckEntitlementResponse{
-					Allowed:     false,
-					Entitlement: nil,
-				}, nil
-			}
-
-			// Validate cached entitlement is still active and not expired
-			if isValidEntitlement(cachedEnt) {
-				return &CheckEntitlementResponse{
-					Allowed:     true,
-					Entitlement: cachedEnt,
-				}, nil
+	// Codes missing from the feature catalog are rejected rather than denied,
+	// so a misspelt feature fails loudly instead of looking unpaid
+	if uc.features != nil {
+		if _, err := uc.features.Resolve(ctx, featureCode); err != nil {
+			if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeNotFound {
+				return nil, status.Errorf(codes.InvalidArgument, "unknown feature_code: %s", featureCode)
 			}
-
-			// Cached entitlement is invalid, evict from cache and fallback to repo
-			uc.cache.DeleteEntitlement(ctx, userID, featureCode)
+			return nil, status.Errorf(codes.Internal, "failed to resolve feature: %v", err)
 		}
 	}
 
-	// Fallback to repository
-	entitlement, found, err := uc.entitlementRepo.Check(ctx, userID, featureCode)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to check entitlement: %v", err)
-	}
+	start := time.Now()
+	cacheHit := false
+	defer func() {
+		uc.metrics.RecordEntitlementCheck(ctx, cacheHit, time.Since(start))
+	}()
 
-	if !found {
-		// Cache negative result
-		if uc.cache != nil {
-			uc.cache.SetEntitlementNotFound(ctx, userID, featureCode)
-		}
-		return &CheckEntitlementResponse{
-			Allowed:     false,
-			Entitlement: nil,
-		}, nil
-	}
-
-	// Validate entitlement is active and not expired
-	if !isValidEntitlement(&entitlement) {
-		// Cache negative result for invalid entitlements
-		if uc.cache != nil {
-			uc.cache.SetEntitlementNotFound(ctx, userID, featureCode)
+	// Look up through the local and Redis tiers, collapsing concurrent misses
+	if uc.cache != nil {
+		entitlement, hit, err := uc.cache.LookupEntitlement(ctx, userID, featureCode, uc.loadValidEntitlement(userID, featureCode))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check entitlement: %v", err)
 		}
+		cacheHit = hit
 		return &CheckEntitlementResponse{
-			Allowed:     false,
-			Entitlement: nil,
+			Allowed:     entitlement != nil,
+			Entitlement: entitlement,
 		}, nil
 	}
 
-	// Cache valid entitlement
-	if uc.cache != nil {
-		uc.cache.SetEntitlement(ctx, entitlement, 0) // Use default TTL
+	entitlement, err := uc.loadValidEntitlement(userID, featureCode)(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check entitlement: %v", err)
 	}
-
 	return &CheckEntitlementResponse{
-		Allowed:     true,
-		Entitlement: &entitlement,
+		Allowed:     entitlement != nil,
+		Entitlement: entitlement,
 	}, nil
 }
 
+// loadValidEntitlement loads a user's entitlement to a feature from the
+// repository, treating inactive and expired entitlements as missing
+func (uc *EntitlementUseCase) loadValidEntitlement(userID, featureCode string) cache.EntitlementLoader {
+	return func(ctx context.Context) (*domain.Entitlement, error) {
+		entitlement, found, err := uc.entitlementRepo.Check(ctx, userID, featureCode)
+		if err != nil {
+			return nil, err
+		}
+		if !found || !isValidEntitlement(&entitlement) {
+			return nil, nil
+		}
+		return &entitlement, nil
+	}
+}
+
 // ListUserEntitlements lists all entitlements for a user
 func (uc *EntitlementUseCase) ListUserEntitlements(ctx context.Context, userID string) ([]*domain.Entitlement, error) {
 	// Use authenticated user_id from context if userID is empty
@@ -145,6 +147,32 @@ func (uc *EntitlementUseCase) ListUserEntitlements(ctx context.Context, userID s
 	return result, nil
 }
 
+// ExportEntitlements calls fn for every entitlement matching the filter, in
+// creation order. Rows are fetched in keyset batches so memory use stays
+// bounded regardless of the result size. Iteration stops at the first error
+// from fn.
+func (uc *EntitlementUseCase) ExportEntitlements(ctx context.Context, filter domain.EntitlementFilter, fn func(*domain.Entitlement) error) error {
+	var cursor *domain.EntitlementCursor
+	for {
+		entitlements, err := uc.entitlementRepo.ListAfter(ctx, filter, cursor, exportBatchSize)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to export entitlements: %v", err)
+		}
+
+		for i := range entitlements {
+			if err := fn(&entitlements[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(entitlements) < exportBatchSize {
+			return nil
+		}
+		last := entitlements[len(entitlements)-1]
+		cursor = &domain.EntitlementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
 // CreateEntitlement creates a new entitlement
 func (uc *EntitlementUseCase) CreateEntitlement(ctx context.Context, userID, featureCode string, planID uuid.UUID, expiresAt *time.Time) (*domain.Entitlement, error) {
 	entitlement := domain.Entitlement{
@@ -195,6 +223,25 @@ func extractUserIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// authorizeOwner returns the user whose data the caller may access when
+// asking for userID's. Admins may access any user, or every user when
+// userID is empty; everyone else only their own, which an empty userID
+// defaults to.
+func authorizeOwner(ctx context.Context, userID string) (string, error) {
+	if auth.IsAdmin(ctx) {
+		return userID, nil
+	}
+
+	caller := extractUserIDFromContext(ctx)
+	if caller == "" {
+		return "", domain.NewUnauthorizedError("user data can only be accessed by an authenticated user")
+	}
+	if userID != "" && userID != caller {
+		return "", domain.NewUnauthorizedError("user data can only be accessed by its owner")
+	}
+	return caller, nil
+}
+
 func isValidEntitlement(ent *domain.Entitlement) bool {
 	if ent == nil {
 		return false
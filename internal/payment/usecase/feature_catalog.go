@@ -0,0 +1,289 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+const (
+	// featureCatalogTTL bounds how long a replica resolves feature codes from
+	// its copy of the catalog; features changed on another replica are seen
+	// within it
+	featureCatalogTTL = 30 * time.Second
+	// featureCatalogRetryInterval is how long a replica serves its copy after
+	// failing to reload the catalog before it tries again
+	featureCatalogRetryInterval = 5 * time.Second
+	// featureCatalogMissInterval is the least time between reloads for codes
+	// missing from a replica's copy, which may have been created on another
+	featureCatalogMissInterval = time.Second
+)
+
+// FeatureCatalogUseCase manages the feature catalog, the single source of
+// the feature codes plans may include, webhooks grant and checks accept
+type FeatureCatalogUseCase struct {
+	featureRepo repo.FeatureRepository
+	planRepo    repo.PlanRepository
+	auditor     *audit.Recorder // Can be nil if auditing is disabled
+
+	loads      singleflight.Group
+	mu         sync.Mutex
+	features   map[string]domain.Feature // Cached catalog, nil until loaded
+	expiresAt  time.Time                 // When the cached catalog is next reloaded
+	reloadedAt time.Time                 // When a reload last finished, successful or not
+	generation uint64                    // Bumped by invalidate so earlier loads are not cached
+}
+
+// NewFeatureCatalogUseCase creates a new feature catalog use case
+func NewFeatureCatalogUseCase(featureRepo repo.FeatureRepository, planRepo repo.PlanRepository, auditor *audit.Recorder) *FeatureCatalogUseCase {
+	return &FeatureCatalogUseCase{
+		featureRepo: featureRepo,
+		planRepo:    planRepo,
+		auditor:     auditor,
+	}
+}
+
+// ListFeatures lists every catalog feature, deprecated ones included
+func (uc *FeatureCatalogUseCase) ListFeatures(ctx context.Context) ([]domain.Feature, error) {
+	features, err := uc.featureRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list features: %w", err)
+	}
+	return features, nil
+}
+
+// CreateFeature validates and adds a feature to the catalog
+func (uc *FeatureCatalogUseCase) CreateFeature(ctx context.Context, feature domain.Feature) (*domain.Feature, error) {
+	feature.Deprecated = false
+	if err := feature.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.featureRepo.Create(ctx, feature)
+	if err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityFeature,
+		EntityID:   created.Code,
+		Action:     domain.AuditActionCreate,
+		After:      created,
+	})
+	log.Info(ctx, "Feature created", zap.String("feature_code", created.Code))
+
+	return &created, nil
+}
+
+// UpdateFeature applies a partial update to a feature. A quota schema change
+// is rejected while a catalog plan sets a usage limit it would no longer
+// govern; deprecating a feature leaves the plans that include it unchanged.
+func (uc *FeatureCatalogUseCase) UpdateFeature(ctx context.Context, code string, update domain.FeatureUpdate) (*domain.Feature, error) {
+	current, err := uc.featureRepo.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	feature := update.Apply(current)
+	if err := feature.Validate(); err != nil {
+		return nil, err
+	}
+	if update.QuotaSchema != nil {
+		if err := uc.checkPlansWithSchema(ctx, feature); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := uc.featureRepo.Update(ctx, feature)
+	if err != nil {
+		return nil, err
+	}
+	uc.invalidate()
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityFeature,
+		EntityID:   updated.Code,
+		Action:     domain.AuditActionUpdate,
+		Before:     current,
+		After:      updated,
+	})
+	log.Info(ctx, "Feature updated",
+		zap.String("feature_code", updated.Code),
+		zap.Bool("deprecated", updated.Deprecated))
+
+	return &updated, nil
+}
+
+// Resolve returns the catalog entry for a feature code, or a not-found
+// domain error if the code is not in the catalog
+func (uc *FeatureCatalogUseCase) Resolve(ctx context.Context, code string) (domain.Feature, error) {
+	features, err := uc.catalogWith(ctx, []string{code})
+	if err != nil {
+		return domain.Feature{}, err
+	}
+	feature, ok := features[code]
+	if !ok {
+		return domain.Feature{}, domain.NewNotFoundError("feature", code)
+	}
+	return feature, nil
+}
+
+// ValidatePlan checks a plan's features and usage limits against the catalog.
+// Every feature must be in the catalog and deprecated ones may not be added,
+// though a plan keeps those it already includes (current, nil for a new
+// plan). Usage limits must be governed by one of the plan's features; they
+// are not rechecked on updates that change neither them nor the features.
+func (uc *FeatureCatalogUseCase) ValidatePlan(ctx context.Context, plan domain.Plan, current *domain.Plan) error {
+	features, err := uc.catalogWith(ctx, plan.FeatureCodes)
+	if err != nil {
+		return err
+	}
+
+	included := make([]domain.Feature, 0, len(plan.FeatureCodes))
+	for _, code := range plan.FeatureCodes {
+		feature, ok := features[code]
+		if !ok {
+			return domain.NewInvalidInputError("invalid feature codes", fmt.Sprintf("unknown feature code: %s", code))
+		}
+		if feature.Deprecated && (current == nil || !slices.Contains(current.FeatureCodes, code)) {
+			return domain.NewInvalidInputError("invalid feature codes", fmt.Sprintf("feature %s is deprecated", code))
+		}
+		included = append(included, feature)
+	}
+
+	if current != nil && slices.Equal(current.FeatureCodes, plan.FeatureCodes) && bytes.Equal(current.UsageLimits, plan.UsageLimits) {
+		return nil
+	}
+	return domain.ValidateUsageLimits(plan.UsageLimits, included)
+}
+
+// checkPlansWithSchema checks that every catalog plan including a feature
+// still validates with the feature's new quota schema
+func (uc *FeatureCatalogUseCase) checkPlansWithSchema(ctx context.Context, feature domain.Feature) error {
+	features, err := uc.catalog(ctx)
+	if err != nil {
+		return err
+	}
+	plans, err := uc.planRepo.List(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	for _, plan := range plans {
+		if !slices.Contains(plan.FeatureCodes, feature.Code) {
+			continue
+		}
+		included := make([]domain.Feature, 0, len(plan.FeatureCodes))
+		for _, code := range plan.FeatureCodes {
+			if code == feature.Code {
+				included = append(included, feature)
+			} else if f, ok := features[code]; ok {
+				included = append(included, f)
+			}
+		}
+		if err := domain.ValidateUsageLimits(plan.UsageLimits, included); err != nil {
+			return domain.NewInvalidStateError("quota schema conflicts with a plan", fmt.Sprintf("plan %s: %s", plan.Code, domain.GetDomainError(err).Details))
+		}
+	}
+	return nil
+}
+
+// catalog returns the cached catalog, reloading it once it is older than
+// featureCatalogTTL
+func (uc *FeatureCatalogUseCase) catalog(ctx context.Context) (map[string]domain.Feature, error) {
+	uc.mu.Lock()
+	features, expired := uc.features, !time.Now().Before(uc.expiresAt)
+	uc.mu.Unlock()
+
+	if features != nil && !expired {
+		return features, nil
+	}
+	return uc.reload(ctx)
+}
+
+// catalogWith returns the cached catalog, reloading it first when it is due
+// or when one of codes is missing from a copy older than
+// featureCatalogMissInterval, so features created on another replica are
+// found before the copy expires
+func (uc *FeatureCatalogUseCase) catalogWith(ctx context.Context, codes []string) (map[string]domain.Feature, error) {
+	features, err := uc.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range codes {
+		if _, ok := features[code]; ok {
+			continue
+		}
+		uc.mu.Lock()
+		recent := time.Since(uc.reloadedAt) < featureCatalogMissInterval
+		uc.mu.Unlock()
+		if recent {
+			return features, nil
+		}
+		return uc.reload(ctx)
+	}
+	return features, nil
+}
+
+// reload loads the catalog from the repository without holding the lock,
+// sharing one load among concurrent callers. When the load fails, the
+// cached catalog is served until featureCatalogRetryInterval has passed;
+// only a replica that never loaded it returns the error.
+func (uc *FeatureCatalogUseCase) reload(ctx context.Context) (map[string]domain.Feature, error) {
+	uc.mu.Lock()
+	generation := uc.generation
+	uc.mu.Unlock()
+
+	v, err, _ := uc.loads.Do(fmt.Sprintf("catalog:%d", generation), func() (interface{}, error) {
+		list, err := uc.featureRepo.List(ctx)
+
+		uc.mu.Lock()
+		defer uc.mu.Unlock()
+		now := time.Now()
+		uc.reloadedAt = now
+
+		if err != nil {
+			if uc.features == nil {
+				return nil, fmt.Errorf("failed to load feature catalog: %w", err)
+			}
+			uc.expiresAt = now.Add(featureCatalogRetryInterval)
+			log.Warn(ctx, "Failed to reload feature catalog, serving the cached copy", zap.Error(err))
+			return uc.features, nil
+		}
+
+		features := make(map[string]domain.Feature, len(list))
+		for _, feature := range list {
+			features[feature.Code] = feature
+		}
+		if uc.generation == generation {
+			uc.features, uc.expiresAt = features, now.Add(featureCatalogTTL)
+		}
+		return features, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]domain.Feature), nil
+}
+
+// invalidate expires the cached catalog after a change made through this
+// replica; the copy is still served should the reload fail
+func (uc *FeatureCatalogUseCase) invalidate() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.expiresAt = time.Time{}
+	uc.generation++
+}
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/payment/repo/memory"
+)
+
+// newFeatureCatalog returns a catalog holding pro_storage, which governs
+// storage_gb, priority_support and the deprecated legacy_backup
+func newFeatureCatalog(t *testing.T, store *memory.Store) *FeatureCatalogUseCase {
+	t.Helper()
+	ctx := context.Background()
+	features := NewFeatureCatalogUseCase(store.Feature(), store.Plan(), nil)
+
+	for _, feature := range []domain.Feature{
+		{Code: "pro_storage", QuotaSchema: map[string]domain.QuotaType{"storage_gb": domain.QuotaTypeInteger}, DefaultLimits: json.RawMessage(`{"storage_gb": 100}`)},
+		{Code: "priority_support"},
+		{Code: "legacy_backup"},
+	} {
+		if _, err := features.CreateFeature(ctx, feature); err != nil {
+			t.Fatalf("CreateFeature(%s) error = %v", feature.Code, err)
+		}
+	}
+	deprecated := true
+	if _, err := features.UpdateFeature(ctx, "legacy_backup", domain.FeatureUpdate{Deprecated: &deprecated}); err != nil {
+		t.Fatalf("UpdateFeature() error = %v", err)
+	}
+	return features
+}
+
+func catalogPlan(featureCodes ...string) domain.Plan {
+	return domain.Plan{
+		Code:         "pro_monthly",
+		Name:         "Pro",
+		FeatureCodes: featureCodes,
+		BillingCycle: domain.BillingCycleMonthly,
+		PriceDollars: 19.99,
+		Currency:     "USD",
+		MaxUsers:     1,
+		UsageLimits:  json.RawMessage(`{"storage_gb": 500}`),
+	}
+}
+
+func TestPlanCatalogUseCase_ValidatesFeatures(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	features := newFeatureCatalog(t, store)
+	plans := NewPlanCatalogUseCase(store.Plan(), features, nil)
+
+	rejected := map[string]domain.Plan{
+		"unknown feature":    catalogPlan("pro_storage", "premium_feature"),
+		"deprecated feature": catalogPlan("pro_storage", "legacy_backup"),
+		"ungoverned limit":   catalogPlan("priority_support"),
+	}
+	for name, plan := range rejected {
+		t.Run(name, func(t *testing.T) {
+			if _, err := plans.CreatePlan(ctx, plan); errorCode(err) != domain.ErrCodeInvalidInput {
+				t.Errorf("CreatePlan() error = %v, want invalid input", err)
+			}
+		})
+	}
+
+	// A plan that already includes a feature keeps it once it is deprecated
+	plan := catalogPlan("pro_storage", "legacy_backup")
+	plan.Active, plan.Version = true, 1
+	if _, err := store.Plan().Create(ctx, plan); err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+	name := "Pro Plus"
+	if _, _, err := plans.UpdatePlan(ctx, "pro_monthly", domain.PlanUpdate{Name: &name}); err != nil {
+		t.Errorf("UpdatePlan() error = %v, want the deprecated feature kept", err)
+	}
+	if _, _, err := plans.UpdatePlan(ctx, "pro_monthly", domain.PlanUpdate{UsageLimits: json.RawMessage(`{"storage_gb": "lots"}`)}); errorCode(err) != domain.ErrCodeInvalidInput {
+		t.Errorf("UpdatePlan() error = %v, want invalid input for a mistyped limit", err)
+	}
+
+	// Dropping storage_gb from the schema would orphan the plan's limit
+	if _, err := features.UpdateFeature(ctx, "pro_storage", domain.FeatureUpdate{
+		QuotaSchema:   map[string]domain.QuotaType{},
+		DefaultLimits: json.RawMessage(`{}`),
+	}); errorCode(err) != domain.ErrCodeInvalidState {
+		t.Errorf("UpdateFeature() error = %v, want invalid state", err)
+	}
+}
+
+func TestPlanFeatureService_EntitlementsForPlan(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	features := newFeatureCatalog(t, store)
+
+	// Plans created before the catalog may include codes missing from it
+	plan := catalogPlan("pro_storage", "priority_support", "premium_feature")
+	plan.Active, plan.Version = true, 1
+	created, err := store.Plan().Create(ctx, plan)
+	if err != nil {
+		t.Fatalf("failed to create plan: %v", err)
+	}
+
+	entitlements, err := NewPlanFeatureService(store.Plan(), features).EntitlementsForPlan(ctx, "user-1", "pro_monthly", 0, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("EntitlementsForPlan() error = %v", err)
+	}
+	if len(entitlements) != 2 {
+		t.Fatalf("got %d entitlements, want 2 with premium_feature skipped", len(entitlements))
+	}
+	for _, entitlement := range entitlements {
+		if entitlement.PlanID != created.ID {
+			t.Errorf("%s: plan ID = %s, want %s", entitlement.FeatureCode, entitlement.PlanID, created.ID)
+		}
+	}
+	if got := string(entitlements[0].UsageLimits); got != `{"storage_gb":500}` {
+		t.Errorf("pro_storage usage limits = %s, want the plan's", got)
+	}
+	if got := string(entitlements[1].UsageLimits); got != `{}` {
+		t.Errorf("priority_support usage limits = %s, want {}", got)
+	}
+
+	// Checkouts started before the plan was archived still complete
+	if _, err := store.Plan().Archive(ctx, "pro_monthly"); err != nil {
+		t.Fatalf("failed to archive plan: %v", err)
+	}
+	entitlements, err = NewPlanFeatureService(store.Plan(), features).EntitlementsForPlan(ctx, "user-1", "pro_monthly", 0, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("EntitlementsForPlan() of an archived plan error = %v", err)
+	}
+	if len(entitlements) != 2 {
+		t.Errorf("got %d entitlements for the archived plan, want 2", len(entitlements))
+	}
+}
+
+func TestEntitlementChecks_RejectUnknownFeatures(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	features := newFeatureCatalog(t, store)
+
+	_, err := NewEntitlementUseCase(store.Entitlement(), features, nil, nil, nil, nil, nil).CheckEntitlement(ctx, "user-1", "premium_feature")
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CheckEntitlement() error = %v, want InvalidArgument", err)
+	}
+	check, err := NewEntitlementUseCase(store.Entitlement(), features, nil, nil, nil, nil, nil).CheckEntitlement(ctx, "user-1", "legacy_backup")
+	if err != nil || check.Allowed {
+		t.Errorf("CheckEntitlement() of a deprecated feature = %+v, %v, want denied", check, err)
+	}
+
+	bulk, err := NewBulkEntitlementUseCase(store.Entitlement(), features, nil).BulkCheckEntitlements(ctx, BulkCheckRequest{
+		UserID: "user-1",
+		Checks: []BulkCheckItem{{FeatureCode: "premium_feature"}, {FeatureCode: "pro_storage"}},
+	})
+	if err != nil {
+		t.Fatalf("BulkCheckEntitlements() error = %v", err)
+	}
+	if reason := bulk.Results[0].Reason; reason != "Unknown feature code" {
+		t.Errorf("unknown feature reason = %q", reason)
+	}
+	if reason := bulk.Results[1].Reason; reason != "No active entitlement found" {
+		t.Errorf("catalog feature reason = %q", reason)
+	}
+
+	explanation, err := NewEntitlementExplainUseCase(store.Entitlement(), store.Subscription(), store.Plan(), features, nil).ExplainEntitlement(ctx, "user-1", "premium_feature", "")
+	if err != nil {
+		t.Fatalf("ExplainEntitlement() error = %v", err)
+	}
+	if explanation.Rule != EntitlementRuleUnknownFeature || explanation.Feature != nil {
+		t.Errorf("explanation rule = %s, feature = %v, want %s without a feature", explanation.Rule, explanation.Feature, EntitlementRuleUnknownFeature)
+	}
+}
+
+// flakyFeatureRepo fails to list features while down
+type flakyFeatureRepo struct {
+	repo.FeatureRepository
+	down bool
+}
+
+func (r *flakyFeatureRepo) List(ctx context.Context) ([]domain.Feature, error) {
+	if r.down {
+		return nil, errors.New("connection refused")
+	}
+	return r.FeatureRepository.List(ctx)
+}
+
+func TestFeatureCatalogUseCase_Reloads(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	admin := newFeatureCatalog(t, store)
+	features := &flakyFeatureRepo{FeatureRepository: store.Feature()}
+	replica := NewFeatureCatalogUseCase(features, store.Plan(), nil)
+
+	if _, err := replica.Resolve(ctx, "pro_storage"); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	// A feature created on another replica is found without waiting for the
+	// copy to expire, once it is older than the miss interval
+	if _, err := admin.CreateFeature(ctx, domain.Feature{Code: "shared_albums"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if _, err := replica.Resolve(ctx, "shared_albums"); errorCode(err) != domain.ErrCodeNotFound {
+		t.Errorf("Resolve() error = %v, want not found within the miss interval", err)
+	}
+	replica.reloadedAt = time.Now().Add(-featureCatalogMissInterval)
+	if _, err := replica.Resolve(ctx, "shared_albums"); err != nil {
+		t.Errorf("Resolve() error = %v, want the reloaded feature", err)
+	}
+
+	// A failed reload serves the cached copy
+	features.down = true
+	replica.invalidate()
+	if _, err := replica.Resolve(ctx, "shared_albums"); err != nil {
+		t.Errorf("Resolve() error = %v, want the cached feature", err)
+	}
+	if _, err := NewFeatureCatalogUseCase(features, store.Plan(), nil).Resolve(ctx, "pro_storage"); err == nil {
+		t.Error("expected an error when the catalog was never loaded")
+	}
+}
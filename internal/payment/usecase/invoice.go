@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/invoice"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// InvoiceFormat selects the document rendered for an invoice
+type InvoiceFormat string
+
+const (
+	InvoiceFormatNone InvoiceFormat = ""     // Invoice data only
+	InvoiceFormatHTML InvoiceFormat = "html" // HTML document
+	InvoiceFormatPDF  InvoiceFormat = "pdf"  // PDF document
+)
+
+const (
+	defaultInvoicePageSize = 50
+	maxInvoicePageSize     = 500
+)
+
+// InvoiceUseCase provides business logic for invoices
+type InvoiceUseCase struct {
+	invoiceRepo repo.InvoiceRepository
+	planRepo    repo.PlanRepository
+	renderer    *invoice.Renderer
+}
+
+// NewInvoiceUseCase creates a new invoice use case
+func NewInvoiceUseCase(invoiceRepo repo.InvoiceRepository, planRepo repo.PlanRepository, renderer *invoice.Renderer) *InvoiceUseCase {
+	return &InvoiceUseCase{
+		invoiceRepo: invoiceRepo,
+		planRepo:    planRepo,
+		renderer:    renderer,
+	}
+}
+
+// InvoiceRequest describes a completed payment to invoice
+type InvoiceRequest struct {
+	Payment       *domain.Payment
+	Session       *domain.CheckoutSession // Checkout the payment was made through, if recorded
+	PlanCode      string                  // Used when there is no session
+	FamilyID      *string                 // Used when there is no session
+	Billing       domain.BillingDetails
+	DiscountCents int64 // Total discount applied by the provider
+	TaxCents      int64 // Total tax charged by the provider; ignored when the session was taxed at checkout
+}
+
+// GenerateForPayment issues the invoice for a completed payment. It is
+// idempotent: a payment that was already invoiced returns its invoice.
+func (uc *InvoiceUseCase) GenerateForPayment(ctx context.Context, req InvoiceRequest) (*domain.Invoice, error) {
+	if req.Payment == nil {
+		return nil, domain.NewInvalidInputError("invalid invoice request", "payment is required")
+	}
+
+	existing, err := uc.invoiceRepo.GetByPaymentID(ctx, req.Payment.ID)
+	if err == nil {
+		return &existing, nil
+	}
+	if !isNotFound(err) {
+		return nil, fmt.Errorf("failed to look up invoice for payment: %w", err)
+	}
+
+	inv := domain.Invoice{
+		PaymentID: req.Payment.ID,
+		UserID:    req.Payment.CustomerID,
+		FamilyID:  req.FamilyID,
+		PlanCode:  req.PlanCode,
+		Currency:  req.Payment.Currency,
+		Billing:   req.Billing,
+		Status:    domain.InvoiceStatusIssued,
+		IssuedAt:  time.Now().UTC(),
+	}
+
+	basePriceCents := dollarsToCents(req.Payment.Amount)
+	quotedPriceCents := basePriceCents
+	tax := domain.TaxQuote{Name: "Tax", TaxCents: req.TaxCents}
+	if s := req.Session; s != nil {
+		inv.CheckoutSessionID = &s.ID
+		inv.UserID = s.UserID
+		inv.FamilyID = s.FamilyID
+		inv.PlanCode = s.PlanCode
+		inv.PlanVersion = s.PlanVersion
+		inv.Currency = s.Currency
+		basePriceCents = dollarsToCents(s.BasePrice)
+		quotedPriceCents = s.QuotedPriceCents()
+		if s.Tax.Name != "" {
+			tax = s.Tax
+		}
+		if inv.Billing.TaxID == "" {
+			inv.Billing.TaxID = s.TaxID
+		}
+	}
+
+	inv.TaxInclusive = tax.Inclusive
+	inv.ReverseCharge = tax.ReverseCharge
+	if tax.ReverseCharge && tax.TotalCents < quotedPriceCents {
+		// The buyer paid the tax-inclusive price net of tax; invoice the
+		// lines net of tax too
+		factor := 1 + tax.RatePercent/100
+		basePriceCents = int64(math.Round(float64(basePriceCents) / factor))
+		quotedPriceCents = tax.TotalCents
+	}
+
+	inv.AddLine(domain.InvoiceLinePlan, uc.planDescription(ctx, inv.PlanCode, inv.PlanVersion), basePriceCents)
+	if adjustment := quotedPriceCents - basePriceCents; adjustment != 0 {
+		description := "Regional pricing"
+		if req.Session != nil && req.Session.CountryCode != "" {
+			description = fmt.Sprintf("Regional pricing (%s)", req.Session.CountryCode)
+		}
+		inv.AddLine(domain.InvoiceLineZoneAdjustment, description, adjustment)
+	}
+	if req.DiscountCents > 0 {
+		inv.AddLine(domain.InvoiceLineDiscount, "Discount", -req.DiscountCents)
+	}
+	if tax.TaxCents > 0 || tax.ReverseCharge {
+		inv.AddLine(domain.InvoiceLineTax, tax.Label(), tax.TaxCents)
+	}
+
+	if err := inv.Validate(); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.invoiceRepo.Create(ctx, inv)
+	if err != nil {
+		// A concurrent delivery of the same webhook invoiced the payment first
+		if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeAlreadyExists {
+			existing, getErr := uc.invoiceRepo.GetByPaymentID(ctx, req.Payment.ID)
+			if getErr == nil {
+				return &existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	log.Info(ctx, "Invoice issued",
+		zap.String("invoice_number", created.Number),
+		zap.String("payment_id", created.PaymentID.String()),
+		zap.String("user_id", created.UserID),
+		zap.Int64("total_cents", created.TotalCents))
+
+	return &created, nil
+}
+
+// GetInvoice retrieves an invoice by its ID or invoice number. Only its
+// owner and admins may read it.
+func (uc *InvoiceUseCase) GetInvoice(ctx context.Context, idOrNumber string) (*domain.Invoice, error) {
+	if idOrNumber == "" {
+		return nil, domain.NewInvalidInputError("invalid invoice ID", "id is required")
+	}
+
+	var (
+		inv domain.Invoice
+		err error
+	)
+	if id, parseErr := uuid.Parse(idOrNumber); parseErr == nil {
+		inv, err = uc.invoiceRepo.GetByID(ctx, id)
+	} else {
+		inv, err = uc.invoiceRepo.GetByNumber(ctx, idOrNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := authorizeOwner(ctx, inv.UserID); err != nil {
+		return nil, err
+	}
+
+	return &inv, nil
+}
+
+// ListInvoices returns one page of invoices, newest first. The filter's
+// user defaults to the authenticated user; only admins may list another
+// user's invoices, or every user's.
+func (uc *InvoiceUseCase) ListInvoices(ctx context.Context, filter domain.InvoiceFilter, pageSize int, pageToken string) (*domain.InvoicePage, error) {
+	owner, err := authorizeOwner(ctx, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+	filter.UserID = owner
+
+	if pageSize <= 0 {
+		pageSize = defaultInvoicePageSize
+	}
+	if pageSize > maxInvoicePageSize {
+		pageSize = maxInvoicePageSize
+	}
+
+	cursor, err := domain.DecodeInvoicePageToken(pageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	invoices, err := uc.invoiceRepo.List(ctx, filter, cursor, pageSize+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	page := &domain.InvoicePage{}
+	if len(invoices) > pageSize {
+		invoices = invoices[:pageSize]
+		last := invoices[pageSize-1]
+		page.NextPageToken = domain.EncodeInvoicePageToken(&domain.InvoiceCursor{Year: last.Year, Sequence: last.Sequence})
+	}
+
+	page.Invoices = make([]*domain.Invoice, len(invoices))
+	for i := range invoices {
+		page.Invoices[i] = &invoices[i]
+	}
+
+	return page, nil
+}
+
+// RenderInvoice renders an invoice document, returning it with its content type
+func (uc *InvoiceUseCase) RenderInvoice(inv *domain.Invoice, format InvoiceFormat) ([]byte, string, error) {
+	switch format {
+	case InvoiceFormatNone:
+		return nil, "", nil
+	case InvoiceFormatHTML:
+		doc, err := uc.renderer.RenderHTML(inv)
+		return doc, invoice.ContentTypeHTML, err
+	case InvoiceFormatPDF:
+		doc, err := uc.renderer.RenderPDF(inv)
+		return doc, invoice.ContentTypePDF, err
+	default:
+		return nil, "", domain.NewInvalidInputError("invalid invoice format", string(format))
+	}
+}
+
+// planDescription describes the plan line, falling back to the plan code when
+// the plan cannot be loaded
+func (uc *InvoiceUseCase) planDescription(ctx context.Context, planCode string, version int32) string {
+	name := planCode
+	if plan, err := uc.planRepo.GetByIDIncludingArchived(ctx, planCode); err == nil && plan.Name != "" {
+		name = plan.Name
+	}
+	if version > 0 {
+		return fmt.Sprintf("%s (v%d)", name, version)
+	}
+	return name
+}
+
+// isNotFound reports whether err is a domain not-found error
+func isNotFound(err error) bool {
+	domainErr := domain.GetDomainError(err)
+	return domainErr != nil && domainErr.Code == domain.ErrCodeNotFound
+}
+
+// dollarsToCents converts a dollar amount to cents
+func dollarsToCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/auth"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// memoryInvoiceRepo holds invoices in memory
+type memoryInvoiceRepo struct {
+	repo.InvoiceRepository
+	invoices []domain.Invoice
+}
+
+func (r *memoryInvoiceRepo) GetByID(ctx context.Context, id uuid.UUID) (domain.Invoice, error) {
+	for _, inv := range r.invoices {
+		if inv.ID == id {
+			return inv, nil
+		}
+	}
+	return domain.Invoice{}, domain.NewNotFoundError("invoice", id.String())
+}
+
+func (r *memoryInvoiceRepo) List(ctx context.Context, filter domain.InvoiceFilter, after *domain.InvoiceCursor, limit int) ([]domain.Invoice, error) {
+	var invoices []domain.Invoice
+	for _, inv := range r.invoices {
+		if filter.UserID == "" || inv.UserID == filter.UserID {
+			invoices = append(invoices, inv)
+		}
+	}
+	return invoices, nil
+}
+
+func TestInvoiceUseCase_OwnerAccess(t *testing.T) {
+	invoices := &memoryInvoiceRepo{invoices: []domain.Invoice{
+		{ID: uuid.New(), UserID: "user-1", Number: "INV-2025-000001"},
+		{ID: uuid.New(), UserID: "user-2", Number: "INV-2025-000002"},
+	}}
+	uc := NewInvoiceUseCase(invoices, nil, nil)
+	user := log.WithUserID(context.Background(), "user-1")
+	admin := auth.WithAdmin(log.WithUserID(context.Background(), "support-1"))
+
+	page, err := uc.ListInvoices(user, domain.InvoiceFilter{}, 0, "")
+	if err != nil {
+		t.Fatalf("ListInvoices() error = %v", err)
+	}
+	if len(page.Invoices) != 1 || page.Invoices[0].UserID != "user-1" {
+		t.Errorf("expected only the caller's invoice, got %+v", page.Invoices)
+	}
+	if _, err := uc.ListInvoices(user, domain.InvoiceFilter{UserID: "user-2"}, 0, ""); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("ListInvoices() of another user error = %v, want unauthorized", err)
+	}
+	if _, err := uc.ListInvoices(context.Background(), domain.InvoiceFilter{}, 0, ""); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("ListInvoices() without a user error = %v, want unauthorized", err)
+	}
+
+	if _, err := uc.GetInvoice(user, invoices.invoices[0].ID.String()); err != nil {
+		t.Errorf("GetInvoice() of the caller's invoice error = %v", err)
+	}
+	if _, err := uc.GetInvoice(user, invoices.invoices[1].ID.String()); errorCode(err) != domain.ErrCodeUnauthorized {
+		t.Errorf("GetInvoice() of another user's invoice error = %v, want unauthorized", err)
+	}
+
+	// Admins read any user's invoices, and every user's at once
+	if _, err := uc.GetInvoice(admin, invoices.invoices[1].ID.String()); err != nil {
+		t.Errorf("GetInvoice() as admin error = %v", err)
+	}
+	if page, err := uc.ListInvoices(admin, domain.InvoiceFilter{}, 0, ""); err != nil || len(page.Invoices) != 2 {
+		t.Errorf("ListInvoices() as admin = %+v, %v, want every invoice", page, err)
+	}
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// fullRefundID identifies the refund posted when a payment moves to
+// refunded without a refund of its own
+const fullRefundID = "full"
+
+// LedgerUseCase records money movements in the double-entry ledger and
+// answers balance queries
+type LedgerUseCase struct {
+	ledgerRepo  repo.LedgerRepository
+	paymentRepo repo.PaymentRepository
+}
+
+// NewLedgerUseCase creates a new ledger use case
+func NewLedgerUseCase(ledgerRepo repo.LedgerRepository, paymentRepo repo.PaymentRepository) *LedgerUseCase {
+	return &LedgerUseCase{
+		ledgerRepo:  ledgerRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// UpdatePaymentStatus moves a payment to a new status and, in the same
+// transaction, posts the money movement the change implies: completing a
+// payment posts its charge, refunding it posts a refund of whatever was not
+// refunded yet. Other transitions move no money.
+func (uc *LedgerUseCase) UpdatePaymentStatus(ctx context.Context, payment *domain.Payment, status string, breakdown domain.ChargeBreakdown) error {
+	var entry *domain.JournalEntry
+	switch domain.PaymentStatus(status) {
+	case domain.PaymentStatusCompleted:
+		entry = domain.NewChargeEntry(payment, breakdown)
+	case domain.PaymentStatusRefunded:
+		charge, refundedCents, err := uc.paymentHistory(ctx, payment)
+		if err != nil {
+			return err
+		}
+		if remaining := dollarsToCents(payment.Amount) - refundedCents; remaining > 0 {
+			entry = domain.NewRefundEntry(payment, charge, fullRefundID, remaining)
+		}
+	}
+	if entry != nil && len(entry.Postings) == 0 {
+		// Nothing changed hands, e.g. a fully discounted payment
+		entry = nil
+	}
+
+	return uc.ledgerRepo.UpdatePaymentStatus(ctx, payment.ID.String(), status, entry)
+}
+
+// RecordRefund posts a partial or full refund of a payment. Refunds beyond
+// what is left of the payment are rejected.
+func (uc *LedgerUseCase) RecordRefund(ctx context.Context, paymentID, refundID string, amountCents int64) (*domain.JournalEntry, error) {
+	if refundID == "" {
+		return nil, domain.NewInvalidInputError("invalid refund", "refund_id is required")
+	}
+	if amountCents <= 0 {
+		return nil, domain.NewInvalidInputError("invalid refund", fmt.Sprintf("amount must be positive: %d", amountCents))
+	}
+
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	charge, refundedCents, err := uc.paymentHistory(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := dollarsToCents(payment.Amount) - refundedCents; amountCents > remaining {
+		return nil, domain.NewInvalidInputError("invalid refund", fmt.Sprintf("amount %d exceeds the %d left to refund", amountCents, remaining))
+	}
+
+	entry := domain.NewRefundEntry(payment, charge, refundID, amountCents)
+	if _, err := uc.ledgerRepo.Post(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ApplyRefund posts a refund the billing provider reported and, once the
+// payment is refunded in full, marks it refunded in the same transaction.
+// Redeliveries of a refund post nothing, as do refunds of a payment already
+// refunded. It reports whether the payment was marked refunded.
+func (uc *LedgerUseCase) ApplyRefund(ctx context.Context, payment *domain.Payment, refundID string, amountCents int64) (bool, error) {
+	if refundID == "" {
+		return false, domain.NewInvalidInputError("invalid refund", "refund_id is required")
+	}
+	if amountCents <= 0 {
+		return false, domain.NewInvalidInputError("invalid refund", fmt.Sprintf("amount must be positive: %d", amountCents))
+	}
+	if payment.Status == string(domain.PaymentStatusRefunded) {
+		return false, nil
+	}
+	if _, err := uc.ledgerRepo.GetEntryByReference(ctx, domain.RefundReference(payment.ID, refundID)); err == nil {
+		return false, nil
+	} else if !isNotFound(err) {
+		return false, err
+	}
+
+	charge, refundedCents, err := uc.paymentHistory(ctx, payment)
+	if err != nil {
+		return false, err
+	}
+	remaining := dollarsToCents(payment.Amount) - refundedCents
+	if amountCents < remaining {
+		_, err := uc.RecordRefund(ctx, payment.ID.String(), refundID, amountCents)
+		return false, err
+	}
+
+	var entry *domain.JournalEntry
+	if remaining > 0 {
+		entry = domain.NewRefundEntry(payment, charge, refundID, remaining)
+	}
+	if err := uc.ledgerRepo.UpdatePaymentStatus(ctx, payment.ID.String(), string(domain.PaymentStatusRefunded), entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecordProviderFee posts a fee the provider charged for a payment outside
+// of its charge; reference identifies the fee at the provider
+func (uc *LedgerUseCase) RecordProviderFee(ctx context.Context, paymentID, reference string, feeCents int64) (*domain.JournalEntry, error) {
+	if reference == "" {
+		return nil, domain.NewInvalidInputError("invalid provider fee", "reference is required")
+	}
+	if feeCents <= 0 {
+		return nil, domain.NewInvalidInputError("invalid provider fee", fmt.Sprintf("fee must be positive: %d", feeCents))
+	}
+
+	return uc.postForPayment(ctx, paymentID, func(payment *domain.Payment) *domain.JournalEntry {
+		return domain.NewProviderFeeEntry(payment, reference, feeCents)
+	})
+}
+
+// OpenDispute posts the funds the provider withheld when a payment was
+// disputed, together with the dispute fee
+func (uc *LedgerUseCase) OpenDispute(ctx context.Context, paymentID, disputeID string, amountCents, feeCents int64) (*domain.JournalEntry, error) {
+	if disputeID == "" {
+		return nil, domain.NewInvalidInputError("invalid dispute", "dispute_id is required")
+	}
+	if amountCents <= 0 || feeCents < 0 {
+		return nil, domain.NewInvalidInputError("invalid dispute", fmt.Sprintf("amount must be positive and fee not negative: %d, %d", amountCents, feeCents))
+	}
+
+	return uc.postForPayment(ctx, paymentID, func(payment *domain.Payment) *domain.JournalEntry {
+		return domain.NewDisputeOpenedEntry(payment, disputeID, amountCents, feeCents)
+	})
+}
+
+// CloseDispute releases the funds withheld for a dispute, back to the
+// provider balance when it was won and to losses when it was lost
+func (uc *LedgerUseCase) CloseDispute(ctx context.Context, paymentID, disputeID string, won bool) (*domain.JournalEntry, error) {
+	opened, err := uc.ledgerRepo.GetEntryByReference(ctx, domain.DisputeReference(disputeID, "opened"))
+	if err != nil {
+		return nil, err
+	}
+	amountCents := opened.Sum(domain.AccountDisputeReserve, domain.PostingDebit)
+
+	return uc.postForPayment(ctx, paymentID, func(payment *domain.Payment) *domain.JournalEntry {
+		return domain.NewDisputeClosedEntry(payment, disputeID, amountCents, won)
+	})
+}
+
+// CustomerBalances returns the balance of every account a customer's money
+// moved through, per currency
+func (uc *LedgerUseCase) CustomerBalances(ctx context.Context, customerID string) ([]domain.AccountBalance, error) {
+	if customerID == "" {
+		return nil, domain.NewInvalidInputError("invalid balance query", "customer_id is required")
+	}
+	return uc.ledgerRepo.Balances(ctx, customerID, "")
+}
+
+// AccountBalances returns the balance of an account per currency, or of
+// every account when accountCode is empty
+func (uc *LedgerUseCase) AccountBalances(ctx context.Context, accountCode string) ([]domain.AccountBalance, error) {
+	return uc.ledgerRepo.Balances(ctx, "", accountCode)
+}
+
+// TrialBalance returns every account balance together with the entries
+// whose debits and credits differ
+func (uc *LedgerUseCase) TrialBalance(ctx context.Context) (*domain.TrialBalance, error) {
+	balances, err := uc.ledgerRepo.Balances(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+	unbalanced, err := uc.ledgerRepo.ListUnbalancedEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.TrialBalance{Accounts: balances, Unbalanced: unbalanced}, nil
+}
+
+// postForPayment loads a payment and posts the entry built for it
+func (uc *LedgerUseCase) postForPayment(ctx context.Context, paymentID string, build func(*domain.Payment) *domain.JournalEntry) (*domain.JournalEntry, error) {
+	payment, err := uc.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := build(payment)
+	if _, err := uc.ledgerRepo.Post(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// paymentHistory returns a payment's charge entry, nil when it was never
+// posted, and the total refunded so far
+func (uc *LedgerUseCase) paymentHistory(ctx context.Context, payment *domain.Payment) (*domain.JournalEntry, int64, error) {
+	entries, err := uc.ledgerRepo.ListEntriesByPayment(ctx, payment.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		charge        *domain.JournalEntry
+		refundedCents int64
+	)
+	for _, entry := range entries {
+		switch entry.Kind {
+		case domain.JournalEntryCharge:
+			charge = entry
+		case domain.JournalEntryRefund:
+			refundedCents += entry.Sum(domain.AccountProviderClearing, domain.PostingCredit)
+		}
+	}
+	return charge, refundedCents, nil
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// memoryLedgerRepo keeps posted entries and payment statuses in memory
+type memoryLedgerRepo struct {
+	repo.LedgerRepository
+	entries  []*domain.JournalEntry
+	statuses map[string]string
+}
+
+func (r *memoryLedgerRepo) Post(ctx context.Context, entry *domain.JournalEntry) (bool, error) {
+	if err := entry.Validate(); err != nil {
+		return false, err
+	}
+	for _, e := range r.entries {
+		if e.Reference == entry.Reference {
+			return false, nil
+		}
+	}
+	r.entries = append(r.entries, entry)
+	return true, nil
+}
+
+func (r *memoryLedgerRepo) UpdatePaymentStatus(ctx context.Context, paymentID, status string, entry *domain.JournalEntry) error {
+	if entry != nil {
+		if _, err := r.Post(ctx, entry); err != nil {
+			return err
+		}
+	}
+	if r.statuses == nil {
+		r.statuses = make(map[string]string)
+	}
+	r.statuses[paymentID] = status
+	return nil
+}
+
+func (r *memoryLedgerRepo) GetEntryByReference(ctx context.Context, reference string) (*domain.JournalEntry, error) {
+	for _, e := range r.entries {
+		if e.Reference == reference {
+			return e, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("journal entry", reference)
+}
+
+func (r *memoryLedgerRepo) ListEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]*domain.JournalEntry, error) {
+	var entries []*domain.JournalEntry
+	for _, e := range r.entries {
+		if e.PaymentID != nil && *e.PaymentID == paymentID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// balance returns an account's balance on its debit side
+func (r *memoryLedgerRepo) balance(account string) int64 {
+	var cents int64
+	for _, e := range r.entries {
+		cents += e.Sum(account, domain.PostingDebit) - e.Sum(account, domain.PostingCredit)
+	}
+	return cents
+}
+
+// ledgerPaymentRepo serves a single payment
+type ledgerPaymentRepo struct {
+	repo.PaymentRepository
+	payment *domain.Payment
+}
+
+func (r *ledgerPaymentRepo) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
+	if r.payment.ID.String() != id {
+		return nil, domain.NewNotFoundError("payment", id)
+	}
+	return r.payment, nil
+}
+
+func (r *ledgerPaymentRepo) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	if r.payment.OrderID != orderID {
+		return nil, domain.NewNotFoundError("payment", orderID)
+	}
+	return r.payment, nil
+}
+
+func TestLedgerUseCase_PaymentLifecycle(t *testing.T) {
+	ctx := context.Background()
+	payment := &domain.Payment{ID: uuid.New(), Amount: 12, Currency: "USD", CustomerID: "user-1"}
+	ledger := &memoryLedgerRepo{}
+	uc := NewLedgerUseCase(ledger, &ledgerPaymentRepo{payment: payment})
+
+	breakdown := domain.ChargeBreakdown{TaxCents: 200, FeeCents: 65}
+	if err := uc.UpdatePaymentStatus(ctx, payment, string(domain.PaymentStatusCompleted), breakdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A replayed completion posts nothing new
+	if err := uc.UpdatePaymentStatus(ctx, payment, string(domain.PaymentStatusCompleted), breakdown); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ledger.entries) != 1 || ledger.statuses[payment.ID.String()] != "completed" {
+		t.Fatalf("expected one charge and a completed payment, got %d entries", len(ledger.entries))
+	}
+	if got := ledger.balance(domain.AccountProviderClearing); got != 1135 {
+		t.Errorf("expected 1135 held by the provider, got %d", got)
+	}
+
+	if _, err := uc.RecordRefund(ctx, payment.ID.String(), "re_1", 300); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.RecordRefund(ctx, payment.ID.String(), "re_2", 1000); err == nil {
+		t.Error("expected an error when refunding more than is left")
+	}
+
+	// Refunding the payment refunds the remainder
+	if err := uc.UpdatePaymentStatus(ctx, payment, string(domain.PaymentStatusRefunded), domain.ChargeBreakdown{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	full, err := ledger.GetEntryByReference(ctx, domain.RefundReference(payment.ID, fullRefundID))
+	if err != nil {
+		t.Fatalf("expected a full refund entry: %v", err)
+	}
+	if full.Sum(domain.AccountProviderClearing, domain.PostingCredit) != 900 || full.Sum(domain.AccountTaxPayable, domain.PostingDebit) != 150 {
+		t.Errorf("unexpected full refund postings: %+v", full.Postings)
+	}
+	if got := ledger.balance(domain.AccountTaxPayable); got != 0 {
+		t.Errorf("expected all tax to be refunded, got %d", got)
+	}
+	if got := ledger.balance(domain.AccountProviderClearing); got != -65 {
+		t.Errorf("expected only the fee to be lost, got %d", got)
+	}
+
+	var debits, credits int64
+	for _, e := range ledger.entries {
+		for _, p := range e.Postings {
+			if p.Direction == domain.PostingDebit {
+				debits += p.AmountCents
+			} else {
+				credits += p.AmountCents
+			}
+		}
+	}
+	if debits != credits {
+		t.Errorf("expected debits to equal credits, got %d and %d", debits, credits)
+	}
+}
+
+func TestLedgerUseCase_Disputes(t *testing.T) {
+	ctx := context.Background()
+	payment := &domain.Payment{ID: uuid.New(), Amount: 10, Currency: "USD"}
+	ledger := &memoryLedgerRepo{}
+	uc := NewLedgerUseCase(ledger, &ledgerPaymentRepo{payment: payment})
+
+	if _, err := uc.CloseDispute(ctx, payment.ID.String(), "dp_1", true); err == nil {
+		t.Error("expected an error closing a dispute that was never opened")
+	}
+	if _, err := uc.OpenDispute(ctx, payment.ID.String(), "dp_1", 1000, 1500); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lost, err := uc.CloseDispute(ctx, payment.ID.String(), "dp_1", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lost.Sum(domain.AccountDisputeLosses, domain.PostingDebit) != 1000 {
+		t.Errorf("unexpected lost dispute postings: %+v", lost.Postings)
+	}
+	if got := ledger.balance(domain.AccountDisputeReserve); got != 0 {
+		t.Errorf("expected the reserve to be released, got %d", got)
+	}
+
+	if _, err := uc.RecordProviderFee(ctx, payment.ID.String(), "", 30); err == nil {
+		t.Error("expected an error for a fee without a reference")
+	}
+	if _, err := uc.OpenDispute(ctx, uuid.NewString(), "dp_2", 1000, 0); err == nil {
+		t.Error("expected an error for an unknown payment")
+	}
+}
+
+func TestCheckoutUseCase_ReversalWebhooksPostToLedger(t *testing.T) {
+	ctx := context.Background()
+	payment := &domain.Payment{ID: uuid.New(), Amount: 12, Currency: "USD", CustomerID: "user-1", OrderID: "cs_1"}
+	payments := &ledgerPaymentRepo{payment: payment}
+	ledger := &memoryLedgerRepo{}
+	ledgerUseCase := NewLedgerUseCase(ledger, payments)
+	if err := ledgerUseCase.UpdatePaymentStatus(ctx, payment, string(domain.PaymentStatusCompleted), domain.ChargeBreakdown{TaxCents: 200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payment.Status = string(domain.PaymentStatusCompleted)
+
+	uc := NewCheckoutUseCase(nil, nil, nil, payments, nil, nil,
+		nil, "stripe", DefaultCheckoutExpiryConfig(), nil, nil, nil, CheckoutOptions{Ledger: ledgerUseCase})
+	apply := func(wr billing.WebhookResult) {
+		t.Helper()
+		wr.SessionID = "cs_1"
+		if err := uc.ApplyWebhook(ctx, wr); err != nil {
+			t.Fatalf("ApplyWebhook() error = %v", err)
+		}
+	}
+	refund := func(reference string, amount float64) billing.WebhookResult {
+		return billing.WebhookResult{EventType: string(billing.WebhookEventTypePaymentRefunded), Status: "refunded", Reference: reference, Amount: amount}
+	}
+
+	apply(billing.WebhookResult{EventType: string(billing.WebhookEventTypePaymentChargeback), Status: "chargeback", Reference: "dp_1", Amount: 12, FeeAmount: 15})
+	if got := ledger.balance(domain.AccountDisputeReserve); got != 1200 {
+		t.Errorf("expected the chargeback to withhold 1200, got %d", got)
+	}
+
+	// A partial refund is posted once however often it is delivered, and a
+	// refund that failed at the provider posts nothing
+	apply(refund("re_1", 3))
+	apply(refund("re_1", 3))
+	apply(billing.WebhookResult{EventType: string(billing.WebhookEventTypePaymentRefunded), Status: "failed", Reference: "re_2", Amount: 9})
+	if _, err := ledger.GetEntryByReference(ctx, domain.RefundReference(payment.ID, "re_1")); err != nil {
+		t.Fatalf("expected the partial refund to be posted: %v", err)
+	}
+	if len(ledger.entries) != 3 || ledger.statuses[payment.ID.String()] != "completed" {
+		t.Fatalf("expected a charge, dispute and refund of a completed payment, got %d entries, %s", len(ledger.entries), ledger.statuses[payment.ID.String()])
+	}
+
+	// Refunding the rest marks the payment refunded
+	apply(refund("re_3", 9))
+	if ledger.statuses[payment.ID.String()] != "refunded" {
+		t.Errorf("expected the payment to be refunded, got %s", ledger.statuses[payment.ID.String()])
+	}
+	if got := ledger.balance(domain.AccountTaxPayable); got != 0 {
+		t.Errorf("expected all tax to be refunded, got %d", got)
+	}
+
+	// Reversals of payments that were never recorded are acknowledged
+	if err := uc.ApplyWebhook(ctx, billing.WebhookResult{EventType: string(billing.WebhookEventTypePaymentRefunded), SessionID: "cs_unknown", Reference: "re_4", Amount: 1}); err != nil {
+		t.Errorf("expected an unknown payment's refund to be acknowledged, got %v", err)
+	}
+}
@@ -7,19 +7,27 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
 // PaymentUseCase provides business logic for payment operations
 type PaymentUseCase struct {
-	paymentRepo repo.PaymentRepository
+	paymentRepo   repo.PaymentRepository
+	ledgerUseCase *LedgerUseCase            // Can be nil if the ledger is disabled
+	auditor       *audit.Recorder           // Can be nil if auditing is disabled
+	metrics       *metrics.MetricsCollector // Can be nil if metrics are disabled
 }
 
 // NewPaymentUseCase creates a new payment use case
-func NewPaymentUseCase(paymentRepo repo.PaymentRepository) *PaymentUseCase {
+func NewPaymentUseCase(paymentRepo repo.PaymentRepository, ledgerUseCase *LedgerUseCase, auditor *audit.Recorder, metricsCollector *metrics.MetricsCollector) *PaymentUseCase {
 	return &PaymentUseCase{
-		paymentRepo: paymentRepo,
+		paymentRepo:   paymentRepo,
+		ledgerUseCase: ledgerUseCase,
+		auditor:       auditor,
+		metrics:       metricsCollector,
 	}
 }
 
@@ -97,11 +105,26 @@ func (uc *PaymentUseCase) UpdatePaymentStatus(ctx context.Context, id string, st
 		return domain.NewInvalidInputError("invalid payment status", fmt.Sprintf("status: %s", status))
 	}
 
-	// Update status
-	if err := uc.paymentRepo.UpdateStatus(ctx, id, status); err != nil {
+	payment, err := uc.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment == nil {
+		return domain.NewNotFoundError("payment", id)
+	}
+
+	// Update status, posting the money movement it implies to the ledger
+	if uc.ledgerUseCase != nil {
+		if err := uc.ledgerUseCase.UpdatePaymentStatus(ctx, payment, status, domain.ChargeBreakdown{}); err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+	} else if err := uc.paymentRepo.UpdateStatus(ctx, id, status); err != nil {
 		return fmt.Errorf("failed to update payment status: %w", err)
 	}
 
+	recordPaymentStatusChange(ctx, uc.auditor, payment, status)
+	uc.recordOutcome(ctx, payment, status)
+
 	// TODO: Publish payment status updated event
 
 	return nil
@@ -132,6 +155,19 @@ func (uc *PaymentUseCase) CompletePayment(ctx context.Context, id string, extern
 	return nil
 }
 
+// recordOutcome records payment metrics once a payment reaches a terminal
+// completed or failed status
+func (uc *PaymentUseCase) recordOutcome(ctx context.Context, payment *domain.Payment, status string) {
+	if uc.metrics == nil {
+		return
+	}
+	if status != string(domain.PaymentStatusCompleted) && status != string(domain.PaymentStatusFailed) {
+		return
+	}
+
+	uc.metrics.RecordPayment(ctx, status == string(domain.PaymentStatusCompleted), payment.Amount, time.Since(payment.CreatedAt))
+}
+
 // GetPaymentsByCustomer retrieves payments for a customer
 func (uc *PaymentUseCase) GetPaymentsByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*domain.PaymentResponse, error) {
 	payments, err := uc.paymentRepo.GetByCustomerID(ctx, customerID, limit, offset)
@@ -216,3 +252,140 @@ func (uc *PaymentUseCase) CountPayments(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+const (
+	defaultPaymentPageSize = 50
+	maxPaymentPageSize     = 1000
+)
+
+// SearchPayments returns one page of payments matching the query, together
+// with the total match count and a token for the next page. A page token
+// takes precedence over the query's offset and must have been issued for the
+// same sort order.
+func (uc *PaymentUseCase) SearchPayments(ctx context.Context, query domain.PaymentListQuery, pageToken string) (*domain.PaymentPage, error) {
+	if err := query.Filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	if query.SortBy == "" {
+		query.SortBy = domain.PaymentSortByCreatedAt
+	}
+	if query.SortBy != domain.PaymentSortByCreatedAt && query.SortBy != domain.PaymentSortByAmount {
+		return nil, domain.NewInvalidInputError("invalid sort field", fmt.Sprintf("sort_by: %s", query.SortBy))
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultPaymentPageSize
+	}
+	if query.Limit > maxPaymentPageSize {
+		query.Limit = maxPaymentPageSize
+	}
+	if query.Offset < 0 {
+		return nil, domain.NewInvalidInputError("invalid offset", "offset must not be negative")
+	}
+
+	cursor, err := domain.DecodePageToken(pageToken)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil && (cursor.SortBy != query.SortBy || cursor.SortDesc != query.SortDesc) {
+		return nil, domain.NewInvalidInputError("invalid page token", "token was issued for a different sort order")
+	}
+	query.Cursor = cursor
+
+	// Fetch one extra row to learn whether another page follows
+	pageSize := query.Limit
+	query.Limit = pageSize + 1
+	payments, err := uc.paymentRepo.ListFiltered(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search payments: %w", err)
+	}
+
+	total, err := uc.paymentRepo.CountFiltered(ctx, query.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count payments: %w", err)
+	}
+
+	page := &domain.PaymentPage{Total: total}
+	if len(payments) > pageSize {
+		payments = payments[:pageSize]
+		page.NextPageToken = domain.EncodePageToken(domain.CursorAfter(payments[pageSize-1], query.SortBy, query.SortDesc))
+	}
+
+	page.Payments = make([]*domain.PaymentResponse, len(payments))
+	for i, payment := range payments {
+		page.Payments[i] = toPaymentResponse(payment)
+	}
+
+	return page, nil
+}
+
+// exportBatchSize is the number of rows fetched per round trip while exporting
+const exportBatchSize = 500
+
+// ExportPayments calls fn for every payment matching the query's filter, in
+// the query's sort order. Rows are fetched in keyset batches so memory use
+// stays bounded regardless of the result size. Limit, offset and cursor on
+// the query are ignored. Iteration stops at the first error from fn.
+func (uc *PaymentUseCase) ExportPayments(ctx context.Context, query domain.PaymentListQuery, fn func(*domain.PaymentResponse) error) error {
+	if err := query.Filter.Validate(); err != nil {
+		return err
+	}
+	if query.SortBy == "" {
+		query.SortBy = domain.PaymentSortByCreatedAt
+	}
+	if query.SortBy != domain.PaymentSortByCreatedAt && query.SortBy != domain.PaymentSortByAmount {
+		return domain.NewInvalidInputError("invalid sort field", fmt.Sprintf("sort_by: %s", query.SortBy))
+	}
+
+	query.Limit = exportBatchSize
+	query.Offset = 0
+	query.Cursor = nil
+
+	for {
+		payments, err := uc.paymentRepo.ListFiltered(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to export payments: %w", err)
+		}
+
+		for _, payment := range payments {
+			if err := fn(toPaymentResponse(payment)); err != nil {
+				return err
+			}
+		}
+
+		if len(payments) < exportBatchSize {
+			return nil
+		}
+		query.Cursor = domain.CursorAfter(payments[len(payments)-1], query.SortBy, query.SortDesc)
+	}
+}
+
+// toPaymentResponse converts a payment to its response representation
+func toPaymentResponse(payment *domain.Payment) *domain.PaymentResponse {
+	return &domain.PaymentResponse{
+		ID:                payment.ID,
+		Amount:            payment.Amount,
+		Currency:          payment.Currency,
+		Status:            payment.Status,
+		PaymentMethod:     payment.PaymentMethod,
+		CustomerID:        payment.CustomerID,
+		OrderID:           payment.OrderID,
+		Description:       payment.Description,
+		ExternalPaymentID: payment.ExternalPaymentID,
+		CreatedAt:         payment.CreatedAt,
+		UpdatedAt:         payment.UpdatedAt,
+	}
+}
+
+// recordPaymentStatusChange audits a payment's move to a new status
+func recordPaymentStatusChange(ctx context.Context, auditor *audit.Recorder, payment *domain.Payment, status string) {
+	updated := *payment
+	updated.Status = status
+	auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityPayment,
+		EntityID:   payment.ID.String(),
+		Action:     domain.AuditActionStatusChange,
+		Before:     payment,
+		After:      &updated,
+	})
+}
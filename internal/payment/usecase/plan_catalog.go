@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// PlanCatalogUseCase manages the plan catalog. Changes to a plan's price or
+// features create a new plan version; subscriptions keep the version they
+// were sold at. Feature codes and usage limits are checked against the
+// feature catalog.
+type PlanCatalogUseCase struct {
+	planRepo repo.PlanRepository
+	features *FeatureCatalogUseCase
+	auditor  *audit.Recorder // Can be nil if auditing is disabled
+}
+
+// NewPlanCatalogUseCase creates a new plan catalog use case
+func NewPlanCatalogUseCase(planRepo repo.PlanRepository, features *FeatureCatalogUseCase, auditor *audit.Recorder) *PlanCatalogUseCase {
+	return &PlanCatalogUseCase{
+		planRepo: planRepo,
+		features: features,
+		auditor:  auditor,
+	}
+}
+
+// CreatePlan validates and adds a new plan to the catalog at version 1
+func (uc *PlanCatalogUseCase) CreatePlan(ctx context.Context, plan domain.Plan) (*domain.Plan, error) {
+	plan.Currency = strings.ToUpper(plan.Currency)
+	plan.Active = true
+	plan.Version = 1
+	plan.ArchivedAt = nil
+
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	if err := uc.features.ValidatePlan(ctx, plan, nil); err != nil {
+		return nil, err
+	}
+
+	created, err := uc.planRepo.Create(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityPlan,
+		EntityID:   created.Code,
+		Action:     domain.AuditActionCreate,
+		After:      created,
+	})
+	log.Info(ctx, "Plan created",
+		zap.String("plan_id", created.Code),
+		zap.Int32("version", created.Version))
+
+	return &created, nil
+}
+
+// UpdatePlan applies a partial update to a plan. It reports whether the
+// update created a new plan version.
+func (uc *PlanCatalogUseCase) UpdatePlan(ctx context.Context, id string, update domain.PlanUpdate) (*domain.Plan, bool, error) {
+	current, err := uc.planRepo.GetByIDIncludingArchived(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if current.IsArchived() {
+		return nil, false, domain.NewInvalidStateError("plan is archived", fmt.Sprintf("plan_id: %s", id))
+	}
+
+	plan := update.Apply(current)
+	plan.Currency = strings.ToUpper(plan.Currency)
+	if err := plan.Validate(); err != nil {
+		return nil, false, err
+	}
+	if err := uc.features.ValidatePlan(ctx, plan, &current); err != nil {
+		return nil, false, err
+	}
+
+	updated, err := uc.planRepo.Update(ctx, plan)
+	if err != nil {
+		return nil, false, err
+	}
+
+	newVersion := updated.Version != current.Version
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityPlan,
+		EntityID:   updated.Code,
+		Action:     domain.AuditActionUpdate,
+		Before:     current,
+		After:      updated,
+	})
+	log.Info(ctx, "Plan updated",
+		zap.String("plan_id", updated.Code),
+		zap.Int32("version", updated.Version),
+		zap.Bool("new_version", newVersion))
+
+	return &updated, newVersion, nil
+}
+
+// ArchivePlan stops a plan from being sold. Existing subscriptions are unaffected.
+func (uc *PlanCatalogUseCase) ArchivePlan(ctx context.Context, id string) (*domain.Plan, error) {
+	current, err := uc.planRepo.GetByIDIncludingArchived(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	archived, err := uc.planRepo.Archive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityPlan,
+		EntityID:   archived.Code,
+		Action:     domain.AuditActionArchive,
+		Before:     current,
+		After:      archived,
+	})
+
+	log.Info(ctx, "Plan archived", zap.String("plan_id", archived.Code))
+
+	return &archived, nil
+}
+
+// GetPlan retrieves a plan, archived or not, with its version history
+func (uc *PlanCatalogUseCase) GetPlan(ctx context.Context, id string) (*domain.Plan, []domain.PlanVersion, error) {
+	plan, err := uc.planRepo.GetByIDIncludingArchived(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	versions, err := uc.planRepo.ListVersions(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list plan versions: %w", err)
+	}
+
+	return &plan, versions, nil
+}
+
+// ListPlans lists catalog plans, optionally including archived ones
+func (uc *PlanCatalogUseCase) ListPlans(ctx context.Context, includeArchived bool) ([]domain.Plan, error) {
+	plans, err := uc.planRepo.List(ctx, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+	return plans, nil
+}
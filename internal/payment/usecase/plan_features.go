@@ -15,70 +15,114 @@ import (
 // PlanFeatureService handles plan-to-feature mapping and entitlement creation
 type PlanFeatureService struct {
 	planRepo repo.PlanRepository
+	features *FeatureCatalogUseCase // Can be nil if no entitlements are granted through the service
 	logger   *zap.Logger
 }
 
 // NewPlanFeatureService creates a new plan feature service
-func NewPlanFeatureService(planRepo repo.PlanRepository) *PlanFeatureService {
+func NewPlanFeatureService(planRepo repo.PlanRepository, features *FeatureCatalogUseCase) *PlanFeatureService {
 	return &PlanFeatureService{
 		planRepo: planRepo,
+		features: features,
 		logger:   log.L(context.Background()),
 	}
 }
 
-// GrantEntitlementsForPlan grants all entitlements for a given plan
-func (pfs *PlanFeatureService) GrantEntitlementsForPlan(ctx context.Context, userID string, planIDString string, familyID *string, subscriptionID *string, expiresAt *time.Time) ([]string, error) {
-	// Get plan details from database
-	plan, err := pfs.planRepo.GetByID(ctx, planIDString)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get plan %s: %w", planIDString, err)
+// EntitlementsForPlan returns the entitlements a plan grants, one per
+// feature, resolved through the feature catalog. Each carries the feature's
+// default limits, overridden by the plan's usage limits for the keys the
+// feature governs. Feature codes missing from the catalog are skipped. A
+// non-zero planVersion grants the features and limits of that version, so
+// checkouts and subscriptions keep what they were sold.
+func (pfs *PlanFeatureService) EntitlementsForPlan(ctx context.Context, userID string, planIDString string, planVersion int32, familyID *string, subscriptionID *string, expiresAt *time.Time) ([]domain.Entitlement, error) {
+	if pfs.features == nil {
+		return nil, fmt.Errorf("no feature catalog to resolve the features of plan %s", planIDString)
 	}
 
-	// Convert plan ID string to UUID
-	planUUID := uuid.NewSHA1(uuid.NameSpaceOID, []byte(planIDString))
-
-	grantedFeatures := make([]string, 0, len(plan.FeatureCodes))
+	plan, err := pfs.PlanAtVersion(ctx, planIDString, planVersion)
+	if err != nil {
+		return nil, err
+	}
 
-	// Grant entitlements for each feature in the plan
+	now := time.Now()
+	entitlements := make([]domain.Entitlement, 0, len(plan.FeatureCodes))
 	for _, featureCode := range plan.FeatureCodes {
-		// Create entitlement for this feature
+		feature, err := pfs.features.Resolve(ctx, featureCode)
+		if err != nil {
+			if domainErr := domain.GetDomainError(err); domainErr != nil && domainErr.Code == domain.ErrCodeNotFound {
+				pfs.logger.Warn("Plan includes a feature missing from the catalog, not granting it",
+					zap.String("plan_id", planIDString),
+					zap.String("feature_code", featureCode))
+				continue
+			}
+			return nil, err
+		}
+
 		entitlement := domain.Entitlement{
 			ID:          uuid.New(),
 			UserID:      userID,
 			FamilyID:    familyID,
-			FeatureCode: featureCode,
-			PlanID:      planUUID,
+			FeatureCode: feature.Code,
+			PlanID:      plan.ID,
 			Status:      "active",
-			GrantedAt:   time.Now(),
+			GrantedAt:   now,
 			ExpiresAt:   expiresAt,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			UsageLimits: feature.Limits(plan.UsageLimits),
+			CreatedAt:   now,
+			UpdatedAt:   now,
 		}
 
 		// Set subscription ID if provided
-		if subscriptionID != nil {
+		if subscriptionID != nil && *subscriptionID != "" {
 			entitlement.SubscriptionID = subscriptionID
 		}
 
-		// Insert entitlement (this will be handled by the entitlement use case)
-		grantedFeatures = append(grantedFeatures, featureCode)
+		entitlements = append(entitlements, entitlement)
+	}
+
+	return entitlements, nil
+}
 
-		pfs.logger.Info("Granting entitlement",
-			zap.String("user_id", userID),
-			zap.String("plan_id", planIDString),
-			zap.String("feature_code", featureCode),
-			zap.String("family_id", getStringValue(familyID)))
+// PlanAtVersion returns a plan, archived or not, as it was sold at version.
+// Archived plans still grant their features to checkouts and subscriptions
+// sold before the plan was archived. A zero version returns the current one.
+func (pfs *PlanFeatureService) PlanAtVersion(ctx context.Context, planIDString string, version int32) (domain.Plan, error) {
+	plan, err := pfs.planRepo.GetByIDIncludingArchived(ctx, planIDString)
+	if err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to get plan %s: %w", planIDString, err)
+	}
+	if version == 0 || version == plan.Version {
+		return plan, nil
 	}
 
-	return grantedFeatures, nil
+	pinned, err := pfs.planRepo.GetVersion(ctx, planIDString, version)
+	if err != nil {
+		return domain.Plan{}, fmt.Errorf("failed to get version %d of plan %s: %w", version, planIDString, err)
+	}
+	return plan.AtVersion(pinned), nil
 }
 
-// GetPlanFeatures returns the feature codes for a given plan
+// GetPlanFeatures returns the feature codes for a given plan, archived or not
 func (pfs *PlanFeatureService) GetPlanFeatures(ctx context.Context, planIDString string) ([]string, error) {
-	plan, err := pfs.planRepo.GetByID(ctx, planIDString)
+	plan, err := pfs.planRepo.GetByIDIncludingArchived(ctx, planIDString)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get plan %s: %w", planIDString, err)
 	}
 
 	return plan.FeatureCodes, nil
 }
+
+// CatalogPlans returns every catalog plan, archived ones included, keyed by
+// plan UUID
+func (pfs *PlanFeatureService) CatalogPlans(ctx context.Context) (map[uuid.UUID]domain.Plan, error) {
+	plans, err := pfs.planRepo.List(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	byID := make(map[uuid.UUID]domain.Plan, len(plans))
+	for _, plan := range plans {
+		byID[plan.ID] = plan
+	}
+	return byID, nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/jia-app/paymentservice/internal/payment/audit"
 	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/jia-app/paymentservice/internal/payment/repo"
 	"github.com/jia-app/paymentservice/internal/shared/log"
@@ -15,12 +16,14 @@ import (
 // PricingZoneUseCase provides business logic for pricing zone operations
 type PricingZoneUseCase struct {
 	pricingZoneRepo repo.PricingZoneRepository
+	auditor         *audit.Recorder // Can be nil if auditing is disabled
 }
 
 // NewPricingZoneUseCase creates a new pricing zone use case
-func NewPricingZoneUseCase(pricingZoneRepo repo.PricingZoneRepository) *PricingZoneUseCase {
+func NewPricingZoneUseCase(pricingZoneRepo repo.PricingZoneRepository, auditor *audit.Recorder) *PricingZoneUseCase {
 	return &PricingZoneUseCase{
 		pricingZoneRepo: pricingZoneRepo,
+		auditor:         auditor,
 	}
 }
 
@@ -168,6 +171,7 @@ func (uc *PricingZoneUseCase) UpsertPricingZone(ctx context.Context, zone domain
 		zone.ZoneName = domain.GetZoneName(zone.Zone)
 	}
 
+	before := uc.existingZone(ctx, zone.ISOCode)
 	savedZone, err := uc.pricingZoneRepo.Upsert(ctx, zone)
 	if err != nil {
 		log.Error(ctx, "Failed to upsert pricing zone",
@@ -175,6 +179,7 @@ func (uc *PricingZoneUseCase) UpsertPricingZone(ctx context.Context, zone domain
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to upsert pricing zone: %w", err)
 	}
+	uc.recordZoneChange(ctx, zone.ISOCode, before, &savedZone)
 
 	log.Info(ctx, "Pricing zone upserted successfully",
 		zap.String("iso_code", savedZone.ISOCode),
@@ -217,6 +222,11 @@ func (uc *PricingZoneUseCase) BulkUpsertPricingZones(ctx context.Context, zones
 		}
 	}
 
+	before := make([]*domain.PricingZone, len(zones))
+	for i, zone := range zones {
+		before[i] = uc.existingZone(ctx, zone.ISOCode)
+	}
+
 	err := uc.pricingZoneRepo.BulkUpsert(ctx, zones)
 	if err != nil {
 		log.Error(ctx, "Failed to bulk upsert pricing zones",
@@ -224,6 +234,9 @@ func (uc *PricingZoneUseCase) BulkUpsertPricingZones(ctx context.Context, zones
 			zap.Error(err))
 		return fmt.Errorf("failed to bulk upsert pricing zones: %w", err)
 	}
+	for i := range zones {
+		uc.recordZoneChange(ctx, zones[i].ISOCode, before[i], &zones[i])
+	}
 
 	log.Info(ctx, "Pricing zones bulk upserted successfully",
 		zap.Int("count", len(zones)))
@@ -239,6 +252,7 @@ func (uc *PricingZoneUseCase) DeletePricingZone(ctx context.Context, isoCode str
 		return fmt.Errorf("ISO code is required")
 	}
 
+	before := uc.existingZone(ctx, isoCode)
 	err := uc.pricingZoneRepo.Delete(ctx, isoCode)
 	if err != nil {
 		log.Error(ctx, "Failed to delete pricing zone",
@@ -246,9 +260,42 @@ func (uc *PricingZoneUseCase) DeletePricingZone(ctx context.Context, isoCode str
 			zap.Error(err))
 		return fmt.Errorf("failed to delete pricing zone: %w", err)
 	}
+	uc.recordZoneChange(ctx, isoCode, before, nil)
 
 	log.Info(ctx, "Pricing zone deleted successfully",
 		zap.String("iso_code", isoCode))
 
 	return nil
 }
+
+// existingZone returns the pricing zone stored for an ISO code before a
+// change, or nil when there is none or auditing is disabled
+func (uc *PricingZoneUseCase) existingZone(ctx context.Context, isoCode string) *domain.PricingZone {
+	if uc.auditor == nil {
+		return nil
+	}
+	zone, err := uc.pricingZoneRepo.GetByISOCode(ctx, isoCode)
+	if err != nil || zone.ISOCode == "" {
+		return nil
+	}
+	return &zone
+}
+
+// recordZoneChange audits the creation, update or deletion of a pricing zone
+func (uc *PricingZoneUseCase) recordZoneChange(ctx context.Context, isoCode string, before, after *domain.PricingZone) {
+	action := domain.AuditActionUpdate
+	switch {
+	case before == nil:
+		action = domain.AuditActionCreate
+	case after == nil:
+		action = domain.AuditActionDelete
+	}
+
+	uc.auditor.Record(ctx, audit.Change{
+		EntityType: domain.AuditEntityPricingZone,
+		EntityID:   isoCode,
+		Action:     action,
+		Before:     before,
+		After:      after,
+	})
+}
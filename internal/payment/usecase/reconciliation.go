@@ -0,0 +1,466 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// reconcileMatchSlack widens the provider listing on both sides of the
+// reconciled window, so that a record created just across an edge of the
+// window on one side is still matched on the other
+const reconcileMatchSlack = time.Hour
+
+// reconcilePageSize is the number of records fetched per round trip
+const reconcilePageSize = 100
+
+// expectedPaymentStatuses lists the local payment statuses consistent with
+// each provider charge status
+var expectedPaymentStatuses = map[billing.ChargeStatus][]domain.PaymentStatus{
+	billing.ChargeStatusSucceeded:      {domain.PaymentStatusCompleted},
+	billing.ChargeStatusRefunded:       {domain.PaymentStatusRefunded},
+	billing.ChargeStatusFailed:         {domain.PaymentStatusFailed, domain.PaymentStatusCancelled},
+	billing.ChargeStatusPending:        {domain.PaymentStatusPending},
+	billing.ChargeStatusRequiresAction: {domain.PaymentStatusPending},
+}
+
+// Reconciler compares the charges and subscriptions recorded by the billing
+// provider with local payments and subscriptions, catching drift left by
+// lost webhooks
+type Reconciler struct {
+	billingProvider  billing.Provider
+	providerName     string
+	paymentRepo      repo.PaymentRepository
+	subscriptionRepo repo.SubscriptionRepository
+	applyWebhook     func(context.Context, billing.WebhookResult) error // Re-applies missed events; nil disables healing
+}
+
+// NewReconciler creates a new reconciler. checkoutUseCase may be nil when
+// discrepancies are only reported, never healed.
+func NewReconciler(
+	billingProvider billing.Provider,
+	providerName string,
+	paymentRepo repo.PaymentRepository,
+	subscriptionRepo repo.SubscriptionRepository,
+	checkoutUseCase *CheckoutUseCase,
+) *Reconciler {
+	r := &Reconciler{
+		billingProvider:  billingProvider,
+		providerName:     providerName,
+		paymentRepo:      paymentRepo,
+		subscriptionRepo: subscriptionRepo,
+	}
+	if checkoutUseCase != nil {
+		r.applyWebhook = checkoutUseCase.ApplyWebhook
+	}
+	return r
+}
+
+// ReconcileRequest selects the window of records to reconcile
+type ReconcileRequest struct {
+	From time.Time // Inclusive
+	To   time.Time // Exclusive
+	Heal bool      // Re-apply completions the provider recorded but we missed
+}
+
+// Reconcile reports every discrepancy between the billing provider and the
+// local database for records created in the requested window. With Heal set,
+// charges the provider settled but we never completed, and active provider
+// subscriptions we have no record of, are re-applied through ApplyWebhook.
+// Other discrepancies need a human and are only reported.
+func (r *Reconciler) Reconcile(ctx context.Context, req ReconcileRequest) (*domain.ReconciliationReport, error) {
+	if req.From.IsZero() || req.To.IsZero() || !req.From.Before(req.To) {
+		return nil, domain.NewInvalidInputError("invalid reconciliation window", "from must be before to")
+	}
+	if req.Heal && r.applyWebhook == nil {
+		return nil, domain.NewInvalidInputError("invalid reconciliation request", "healing is not available")
+	}
+
+	report := &domain.ReconciliationReport{
+		Provider:      r.providerName,
+		From:          req.From,
+		To:            req.To,
+		Discrepancies: []domain.Discrepancy{},
+		StartedAt:     time.Now().UTC(),
+	}
+
+	// Behind a routing provider, reconcile the provider this reconciler is for
+	ctx = billing.WithProviderName(ctx, r.providerName)
+	if err := r.reconcilePayments(ctx, req, report); err != nil {
+		return nil, err
+	}
+	if err := r.reconcileSubscriptions(ctx, req, report); err != nil {
+		return nil, err
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	log.Info(ctx, "Reconciliation finished",
+		zap.String("provider", r.providerName),
+		zap.Time("from", req.From),
+		zap.Time("to", req.To),
+		zap.Int("charges_checked", report.ChargesChecked),
+		zap.Int("payments_checked", report.PaymentsChecked),
+		zap.Int("subscriptions_checked", report.SubscriptionsChecked),
+		zap.Int("discrepancies", len(report.Discrepancies)),
+		zap.Int("healed", report.Healed))
+
+	return report, nil
+}
+
+// reconcilePayments matches provider charges with local payments in both
+// directions. Providers that cannot list charges are skipped, since every
+// local payment would otherwise be reported as missing at the provider.
+func (r *Reconciler) reconcilePayments(ctx context.Context, req ReconcileRequest, report *domain.ReconciliationReport) error {
+	charges, err := r.listCharges(ctx, req.From.Add(-reconcileMatchSlack), req.To.Add(reconcileMatchSlack))
+	if errors.Is(err, billing.ErrNotSupported) {
+		log.Warn(ctx, "Billing provider cannot list charges; skipping payment reconciliation",
+			zap.String("provider", r.providerName))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	matched := make(map[string]bool) // Local payment IDs claimed by a provider charge
+	for _, charge := range charges {
+		payment, err := r.localPayment(ctx, charge)
+		if err != nil {
+			return err
+		}
+		if payment != nil {
+			matched[payment.ID.String()] = true
+		}
+		if !inReconcileWindow(charge.CreatedAt, req) {
+			continue
+		}
+
+		report.ChargesChecked++
+		for _, d := range comparePayment(charge, payment) {
+			if req.Heal && chargeHealable(charge, d) {
+				r.heal(ctx, &d, chargeWebhook(charge))
+				if d.Healed {
+					report.Healed++
+				}
+			}
+			report.Discrepancies = append(report.Discrepancies, d)
+		}
+	}
+
+	// Settled local payments the provider has no charge for
+	query := domain.PaymentListQuery{
+		Filter: domain.PaymentFilter{CreatedAfter: &req.From, CreatedBefore: &req.To},
+		SortBy: domain.PaymentSortByCreatedAt,
+		Limit:  reconcilePageSize,
+	}
+	for {
+		payments, err := r.paymentRepo.ListFiltered(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to list local payments: %w", err)
+		}
+
+		for _, payment := range payments {
+			if payment.Provider != "" && payment.Provider != r.providerName {
+				continue // Taken with another provider
+			}
+			report.PaymentsChecked++
+			settled := payment.Status == string(domain.PaymentStatusCompleted) || payment.Status == string(domain.PaymentStatusRefunded)
+			if !settled || matched[payment.ID.String()] {
+				continue
+			}
+			report.Discrepancies = append(report.Discrepancies, domain.Discrepancy{
+				Kind:             domain.DiscrepancyMissingProvider,
+				Record:           domain.ReconciledPayment,
+				SessionID:        payment.OrderID,
+				LocalID:          payment.ID.String(),
+				UserID:           payment.CustomerID,
+				Currency:         payment.Currency,
+				LocalAmountCents: dollarsToCents(payment.Amount),
+				LocalStatus:      payment.Status,
+				CreatedAt:        payment.CreatedAt,
+			})
+		}
+
+		if len(payments) < reconcilePageSize {
+			return nil
+		}
+		query.Cursor = domain.CursorAfter(payments[len(payments)-1], query.SortBy, query.SortDesc)
+	}
+}
+
+// reconcileSubscriptions matches provider subscriptions with local ones in both directions
+func (r *Reconciler) reconcileSubscriptions(ctx context.Context, req ReconcileRequest, report *domain.ReconciliationReport) error {
+	subs, err := r.listSubscriptions(ctx, req.From.Add(-reconcileMatchSlack), req.To.Add(reconcileMatchSlack))
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(subs)) // Provider subscription IDs
+	for _, sub := range subs {
+		seen[sub.ID] = true
+		if !inReconcileWindow(sub.CreatedAt, req) {
+			continue
+		}
+
+		report.SubscriptionsChecked++
+		local, err := r.subscriptionRepo.GetByExternalID(ctx, sub.ID)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to get local subscription: %w", err)
+		}
+
+		d := domain.Discrepancy{
+			Record:         domain.ReconciledSubscription,
+			ProviderID:     sub.ID,
+			UserID:         sub.UserID,
+			ProviderStatus: sub.Status,
+			CreatedAt:      sub.CreatedAt,
+		}
+		switch {
+		case local == nil:
+			d.Kind = domain.DiscrepancyMissingLocal
+			if req.Heal && sub.Status == domain.SubscriptionStatusActive {
+				r.heal(ctx, &d, subscriptionWebhook(sub))
+				if d.Healed {
+					report.Healed++
+				}
+			}
+		case local.Status != sub.Status:
+			d.Kind = domain.DiscrepancyStatusMismatch
+			d.LocalID = local.ID.String()
+			d.LocalStatus = local.Status
+		default:
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, d)
+	}
+
+	// Local subscriptions the provider has no record of
+	locals, err := r.subscriptionRepo.ListCreatedBetween(ctx, req.From, req.To)
+	if err != nil {
+		return fmt.Errorf("failed to list local subscriptions: %w", err)
+	}
+	for _, local := range locals {
+		if local.ExternalSubscriptionID == "" {
+			continue // Never synced with a provider
+		}
+		report.SubscriptionsChecked++
+		if seen[local.ExternalSubscriptionID] {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, domain.Discrepancy{
+			Kind:        domain.DiscrepancyMissingProvider,
+			Record:      domain.ReconciledSubscription,
+			ProviderID:  local.ExternalSubscriptionID,
+			LocalID:     local.ID.String(),
+			UserID:      local.UserID,
+			LocalStatus: local.Status,
+			CreatedAt:   local.CreatedAt,
+		})
+	}
+
+	return nil
+}
+
+// listCharges pages through all provider charges created in [from, to)
+func (r *Reconciler) listCharges(ctx context.Context, from, to time.Time) ([]billing.Charge, error) {
+	var charges []billing.Charge
+	req := billing.ListRequest{CreatedAfter: from, CreatedBefore: to, Limit: reconcilePageSize}
+	for {
+		page, err := r.billingProvider.ListCharges(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list charges from %s: %w", r.providerName, err)
+		}
+		charges = append(charges, page.Charges...)
+		if page.NextCursor == "" {
+			return charges, nil
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// listSubscriptions pages through all provider subscriptions created in [from, to)
+func (r *Reconciler) listSubscriptions(ctx context.Context, from, to time.Time) ([]billing.Subscription, error) {
+	var subs []billing.Subscription
+	req := billing.ListRequest{CreatedAfter: from, CreatedBefore: to, Limit: reconcilePageSize}
+	for {
+		page, err := r.billingProvider.ListSubscriptions(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subscriptions from %s: %w", r.providerName, err)
+		}
+		subs = append(subs, page.Subscriptions...)
+		if page.NextCursor == "" {
+			return subs, nil
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// localPayment finds the local payment for a provider charge, by checkout
+// session for checkout payments and by external payment ID otherwise. It
+// returns nil when there is none.
+func (r *Reconciler) localPayment(ctx context.Context, charge billing.Charge) (*domain.Payment, error) {
+	if charge.SessionID != "" {
+		payment, err := r.paymentRepo.GetByOrderID(ctx, charge.SessionID)
+		if err == nil {
+			return payment, nil
+		}
+		if !isNotFound(err) {
+			return nil, fmt.Errorf("failed to get local payment: %w", err)
+		}
+	}
+
+	payments, err := r.paymentRepo.ListFiltered(ctx, domain.PaymentListQuery{
+		Filter: domain.PaymentFilter{ExternalPaymentID: charge.ID},
+		SortBy: domain.PaymentSortByCreatedAt,
+		Limit:  1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local payment: %w", err)
+	}
+	if len(payments) == 0 {
+		return nil, nil
+	}
+	return payments[0], nil
+}
+
+// heal re-applies a missed event, recording the outcome on the discrepancy
+func (r *Reconciler) heal(ctx context.Context, d *domain.Discrepancy, wr billing.WebhookResult) {
+	ctx = audit.WithReason(ctx, fmt.Sprintf("reconciliation: %s %s", d.Kind, d.ProviderID))
+	if err := r.applyWebhook(ctx, wr); err != nil {
+		d.HealError = err.Error()
+		log.Warn(ctx, "Failed to heal reconciliation discrepancy",
+			zap.String("kind", string(d.Kind)),
+			zap.String("provider_id", d.ProviderID),
+			zap.Error(err))
+		return
+	}
+
+	d.Healed = true
+	log.Info(ctx, "Healed reconciliation discrepancy",
+		zap.String("kind", string(d.Kind)),
+		zap.String("provider_id", d.ProviderID),
+		zap.String("user_id", d.UserID))
+}
+
+// comparePayment returns the discrepancies between a provider charge and its
+// local payment, which is nil when there is none. Unsettled charges without
+// a local payment are not discrepancies.
+func comparePayment(charge billing.Charge, payment *domain.Payment) []domain.Discrepancy {
+	d := domain.Discrepancy{
+		Record:              domain.ReconciledPayment,
+		ProviderID:          charge.ID,
+		SessionID:           charge.SessionID,
+		UserID:              charge.UserID,
+		Currency:            charge.Currency,
+		ProviderAmountCents: dollarsToCents(charge.Amount),
+		ProviderStatus:      string(charge.Status),
+		CreatedAt:           charge.CreatedAt,
+	}
+
+	if payment == nil {
+		if charge.Status != billing.ChargeStatusSucceeded && charge.Status != billing.ChargeStatusRefunded {
+			return nil
+		}
+		d.Kind = domain.DiscrepancyMissingLocal
+		return []domain.Discrepancy{d}
+	}
+
+	d.LocalID = payment.ID.String()
+	d.LocalAmountCents = dollarsToCents(payment.Amount)
+	d.LocalStatus = payment.Status
+	if d.UserID == "" {
+		d.UserID = payment.CustomerID
+	}
+
+	var found []domain.Discrepancy
+	if d.ProviderAmountCents != d.LocalAmountCents || !strings.EqualFold(charge.Currency, payment.Currency) {
+		amount := d
+		amount.Kind = domain.DiscrepancyAmountMismatch
+		if !strings.EqualFold(charge.Currency, payment.Currency) {
+			amount.Currency = fmt.Sprintf("%s/%s", charge.Currency, payment.Currency)
+		}
+		found = append(found, amount)
+	}
+	if !paymentStatusMatches(charge.Status, payment.Status) {
+		status := d
+		status.Kind = domain.DiscrepancyStatusMismatch
+		found = append(found, status)
+	}
+	return found
+}
+
+// paymentStatusMatches reports whether a local payment status is consistent
+// with the provider's charge status
+func paymentStatusMatches(chargeStatus billing.ChargeStatus, paymentStatus string) bool {
+	for _, expected := range expectedPaymentStatuses[chargeStatus] {
+		if paymentStatus == string(expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// chargeHealable reports whether a discrepancy is a settled charge whose
+// completion we missed: either no local payment at all, or one still pending
+func chargeHealable(charge billing.Charge, d domain.Discrepancy) bool {
+	if charge.Status != billing.ChargeStatusSucceeded {
+		return false
+	}
+	return d.Kind == domain.DiscrepancyMissingLocal ||
+		(d.Kind == domain.DiscrepancyStatusMismatch && d.LocalStatus == string(domain.PaymentStatusPending))
+}
+
+// chargeWebhook rebuilds the completion webhook of a settled charge
+func chargeWebhook(charge billing.Charge) billing.WebhookResult {
+	return billing.WebhookResult{
+		EventType:      string(billing.WebhookEventTypeCheckoutSessionCompleted),
+		SessionID:      charge.SessionID,
+		SubscriptionID: charge.SubscriptionID,
+		UserID:         charge.UserID,
+		FamilyID:       charge.FamilyID,
+		PlanIDString:   charge.PlanID,
+		Amount:         charge.Amount,
+		Currency:       charge.Currency,
+		Status:         "completed",
+		Metadata: map[string]interface{}{
+			"reconciled":         true,
+			"provider_charge_id": charge.ID,
+		},
+	}
+}
+
+// subscriptionWebhook rebuilds the creation webhook of an active subscription.
+// Entitlements of a subscription set to cancel expire with its period; others
+// are kept until the subscription ends.
+func subscriptionWebhook(sub billing.Subscription) billing.WebhookResult {
+	wr := billing.WebhookResult{
+		EventType:      string(billing.WebhookEventTypeSubscriptionCreated),
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		FamilyID:       sub.FamilyID,
+		PlanIDString:   sub.PlanID,
+		Status:         sub.Status,
+		Metadata: map[string]interface{}{
+			"reconciled": true,
+		},
+	}
+	if sub.CancelAtPeriodEnd && !sub.CurrentPeriodEnd.IsZero() {
+		expiresAt := sub.CurrentPeriodEnd
+		wr.ExpiresAt = &expiresAt
+	}
+	return wr
+}
+
+// inReconcileWindow reports whether t falls in the request's [From, To) window
+func inReconcileWindow(t time.Time, req ReconcileRequest) bool {
+	return !t.Before(req.From) && t.Before(req.To)
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/billing"
+	"github.com/jia-app/paymentservice/internal/billing/billingtest"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// reconcilePaymentRepo serves the payment lookups the reconciler makes
+type reconcilePaymentRepo struct {
+	repo.PaymentRepository
+	payments []*domain.Payment
+}
+
+func (r *reconcilePaymentRepo) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
+	for _, p := range r.payments {
+		if p.OrderID == orderID {
+			return p, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("payment", orderID)
+}
+
+func (r *reconcilePaymentRepo) ListFiltered(ctx context.Context, query domain.PaymentListQuery) ([]*domain.Payment, error) {
+	if query.Cursor != nil {
+		return nil, nil
+	}
+	var matched []*domain.Payment
+	for _, p := range r.payments {
+		f := query.Filter
+		if f.ExternalPaymentID != "" && p.ExternalPaymentID != f.ExternalPaymentID {
+			continue
+		}
+		if f.CreatedAfter != nil && p.CreatedAt.Before(*f.CreatedAfter) {
+			continue
+		}
+		if f.CreatedBefore != nil && !p.CreatedAt.Before(*f.CreatedBefore) {
+			continue
+		}
+		matched = append(matched, p)
+	}
+	return matched, nil
+}
+
+// reconcileSubscriptionRepo serves the subscription lookups the reconciler makes
+type reconcileSubscriptionRepo struct {
+	repo.SubscriptionRepository
+	subs []*domain.Subscription
+}
+
+func (r *reconcileSubscriptionRepo) GetByExternalID(ctx context.Context, externalID string) (*domain.Subscription, error) {
+	for _, s := range r.subs {
+		if s.ExternalSubscriptionID == externalID {
+			return s, nil
+		}
+	}
+	return nil, domain.NewNotFoundError("subscription", externalID)
+}
+
+func (r *reconcileSubscriptionRepo) ListCreatedBetween(ctx context.Context, from, to time.Time) ([]*domain.Subscription, error) {
+	var matched []*domain.Subscription
+	for _, s := range r.subs {
+		if !s.CreatedAt.Before(from) && s.CreatedAt.Before(to) {
+			matched = append(matched, s)
+		}
+	}
+	return matched, nil
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+
+	provider := billingtest.NewFakeProvider()
+	// Matches its payment
+	provider.AddCharge(billing.Charge{ID: "pi_ok", SessionID: "cs_ok", Amount: 9.99, Currency: "USD", Status: billing.ChargeStatusSucceeded, CreatedAt: at(1)})
+	// Webhook lost: paid at the provider, still pending locally
+	provider.AddCharge(billing.Charge{ID: "pi_lost", SessionID: "cs_lost", UserID: "user-2", PlanID: "premium", Amount: 9.99, Currency: "USD", Status: billing.ChargeStatusSucceeded, CreatedAt: at(2)})
+	// Charged a different amount than recorded
+	provider.AddCharge(billing.Charge{ID: "pi_amount", SessionID: "cs_amount", Amount: 12.00, Currency: "usd", Status: billing.ChargeStatusSucceeded, CreatedAt: at(3)})
+	// No local payment at all
+	provider.AddCharge(billing.Charge{ID: "ch_orphan", Amount: 5, Currency: "USD", Status: billing.ChargeStatusSucceeded, CreatedAt: at(4)})
+	// Unsettled charges without a payment are not discrepancies
+	provider.AddCharge(billing.Charge{ID: "ch_failed", Amount: 5, Currency: "USD", Status: billing.ChargeStatusFailed, CreatedAt: at(5)})
+	// Outside the window, but still claims its payment
+	provider.AddCharge(billing.Charge{ID: "pi_edge", SessionID: "cs_edge", Amount: 1, Currency: "USD", Status: billing.ChargeStatusSucceeded, CreatedAt: from.Add(-time.Minute)})
+
+	provider.AddSubscription(billing.Subscription{ID: "sub_ok", Status: "active", CreatedAt: at(1)})
+	provider.AddSubscription(billing.Subscription{ID: "sub_status", Status: "cancelled", CreatedAt: at(2)})
+	provider.AddSubscription(billing.Subscription{ID: "sub_missing", UserID: "user-3", PlanID: "premium", Status: "active", CreatedAt: at(3)})
+
+	payments := &reconcilePaymentRepo{payments: []*domain.Payment{
+		{ID: uuid.New(), OrderID: "cs_ok", Amount: 9.99, Currency: "USD", Status: "completed", CreatedAt: at(1)},
+		{ID: uuid.New(), OrderID: "cs_lost", Amount: 9.99, Currency: "USD", Status: "pending", CreatedAt: at(2)},
+		{ID: uuid.New(), OrderID: "cs_amount", Amount: 9.99, Currency: "USD", Status: "completed", CreatedAt: at(3)},
+		{ID: uuid.New(), OrderID: "cs_edge", Amount: 1, Currency: "USD", Status: "completed", CreatedAt: from},
+		{ID: uuid.New(), OrderID: "cs_local_only", Provider: "fake", Amount: 3, Currency: "USD", Status: "completed", CreatedAt: at(6)},
+		{ID: uuid.New(), OrderID: "cs_abandoned", Amount: 3, Currency: "USD", Status: "cancelled", CreatedAt: at(7)},
+		// Routed to another provider, which this reconciler does not check
+		{ID: uuid.New(), OrderID: "PL0001", Provider: "adyen", Amount: 3, Currency: "EUR", Status: "completed", CreatedAt: at(8)},
+	}}
+	subs := &reconcileSubscriptionRepo{subs: []*domain.Subscription{
+		{ID: uuid.New(), ExternalSubscriptionID: "sub_ok", Status: "active", CreatedAt: at(1)},
+		{ID: uuid.New(), ExternalSubscriptionID: "sub_status", Status: "active", CreatedAt: at(2)},
+		{ID: uuid.New(), ExternalSubscriptionID: "sub_gone", Status: "active", CreatedAt: at(4)},
+		{ID: uuid.New(), Status: "active", CreatedAt: at(5)}, // Never synced with the provider
+	}}
+
+	r := NewReconciler(provider, "fake", payments, subs, nil)
+	var applied []billing.WebhookResult
+	r.applyWebhook = func(ctx context.Context, wr billing.WebhookResult) error {
+		applied = append(applied, wr)
+		return nil
+	}
+
+	report, err := r.Reconcile(context.Background(), ReconcileRequest{From: from, To: to, Heal: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type key struct {
+		kind domain.DiscrepancyKind
+		id   string
+	}
+	got := make(map[key]domain.Discrepancy)
+	for _, d := range report.Discrepancies {
+		id := d.ProviderID
+		if id == "" {
+			id = d.SessionID
+		}
+		got[key{d.Kind, id}] = d
+	}
+
+	want := []key{
+		{domain.DiscrepancyStatusMismatch, "pi_lost"},
+		{domain.DiscrepancyAmountMismatch, "pi_amount"},
+		{domain.DiscrepancyMissingLocal, "ch_orphan"},
+		{domain.DiscrepancyMissingProvider, "cs_local_only"},
+		{domain.DiscrepancyStatusMismatch, "sub_status"},
+		{domain.DiscrepancyMissingLocal, "sub_missing"},
+		{domain.DiscrepancyMissingProvider, "sub_gone"},
+	}
+	for _, k := range want {
+		if _, ok := got[k]; !ok {
+			t.Errorf("expected a %s discrepancy for %s", k.kind, k.id)
+		}
+	}
+	if len(report.Discrepancies) != len(want) {
+		t.Errorf("expected %d discrepancies, got %d: %+v", len(want), len(report.Discrepancies), report.Discrepancies)
+	}
+
+	if d := got[key{domain.DiscrepancyAmountMismatch, "pi_amount"}]; d.ProviderAmountCents != 1200 || d.LocalAmountCents != 999 {
+		t.Errorf("unexpected amounts: %+v", d)
+	}
+	if report.ChargesChecked != 5 || report.PaymentsChecked != 6 {
+		t.Errorf("expected 5 charges and 6 payments checked, got %d and %d", report.ChargesChecked, report.PaymentsChecked)
+	}
+
+	// The lost completion, the orphan charge and the missing active
+	// subscription are healed; mismatched amounts and statuses are not
+	if report.Healed != 3 || len(applied) != 3 {
+		t.Fatalf("expected 3 healed discrepancies, got %d (%d applied)", report.Healed, len(applied))
+	}
+	if wr := applied[0]; wr.SessionID != "cs_lost" || wr.UserID != "user-2" || wr.PlanIDString != "premium" {
+		t.Errorf("unexpected re-applied webhook: %+v", wr)
+	}
+	if wr := applied[2]; wr.SubscriptionID != "sub_missing" || wr.EventType != string(billing.WebhookEventTypeSubscriptionCreated) {
+		t.Errorf("unexpected re-applied webhook: %+v", wr)
+	}
+	if !got[key{domain.DiscrepancyMissingLocal, "sub_missing"}].Healed {
+		t.Error("expected the missing subscription to be marked healed")
+	}
+}
+
+func TestReconciler_ReconcilePages(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	provider := billingtest.NewFakeProvider()
+	for i := 0; i < reconcilePageSize+5; i++ {
+		provider.AddCharge(billing.Charge{ID: uuid.NewString(), Amount: 1, Currency: "USD", Status: billing.ChargeStatusSucceeded, CreatedAt: from.Add(time.Duration(i) * time.Second)})
+	}
+
+	r := NewReconciler(provider, "fake", &reconcilePaymentRepo{}, &reconcileSubscriptionRepo{}, nil)
+	report, err := r.Reconcile(context.Background(), ReconcileRequest{From: from, To: from.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.ChargesChecked != reconcilePageSize+5 || report.Count(domain.DiscrepancyMissingLocal) != reconcilePageSize+5 {
+		t.Errorf("expected every page to be checked, got %d charges and %d missing", report.ChargesChecked, report.Count(domain.DiscrepancyMissingLocal))
+	}
+}
+
+// noChargesProvider is a provider that cannot list charges
+type noChargesProvider struct {
+	*billingtest.FakeProvider
+}
+
+func (p noChargesProvider) ListCharges(ctx context.Context, req billing.ListRequest) (*billing.ChargePage, error) {
+	return nil, billing.ErrNotSupported
+}
+
+func TestReconciler_ReconcileChargesNotSupported(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	payments := &reconcilePaymentRepo{payments: []*domain.Payment{
+		{ID: uuid.New(), OrderID: "cs_1", Amount: 9.99, Currency: "USD", Status: string(domain.PaymentStatusCompleted), CreatedAt: from.Add(time.Minute)},
+	}}
+
+	r := NewReconciler(noChargesProvider{billingtest.NewFakeProvider()}, "adyen", payments, &reconcileSubscriptionRepo{}, nil)
+	report, err := r.Reconcile(context.Background(), ReconcileRequest{From: from, To: from.Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.PaymentsChecked != 0 || len(report.Discrepancies) != 0 {
+		t.Errorf("expected payments not to be reconciled, got %+v", report)
+	}
+}
+
+func TestReconciler_ReconcileInvalid(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := NewReconciler(billingtest.NewFakeProvider(), "fake", &reconcilePaymentRepo{}, &reconcileSubscriptionRepo{}, nil)
+
+	if _, err := r.Reconcile(context.Background(), ReconcileRequest{From: from, To: from}); err == nil {
+		t.Error("expected an error for an empty window")
+	}
+	if _, err := r.Reconcile(context.Background(), ReconcileRequest{From: from, To: from.Add(time.Hour), Heal: true}); err == nil {
+		t.Error("expected an error when healing without a checkout use case")
+	}
+}
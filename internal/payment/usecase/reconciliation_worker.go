@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// ReconciliationConfig holds configuration for scheduled provider reconciliation
+type ReconciliationConfig struct {
+	Interval time.Duration `json:"interval"`  // How often the job runs
+	Window   time.Duration `json:"window"`    // How far back each run looks; overlapping runs are harmless
+	Delay    time.Duration `json:"delay"`     // Records younger than this are left to webhooks still in flight
+	AutoHeal bool          `json:"auto_heal"` // Re-apply missed completions through ApplyWebhook
+}
+
+// DefaultReconciliationConfig returns a default reconciliation configuration
+func DefaultReconciliationConfig() ReconciliationConfig {
+	return ReconciliationConfig{
+		Interval: 1 * time.Hour,
+		Window:   25 * time.Hour,
+		Delay:    15 * time.Minute,
+	}
+}
+
+// ReconciliationWorker periodically reconciles the recent past with the
+// billing provider, logging every discrepancy found
+type ReconciliationWorker struct {
+	reconciler *Reconciler
+	config     ReconciliationConfig
+	ticker     *time.Ticker
+	stopChan   chan bool
+}
+
+// NewReconciliationWorker creates a new reconciliation worker
+func NewReconciliationWorker(reconciler *Reconciler, config ReconciliationConfig) *ReconciliationWorker {
+	defaults := DefaultReconciliationConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Window <= 0 {
+		config.Window = defaults.Window
+	}
+	if config.Delay < 0 {
+		config.Delay = defaults.Delay
+	}
+
+	return &ReconciliationWorker{
+		reconciler: reconciler,
+		config:     config,
+		stopChan:   make(chan bool),
+	}
+}
+
+// Start starts the reconciliation worker
+func (w *ReconciliationWorker) Start(ctx context.Context) {
+	w.ticker = time.NewTicker(w.config.Interval)
+	log.L(ctx).Info("Starting reconciliation worker",
+		zap.Duration("interval", w.config.Interval),
+		zap.Duration("window", w.config.Window),
+		zap.Bool("auto_heal", w.config.AutoHeal))
+
+	go func() {
+		for {
+			select {
+			case <-w.ticker.C:
+				w.reconcile(ctx)
+			case <-w.stopChan:
+				log.L(ctx).Info("Stopping reconciliation worker")
+				return
+			case <-ctx.Done():
+				log.L(ctx).Info("Reconciliation worker context cancelled")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the reconciliation worker
+func (w *ReconciliationWorker) Stop() {
+	if w.ticker != nil {
+		w.ticker.Stop()
+	}
+	w.stopChan <- true
+}
+
+// reconcile runs one reconciliation pass over the configured window
+func (w *ReconciliationWorker) reconcile(ctx context.Context) {
+	to := time.Now().Add(-w.config.Delay)
+	report, err := w.reconciler.Reconcile(ctx, ReconcileRequest{
+		From: to.Add(-w.config.Window),
+		To:   to,
+		Heal: w.config.AutoHeal,
+	})
+	if err != nil {
+		log.Error(ctx, "Failed to reconcile with billing provider", zap.Error(err))
+		return
+	}
+
+	for _, d := range report.Discrepancies {
+		log.Warn(ctx, "Reconciliation discrepancy",
+			zap.String("kind", string(d.Kind)),
+			zap.String("record", string(d.Record)),
+			zap.String("provider_id", d.ProviderID),
+			zap.String("local_id", d.LocalID),
+			zap.String("user_id", d.UserID),
+			zap.String("provider_status", d.ProviderStatus),
+			zap.String("local_status", d.LocalStatus),
+			zap.Int64("provider_amount_cents", d.ProviderAmountCents),
+			zap.Int64("local_amount_cents", d.LocalAmountCents),
+			zap.Bool("healed", d.Healed))
+	}
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// maxRevenueReportMonths bounds the number of months one report covers
+const maxRevenueReportMonths = 36
+
+// RevenueUseCase reports recurring revenue and churn from daily snapshots
+// of the subscriptions paying at the end of each day
+type RevenueUseCase struct {
+	revenueRepo repo.RevenueRepository
+	now         func() time.Time
+}
+
+// NewRevenueUseCase creates a new revenue use case
+func NewRevenueUseCase(revenueRepo repo.RevenueRepository) *RevenueUseCase {
+	return &RevenueUseCase{
+		revenueRepo: revenueRepo,
+		now:         time.Now,
+	}
+}
+
+// RevenueReportRequest describes the months and slicing of a revenue report
+type RevenueReportRequest struct {
+	From    time.Time // First month; any time within it
+	To      time.Time // Last month, inclusive; any time within it
+	GroupBy []domain.RevenueDimension
+}
+
+// GetRevenueReport returns the MRR and customer movements of every month
+// in the range. A month runs from the snapshot of the day before it
+// starts to the latest snapshot within it, so the current month covers
+// the days snapshotted so far; months without any snapshot are left out.
+func (uc *RevenueUseCase) GetRevenueReport(ctx context.Context, req RevenueReportRequest) (*domain.RevenueReport, error) {
+	from, to := domain.MonthStart(req.From), domain.MonthStart(req.To)
+	if req.From.IsZero() || req.To.IsZero() {
+		return nil, domain.NewInvalidInputError("invalid revenue report", "from and to are required")
+	}
+	if to.Before(from) {
+		return nil, domain.NewInvalidInputError("invalid revenue report", "to must not be before from")
+	}
+	if months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1; months > maxRevenueReportMonths {
+		return nil, domain.NewInvalidInputError("invalid revenue report", fmt.Sprintf("at most %d months can be reported at once, got %d", maxRevenueReportMonths, months))
+	}
+
+	report := &domain.RevenueReport{
+		From:      from,
+		To:        to,
+		GroupBy:   uniqueDimensions(req.GroupBy),
+		Movements: []domain.RevenueMovement{},
+	}
+
+	// The end of one month is the start of the next; load each day once
+	snapshots := make(map[string][]domain.RevenueSnapshot)
+	load := func(date *time.Time) ([]domain.RevenueSnapshot, error) {
+		if date == nil {
+			return nil, nil
+		}
+		key := date.Format(time.DateOnly)
+		if s, ok := snapshots[key]; ok {
+			return s, nil
+		}
+		s, err := uc.revenueRepo.ListSnapshot(ctx, *date)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[key] = s
+		return s, nil
+	}
+
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		endDate, err := uc.revenueRepo.LatestSnapshotDate(ctx, month.AddDate(0, 1, -1))
+		if err != nil {
+			return nil, err
+		}
+		if endDate == nil || endDate.Before(month) {
+			continue
+		}
+		startDate, err := uc.revenueRepo.LatestSnapshotDate(ctx, month.AddDate(0, 0, -1))
+		if err != nil {
+			return nil, err
+		}
+
+		start, err := load(startDate)
+		if err != nil {
+			return nil, err
+		}
+		end, err := load(endDate)
+		if err != nil {
+			return nil, err
+		}
+		report.Movements = append(report.Movements, domain.ComputeRevenueMovements(month, start, end, report.GroupBy)...)
+	}
+
+	return report, nil
+}
+
+// Snapshot records the subscriptions paying at the end of a day,
+// replacing an earlier snapshot of the same day
+func (uc *RevenueUseCase) Snapshot(ctx context.Context, date time.Time) (int64, error) {
+	if date.After(uc.now()) {
+		return 0, domain.NewInvalidInputError("invalid revenue snapshot", "cannot snapshot a future day")
+	}
+	return uc.revenueRepo.Snapshot(ctx, date)
+}
+
+// SnapshotMissingDays snapshots every completed day after the latest
+// snapshot, up to yesterday. Without any snapshot it backfills at most
+// backfillDays days. It returns the days snapshotted.
+func (uc *RevenueUseCase) SnapshotMissingDays(ctx context.Context, backfillDays int) ([]time.Time, error) {
+	now := uc.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	first := today.AddDate(0, 0, -backfillDays)
+	latest, err := uc.revenueRepo.LatestSnapshotDate(ctx, yesterday)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && !latest.Before(first) {
+		first = latest.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for day := first; !day.After(yesterday); day = day.AddDate(0, 0, 1) {
+		if _, err := uc.revenueRepo.Snapshot(ctx, day); err != nil {
+			return days, fmt.Errorf("failed to snapshot %s: %w", day.Format(time.DateOnly), err)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// uniqueDimensions drops repeated dimensions, keeping their order
+func uniqueDimensions(dims []domain.RevenueDimension) []domain.RevenueDimension {
+	seen := make(map[domain.RevenueDimension]bool, len(dims))
+	unique := make([]domain.RevenueDimension, 0, len(dims))
+	for _, d := range dims {
+		if !seen[d] {
+			seen[d] = true
+			unique = append(unique, d)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+)
+
+// memoryRevenueRepo keeps snapshots in memory by day
+type memoryRevenueRepo struct {
+	repo.RevenueRepository
+	snapshots map[string][]domain.RevenueSnapshot
+	taken     []string
+}
+
+func (r *memoryRevenueRepo) Snapshot(ctx context.Context, date time.Time) (int64, error) {
+	key := date.Format(time.DateOnly)
+	r.taken = append(r.taken, key)
+	if _, ok := r.snapshots[key]; !ok {
+		r.snapshots[key] = []domain.RevenueSnapshot{}
+	}
+	return int64(len(r.snapshots[key])), nil
+}
+
+func (r *memoryRevenueRepo) LatestSnapshotDate(ctx context.Context, onOrBefore time.Time) (*time.Time, error) {
+	var latest *time.Time
+	for key := range r.snapshots {
+		day, _ := time.Parse(time.DateOnly, key)
+		if !day.After(onOrBefore) && (latest == nil || day.After(*latest)) {
+			latest = &day
+		}
+	}
+	return latest, nil
+}
+
+func (r *memoryRevenueRepo) ListSnapshot(ctx context.Context, date time.Time) ([]domain.RevenueSnapshot, error) {
+	return r.snapshots[date.Format(time.DateOnly)], nil
+}
+
+func TestRevenueUseCase_GetRevenueReport(t *testing.T) {
+	sub := func(user string, mrr int64) domain.RevenueSnapshot {
+		return domain.RevenueSnapshot{SubscriptionID: uuid.NewSHA1(uuid.NameSpaceOID, []byte(user)), UserID: user, PlanID: "premium", Currency: "USD", MRRCents: mrr}
+	}
+	revenue := &memoryRevenueRepo{snapshots: map[string][]domain.RevenueSnapshot{
+		"2025-01-31": {sub("u1", 1000), sub("u2", 1000)},
+		"2025-02-28": {sub("u1", 1000), sub("u3", 500)},
+		// March has no snapshot; April's latest is mid-month
+		"2025-04-15": {sub("u3", 500)},
+	}}
+	uc := NewRevenueUseCase(revenue)
+
+	report, err := uc.GetRevenueReport(context.Background(), RevenueReportRequest{
+		From:    time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC),
+		To:      time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+		GroupBy: []domain.RevenueDimension{domain.RevenueByPlan, domain.RevenueByPlan},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.GroupBy) != 1 {
+		t.Errorf("expected repeated dimensions to be dropped, got %v", report.GroupBy)
+	}
+	if len(report.Movements) != 3 {
+		t.Fatalf("expected January, February and April, got %+v", report.Movements)
+	}
+
+	jan, feb, apr := report.Movements[0], report.Movements[1], report.Movements[2]
+	if jan.StartingMRRCents != 0 || jan.NewMRRCents != 2000 || jan.NewCustomers != 2 {
+		t.Errorf("expected January to start from nothing, got %+v", jan)
+	}
+	if feb.StartingMRRCents != 2000 || feb.ChurnedMRRCents != 1000 || feb.NewMRRCents != 500 || feb.EndingMRRCents != 1500 || feb.ChurnedCustomers != 1 {
+		t.Errorf("unexpected February: %+v", feb)
+	}
+	// April starts from the last snapshot before it, taken in February
+	if !apr.Month.Equal(time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) || apr.StartingMRRCents != 1500 || apr.EndingMRRCents != 500 {
+		t.Errorf("unexpected April: %+v", apr)
+	}
+}
+
+func TestRevenueUseCase_GetRevenueReportInvalid(t *testing.T) {
+	uc := NewRevenueUseCase(&memoryRevenueRepo{snapshots: map[string][]domain.RevenueSnapshot{}})
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []RevenueReportRequest{
+		{From: jan},
+		{From: jan, To: jan.AddDate(0, -1, 0)},
+		{From: jan, To: jan.AddDate(0, maxRevenueReportMonths, 0)},
+	}
+	for _, req := range tests {
+		if _, err := uc.GetRevenueReport(context.Background(), req); err == nil {
+			t.Errorf("expected an error for %v to %v", req.From, req.To)
+		}
+	}
+}
+
+func TestRevenueUseCase_SnapshotMissingDays(t *testing.T) {
+	revenue := &memoryRevenueRepo{snapshots: map[string][]domain.RevenueSnapshot{}}
+	uc := NewRevenueUseCase(revenue)
+	uc.now = func() time.Time { return time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC) }
+
+	days, err := uc.SnapshotMissingDays(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(days) != 3 || revenue.taken[0] != "2025-03-07" || revenue.taken[2] != "2025-03-09" {
+		t.Errorf("expected a 3-day backfill up to yesterday, got %v", revenue.taken)
+	}
+
+	// Later runs only take the days that ended since
+	uc.now = func() time.Time { return time.Date(2025, 3, 12, 1, 0, 0, 0, time.UTC) }
+	revenue.taken = nil
+	if _, err := uc.SnapshotMissingDays(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(revenue.taken) != 2 || revenue.taken[0] != "2025-03-10" || revenue.taken[1] != "2025-03-11" {
+		t.Errorf("expected 2025-03-10 and 2025-03-11, got %v", revenue.taken)
+	}
+
+	if _, err := uc.Snapshot(context.Background(), time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("expected an error snapshotting a future day")
+	}
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// RevenueSnapshotConfig holds configuration for the daily revenue snapshots
+type RevenueSnapshotConfig struct {
+	Interval     time.Duration `json:"interval"`      // How often the worker looks for days to snapshot
+	BackfillDays int           `json:"backfill_days"` // Days snapshotted when there is no snapshot yet
+}
+
+// DefaultRevenueSnapshotConfig returns a default revenue snapshot configuration
+func DefaultRevenueSnapshotConfig() RevenueSnapshotConfig {
+	return RevenueSnapshotConfig{
+		Interval:     1 * time.Hour,
+		BackfillDays: 31,
+	}
+}
+
+// RevenueSnapshotWorker snapshots every day once it has ended, keeping
+// revenue reports fast and current
+type RevenueSnapshotWorker struct {
+	revenueUseCase *RevenueUseCase
+	config         RevenueSnapshotConfig
+	ticker         *time.Ticker
+	stopChan       chan bool
+}
+
+// NewRevenueSnapshotWorker creates a new revenue snapshot worker
+func NewRevenueSnapshotWorker(revenueUseCase *RevenueUseCase, config RevenueSnapshotConfig) *RevenueSnapshotWorker {
+	defaults := DefaultRevenueSnapshotConfig()
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.BackfillDays <= 0 {
+		config.BackfillDays = defaults.BackfillDays
+	}
+
+	return &RevenueSnapshotWorker{
+		revenueUseCase: revenueUseCase,
+		config:         config,
+		stopChan:       make(chan bool),
+	}
+}
+
+// Start starts the revenue snapshot worker, snapshotting missed days right away
+func (w *RevenueSnapshotWorker) Start(ctx context.Context) {
+	w.ticker = time.NewTicker(w.config.Interval)
+	log.L(ctx).Info("Starting revenue snapshot worker",
+		zap.Duration("interval", w.config.Interval),
+		zap.Int("backfill_days", w.config.BackfillDays))
+
+	go func() {
+		w.snapshot(ctx)
+		for {
+			select {
+			case <-w.ticker.C:
+				w.snapshot(ctx)
+			case <-w.stopChan:
+				log.L(ctx).Info("Stopping revenue snapshot worker")
+				return
+			case <-ctx.Done():
+				log.L(ctx).Info("Revenue snapshot worker context cancelled")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the revenue snapshot worker
+func (w *RevenueSnapshotWorker) Stop() {
+	if w.ticker != nil {
+		w.ticker.Stop()
+	}
+	w.stopChan <- true
+}
+
+// snapshot snapshots the days that ended since the last run
+func (w *RevenueSnapshotWorker) snapshot(ctx context.Context) {
+	days, err := w.revenueUseCase.SnapshotMissingDays(ctx, w.config.BackfillDays)
+	for _, day := range days {
+		log.Info(ctx, "Revenue snapshot taken", zap.String("date", day.Format(time.DateOnly)))
+	}
+	if err != nil {
+		log.Error(ctx, "Failed to take revenue snapshots", zap.Error(err))
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo"
+	"github.com/jia-app/paymentservice/internal/shared/log"
+)
+
+// TaxEngine computes the tax charged on a sale from the imported tax rates
+type TaxEngine struct {
+	taxRateRepo repo.TaxRateRepository
+}
+
+// NewTaxEngine creates a new tax engine
+func NewTaxEngine(taxRateRepo repo.TaxRateRepository) *TaxEngine {
+	return &TaxEngine{
+		taxRateRepo: taxRateRepo,
+	}
+}
+
+// TaxRequest describes a sale to compute tax for
+type TaxRequest struct {
+	CountryCode string // Buyer's country, ISO 3166-1 alpha-2
+	Region      string // Buyer's state or province (optional)
+	TaxID       string // Buyer's business tax ID (optional)
+	PriceCents  int64  // Price before tax, or including tax where the rate is inclusive
+}
+
+// Quote computes the tax on a sale. Jurisdictions without an imported rate
+// are not taxed. A business tax ID must be well-formed and triggers the
+// reverse charge where the jurisdiction applies it.
+func (e *TaxEngine) Quote(ctx context.Context, req TaxRequest) (domain.TaxQuote, error) {
+	countryCode := strings.ToUpper(strings.TrimSpace(req.CountryCode))
+	region := strings.ToUpper(strings.TrimSpace(req.Region))
+
+	reverseCharge := false
+	if req.TaxID != "" {
+		if !domain.ValidTaxID(countryCode, req.TaxID) {
+			return domain.TaxQuote{}, domain.NewInvalidInputError("invalid tax ID", fmt.Sprintf("%q is not a valid business tax ID for %s", req.TaxID, countryCode))
+		}
+		reverseCharge = true
+	}
+
+	if countryCode == "" {
+		return domain.CalculateTax(req.PriceCents, nil, false), nil
+	}
+
+	rate, err := e.taxRateRepo.GetRate(ctx, countryCode, region)
+	if err != nil {
+		if isNotFound(err) {
+			log.Debug(ctx, "No tax rate for jurisdiction, not charging tax",
+				zap.String("country_code", countryCode),
+				zap.String("region", region))
+			return domain.CalculateTax(req.PriceCents, nil, false), nil
+		}
+		return domain.TaxQuote{}, fmt.Errorf("failed to get tax rate: %w", err)
+	}
+
+	return domain.CalculateTax(req.PriceCents, &rate, reverseCharge), nil
+}
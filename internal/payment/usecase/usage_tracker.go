@@ -16,6 +16,7 @@ import (
 	"github.com/jia-app/paymentservice/internal/shared/cache"
 	"github.com/jia-app/paymentservice/internal/shared/events"
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
 // UsageTracker handles usage tracking and quota management
@@ -24,6 +25,7 @@ type UsageTracker struct {
 	entitlementRepo repo.EntitlementRepository
 	cache           *cache.Cache
 	eventPublisher  events.UsagePublisher
+	metrics         *metrics.MetricsCollector // Can be nil if metrics are disabled
 }
 
 // NewUsageTracker creates a new usage tracker
@@ -32,12 +34,14 @@ func NewUsageTracker(
 	entitlementRepo repo.EntitlementRepository,
 	cache *cache.Cache,
 	eventPublisher events.UsagePublisher,
+	metricsCollector *metrics.MetricsCollector,
 ) *UsageTracker {
 	return &UsageTracker{
 		usageRepo:       usageRepo,
 		entitlementRepo: entitlementRepo,
 		cache:           cache,
 		eventPublisher:  eventPublisher,
+		metrics:         metricsCollector,
 	}
 }
 
@@ -117,6 +121,7 @@ func (ut *UsageTracker) TrackUsage(ctx context.Context, req TrackUsageRequest) (
 
 	// Check if usage would exceed quota
 	if currentUsage+req.ResourceSize > quotaLimit {
+		ut.metrics.RecordUsage(ctx, req.ResourceSize, true)
 		return &TrackUsageResponse{
 			Allowed:        false,
 			RemainingQuota: max(0, quotaLimit-currentUsage),
@@ -158,6 +163,8 @@ func (ut *UsageTracker) TrackUsage(ctx context.Context, req TrackUsageRequest) (
 		}
 	}
 
+	ut.metrics.RecordUsage(ctx, req.ResourceSize, false)
+
 	// Calculate remaining quota
 	remainingQuota := quotaLimit - (currentUsage + req.ResourceSize)
 
@@ -34,7 +34,6 @@ type StripeRequest struct {
 type WebhookResult struct {
 	UserID         string                 `json:"user_id"`
 	FamilyID       *string                `json:"family_id,omitempty"`
-	FeatureCode    string                 `json:"feature_code"`
 	PlanID         uuid.UUID              `json:"plan_id"`
 	PlanIDString   string                 `json:"plan_id_string"`
 	SubscriptionID string                 `json:"subscription_id"`
@@ -113,9 +112,6 @@ func (p *Parser) parsePaymentIntentSucceeded(event StripeEvent) (*WebhookResult,
 		return nil, fmt.Errorf("missing user_id in metadata")
 	}
 
-	// Feature code is no longer required in metadata - it will be determined from the plan
-	featureCode := "" // Will be determined from plan
-
 	planIDString, ok := metadata["plan_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing plan_id in metadata")
@@ -137,7 +133,6 @@ func (p *Parser) parsePaymentIntentSucceeded(event StripeEvent) (*WebhookResult,
 	return &WebhookResult{
 		UserID:         userID,
 		FamilyID:       familyID,
-		FeatureCode:    featureCode,
 		PlanID:         planID,
 		PlanIDString:   planIDString,
 		SubscriptionID: "", // Payment intents don't have subscription IDs
@@ -184,9 +179,6 @@ func (p *Parser) parseInvoicePaymentSucceeded(event StripeEvent) (*WebhookResult
 		return nil, fmt.Errorf("missing user_id in metadata")
 	}
 
-	// Feature code is no longer required in metadata - it will be determined from the plan
-	featureCode := "" // Will be determined from plan
-
 	planIDString, ok := metadata["plan_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing plan_id in metadata")
@@ -218,7 +210,6 @@ func (p *Parser) parseInvoicePaymentSucceeded(event StripeEvent) (*WebhookResult
 	return &WebhookResult{
 		UserID:         userID,
 		FamilyID:       familyID,
-		FeatureCode:    featureCode,
 		PlanID:         planID,
 		PlanIDString:   planIDString,
 		SubscriptionID: subscriptionID,
@@ -261,9 +252,6 @@ func (p *Parser) parseSubscriptionCreated(event StripeEvent) (*WebhookResult, er
 		return nil, fmt.Errorf("missing user_id in metadata")
 	}
 
-	// Feature code is no longer required in metadata - it will be determined from the plan
-	featureCode := "" // Will be determined from plan
-
 	planIDString, ok := metadata["plan_id"].(string)
 	if !ok {
 		return nil, fmt.Errorf("missing plan_id in metadata")
@@ -288,7 +276,6 @@ func (p *Parser) parseSubscriptionCreated(event StripeEvent) (*WebhookResult, er
 	return &WebhookResult{
 		UserID:         userID,
 		FamilyID:       familyID,
-		FeatureCode:    featureCode,
 		PlanID:         planID,
 		PlanIDString:   planIDString,
 		SubscriptionID: subscriptionID,
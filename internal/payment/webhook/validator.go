@@ -117,16 +117,16 @@ func (v *Validator) constantTimeCompare(a, b string) bool {
 	return result == 0
 }
 
-// ValidatePayPalWebhook validates a PayPal webhook signature (placeholder)
+// ValidatePayPalWebhook rejects PayPal webhooks. PayPal signs webhooks with
+// a certificate rather than a shared secret; they are verified by
+// paypalbp.Adapter.ValidateWebhook, which holds the certificate.
 func (v *Validator) ValidatePayPalWebhook(payload []byte, signature string) error {
-	// TODO: Implement PayPal webhook validation
-	// PayPal uses different signature validation method
-	return fmt.Errorf("PayPal webhook validation not implemented")
+	return fmt.Errorf("PayPal webhooks are validated by the paypal billing provider")
 }
 
-// ValidateAdyenWebhook validates an Adyen webhook signature (placeholder)
+// ValidateAdyenWebhook rejects Adyen webhooks. Adyen signs each item of a
+// notification inside the payload with the webhook's HMAC key; they are
+// verified by adyenbp.Adapter.ValidateWebhook, which holds the key.
 func (v *Validator) ValidateAdyenWebhook(payload []byte, signature string) error {
-	// TODO: Implement Adyen webhook validation
-	// Adyen uses HMAC-SHA256 with different format
-	return fmt.Errorf("Adyen webhook validation not implemented")
+	return fmt.Errorf("Adyen webhooks are validated by the adyen billing provider")
 }
@@ -0,0 +1,18 @@
+package auth
+
+import "context"
+
+// adminKey is the context key of the marker set by WithAdmin
+type adminKey struct{}
+
+// WithAdmin returns a context marking the caller as an administrator, who
+// may call admin RPCs and act on other users' records
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey{}, true)
+}
+
+// IsAdmin reports whether the caller was marked as an administrator by WithAdmin
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
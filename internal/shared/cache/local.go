@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// localEntitlements is the in-process tier in front of Redis: a fixed-size
+// LRU of entitlement lookups. A nil entitlement caches a negative result.
+type localEntitlements struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	order      *list.List // Front is the most recently used
+	entries    map[string]*list.Element
+	generation uint64 // Bumped by every invalidation
+}
+
+type localEntry struct {
+	key         string
+	entitlement *domain.Entitlement
+	expiresAt   time.Time
+}
+
+func newLocalEntitlements(size int, ttl time.Duration) *localEntitlements {
+	return &localEntitlements{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns a copy of the cached lookup for key, if it has not expired
+func (l *localEntitlements) get(key string, now time.Time) (*domain.Entitlement, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*localEntry)
+	if !now.Before(entry.expiresAt) {
+		l.removeElement(elem)
+		return nil, false
+	}
+
+	l.order.MoveToFront(elem)
+	return copyEntitlement(entry.entitlement), true
+}
+
+// currentGeneration is read before a lookup goes to Redis so that add can
+// tell whether an invalidation raced it
+func (l *localEntitlements) currentGeneration() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.generation
+}
+
+// add caches a lookup for at most ttl, never past the entitlement's own
+// expiry. It is skipped if anything was invalidated since generation was read,
+// as the lookup may predate the invalidation.
+func (l *localEntitlements) add(key string, ent *domain.Entitlement, ttl time.Duration, generation uint64, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if generation != l.generation {
+		return
+	}
+
+	expiresAt := now.Add(min(ttl, l.ttl))
+	if ent != nil && ent.ExpiresAt != nil && ent.ExpiresAt.Before(expiresAt) {
+		expiresAt = *ent.ExpiresAt
+	}
+	entry := &localEntry{key: key, entitlement: copyEntitlement(ent), expiresAt: expiresAt}
+
+	if elem, ok := l.entries[key]; ok {
+		elem.Value = entry
+		l.order.MoveToFront(elem)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(entry)
+	for l.order.Len() > l.size {
+		l.removeElement(l.order.Back())
+	}
+}
+
+// remove drops key and invalidates lookups still in flight
+func (l *localEntitlements) remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	if elem, ok := l.entries[key]; ok {
+		l.removeElement(elem)
+	}
+}
+
+// clear drops every entry, e.g. after invalidations may have been missed
+func (l *localEntitlements) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.generation++
+	l.order.Init()
+	clear(l.entries)
+}
+
+func (l *localEntitlements) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+func (l *localEntitlements) removeElement(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.entries, elem.Value.(*localEntry).key)
+}
+
+// copyEntitlement keeps callers from mutating cached entitlements
+func copyEntitlement(ent *domain.Entitlement) *domain.Entitlement {
+	if ent == nil {
+		return nil
+	}
+	copied := *ent
+	return &copied
+}
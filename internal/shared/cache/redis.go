@@ -6,13 +6,20 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/jia-app/paymentservice/internal/payment/domain"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
-// Cache represents a Redis cache implementation
+// Cache represents a Redis cache implementation. Entitlements can also be
+// kept in an in-process tier in front of Redis; see NewTieredCache.
 type Cache struct {
-	client *redis.Client
+	client  *redis.Client
+	local   *localEntitlements        // Nil when the local tier is disabled
+	loads   singleflight.Group        // Collapses concurrent misses of the same entitlement
+	metrics *metrics.MetricsCollector // Can be nil if metrics are disabled
 }
 
 // NewCache creates a new Redis cache instance
@@ -113,7 +120,7 @@ func (c *Cache) Expire(ctx context.Context, key string, expiration time.Duration
 
 // GetEntitlement retrieves an entitlement from cache
 func (c *Cache) GetEntitlement(ctx context.Context, userID, featureCode string) (*domain.Entitlement, bool, error) {
-	key := fmt.Sprintf("entl:%s:%s", userID, featureCode)
+	key := entitlementKey(userID, featureCode)
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -134,7 +141,7 @@ func (c *Cache) GetEntitlement(ctx context.Context, userID, featureCode string)
 
 // SetEntitlement stores an entitlement in cache
 func (c *Cache) SetEntitlement(ctx context.Context, ent domain.Entitlement, ttl time.Duration) error {
-	key := fmt.Sprintf("entl:%s:%s", ent.UserID, ent.FeatureCode)
+	key := entitlementKey(ent.UserID, ent.FeatureCode)
 
 	// Default TTL to 2 minutes if not specified
 	if ttl <= 0 {
@@ -149,6 +156,7 @@ func (c *Cache) SetEntitlement(ctx context.Context, ent domain.Entitlement, ttl
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set entitlement in cache: %w", err)
 	}
+	c.dropLocal(key)
 
 	return nil
 }
@@ -156,7 +164,7 @@ func (c *Cache) SetEntitlement(ctx context.Context, ent domain.Entitlement, ttl
 // SetEntitlementNotFound caches a negative result for an entitlement
 // Uses a shorter TTL (10 seconds max) to avoid caching stale negative results
 func (c *Cache) SetEntitlementNotFound(ctx context.Context, userID, featureCode string) error {
-	key := fmt.Sprintf("entl:%s:%s", userID, featureCode)
+	key := entitlementKey(userID, featureCode)
 
 	// Cache negative result for 10 seconds maximum
 	ttl := 10 * time.Second
@@ -175,13 +183,14 @@ func (c *Cache) SetEntitlementNotFound(ctx context.Context, userID, featureCode
 	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
 		return fmt.Errorf("failed to set negative result in cache: %w", err)
 	}
+	c.dropLocal(key)
 
 	return nil
 }
 
 // IsEntitlementNotFound checks if the cached value represents a negative result
 func (c *Cache) IsEntitlementNotFound(ctx context.Context, userID, featureCode string) (bool, error) {
-	key := fmt.Sprintf("entl:%s:%s", userID, featureCode)
+	key := entitlementKey(userID, featureCode)
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -205,8 +214,22 @@ func (c *Cache) IsEntitlementNotFound(ctx context.Context, userID, featureCode s
 	return false, nil
 }
 
-// DeleteEntitlement removes an entitlement from cache
+// DeleteEntitlement removes an entitlement from cache, and tells every
+// replica to drop its local copy
 func (c *Cache) DeleteEntitlement(ctx context.Context, userID, featureCode string) error {
-	key := fmt.Sprintf("entl:%s:%s", userID, featureCode)
-	return c.client.Del(ctx, key).Err()
+	key := entitlementKey(userID, featureCode)
+	c.dropLocal(key)
+
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+	if err := c.client.Publish(ctx, EntitlementInvalidationChannel, key).Err(); err != nil {
+		return fmt.Errorf("failed to publish entitlement invalidation: %w", err)
+	}
+	return nil
+}
+
+// entitlementKey is the Redis key of a user's entitlement to a feature
+func entitlementKey(userID, featureCode string) string {
+	return fmt.Sprintf("entl:%s:%s", userID, featureCode)
 }
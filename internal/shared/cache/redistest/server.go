@@ -0,0 +1,398 @@
+// Package redistest is a fake Redis server for tests. It speaks enough of
+// RESP2 for go-redis clients to GET, SET, DEL, PUBLISH and SUBSCRIBE, keeps
+// data in memory, and can add a per-command delay to stand in for the
+// network hop to a real server.
+package redistest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config configures a fake server
+type Config struct {
+	Latency time.Duration // Delay before answering each command
+}
+
+// Server is a fake Redis server listening on a local port
+type Server struct {
+	Addr string // host:port to point a client at
+
+	cfg      Config
+	listener net.Listener
+	commands atomic.Int64
+
+	mu          sync.Mutex
+	values      map[string]entry
+	subscribers map[string]map[*conn]struct{}
+	conns       map[*conn]struct{}
+	wg          sync.WaitGroup
+}
+
+type entry struct {
+	value     string
+	expiresAt time.Time // Zero if the key does not expire
+}
+
+// NewServer starts a fake server on a random local port
+func NewServer(cfg Config) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("redistest: failed to listen: %v", err))
+	}
+
+	s := &Server{
+		Addr:        listener.Addr().String(),
+		cfg:         cfg,
+		listener:    listener,
+		values:      make(map[string]entry),
+		subscribers: make(map[string]map[*conn]struct{}),
+		conns:       make(map[*conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.accept()
+	return s
+}
+
+// Close stops the server and drops every connection
+func (s *Server) Close() {
+	s.listener.Close()
+
+	s.mu.Lock()
+	for c := range s.conns {
+		c.netConn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Commands returns how many commands the server has answered
+func (s *Server) Commands() int64 {
+	return s.commands.Load()
+}
+
+// Get returns a key's value as a test would observe it
+func (s *Server) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.getLocked(key)
+}
+
+func (s *Server) accept() {
+	defer s.wg.Done()
+	for {
+		netConn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c := &conn{netConn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.serve(c)
+	}
+}
+
+func (s *Server) serve(c *conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		for channel, subscribers := range s.subscribers {
+			delete(subscribers, c)
+			if len(subscribers) == 0 {
+				delete(s.subscribers, channel)
+			}
+		}
+		s.mu.Unlock()
+		c.netConn.Close()
+	}()
+
+	for {
+		args, err := readCommand(c.reader)
+		if err != nil {
+			return
+		}
+		if s.cfg.Latency > 0 {
+			time.Sleep(s.cfg.Latency)
+		}
+		s.commands.Add(1)
+		if err := s.execute(c, args); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) execute(c *conn, args []string) error {
+	if len(args) == 0 {
+		return c.write("-ERR empty command\r\n")
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		if c.isSubscribed() {
+			return c.write(arrayOf(bulk("pong"), bulk("")))
+		}
+		return c.write("+PONG\r\n")
+
+	case "GET":
+		if len(args) != 2 {
+			return c.write(wrongArgs(args[0]))
+		}
+		s.mu.Lock()
+		value, ok := s.getLocked(args[1])
+		s.mu.Unlock()
+		if !ok {
+			return c.write("$-1\r\n")
+		}
+		return c.write(bulk(value))
+
+	case "SET":
+		return s.set(c, args)
+
+	case "DEL":
+		if len(args) < 2 {
+			return c.write(wrongArgs(args[0]))
+		}
+		deleted := 0
+		s.mu.Lock()
+		for _, key := range args[1:] {
+			if _, ok := s.getLocked(key); ok {
+				deleted++
+			}
+			delete(s.values, key)
+		}
+		s.mu.Unlock()
+		return c.write(integer(deleted))
+
+	case "PUBLISH":
+		if len(args) != 3 {
+			return c.write(wrongArgs(args[0]))
+		}
+		s.mu.Lock()
+		receivers := make([]*conn, 0, len(s.subscribers[args[1]]))
+		for sub := range s.subscribers[args[1]] {
+			receivers = append(receivers, sub)
+		}
+		s.mu.Unlock()
+
+		message := arrayOf(bulk("message"), bulk(args[1]), bulk(args[2]))
+		for _, sub := range receivers {
+			sub.write(message)
+		}
+		return c.write(integer(len(receivers)))
+
+	case "SUBSCRIBE":
+		if len(args) < 2 {
+			return c.write(wrongArgs(args[0]))
+		}
+		for _, channel := range args[1:] {
+			s.mu.Lock()
+			if s.subscribers[channel] == nil {
+				s.subscribers[channel] = make(map[*conn]struct{})
+			}
+			s.subscribers[channel][c] = struct{}{}
+			count := c.subscribe(channel)
+			s.mu.Unlock()
+
+			if err := c.write(arrayOf(bulk("subscribe"), bulk(channel), integer(count))); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "UNSUBSCRIBE":
+		channels := args[1:]
+		if len(channels) == 0 {
+			channels = c.channels()
+		}
+		for _, channel := range channels {
+			s.mu.Lock()
+			delete(s.subscribers[channel], c)
+			count := c.unsubscribe(channel)
+			s.mu.Unlock()
+
+			if err := c.write(arrayOf(bulk("unsubscribe"), bulk(channel), integer(count))); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		// Includes HELLO and CLIENT, which clients fall back from
+		return c.write(fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0]))
+	}
+}
+
+func (s *Server) set(c *conn, args []string) error {
+	if len(args) < 3 {
+		return c.write(wrongArgs(args[0]))
+	}
+
+	e := entry{value: args[2]}
+	for i := 3; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		if (option != "EX" && option != "PX") || i+1 >= len(args) {
+			return c.write("-ERR syntax error\r\n")
+		}
+		n, err := strconv.ParseInt(args[i+1], 10, 64)
+		if err != nil || n <= 0 {
+			return c.write("-ERR invalid expire time in 'set' command\r\n")
+		}
+		unit := time.Second
+		if option == "PX" {
+			unit = time.Millisecond
+		}
+		e.expiresAt = time.Now().Add(time.Duration(n) * unit)
+		i++
+	}
+
+	s.mu.Lock()
+	s.values[args[1]] = e
+	s.mu.Unlock()
+	return c.write("+OK\r\n")
+}
+
+func (s *Server) getLocked(key string) (string, bool) {
+	e, ok := s.values[key]
+	if !ok {
+		return "", false
+	}
+	if !e.expiresAt.IsZero() && !time.Now().Before(e.expiresAt) {
+		delete(s.values, key)
+		return "", false
+	}
+	return e.value, true
+}
+
+// conn is a client connection. Writes are serialized because published
+// messages are written from the publisher's goroutine.
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+
+	writeMu sync.Mutex
+	writer  *bufio.Writer
+
+	subscriptions []string // Guarded by writeMu
+}
+
+func (c *conn) write(reply string) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := c.writer.WriteString(reply); err != nil {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+func (c *conn) isSubscribed() bool {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return len(c.subscriptions) > 0
+}
+
+func (c *conn) subscribe(channel string) int {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	for _, existing := range c.subscriptions {
+		if existing == channel {
+			return len(c.subscriptions)
+		}
+	}
+	c.subscriptions = append(c.subscriptions, channel)
+	return len(c.subscriptions)
+}
+
+func (c *conn) unsubscribe(channel string) int {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	for i, existing := range c.subscriptions {
+		if existing == channel {
+			c.subscriptions = append(c.subscriptions[:i], c.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return len(c.subscriptions)
+}
+
+func (c *conn) channels() []string {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return append([]string(nil), c.subscriptions...)
+}
+
+// readCommand reads one command sent as a RESP array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != '*' {
+		return nil, errors.New("redistest: expected an array")
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil {
+		return nil, fmt.Errorf("redistest: invalid array length: %w", err)
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if len(header) == 0 || header[0] != '$' {
+			return nil, errors.New("redistest: expected a bulk string")
+		}
+		size, err := strconv.Atoi(header[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redistest: invalid bulk length: %w", err)
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func integer(n int) string {
+	return fmt.Sprintf(":%d\r\n", n)
+}
+
+func arrayOf(items ...string) string {
+	return fmt.Sprintf("*%d\r\n%s", len(items), strings.Join(items, ""))
+}
+
+func wrongArgs(command string) string {
+	return fmt.Sprintf("-ERR wrong number of arguments for '%s' command\r\n", strings.ToLower(command))
+}
@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/metrics"
+)
+
+// EntitlementInvalidationChannel is the Redis pub/sub channel DeleteEntitlement
+// announces evicted entitlement keys on
+const EntitlementInvalidationChannel = "entitlements:cache:invalidate"
+
+// Redis TTLs of entitlement lookups, matching SetEntitlement and
+// SetEntitlementNotFound
+const (
+	entitlementTTL         = 2 * time.Minute
+	entitlementNotFoundTTL = 10 * time.Second
+)
+
+// entitlementLoadTimeout bounds a shared entitlement load, which runs apart
+// from the context of the caller that started it
+const entitlementLoadTimeout = 5 * time.Second
+
+// LocalConfig configures the in-process entitlement tier
+type LocalConfig struct {
+	Size int           // Entitlements kept per replica; 0 disables the local tier
+	TTL  time.Duration // Longest an entitlement is served locally without asking Redis
+}
+
+// NewTieredCache creates a cache on an existing Redis client with an
+// in-process LRU of entitlements in front of Redis. Run RunInvalidation
+// alongside it so that evictions made by other replicas reach this one.
+func NewTieredCache(client *redis.Client, local LocalConfig, metricsCollector *metrics.MetricsCollector) *Cache {
+	c := &Cache{client: client, metrics: metricsCollector}
+	if local.Size > 0 && local.TTL > 0 {
+		c.local = newLocalEntitlements(local.Size, local.TTL)
+	}
+	return c
+}
+
+// EntitlementLoader loads an entitlement from the source of truth on a cache
+// miss. It returns nil when the user has no valid entitlement to the feature.
+type EntitlementLoader func(ctx context.Context) (*domain.Entitlement, error)
+
+// LookupEntitlement returns a user's valid entitlement to a feature, or nil if
+// they have none, and whether it came from the cache. Lookups go to the local
+// tier, then Redis, then load; concurrent misses of the same entitlement share
+// one Redis read and one load. The shared load is not cancelled with the
+// caller that started it; a caller whose context ends stops waiting for it.
+func (c *Cache) LookupEntitlement(ctx context.Context, userID, featureCode string, load EntitlementLoader) (*domain.Entitlement, bool, error) {
+	start := time.Now()
+	key := entitlementKey(userID, featureCode)
+
+	if c.local != nil {
+		if ent, ok := c.local.get(key, start); ok && usableEntitlement(ent, start) {
+			c.metrics.RecordCacheOperation(ctx, true, time.Since(start))
+			return ent, true, nil
+		}
+	}
+
+	loaded := c.loads.DoChan(key, func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), entitlementLoadTimeout)
+		defer cancel()
+		return c.loadEntitlement(loadCtx, key, load)
+	})
+
+	var result singleflight.Result
+	select {
+	case result = <-loaded:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+	if result.Err != nil {
+		return nil, false, result.Err
+	}
+
+	lookup := result.Val.(entitlementLookup)
+	c.metrics.RecordCacheOperation(ctx, lookup.hit, time.Since(start))
+	return copyEntitlement(lookup.entitlement), lookup.hit, nil
+}
+
+// entitlementLookup is the result shared by collapsed lookups
+type entitlementLookup struct {
+	entitlement *domain.Entitlement
+	hit         bool
+}
+
+// loadEntitlement reads an entitlement through Redis, loading and caching it
+// on a miss. Redis errors fall through to load so that lookups keep working
+// while Redis is down.
+func (c *Cache) loadEntitlement(ctx context.Context, key string, load EntitlementLoader) (entitlementLookup, error) {
+	var generation uint64
+	if c.local != nil {
+		generation = c.local.currentGeneration()
+	}
+
+	if ent, found := c.getRemoteEntitlement(ctx, key); found {
+		c.addLocal(key, ent, generation)
+		return entitlementLookup{entitlement: ent, hit: true}, nil
+	}
+
+	ent, err := load(ctx)
+	if err != nil {
+		return entitlementLookup{}, err
+	}
+
+	var data []byte
+	ttl := entitlementNotFoundTTL
+	if ent != nil {
+		data, err = json.Marshal(ent)
+		ttl = entitlementTTL
+	} else {
+		data, err = json.Marshal(map[string]interface{}{"not_found": true, "cached_at": time.Now().Unix()})
+	}
+	if err == nil {
+		c.client.Set(ctx, key, data, ttl)
+	}
+
+	c.addLocal(key, ent, generation)
+	return entitlementLookup{entitlement: ent}, nil
+}
+
+// getRemoteEntitlement reads a cached lookup from Redis in one round trip.
+// Stale entitlements are not returned.
+func (c *Cache) getRemoteEntitlement(ctx context.Context, key string) (*domain.Entitlement, bool) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var marker struct {
+		NotFound bool `json:"not_found"`
+	}
+	if err := json.Unmarshal(data, &marker); err != nil {
+		return nil, false
+	}
+	if marker.NotFound {
+		return nil, true
+	}
+
+	var ent domain.Entitlement
+	if err := json.Unmarshal(data, &ent); err != nil || !usableEntitlement(&ent, time.Now()) {
+		return nil, false
+	}
+	return &ent, true
+}
+
+// usableEntitlement reports whether a cached lookup can still be served. A
+// negative result always can; an entitlement only until it expires.
+func usableEntitlement(ent *domain.Entitlement, now time.Time) bool {
+	if ent == nil {
+		return true
+	}
+	return ent.Status == "active" && (ent.ExpiresAt == nil || ent.ExpiresAt.After(now))
+}
+
+func (c *Cache) addLocal(key string, ent *domain.Entitlement, generation uint64) {
+	if c.local == nil {
+		return
+	}
+	ttl := entitlementTTL
+	if ent == nil {
+		ttl = entitlementNotFoundTTL
+	}
+	c.local.add(key, ent, ttl, generation, time.Now())
+}
+
+func (c *Cache) dropLocal(key string) {
+	if c.local != nil {
+		c.local.remove(key)
+	}
+}
+
+// RunInvalidation drops local entitlements evicted by any replica until ctx is
+// cancelled. The local tier is cleared whenever the subscription is
+// (re)established, since invalidations may have been missed while it was
+// down. It returns immediately when the local tier is disabled.
+func (c *Cache) RunInvalidation(ctx context.Context) error {
+	if c.local == nil {
+		return nil
+	}
+
+	pubsub := c.client.Subscribe(ctx, EntitlementInvalidationChannel)
+	defer pubsub.Close()
+
+	messages := pubsub.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			switch m := msg.(type) {
+			case *redis.Subscription:
+				if m.Kind == "subscribe" {
+					c.local.clear()
+				}
+			case *redis.Message:
+				c.local.remove(m.Payload)
+			}
+		}
+	}
+}
+
+// LocalEntitlements returns how many entitlement lookups this replica holds
+// in its local tier
+func (c *Cache) LocalEntitlements() int {
+	if c.local == nil {
+		return 0
+	}
+	return c.local.len()
+}
@@ -0,0 +1,293 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/shared/cache/redistest"
+)
+
+func newTestCache(t *testing.T, srv *redistest.Server, local LocalConfig) *Cache {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr})
+	t.Cleanup(func() { client.Close() })
+	return NewTieredCache(client, local, nil)
+}
+
+// countingLoader returns ent on every load and counts the loads
+func countingLoader(ent *domain.Entitlement, loads *atomic.Int32) EntitlementLoader {
+	return func(ctx context.Context) (*domain.Entitlement, error) {
+		loads.Add(1)
+		return ent, nil
+	}
+}
+
+func testEntitlement(userID, featureCode string) *domain.Entitlement {
+	return &domain.Entitlement{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FeatureCode: featureCode,
+		Status:      "active",
+		GrantedAt:   time.Now(),
+	}
+}
+
+func TestCache_LookupEntitlementTiers(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{})
+	defer srv.Close()
+	ctx := context.Background()
+	c := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+
+	var loads atomic.Int32
+	load := countingLoader(testEntitlement("user-1", "storage"), &loads)
+
+	ent, hit, err := c.LookupEntitlement(ctx, "user-1", "storage", load)
+	if err != nil || ent == nil || hit {
+		t.Fatalf("first lookup = %v, hit %v, err %v; want a loaded miss", ent, hit, err)
+	}
+	if _, ok := srv.Get(entitlementKey("user-1", "storage")); !ok {
+		t.Error("expected the loaded entitlement to be written to Redis")
+	}
+
+	commands := srv.Commands()
+	ent, hit, err = c.LookupEntitlement(ctx, "user-1", "storage", load)
+	if err != nil || ent == nil || !hit {
+		t.Fatalf("second lookup = %v, hit %v, err %v; want a hit", ent, hit, err)
+	}
+	if srv.Commands() != commands {
+		t.Errorf("expected a local hit to skip Redis, %d commands were sent", srv.Commands()-commands)
+	}
+	if loads.Load() != 1 {
+		t.Errorf("expected 1 load, got %d", loads.Load())
+	}
+
+	// Another replica without the entitlement locally reads it from Redis
+	other := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+	ent, hit, err = other.LookupEntitlement(ctx, "user-1", "storage", load)
+	if err != nil || ent == nil || !hit {
+		t.Fatalf("lookup on another replica = %v, hit %v, err %v; want a Redis hit", ent, hit, err)
+	}
+	if loads.Load() != 1 {
+		t.Errorf("expected the Redis hit not to load, got %d loads", loads.Load())
+	}
+}
+
+func TestCache_LookupEntitlementNegative(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{})
+	defer srv.Close()
+	ctx := context.Background()
+	c := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+
+	var loads atomic.Int32
+	for range 3 {
+		ent, _, err := c.LookupEntitlement(ctx, "user-1", "storage", countingLoader(nil, &loads))
+		if err != nil || ent != nil {
+			t.Fatalf("lookup = %v, err %v; want no entitlement", ent, err)
+		}
+	}
+	if loads.Load() != 1 {
+		t.Errorf("expected the negative result to be cached, got %d loads", loads.Load())
+	}
+
+	notFound, err := c.IsEntitlementNotFound(ctx, "user-1", "storage")
+	if err != nil || !notFound {
+		t.Errorf("expected a negative marker in Redis, got %v, err %v", notFound, err)
+	}
+}
+
+func TestCache_LookupEntitlementCollapsesMisses(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{Latency: 5 * time.Millisecond})
+	defer srv.Close()
+	c := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*domain.Entitlement, error) {
+		loads.Add(1)
+		<-release
+		return testEntitlement("user-1", "storage"), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ent, _, err := c.LookupEntitlement(context.Background(), "user-1", "storage", load); err != nil || ent == nil {
+				t.Errorf("lookup = %v, err %v", ent, err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if loads.Load() != 1 {
+		t.Errorf("expected concurrent misses to share 1 load, got %d", loads.Load())
+	}
+}
+
+func TestCache_LookupEntitlementOutlivesCancelledCaller(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{})
+	defer srv.Close()
+	c := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*domain.Entitlement, error) {
+		close(started)
+		select {
+		case <-release:
+			return testEntitlement("user-1", "storage"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// The caller that starts the load gives up while it is running
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := c.LookupEntitlement(first, "user-1", "storage", load)
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		ent, _, err := c.LookupEntitlement(context.Background(), "user-1", "storage", load)
+		if err == nil && ent == nil {
+			err = context.Canceled
+		}
+		second <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("cancelled lookup err = %v, want context.Canceled", err)
+	}
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("expected the waiting lookup to get the shared load, got %v", err)
+	}
+}
+
+func TestCache_DeleteEntitlementInvalidatesReplicas(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{})
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+	b := newTestCache(t, srv, LocalConfig{Size: 10, TTL: time.Minute})
+	done := make(chan error, 1)
+	go func() { done <- b.RunInvalidation(ctx) }()
+
+	var loads atomic.Int32
+	load := countingLoader(testEntitlement("user-1", "storage"), &loads)
+
+	// Wait until b is subscribed, which clears its local tier
+	deadline := time.Now().Add(2 * time.Second)
+	for a.client.Publish(ctx, EntitlementInvalidationChannel, "entl:nobody:nothing").Val() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the invalidation subscription")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The probe's invalidation may still be in flight and void the first fill
+	for b.LocalEntitlements() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for b to hold the entitlement locally")
+		}
+		if _, _, err := b.LookupEntitlement(ctx, "user-1", "storage", load); err != nil {
+			t.Fatalf("lookup error = %v", err)
+		}
+	}
+
+	if err := a.DeleteEntitlement(ctx, "user-1", "storage"); err != nil {
+		t.Fatalf("DeleteEntitlement() error = %v", err)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for b.LocalEntitlements() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for b to drop its local copy")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("RunInvalidation() error = %v", err)
+	}
+}
+
+func TestCache_LookupEntitlementWithoutLocalTier(t *testing.T) {
+	srv := redistest.NewServer(redistest.Config{})
+	defer srv.Close()
+	ctx := context.Background()
+	c := newTestCache(t, srv, LocalConfig{})
+
+	var loads atomic.Int32
+	load := countingLoader(testEntitlement("user-1", "storage"), &loads)
+	for range 2 {
+		if _, _, err := c.LookupEntitlement(ctx, "user-1", "storage", load); err != nil {
+			t.Fatalf("lookup error = %v", err)
+		}
+	}
+
+	commands := srv.Commands()
+	if _, hit, _ := c.LookupEntitlement(ctx, "user-1", "storage", load); !hit || srv.Commands() == commands {
+		t.Error("expected lookups to be served by Redis")
+	}
+	if loads.Load() != 1 || c.LocalEntitlements() != 0 {
+		t.Errorf("expected 1 load and nothing held locally, got %d loads and %d local", loads.Load(), c.LocalEntitlements())
+	}
+	if err := c.RunInvalidation(ctx); err != nil {
+		t.Errorf("RunInvalidation() error = %v", err)
+	}
+}
+
+func TestLocalEntitlements(t *testing.T) {
+	now := time.Now()
+	l := newLocalEntitlements(2, time.Minute)
+
+	for _, key := range []string{"a", "b"} {
+		l.add(key, testEntitlement("user-1", key), time.Hour, l.currentGeneration(), now)
+	}
+	l.get("a", now)
+	l.add("c", testEntitlement("user-1", "c"), time.Hour, l.currentGeneration(), now)
+	if _, ok := l.get("b", now); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := l.get("a", now); !ok {
+		t.Error("expected the recently used entry to be kept")
+	}
+
+	if _, ok := l.get("a", now.Add(time.Minute)); ok {
+		t.Error("expected entries to expire after the local TTL")
+	}
+
+	expiresAt := now.Add(time.Second)
+	ent := testEntitlement("user-1", "d")
+	ent.ExpiresAt = &expiresAt
+	l.add("d", ent, time.Hour, l.currentGeneration(), now)
+	if _, ok := l.get("d", expiresAt); ok {
+		t.Error("expected entries to expire with their entitlement")
+	}
+
+	generation := l.currentGeneration()
+	l.remove("c")
+	l.add("e", testEntitlement("user-1", "e"), time.Hour, generation, now)
+	if _, ok := l.get("e", now); ok {
+		t.Error("expected a fill that raced an invalidation to be skipped")
+	}
+}
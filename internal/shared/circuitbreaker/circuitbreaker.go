@@ -2,7 +2,7 @@ package circuitbreaker
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"sync"
 	"time"
 )
@@ -29,6 +29,10 @@ func (s State) String() string {
 	}
 }
 
+// ErrOpenState is returned instead of calling the protected function while
+// the circuit is open
+var ErrOpenState = errors.New("circuit breaker is open")
+
 // Config holds circuit breaker configuration
 type Config struct {
 	MaxRequests      uint32        // Maximum requests in half-open state
@@ -84,7 +88,7 @@ func NewCircuitBreakerWithCallback(config Config, onStateChange func(from, to St
 func (cb *CircuitBreaker) Execute(ctx context.Context, fn func() (interface{}, error)) (interface{}, error) {
 	// Check if we should allow the request
 	if !cb.canExecute() {
-		return nil, fmt.Errorf("circuit breaker is %s", cb.state.String())
+		return nil, ErrOpenState
 	}
 
 	// Execute the function
@@ -107,7 +111,7 @@ func (cb *CircuitBreaker) ExecuteAsync(ctx context.Context, fn func() (interface
 		if !cb.canExecute() {
 			resultChan <- Result{
 				Value: nil,
-				Error: fmt.Errorf("circuit breaker is %s", cb.state.String()),
+				Error: ErrOpenState,
 			}
 			return
 		}
@@ -11,16 +11,20 @@ import (
 type Config struct {
 	AppName          string                 `mapstructure:"app_name"`
 	GRPC             GRPCConfig             `mapstructure:"grpc"`
+	Admin            AdminConfig            `mapstructure:"admin"`
+	Storage          StorageConfig          `mapstructure:"storage"`
 	Postgres         PostgresConfig         `mapstructure:"postgres"`
 	Redis            RedisConfig            `mapstructure:"redis"`
 	Auth             AuthConfig             `mapstructure:"auth"`
 	Billing          BillingConfig          `mapstructure:"billing"`
+	Invoice          InvoiceConfig          `mapstructure:"invoice"`
 	Events           EventsConfig           `mapstructure:"events"`
 	Log              LogConfig              `mapstructure:"log"`
 	ServiceMesh      ServiceMeshConfig      `mapstructure:"service_mesh"`
 	MTLS             MTLSConfig             `mapstructure:"mtls"`
 	ExternalServices ExternalServicesConfig `mapstructure:"external_services"`
 	CircuitBreaker   CircuitBreakerConfig   `mapstructure:"circuit_breaker"`
+	Tracing          TracingConfig          `mapstructure:"tracing"`
 }
 
 // GRPCConfig holds gRPC server configuration
@@ -29,6 +33,11 @@ type GRPCConfig struct {
 	EnableReflection bool   `mapstructure:"enable_reflection"` // Enable gRPC reflection (for debugging)
 }
 
+// AdminConfig holds the HTTP admin server configuration
+type AdminConfig struct {
+	Address string `mapstructure:"address"` // Admin HTTP address serving /metrics, /healthz and /readyz (e.g., ":9090")
+}
+
 // APIGatewayConfig holds API Gateway configuration
 type APIGatewayConfig struct {
 	Address           string `mapstructure:"address"`
@@ -41,6 +50,17 @@ type APIGatewayConfig struct {
 	RequestTimeoutSec int    `mapstructure:"request_timeout_seconds"`
 }
 
+// Storage modes accepted by StorageConfig.Mode
+const (
+	StorageModePostgres = "postgres"
+	StorageModeMemory   = "memory"
+)
+
+// StorageConfig selects where the payment repositories keep their data
+type StorageConfig struct {
+	Mode string `mapstructure:"mode"` // postgres, or memory for demos (nothing is persisted)
+}
+
 // PostgresConfig holds PostgreSQL configuration
 type PostgresConfig struct {
 	DSN      string `mapstructure:"dsn"`       // Database connection string
@@ -49,22 +69,88 @@ type PostgresConfig struct {
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Addr     string `mapstructure:"addr"`     // Redis server address (e.g., "localhost:6379")
-	DB       int    `mapstructure:"db"`       // Redis database number
-	Password string `mapstructure:"password"` // Redis password (optional)
+	Addr             string `mapstructure:"addr"`                    // Redis server address (e.g., "localhost:6379")
+	DB               int    `mapstructure:"db"`                      // Redis database number
+	Password         string `mapstructure:"password"`                // Redis password (optional)
+	LocalCacheSize   int    `mapstructure:"local_cache_size"`        // Entitlements cached in process in front of Redis (0 disables)
+	LocalCacheTTLSec int    `mapstructure:"local_cache_ttl_seconds"` // Longest an entitlement is served from the process cache
 }
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	PublicKeyPEM string `mapstructure:"public_key_pem"`
+	PublicKeyPEM  string   `mapstructure:"public_key_pem"`
+	AdminSubjects []string `mapstructure:"admin_subjects"` // User and spiffe IDs allowed to call admin RPCs
 }
 
 // BillingConfig holds billing provider configuration
 type BillingConfig struct {
-	Provider            string `mapstructure:"provider"`
+	Provider            string `mapstructure:"provider"` // stripe, paypal, adyen, mock or routing
 	StripeSecret        string `mapstructure:"stripe_secret"`
 	StripePublishable   string `mapstructure:"stripe_publishable"`
 	StripeWebhookSecret string `mapstructure:"stripe_webhook_secret"`
+	StripeBaseURL       string `mapstructure:"stripe_base_url"` // API base URL; empty uses Stripe's
+
+	PayPalClientID     string `mapstructure:"paypal_client_id"`
+	PayPalClientSecret string `mapstructure:"paypal_client_secret"`
+	PayPalBaseURL      string `mapstructure:"paypal_base_url"`     // REST API base URL, sandbox or live
+	PayPalWebhookID    string `mapstructure:"paypal_webhook_id"`   // ID of the webhook subscription, part of every signed message
+	PayPalWebhookCert  string `mapstructure:"paypal_webhook_cert"` // Path to the PEM certificate webhooks are signed with
+
+	AdyenAPIKey          string `mapstructure:"adyen_api_key"`
+	AdyenMerchantAccount string `mapstructure:"adyen_merchant_account"`
+	AdyenBaseURL         string `mapstructure:"adyen_base_url"` // Checkout API base URL including the version, test or live
+	AdyenHMACKey         string `mapstructure:"adyen_hmac_key"` // Hex HMAC key notifications are signed with
+
+	Routing          RoutingConfig          `mapstructure:"routing"`
+	CheckoutExpiry   CheckoutExpiryConfig   `mapstructure:"checkout_expiry"`
+	Reconciliation   ReconciliationConfig   `mapstructure:"reconciliation"`
+	RevenueSnapshots RevenueSnapshotsConfig `mapstructure:"revenue_snapshots"`
+}
+
+// RoutingConfig holds the providers checkouts are routed between when the
+// billing provider is "routing"
+type RoutingConfig struct {
+	Providers []string            `mapstructure:"providers"` // Providers to load; the first is the default
+	Rules     []RoutingRuleConfig `mapstructure:"rules"`     // First matching rule wins
+}
+
+// RoutingRuleConfig picks the providers a checkout is tried with, in order.
+// Empty criteria match anything.
+type RoutingRuleConfig struct {
+	Countries  []string `mapstructure:"countries"`  // ISO country codes of the pricing zone
+	Currencies []string `mapstructure:"currencies"` // ISO currency codes
+	Plans      []string `mapstructure:"plans"`      // Plan IDs or plan codes
+	Providers  []string `mapstructure:"providers"`
+}
+
+// CheckoutExpiryConfig holds checkout session expiry configuration
+type CheckoutExpiryConfig struct {
+	IntervalSec          int            `mapstructure:"interval_seconds"`       // How often to look for expired sessions
+	BatchSize            int            `mapstructure:"batch_size"`             // Sessions expired per pass
+	DefaultWindowMinutes int            `mapstructure:"default_window_minutes"` // Session lifetime for providers without an override
+	WindowMinutes        map[string]int `mapstructure:"window_minutes"`         // Session lifetime per provider, e.g. {"stripe": 1440}
+}
+
+// ReconciliationConfig holds scheduled provider reconciliation configuration
+type ReconciliationConfig struct {
+	IntervalMinutes int  `mapstructure:"interval_minutes"` // How often to reconcile
+	WindowHours     int  `mapstructure:"window_hours"`     // How far back each run looks
+	DelayMinutes    int  `mapstructure:"delay_minutes"`    // Skip records younger than this; their webhooks may be in flight
+	AutoHeal        bool `mapstructure:"auto_heal"`        // Re-apply missed completions
+}
+
+// RevenueSnapshotsConfig holds the daily revenue snapshot configuration
+type RevenueSnapshotsConfig struct {
+	IntervalMinutes int `mapstructure:"interval_minutes"` // How often to look for days to snapshot
+	BackfillDays    int `mapstructure:"backfill_days"`    // Days snapshotted when there is no snapshot yet
+}
+
+// InvoiceConfig holds the seller details printed on invoices
+type InvoiceConfig struct {
+	SellerName    string   `mapstructure:"seller_name"`    // Legal name of the issuer; defaults to app_name
+	SellerAddress []string `mapstructure:"seller_address"` // Address lines as printed
+	SellerEmail   string   `mapstructure:"seller_email"`   // Billing contact address
+	SellerTaxID   string   `mapstructure:"seller_tax_id"`  // Issuer tax ID, e.g. a VAT number
 }
 
 // EventsConfig holds event streaming configuration
@@ -127,6 +213,17 @@ type CircuitBreakerConfig struct {
 	HalfOpenMaxCalls int  `mapstructure:"half_open_max_calls"`
 }
 
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`      // Enable span export
+	Exporter    string  `mapstructure:"exporter"`     // Exporter: "otlp", "stdout", "file" or "none"
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP gRPC collector endpoint (e.g., "otel-collector:4317")
+	Insecure    bool    `mapstructure:"insecure"`     // Disable TLS for the OTLP exporter
+	FilePath    string  `mapstructure:"file_path"`    // Output path for the file exporter
+	SampleRatio float64 `mapstructure:"sample_ratio"` // Fraction of new traces to sample (0 or 1 samples all)
+	ServiceName string  `mapstructure:"service_name"` // Overrides app_name as the reported service name
+}
+
 // Load loads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
@@ -180,12 +277,28 @@ func setDefaults() {
 	viper.SetDefault("app_name", "payment-service")
 	viper.SetDefault("grpc.address", ":8081")
 	viper.SetDefault("grpc.enable_reflection", false)
+	viper.SetDefault("admin.address", ":9090")
+	viper.SetDefault("storage.mode", StorageModePostgres)
 	viper.SetDefault("postgres.max_conns", 10)
 	viper.SetDefault("redis.addr", "localhost:6379")
 	viper.SetDefault("redis.db", 0)
+	viper.SetDefault("redis.local_cache_size", 10000)
+	viper.SetDefault("redis.local_cache_ttl_seconds", 30)
 	viper.SetDefault("auth.public_key_pem", "")
 	viper.SetDefault("billing.provider", "stripe")
 	viper.SetDefault("billing.stripe_publishable", "")
+	viper.SetDefault("billing.paypal_base_url", "https://api-m.sandbox.paypal.com")
+	viper.SetDefault("billing.adyen_base_url", "https://checkout-test.adyen.com/v71")
+	viper.SetDefault("billing.checkout_expiry.interval_seconds", 60)
+	viper.SetDefault("billing.checkout_expiry.batch_size", 100)
+	viper.SetDefault("billing.checkout_expiry.default_window_minutes", 60)
+	viper.SetDefault("billing.checkout_expiry.window_minutes", map[string]int{"stripe": 1440, "paypal": 180, "adyen": 1440})
+	viper.SetDefault("billing.reconciliation.interval_minutes", 60)
+	viper.SetDefault("billing.reconciliation.window_hours", 25)
+	viper.SetDefault("billing.reconciliation.delay_minutes", 15)
+	viper.SetDefault("billing.reconciliation.auto_heal", false)
+	viper.SetDefault("billing.revenue_snapshots.interval_minutes", 60)
+	viper.SetDefault("billing.revenue_snapshots.backfill_days", 31)
 	viper.SetDefault("events.provider", "kafka")
 	viper.SetDefault("events.topic", "payments")
 	viper.SetDefault("log.level", "info")
@@ -214,6 +327,13 @@ func setDefaults() {
 	viper.SetDefault("circuit_breaker.success_threshold", 2)
 	viper.SetDefault("circuit_breaker.timeout_seconds", 60)
 	viper.SetDefault("circuit_breaker.half_open_max_calls", 3)
+
+	// Tracing defaults
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.sample_ratio", 1.0)
 }
 
 // Validate validates the configuration
@@ -224,14 +344,32 @@ func (c *Config) Validate() error {
 	if c.GRPC.Address == "" {
 		return fmt.Errorf("grpc.address is required")
 	}
-	if c.Postgres.DSN == "" {
-		return fmt.Errorf("postgres.dsn is required")
-	}
-	if c.Postgres.MaxConns <= 0 {
-		return fmt.Errorf("postgres.max_conns must be greater than 0")
+	switch c.Storage.Mode {
+	case StorageModePostgres:
+		if c.Postgres.DSN == "" {
+			return fmt.Errorf("postgres.dsn is required")
+		}
+		if c.Postgres.MaxConns <= 0 {
+			return fmt.Errorf("postgres.max_conns must be greater than 0")
+		}
+	case StorageModeMemory:
+	default:
+		return fmt.Errorf("storage.mode must be %s or %s", StorageModePostgres, StorageModeMemory)
 	}
 	if c.Redis.Addr == "" {
 		return fmt.Errorf("redis.addr is required")
 	}
+	if c.Redis.LocalCacheSize < 0 {
+		return fmt.Errorf("redis.local_cache_size must not be negative")
+	}
+	if c.Billing.Provider == "routing" && len(c.Billing.Routing.Providers) == 0 {
+		return fmt.Errorf("billing.routing.providers is required when billing.provider is routing")
+	}
+	if c.Billing.CheckoutExpiry.DefaultWindowMinutes < 0 {
+		return fmt.Errorf("billing.checkout_expiry.default_window_minutes must not be negative")
+	}
+	if c.Billing.Reconciliation.DelayMinutes < 0 {
+		return fmt.Errorf("billing.reconciliation.delay_minutes must not be negative")
+	}
 	return nil
 }
@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/tracing"
 )
 
 // Pool represents a PostgreSQL connection pool
@@ -52,6 +53,7 @@ func NewPool(ctx context.Context, config *Config) (*Pool, error) {
 	poolConfig.MaxConnLifetime = config.MaxConnLifetime
 	poolConfig.MaxConnIdleTime = config.MaxConnIdleTime
 	poolConfig.HealthCheckPeriod = config.HealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = tracing.NewPgxTracer()
 
 	// Create pool
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
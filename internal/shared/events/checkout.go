@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// EventTypeCheckoutAbandoned is published when a checkout session expires unpaid
+const EventTypeCheckoutAbandoned = "checkout.abandoned"
+
+// CheckoutAbandonedEvent carries what a customer was offered at checkout so
+// that follow-up (e.g. abandoned-cart emails) can reference the plan and price
+type CheckoutAbandonedEvent struct {
+	Type              string  `json:"type"`
+	SessionID         string  `json:"session_id"`
+	Provider          string  `json:"provider"`
+	UserID            string  `json:"user_id"`
+	FamilyID          *string `json:"family_id,omitempty"`
+	PlanID            string  `json:"plan_id"`
+	PlanVersion       int32   `json:"plan_version"`
+	CountryCode       string  `json:"country_code,omitempty"`
+	BasePrice         float64 `json:"base_price"`         // Catalog price in dollars
+	QuotedPrice       float64 `json:"quoted_price"`       // Price offered to the customer in dollars
+	PricingMultiplier float64 `json:"pricing_multiplier"` // Applied pricing zone multiplier
+	Currency          string  `json:"currency"`
+	CreatedAt         int64   `json:"created_at"` // When the checkout was started
+	ExpiredAt         int64   `json:"expired_at"` // When the session expired
+}
+
+// NewCheckoutAbandonedEvent builds the abandoned event for an expired session
+func NewCheckoutAbandonedEvent(s domain.CheckoutSession) *CheckoutAbandonedEvent {
+	return &CheckoutAbandonedEvent{
+		Type:              EventTypeCheckoutAbandoned,
+		SessionID:         s.ProviderSessionID,
+		Provider:          s.Provider,
+		UserID:            s.UserID,
+		FamilyID:          s.FamilyID,
+		PlanID:            s.PlanCode,
+		PlanVersion:       s.PlanVersion,
+		CountryCode:       s.CountryCode,
+		BasePrice:         s.BasePrice,
+		QuotedPrice:       s.QuotedPrice,
+		PricingMultiplier: s.PricingMultiplier,
+		Currency:          s.Currency,
+		CreatedAt:         s.CreatedAt.Unix(),
+		ExpiredAt:         s.ExpiresAt.Unix(),
+	}
+}
+
+// CheckoutPublisher defines the interface for publishing checkout events
+type CheckoutPublisher interface {
+	// PublishCheckoutAbandoned publishes a checkout.abandoned event
+	PublishCheckoutAbandoned(ctx context.Context, event *CheckoutAbandonedEvent) error
+}
+
+// PublishCheckoutAbandoned implements CheckoutPublisher for NoopPublisher
+func (NoopPublisher) PublishCheckoutAbandoned(ctx context.Context, event *CheckoutAbandonedEvent) error {
+	return nil
+}
+
+// PublishCheckoutAbandoned implements CheckoutPublisher for KafkaPublisher
+func (p *KafkaPublisher) PublishCheckoutAbandoned(ctx context.Context, event *CheckoutAbandonedEvent) error {
+	// TODO: Implement actual Kafka publishing logic
+	p.logger.Info("Publishing checkout abandoned event to Kafka",
+		zap.String("topic", p.topic),
+		zap.String("session_id", event.SessionID),
+		zap.String("user_id", event.UserID),
+		zap.String("plan_id", event.PlanID),
+		zap.Float64("quoted_price", event.QuotedPrice),
+		zap.String("currency", event.Currency),
+	)
+	return nil
+}
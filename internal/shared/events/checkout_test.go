@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func TestNewCheckoutAbandonedEvent(t *testing.T) {
+	familyID := "family123"
+	expiresAt := time.Now().Truncate(time.Second)
+	session := domain.CheckoutSession{
+		ID:                uuid.New(),
+		Provider:          "stripe",
+		ProviderSessionID: "cs_test_123",
+		PlanCode:          "pro_monthly",
+		PlanVersion:       2,
+		UserID:            "user123",
+		FamilyID:          &familyID,
+		CountryCode:       "IN",
+		BasePrice:         19.99,
+		QuotedPrice:       6,
+		PricingMultiplier: 0.3,
+		Currency:          "USD",
+		Status:            domain.CheckoutSessionStatusExpired,
+		ExpiresAt:         expiresAt,
+	}
+
+	event := NewCheckoutAbandonedEvent(session)
+
+	if event.Type != EventTypeCheckoutAbandoned {
+		t.Errorf("expected type %s, got %s", EventTypeCheckoutAbandoned, event.Type)
+	}
+	if event.SessionID != "cs_test_123" || event.PlanID != "pro_monthly" || event.PlanVersion != 2 {
+		t.Errorf("unexpected session fields: %+v", event)
+	}
+	if event.QuotedPrice != 6 || event.BasePrice != 19.99 || event.Currency != "USD" {
+		t.Errorf("unexpected price fields: %+v", event)
+	}
+	if event.FamilyID == nil || *event.FamilyID != familyID {
+		t.Errorf("expected family ID %s", familyID)
+	}
+	if event.ExpiredAt != expiresAt.Unix() {
+		t.Errorf("expected expired_at %d, got %d", expiresAt.Unix(), event.ExpiredAt)
+	}
+}
+
+func TestCheckoutPublisherInterface(t *testing.T) {
+	var _ CheckoutPublisher = NoopPublisher{}
+	var _ CheckoutPublisher = &KafkaPublisher{}
+
+	event := &CheckoutAbandonedEvent{Type: EventTypeCheckoutAbandoned, SessionID: "cs_test_123"}
+	if err := NewKafkaPublisher("checkout", zap.NewNop()).PublishCheckoutAbandoned(context.Background(), event); err != nil {
+		t.Errorf("Expected no error from KafkaPublisher, got: %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+// Entitlement change types delivered to watchers
+const (
+	EntitlementChangeGranted = "granted"
+	EntitlementChangeRevoked = "revoked"
+	EntitlementChangeExpired = "expired"
+	EntitlementChangeUpdated = "updated"
+)
+
+// EntitlementChangesChannel is the Redis pub/sub channel entitlement changes
+// are fanned out on between replicas
+const EntitlementChangesChannel = "entitlements:changes"
+
+// entitlementWatchBuffer is how many changes a watcher may fall behind by
+// before it is dropped
+const entitlementWatchBuffer = 64
+
+// ErrEntitlementWatchLagged is reported by a watch that was dropped because
+// its consumer did not keep up
+var ErrEntitlementWatchLagged = errors.New("entitlement watch fell behind")
+
+// EntitlementChange is an entitlement grant, revoke, expiry or update as seen
+// by watchers
+type EntitlementChange struct {
+	Type        string             `json:"type"`
+	Action      string             `json:"action"` // Action passed to PublishEntitlementUpdated
+	Entitlement domain.Entitlement `json:"entitlement"`
+	OccurredAt  time.Time          `json:"occurred_at"`
+}
+
+// NewEntitlementChange classifies a published entitlement action
+func NewEntitlementChange(e domain.Entitlement, action string) EntitlementChange {
+	changeType := EntitlementChangeUpdated
+	switch {
+	case action == EntitlementChangeExpired:
+		changeType = EntitlementChangeExpired
+	case e.Status == "revoked" || action == EntitlementChangeRevoked:
+		changeType = EntitlementChangeRevoked
+	case action == "created" || action == "webhook_created" || action == EntitlementChangeGranted:
+		changeType = EntitlementChangeGranted
+	}
+
+	return EntitlementChange{
+		Type:        changeType,
+		Action:      action,
+		Entitlement: e,
+		OccurredAt:  time.Now().UTC(),
+	}
+}
+
+// EntitlementHub fans entitlement changes out to the watchers on this
+// replica. It implements EntitlementPublisher so it can be handed to the use
+// cases directly when there is a single replica; with several replicas, use
+// RedisEntitlementFanout in front of it.
+type EntitlementHub struct {
+	mu       sync.Mutex
+	watchers map[string]map[*EntitlementWatch]struct{} // Keyed by user or family
+}
+
+// NewEntitlementHub creates an entitlement hub with no watchers
+func NewEntitlementHub() *EntitlementHub {
+	return &EntitlementHub{watchers: make(map[string]map[*EntitlementWatch]struct{})}
+}
+
+// PublishEntitlementUpdated implements EntitlementPublisher for EntitlementHub
+func (h *EntitlementHub) PublishEntitlementUpdated(ctx context.Context, e domain.Entitlement, action string) error {
+	h.Deliver(NewEntitlementChange(e, action))
+	return nil
+}
+
+// Deliver sends a change to every watcher of its user or family. Watchers
+// whose buffer is full are dropped rather than blocking the publisher.
+func (h *EntitlementHub) Deliver(change EntitlementChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := []string{userWatchKey(change.Entitlement.UserID)}
+	if change.Entitlement.FamilyID != nil && *change.Entitlement.FamilyID != "" {
+		keys = append(keys, familyWatchKey(*change.Entitlement.FamilyID))
+	}
+
+	delivered := make(map[*EntitlementWatch]struct{})
+	for _, key := range keys {
+		for w := range h.watchers[key] {
+			if _, ok := delivered[w]; ok {
+				continue
+			}
+			delivered[w] = struct{}{}
+
+			select {
+			case w.changes <- change:
+			default:
+				w.err = ErrEntitlementWatchLagged
+				h.removeLocked(w)
+			}
+		}
+	}
+}
+
+// Watch starts watching the changes of a user and, optionally, their families
+func (h *EntitlementHub) Watch(userID string, familyIDs ...string) *EntitlementWatch {
+	w := &EntitlementWatch{
+		hub:     h,
+		changes: make(chan EntitlementChange, entitlementWatchBuffer),
+		keys:    make(map[string]struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.addKeyLocked(w, userWatchKey(userID))
+	for _, familyID := range familyIDs {
+		if familyID != "" {
+			h.addKeyLocked(w, familyWatchKey(familyID))
+		}
+	}
+	return w
+}
+
+// Watchers returns the number of open watches on this replica
+func (h *EntitlementHub) Watchers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	open := make(map[*EntitlementWatch]struct{})
+	for _, watchers := range h.watchers {
+		for w := range watchers {
+			open[w] = struct{}{}
+		}
+	}
+	return len(open)
+}
+
+func (h *EntitlementHub) addKeyLocked(w *EntitlementWatch, key string) {
+	if w.closed {
+		return
+	}
+	if h.watchers[key] == nil {
+		h.watchers[key] = make(map[*EntitlementWatch]struct{})
+	}
+	h.watchers[key][w] = struct{}{}
+	w.keys[key] = struct{}{}
+}
+
+func (h *EntitlementHub) removeLocked(w *EntitlementWatch) {
+	if w.closed {
+		return
+	}
+	w.closed = true
+	for key := range w.keys {
+		delete(h.watchers[key], w)
+		if len(h.watchers[key]) == 0 {
+			delete(h.watchers, key)
+		}
+	}
+	close(w.changes)
+}
+
+func userWatchKey(userID string) string     { return "user:" + userID }
+func familyWatchKey(familyID string) string { return "family:" + familyID }
+
+// EntitlementWatch receives the entitlement changes of one user and their
+// families until it is closed
+type EntitlementWatch struct {
+	hub     *EntitlementHub
+	changes chan EntitlementChange
+
+	// Guarded by hub.mu
+	keys   map[string]struct{}
+	closed bool
+	err    error
+}
+
+// Changes returns the channel changes are delivered on. It is closed when the
+// watch is closed or dropped; Err reports which.
+func (w *EntitlementWatch) Changes() <-chan EntitlementChange {
+	return w.changes
+}
+
+// AddFamily also delivers the changes of a family to this watch
+func (w *EntitlementWatch) AddFamily(familyID string) {
+	if familyID == "" {
+		return
+	}
+
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+	w.hub.addKeyLocked(w, familyWatchKey(familyID))
+}
+
+// Err returns ErrEntitlementWatchLagged if the watch was dropped for falling
+// behind, and nil otherwise
+func (w *EntitlementWatch) Err() error {
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+	return w.err
+}
+
+// Close stops the watch. It is safe to call more than once.
+func (w *EntitlementWatch) Close() {
+	w.hub.mu.Lock()
+	defer w.hub.mu.Unlock()
+	w.hub.removeLocked(w)
+}
+
+// RedisEntitlementFanout publishes entitlement changes on a Redis channel and
+// relays the changes of every replica, its own included, into a local hub,
+// so a watcher sees changes made by any replica
+type RedisEntitlementFanout struct {
+	client  *redis.Client
+	channel string
+	hub     *EntitlementHub
+	logger  *zap.Logger
+}
+
+// NewRedisEntitlementFanout creates a fan-out over EntitlementChangesChannel
+func NewRedisEntitlementFanout(client *redis.Client, hub *EntitlementHub, logger *zap.Logger) *RedisEntitlementFanout {
+	return &RedisEntitlementFanout{
+		client:  client,
+		channel: EntitlementChangesChannel,
+		hub:     hub,
+		logger:  logger,
+	}
+}
+
+// PublishEntitlementUpdated implements EntitlementPublisher for
+// RedisEntitlementFanout. The change reaches local watchers through Run.
+func (f *RedisEntitlementFanout) PublishEntitlementUpdated(ctx context.Context, e domain.Entitlement, action string) error {
+	payload, err := json.Marshal(NewEntitlementChange(e, action))
+	if err != nil {
+		return fmt.Errorf("failed to encode entitlement change: %w", err)
+	}
+
+	if err := f.client.Publish(ctx, f.channel, payload).Err(); err != nil {
+		return fmt.Errorf("failed to publish entitlement change: %w", err)
+	}
+	return nil
+}
+
+// Run relays changes from Redis into the hub until ctx is cancelled. The
+// subscription reconnects on its own after Redis errors.
+func (f *RedisEntitlementFanout) Run(ctx context.Context) error {
+	pubsub := f.client.Subscribe(ctx, f.channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var change EntitlementChange
+			if err := json.Unmarshal([]byte(msg.Payload), &change); err != nil {
+				f.logger.Warn("Dropping malformed entitlement change",
+					zap.String("channel", f.channel),
+					zap.Error(err))
+				continue
+			}
+			f.hub.Deliver(change)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+)
+
+func watchedEntitlement(userID string, familyID *string) domain.Entitlement {
+	return domain.Entitlement{
+		ID:          uuid.New(),
+		UserID:      userID,
+		FamilyID:    familyID,
+		FeatureCode: "storage",
+		Status:      "active",
+	}
+}
+
+func TestEntitlementHub_DeliversToUserAndFamily(t *testing.T) {
+	hub := NewEntitlementHub()
+	ctx := context.Background()
+	family := "family-1"
+
+	user := hub.Watch("user-1")
+	defer user.Close()
+	member := hub.Watch("user-2", family)
+	defer member.Close()
+
+	if err := hub.PublishEntitlementUpdated(ctx, watchedEntitlement("user-1", &family), "webhook_created"); err != nil {
+		t.Fatalf("PublishEntitlementUpdated() error = %v", err)
+	}
+
+	for name, w := range map[string]*EntitlementWatch{"user": user, "family member": member} {
+		select {
+		case change := <-w.Changes():
+			if change.Type != EntitlementChangeGranted || change.Action != "webhook_created" {
+				t.Errorf("%s: unexpected change %+v", name, change)
+			}
+		default:
+			t.Errorf("%s: expected a change", name)
+		}
+	}
+
+	hub.Deliver(NewEntitlementChange(watchedEntitlement("user-3", nil), "created"))
+	select {
+	case change := <-user.Changes():
+		t.Errorf("expected no change for another user, got %+v", change)
+	default:
+	}
+}
+
+func TestEntitlementHub_DeliversOncePerWatch(t *testing.T) {
+	hub := NewEntitlementHub()
+	family := "family-1"
+
+	w := hub.Watch("user-1", family)
+	defer w.Close()
+
+	hub.Deliver(NewEntitlementChange(watchedEntitlement("user-1", &family), "created"))
+	<-w.Changes()
+	select {
+	case change := <-w.Changes():
+		t.Errorf("expected a single delivery, got a second %+v", change)
+	default:
+	}
+}
+
+func TestEntitlementHub_AddFamily(t *testing.T) {
+	hub := NewEntitlementHub()
+	family := "family-1"
+
+	w := hub.Watch("user-1")
+	defer w.Close()
+	w.AddFamily(family)
+
+	hub.Deliver(NewEntitlementChange(watchedEntitlement("user-2", &family), "created"))
+	select {
+	case <-w.Changes():
+	default:
+		t.Error("expected the family's change after AddFamily")
+	}
+}
+
+func TestEntitlementHub_DropsLaggingWatch(t *testing.T) {
+	hub := NewEntitlementHub()
+	w := hub.Watch("user-1")
+
+	for range entitlementWatchBuffer + 1 {
+		hub.Deliver(NewEntitlementChange(watchedEntitlement("user-1", nil), "created"))
+	}
+
+	received := 0
+	for range w.Changes() {
+		received++
+	}
+	if received != entitlementWatchBuffer {
+		t.Errorf("expected %d buffered changes before the watch closed, got %d", entitlementWatchBuffer, received)
+	}
+	if !errors.Is(w.Err(), ErrEntitlementWatchLagged) {
+		t.Errorf("expected ErrEntitlementWatchLagged, got %v", w.Err())
+	}
+	if hub.Watchers() != 0 {
+		t.Errorf("expected the lagging watch to be removed, %d remain", hub.Watchers())
+	}
+
+	// Closing a dropped watch is a no-op
+	w.Close()
+}
+
+func TestEntitlementWatch_Close(t *testing.T) {
+	hub := NewEntitlementHub()
+	w := hub.Watch("user-1", "family-1")
+	w.Close()
+	w.Close()
+
+	if _, ok := <-w.Changes(); ok {
+		t.Error("expected the changes channel to be closed")
+	}
+	if w.Err() != nil {
+		t.Errorf("expected no error after Close, got %v", w.Err())
+	}
+	if hub.Watchers() != 0 {
+		t.Errorf("expected no watchers after Close, got %d", hub.Watchers())
+	}
+
+	// Delivering after close must not panic
+	hub.Deliver(NewEntitlementChange(watchedEntitlement("user-1", nil), "created"))
+}
+
+func TestNewEntitlementChange(t *testing.T) {
+	tests := []struct {
+		action string
+		status string
+		want   string
+	}{
+		{"created", "active", EntitlementChangeGranted},
+		{"webhook_created", "active", EntitlementChangeGranted},
+		{"revoked", "revoked", EntitlementChangeRevoked},
+		{"updated", "revoked", EntitlementChangeRevoked},
+		{"expired", "active", EntitlementChangeExpired},
+		{"updated", "suspended", EntitlementChangeUpdated},
+	}
+
+	for _, tt := range tests {
+		e := watchedEntitlement("user-1", nil)
+		e.Status = tt.status
+		if got := NewEntitlementChange(e, tt.action).Type; got != tt.want {
+			t.Errorf("NewEntitlementChange(status %q, action %q) = %q, want %q", tt.status, tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestEntitlementChange_JSONRoundTrip(t *testing.T) {
+	family := "family-1"
+	change := NewEntitlementChange(watchedEntitlement("user-1", &family), "revoked")
+
+	payload, err := json.Marshal(change)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded EntitlementChange
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if decoded.Type != change.Type || decoded.Entitlement.ID != change.Entitlement.ID || !decoded.OccurredAt.Equal(change.OccurredAt) {
+		t.Errorf("expected %+v after a round trip, got %+v", change, decoded)
+	}
+	if decoded.Entitlement.FamilyID == nil || *decoded.Entitlement.FamilyID != family {
+		t.Errorf("expected family %q after a round trip, got %v", family, decoded.Entitlement.FamilyID)
+	}
+}
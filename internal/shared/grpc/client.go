@@ -16,6 +16,7 @@ import (
 	"google.golang.org/grpc/metadata"
 
 	"github.com/jia-app/paymentservice/internal/shared/log"
+	"github.com/jia-app/paymentservice/internal/shared/tracing"
 )
 
 // ClientConfig holds configuration for gRPC client connections
@@ -86,6 +87,9 @@ func NewClient(config ClientConfig) (*Client, error) {
 			grpc.MaxCallRecvMsgSize(4*1024*1024), // 4MB
 			grpc.MaxCallSendMsgSize(4*1024*1024), // 4MB
 		),
+		// Propagate trace context to downstream services
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(tracing.StreamClientInterceptor()),
 	}
 
 	// Setup TLS/mTLS
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Server is the HTTP admin server exposing health probes and Prometheus
+// metrics on a port separate from the gRPC API
+type Server struct {
+	httpServer *http.Server
+	service    *Service
+	logger     *zap.Logger
+}
+
+// NewServer creates an admin server listening on addr
+func NewServer(addr string, service *Service) *Server {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	service.RegisterRoutes(router)
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           router,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		service: service,
+		logger:  service.logger,
+	}
+}
+
+// Handler returns the HTTP handler serving the admin routes
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// Serve listens on the configured address and serves until ctx is cancelled
+func (s *Server) Serve(ctx context.Context) error {
+	lis, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+
+	s.logger.Info("Starting admin HTTP server", zap.String("address", lis.Addr().String()))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.httpServer.Serve(lis)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		s.logger.Info("Shutting down admin HTTP server")
+		return s.httpServer.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
@@ -3,6 +3,7 @@ package health
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,11 +13,18 @@ import (
 	"github.com/jia-app/paymentservice/internal/shared/metrics"
 )
 
+// CheckFunc reports whether a dependency is usable. A nil error means ready.
+type CheckFunc func(ctx context.Context) error
+
 // Service provides health check endpoints
 type Service struct {
 	healthChecker    *metrics.HealthChecker
 	metricsCollector *metrics.MetricsCollector
 	logger           *zap.Logger
+	startTime        time.Time
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
 }
 
 // NewService creates a new health check service
@@ -25,9 +33,18 @@ func NewService(metricsCollector *metrics.MetricsCollector) *Service {
 		healthChecker:    metrics.NewHealthChecker(metricsCollector),
 		metricsCollector: metricsCollector,
 		logger:           log.L(context.Background()),
+		startTime:        time.Now(),
+		checks:           make(map[string]CheckFunc),
 	}
 }
 
+// AddReadinessCheck registers a dependency check consulted by /readyz
+func (s *Service) AddReadinessCheck(name string, check CheckFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
 // RegisterRoutes registers health check routes
 func (s *Service) RegisterRoutes(router *gin.Engine) {
 	// Health check endpoint
@@ -35,6 +52,10 @@ func (s *Service) RegisterRoutes(router *gin.Engine) {
 	router.GET("/health/ready", s.readinessCheck)
 	router.GET("/health/live", s.livenessCheck)
 
+	// Kubernetes-style probes
+	router.GET("/healthz", s.healthz)
+	router.GET("/readyz", s.readyz)
+
 	// Metrics endpoint
 	router.GET("/metrics", s.metrics)
 
@@ -110,22 +131,61 @@ func (s *Service) livenessCheck(c *gin.Context) {
 	response := gin.H{
 		"status":    "alive",
 		"timestamp": time.Now().Format(time.RFC3339),
-		"uptime":    time.Since(time.Now()).String(), // This would be actual uptime in production
+		"uptime":    time.Since(s.startTime).String(),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// metrics provides Prometheus metrics
-func (s *Service) metrics(c *gin.Context) {
-	// In a real implementation, this would return Prometheus metrics
-	// For now, return a simple response
+// healthz reports process liveness; it never checks dependencies
+func (s *Service) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"uptime": time.Since(s.startTime).String(),
+	})
+}
+
+// readyz runs every registered readiness check and reports 503 if any fail
+func (s *Service) readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	s.mu.RLock()
+	checks := make(map[string]CheckFunc, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.mu.RUnlock()
+
+	ready := true
+	results := make(map[string]string, len(checks))
+	for name, check := range checks {
+		if err := check(ctx); err != nil {
+			ready = false
+			results[name] = err.Error()
+			s.logger.Warn("Readiness check failed",
+				zap.String("check", name),
+				zap.Error(err))
+			continue
+		}
+		results[name] = "ok"
+	}
+
 	response := gin.H{
-		"message":   "Metrics endpoint - Prometheus metrics would be served here",
-		"timestamp": time.Now().Format(time.RFC3339),
+		"status": getOverallStatus(ready),
+		"checks": results,
 	}
 
-	c.JSON(http.StatusOK, response)
+	if ready {
+		c.JSON(http.StatusOK, response)
+	} else {
+		c.JSON(http.StatusServiceUnavailable, response)
+	}
+}
+
+// metrics serves Prometheus metrics from the collector's registry
+func (s *Service) metrics(c *gin.Context) {
+	s.metricsCollector.Handler().ServeHTTP(c.Writer, c.Request)
 }
 
 // status provides detailed service status
@@ -156,6 +216,8 @@ func (s *Service) status(c *gin.Context) {
 			"health":  "/health",
 			"ready":   "/health/ready",
 			"live":    "/health/live",
+			"healthz": "/healthz",
+			"readyz":  "/readyz",
 			"metrics": "/metrics",
 			"status":  "/status",
 		},
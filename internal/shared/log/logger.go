@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -107,7 +108,12 @@ func L(ctx context.Context) *zap.Logger {
 		logger = logger.With(zap.String("request_id", requestID))
 	}
 
-	if traceID, ok := ctx.Value(TraceIDKey).(string); ok && traceID != "" {
+	// Prefer the active span context; fall back to an explicitly set trace ID
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With(
+			zap.String("trace_id", sc.TraceID().String()),
+			zap.String("span_id", sc.SpanID().String()))
+	} else if traceID, ok := ctx.Value(TraceIDKey).(string); ok && traceID != "" {
 		logger = logger.With(zap.String("trace_id", traceID))
 	}
 
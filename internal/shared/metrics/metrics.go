@@ -2,11 +2,14 @@ package metrics
 
 import (
 	"context"
+	"net/http"
 	"time"
 
 	"github.com/jia-app/paymentservice/internal/shared/log"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
@@ -37,7 +40,7 @@ type MetricsCollector struct {
 	usageAmount   prometheus.Histogram
 
 	// Dunning metrics
-	dunningEvents prometheus.Counter
+	dunningEvents *prometheus.CounterVec
 	retryAttempts prometheus.Counter
 	retrySuccess  prometheus.Counter
 	retryFailed   prometheus.Counter
@@ -53,7 +56,7 @@ type MetricsCollector struct {
 	rateLimitAllowed  prometheus.Counter
 
 	// Webhook metrics
-	webhookReceived  prometheus.Counter
+	webhookReceived  *prometheus.CounterVec
 	webhookProcessed prometheus.Counter
 	webhookFailed    prometheus.Counter
 	webhookDuration  prometheus.Histogram
@@ -68,189 +71,282 @@ type MetricsCollector struct {
 	cacheMisses   prometheus.Counter
 	cacheDuration prometheus.Histogram
 
-	logger *zap.Logger
+	// gRPC metrics
+	rpcHandled  *prometheus.CounterVec
+	rpcDuration *prometheus.HistogramVec
+
+	registry *prometheus.Registry
+	logger   *zap.Logger
 }
 
-// NewMetricsCollector creates a new metrics collector
+// NewMetricsCollector creates a new metrics collector backed by its own
+// registry, which also exports Go runtime and process metrics
 func NewMetricsCollector() *MetricsCollector {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return NewMetricsCollectorWithRegistry(registry)
+}
+
+// NewMetricsCollectorWithRegistry creates a metrics collector that registers
+// its metrics with the given registry
+func NewMetricsCollectorWithRegistry(registry *prometheus.Registry) *MetricsCollector {
+	factory := promauto.With(registry)
+
 	return &MetricsCollector{
 		// Payment metrics
-		paymentTotal: promauto.NewCounter(prometheus.CounterOpts{
+		paymentTotal: factory.NewCounter(prometheus.CounterOpts{
 			Name: "payment_total",
 			Help: "Total number of payment requests",
 		}),
-		paymentSuccess: promauto.NewCounter(prometheus.CounterOpts{
+		paymentSuccess: factory.NewCounter(prometheus.CounterOpts{
 			Name: "payment_success_total",
 			Help: "Total number of successful payments",
 		}),
-		paymentFailed: promauto.NewCounter(prometheus.CounterOpts{
+		paymentFailed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "payment_failed_total",
 			Help: "Total number of failed payments",
 		}),
-		paymentDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		paymentDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "payment_duration_seconds",
 			Help:    "Duration of payment processing",
 			Buckets: prometheus.DefBuckets,
 		}),
-		paymentAmount: promauto.NewHistogram(prometheus.HistogramOpts{
+		paymentAmount: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "payment_amount_dollars",
 			Help:    "Payment amounts in dollars",
 			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
 		}),
 
 		// Entitlement metrics
-		entitlementChecks: promauto.NewCounter(prometheus.CounterOpts{
+		entitlementChecks: factory.NewCounter(prometheus.CounterOpts{
 			Name: "entitlement_checks_total",
 			Help: "Total number of entitlement checks",
 		}),
-		entitlementCacheHits: promauto.NewCounter(prometheus.CounterOpts{
+		entitlementCacheHits: factory.NewCounter(prometheus.CounterOpts{
 			Name: "entitlement_cache_hits_total",
 			Help: "Total number of entitlement cache hits",
 		}),
-		entitlementCacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+		entitlementCacheMisses: factory.NewCounter(prometheus.CounterOpts{
 			Name: "entitlement_cache_misses_total",
 			Help: "Total number of entitlement cache misses",
 		}),
-		entitlementDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		entitlementDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "entitlement_check_duration_seconds",
 			Help:    "Duration of entitlement checks",
 			Buckets: prometheus.DefBuckets,
 		}),
 
 		// Subscription metrics
-		subscriptionTotal: promauto.NewCounter(prometheus.CounterOpts{
+		subscriptionTotal: factory.NewCounter(prometheus.CounterOpts{
 			Name: "subscription_total",
 			Help: "Total number of subscriptions",
 		}),
-		subscriptionActive: promauto.NewGauge(prometheus.GaugeOpts{
+		subscriptionActive: factory.NewGauge(prometheus.GaugeOpts{
 			Name: "subscription_active",
 			Help: "Number of active subscriptions",
 		}),
-		subscriptionCancelled: promauto.NewCounter(prometheus.CounterOpts{
+		subscriptionCancelled: factory.NewCounter(prometheus.CounterOpts{
 			Name: "subscription_cancelled_total",
 			Help: "Total number of cancelled subscriptions",
 		}),
-		subscriptionSuspended: promauto.NewCounter(prometheus.CounterOpts{
+		subscriptionSuspended: factory.NewCounter(prometheus.CounterOpts{
 			Name: "subscription_suspended_total",
 			Help: "Total number of suspended subscriptions",
 		}),
 
 		// Usage metrics
-		usageTracked: promauto.NewCounter(prometheus.CounterOpts{
+		usageTracked: factory.NewCounter(prometheus.CounterOpts{
 			Name: "usage_tracked_total",
 			Help: "Total number of usage tracking events",
 		}),
-		quotaExceeded: promauto.NewCounter(prometheus.CounterOpts{
+		quotaExceeded: factory.NewCounter(prometheus.CounterOpts{
 			Name: "quota_exceeded_total",
 			Help: "Total number of quota exceeded events",
 		}),
-		usageAmount: promauto.NewHistogram(prometheus.HistogramOpts{
+		usageAmount: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "usage_amount_bytes",
 			Help:    "Usage amounts in bytes",
 			Buckets: prometheus.ExponentialBuckets(1024, 2, 20), // 1KB to 1GB
 		}),
 
 		// Dunning metrics
-		dunningEvents: promauto.NewCounter(prometheus.CounterOpts{
+		dunningEvents: factory.NewCounterVec(prometheus.CounterOpts{
 			Name: "dunning_events_total",
-			Help: "Total number of dunning events",
-		}),
-		retryAttempts: promauto.NewCounter(prometheus.CounterOpts{
+			Help: "Total number of dunning events by event type",
+		}, []string{"event_type"}),
+		retryAttempts: factory.NewCounter(prometheus.CounterOpts{
 			Name: "retry_attempts_total",
 			Help: "Total number of retry attempts",
 		}),
-		retrySuccess: promauto.NewCounter(prometheus.CounterOpts{
+		retrySuccess: factory.NewCounter(prometheus.CounterOpts{
 			Name: "retry_success_total",
 			Help: "Total number of successful retries",
 		}),
-		retryFailed: promauto.NewCounter(prometheus.CounterOpts{
+		retryFailed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "retry_failed_total",
 			Help: "Total number of failed retries",
 		}),
 
 		// Circuit breaker metrics
-		circuitBreakerState: promauto.NewGauge(prometheus.GaugeOpts{
+		circuitBreakerState: factory.NewGauge(prometheus.GaugeOpts{
 			Name: "circuit_breaker_state",
 			Help: "Circuit breaker state (0=closed, 1=open, 2=half-open)",
 		}),
-		circuitBreakerFailures: promauto.NewCounter(prometheus.CounterOpts{
+		circuitBreakerFailures: factory.NewCounter(prometheus.CounterOpts{
 			Name: "circuit_breaker_failures_total",
 			Help: "Total number of circuit breaker failures",
 		}),
-		circuitBreakerSuccesses: promauto.NewCounter(prometheus.CounterOpts{
+		circuitBreakerSuccesses: factory.NewCounter(prometheus.CounterOpts{
 			Name: "circuit_breaker_successes_total",
 			Help: "Total number of circuit breaker successes",
 		}),
 
 		// Rate limiting metrics
-		rateLimitRequests: promauto.NewCounter(prometheus.CounterOpts{
+		rateLimitRequests: factory.NewCounter(prometheus.CounterOpts{
 			Name: "rate_limit_requests_total",
 			Help: "Total number of rate limit requests",
 		}),
-		rateLimitRejected: promauto.NewCounter(prometheus.CounterOpts{
+		rateLimitRejected: factory.NewCounter(prometheus.CounterOpts{
 			Name: "rate_limit_rejected_total",
 			Help: "Total number of rate limit rejections",
 		}),
-		rateLimitAllowed: promauto.NewCounter(prometheus.CounterOpts{
+		rateLimitAllowed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "rate_limit_allowed_total",
 			Help: "Total number of rate limit allowances",
 		}),
 
 		// Webhook metrics
-		webhookReceived: promauto.NewCounter(prometheus.CounterOpts{
+		webhookReceived: factory.NewCounterVec(prometheus.CounterOpts{
 			Name: "webhook_received_total",
-			Help: "Total number of webhooks received",
-		}),
-		webhookProcessed: promauto.NewCounter(prometheus.CounterOpts{
+			Help: "Total number of webhooks received by event type",
+		}, []string{"event_type"}),
+		webhookProcessed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "webhook_processed_total",
 			Help: "Total number of webhooks processed",
 		}),
-		webhookFailed: promauto.NewCounter(prometheus.CounterOpts{
+		webhookFailed: factory.NewCounter(prometheus.CounterOpts{
 			Name: "webhook_failed_total",
 			Help: "Total number of failed webhooks",
 		}),
-		webhookDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		webhookDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "webhook_duration_seconds",
 			Help:    "Duration of webhook processing",
 			Buckets: prometheus.DefBuckets,
 		}),
 
 		// Database metrics
-		dbConnections: promauto.NewGauge(prometheus.GaugeOpts{
+		dbConnections: factory.NewGauge(prometheus.GaugeOpts{
 			Name: "db_connections_active",
 			Help: "Number of active database connections",
 		}),
-		dbQueryDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		dbQueryDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "db_query_duration_seconds",
 			Help:    "Duration of database queries",
 			Buckets: prometheus.DefBuckets,
 		}),
-		dbQueryErrors: promauto.NewCounter(prometheus.CounterOpts{
+		dbQueryErrors: factory.NewCounter(prometheus.CounterOpts{
 			Name: "db_query_errors_total",
 			Help: "Total number of database query errors",
 		}),
 
 		// Cache metrics
-		cacheHits: promauto.NewCounter(prometheus.CounterOpts{
+		cacheHits: factory.NewCounter(prometheus.CounterOpts{
 			Name: "cache_hits_total",
 			Help: "Total number of cache hits",
 		}),
-		cacheMisses: promauto.NewCounter(prometheus.CounterOpts{
+		cacheMisses: factory.NewCounter(prometheus.CounterOpts{
 			Name: "cache_misses_total",
 			Help: "Total number of cache misses",
 		}),
-		cacheDuration: promauto.NewHistogram(prometheus.HistogramOpts{
+		cacheDuration: factory.NewHistogram(prometheus.HistogramOpts{
 			Name:    "cache_duration_seconds",
 			Help:    "Duration of cache operations",
 			Buckets: prometheus.DefBuckets,
 		}),
 
-		logger: log.L(context.Background()),
+		// gRPC metrics
+		rpcHandled: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server by method and status code",
+		}, []string{"method", "code"}),
+		rpcDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Duration of RPCs handled by the server by method",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+
+		registry: registry,
+		logger:   log.L(context.Background()),
+	}
+}
+
+// Handler returns an HTTP handler that serves the collector's registry in
+// the Prometheus exposition format
+func (mc *MetricsCollector) Handler() http.Handler {
+	return promhttp.HandlerFor(mc.registry, promhttp.HandlerOpts{Registry: mc.registry})
+}
+
+// Gatherer returns the registry backing this collector
+func (mc *MetricsCollector) Gatherer() prometheus.Gatherer {
+	return mc.registry
+}
+
+// Label values are restricted to known sets so that caller-controlled input
+// (e.g. a provider's webhook event type) cannot grow series without bound.
+// Anything outside the set is reported as "other".
+const otherLabelValue = "other"
+
+var (
+	dunningEventTypes = labelSet(
+		"payment_failed",
+		"retry_scheduled",
+		"retry_attempted",
+		"retry_succeeded",
+		"retry_failed",
+		"subscription_suspended",
+		"subscription_cancelled",
+		"dunning_escalated",
+	)
+
+	webhookEventTypes = labelSet(
+		"checkout.session.completed",
+		"payment.succeeded",
+		"payment.failed",
+		"payment.pending",
+		"payment.refunded",
+		"payment.chargeback",
+		"payment_intent.succeeded",
+		"payment_intent.payment_failed",
+		"subscription.created",
+		"subscription.updated",
+		"subscription.cancelled",
+	)
+)
+
+func labelSet(values ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// boundedLabel returns value if it is in allowed, otherwise "other"
+func boundedLabel(value string, allowed map[string]struct{}) string {
+	if _, ok := allowed[value]; ok {
+		return value
 	}
+	return otherLabelValue
 }
 
 // Payment metrics methods
 func (mc *MetricsCollector) RecordPayment(ctx context.Context, success bool, amount float64, duration time.Duration) {
+	if mc == nil {
+		return
+	}
 	mc.paymentTotal.Inc()
 	if success {
 		mc.paymentSuccess.Inc()
@@ -263,6 +359,9 @@ func (mc *MetricsCollector) RecordPayment(ctx context.Context, success bool, amo
 
 // Entitlement metrics methods
 func (mc *MetricsCollector) RecordEntitlementCheck(ctx context.Context, cacheHit bool, duration time.Duration) {
+	if mc == nil {
+		return
+	}
 	mc.entitlementChecks.Inc()
 	if cacheHit {
 		mc.entitlementCacheHits.Inc()
@@ -274,6 +373,9 @@ func (mc *MetricsCollector) RecordEntitlementCheck(ctx context.Context, cacheHit
 
 // Subscription metrics methods
 func (mc *MetricsCollector) RecordSubscription(ctx context.Context, eventType string) {
+	if mc == nil {
+		return
+	}
 	mc.subscriptionTotal.Inc()
 	switch eventType {
 	case "cancelled":
@@ -289,6 +391,9 @@ func (mc *MetricsCollector) UpdateActiveSubscriptions(count int) {
 
 // Usage metrics methods
 func (mc *MetricsCollector) RecordUsage(ctx context.Context, amount int64, quotaExceeded bool) {
+	if mc == nil {
+		return
+	}
 	mc.usageTracked.Inc()
 	if quotaExceeded {
 		mc.quotaExceeded.Inc()
@@ -298,7 +403,10 @@ func (mc *MetricsCollector) RecordUsage(ctx context.Context, amount int64, quota
 
 // Dunning metrics methods
 func (mc *MetricsCollector) RecordDunningEvent(ctx context.Context, eventType string) {
-	mc.dunningEvents.Inc()
+	if mc == nil {
+		return
+	}
+	mc.dunningEvents.WithLabelValues(boundedLabel(eventType, dunningEventTypes)).Inc()
 	switch eventType {
 	case "retry_attempted":
 		mc.retryAttempts.Inc()
@@ -342,8 +450,11 @@ func (mc *MetricsCollector) RecordRateLimit(ctx context.Context, allowed bool) {
 }
 
 // Webhook metrics methods
-func (mc *MetricsCollector) RecordWebhook(ctx context.Context, success bool, duration time.Duration) {
-	mc.webhookReceived.Inc()
+func (mc *MetricsCollector) RecordWebhook(ctx context.Context, eventType string, success bool, duration time.Duration) {
+	if mc == nil {
+		return
+	}
+	mc.webhookReceived.WithLabelValues(boundedLabel(eventType, webhookEventTypes)).Inc()
 	if success {
 		mc.webhookProcessed.Inc()
 	} else {
@@ -366,6 +477,9 @@ func (mc *MetricsCollector) RecordDBQuery(ctx context.Context, success bool, dur
 
 // Cache metrics methods
 func (mc *MetricsCollector) RecordCacheOperation(ctx context.Context, hit bool, duration time.Duration) {
+	if mc == nil {
+		return
+	}
 	if hit {
 		mc.cacheHits.Inc()
 	} else {
@@ -374,6 +488,15 @@ func (mc *MetricsCollector) RecordCacheOperation(ctx context.Context, hit bool,
 	mc.cacheDuration.Observe(duration.Seconds())
 }
 
+// gRPC metrics methods
+func (mc *MetricsCollector) RecordRPC(ctx context.Context, method string, code string, duration time.Duration) {
+	if mc == nil {
+		return
+	}
+	mc.rpcHandled.WithLabelValues(method, code).Inc()
+	mc.rpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
 // HealthChecker provides health check functionality
 type HealthChecker struct {
 	metricsCollector *MetricsCollector
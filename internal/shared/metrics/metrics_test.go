@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// exerciseCollector touches every metric so that labelled vectors are exported
+func exerciseCollector(mc *MetricsCollector) {
+	ctx := context.Background()
+	mc.RecordPayment(ctx, true, 9.99, time.Second)
+	mc.RecordEntitlementCheck(ctx, true, time.Millisecond)
+	mc.RecordSubscription(ctx, "cancelled")
+	mc.UpdateActiveSubscriptions(1)
+	mc.RecordUsage(ctx, 2048, false)
+	mc.RecordDunningEvent(ctx, "retry_attempted")
+	mc.UpdateCircuitBreakerState(ctx, "closed")
+	mc.RecordCircuitBreakerResult(ctx, true)
+	mc.RecordRateLimit(ctx, true)
+	mc.RecordWebhook(ctx, "checkout.session.completed", true, time.Millisecond)
+	mc.UpdateDBConnections(3)
+	mc.RecordDBQuery(ctx, true, time.Millisecond)
+	mc.RecordCacheOperation(ctx, true, time.Millisecond)
+	mc.RecordRPC(ctx, "/payment.v1.PaymentService/GetPayment", "OK", time.Millisecond)
+}
+
+// TestMetricNames_Golden guards the exported metric names, which dashboards
+// and alerts depend on. Run with -update to accept intentional changes.
+func TestMetricNames_Golden(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mc := NewMetricsCollectorWithRegistry(registry)
+	exerciseCollector(mc)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+
+	names := make([]string, 0, len(families))
+	for _, mf := range families {
+		names = append(names, mf.GetName()+" "+strings.ToLower(mf.GetType().String()))
+	}
+	sort.Strings(names)
+	got := strings.Join(names, "\n") + "\n"
+
+	golden := filepath.Join("testdata", "metric_names.golden")
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("metric names changed (run go test -update to accept)\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestBoundedLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	mc := NewMetricsCollectorWithRegistry(registry)
+	ctx := context.Background()
+
+	mc.RecordWebhook(ctx, "checkout.session.completed", true, time.Millisecond)
+	mc.RecordWebhook(ctx, "payment.refunded", true, time.Millisecond)
+	mc.RecordWebhook(ctx, "attacker.controlled.1", false, time.Millisecond)
+	mc.RecordWebhook(ctx, "attacker.controlled.2", false, time.Millisecond)
+	mc.RecordDunningEvent(ctx, "not_a_dunning_event")
+
+	if got := testutil.CollectAndCount(mc.webhookReceived); got != 3 {
+		t.Errorf("expected 3 webhook series, got %d", got)
+	}
+	if got := testutil.ToFloat64(mc.webhookReceived.WithLabelValues(otherLabelValue)); got != 2 {
+		t.Errorf("expected unknown event types to fold into %q, got %v", otherLabelValue, got)
+	}
+	if got := testutil.ToFloat64(mc.dunningEvents.WithLabelValues(otherLabelValue)); got != 1 {
+		t.Errorf("expected unknown dunning event to fold into %q, got %v", otherLabelValue, got)
+	}
+}
+
+func TestNilCollector(t *testing.T) {
+	var mc *MetricsCollector
+	// Usecases may be constructed without metrics; recording must not panic
+	mc.RecordPayment(context.Background(), true, 1, time.Second)
+	mc.RecordWebhook(context.Background(), "payment.succeeded", true, time.Second)
+	mc.RecordDunningEvent(context.Background(), "payment_failed")
+	mc.RecordUsage(context.Background(), 1, false)
+	mc.RecordEntitlementCheck(context.Background(), false, time.Second)
+}
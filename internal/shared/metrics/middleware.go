@@ -6,6 +6,7 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
 	"github.com/jia-app/paymentservice/internal/shared/log"
 )
@@ -82,32 +83,11 @@ func (mi *MetricsInterceptor) StreamServerInterceptor() grpc.StreamServerInterce
 	}
 }
 
-// recordMethodMetrics records metrics based on the gRPC method
+// recordMethodMetrics records per-RPC request metrics. Business metrics
+// (payments, webhooks, entitlement checks) are recorded by the usecases
+// themselves, where the outcome is actually known.
 func (mi *MetricsInterceptor) recordMethodMetrics(ctx context.Context, method string, err error, duration time.Duration) {
-	success := err == nil
-
-	// Record general request metrics
-	if success {
-		mi.metricsCollector.RecordRateLimit(ctx, true)
-	} else {
-		mi.metricsCollector.RecordRateLimit(ctx, false)
-	}
-
-	// Record specific metrics based on method
-	switch method {
-	case "/payment.v1.PaymentService/ProcessPayment":
-		mi.metricsCollector.RecordPayment(ctx, success, 0, duration)
-	case "/payment.v1.PaymentService/CheckEntitlement":
-		mi.metricsCollector.RecordEntitlementCheck(ctx, false, duration) // Assume cache miss for now
-	case "/payment.v1.PaymentService/BulkCheckEntitlements":
-		mi.metricsCollector.RecordEntitlementCheck(ctx, false, duration) // Assume cache miss for now
-	case "/payment.v1.PaymentService/PaymentSuccessWebhook":
-		mi.metricsCollector.RecordWebhook(ctx, success, duration)
-	default:
-		// For other methods, just record general metrics
-		mi.logger.Debug("Unknown method for metrics",
-			zap.String("method", method))
-	}
+	mi.metricsCollector.RecordRPC(ctx, method, status.Code(err).String(), duration)
 }
 
 // DatabaseMetricsInterceptor provides database operation metrics
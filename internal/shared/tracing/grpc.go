@@ -0,0 +1,128 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataCarrier adapts gRPC metadata to the OTel TextMapCarrier interface
+type MetadataCarrier metadata.MD
+
+// Get returns the first value for key
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set sets key to value, replacing any existing values
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns all keys in the carrier
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// InjectOutgoing writes the span context from ctx into the outgoing gRPC metadata
+func InjectOutgoing(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		md = metadata.New(nil)
+	} else {
+		md = md.Copy()
+	}
+	otel.GetTextMapPropagator().Inject(ctx, MetadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// ExtractIncoming returns a context carrying the remote span context found in
+// the incoming gRPC metadata (if any)
+func ExtractIncoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, MetadataCarrier(md))
+}
+
+// RPCAttributes returns the semantic-convention attributes for a full gRPC
+// method name such as "/payment.v1.PaymentService/CreatePayment"
+func RPCAttributes(fullMethod string) []attribute.KeyValue {
+	service, method := splitMethod(fullMethod)
+	return []attribute.KeyValue{
+		semconv.RPCSystemGRPC,
+		semconv.RPCService(service),
+		semconv.RPCMethod(method),
+	}
+}
+
+// SpanName returns the span name for a full gRPC method name
+func SpanName(fullMethod string) string {
+	return strings.TrimPrefix(fullMethod, "/")
+}
+
+// EndRPC records the gRPC status code of err on the span and ends it
+func EndRPC(span trace.Span, err error) {
+	st, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(st.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, st.Message())
+	}
+	span.End()
+}
+
+// UnaryClientInterceptor starts a client span for each unary call and
+// propagates its context to the server via W3C trace-context headers
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, span := Start(ctx, SpanName(method),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(RPCAttributes(method)...),
+		)
+
+		err := invoker(InjectOutgoing(ctx), method, req, reply, cc, opts...)
+		EndRPC(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor starts a client span for each stream. The span
+// covers stream establishment only.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := Start(ctx, SpanName(method),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(RPCAttributes(method)...),
+		)
+
+		stream, err := streamer(InjectOutgoing(ctx), desc, cc, method, opts...)
+		EndRPC(span, err)
+		return stream, err
+	}
+}
+
+// splitMethod splits "/pkg.Service/Method" into service and method
+func splitMethod(fullMethod string) (string, string) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/jia-app/paymentservice/internal/shared/config"
+)
+
+// installRecorder installs an in-memory tracer provider for the duration of the test
+func installRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	if _, err := Init(context.Background(), config.TracingConfig{}, "test"); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+func TestUnaryClientInterceptor_PropagatesTraceContext(t *testing.T) {
+	recorder := installRecorder(t)
+
+	ctx, parent := Start(context.Background(), "parent")
+	defer parent.End()
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	method := "/payment.v1.PaymentService/GetPayment"
+	if err := UnaryClientInterceptor()(ctx, method, nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor returned error: %v", err)
+	}
+
+	if len(outgoing.Get("traceparent")) == 0 {
+		t.Fatal("expected traceparent header in outgoing metadata")
+	}
+
+	// The server side should continue the same trace
+	serverCtx := ExtractIncoming(metadata.NewIncomingContext(context.Background(), outgoing))
+	remote := trace.SpanContextFromContext(serverCtx)
+	if !remote.IsRemote() {
+		t.Error("expected extracted span context to be remote")
+	}
+	if remote.TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("expected trace ID %s, got %s", parent.SpanContext().TraceID(), remote.TraceID())
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 ended span, got %d", len(ended))
+	}
+	if ended[0].Name() != "payment.v1.PaymentService/GetPayment" {
+		t.Errorf("unexpected span name %q", ended[0].Name())
+	}
+	if ended[0].SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected client span, got %s", ended[0].SpanKind())
+	}
+}
+
+func TestInit_FileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans.json")
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	shutdown, err := Init(context.Background(), config.TracingConfig{
+		Enabled:  true,
+		Exporter: ExporterFile,
+		FilePath: path,
+	}, "payment-service-test")
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	_, span := Start(context.Background(), "file-exporter-span")
+	span.End()
+
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read trace file: %v", err)
+	}
+	if !strings.Contains(string(data), "file-exporter-span") {
+		t.Errorf("expected span in trace file, got %s", data)
+	}
+	if !strings.Contains(string(data), "payment-service-test") {
+		t.Errorf("expected service name in trace file, got %s", data)
+	}
+}
+
+func TestInit_UnsupportedExporter(t *testing.T) {
+	_, err := Init(context.Background(), config.TracingConfig{Enabled: true, Exporter: "zipkin"}, "test")
+	if err == nil {
+		t.Fatal("expected error for unsupported exporter")
+	}
+}
+
+func TestRedisError_IgnoresNil(t *testing.T) {
+	if err := redisError(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
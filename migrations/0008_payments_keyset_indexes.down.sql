@@ -0,0 +1,8 @@
+-- Migration: 0008_payments_keyset_indexes_down
+-- Description: Remove payment listing indexes
+
+DROP INDEX IF EXISTS idx_payments_payment_method;
+DROP INDEX IF EXISTS idx_payments_currency;
+DROP INDEX IF EXISTS idx_payments_customer_created_at_id;
+DROP INDEX IF EXISTS idx_payments_amount_id;
+DROP INDEX IF EXISTS idx_payments_created_at_id;
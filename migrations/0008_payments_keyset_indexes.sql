@@ -0,0 +1,11 @@
+-- Migration: 0008_payments_keyset_indexes
+-- Description: Add indexes supporting filtered payment listing and keyset pagination
+
+-- Keyset pagination orders by (sort key, id)
+CREATE INDEX IF NOT EXISTS idx_payments_created_at_id ON payments(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_payments_amount_id ON payments(amount, id);
+CREATE INDEX IF NOT EXISTS idx_payments_customer_created_at_id ON payments(customer_id, created_at, id);
+
+-- Finance filters
+CREATE INDEX IF NOT EXISTS idx_payments_currency ON payments(currency);
+CREATE INDEX IF NOT EXISTS idx_payments_payment_method ON payments(payment_method);
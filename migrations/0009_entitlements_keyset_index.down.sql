@@ -0,0 +1,4 @@
+-- Migration: 0009_entitlements_keyset_index_down
+-- Description: Remove entitlements keyset index
+
+DROP INDEX IF EXISTS idx_entitlements_created_at_id;
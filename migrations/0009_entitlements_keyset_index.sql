@@ -0,0 +1,4 @@
+-- Migration: 0009_entitlements_keyset_index
+-- Description: Support keyset iteration over entitlements for exports
+
+CREATE INDEX IF NOT EXISTS idx_entitlements_created_at_id ON entitlements(created_at, id);
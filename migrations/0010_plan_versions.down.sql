@@ -0,0 +1,16 @@
+-- Migration: 0010_plan_versions_down
+-- Description: Remove plan versioning and restore subscriptions.plan_id as UUID
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS plan_version;
+DROP TABLE IF EXISTS plan_versions;
+ALTER TABLE plans DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE plans DROP COLUMN IF EXISTS version;
+
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS fk_subscriptions_plan_id;
+ALTER TABLE subscriptions ALTER COLUMN plan_id TYPE UUID
+    USING CASE
+        WHEN plan_id ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$' THEN plan_id::uuid
+        ELSE plan_uuid(plan_id)
+    END;
+
+DROP FUNCTION IF EXISTS plan_uuid(TEXT);
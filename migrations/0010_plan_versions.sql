@@ -0,0 +1,66 @@
+-- Migration: 0010_plan_versions
+-- Description: Version plan prices and features, and reconcile subscriptions.plan_id with plans.id
+
+-- plan_uuid derives the UUID the application uses for a string plan ID
+-- (uuid.NewSHA1(uuid.NameSpaceOID, id) in Go, i.e. a version 5 UUID in the
+-- OID namespace), so rows written with either form can be matched up.
+CREATE OR REPLACE FUNCTION plan_uuid(plan_id TEXT)
+RETURNS UUID AS $$
+DECLARE
+    hash BYTEA;
+BEGIN
+    hash := digest(decode('6ba7b8129dad11d180b400c04fd430c8', 'hex') || convert_to(plan_id, 'UTF8'), 'sha1');
+    hash := set_byte(hash, 6, (get_byte(hash, 6) & 15) | 80);
+    hash := set_byte(hash, 8, (get_byte(hash, 8) & 63) | 128);
+    RETURN encode(substring(hash FROM 1 FOR 16), 'hex')::uuid;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
+
+-- Reconcile subscriptions.plan_id (UUID) with plans.id (VARCHAR): store the
+-- plan's string ID, translating derived UUIDs back to the plan they name.
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_plan_id_fkey;
+ALTER TABLE subscriptions ALTER COLUMN plan_id TYPE VARCHAR(100) USING plan_id::text;
+UPDATE subscriptions s
+SET plan_id = p.id
+FROM plans p
+WHERE plan_uuid(p.id)::text = s.plan_id;
+
+-- NOT VALID keeps the migration from failing on legacy rows whose plan no
+-- longer exists; new and updated rows are checked
+ALTER TABLE subscriptions
+    ADD CONSTRAINT fk_subscriptions_plan_id FOREIGN KEY (plan_id)
+    REFERENCES plans(id) ON UPDATE CASCADE NOT VALID;
+
+-- Plans carry their current version and an archive timestamp
+ALTER TABLE plans ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE plans ADD COLUMN archived_at TIMESTAMP;
+
+-- plan_versions keeps every priced/featured revision of a plan. Rows are
+-- immutable; a change to price, billing cycle, features or limits appends a
+-- new version and bumps plans.version.
+CREATE TABLE plan_versions (
+    plan_id VARCHAR(100) NOT NULL REFERENCES plans(id) ON UPDATE CASCADE,
+    version INTEGER NOT NULL,
+    feature_codes TEXT[] NOT NULL,
+    billing_cycle VARCHAR(50),
+    price_cents INTEGER NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    max_users INTEGER,
+    usage_limits JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (plan_id, version)
+);
+
+INSERT INTO plan_versions (plan_id, version, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, created_at)
+SELECT id, 1, feature_codes, billing_cycle, price_cents, currency, max_users, usage_limits, created_at
+FROM plans;
+
+-- Subscriptions are pinned to the plan version they were sold at, so later
+-- price or feature changes do not affect them
+ALTER TABLE subscriptions ADD COLUMN plan_version INTEGER;
+UPDATE subscriptions SET plan_version = 1 WHERE plan_id IN (SELECT id FROM plans);
+
+COMMENT ON TABLE plan_versions IS 'Immutable revisions of plan pricing and features';
+COMMENT ON COLUMN plans.version IS 'Current plan version; new subscriptions are pinned to it';
+COMMENT ON COLUMN plans.archived_at IS 'When the plan was archived; archived plans are not sold but keep existing subscriptions';
+COMMENT ON COLUMN subscriptions.plan_version IS 'Plan version the subscription was sold at (grandfathered)';
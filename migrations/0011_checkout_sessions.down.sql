@@ -0,0 +1,5 @@
+-- Migration: 0011_checkout_sessions_down
+-- Description: Remove checkout sessions table
+
+DROP TRIGGER IF EXISTS update_checkout_sessions_updated_at ON checkout_sessions;
+DROP TABLE IF EXISTS checkout_sessions;
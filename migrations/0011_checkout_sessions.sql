@@ -0,0 +1,38 @@
+-- Migration: 0011_checkout_sessions
+-- Description: Persist checkout sessions created through the billing provider
+
+CREATE TABLE IF NOT EXISTS checkout_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(50) NOT NULL,
+    provider_session_id VARCHAR(255) NOT NULL,
+    plan_id VARCHAR(100) NOT NULL REFERENCES plans(id),
+    plan_version INTEGER NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    family_id VARCHAR(255), -- NULL for individual purchases
+    country_code VARCHAR(2),
+    base_price_cents INTEGER NOT NULL,
+    quoted_price_cents INTEGER NOT NULL,
+    pricing_multiplier DECIMAL(5,2) NOT NULL DEFAULT 1.00,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'complete', 'expired', 'cancelled')),
+    url TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_session_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_sessions_provider_session_id ON checkout_sessions(provider_session_id);
+CREATE INDEX IF NOT EXISTS idx_checkout_sessions_user_id ON checkout_sessions(user_id);
+CREATE INDEX IF NOT EXISTS idx_checkout_sessions_status_expires_at ON checkout_sessions(status, expires_at);
+
+CREATE TRIGGER update_checkout_sessions_updated_at
+    BEFORE UPDATE ON checkout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE checkout_sessions IS 'Checkout sessions opened with a billing provider; the source of truth for completion webhooks';
+COMMENT ON COLUMN checkout_sessions.base_price_cents IS 'Catalog price of the plan version at checkout, in cents';
+COMMENT ON COLUMN checkout_sessions.quoted_price_cents IS 'Price quoted to the customer after the pricing zone multiplier, in cents';
+COMMENT ON COLUMN checkout_sessions.pricing_multiplier IS 'Pricing zone multiplier applied to the base price';
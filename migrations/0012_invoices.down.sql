@@ -0,0 +1,7 @@
+-- Migration: 0012_invoices_down
+-- Description: Remove invoices
+
+DROP TABLE IF EXISTS invoice_line_items;
+DROP TRIGGER IF EXISTS update_invoices_updated_at ON invoices;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_sequences;
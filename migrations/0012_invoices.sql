@@ -0,0 +1,61 @@
+-- Migration: 0012_invoices
+-- Description: Invoices with gap-free per-year sequential numbers and line items
+
+-- One row per calendar year holding the last invoice number issued. The
+-- counter is incremented in the same transaction that inserts the invoice,
+-- so a failed insert rolls the number back and no gaps appear.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    year INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    number VARCHAR(32) NOT NULL UNIQUE,
+    year INTEGER NOT NULL,
+    sequence INTEGER NOT NULL,
+    payment_id UUID NOT NULL UNIQUE REFERENCES payments(id),
+    checkout_session_id UUID REFERENCES checkout_sessions(id),
+    user_id VARCHAR(255) NOT NULL,
+    family_id VARCHAR(255), -- NULL for individual purchases
+    plan_id VARCHAR(100) NOT NULL,
+    plan_version INTEGER NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    subtotal_cents BIGINT NOT NULL,
+    discount_cents BIGINT NOT NULL DEFAULT 0,
+    tax_cents BIGINT NOT NULL DEFAULT 0,
+    total_cents BIGINT NOT NULL,
+    billing_name VARCHAR(255),
+    billing_email VARCHAR(255),
+    billing_address JSONB,
+    billing_tax_id VARCHAR(64),
+    status VARCHAR(20) NOT NULL DEFAULT 'issued' CHECK (status IN ('issued', 'void')),
+    issued_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (year, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoices_user_id_year_sequence ON invoices(user_id, year DESC, sequence DESC);
+
+CREATE TRIGGER update_invoices_updated_at
+    BEFORE UPDATE ON invoices
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS invoice_line_items (
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('plan', 'zone_adjustment', 'discount', 'tax')),
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL DEFAULT 1,
+    unit_amount_cents BIGINT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    PRIMARY KEY (invoice_id, position)
+);
+
+COMMENT ON TABLE invoices IS 'Invoices issued for completed payments';
+COMMENT ON COLUMN invoices.number IS 'Human-readable invoice number, e.g. INV-2025-000042';
+COMMENT ON COLUMN invoices.sequence IS 'Gap-free sequence number within the year';
+COMMENT ON COLUMN invoices.billing_address IS 'Buyer postal address as a JSON object';
+COMMENT ON COLUMN invoice_line_items.amount_cents IS 'Signed line amount in cents; discounts and downward zone adjustments are negative';
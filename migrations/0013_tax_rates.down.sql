@@ -0,0 +1,19 @@
+-- Migration: 0013_tax_rates_down
+-- Description: Remove tax rates and checkout tax columns
+
+ALTER TABLE invoices
+    DROP COLUMN IF EXISTS reverse_charge,
+    DROP COLUMN IF EXISTS tax_inclusive;
+
+ALTER TABLE checkout_sessions
+    DROP COLUMN IF EXISTS total_cents,
+    DROP COLUMN IF EXISTS tax_cents,
+    DROP COLUMN IF EXISTS reverse_charge,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_rate_percent,
+    DROP COLUMN IF EXISTS tax_name,
+    DROP COLUMN IF EXISTS tax_id,
+    DROP COLUMN IF EXISTS region;
+
+DROP TRIGGER IF EXISTS update_tax_rates_updated_at ON tax_rates;
+DROP TABLE IF EXISTS tax_rates;
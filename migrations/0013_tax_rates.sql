@@ -0,0 +1,52 @@
+-- Migration: 0013_tax_rates
+-- Description: Tax rates by country and region, and the tax charged at checkout
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    country_code VARCHAR(2) NOT NULL,
+    region VARCHAR(10) NOT NULL DEFAULT '', -- Empty for the country-wide rate
+    name VARCHAR(50) NOT NULL,
+    rate_percent DECIMAL(6,3) NOT NULL CHECK (rate_percent >= 0 AND rate_percent < 100),
+    inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    digital_services BOOLEAN NOT NULL DEFAULT TRUE,
+    reverse_charge BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (country_code, region)
+);
+
+CREATE TRIGGER update_tax_rates_updated_at
+    BEFORE UPDATE ON tax_rates
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE tax_rates IS 'VAT, GST and sales tax rates applied at checkout, imported with cmd/import-tax-rates';
+COMMENT ON COLUMN tax_rates.region IS 'State or province code; a regional rate takes precedence over the country-wide rate';
+COMMENT ON COLUMN tax_rates.rate_percent IS 'Tax rate in percent, e.g. 20.000';
+COMMENT ON COLUMN tax_rates.inclusive IS 'Whether catalog prices in this jurisdiction already include the tax';
+COMMENT ON COLUMN tax_rates.digital_services IS 'Whether the tax applies to digital services; when false subscriptions are not taxed';
+COMMENT ON COLUMN tax_rates.reverse_charge IS 'Whether business buyers with a tax ID account for the tax themselves';
+
+ALTER TABLE checkout_sessions
+    ADD COLUMN IF NOT EXISTS region VARCHAR(10),
+    ADD COLUMN IF NOT EXISTS tax_id VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS tax_name VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS tax_rate_percent DECIMAL(6,3) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS reverse_charge BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS tax_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS total_cents INTEGER;
+
+UPDATE checkout_sessions SET total_cents = quoted_price_cents WHERE total_cents IS NULL;
+ALTER TABLE checkout_sessions ALTER COLUMN total_cents SET NOT NULL;
+
+COMMENT ON COLUMN checkout_sessions.tax_id IS 'Business tax ID supplied by the buyer';
+COMMENT ON COLUMN checkout_sessions.tax_cents IS 'Tax on the quoted price in cents, included in total_cents';
+COMMENT ON COLUMN checkout_sessions.total_cents IS 'Amount charged by the billing provider in cents';
+
+ALTER TABLE invoices
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS reverse_charge BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN invoices.tax_inclusive IS 'Whether the line amounts include the tax line';
+COMMENT ON COLUMN invoices.reverse_charge IS 'Whether the buyer accounts for the tax under the reverse charge';
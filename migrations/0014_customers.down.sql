@@ -0,0 +1,7 @@
+-- Migration: 0014_customers_down
+-- Description: Remove customer billing profiles and saved payment methods
+
+DROP TRIGGER IF EXISTS update_payment_methods_updated_at ON payment_methods;
+DROP TABLE IF EXISTS payment_methods;
+DROP TRIGGER IF EXISTS update_customers_updated_at ON customers;
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,56 @@
+-- Migration: 0014_customers
+-- Description: Customer billing profiles and the vault of saved payment methods
+
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL UNIQUE,
+    name VARCHAR(255),
+    email VARCHAR(255),
+    address JSONB,
+    tax_id VARCHAR(50),
+    preferred_currency VARCHAR(3),
+    provider VARCHAR(50),
+    provider_customer_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_customer_id)
+);
+
+CREATE TRIGGER update_customers_updated_at
+    BEFORE UPDATE ON customers
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE customers IS 'Billing profile of a user, mirrored to the billing provider as a customer';
+COMMENT ON COLUMN customers.address IS 'Billing address as JSON: line1, line2, city, region, postal_code, country';
+COMMENT ON COLUMN customers.tax_id IS 'Business tax ID, normalized; applies the reverse charge at checkout where valid';
+COMMENT ON COLUMN customers.preferred_currency IS 'Currency the customer prefers to be billed in (optional)';
+COMMENT ON COLUMN customers.provider_customer_id IS 'Customer ID at the billing provider, set once the customer is created there';
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    provider_payment_method_id VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL DEFAULT 'credit_card' CHECK (type IN ('credit_card', 'debit_card', 'bank_transfer', 'digital_wallet')),
+    brand VARCHAR(30),
+    last4 VARCHAR(4),
+    exp_month INTEGER,
+    exp_year INTEGER,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, provider_payment_method_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_customer_id ON payment_methods(customer_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_methods_customer_default ON payment_methods(customer_id) WHERE is_default;
+
+CREATE TRIGGER update_payment_methods_updated_at
+    BEFORE UPDATE ON payment_methods
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE payment_methods IS 'Payment methods tokenized by the billing provider; card details are never stored beyond brand, last4 and expiry';
+COMMENT ON COLUMN payment_methods.provider_payment_method_id IS 'Payment method ID at the billing provider, used to charge it';
+COMMENT ON COLUMN payment_methods.is_default IS 'Method reused by checkout and dunning retries; at most one per customer';
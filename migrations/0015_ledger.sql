@@ -0,0 +1,79 @@
+-- Migration: 0015_ledger
+-- Description: Append-only double-entry ledger of money movements
+
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('asset', 'liability', 'revenue', 'expense')),
+    normal_balance VARCHAR(6) NOT NULL CHECK (normal_balance IN ('debit', 'credit')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE ledger_accounts IS 'Chart of accounts the ledger posts to';
+COMMENT ON COLUMN ledger_accounts.normal_balance IS 'Side that increases the account; contra accounts such as discounts are debit-normal revenue';
+
+INSERT INTO ledger_accounts (code, name, type, normal_balance) VALUES
+    ('provider_clearing', 'Funds held by the billing provider', 'asset', 'debit'),
+    ('dispute_reserve', 'Funds withheld for open disputes', 'asset', 'debit'),
+    ('revenue', 'Subscription revenue', 'revenue', 'credit'),
+    ('discounts', 'Discounts granted', 'revenue', 'debit'),
+    ('refunds', 'Refunds issued', 'revenue', 'debit'),
+    ('tax_payable', 'Tax collected and payable', 'liability', 'credit'),
+    ('provider_fees', 'Billing provider fees', 'expense', 'debit'),
+    ('dispute_losses', 'Disputes lost', 'expense', 'debit')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    reference VARCHAR(255) NOT NULL UNIQUE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('charge', 'refund', 'dispute_opened', 'dispute_won', 'dispute_lost', 'provider_fee')),
+    payment_id UUID,
+    customer_id VARCHAR(255),
+    currency VARCHAR(3) NOT NULL,
+    description TEXT,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_payment_id ON journal_entries(payment_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_occurred_at ON journal_entries(occurred_at);
+
+COMMENT ON TABLE journal_entries IS 'One balanced money movement, e.g. a charge or a refund; never updated or deleted';
+COMMENT ON COLUMN journal_entries.reference IS 'Idempotency key, e.g. payment:<id>:charge; replays of a movement post nothing';
+COMMENT ON COLUMN journal_entries.payment_id IS 'Payment the movement belongs to; not a foreign key so the ledger outlives deleted payments';
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entry_id UUID NOT NULL REFERENCES journal_entries(id),
+    account_code VARCHAR(50) NOT NULL REFERENCES ledger_accounts(code),
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency VARCHAR(3) NOT NULL,
+    customer_id VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry_id ON ledger_postings(entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account_code ON ledger_postings(account_code, currency);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_customer_id ON ledger_postings(customer_id, currency);
+
+COMMENT ON TABLE ledger_postings IS 'Debit or credit of one account; the postings of an entry balance per currency';
+COMMENT ON COLUMN ledger_postings.customer_id IS 'Copied from the entry so balances per customer need no join';
+
+-- The ledger is append-only: corrections are posted as new entries
+CREATE OR REPLACE FUNCTION reject_ledger_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER journal_entries_append_only
+    BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_ledger_change();
+
+CREATE TRIGGER ledger_postings_append_only
+    BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_ledger_change();
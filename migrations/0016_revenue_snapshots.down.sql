@@ -0,0 +1,4 @@
+-- Migration: 0016_revenue_snapshots_down
+-- Description: Remove revenue snapshots
+
+DROP TABLE IF EXISTS revenue_snapshots;
@@ -0,0 +1,18 @@
+-- Migration: 0016_revenue_snapshots
+-- Description: Daily snapshots of recurring revenue per subscription for MRR and churn reporting
+
+CREATE TABLE IF NOT EXISTS revenue_snapshots (
+    snapshot_date DATE NOT NULL,
+    subscription_id UUID NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    plan_id VARCHAR(100) NOT NULL,
+    pricing_zone VARCHAR(1) NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    mrr_cents BIGINT NOT NULL CHECK (mrr_cents >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (snapshot_date, subscription_id)
+);
+
+COMMENT ON TABLE revenue_snapshots IS 'Subscriptions paying at the end of each day and their monthly recurring revenue; rebuilt per day by the snapshot worker and cmd/revenue-report';
+COMMENT ON COLUMN revenue_snapshots.pricing_zone IS 'Pricing zone of the country the subscription was bought from; empty when unknown';
+COMMENT ON COLUMN revenue_snapshots.mrr_cents IS 'Price paid normalized to a month: yearly prices are divided by 12';
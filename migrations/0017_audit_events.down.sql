@@ -0,0 +1,6 @@
+-- Migration: 0017_audit_events_down
+-- Description: Remove the audit log
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+DROP FUNCTION IF EXISTS reject_audit_change();
+DROP TABLE IF EXISTS audit_events;
@@ -0,0 +1,35 @@
+-- Migration: 0017_audit_events
+-- Description: Append-only audit log of changes to payments, entitlements, subscriptions and the catalog
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type VARCHAR(50) NOT NULL CHECK (entity_type IN ('payment', 'entitlement', 'subscription', 'plan', 'pricing_zone')),
+    entity_id VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL CHECK (actor_type IN ('user', 'service', 'system')),
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    changes JSONB NOT NULL DEFAULT '{}',
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    reason TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_entity ON audit_events(entity_type, entity_id, occurred_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events(actor_id, occurred_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events(occurred_at DESC, id DESC);
+
+COMMENT ON TABLE audit_events IS 'Who changed what and why; never updated or deleted';
+COMMENT ON COLUMN audit_events.actor_id IS 'User ID or SPIFFE ID from the auth context; empty for system changes such as webhooks and workers';
+COMMENT ON COLUMN audit_events.changes IS 'Changed fields as {"field": {"before": ..., "after": ...}}';
+
+CREATE OR REPLACE FUNCTION reject_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit log is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_change();
@@ -0,0 +1,5 @@
+-- Migration: 0018_payment_provider_down
+-- Description: Remove the billing provider of payments
+
+DROP INDEX IF EXISTS idx_payments_provider;
+ALTER TABLE payments DROP COLUMN IF EXISTS provider;
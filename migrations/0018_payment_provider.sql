@@ -0,0 +1,14 @@
+-- Migration: 0018_payment_provider
+-- Description: Record the billing provider each payment was taken with, so that routed checkouts are refunded and reconciled with the right provider
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS provider VARCHAR(50);
+
+-- Checkout payments are keyed by their session ID
+UPDATE payments p
+SET provider = cs.provider
+FROM checkout_sessions cs
+WHERE p.order_id = cs.provider_session_id AND p.provider IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_payments_provider ON payments(provider);
+
+COMMENT ON COLUMN payments.provider IS 'Billing provider the payment was taken with; NULL for payments made before routing';
@@ -0,0 +1,5 @@
+-- Migration: 0019_entitlement_source_down
+-- Description: Remove the source of entitlements
+
+DROP INDEX IF EXISTS idx_entitlements_source;
+ALTER TABLE entitlements DROP COLUMN IF EXISTS source;
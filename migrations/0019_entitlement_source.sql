@@ -0,0 +1,9 @@
+-- Migration: 0019_entitlement_source
+-- Description: Record whether an entitlement was paid for or granted as a complimentary account, so comps can be reported apart from paid grants
+
+ALTER TABLE entitlements ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'paid'
+    CHECK (source IN ('paid', 'complimentary'));
+
+CREATE INDEX IF NOT EXISTS idx_entitlements_source ON entitlements(source) WHERE source <> 'paid';
+
+COMMENT ON COLUMN entitlements.source IS 'paid, or complimentary for entitlements granted by support without payment';
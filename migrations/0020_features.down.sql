@@ -0,0 +1,10 @@
+-- Migration: 0020_features_down
+-- Description: Remove the feature catalog
+
+-- Audit events are append-only, so events about features are kept and only
+-- new rows are checked
+ALTER TABLE audit_events DROP CONSTRAINT IF EXISTS audit_events_entity_type_check;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_entity_type_check
+    CHECK (entity_type IN ('payment', 'entitlement', 'subscription', 'plan', 'pricing_zone')) NOT VALID;
+
+DROP TABLE IF EXISTS features;
@@ -0,0 +1,56 @@
+-- Migration: 0020_features
+-- Description: Feature catalog that plans, webhooks and entitlement checks resolve feature codes against
+
+-- quota_schema maps each usage_limits key a feature governs to its JSON type
+-- (integer, number, string, boolean, array or object). Plans may only set
+-- usage limits declared by one of their features, and entitlements granted
+-- for a feature start from its default_limits.
+CREATE TABLE IF NOT EXISTS features (
+    code VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    quota_schema JSONB NOT NULL DEFAULT '{}',
+    default_limits JSONB NOT NULL DEFAULT '{}',
+    deprecated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN features.deprecated IS 'Deprecated features can no longer be added to plans; existing plans and entitlements keep them';
+
+INSERT INTO features (code, description, quota_schema, default_limits) VALUES
+    ('basic_storage', 'Basic cloud storage', '{"storage_gb": "integer"}', '{"storage_gb": 10}'),
+    ('pro_storage', 'Pro cloud storage', '{"storage_gb": "integer"}', '{"storage_gb": 100}'),
+    ('family_storage', 'Shared family storage', '{"storage_gb": "integer"}', '{"storage_gb": 500}'),
+    ('enterprise_storage', 'Unlimited storage; -1 means no limit', '{"storage_gb": "integer"}', '{"storage_gb": -1}'),
+    ('basic_support', 'Email support', '{"support_level": "string"}', '{"support_level": "email"}'),
+    ('pro_support', 'Priority email support', '{"support_level": "string"}', '{"support_level": "priority_email"}'),
+    ('family_support', 'Priority email support for families', '{"support_level": "string"}', '{"support_level": "priority_email"}'),
+    ('enterprise_support', 'Dedicated support', '{"support_level": "string"}', '{"support_level": "dedicated"}'),
+    ('core_features', 'Core application features and API quota', '{"api_calls_per_month": "integer"}', '{"api_calls_per_month": 1000}'),
+    ('advanced_analytics', 'Analytics dashboards and history', '{"analytics_retention_days": "integer"}', '{"analytics_retention_days": 90}'),
+    ('api_access', 'Programmatic API access', '{}', '{}'),
+    ('family_sharing', 'Sharing with family members', '{"family_members": "integer"}', '{"family_members": 6}'),
+    ('parental_controls', 'Parental controls for family members', '{}', '{}'),
+    ('sso', 'Single sign-on', '{"sso_providers": "array"}', '{"sso_providers": ["saml", "oidc"]}'),
+    ('audit_logs', 'Organization audit logs', '{}', '{}'),
+    ('custom_integrations', 'Custom integrations', '{}', '{}')
+ON CONFLICT (code) DO NOTHING;
+
+-- Register any other code already sold by a plan, so existing plans stay
+-- valid, then codes only found on entitlements, such as the premium_feature
+-- the Stripe adapter used to fall back to. The latter are deprecated: their
+-- grants keep working but no plan can add them.
+INSERT INTO features (code, description)
+SELECT DISTINCT code, 'Registered from existing plans'
+FROM plans, unnest(feature_codes) AS code
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO features (code, description, deprecated)
+SELECT DISTINCT feature_code, 'Registered from existing entitlements', TRUE
+FROM entitlements
+ON CONFLICT (code) DO NOTHING;
+
+-- Feature catalog changes are audited like plan changes
+ALTER TABLE audit_events DROP CONSTRAINT IF EXISTS audit_events_entity_type_check;
+ALTER TABLE audit_events ADD CONSTRAINT audit_events_entity_type_check
+    CHECK (entity_type IN ('payment', 'entitlement', 'subscription', 'plan', 'pricing_zone', 'feature'));
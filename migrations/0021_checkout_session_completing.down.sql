@@ -0,0 +1,10 @@
+-- Migration: 0021_checkout_session_completing_down
+-- Description: Remove the completing status of checkout sessions
+
+-- Sessions left mid-completion go back to open so their webhook can be
+-- redelivered
+UPDATE checkout_sessions SET status = 'open' WHERE status = 'completing';
+
+ALTER TABLE checkout_sessions DROP CONSTRAINT IF EXISTS checkout_sessions_status_check;
+ALTER TABLE checkout_sessions ADD CONSTRAINT checkout_sessions_status_check
+    CHECK (status IN ('open', 'complete', 'expired', 'cancelled'));
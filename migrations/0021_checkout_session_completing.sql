@@ -0,0 +1,6 @@
+-- Migration: 0021_checkout_session_completing
+-- Description: Claim checkout sessions while their completion webhook is applied, so that concurrent deliveries complete a session once and a session is only complete once its payment is
+
+ALTER TABLE checkout_sessions DROP CONSTRAINT IF EXISTS checkout_sessions_status_check;
+ALTER TABLE checkout_sessions ADD CONSTRAINT checkout_sessions_status_check
+    CHECK (status IN ('open', 'completing', 'complete', 'expired', 'cancelled'));
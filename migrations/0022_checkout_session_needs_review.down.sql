@@ -0,0 +1,10 @@
+-- Migration: 0022_checkout_session_needs_review_down
+-- Description: Remove the needs_review status of checkout sessions
+
+-- Held sessions were paid but granted nothing; cancelled is the closest
+-- earlier status
+UPDATE checkout_sessions SET status = 'cancelled' WHERE status = 'needs_review';
+
+ALTER TABLE checkout_sessions DROP CONSTRAINT IF EXISTS checkout_sessions_status_check;
+ALTER TABLE checkout_sessions ADD CONSTRAINT checkout_sessions_status_check
+    CHECK (status IN ('open', 'completing', 'complete', 'expired', 'cancelled'));
@@ -0,0 +1,6 @@
+-- Migration: 0022_checkout_session_needs_review
+-- Description: Hold checkout sessions whose completion webhook reports a different amount or currency than was quoted, granting nothing until they have been reviewed
+
+ALTER TABLE checkout_sessions DROP CONSTRAINT IF EXISTS checkout_sessions_status_check;
+ALTER TABLE checkout_sessions ADD CONSTRAINT checkout_sessions_status_check
+    CHECK (status IN ('open', 'completing', 'complete', 'needs_review', 'expired', 'cancelled'));
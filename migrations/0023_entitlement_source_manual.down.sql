@@ -0,0 +1,11 @@
+-- Migration: 0023_entitlement_source_manual_down
+-- Description: Remove the manual source of entitlements
+
+-- Manual grants were recorded as paid before
+UPDATE entitlements SET source = 'paid' WHERE source = 'manual';
+
+ALTER TABLE entitlements DROP CONSTRAINT IF EXISTS entitlements_source_check;
+ALTER TABLE entitlements ADD CONSTRAINT entitlements_source_check
+    CHECK (source IN ('paid', 'complimentary'));
+
+COMMENT ON COLUMN entitlements.source IS 'paid, or complimentary for entitlements granted by support without payment';
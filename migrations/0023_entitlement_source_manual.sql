@@ -0,0 +1,8 @@
+-- Migration: 0023_entitlement_source_manual
+-- Description: Record entitlements granted by support that are not comps as manual, so that grants made outside of checkout are never reported as paid
+
+ALTER TABLE entitlements DROP CONSTRAINT IF EXISTS entitlements_source_check;
+ALTER TABLE entitlements ADD CONSTRAINT entitlements_source_check
+    CHECK (source IN ('paid', 'complimentary', 'manual'));
+
+COMMENT ON COLUMN entitlements.source IS 'paid for checkouts and subscriptions, complimentary for comps granted by support without payment, manual for other grants made by support';
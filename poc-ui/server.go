@@ -15,8 +15,14 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	paymentv1 "github.com/jia-app/paymentservice/api/payment/v1"
+	"github.com/jia-app/paymentservice/internal/payment/audit"
+	"github.com/jia-app/paymentservice/internal/payment/domain"
+	"github.com/jia-app/paymentservice/internal/payment/repo/postgres"
+	shareddb "github.com/jia-app/paymentservice/internal/shared/db"
+	sharedlog "github.com/jia-app/paymentservice/internal/shared/log"
 )
 
 const (
@@ -59,16 +65,12 @@ type WebhookResponse struct {
 }
 
 type CreateEntitlementRequest struct {
-	UserID         string                 `json:"user_id"`
-	FamilyID       string                 `json:"family_id,omitempty"`
-	FeatureCode    string                 `json:"feature_code"`
-	PlanID         string                 `json:"plan_id"`
-	SubscriptionID string                 `json:"subscription_id,omitempty"`
-	Status         string                 `json:"status"`
-	GrantedAt      string                 `json:"granted_at"`
-	ExpiresAt      *string                `json:"expires_at,omitempty"`
-	UsageLimits    map[string]interface{} `json:"usage_limits,omitempty"`
-	Metadata       map[string]interface{} `json:"metadata,omitempty"`
+	UserID        string  `json:"user_id"`
+	FeatureCode   string  `json:"feature_code"`
+	PlanID        string  `json:"plan_id"`
+	ExpiresAt     *string `json:"expires_at,omitempty"` // RFC 3339
+	Complimentary bool    `json:"complimentary,omitempty"`
+	Reason        string  `json:"reason"`
 }
 
 type CreateEntitlementResponse struct {
@@ -128,6 +130,18 @@ func main() {
 	}
 	log.Println("Database connection established")
 
+	// Admin edits are recorded in the audit log through the service's store
+	auditPool, err := shareddb.NewPool(context.Background(), &shareddb.Config{DSN: dbConnStr, MaxConns: 2})
+	if err != nil {
+		log.Fatalf("Failed to create audit database pool: %v", err)
+	}
+	defer auditPool.Close()
+	store, err := postgres.NewStoreWithPool(auditPool.Pool)
+	if err != nil {
+		log.Fatalf("Failed to create audit store: %v", err)
+	}
+	auditor := audit.NewRecorder(store.Audit())
+
 	// Create gRPC connection
 	conn, err := grpc.Dial(grpcAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -174,11 +188,11 @@ func main() {
 		case http.MethodGet:
 			handleAdminListPlans(w, r, db)
 		case http.MethodPost:
-			handleAdminCreatePlan(w, r, db)
+			handleAdminCreatePlan(w, r, db, auditor)
 		case http.MethodPut:
-			handleAdminUpdatePlan(w, r, db)
+			handleAdminUpdatePlan(w, r, db, auditor)
 		case http.MethodDelete:
-			handleAdminDeletePlan(w, r, db)
+			handleAdminDeletePlan(w, r, db, auditor)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -189,11 +203,11 @@ func main() {
 		case http.MethodGet:
 			handleAdminListPricingZones(w, r, db)
 		case http.MethodPost:
-			handleAdminCreatePricingZone(w, r, db)
+			handleAdminCreatePricingZone(w, r, db, auditor)
 		case http.MethodPut:
-			handleAdminUpdatePricingZone(w, r, db)
+			handleAdminUpdatePricingZone(w, r, db, auditor)
 		case http.MethodDelete:
-			handleAdminDeletePricingZone(w, r, db)
+			handleAdminDeletePricingZone(w, r, db, auditor)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -514,33 +528,25 @@ func handleCreateEntitlement(w http.ResponseWriter, r *http.Request, client paym
 	}
 
 	// Validate required fields
-	if req.UserID == "" || req.PlanID == "" {
-		http.Error(w, "Missing required fields: user_id, plan_id", http.StatusBadRequest)
+	if req.UserID == "" || req.PlanID == "" || req.FeatureCode == "" || req.Reason == "" {
+		http.Error(w, "Missing required fields: user_id, plan_id, feature_code, reason", http.StatusBadRequest)
 		return
 	}
 
-	// Create a webhook payload to simulate entitlement creation
-	webhookPayload := map[string]interface{}{
-		"session_id": req.SubscriptionID,
-		"user_id":    req.UserID,
-		"plan_id":    req.PlanID,
-		"amount":     19.99, // Default amount
-		"currency":   "USD",
-		"status":     req.Status,
-		"expires_at": req.ExpiresAt,
-		"metadata": map[string]interface{}{
-			"family_id":      req.FamilyID,
-			"country_code":   "US",
-			"base_price":     19.99,
-			"adjusted_price": 19.99,
-		},
+	grpcReq := &paymentv1.GrantEntitlementRequest{
+		UserId:        req.UserID,
+		FeatureCode:   req.FeatureCode,
+		PlanId:        req.PlanID,
+		Complimentary: req.Complimentary,
+		Reason:        req.Reason,
 	}
-
-	// Convert payload to bytes
-	payloadBytes, err := json.Marshal(webhookPayload)
-	if err != nil {
-		http.Error(w, "Failed to marshal webhook payload", http.StatusInternalServerError)
-		return
+	if req.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			http.Error(w, "Invalid expires_at, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		grpcReq.ExpiresAt = timestamppb.New(expiresAt)
 	}
 
 	// Call gRPC service
@@ -553,14 +559,7 @@ func handleCreateEntitlement(w http.ResponseWriter, r *http.Request, client paym
 	})
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
-	// Convert to gRPC request
-	grpcReq := &paymentv1.ProcessWebhookRequest{
-		Payload:   payloadBytes,
-		Signature: "direct_entitlement_creation",
-		Provider:  "stripe",
-	}
-
-	resp, err := client.ProcessWebhook(ctx, grpcReq)
+	resp, err := client.GrantEntitlement(ctx, grpcReq)
 	if err != nil {
 		log.Printf("gRPC error: %v", err)
 		http.Error(w, fmt.Sprintf("Failed to create entitlement: %v", err), http.StatusInternalServerError)
@@ -569,9 +568,9 @@ func handleCreateEntitlement(w http.ResponseWriter, r *http.Request, client paym
 
 	// Convert response
 	response := CreateEntitlementResponse{
-		Success:       resp.Success,
-		Message:       resp.Message,
-		EntitlementID: "created_via_webhook", // We don't have the actual ID from webhook
+		Success:       true,
+		Message:       "Entitlement granted",
+		EntitlementID: resp.Entitlement.Id,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -866,7 +865,7 @@ func handleAdminListPlans(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 }
 
 // handleAdminCreatePlan - Create a new plan
-func handleAdminCreatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminCreatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	var req struct {
 		ID           string   `json:"id"`
 		Name         string   `json:"name"`
@@ -897,6 +896,7 @@ func handleAdminCreatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 		http.Error(w, fmt.Sprintf("Failed to create plan: %v", err), http.StatusInternalServerError)
 		return
 	}
+	auditAdminChange(r, auditor, db, domain.AuditEntityPlan, id, domain.AuditActionCreate, nil)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -907,7 +907,7 @@ func handleAdminCreatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 }
 
 // handleAdminUpdatePlan - Update an existing plan
-func handleAdminUpdatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminUpdatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	var req UpdatePlanRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -965,6 +965,7 @@ func handleAdminUpdatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	query := fmt.Sprintf("UPDATE plans SET %s WHERE id = $%d",
 		joinStrings(updates, ", "), argPos)
 
+	before := auditRow(db, domain.AuditEntityPlan, req.ID)
 	result, err := db.Exec(query, args...)
 	if err != nil {
 		log.Printf("Database error: %v", err)
@@ -973,6 +974,9 @@ func handleAdminUpdatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		auditAdminChange(r, auditor, db, domain.AuditEntityPlan, req.ID, domain.AuditActionUpdate, before)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":       rowsAffected > 0,
@@ -982,13 +986,14 @@ func handleAdminUpdatePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 }
 
 // handleAdminDeletePlan - Soft delete a plan (set active to false)
-func handleAdminDeletePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminDeletePlan(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	planID := r.URL.Query().Get("id")
 	if planID == "" {
 		http.Error(w, "Plan ID is required", http.StatusBadRequest)
 		return
 	}
 
+	before := auditRow(db, domain.AuditEntityPlan, planID)
 	query := "UPDATE plans SET active = false, updated_at = NOW() WHERE id = $1"
 	result, err := db.Exec(query, planID)
 	if err != nil {
@@ -998,6 +1003,9 @@ func handleAdminDeletePlan(w http.ResponseWriter, r *http.Request, db *sql.DB) {
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		auditAdminChange(r, auditor, db, domain.AuditEntityPlan, planID, domain.AuditActionArchive, before)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":       rowsAffected > 0,
@@ -1055,7 +1063,7 @@ func handleAdminListPricingZones(w http.ResponseWriter, r *http.Request, db *sql
 }
 
 // handleAdminCreatePricingZone - Create a new pricing zone
-func handleAdminCreatePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminCreatePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	var req CreatePricingZoneRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -1075,6 +1083,7 @@ func handleAdminCreatePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 		http.Error(w, fmt.Sprintf("Failed to create pricing zone: %v", err), http.StatusInternalServerError)
 		return
 	}
+	auditAdminChange(r, auditor, db, domain.AuditEntityPricingZone, id, domain.AuditActionCreate, nil)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -1085,7 +1094,7 @@ func handleAdminCreatePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 }
 
 // handleAdminUpdatePricingZone - Update an existing pricing zone
-func handleAdminUpdatePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminUpdatePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	var req UpdatePricingZoneRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid JSON", http.StatusBadRequest)
@@ -1139,6 +1148,7 @@ func handleAdminUpdatePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 	query := fmt.Sprintf("UPDATE pricing_zones SET %s WHERE id = $%d",
 		joinStrings(updates, ", "), argPos)
 
+	before := auditRow(db, domain.AuditEntityPricingZone, req.ID)
 	result, err := db.Exec(query, args...)
 	if err != nil {
 		log.Printf("Database error: %v", err)
@@ -1147,6 +1157,9 @@ func handleAdminUpdatePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		auditAdminChange(r, auditor, db, domain.AuditEntityPricingZone, req.ID, domain.AuditActionUpdate, before)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":       rowsAffected > 0,
@@ -1156,13 +1169,14 @@ func handleAdminUpdatePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 }
 
 // handleAdminDeletePricingZone - Delete a pricing zone
-func handleAdminDeletePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB) {
+func handleAdminDeletePricingZone(w http.ResponseWriter, r *http.Request, db *sql.DB, auditor *audit.Recorder) {
 	zoneID := r.URL.Query().Get("id")
 	if zoneID == "" {
 		http.Error(w, "Pricing zone ID is required", http.StatusBadRequest)
 		return
 	}
 
+	before := auditRow(db, domain.AuditEntityPricingZone, zoneID)
 	query := "DELETE FROM pricing_zones WHERE id = $1"
 	result, err := db.Exec(query, zoneID)
 	if err != nil {
@@ -1172,6 +1186,9 @@ func handleAdminDeletePricingZone(w http.ResponseWriter, r *http.Request, db *sq
 	}
 
 	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected > 0 {
+		auditAdminChange(r, auditor, db, domain.AuditEntityPricingZone, zoneID, domain.AuditActionDelete, before)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"success":       rowsAffected > 0,
@@ -1336,6 +1353,47 @@ func handleAdminListEntitlements(w http.ResponseWriter, r *http.Request, db *sql
 }
 
 // Helper function to join strings
+// auditTables maps the audited entities the admin API edits to their tables
+var auditTables = map[domain.AuditEntityType]string{
+	domain.AuditEntityPlan:        "plans",
+	domain.AuditEntityPricingZone: "pricing_zones",
+}
+
+// auditRow loads a row as JSON for the audit log, or nil when it does not exist
+func auditRow(db *sql.DB, entityType domain.AuditEntityType, id string) json.RawMessage {
+	var row json.RawMessage
+	query := fmt.Sprintf("SELECT row_to_json(t) FROM %s t WHERE id::text = $1", auditTables[entityType])
+	if err := db.QueryRow(query, id).Scan(&row); err != nil {
+		return nil
+	}
+	return row
+}
+
+// auditAdminChange records an admin edit. The admin is named by the
+// X-Admin-User header; X-Request-ID and X-Audit-Reason are recorded too.
+func auditAdminChange(r *http.Request, auditor *audit.Recorder, db *sql.DB, entityType domain.AuditEntityType, id, action string, before json.RawMessage) {
+	admin := r.Header.Get("X-Admin-User")
+	if admin == "" {
+		admin = "poc-admin"
+	}
+	requestID := r.Header.Get("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+	ctx := sharedlog.WithUserID(r.Context(), admin)
+	ctx = sharedlog.WithRequestID(ctx, requestID)
+	ctx = audit.WithReason(ctx, r.Header.Get("X-Audit-Reason"))
+
+	change := audit.Change{EntityType: entityType, EntityID: id, Action: action}
+	if before != nil {
+		change.Before = before
+	}
+	if after := auditRow(db, entityType, id); after != nil {
+		change.After = after
+	}
+	auditor.Record(ctx, change)
+}
+
 func joinStrings(strs []string, sep string) string {
 	if len(strs) == 0 {
 		return ""